@@ -0,0 +1,78 @@
+package block
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// VerifyOperationProof checks whether the operation fact is in the operations
+// tree of the block with the proof from FixedTree.Proof(). The root of proof
+// should match with Manifest.OperationsHash().
+func VerifyOperationProof(
+	manifest Manifest,
+	fact valuehash.Hash,
+	pr []tree.FixedTreeNode,
+) (operation.FixedTreeNode, error) {
+	if fact == nil {
+		return operation.FixedTreeNode{}, errors.Errorf("empty operation fact hash")
+	}
+
+	n, err := verifyProof(manifest, manifest.OperationsHash(), fact.Bytes(), pr)
+	if err != nil {
+		return operation.FixedTreeNode{}, err
+	}
+
+	i, ok := n.(operation.FixedTreeNode)
+	if !ok {
+		return operation.FixedTreeNode{}, errors.Errorf("expected operation.FixedTreeNode, not %T", n)
+	}
+
+	return i, nil
+}
+
+// VerifyStateProof checks whether the state is in the states tree of the
+// block with the proof from FixedTree.Proof(). The root of proof should match
+// with Manifest.StatesHash().
+func VerifyStateProof(manifest Manifest, st state.State, pr []tree.FixedTreeNode) error {
+	if err := isvalid.Check(nil, false, st); err != nil {
+		return err
+	}
+
+	if st.Height() != manifest.Height() {
+		return errors.Errorf("state height does not match with manifest; %d != %d", st.Height(), manifest.Height())
+	}
+
+	if !st.Hash().Equal(st.GenerateHash()) {
+		return errors.Errorf("state hash does not match")
+	}
+
+	_, err := verifyProof(manifest, manifest.StatesHash(), st.Hash().Bytes(), pr)
+
+	return err
+}
+
+func verifyProof(manifest Manifest, root valuehash.Hash, key []byte, pr []tree.FixedTreeNode) (tree.FixedTreeNode, error) {
+	if err := isvalid.Check(nil, false, manifest); err != nil {
+		return nil, err
+	}
+
+	if root == nil || root.IsEmpty() {
+		return nil, errors.Errorf("empty tree root in manifest")
+	}
+
+	if len(pr) < 1 || pr[len(pr)-1] == nil {
+		return nil, errors.Errorf("empty proof")
+	}
+
+	if !bytes.Equal(pr[len(pr)-1].Hash(), root.Bytes()) {
+		return nil, errors.Errorf("root of proof does not match with manifest")
+	}
+
+	return tree.ProveFixedTreeProofByKey(pr, key)
+}
@@ -0,0 +1,113 @@
+package base
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+)
+
+var (
+	EquivocationType     = hint.Type("equivocation")
+	EquivocationV0Hint   = hint.NewHint(EquivocationType, "v0.0.1")
+	EquivocationV0Hinter = EquivocationV0{BaseHinter: hint.NewBaseHinter(EquivocationV0Hint)}
+)
+
+// EquivocationV0 is the evidence of double vote; the node signed the
+// different ballot facts at the same height, round and stage. The signed
+// ballot facts prove that the node voted twice.
+type EquivocationV0 struct {
+	hint.BaseHinter
+	first   SignedBallotFact
+	second  SignedBallotFact
+	foundAt time.Time
+}
+
+func NewEquivocationV0(first, second SignedBallotFact, foundAt time.Time) EquivocationV0 {
+	return EquivocationV0{
+		BaseHinter: hint.NewBaseHinter(EquivocationV0Hint),
+		first:      first,
+		second:     second,
+		foundAt:    foundAt,
+	}
+}
+
+// NewEquivocationV0FromBallots returns the evidence from ballots; if the
+// ballots are not double vote, false is returned.
+func NewEquivocationV0FromBallots(first, second Ballot, foundAt time.Time) (EquivocationV0, bool) {
+	ev := NewEquivocationV0(first.SignedFact(), second.SignedFact(), foundAt)
+	if err := ev.isDoubleVote(); err != nil {
+		return EquivocationV0{}, false
+	}
+
+	return ev, true
+}
+
+func (ev EquivocationV0) IsValid(networkID []byte) error {
+	if err := isvalid.Check(networkID, false, ev.BaseHinter, ev.first, ev.second); err != nil {
+		return err
+	}
+
+	if ev.foundAt.IsZero() {
+		return isvalid.InvalidError.Errorf("empty found time")
+	}
+
+	return ev.isDoubleVote()
+}
+
+func (ev EquivocationV0) isDoubleVote() error {
+	a, b := ev.first.Fact(), ev.second.Fact()
+
+	switch {
+	case !ev.first.FactSign().Node().Equal(ev.second.FactSign().Node()):
+		return isvalid.InvalidError.Errorf("different nodes; %q != %q",
+			ev.first.FactSign().Node(), ev.second.FactSign().Node())
+	case a.Height() != b.Height():
+		return isvalid.InvalidError.Errorf("different heights; %d != %d", a.Height(), b.Height())
+	case a.Round() != b.Round():
+		return isvalid.InvalidError.Errorf("different rounds; %d != %d", a.Round(), b.Round())
+	case a.Stage() != b.Stage():
+		return isvalid.InvalidError.Errorf("different stages; %s != %s", a.Stage(), b.Stage())
+	case a.Hash().Equal(b.Hash()):
+		return isvalid.InvalidError.Errorf("same ballot fact, %s", a.Hash())
+	default:
+		return nil
+	}
+}
+
+// Key identifies the double vote; only one evidence is kept for the same node,
+// height, round and stage.
+func (ev EquivocationV0) Key() string {
+	return fmt.Sprintf("%d-%d-%d-%s", ev.Height(), ev.Round(), ev.Stage(), ev.Node())
+}
+
+func (ev EquivocationV0) Node() Address {
+	return ev.first.FactSign().Node()
+}
+
+func (ev EquivocationV0) Height() Height {
+	return ev.first.Fact().Height()
+}
+
+func (ev EquivocationV0) Round() Round {
+	return ev.first.Fact().Round()
+}
+
+func (ev EquivocationV0) Stage() Stage {
+	return ev.first.Fact().Stage()
+}
+
+// First is the first received vote.
+func (ev EquivocationV0) First() SignedBallotFact {
+	return ev.first
+}
+
+// Second is the vote, which is received after First.
+func (ev EquivocationV0) Second() SignedBallotFact {
+	return ev.second
+}
+
+func (ev EquivocationV0) FoundAt() time.Time {
+	return ev.foundAt
+}
@@ -0,0 +1,31 @@
+package base
+
+import (
+	"time"
+
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (ev EquivocationV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(ev.Hint()), bson.M{
+		"first":    ev.first,
+		"second":   ev.second,
+		"found_at": ev.foundAt,
+	}))
+}
+
+type EquivocationV0UnpackerBSON struct {
+	FI bson.Raw  `bson:"first"`
+	SE bson.Raw  `bson:"second"`
+	FA time.Time `bson:"found_at"`
+}
+
+func (ev *EquivocationV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uev EquivocationV0UnpackerBSON
+	if err := enc.Unmarshal(b, &uev); err != nil {
+		return err
+	}
+
+	return ev.unpack(enc, uev.FI, uev.SE, uev.FA)
+}
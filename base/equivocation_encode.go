@@ -0,0 +1,26 @@
+package base
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/util/encoder"
+)
+
+func (ev *EquivocationV0) unpack(
+	enc encoder.Encoder,
+	bfirst,
+	bsecond []byte,
+	foundAt time.Time,
+) error {
+	if err := encoder.Decode(bfirst, enc, &ev.first); err != nil {
+		return err
+	}
+
+	if err := encoder.Decode(bsecond, enc, &ev.second); err != nil {
+		return err
+	}
+
+	ev.foundAt = foundAt
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package base
+
+import (
+	"encoding/json"
+
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+type EquivocationV0PackerJSON struct {
+	jsonenc.HintedHead
+	FI SignedBallotFact `json:"first"`
+	SE SignedBallotFact `json:"second"`
+	FA localtime.Time   `json:"found_at"`
+}
+
+func (ev EquivocationV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(EquivocationV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(ev.Hint()),
+		FI:         ev.first,
+		SE:         ev.second,
+		FA:         localtime.NewTime(ev.foundAt),
+	})
+}
+
+type EquivocationV0UnpackerJSON struct {
+	FI json.RawMessage `json:"first"`
+	SE json.RawMessage `json:"second"`
+	FA localtime.Time  `json:"found_at"`
+}
+
+func (ev *EquivocationV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uev EquivocationV0UnpackerJSON
+	if err := enc.Unmarshal(b, &uev); err != nil {
+		return err
+	}
+
+	return ev.unpack(enc, uev.FI, uev.SE, uev.FA.Time)
+}
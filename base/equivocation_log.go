@@ -0,0 +1,15 @@
+package base
+
+import (
+	"github.com/rs/zerolog"
+)
+
+func (ev EquivocationV0) MarshalZerologObject(e *zerolog.Event) {
+	e.
+		Stringer("node", ev.Node()).
+		Int64("height", ev.Height().Int64()).
+		Uint64("round", ev.Round().Uint64()).
+		Stringer("stage", ev.Stage()).
+		Stringer("first", ev.first.Fact().Hash()).
+		Stringer("second", ev.second.Fact().Hash())
+}
@@ -0,0 +1,126 @@
+package base
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/stretchr/testify/suite"
+)
+
+type testEquivocation struct {
+	suite.Suite
+	networkID NetworkID
+}
+
+func (t *testEquivocation) SetupSuite() {
+	t.networkID = NetworkID([]byte("showme"))
+}
+
+func (t *testEquivocation) newSigned(n *DummyNode, height Height, round Round, stage Stage) SignedBallotFact {
+	fact := NewDummyBallotFact()
+	fact.HT = height
+	fact.R = round
+	fact.S = stage
+
+	sf, err := NewBaseSignedBallotFactFromFact(fact, n.Address(), n.Privatekey(), t.networkID)
+	t.NoError(err)
+
+	return sf
+}
+
+func (t *testEquivocation) TestNew() {
+	n := RandomNode("n0")
+
+	ev := NewEquivocationV0(
+		t.newSigned(n, Height(33), Round(1), StageACCEPT),
+		t.newSigned(n, Height(33), Round(1), StageACCEPT),
+		localtime.UTCNow(),
+	)
+	t.NoError(ev.IsValid(t.networkID))
+
+	t.True(n.Address().Equal(ev.Node()))
+	t.Equal(Height(33), ev.Height())
+	t.Equal(Round(1), ev.Round())
+	t.Equal(StageACCEPT, ev.Stage())
+	t.Equal(fmt.Sprintf("33-1-%d-%s", StageACCEPT, n.Address()), ev.Key())
+}
+
+func (t *testEquivocation) TestInvalid() {
+	n0 := RandomNode("n0")
+	n1 := RandomNode("n1")
+
+	first := t.newSigned(n0, Height(33), Round(1), StageACCEPT)
+
+	cases := []struct {
+		name   string
+		second SignedBallotFact
+		err    string
+	}{
+		{name: "different node", second: t.newSigned(n1, Height(33), Round(1), StageACCEPT), err: "different nodes"},
+		{name: "different height", second: t.newSigned(n0, Height(34), Round(1), StageACCEPT), err: "different heights"},
+		{name: "different round", second: t.newSigned(n0, Height(33), Round(2), StageACCEPT), err: "different rounds"},
+		{name: "different stage", second: t.newSigned(n0, Height(33), Round(1), StageINIT), err: "different stages"},
+		{name: "same fact", second: first, err: "same ballot fact"},
+	}
+
+	for i, c := range cases {
+		ev := NewEquivocationV0(first, c.second, localtime.UTCNow())
+
+		err := ev.IsValid(t.networkID)
+		t.Error(err, "%d: %v", i, c.name)
+		t.Contains(err.Error(), c.err, "%d: %v", i, c.name)
+	}
+
+	// NOTE signed by different network id
+	ev := NewEquivocationV0(first, t.newSigned(n0, Height(33), Round(1), StageACCEPT), localtime.UTCNow())
+	t.Error(ev.IsValid(NetworkID([]byte("findme"))))
+}
+
+func (t *testEquivocation) testEncode(enc encoder.Encoder) {
+	_ = enc.Add(StringAddressHinter)
+	_ = enc.Add(key.BasePublickey{})
+	_ = enc.Add(DummyBallotFact{})
+	_ = enc.Add(SignedBallotFactHinter)
+	_ = enc.Add(BallotFactSignHinter)
+	_ = enc.Add(EquivocationV0Hinter)
+
+	n := RandomNode("n0")
+
+	ev := NewEquivocationV0(
+		t.newSigned(n, Height(33), Round(1), StageINIT),
+		t.newSigned(n, Height(33), Round(1), StageINIT),
+		localtime.UTCNow(),
+	)
+
+	b, err := enc.Marshal(ev)
+	t.NoError(err)
+
+	hinter, err := enc.Decode(b)
+	t.NoError(err)
+
+	uev, ok := hinter.(EquivocationV0)
+	t.True(ok)
+
+	t.NoError(uev.IsValid(t.networkID))
+	t.Equal(ev.Key(), uev.Key())
+	t.True(ev.First().Fact().Hash().Equal(uev.First().Fact().Hash()))
+	t.True(ev.Second().Fact().Hash().Equal(uev.Second().Fact().Hash()))
+	t.True(localtime.Equal(ev.FoundAt(), uev.FoundAt()))
+}
+
+func (t *testEquivocation) TestEncodeJSON() {
+	t.testEncode(jsonenc.NewEncoder())
+}
+
+func (t *testEquivocation) TestEncodeBSON() {
+	t.testEncode(bsonenc.NewEncoder())
+}
+
+func TestEquivocation(t *testing.T) {
+	suite.Run(t, new(testEquivocation))
+}
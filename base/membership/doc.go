@@ -0,0 +1,5 @@
+/*
+Package membership provides the operations to change the suffrage nodes and
+their weights.
+*/
+package membership
@@ -0,0 +1,169 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	JoinSuffrageFactType   = hint.Type("join-suffrage-operation-fact")
+	JoinSuffrageFactHint   = hint.NewHint(JoinSuffrageFactType, "v0.0.1")
+	JoinSuffrageFactHinter = JoinSuffrageFact{BaseHinter: hint.NewBaseHinter(JoinSuffrageFactHint)}
+	JoinSuffrageType       = hint.Type("join-suffrage-operation")
+	JoinSuffrageHint       = hint.NewHint(JoinSuffrageType, "v0.0.1")
+	JoinSuffrageHinter     = JoinSuffrage{BaseOperation: operation.EmptyBaseOperation(JoinSuffrageHint)}
+)
+
+// JoinSuffrageFact adds new node to the suffrage with the given weight.
+type JoinSuffrageFact struct {
+	hint.BaseHinter
+	h         valuehash.Hash
+	token     []byte
+	node      base.Address
+	publickey key.Publickey
+	weight    uint
+}
+
+func NewJoinSuffrageFact(
+	token []byte,
+	node base.Address,
+	publickey key.Publickey,
+	weight uint,
+) JoinSuffrageFact {
+	fact := JoinSuffrageFact{
+		BaseHinter: hint.NewBaseHinter(JoinSuffrageFactHint),
+		token:      token,
+		node:       node,
+		publickey:  publickey,
+		weight:     weight,
+	}
+	fact.h = fact.GenerateHash()
+
+	return fact
+}
+
+func (fact JoinSuffrageFact) IsValid(networkID []byte) error {
+	if err := fact.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	if err := operation.IsValidOperationFact(fact, networkID); err != nil {
+		return err
+	}
+
+	if err := isvalid.Check(nil, false, fact.node, fact.publickey); err != nil {
+		return err
+	}
+
+	if fact.weight < 1 {
+		return isvalid.InvalidError.Errorf("weight should be over zero")
+	}
+
+	if !fact.h.Equal(fact.GenerateHash()) {
+		return isvalid.InvalidError.Errorf("wrong fact hash")
+	}
+
+	return nil
+}
+
+func (fact JoinSuffrageFact) Hash() valuehash.Hash {
+	return fact.h
+}
+
+func (fact JoinSuffrageFact) GenerateHash() valuehash.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact JoinSuffrageFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.token,
+		fact.node.Bytes(),
+		fact.publickey.Bytes(),
+		util.UintToBytes(fact.weight),
+	)
+}
+
+func (fact JoinSuffrageFact) Token() []byte {
+	return fact.token
+}
+
+func (fact JoinSuffrageFact) Node() base.Address {
+	return fact.node
+}
+
+func (fact JoinSuffrageFact) Publickey() key.Publickey {
+	return fact.publickey
+}
+
+func (fact JoinSuffrageFact) Weight() uint {
+	return fact.weight
+}
+
+// JoinSuffrage should be signed by the joining node. The signs of the existing
+// suffrage nodes, which are over threshold, are also needed; it is checked by
+// OperationProcessor.
+type JoinSuffrage struct {
+	operation.BaseOperation
+}
+
+func NewJoinSuffrage(fact JoinSuffrageFact, fs []base.FactSign) (JoinSuffrage, error) {
+	bo, err := operation.NewBaseOperationFromFact(JoinSuffrageHint, fact, fs)
+	if err != nil {
+		return JoinSuffrage{}, err
+	}
+
+	return JoinSuffrage{BaseOperation: bo}, nil
+}
+
+func (op JoinSuffrage) IsValid(networkID []byte) error {
+	if err := op.BaseOperation.IsValid(networkID); err != nil {
+		return err
+	}
+
+	fact, ok := op.Fact().(JoinSuffrageFact)
+	if !ok {
+		return isvalid.InvalidError.Errorf("not JoinSuffrageFact, %T", op.Fact())
+	}
+
+	if !isSignedBy(op.Signs(), fact.publickey) {
+		return isvalid.InvalidError.Errorf("not signed by joining node, %q", fact.node)
+	}
+
+	return nil
+}
+
+func (op JoinSuffrage) AddFactSigns(fs ...base.FactSign) (base.FactSignUpdater, error) {
+	i, err := op.BaseOperation.AddFactSigns(fs...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.BaseOperation = i.(operation.BaseOperation)
+
+	return op, nil
+}
+
+// Process of JoinSuffrage does nothing; JoinSuffrage is processed by
+// OperationProcessor.
+func (JoinSuffrage) Process(
+	func(key string) (state.State, bool, error),
+	func(valuehash.Hash, ...state.State) error,
+) error {
+	return operation.NewBaseReasonError("join suffrage operation should be processed by membership processor")
+}
+
+func isSignedBy(fs []base.FactSign, pub key.Publickey) bool {
+	for i := range fs {
+		if fs[i].Signer().Equal(pub) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,39 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (fact JoinSuffrageFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(fact.Hint()),
+		bson.M{
+			"hash":      fact.h,
+			"token":     fact.token,
+			"node":      fact.node,
+			"publickey": fact.publickey,
+			"weight":    fact.weight,
+		},
+	))
+}
+
+type JoinSuffrageFactBSONUnpacker struct {
+	H  valuehash.Bytes      `bson:"hash"`
+	TK []byte               `bson:"token"`
+	ND base.AddressDecoder  `bson:"node"`
+	PK key.PublickeyDecoder `bson:"publickey"`
+	WT uint                 `bson:"weight"`
+}
+
+func (fact *JoinSuffrageFact) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uf JoinSuffrageFactBSONUnpacker
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, uf.H, uf.TK, uf.ND, uf.PK, uf.WT)
+}
@@ -0,0 +1,35 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (fact *JoinSuffrageFact) unpack(
+	enc encoder.Encoder,
+	h valuehash.Hash,
+	token []byte,
+	bnode base.AddressDecoder,
+	bpk key.PublickeyDecoder,
+	weight uint,
+) error {
+	node, err := bnode.Encode(enc)
+	if err != nil {
+		return err
+	}
+
+	pk, err := bpk.Encode(enc)
+	if err != nil {
+		return err
+	}
+
+	fact.h = h
+	fact.token = token
+	fact.node = node
+	fact.publickey = pk
+	fact.weight = weight
+
+	return nil
+}
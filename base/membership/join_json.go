@@ -0,0 +1,45 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type JoinSuffrageFactJSONPacker struct {
+	jsonenc.HintedHead
+	H  valuehash.Hash `json:"hash"`
+	TK []byte         `json:"token"`
+	ND base.Address   `json:"node"`
+	PK key.Publickey  `json:"publickey"`
+	WT uint           `json:"weight"`
+}
+
+func (fact JoinSuffrageFact) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(JoinSuffrageFactJSONPacker{
+		HintedHead: jsonenc.NewHintedHead(fact.Hint()),
+		H:          fact.h,
+		TK:         fact.token,
+		ND:         fact.node,
+		PK:         fact.publickey,
+		WT:         fact.weight,
+	})
+}
+
+type JoinSuffrageFactJSONUnpacker struct {
+	H  valuehash.Bytes      `json:"hash"`
+	TK []byte               `json:"token"`
+	ND base.AddressDecoder  `json:"node"`
+	PK key.PublickeyDecoder `json:"publickey"`
+	WT uint                 `json:"weight"`
+}
+
+func (fact *JoinSuffrageFact) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uf JoinSuffrageFactJSONUnpacker
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, uf.H, uf.TK, uf.ND, uf.PK, uf.WT)
+}
@@ -0,0 +1,160 @@
+package membership
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/stretchr/testify/suite"
+)
+
+func newFactSign(priv key.Privatekey, fact base.Fact, networkID []byte) (base.FactSign, error) {
+	sig, err := base.NewFactSignature(priv, fact, networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return base.NewBaseFactSign(priv.Publickey(), sig), nil
+}
+
+type testJoinSuffrage struct {
+	suite.Suite
+	networkID []byte
+}
+
+func (t *testJoinSuffrage) SetupSuite() {
+	t.networkID = util.UUID().Bytes()
+}
+
+func (t *testJoinSuffrage) TestNew() {
+	priv := key.NewBasePrivatekey()
+	fact := NewJoinSuffrageFact(util.UUID().Bytes(), base.RandomStringAddress(), priv.Publickey(), 3)
+	t.NoError(fact.IsValid(nil))
+
+	fs, err := newFactSign(priv, fact, t.networkID)
+	t.NoError(err)
+
+	op, err := NewJoinSuffrage(fact, []base.FactSign{fs})
+	t.NoError(err)
+	t.NoError(op.IsValid(t.networkID))
+}
+
+func (t *testJoinSuffrage) TestZeroWeight() {
+	priv := key.NewBasePrivatekey()
+	fact := NewJoinSuffrageFact(util.UUID().Bytes(), base.RandomStringAddress(), priv.Publickey(), 0)
+
+	err := fact.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "weight should be over zero")
+}
+
+func (t *testJoinSuffrage) TestNotSignedByJoiningNode() {
+	priv := key.NewBasePrivatekey()
+	fact := NewJoinSuffrageFact(util.UUID().Bytes(), base.RandomStringAddress(), priv.Publickey(), 1)
+
+	fs, err := newFactSign(key.NewBasePrivatekey(), fact, t.networkID)
+	t.NoError(err)
+
+	op, err := NewJoinSuffrage(fact, []base.FactSign{fs})
+	t.NoError(err)
+
+	err = op.IsValid(t.networkID)
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "not signed by joining node")
+}
+
+func TestJoinSuffrage(t *testing.T) {
+	suite.Run(t, new(testJoinSuffrage))
+}
+
+type testJoinSuffrageEncode struct {
+	suite.Suite
+	enc  encoder.Encoder
+	encs *encoder.Encoders
+}
+
+func (t *testJoinSuffrageEncode) SetupSuite() {
+	t.encs = encoder.NewEncoders()
+	_ = t.encs.AddEncoder(t.enc)
+
+	_ = t.encs.TestAddHinter(base.StringAddressHinter)
+	_ = t.encs.TestAddHinter(base.BaseFactSignHinter)
+	_ = t.encs.TestAddHinter(key.BasePublickey{})
+	_ = t.encs.TestAddHinter(JoinSuffrageFactHinter)
+	_ = t.encs.TestAddHinter(JoinSuffrageHinter)
+	_ = t.encs.TestAddHinter(LeaveSuffrageFactHinter)
+	_ = t.encs.TestAddHinter(LeaveSuffrageHinter)
+}
+
+func (t *testJoinSuffrageEncode) TestJoin() {
+	networkID := util.UUID().Bytes()
+
+	priv := key.NewBasePrivatekey()
+	fact := NewJoinSuffrageFact(util.UUID().Bytes(), base.RandomStringAddress(), priv.Publickey(), 3)
+
+	fs, err := newFactSign(priv, fact, networkID)
+	t.NoError(err)
+
+	op, err := NewJoinSuffrage(fact, []base.FactSign{fs})
+	t.NoError(err)
+
+	b, err := t.enc.Marshal(op)
+	t.NoError(err)
+
+	hinter, err := t.enc.Decode(b)
+	t.NoError(err)
+
+	uop, ok := hinter.(JoinSuffrage)
+	t.True(ok)
+	t.NoError(uop.IsValid(networkID))
+	t.True(op.Hash().Equal(uop.Hash()))
+
+	ufact := uop.Fact().(JoinSuffrageFact)
+	t.True(fact.Hash().Equal(ufact.Hash()))
+	t.Equal(fact.Token(), ufact.Token())
+	t.True(fact.Node().Equal(ufact.Node()))
+	t.True(fact.Publickey().Equal(ufact.Publickey()))
+	t.Equal(fact.Weight(), ufact.Weight())
+}
+
+func (t *testJoinSuffrageEncode) TestLeave() {
+	networkID := util.UUID().Bytes()
+
+	fact := NewLeaveSuffrageFact(util.UUID().Bytes(), base.RandomStringAddress())
+
+	fs, err := newFactSign(key.NewBasePrivatekey(), fact, networkID)
+	t.NoError(err)
+
+	op, err := NewLeaveSuffrage(fact, []base.FactSign{fs})
+	t.NoError(err)
+
+	b, err := t.enc.Marshal(op)
+	t.NoError(err)
+
+	hinter, err := t.enc.Decode(b)
+	t.NoError(err)
+
+	uop, ok := hinter.(LeaveSuffrage)
+	t.True(ok)
+	t.NoError(uop.IsValid(networkID))
+	t.True(op.Hash().Equal(uop.Hash()))
+
+	ufact := uop.Fact().(LeaveSuffrageFact)
+	t.True(fact.Hash().Equal(ufact.Hash()))
+	t.Equal(fact.Token(), ufact.Token())
+	t.True(fact.Node().Equal(ufact.Node()))
+}
+
+func TestJoinSuffrageEncodeJSON(t *testing.T) {
+	suite.Run(t, &testJoinSuffrageEncode{enc: jsonenc.NewEncoder()})
+}
+
+func TestJoinSuffrageEncodeBSON(t *testing.T) {
+	suite.Run(t, &testJoinSuffrageEncode{enc: bsonenc.NewEncoder()})
+}
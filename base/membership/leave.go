@@ -0,0 +1,126 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	LeaveSuffrageFactType   = hint.Type("leave-suffrage-operation-fact")
+	LeaveSuffrageFactHint   = hint.NewHint(LeaveSuffrageFactType, "v0.0.1")
+	LeaveSuffrageFactHinter = LeaveSuffrageFact{BaseHinter: hint.NewBaseHinter(LeaveSuffrageFactHint)}
+	LeaveSuffrageType       = hint.Type("leave-suffrage-operation")
+	LeaveSuffrageHint       = hint.NewHint(LeaveSuffrageType, "v0.0.1")
+	LeaveSuffrageHinter     = LeaveSuffrage{BaseOperation: operation.EmptyBaseOperation(LeaveSuffrageHint)}
+)
+
+// LeaveSuffrageFact removes the node from the suffrage.
+type LeaveSuffrageFact struct {
+	hint.BaseHinter
+	h     valuehash.Hash
+	token []byte
+	node  base.Address
+}
+
+func NewLeaveSuffrageFact(token []byte, node base.Address) LeaveSuffrageFact {
+	fact := LeaveSuffrageFact{
+		BaseHinter: hint.NewBaseHinter(LeaveSuffrageFactHint),
+		token:      token,
+		node:       node,
+	}
+	fact.h = fact.GenerateHash()
+
+	return fact
+}
+
+func (fact LeaveSuffrageFact) IsValid(networkID []byte) error {
+	if err := fact.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	if err := operation.IsValidOperationFact(fact, networkID); err != nil {
+		return err
+	}
+
+	if err := isvalid.Check(nil, false, fact.node); err != nil {
+		return err
+	}
+
+	if !fact.h.Equal(fact.GenerateHash()) {
+		return isvalid.InvalidError.Errorf("wrong fact hash")
+	}
+
+	return nil
+}
+
+func (fact LeaveSuffrageFact) Hash() valuehash.Hash {
+	return fact.h
+}
+
+func (fact LeaveSuffrageFact) GenerateHash() valuehash.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact LeaveSuffrageFact) Bytes() []byte {
+	return util.ConcatBytesSlice(fact.token, fact.node.Bytes())
+}
+
+func (fact LeaveSuffrageFact) Token() []byte {
+	return fact.token
+}
+
+func (fact LeaveSuffrageFact) Node() base.Address {
+	return fact.node
+}
+
+// LeaveSuffrage should be signed by the leaving node or by the other suffrage
+// nodes, which are over threshold; it is checked by OperationProcessor.
+type LeaveSuffrage struct {
+	operation.BaseOperation
+}
+
+func NewLeaveSuffrage(fact LeaveSuffrageFact, fs []base.FactSign) (LeaveSuffrage, error) {
+	bo, err := operation.NewBaseOperationFromFact(LeaveSuffrageHint, fact, fs)
+	if err != nil {
+		return LeaveSuffrage{}, err
+	}
+
+	return LeaveSuffrage{BaseOperation: bo}, nil
+}
+
+func (op LeaveSuffrage) IsValid(networkID []byte) error {
+	if err := op.BaseOperation.IsValid(networkID); err != nil {
+		return err
+	}
+
+	if _, ok := op.Fact().(LeaveSuffrageFact); !ok {
+		return isvalid.InvalidError.Errorf("not LeaveSuffrageFact, %T", op.Fact())
+	}
+
+	return nil
+}
+
+func (op LeaveSuffrage) AddFactSigns(fs ...base.FactSign) (base.FactSignUpdater, error) {
+	i, err := op.BaseOperation.AddFactSigns(fs...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.BaseOperation = i.(operation.BaseOperation)
+
+	return op, nil
+}
+
+// Process of LeaveSuffrage does nothing; LeaveSuffrage is processed by
+// OperationProcessor.
+func (LeaveSuffrage) Process(
+	func(key string) (state.State, bool, error),
+	func(valuehash.Hash, ...state.State) error,
+) error {
+	return operation.NewBaseReasonError("leave suffrage operation should be processed by membership processor")
+}
@@ -0,0 +1,34 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (fact LeaveSuffrageFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(fact.Hint()),
+		bson.M{
+			"hash":  fact.h,
+			"token": fact.token,
+			"node":  fact.node,
+		},
+	))
+}
+
+type LeaveSuffrageFactBSONUnpacker struct {
+	H  valuehash.Bytes     `bson:"hash"`
+	TK []byte              `bson:"token"`
+	ND base.AddressDecoder `bson:"node"`
+}
+
+func (fact *LeaveSuffrageFact) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uf LeaveSuffrageFactBSONUnpacker
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, uf.H, uf.TK, uf.ND)
+}
@@ -0,0 +1,25 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (fact *LeaveSuffrageFact) unpack(
+	enc encoder.Encoder,
+	h valuehash.Hash,
+	token []byte,
+	bnode base.AddressDecoder,
+) error {
+	node, err := bnode.Encode(enc)
+	if err != nil {
+		return err
+	}
+
+	fact.h = h
+	fact.token = token
+	fact.node = node
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package membership
+
+import (
+	"github.com/spikeekips/mitum/base"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type LeaveSuffrageFactJSONPacker struct {
+	jsonenc.HintedHead
+	H  valuehash.Hash `json:"hash"`
+	TK []byte         `json:"token"`
+	ND base.Address   `json:"node"`
+}
+
+func (fact LeaveSuffrageFact) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(LeaveSuffrageFactJSONPacker{
+		HintedHead: jsonenc.NewHintedHead(fact.Hint()),
+		H:          fact.h,
+		TK:         fact.token,
+		ND:         fact.node,
+	})
+}
+
+type LeaveSuffrageFactJSONUnpacker struct {
+	H  valuehash.Bytes     `json:"hash"`
+	TK []byte              `json:"token"`
+	ND base.AddressDecoder `json:"node"`
+}
+
+func (fact *LeaveSuffrageFact) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uf LeaveSuffrageFactJSONUnpacker
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, uf.H, uf.TK, uf.ND)
+}
@@ -0,0 +1,495 @@
+package membership
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/prprocessor"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// NodeStateKey is the state key of the node, which joined by JoinSuffrage. The
+// state has the node.BaseV0 of node.
+func NodeStateKey(stateKey string, n base.Address) string {
+	return NodeStateKeyPrefix(stateKey) + n.String()
+}
+
+// NodeStateKeyPrefix is the common prefix of the node state keys.
+func NodeStateKeyPrefix(stateKey string) string {
+	return fmt.Sprintf("%s:node:", stateKey)
+}
+
+// OperationProcessor processes JoinSuffrage and LeaveSuffrage. The suffrage
+// weights are stored in the state of stateKey; if the state does not exist,
+// genesis weights is used.
+//
+// The membership operations in one block are processed by order in
+// PreProcess and the states are set when the processor is closed, so the
+// operations in same block can be applied one by one. One processor is shared
+// by JoinSuffrage and LeaveSuffrage for same Statepool.
+//
+// The publickeys of suffrage nodes are resolved from the node states of the
+// Statepool, not from the local nodepool, so every node processes the
+// operations with same keys. With the first suffrage weights state, the node
+// states of the genesis suffrage nodes are also stored.
+type OperationProcessor struct {
+	sync.Mutex
+	stateKey             string
+	genesis              base.SuffrageWeights
+	genesisNodes         map[string]key.Publickey
+	ratio                base.ThresholdRatio
+	hasOperationFactFunc func(valuehash.Hash) (bool, error)
+	processors           map[*storage.Statepool]*operationProcessor
+}
+
+// NewOperationProcessor creates new OperationProcessor. genesisNodes have the
+// publickeys of the genesis suffrage nodes; they are used only before the
+// suffrage weights state is stored.
+func NewOperationProcessor(
+	stateKey string,
+	genesis base.SuffrageWeights,
+	genesisNodes []base.Node,
+	ratio base.ThresholdRatio,
+	hasOperationFactFunc func(valuehash.Hash) (bool, error),
+) *OperationProcessor {
+	pubs := map[string]key.Publickey{}
+	for i := range genesisNodes {
+		n := genesisNodes[i]
+		if genesis.Exists(n.Address()) {
+			pubs[n.Address().String()] = n.Publickey()
+		}
+	}
+
+	return &OperationProcessor{
+		stateKey:             stateKey,
+		genesis:              genesis,
+		genesisNodes:         pubs,
+		ratio:                ratio,
+		hasOperationFactFunc: hasOperationFactFunc,
+		processors:           map[*storage.Statepool]*operationProcessor{},
+	}
+}
+
+func (opp *OperationProcessor) New(pool *storage.Statepool) prprocessor.OperationProcessor {
+	opp.Lock()
+	defer opp.Unlock()
+
+	if opr, found := opp.processors[pool]; found {
+		return opr
+	}
+
+	opr := &operationProcessor{
+		parent: opp,
+		pool:   pool,
+		nodes:  map[string]state.State{},
+	}
+
+	opp.processors[pool] = opr
+
+	return opr
+}
+
+// Signers returns the suffrage weights and the publickey getter of the
+// suffrage nodes from the states of pool. Like the membership operations, the
+// other operations, which should be signed by suffrage nodes, can check the
+// signs with them.
+func (opp *OperationProcessor) Signers(pool *storage.Statepool) (
+	base.SuffrageWeights, func(base.Address) (key.Publickey, bool, error), error,
+) {
+	opr := &operationProcessor{
+		parent: opp,
+		pool:   pool,
+		nodes:  map[string]state.State{},
+	}
+
+	sw, err := opr.currentWeights()
+	if err != nil {
+		return base.SuffrageWeights{}, nil, err
+	}
+
+	return sw, opr.publickey, nil
+}
+
+func (*OperationProcessor) PreProcess(state.Processor) (state.Processor, error) {
+	return nil, errors.Errorf("OperationProcessor should be created by New()")
+}
+
+func (*OperationProcessor) Process(state.Processor) error {
+	return errors.Errorf("OperationProcessor should be created by New()")
+}
+
+func (*OperationProcessor) Close() error {
+	return nil
+}
+
+func (*OperationProcessor) Cancel() error {
+	return nil
+}
+
+func (opp *OperationProcessor) remove(pool *storage.Statepool) {
+	opp.Lock()
+	defer opp.Unlock()
+
+	delete(opp.processors, pool)
+}
+
+type membershipUpdate struct {
+	fact    valuehash.Hash
+	nodeKey string
+}
+
+type operationProcessor struct {
+	sync.Mutex
+	parent        *OperationProcessor
+	pool          *storage.Statepool
+	weights       *base.SuffrageWeights
+	weightsState  state.State
+	weightsStored bool
+	nodes         map[string]state.State
+	updates       []membershipUpdate
+	closed        bool
+}
+
+func (opr *operationProcessor) New(*storage.Statepool) prprocessor.OperationProcessor {
+	return opr
+}
+
+func (opr *operationProcessor) PreProcess(op state.Processor) (state.Processor, error) {
+	opr.Lock()
+	defer opr.Unlock()
+
+	var fact valuehash.Hash
+	switch t := op.(type) {
+	case JoinSuffrage:
+		fact = t.Fact().Hash()
+	case LeaveSuffrage:
+		fact = t.Fact().Hash()
+	default:
+		return nil, errors.Errorf("not membership operation, %T", op)
+	}
+
+	if opr.parent.hasOperationFactFunc != nil {
+		switch found, err := opr.parent.hasOperationFactFunc(fact); {
+		case err != nil:
+			return nil, err
+		case found:
+			return nil, operation.NewBaseReasonError("known operation")
+		}
+	}
+
+	sw, err := opr.currentWeights()
+	if err != nil {
+		return nil, err
+	}
+
+	var nsw base.SuffrageWeights
+	var nodeKey string
+	switch t := op.(type) {
+	case JoinSuffrage:
+		nsw, nodeKey, err = opr.join(t, sw)
+	case LeaveSuffrage:
+		nsw, err = opr.leave(t, sw)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	opr.weights = &nsw
+	opr.updates = append(opr.updates, membershipUpdate{fact: fact, nodeKey: nodeKey})
+
+	return op, nil
+}
+
+// Process does nothing; the membership operations are already processed in
+// PreProcess.
+func (*operationProcessor) Process(state.Processor) error {
+	return nil
+}
+
+// Close sets the updated states to Statepool.
+func (opr *operationProcessor) Close() error {
+	opr.Lock()
+	defer opr.Unlock()
+
+	if opr.closed {
+		return nil
+	}
+
+	opr.closed = true
+	opr.parent.remove(opr.pool)
+
+	if len(opr.updates) < 1 {
+		return nil
+	}
+
+	v, err := state.NewHintedValue(*opr.weights)
+	if err != nil {
+		return err
+	}
+
+	wst, err := opr.weightsState.SetValue(v)
+	if err != nil {
+		return err
+	}
+
+	var genesisNodes []state.State
+	if !opr.weightsStored {
+		i, err := opr.genesisNodeStates()
+		if err != nil {
+			return err
+		}
+		genesisNodes = i
+	}
+
+	for i := range opr.updates {
+		u := opr.updates[i]
+
+		sts := []state.State{wst}
+		if len(u.nodeKey) > 0 {
+			sts = append(sts, opr.nodes[u.nodeKey])
+		}
+
+		if i == 0 {
+			sts = append(sts, genesisNodes...)
+		}
+
+		if err := opr.pool.Set(u.fact, sts...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (opr *operationProcessor) Cancel() error {
+	opr.Lock()
+	defer opr.Unlock()
+
+	opr.closed = true
+	opr.parent.remove(opr.pool)
+
+	return nil
+}
+
+func (opr *operationProcessor) join(op JoinSuffrage, sw base.SuffrageWeights) (base.SuffrageWeights, string, error) {
+	fact := op.Fact().(JoinSuffrageFact)
+
+	if sw.Exists(fact.Node()) {
+		return base.SuffrageWeights{}, "", operation.NewBaseReasonError("already in suffrage, %q", fact.Node())
+	}
+
+	switch ok, err := opr.isSignedByThreshold(op.Signs(), sw); {
+	case err != nil:
+		return base.SuffrageWeights{}, "", err
+	case !ok:
+		return base.SuffrageWeights{}, "", operation.NewBaseReasonError("not enough signs of suffrage nodes")
+	}
+
+	m := opr.weightsMap(sw)
+	m[fact.Node()] = fact.Weight()
+
+	nsw := base.NewSuffrageWeights(m)
+	if err := nsw.IsValid(nil); err != nil {
+		return base.SuffrageWeights{}, "", operation.NewBaseReasonErrorFromError(err)
+	}
+
+	nodeKey := NodeStateKey(opr.parent.stateKey, fact.Node())
+
+	nst, err := opr.nodeState(nodeKey, fact.Node(), fact.Publickey())
+	if err != nil {
+		return base.SuffrageWeights{}, "", err
+	}
+
+	opr.nodes[nodeKey] = nst
+
+	return nsw, nodeKey, nil
+}
+
+func (opr *operationProcessor) leave(op LeaveSuffrage, sw base.SuffrageWeights) (base.SuffrageWeights, error) {
+	fact := op.Fact().(LeaveSuffrageFact)
+
+	if !sw.Exists(fact.Node()) {
+		return base.SuffrageWeights{}, operation.NewBaseReasonError("not in suffrage, %q", fact.Node())
+	}
+
+	if len(sw.Nodes()) < 2 {
+		return base.SuffrageWeights{}, operation.NewBaseReasonError("last suffrage node can not leave")
+	}
+
+	var signed bool
+	switch pub, found, err := opr.publickey(fact.Node()); {
+	case err != nil:
+		return base.SuffrageWeights{}, err
+	case found:
+		signed = isSignedBy(op.Signs(), pub)
+	}
+
+	if !signed {
+		switch ok, err := opr.isSignedByThreshold(op.Signs(), sw); {
+		case err != nil:
+			return base.SuffrageWeights{}, err
+		case !ok:
+			return base.SuffrageWeights{}, operation.NewBaseReasonError(
+				"not signed by leaving node or not enough signs of suffrage nodes")
+		}
+	}
+
+	m := opr.weightsMap(sw)
+	for k := range m {
+		if k.Equal(fact.Node()) {
+			delete(m, k)
+		}
+	}
+
+	return base.NewSuffrageWeights(m), nil
+}
+
+func (opr *operationProcessor) currentWeights() (base.SuffrageWeights, error) {
+	if opr.weights != nil {
+		return *opr.weights, nil
+	}
+
+	st, found, err := opr.pool.Get(opr.parent.stateKey)
+	if err != nil {
+		return base.SuffrageWeights{}, err
+	}
+
+	opr.weightsState = st
+
+	opr.weightsStored = found && st.Value() != nil
+
+	sw := opr.parent.genesis
+	if opr.weightsStored {
+		i, ok := st.Value().Interface().(base.SuffrageWeights)
+		if !ok {
+			return base.SuffrageWeights{}, errors.Errorf(
+				"invalid suffrage weights state value, %T", st.Value().Interface())
+		}
+
+		sw = i
+	}
+
+	opr.weights = &sw
+
+	return sw, nil
+}
+
+func (*operationProcessor) weightsMap(sw base.SuffrageWeights) map[base.Address]uint {
+	m := map[base.Address]uint{}
+	nodes := sw.Nodes()
+	weights := sw.Weights()
+	for i := range nodes {
+		m[nodes[i]] = weights[i]
+	}
+
+	return m
+}
+
+// isSignedByThreshold checks the sum of the weights of signed suffrage nodes
+// is over threshold.
+func (opr *operationProcessor) isSignedByThreshold(fs []base.FactSign, sw base.SuffrageWeights) (bool, error) {
+	threshold, err := base.NewThreshold(sw.Total(), opr.parent.ratio)
+	if err != nil {
+		return false, err
+	}
+
+	var signed uint
+	nodes := sw.Nodes()
+	for i := range nodes {
+		switch pub, found, err := opr.publickey(nodes[i]); {
+		case err != nil:
+			return false, err
+		case !found:
+			continue
+		case isSignedBy(fs, pub):
+			signed += sw.Weight(nodes[i])
+		}
+	}
+
+	return signed >= threshold.Threshold, nil
+}
+
+func (opr *operationProcessor) publickey(a base.Address) (key.Publickey, bool, error) {
+	nodeKey := NodeStateKey(opr.parent.stateKey, a)
+
+	st, found := opr.nodes[nodeKey]
+	if !found {
+		i, j, err := opr.pool.Get(nodeKey)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if j {
+			st = i
+		}
+	}
+
+	if st != nil && st.Value() != nil {
+		n, ok := st.Value().Interface().(base.Node)
+		if !ok {
+			return nil, false, errors.Errorf("invalid node state value, %T", st.Value().Interface())
+		}
+
+		return n.Publickey(), true, nil
+	}
+
+	// NOTE after the suffrage weights state is stored, the genesis nodes also
+	// have node states.
+	if opr.weightsStored {
+		return nil, false, nil
+	}
+
+	pub, found := opr.parent.genesisNodes[a.String()]
+
+	return pub, found, nil
+}
+
+// genesisNodeStates returns the node states of the genesis suffrage nodes,
+// which do not have node state yet.
+func (opr *operationProcessor) genesisNodeStates() ([]state.State, error) {
+	nodes := opr.parent.genesis.Nodes()
+
+	var sts []state.State
+	for i := range nodes {
+		pub, found := opr.parent.genesisNodes[nodes[i].String()]
+		if !found {
+			continue
+		}
+
+		nodeKey := NodeStateKey(opr.parent.stateKey, nodes[i])
+		if _, found := opr.nodes[nodeKey]; found {
+			continue
+		}
+
+		st, err := opr.nodeState(nodeKey, nodes[i], pub)
+		if err != nil {
+			return nil, err
+		}
+
+		sts = append(sts, st)
+	}
+
+	return sts, nil
+}
+
+func (opr *operationProcessor) nodeState(nodeKey string, a base.Address, pub key.Publickey) (state.State, error) {
+	st, _, err := opr.pool.Get(nodeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := state.NewHintedValue(node.NewBaseV0(a, pub))
+	if err != nil {
+		return nil, err
+	}
+
+	return st.SetValue(v)
+}
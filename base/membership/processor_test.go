@@ -0,0 +1,400 @@
+package membership
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	leveldbstorage "github.com/spikeekips/mitum/storage/leveldb"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testOperationProcessor struct {
+	suite.Suite
+	stateKey  string
+	networkID []byte
+	nodes     []base.Address
+	privs     map[string]key.Privatekey
+	db        storage.Database
+}
+
+func (t *testOperationProcessor) SetupTest() {
+	t.stateKey = "suffrage-weights"
+	t.networkID = util.UUID().Bytes()
+	t.privs = map[string]key.Privatekey{}
+	t.nodes = nil
+
+	for i := 0; i < 2; i++ {
+		t.newNode()
+	}
+
+	enc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders()
+	_ = encs.AddEncoder(enc)
+	_ = encs.TestAddHinter(base.StringAddressHinter)
+	_ = encs.TestAddHinter(base.SuffrageWeightsHinter)
+	_ = encs.TestAddHinter(key.BasePublickey{})
+	_ = encs.TestAddHinter(node.BaseV0Hinter)
+	_ = encs.TestAddHinter(state.HintedValueHinter)
+	_ = encs.TestAddHinter(state.StateV0{})
+
+	t.db = leveldbstorage.NewMemDatabase(encs, enc)
+}
+
+func (t *testOperationProcessor) newNode() base.Address {
+	a := base.RandomStringAddress()
+	t.nodes = append(t.nodes, a)
+	t.privs[a.String()] = key.NewBasePrivatekey()
+
+	return a
+}
+
+func (t *testOperationProcessor) genesis(nodes ...base.Address) base.SuffrageWeights {
+	m := map[base.Address]uint{}
+	for i := range nodes {
+		m[nodes[i]] = 1
+	}
+
+	return base.NewSuffrageWeights(m)
+}
+
+func (t *testOperationProcessor) newProcessor(genesis base.SuffrageWeights) *OperationProcessor {
+	nodes := genesis.Nodes()
+	genesisNodes := make([]base.Node, len(nodes))
+	for i := range nodes {
+		genesisNodes[i] = node.NewBaseV0(nodes[i], t.privs[nodes[i].String()].Publickey())
+	}
+
+	return NewOperationProcessor(
+		t.stateKey,
+		genesis,
+		genesisNodes,
+		base.ThresholdRatio(67),
+		nil,
+	)
+}
+
+func (t *testOperationProcessor) signs(fact base.Fact, signers ...base.Address) []base.FactSign {
+	fs := make([]base.FactSign, len(signers))
+	for i := range signers {
+		f, err := newFactSign(t.privs[signers[i].String()], fact, t.networkID)
+		t.NoError(err)
+
+		fs[i] = f
+	}
+
+	return fs
+}
+
+func (t *testOperationProcessor) newJoin(a base.Address, weight uint, signers ...base.Address) JoinSuffrage {
+	fact := NewJoinSuffrageFact(util.UUID().Bytes(), a, t.privs[a.String()].Publickey(), weight)
+
+	op, err := NewJoinSuffrage(fact, t.signs(fact, signers...))
+	t.NoError(err)
+
+	return op
+}
+
+func (t *testOperationProcessor) newLeave(a base.Address, signers ...base.Address) LeaveSuffrage {
+	fact := NewLeaveSuffrageFact(util.UUID().Bytes(), a)
+
+	op, err := NewLeaveSuffrage(fact, t.signs(fact, signers...))
+	t.NoError(err)
+
+	return op
+}
+
+func (t *testOperationProcessor) updated(pool *storage.Statepool, k string) (state.State, bool) {
+	sts := pool.Updates()
+	for i := range sts {
+		if sts[i].Key() == k {
+			return sts[i].GetState(), true
+		}
+	}
+
+	return nil, false
+}
+
+func (t *testOperationProcessor) updatedWeights(pool *storage.Statepool) base.SuffrageWeights {
+	st, found := t.updated(pool, t.stateKey)
+	t.True(found)
+
+	sw, ok := st.Value().Interface().(base.SuffrageWeights)
+	t.True(ok)
+
+	return sw
+}
+
+func (t *testOperationProcessor) TestJoin() {
+	n := t.newNode()
+
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opp := t.newProcessor(t.genesis(t.nodes[0], t.nodes[1]))
+	opr := opp.New(pool)
+
+	op := t.newJoin(n, 2, n, t.nodes[0], t.nodes[1])
+	_, err = opr.PreProcess(op)
+	t.NoError(err)
+	t.NoError(opr.Process(op))
+	t.NoError(opr.Close())
+
+	sw := t.updatedWeights(pool)
+	t.Equal(3, len(sw.Nodes()))
+	t.Equal(uint(2), sw.Weight(n))
+
+	st, found := t.updated(pool, NodeStateKey(t.stateKey, n))
+	t.True(found)
+
+	no, ok := st.Value().Interface().(base.Node)
+	t.True(ok)
+	t.True(n.Equal(no.Address()))
+	t.True(t.privs[n.String()].Publickey().Equal(no.Publickey()))
+}
+
+func (t *testOperationProcessor) TestJoinNotEnoughSigns() {
+	n := t.newNode()
+
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opp := t.newProcessor(t.genesis(t.nodes[0], t.nodes[1]))
+	opr := opp.New(pool)
+
+	_, err = opr.PreProcess(t.newJoin(n, 1, n, t.nodes[0]))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "not enough signs")
+
+	t.NoError(opr.Close())
+	t.Empty(pool.Updates())
+}
+
+func (t *testOperationProcessor) TestJoinAlreadyInSuffrage() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opp := t.newProcessor(t.genesis(t.nodes[0], t.nodes[1]))
+	opr := opp.New(pool)
+
+	_, err = opr.PreProcess(t.newJoin(t.nodes[1], 1, t.nodes[0], t.nodes[1]))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "already in suffrage")
+}
+
+func (t *testOperationProcessor) TestLeaveSignedByNode() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opp := t.newProcessor(t.genesis(t.nodes[0], t.nodes[1]))
+	opr := opp.New(pool)
+
+	_, err = opr.PreProcess(t.newLeave(t.nodes[1], t.nodes[1]))
+	t.NoError(err)
+	t.NoError(opr.Close())
+
+	sw := t.updatedWeights(pool)
+	t.Equal([]base.Address{t.nodes[0]}, sw.Nodes())
+}
+
+func (t *testOperationProcessor) TestLeaveNotSigned() {
+	n := t.newNode()
+
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opp := t.newProcessor(t.genesis(t.nodes[0], t.nodes[1], n))
+	opr := opp.New(pool)
+
+	_, err = opr.PreProcess(t.newLeave(n, t.nodes[0]))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "not signed by leaving node")
+}
+
+func (t *testOperationProcessor) TestLastNodeLeave() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opp := t.newProcessor(t.genesis(t.nodes[0]))
+	opr := opp.New(pool)
+
+	_, err = opr.PreProcess(t.newLeave(t.nodes[0], t.nodes[0]))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "last suffrage node")
+}
+
+func (t *testOperationProcessor) TestJoinAndLeaveInSamePool() {
+	n := t.newNode()
+
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opp := t.newProcessor(t.genesis(t.nodes[0], t.nodes[1]))
+
+	// NOTE same processor is shared by JoinSuffrage and LeaveSuffrage
+	opr := opp.New(pool)
+	t.Equal(opr, opp.New(pool))
+
+	_, err = opr.PreProcess(t.newJoin(n, 1, n, t.nodes[0], t.nodes[1]))
+	t.NoError(err)
+
+	// NOTE joined node signs by itself
+	_, err = opr.PreProcess(t.newLeave(t.nodes[0], t.nodes[0]))
+	t.NoError(err)
+
+	_, err = opr.PreProcess(t.newLeave(n, n))
+	t.NoError(err)
+
+	t.NoError(opr.Close())
+
+	sw := t.updatedWeights(pool)
+	t.Equal([]base.Address{t.nodes[1]}, sw.Nodes())
+
+	st, found := t.updated(pool, t.stateKey)
+	t.True(found)
+	t.Equal(3, len(st.Operations()))
+
+	// NOTE after closed, new processor is created
+	t.NotEqual(opr, opp.New(pool))
+}
+
+func (t *testOperationProcessor) TestKnownOperation() {
+	n := t.newNode()
+
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	op := t.newJoin(n, 1, n, t.nodes[0], t.nodes[1])
+
+	opp := t.newProcessor(t.genesis(t.nodes[0], t.nodes[1]))
+	opp.hasOperationFactFunc = func(h valuehash.Hash) (bool, error) {
+		return h.Equal(op.Fact().Hash()), nil
+	}
+
+	_, err = opp.New(pool).PreProcess(op)
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "known operation")
+}
+
+func (t *testOperationProcessor) TestGenesisNodeStates() {
+	n := t.newNode()
+
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	genesis := t.genesis(t.nodes[0], t.nodes[1])
+	opr := t.newProcessor(genesis).New(pool)
+
+	_, err = opr.PreProcess(t.newJoin(n, 1, n, t.nodes[0], t.nodes[1]))
+	t.NoError(err)
+	t.NoError(opr.Close())
+
+	// NOTE with the first suffrage weights state, the genesis nodes are stored
+	for _, a := range []base.Address{t.nodes[0], t.nodes[1], n} {
+		st, found := t.updated(pool, NodeStateKey(t.stateKey, a))
+		t.True(found, "node=%s", a)
+
+		no, ok := st.Value().Interface().(base.Node)
+		t.True(ok)
+		t.True(a.Equal(no.Address()))
+		t.True(t.privs[a.String()].Publickey().Equal(no.Publickey()))
+	}
+
+	sts := pool.Updates()
+	for i := range sts {
+		t.NoError(t.db.(storage.StateUpdater).NewState(sts[i].GetState()))
+	}
+
+	// NOTE after the suffrage weights state is stored, the publickeys are
+	// resolved only from the node states.
+	pool, err = storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opr = NewOperationProcessor(t.stateKey, genesis, nil, base.ThresholdRatio(67), nil).New(pool)
+
+	_, err = opr.PreProcess(t.newLeave(t.nodes[1], t.nodes[1]))
+	t.NoError(err)
+	t.NoError(opr.Close())
+
+	sw := t.updatedWeights(pool)
+	t.Equal(2, len(sw.Nodes()))
+	t.False(sw.Exists(t.nodes[1]))
+}
+
+func (t *testOperationProcessor) TestSigners() {
+	n := t.newNode()
+
+	genesis := t.genesis(t.nodes[0], t.nodes[1])
+
+	{ // NOTE before the suffrage weights state is stored, genesis is used
+		pool, err := storage.NewStatepool(t.db)
+		t.NoError(err)
+
+		sw, publickeyFunc, err := t.newProcessor(genesis).Signers(pool)
+		t.NoError(err)
+		t.True(genesis.Equal(sw))
+
+		pub, found, err := publickeyFunc(t.nodes[0])
+		t.NoError(err)
+		t.True(found)
+		t.True(t.privs[t.nodes[0].String()].Publickey().Equal(pub))
+	}
+
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opr := t.newProcessor(genesis).New(pool)
+
+	_, err = opr.PreProcess(t.newJoin(n, 3, n, t.nodes[0], t.nodes[1]))
+	t.NoError(err)
+	t.NoError(opr.Close())
+
+	sts := pool.Updates()
+	for i := range sts {
+		t.NoError(t.db.(storage.StateUpdater).NewState(sts[i].GetState()))
+	}
+
+	// NOTE the weights and publickeys are loaded from the states, not from the
+	// genesis nodes
+	pool, err = storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	sw, publickeyFunc, err := NewOperationProcessor(t.stateKey, genesis, nil, base.ThresholdRatio(67), nil).Signers(pool)
+	t.NoError(err)
+	t.Equal(3, len(sw.Nodes()))
+	t.Equal(uint(3), sw.Weight(n))
+
+	for _, a := range []base.Address{t.nodes[0], t.nodes[1], n} {
+		pub, found, err := publickeyFunc(a)
+		t.NoError(err)
+		t.True(found)
+		t.True(t.privs[a.String()].Publickey().Equal(pub))
+	}
+
+	_, found, err := publickeyFunc(base.RandomStringAddress())
+	t.NoError(err)
+	t.False(found)
+}
+
+func TestOperationProcessor(t *testing.T) {
+	suite.Run(t, new(testOperationProcessor))
+}
@@ -3,8 +3,10 @@ package node
 import (
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
 )
 
 var (
@@ -42,3 +44,11 @@ func (bn BaseV0) Address() base.Address {
 func (bn BaseV0) Publickey() key.Publickey {
 	return bn.publickey
 }
+
+func (bn BaseV0) Bytes() []byte {
+	return util.ConcatBytesSlice(bn.address.Bytes(), bn.publickey.Bytes())
+}
+
+func (bn BaseV0) Hash() valuehash.Hash {
+	return valuehash.NewSHA256(bn.Bytes())
+}
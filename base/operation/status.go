@@ -0,0 +1,176 @@
+package operation
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	StatusType     = hint.Type("operation-status")
+	StatusV0Hint   = hint.NewHint(StatusType, "v0.0.1")
+	StatusV0Hinter = StatusV0{BaseHinter: hint.NewBaseHinter(StatusV0Hint)}
+)
+
+type StatusKind uint8
+
+const (
+	_ StatusKind = iota
+	// StatusStaged means the operation is staged and waits to be proposed.
+	StatusStaged
+	// StatusProposed means the operation is included in proposal.
+	StatusProposed
+	// StatusIncluded means the operation is stored in block.
+	StatusIncluded
+	// StatusRejected means the operation is stored in block, but it is failed
+	// to be processed by reason.
+	StatusRejected
+)
+
+func (sk StatusKind) String() string {
+	switch sk {
+	case StatusStaged:
+		return "staged"
+	case StatusProposed:
+		return "proposed"
+	case StatusIncluded:
+		return "included"
+	case StatusRejected:
+		return "rejected"
+	default:
+		return "<unknown status>"
+	}
+}
+
+func (sk StatusKind) IsValid([]byte) error {
+	switch sk {
+	case StatusStaged, StatusProposed, StatusIncluded, StatusRejected:
+		return nil
+	}
+
+	return isvalid.InvalidError.Errorf("status=%d", sk)
+}
+
+// IsFinal returns true when the operation is stored in block.
+func (sk StatusKind) IsFinal() bool {
+	switch sk {
+	case StatusIncluded, StatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanUpdate returns true when the status can be updated to next; the final
+// status can not be updated and the status can not go backward.
+func (sk StatusKind) CanUpdate(next StatusKind) bool {
+	return !sk.IsFinal() && next >= sk
+}
+
+func (sk StatusKind) MarshalText() ([]byte, error) {
+	return []byte(sk.String()), nil
+}
+
+func (sk *StatusKind) UnmarshalText(b []byte) error {
+	var t StatusKind
+	switch string(b) {
+	case "staged":
+		t = StatusStaged
+	case "proposed":
+		t = StatusProposed
+	case "included":
+		t = StatusIncluded
+	case "rejected":
+		t = StatusRejected
+	default:
+		return errors.Errorf("<unknown status>")
+	}
+
+	*sk = t
+
+	return nil
+}
+
+// StatusV0 is the receipt of operation; it describes what happened to the
+// operation since it was received.
+type StatusV0 struct {
+	hint.BaseHinter
+	fact      valuehash.Hash
+	status    StatusKind
+	height    base.Height
+	round     base.Round
+	reason    string
+	updatedAt time.Time
+}
+
+func NewStatusV0(
+	fact valuehash.Hash,
+	status StatusKind,
+	height base.Height,
+	round base.Round,
+	reason string,
+	updatedAt time.Time,
+) StatusV0 {
+	return StatusV0{
+		BaseHinter: hint.NewBaseHinter(StatusV0Hint),
+		fact:       fact,
+		status:     status,
+		height:     height,
+		round:      round,
+		reason:     reason,
+		updatedAt:  updatedAt,
+	}
+}
+
+func (so StatusV0) IsValid([]byte) error {
+	if err := isvalid.Check(nil, false, so.BaseHinter, so.fact, so.status); err != nil {
+		return err
+	}
+
+	if so.status == StatusStaged {
+		return nil
+	}
+
+	if err := so.height.IsValid(nil); err != nil {
+		return err
+	}
+
+	if so.status == StatusRejected && len(so.reason) < 1 {
+		return isvalid.InvalidError.Errorf("empty reason of rejected operation")
+	}
+
+	return nil
+}
+
+// Fact is the fact hash of operation.
+func (so StatusV0) Fact() valuehash.Hash {
+	return so.fact
+}
+
+func (so StatusV0) Status() StatusKind {
+	return so.status
+}
+
+// Height is the height of proposal or block; staged operation has empty
+// height.
+func (so StatusV0) Height() base.Height {
+	return so.height
+}
+
+// Round is the round of proposal.
+func (so StatusV0) Round() base.Round {
+	return so.round
+}
+
+// Reason is the reason of rejected operation.
+func (so StatusV0) Reason() string {
+	return so.reason
+}
+
+func (so StatusV0) UpdatedAt() time.Time {
+	return so.updatedAt
+}
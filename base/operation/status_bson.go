@@ -0,0 +1,40 @@
+package operation
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/spikeekips/mitum/base"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (so StatusV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(so.Hint()), bson.M{
+		"fact":       so.fact,
+		"status":     so.status,
+		"height":     so.height,
+		"round":      so.round,
+		"reason":     so.reason,
+		"updated_at": so.updatedAt,
+	}))
+}
+
+type StatusV0UnpackerBSON struct {
+	FC valuehash.Bytes `bson:"fact"`
+	ST StatusKind      `bson:"status"`
+	HT base.Height     `bson:"height"`
+	RD base.Round      `bson:"round"`
+	RS string          `bson:"reason"`
+	UA time.Time       `bson:"updated_at"`
+}
+
+func (so *StatusV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uso StatusV0UnpackerBSON
+	if err := enc.Unmarshal(b, &uso); err != nil {
+		return err
+	}
+
+	return so.unpack(uso.FC, uso.ST, uso.HT, uso.RD, uso.RS, uso.UA)
+}
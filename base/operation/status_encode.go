@@ -0,0 +1,26 @@
+package operation
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (so *StatusV0) unpack(
+	fact valuehash.Hash,
+	status StatusKind,
+	height base.Height,
+	round base.Round,
+	reason string,
+	updatedAt time.Time,
+) error {
+	so.fact = fact
+	so.status = status
+	so.height = height
+	so.round = round
+	so.reason = reason
+	so.updatedAt = updatedAt
+
+	return nil
+}
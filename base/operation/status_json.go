@@ -0,0 +1,48 @@
+package operation
+
+import (
+	"github.com/spikeekips/mitum/base"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type StatusV0PackerJSON struct {
+	jsonenc.HintedHead
+	FC valuehash.Hash `json:"fact"`
+	ST StatusKind     `json:"status"`
+	HT base.Height    `json:"height"`
+	RD base.Round     `json:"round"`
+	RS string         `json:"reason,omitempty"`
+	UA localtime.Time `json:"updated_at"`
+}
+
+func (so StatusV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(StatusV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(so.Hint()),
+		FC:         so.fact,
+		ST:         so.status,
+		HT:         so.height,
+		RD:         so.round,
+		RS:         so.reason,
+		UA:         localtime.NewTime(so.updatedAt),
+	})
+}
+
+type StatusV0UnpackerJSON struct {
+	FC valuehash.Bytes `json:"fact"`
+	ST StatusKind      `json:"status"`
+	HT base.Height     `json:"height"`
+	RD base.Round      `json:"round"`
+	RS string          `json:"reason,omitempty"`
+	UA localtime.Time  `json:"updated_at"`
+}
+
+func (so *StatusV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uso StatusV0UnpackerJSON
+	if err := enc.Unmarshal(b, &uso); err != nil {
+		return err
+	}
+
+	return so.unpack(uso.FC, uso.ST, uso.HT, uso.RD, uso.RS, uso.UA.Time)
+}
@@ -0,0 +1,99 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testStatus struct {
+	suite.Suite
+	encs    *encoder.Encoders
+	encJSON encoder.Encoder
+	encBSON encoder.Encoder
+}
+
+func (t *testStatus) SetupTest() {
+	t.encs = encoder.NewEncoders()
+	t.encJSON = jsonenc.NewEncoder()
+	t.encBSON = bsonenc.NewEncoder()
+
+	_ = t.encs.AddEncoder(t.encJSON)
+	_ = t.encs.AddEncoder(t.encBSON)
+
+	_ = t.encs.TestAddHinter(StatusV0Hinter)
+}
+
+func (t *testStatus) TestIsValid() {
+	so := NewStatusV0(valuehash.RandomSHA256(), StatusStaged, base.NilHeight, base.Round(0), "", localtime.UTCNow())
+	t.NoError(so.IsValid(nil))
+
+	so = NewStatusV0(valuehash.RandomSHA256(), StatusIncluded, base.Height(3), base.Round(0), "", localtime.UTCNow())
+	t.NoError(so.IsValid(nil))
+}
+
+func (t *testStatus) TestInvalid() {
+	so := NewStatusV0(nil, StatusStaged, base.NilHeight, base.Round(0), "", localtime.UTCNow())
+	t.Error(so.IsValid(nil))
+
+	so = NewStatusV0(valuehash.RandomSHA256(), StatusKind(0), base.Height(3), base.Round(0), "", localtime.UTCNow())
+	t.Error(so.IsValid(nil))
+
+	so = NewStatusV0(valuehash.RandomSHA256(), StatusProposed, base.NilHeight, base.Round(0), "", localtime.UTCNow())
+	t.Error(so.IsValid(nil))
+
+	so = NewStatusV0(valuehash.RandomSHA256(), StatusRejected, base.Height(3), base.Round(0), "", localtime.UTCNow())
+	err := so.IsValid(nil)
+	t.Error(err)
+	t.Contains(err.Error(), "empty reason")
+}
+
+func (t *testStatus) TestCanUpdate() {
+	t.True(StatusStaged.CanUpdate(StatusStaged))
+	t.True(StatusStaged.CanUpdate(StatusProposed))
+	t.True(StatusProposed.CanUpdate(StatusProposed))
+	t.True(StatusProposed.CanUpdate(StatusRejected))
+	t.False(StatusProposed.CanUpdate(StatusStaged))
+	t.False(StatusIncluded.CanUpdate(StatusIncluded))
+	t.False(StatusIncluded.CanUpdate(StatusRejected))
+	t.False(StatusRejected.CanUpdate(StatusProposed))
+}
+
+func (t *testStatus) testEncode(enc encoder.Encoder) {
+	so := NewStatusV0(valuehash.RandomSHA256(), StatusRejected, base.Height(3), base.Round(1), "showme", localtime.UTCNow())
+
+	b, err := enc.Marshal(so)
+	t.NoError(err)
+
+	hinter, err := enc.Decode(b)
+	t.NoError(err)
+
+	uso, ok := hinter.(StatusV0)
+	t.True(ok)
+
+	t.NoError(uso.IsValid(nil))
+	t.True(so.Fact().Equal(uso.Fact()))
+	t.Equal(so.Status(), uso.Status())
+	t.Equal(so.Height(), uso.Height())
+	t.Equal(so.Round(), uso.Round())
+	t.Equal(so.Reason(), uso.Reason())
+	t.True(localtime.Equal(so.UpdatedAt(), uso.UpdatedAt()))
+}
+
+func (t *testStatus) TestEncodeJSON() {
+	t.testEncode(t.encJSON)
+}
+
+func (t *testStatus) TestEncodeBSON() {
+	t.testEncode(t.encBSON)
+}
+
+func TestStatus(t *testing.T) {
+	suite.Run(t, new(testStatus))
+}
@@ -0,0 +1,5 @@
+/*
+Package policy provides the network policy, which is stored in state and
+changed by operation.
+*/
+package policy
@@ -0,0 +1,164 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// DefaultStateKey is the state key of the network policy.
+const DefaultStateKey = "network-policy"
+
+var (
+	PolicyV0Type   = hint.Type("network-policy")
+	PolicyV0Hint   = hint.NewHint(PolicyV0Type, "v0.0.1")
+	PolicyV0Hinter = PolicyV0{BaseHinter: hint.NewBaseHinter(PolicyV0Hint)}
+)
+
+// PolicyV0 has the policy values, which should be same in all the nodes. The
+// policy takes effect from the given height.
+type PolicyV0 struct {
+	hint.BaseHinter
+	height                           base.Height
+	thresholdRatio                   base.ThresholdRatio
+	maxOperationsInSeal              uint
+	maxOperationsInProposal          uint
+	timeoutWaitingProposal           time.Duration
+	intervalBroadcastingINITBallot   time.Duration
+	intervalBroadcastingProposal     time.Duration
+	waitBroadcastingACCEPTBallot     time.Duration
+	intervalBroadcastingACCEPTBallot time.Duration
+	timespanValidBallot              time.Duration
+	networkConnectionTimeout         time.Duration
+}
+
+func NewPolicyV0(
+	height base.Height,
+	thresholdRatio base.ThresholdRatio,
+	maxOperationsInSeal,
+	maxOperationsInProposal uint,
+	timeoutWaitingProposal,
+	intervalBroadcastingINITBallot,
+	intervalBroadcastingProposal,
+	waitBroadcastingACCEPTBallot,
+	intervalBroadcastingACCEPTBallot,
+	timespanValidBallot,
+	networkConnectionTimeout time.Duration,
+) PolicyV0 {
+	return PolicyV0{
+		BaseHinter:                       hint.NewBaseHinter(PolicyV0Hint),
+		height:                           height,
+		thresholdRatio:                   thresholdRatio,
+		maxOperationsInSeal:              maxOperationsInSeal,
+		maxOperationsInProposal:          maxOperationsInProposal,
+		timeoutWaitingProposal:           timeoutWaitingProposal,
+		intervalBroadcastingINITBallot:   intervalBroadcastingINITBallot,
+		intervalBroadcastingProposal:     intervalBroadcastingProposal,
+		waitBroadcastingACCEPTBallot:     waitBroadcastingACCEPTBallot,
+		intervalBroadcastingACCEPTBallot: intervalBroadcastingACCEPTBallot,
+		timespanValidBallot:              timespanValidBallot,
+		networkConnectionTimeout:         networkConnectionTimeout,
+	}
+}
+
+func (po PolicyV0) IsValid([]byte) error {
+	if err := isvalid.Check(nil, false, po.BaseHinter, po.height, po.thresholdRatio); err != nil {
+		return err
+	}
+
+	if po.maxOperationsInSeal < 1 {
+		return isvalid.InvalidError.Errorf("zero MaxOperationsInSeal")
+	}
+
+	if po.maxOperationsInProposal < 1 {
+		return isvalid.InvalidError.Errorf("zero MaxOperationsInProposal")
+	}
+
+	for k, d := range map[string]time.Duration{
+		"TimeoutWaitingProposal":           po.timeoutWaitingProposal,
+		"IntervalBroadcastingINITBallot":   po.intervalBroadcastingINITBallot,
+		"IntervalBroadcastingProposal":     po.intervalBroadcastingProposal,
+		"WaitBroadcastingACCEPTBallot":     po.waitBroadcastingACCEPTBallot,
+		"IntervalBroadcastingACCEPTBallot": po.intervalBroadcastingACCEPTBallot,
+		"TimespanValidBallot":              po.timespanValidBallot,
+	} {
+		if d < 1 {
+			return isvalid.InvalidError.Errorf("%s too short; %v", k, d)
+		}
+	}
+
+	if po.networkConnectionTimeout < time.Second {
+		return isvalid.InvalidError.Errorf("NetworkConnectionTimeout too short; %v", po.networkConnectionTimeout)
+	}
+
+	return nil
+}
+
+func (po PolicyV0) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		po.height.Bytes(),
+		util.Float64ToBytes(po.thresholdRatio.Float64()),
+		util.UintToBytes(po.maxOperationsInSeal),
+		util.UintToBytes(po.maxOperationsInProposal),
+		util.DurationToBytes(po.timeoutWaitingProposal),
+		util.DurationToBytes(po.intervalBroadcastingINITBallot),
+		util.DurationToBytes(po.intervalBroadcastingProposal),
+		util.DurationToBytes(po.waitBroadcastingACCEPTBallot),
+		util.DurationToBytes(po.intervalBroadcastingACCEPTBallot),
+		util.DurationToBytes(po.timespanValidBallot),
+		util.DurationToBytes(po.networkConnectionTimeout),
+	)
+}
+
+func (po PolicyV0) Hash() valuehash.Hash {
+	return valuehash.NewSHA256(po.Bytes())
+}
+
+// Height is the height, from which the policy takes effect.
+func (po PolicyV0) Height() base.Height {
+	return po.height
+}
+
+func (po PolicyV0) ThresholdRatio() base.ThresholdRatio {
+	return po.thresholdRatio
+}
+
+func (po PolicyV0) MaxOperationsInSeal() uint {
+	return po.maxOperationsInSeal
+}
+
+func (po PolicyV0) MaxOperationsInProposal() uint {
+	return po.maxOperationsInProposal
+}
+
+func (po PolicyV0) TimeoutWaitingProposal() time.Duration {
+	return po.timeoutWaitingProposal
+}
+
+func (po PolicyV0) IntervalBroadcastingINITBallot() time.Duration {
+	return po.intervalBroadcastingINITBallot
+}
+
+func (po PolicyV0) IntervalBroadcastingProposal() time.Duration {
+	return po.intervalBroadcastingProposal
+}
+
+func (po PolicyV0) WaitBroadcastingACCEPTBallot() time.Duration {
+	return po.waitBroadcastingACCEPTBallot
+}
+
+func (po PolicyV0) IntervalBroadcastingACCEPTBallot() time.Duration {
+	return po.intervalBroadcastingACCEPTBallot
+}
+
+func (po PolicyV0) TimespanValidBallot() time.Duration {
+	return po.timespanValidBallot
+}
+
+func (po PolicyV0) NetworkConnectionTimeout() time.Duration {
+	return po.networkConnectionTimeout
+}
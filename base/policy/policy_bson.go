@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (po PolicyV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(po.Hint()),
+		bson.M{
+			"height":                              po.height,
+			"threshold":                           po.thresholdRatio,
+			"max_operations_in_seal":              po.maxOperationsInSeal,
+			"max_operations_in_proposal":          po.maxOperationsInProposal,
+			"timeout_waiting_proposal":            po.timeoutWaitingProposal,
+			"interval_broadcasting_init_ballot":   po.intervalBroadcastingINITBallot,
+			"interval_broadcasting_proposal":      po.intervalBroadcastingProposal,
+			"wait_broadcasting_accept_ballot":     po.waitBroadcastingACCEPTBallot,
+			"interval_broadcasting_accept_ballot": po.intervalBroadcastingACCEPTBallot,
+			"timespan_valid_ballot":               po.timespanValidBallot,
+			"network_connection_timeout":          po.networkConnectionTimeout,
+		},
+	))
+}
+
+type PolicyV0BSONUnpacker struct {
+	HT base.Height         `bson:"height"`
+	TR base.ThresholdRatio `bson:"threshold"`
+	MS uint                `bson:"max_operations_in_seal"`
+	MP uint                `bson:"max_operations_in_proposal"`
+	TP time.Duration       `bson:"timeout_waiting_proposal"`
+	BI time.Duration       `bson:"interval_broadcasting_init_ballot"`
+	BP time.Duration       `bson:"interval_broadcasting_proposal"`
+	WA time.Duration       `bson:"wait_broadcasting_accept_ballot"`
+	BA time.Duration       `bson:"interval_broadcasting_accept_ballot"`
+	TB time.Duration       `bson:"timespan_valid_ballot"`
+	NT time.Duration       `bson:"network_connection_timeout"`
+}
+
+func (po *PolicyV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var upo PolicyV0BSONUnpacker
+	if err := enc.Unmarshal(b, &upo); err != nil {
+		return err
+	}
+
+	po.height = upo.HT
+	po.thresholdRatio = upo.TR
+	po.maxOperationsInSeal = upo.MS
+	po.maxOperationsInProposal = upo.MP
+	po.timeoutWaitingProposal = upo.TP
+	po.intervalBroadcastingINITBallot = upo.BI
+	po.intervalBroadcastingProposal = upo.BP
+	po.waitBroadcastingACCEPTBallot = upo.WA
+	po.intervalBroadcastingACCEPTBallot = upo.BA
+	po.timespanValidBallot = upo.TB
+	po.networkConnectionTimeout = upo.NT
+
+	return nil
+}
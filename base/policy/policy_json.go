@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+type PolicyV0JSONPacker struct {
+	jsonenc.HintedHead
+	HT base.Height         `json:"height"`
+	TR base.ThresholdRatio `json:"threshold"`
+	MS uint                `json:"max_operations_in_seal"`
+	MP uint                `json:"max_operations_in_proposal"`
+	TP time.Duration       `json:"timeout_waiting_proposal"`
+	BI time.Duration       `json:"interval_broadcasting_init_ballot"`
+	BP time.Duration       `json:"interval_broadcasting_proposal"`
+	WA time.Duration       `json:"wait_broadcasting_accept_ballot"`
+	BA time.Duration       `json:"interval_broadcasting_accept_ballot"`
+	TB time.Duration       `json:"timespan_valid_ballot"`
+	NT time.Duration       `json:"network_connection_timeout"`
+}
+
+func (po PolicyV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(PolicyV0JSONPacker{
+		HintedHead: jsonenc.NewHintedHead(po.Hint()),
+		HT:         po.height,
+		TR:         po.thresholdRatio,
+		MS:         po.maxOperationsInSeal,
+		MP:         po.maxOperationsInProposal,
+		TP:         po.timeoutWaitingProposal,
+		BI:         po.intervalBroadcastingINITBallot,
+		BP:         po.intervalBroadcastingProposal,
+		WA:         po.waitBroadcastingACCEPTBallot,
+		BA:         po.intervalBroadcastingACCEPTBallot,
+		TB:         po.timespanValidBallot,
+		NT:         po.networkConnectionTimeout,
+	})
+}
+
+type PolicyV0JSONUnpacker struct {
+	HT base.Height         `json:"height"`
+	TR base.ThresholdRatio `json:"threshold"`
+	MS uint                `json:"max_operations_in_seal"`
+	MP uint                `json:"max_operations_in_proposal"`
+	TP time.Duration       `json:"timeout_waiting_proposal"`
+	BI time.Duration       `json:"interval_broadcasting_init_ballot"`
+	BP time.Duration       `json:"interval_broadcasting_proposal"`
+	WA time.Duration       `json:"wait_broadcasting_accept_ballot"`
+	BA time.Duration       `json:"interval_broadcasting_accept_ballot"`
+	TB time.Duration       `json:"timespan_valid_ballot"`
+	NT time.Duration       `json:"network_connection_timeout"`
+}
+
+func (po *PolicyV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var upo PolicyV0JSONUnpacker
+	if err := enc.Unmarshal(b, &upo); err != nil {
+		return err
+	}
+
+	po.height = upo.HT
+	po.thresholdRatio = upo.TR
+	po.maxOperationsInSeal = upo.MS
+	po.maxOperationsInProposal = upo.MP
+	po.timeoutWaitingProposal = upo.TP
+	po.intervalBroadcastingINITBallot = upo.BI
+	po.intervalBroadcastingProposal = upo.BP
+	po.waitBroadcastingACCEPTBallot = upo.WA
+	po.intervalBroadcastingACCEPTBallot = upo.BA
+	po.timespanValidBallot = upo.TB
+	po.networkConnectionTimeout = upo.NT
+
+	return nil
+}
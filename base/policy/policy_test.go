@@ -0,0 +1,155 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/stretchr/testify/suite"
+)
+
+func newTestPolicy(height base.Height) PolicyV0 {
+	return NewPolicyV0(
+		height,
+		base.ThresholdRatio(67),
+		100,
+		200,
+		time.Second*5,
+		time.Second,
+		time.Second*2,
+		time.Second*3,
+		time.Second*4,
+		time.Minute,
+		time.Second*3,
+	)
+}
+
+type testPolicy struct {
+	suite.Suite
+}
+
+func (t *testPolicy) TestNew() {
+	po := newTestPolicy(base.Height(33))
+	t.NoError(po.IsValid(nil))
+
+	t.Equal(base.Height(33), po.Height())
+	t.Equal(base.ThresholdRatio(67), po.ThresholdRatio())
+	t.Equal(uint(200), po.MaxOperationsInProposal())
+	t.Equal(time.Second*4, po.IntervalBroadcastingACCEPTBallot())
+}
+
+func (t *testPolicy) TestInvalid() {
+	po := newTestPolicy(base.Height(33))
+	po.maxOperationsInProposal = 0
+
+	err := po.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "zero MaxOperationsInProposal")
+
+	po = newTestPolicy(base.Height(33))
+	po.waitBroadcastingACCEPTBallot = 0
+
+	err = po.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "WaitBroadcastingACCEPTBallot too short")
+
+	po = newTestPolicy(base.Height(33))
+	po.networkConnectionTimeout = time.Millisecond * 10
+
+	err = po.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "NetworkConnectionTimeout too short")
+
+	po = newTestPolicy(base.Height(33))
+	po.thresholdRatio = base.ThresholdRatio(101)
+	t.Error(po.IsValid(nil))
+}
+
+func (t *testPolicy) TestHash() {
+	a := newTestPolicy(base.Height(33))
+	b := newTestPolicy(base.Height(34))
+
+	t.True(a.Hash().Equal(newTestPolicy(base.Height(33)).Hash()))
+	t.False(a.Hash().Equal(b.Hash()))
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(testPolicy))
+}
+
+type testPolicyEncode struct {
+	suite.Suite
+	enc encoder.Encoder
+}
+
+func (t *testPolicyEncode) SetupSuite() {
+	encs := encoder.NewEncoders()
+	_ = encs.AddEncoder(t.enc)
+
+	_ = encs.TestAddHinter(base.BaseFactSignHinter)
+	_ = encs.TestAddHinter(key.BasePublickey{})
+	_ = encs.TestAddHinter(PolicyV0Hinter)
+	_ = encs.TestAddHinter(SetPolicyFactHinter)
+	_ = encs.TestAddHinter(SetPolicyHinter)
+}
+
+func (t *testPolicyEncode) TestPolicy() {
+	po := newTestPolicy(base.Height(33))
+
+	b, err := t.enc.Marshal(po)
+	t.NoError(err)
+
+	hinter, err := t.enc.Decode(b)
+	t.NoError(err)
+
+	upo, ok := hinter.(PolicyV0)
+	t.True(ok)
+	t.NoError(upo.IsValid(nil))
+	t.True(po.Hash().Equal(upo.Hash()))
+	t.Equal(po.TimespanValidBallot(), upo.TimespanValidBallot())
+}
+
+func (t *testPolicyEncode) TestSetPolicy() {
+	networkID := util.UUID().Bytes()
+
+	fact := NewSetPolicyFact(util.UUID().Bytes(), newTestPolicy(base.Height(33)))
+
+	priv := key.NewBasePrivatekey()
+	sig, err := base.NewFactSignature(priv, fact, networkID)
+	t.NoError(err)
+
+	op, err := NewSetPolicy(fact, []base.FactSign{base.NewBaseFactSign(priv.Publickey(), sig)})
+	t.NoError(err)
+	t.NoError(op.IsValid(networkID))
+
+	b, err := t.enc.Marshal(op)
+	t.NoError(err)
+
+	hinter, err := t.enc.Decode(b)
+	t.NoError(err)
+
+	uop, ok := hinter.(SetPolicy)
+	t.True(ok)
+	t.NoError(uop.IsValid(networkID))
+	t.True(op.Hash().Equal(uop.Hash()))
+
+	ufact := uop.Fact().(SetPolicyFact)
+	t.True(fact.Hash().Equal(ufact.Hash()))
+	t.Equal(fact.Token(), ufact.Token())
+	t.True(fact.Policy().Hash().Equal(ufact.Policy().Hash()))
+}
+
+func TestPolicyEncodeJSON(t *testing.T) {
+	suite.Run(t, &testPolicyEncode{enc: jsonenc.NewEncoder()})
+}
+
+func TestPolicyEncodeBSON(t *testing.T) {
+	suite.Run(t, &testPolicyEncode{enc: bsonenc.NewEncoder()})
+}
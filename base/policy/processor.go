@@ -0,0 +1,181 @@
+package policy
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/prprocessor"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// SignersFunc returns the suffrage weights and the publickey getter of the
+// suffrage nodes from the states of Statepool, so every node checks the signs
+// of SetPolicy by the same suffrage of the block.
+type SignersFunc func(*storage.Statepool) (
+	base.SuffrageWeights, func(base.Address) (key.Publickey, bool, error), error)
+
+// OperationProcessor processes SetPolicy. SetPolicy should be signed by the
+// suffrage nodes over threshold and the height of new policy should be higher
+// than the height of block. Only one SetPolicy is allowed in one block.
+//
+// The threshold is decided by the sum of the weights of the signed suffrage
+// nodes and the threshold ratio of the policy at the block height, which is
+// returned by ratioFunc.
+type OperationProcessor struct {
+	sync.Mutex
+	stateKey             string
+	signersFunc          SignersFunc
+	ratioFunc            func(base.Height) (base.ThresholdRatio, error)
+	hasOperationFactFunc func(valuehash.Hash) (bool, error)
+	pool                 *storage.Statepool
+	processed            bool
+}
+
+func NewOperationProcessor(
+	stateKey string,
+	signersFunc SignersFunc,
+	ratioFunc func(base.Height) (base.ThresholdRatio, error),
+	hasOperationFactFunc func(valuehash.Hash) (bool, error),
+) *OperationProcessor {
+	return &OperationProcessor{
+		stateKey:             stateKey,
+		signersFunc:          signersFunc,
+		ratioFunc:            ratioFunc,
+		hasOperationFactFunc: hasOperationFactFunc,
+	}
+}
+
+func (opp *OperationProcessor) New(pool *storage.Statepool) prprocessor.OperationProcessor {
+	return &OperationProcessor{
+		stateKey:             opp.stateKey,
+		signersFunc:          opp.signersFunc,
+		ratioFunc:            opp.ratioFunc,
+		hasOperationFactFunc: opp.hasOperationFactFunc,
+		pool:                 pool,
+	}
+}
+
+func (opp *OperationProcessor) PreProcess(op state.Processor) (state.Processor, error) {
+	opp.Lock()
+	defer opp.Unlock()
+
+	if opp.pool == nil {
+		return nil, errors.Errorf("OperationProcessor should be created by New()")
+	}
+
+	i, ok := op.(SetPolicy)
+	if !ok {
+		return nil, errors.Errorf("not SetPolicy, %T", op)
+	}
+
+	fact := i.Fact().(SetPolicyFact)
+
+	if opp.hasOperationFactFunc != nil {
+		switch found, err := opp.hasOperationFactFunc(fact.Hash()); {
+		case err != nil:
+			return nil, err
+		case found:
+			return nil, operation.NewBaseReasonError("known operation")
+		}
+	}
+
+	if opp.processed {
+		return nil, operation.NewBaseReasonError("policy already updated in this block")
+	}
+
+	if h := fact.Policy().Height(); h <= opp.pool.Height() {
+		return nil, operation.NewBaseReasonError(
+			"height of policy, %d should be higher than block height, %d", h, opp.pool.Height())
+	}
+
+	switch ok, err := opp.isSignedByThreshold(i.Signs()); {
+	case err != nil:
+		return nil, err
+	case !ok:
+		return nil, operation.NewBaseReasonError("not enough signs of suffrage nodes")
+	}
+
+	opp.processed = true
+
+	return op, nil
+}
+
+func (opp *OperationProcessor) Process(op state.Processor) error {
+	i, ok := op.(SetPolicy)
+	if !ok {
+		return errors.Errorf("not SetPolicy, %T", op)
+	}
+
+	fact := i.Fact().(SetPolicyFact)
+
+	st, _, err := opp.pool.Get(opp.stateKey)
+	if err != nil {
+		return err
+	}
+
+	v, err := state.NewHintedValue(fact.Policy())
+	if err != nil {
+		return err
+	}
+
+	nst, err := st.SetValue(v)
+	if err != nil {
+		return err
+	}
+
+	return opp.pool.Set(fact.Hash(), nst)
+}
+
+func (*OperationProcessor) Close() error {
+	return nil
+}
+
+func (*OperationProcessor) Cancel() error {
+	return nil
+}
+
+// isSignedByThreshold checks the sum of the weights of signed suffrage nodes
+// is over threshold.
+func (opp *OperationProcessor) isSignedByThreshold(fs []base.FactSign) (bool, error) {
+	sw, publickeyFunc, err := opp.signersFunc(opp.pool)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load suffrage nodes")
+	}
+
+	ratio, err := opp.ratioFunc(opp.pool.Height())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load threshold ratio")
+	}
+
+	threshold, err := base.NewThreshold(sw.Total(), ratio)
+	if err != nil {
+		return false, err
+	}
+
+	var signed uint
+	nodes := sw.Nodes()
+	for i := range nodes {
+		pub, found, err := publickeyFunc(nodes[i])
+		switch {
+		case err != nil:
+			return false, err
+		case !found:
+			continue
+		}
+
+		for j := range fs {
+			if fs[j].Signer().Equal(pub) {
+				signed += sw.Weight(nodes[i])
+
+				break
+			}
+		}
+	}
+
+	return signed >= threshold.Threshold, nil
+}
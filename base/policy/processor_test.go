@@ -0,0 +1,209 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/storage"
+	leveldbstorage "github.com/spikeekips/mitum/storage/leveldb"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testOperationProcessor struct {
+	suite.Suite
+	networkID []byte
+	nodes     []base.Address
+	privs     map[string]key.Privatekey
+	db        storage.Database
+}
+
+func (t *testOperationProcessor) SetupTest() {
+	t.networkID = util.UUID().Bytes()
+	t.privs = map[string]key.Privatekey{}
+	t.nodes = nil
+
+	for i := 0; i < 3; i++ {
+		a := base.RandomStringAddress()
+		t.nodes = append(t.nodes, a)
+		t.privs[a.String()] = key.NewBasePrivatekey()
+	}
+
+	enc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders()
+	_ = encs.AddEncoder(enc)
+
+	t.db = leveldbstorage.NewMemDatabase(encs, enc)
+}
+
+func (t *testOperationProcessor) newProcessor() *OperationProcessor {
+	return t.newWeightedProcessor(base.NewEqualSuffrageWeights(t.nodes), base.ThresholdRatio(67))
+}
+
+func (t *testOperationProcessor) newWeightedProcessor(sw base.SuffrageWeights, ratio base.ThresholdRatio) *OperationProcessor {
+	return NewOperationProcessor(
+		DefaultStateKey,
+		func(*storage.Statepool) (base.SuffrageWeights, func(base.Address) (key.Publickey, bool, error), error) {
+			return sw, func(a base.Address) (key.Publickey, bool, error) {
+				priv, found := t.privs[a.String()]
+				if !found {
+					return nil, false, nil
+				}
+
+				return priv.Publickey(), true, nil
+			}, nil
+		},
+		func(base.Height) (base.ThresholdRatio, error) { return ratio, nil },
+		nil,
+	)
+}
+
+func (t *testOperationProcessor) newSetPolicy(height base.Height, signers ...base.Address) SetPolicy {
+	fact := NewSetPolicyFact(util.UUID().Bytes(), newTestPolicy(height))
+
+	fs := make([]base.FactSign, len(signers))
+	for i := range signers {
+		priv := t.privs[signers[i].String()]
+		sig, err := base.NewFactSignature(priv, fact, t.networkID)
+		t.NoError(err)
+
+		fs[i] = base.NewBaseFactSign(priv.Publickey(), sig)
+	}
+
+	op, err := NewSetPolicy(fact, fs)
+	t.NoError(err)
+
+	return op
+}
+
+func (t *testOperationProcessor) TestSetPolicy() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opr := t.newProcessor().New(pool)
+
+	op := t.newSetPolicy(pool.Height()+3, t.nodes...)
+
+	_, err = opr.PreProcess(op)
+	t.NoError(err)
+	t.NoError(opr.Process(op))
+	t.NoError(opr.Close())
+
+	sts := pool.Updates()
+	t.Equal(1, len(sts))
+	t.Equal(DefaultStateKey, sts[0].Key())
+
+	po, ok := sts[0].Value().Interface().(PolicyV0)
+	t.True(ok)
+	t.True(op.Fact().(SetPolicyFact).Policy().Hash().Equal(po.Hash()))
+}
+
+func (t *testOperationProcessor) TestNotEnoughSigns() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opr := t.newProcessor().New(pool)
+
+	_, err = opr.PreProcess(t.newSetPolicy(pool.Height()+3, t.nodes[0], t.nodes[1]))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "not enough signs")
+}
+
+func (t *testOperationProcessor) TestLowerHeight() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opr := t.newProcessor().New(pool)
+
+	_, err = opr.PreProcess(t.newSetPolicy(pool.Height(), t.nodes...))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "should be higher than block height")
+}
+
+func (t *testOperationProcessor) TestMultipleInSameBlock() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	opr := t.newProcessor().New(pool)
+
+	_, err = opr.PreProcess(t.newSetPolicy(pool.Height()+3, t.nodes...))
+	t.NoError(err)
+
+	_, err = opr.PreProcess(t.newSetPolicy(pool.Height()+4, t.nodes...))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "already updated")
+}
+
+func (t *testOperationProcessor) TestWithoutNew() {
+	_, err := t.newProcessor().PreProcess(t.newSetPolicy(base.Height(3), t.nodes...))
+	t.Error(err)
+	t.Contains(err.Error(), "should be created by New()")
+}
+
+func (t *testOperationProcessor) TestWeightedSigns() {
+	sw := base.NewSuffrageWeights(map[base.Address]uint{t.nodes[0]: 3, t.nodes[1]: 1, t.nodes[2]: 1})
+
+	{ // NOTE weights of signers, 4 is over threshold, 4 of total 5
+		pool, err := storage.NewStatepool(t.db)
+		t.NoError(err)
+
+		opr := t.newWeightedProcessor(sw, base.ThresholdRatio(67)).New(pool)
+
+		_, err = opr.PreProcess(t.newSetPolicy(pool.Height()+3, t.nodes[0], t.nodes[1]))
+		t.NoError(err)
+	}
+
+	{ // NOTE weights of signers, 2 is under threshold; more than 2/3 of nodes, but not weights
+		pool, err := storage.NewStatepool(t.db)
+		t.NoError(err)
+
+		opr := t.newWeightedProcessor(sw, base.ThresholdRatio(60)).New(pool)
+
+		_, err = opr.PreProcess(t.newSetPolicy(pool.Height()+3, t.nodes[1], t.nodes[2]))
+
+		var operr operation.ReasonError
+		t.True(errors.As(err, &operr))
+		t.Contains(err.Error(), "not enough signs")
+	}
+}
+
+func (t *testOperationProcessor) TestRatioOfBlockHeight() {
+	pool, err := storage.NewStatepool(t.db)
+	t.NoError(err)
+
+	var heights []base.Height
+	opp := NewOperationProcessor(
+		DefaultStateKey,
+		t.newProcessor().signersFunc,
+		func(height base.Height) (base.ThresholdRatio, error) {
+			heights = append(heights, height)
+
+			return base.ThresholdRatio(100), nil
+		},
+		nil,
+	)
+
+	// NOTE 2 of 3 nodes are not enough with the ratio of block height
+	_, err = opp.New(pool).PreProcess(t.newSetPolicy(pool.Height()+3, t.nodes[0], t.nodes[1]))
+
+	var operr operation.ReasonError
+	t.True(errors.As(err, &operr))
+	t.Contains(err.Error(), "not enough signs")
+
+	t.Equal([]base.Height{pool.Height()}, heights)
+}
+
+func TestOperationProcessor(t *testing.T) {
+	suite.Run(t, new(testOperationProcessor))
+}
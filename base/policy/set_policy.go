@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	SetPolicyFactType   = hint.Type("set-policy-operation-fact")
+	SetPolicyFactHint   = hint.NewHint(SetPolicyFactType, "v0.0.1")
+	SetPolicyFactHinter = SetPolicyFact{BaseHinter: hint.NewBaseHinter(SetPolicyFactHint)}
+	SetPolicyType       = hint.Type("set-policy-operation")
+	SetPolicyHint       = hint.NewHint(SetPolicyType, "v0.0.1")
+	SetPolicyHinter     = SetPolicy{BaseOperation: operation.EmptyBaseOperation(SetPolicyHint)}
+)
+
+// SetPolicyFact changes the network policy from the height of policy.
+type SetPolicyFact struct {
+	hint.BaseHinter
+	h      valuehash.Hash
+	token  []byte
+	policy PolicyV0
+}
+
+func NewSetPolicyFact(token []byte, policy PolicyV0) SetPolicyFact {
+	fact := SetPolicyFact{
+		BaseHinter: hint.NewBaseHinter(SetPolicyFactHint),
+		token:      token,
+		policy:     policy,
+	}
+	fact.h = fact.GenerateHash()
+
+	return fact
+}
+
+func (fact SetPolicyFact) IsValid(networkID []byte) error {
+	if err := fact.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	if err := operation.IsValidOperationFact(fact, networkID); err != nil {
+		return err
+	}
+
+	if err := fact.policy.IsValid(nil); err != nil {
+		return err
+	}
+
+	if !fact.h.Equal(fact.GenerateHash()) {
+		return isvalid.InvalidError.Errorf("wrong fact hash")
+	}
+
+	return nil
+}
+
+func (fact SetPolicyFact) Hash() valuehash.Hash {
+	return fact.h
+}
+
+func (fact SetPolicyFact) GenerateHash() valuehash.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact SetPolicyFact) Bytes() []byte {
+	return util.ConcatBytesSlice(fact.token, fact.policy.Bytes())
+}
+
+func (fact SetPolicyFact) Token() []byte {
+	return fact.token
+}
+
+func (fact SetPolicyFact) Policy() PolicyV0 {
+	return fact.policy
+}
+
+// SetPolicy should be signed by the suffrage nodes, which are over threshold;
+// it is checked by OperationProcessor.
+type SetPolicy struct {
+	operation.BaseOperation
+}
+
+func NewSetPolicy(fact SetPolicyFact, fs []base.FactSign) (SetPolicy, error) {
+	bo, err := operation.NewBaseOperationFromFact(SetPolicyHint, fact, fs)
+	if err != nil {
+		return SetPolicy{}, err
+	}
+
+	return SetPolicy{BaseOperation: bo}, nil
+}
+
+func (op SetPolicy) IsValid(networkID []byte) error {
+	if err := op.BaseOperation.IsValid(networkID); err != nil {
+		return err
+	}
+
+	if _, ok := op.Fact().(SetPolicyFact); !ok {
+		return isvalid.InvalidError.Errorf("not SetPolicyFact, %T", op.Fact())
+	}
+
+	return nil
+}
+
+func (op SetPolicy) AddFactSigns(fs ...base.FactSign) (base.FactSignUpdater, error) {
+	i, err := op.BaseOperation.AddFactSigns(fs...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.BaseOperation = i.(operation.BaseOperation)
+
+	return op, nil
+}
+
+// Process of SetPolicy does nothing; SetPolicy is processed by
+// OperationProcessor.
+func (SetPolicy) Process(
+	func(key string) (state.State, bool, error),
+	func(valuehash.Hash, ...state.State) error,
+) error {
+	return operation.NewBaseReasonError("set policy operation should be processed by policy processor")
+}
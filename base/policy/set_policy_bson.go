@@ -0,0 +1,33 @@
+package policy
+
+import (
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (fact SetPolicyFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(fact.Hint()),
+		bson.M{
+			"hash":   fact.h,
+			"token":  fact.token,
+			"policy": fact.policy,
+		},
+	))
+}
+
+type SetPolicyFactBSONUnpacker struct {
+	H  valuehash.Bytes `bson:"hash"`
+	TK []byte          `bson:"token"`
+	PO bson.Raw        `bson:"policy"`
+}
+
+func (fact *SetPolicyFact) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uf SetPolicyFactBSONUnpacker
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, uf.H, uf.TK, uf.PO)
+}
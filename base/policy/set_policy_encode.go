@@ -0,0 +1,24 @@
+package policy
+
+import (
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (fact *SetPolicyFact) unpack(
+	enc encoder.Encoder,
+	h valuehash.Hash,
+	token []byte,
+	bpolicy []byte,
+) error {
+	var po PolicyV0
+	if err := encoder.Decode(bpolicy, enc, &po); err != nil {
+		return err
+	}
+
+	fact.h = h
+	fact.token = token
+	fact.policy = po
+
+	return nil
+}
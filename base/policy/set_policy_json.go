@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"encoding/json"
+
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type SetPolicyFactJSONPacker struct {
+	jsonenc.HintedHead
+	H  valuehash.Hash `json:"hash"`
+	TK []byte         `json:"token"`
+	PO PolicyV0       `json:"policy"`
+}
+
+func (fact SetPolicyFact) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(SetPolicyFactJSONPacker{
+		HintedHead: jsonenc.NewHintedHead(fact.Hint()),
+		H:          fact.h,
+		TK:         fact.token,
+		PO:         fact.policy,
+	})
+}
+
+type SetPolicyFactJSONUnpacker struct {
+	H  valuehash.Bytes `json:"hash"`
+	TK []byte          `json:"token"`
+	PO json.RawMessage `json:"policy"`
+}
+
+func (fact *SetPolicyFact) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uf SetPolicyFactJSONUnpacker
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, uf.H, uf.TK, uf.PO)
+}
@@ -0,0 +1,24 @@
+package prprocessor
+
+import (
+	"github.com/spikeekips/mitum/util/metrics"
+)
+
+var (
+	metricPrepareDuration = metrics.NewHistogramVec(
+		"mitum_proposal_prepare_duration_seconds", "time to prepare proposal", nil, "result")
+	metricSaveDuration = metrics.NewHistogramVec(
+		"mitum_proposal_save_duration_seconds", "time to save prepared block", nil, "result")
+)
+
+func init() {
+	metrics.MustRegister(metricPrepareDuration, metricSaveDuration)
+}
+
+func metricResult(err error) string {
+	if err != nil {
+		return "failed"
+	}
+
+	return "ok"
+}
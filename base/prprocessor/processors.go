@@ -232,6 +232,8 @@ func (pps *Processors) doPrepare(ctx context.Context, processor Processor, outch
 		Stringer("proposal", processor.Fact().Hash()).
 		Logger()
 
+	started := time.Now()
+
 	var blk block.Block
 	err := util.Retry(3, time.Millisecond*200, func(int) error {
 		select {
@@ -276,6 +278,8 @@ func (pps *Processors) doPrepare(ctx context.Context, processor Processor, outch
 		pps.setCurrent(nil)
 	}
 
+	metricPrepareDuration.WithLabelValues(metricResult(err)).Since(started)
+
 	outchan <- Result{Block: blk, Err: err}
 }
 
@@ -323,6 +327,8 @@ func (pps *Processors) doSave(
 		Stringer("proposal", processor.Fact().Hash()).
 		Logger()
 
+	started := time.Now()
+
 	// NOTE tries 3 times
 	err := util.Retry(3, time.Millisecond*200, func(int) error {
 		select {
@@ -365,6 +371,8 @@ func (pps *Processors) doSave(
 		}
 	}
 
+	metricSaveDuration.WithLabelValues(metricResult(err)).Since(started)
+
 	outchan <- Result{Block: blk, Err: err}
 }
 
@@ -16,6 +16,20 @@ type Suffrage interface {
 	Verbose() string
 }
 
+// WeightedSuffrage gives the different voting weight to each node. The nodes
+// and their weights can be changed by height.
+type WeightedSuffrage interface {
+	Suffrage
+	Weights(Height) (SuffrageWeights, error)
+}
+
+// EquivocationSuffrage is notified the double vote of suffrage node, so the
+// suffrage can penalize or exclude the node.
+type EquivocationSuffrage interface {
+	Suffrage
+	Equivocated(EquivocationV0) error
+}
+
 type ActingSuffrage struct {
 	height   Height
 	round    Round
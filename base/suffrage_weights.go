@@ -0,0 +1,161 @@
+package base
+
+import (
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	SuffrageWeightsType   = hint.Type("suffrage-weights")
+	SuffrageWeightsHint   = hint.NewHint(SuffrageWeightsType, "v0.0.1")
+	SuffrageWeightsHinter = SuffrageWeights{BaseHinter: hint.NewBaseHinter(SuffrageWeightsHint)}
+)
+
+// SuffrageWeights has the suffrage nodes and their voting weights. The nodes
+// are sorted by address.
+type SuffrageWeights struct {
+	hint.BaseHinter
+	nodes   []Address
+	weights []uint
+}
+
+func NewSuffrageWeights(weights map[Address]uint) SuffrageWeights {
+	nodes := make([]Address, len(weights))
+	var i int
+	for a := range weights {
+		nodes[i] = a
+		i++
+	}
+
+	SortAddresses(nodes)
+
+	ws := make([]uint, len(nodes))
+	for i := range nodes {
+		ws[i] = weights[nodes[i]]
+	}
+
+	return SuffrageWeights{
+		BaseHinter: hint.NewBaseHinter(SuffrageWeightsHint),
+		nodes:      nodes,
+		weights:    ws,
+	}
+}
+
+// NewEqualSuffrageWeights gives same weight, 1 to all nodes.
+func NewEqualSuffrageWeights(nodes []Address) SuffrageWeights {
+	m := map[Address]uint{}
+	for i := range nodes {
+		m[nodes[i]] = 1
+	}
+
+	return NewSuffrageWeights(m)
+}
+
+func (sw SuffrageWeights) IsValid([]byte) error {
+	if err := sw.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	switch {
+	case len(sw.nodes) < 1:
+		return isvalid.InvalidError.Errorf("empty nodes in suffrage weights")
+	case len(sw.nodes) != len(sw.weights):
+		return isvalid.InvalidError.Errorf(
+			"nodes and weights does not match in suffrage weights; %d != %d", len(sw.nodes), len(sw.weights))
+	}
+
+	founds := map[string]struct{}{}
+	for i := range sw.nodes {
+		n := sw.nodes[i]
+		if err := isvalid.Check(nil, false, n); err != nil {
+			return err
+		}
+
+		if _, found := founds[n.String()]; found {
+			return isvalid.InvalidError.Errorf("duplicated node found in suffrage weights, %q", n)
+		}
+		founds[n.String()] = struct{}{}
+
+		if sw.weights[i] < 1 {
+			return isvalid.InvalidError.Errorf("zero weight found in suffrage weights, %q", n)
+		}
+
+		if i > 0 && sw.nodes[i-1].String() > n.String() {
+			return isvalid.InvalidError.Errorf("nodes not sorted in suffrage weights")
+		}
+	}
+
+	return nil
+}
+
+func (sw SuffrageWeights) Bytes() []byte {
+	bs := make([][]byte, len(sw.nodes)*2)
+	for i := range sw.nodes {
+		bs[i*2] = sw.nodes[i].Bytes()
+		bs[i*2+1] = util.UintToBytes(sw.weights[i])
+	}
+
+	return util.ConcatBytesSlice(bs...)
+}
+
+func (sw SuffrageWeights) Hash() valuehash.Hash {
+	return valuehash.NewSHA256(sw.Bytes())
+}
+
+func (sw SuffrageWeights) Nodes() []Address {
+	return sw.nodes
+}
+
+func (sw SuffrageWeights) Weights() []uint {
+	return sw.weights
+}
+
+// Weight returns the weight of node; if not found, returns 0.
+func (sw SuffrageWeights) Weight(a Address) uint {
+	for i := range sw.nodes {
+		if sw.nodes[i].Equal(a) {
+			return sw.weights[i]
+		}
+	}
+
+	return 0
+}
+
+func (sw SuffrageWeights) Exists(a Address) bool {
+	return sw.Weight(a) > 0
+}
+
+// Total returns the sum of weights.
+func (sw SuffrageWeights) Total() uint {
+	var t uint
+	for i := range sw.weights {
+		t += sw.weights[i]
+	}
+
+	return t
+}
+
+func (sw SuffrageWeights) Map() map[string]uint {
+	m := map[string]uint{}
+	for i := range sw.nodes {
+		m[sw.nodes[i].String()] = sw.weights[i]
+	}
+
+	return m
+}
+
+func (sw SuffrageWeights) Equal(b SuffrageWeights) bool {
+	if len(sw.nodes) != len(b.nodes) {
+		return false
+	}
+
+	for i := range sw.nodes {
+		if !sw.nodes[i].Equal(b.nodes[i]) || sw.weights[i] != b.weights[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,30 @@
+package base
+
+import (
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (sw SuffrageWeights) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(sw.Hint()),
+		bson.M{
+			"nodes":   sw.nodes,
+			"weights": sw.weights,
+		},
+	))
+}
+
+type SuffrageWeightsBSONUnpacker struct {
+	NS []AddressDecoder `bson:"nodes"`
+	WS []uint           `bson:"weights"`
+}
+
+func (sw *SuffrageWeights) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var usw SuffrageWeightsBSONUnpacker
+	if err := enc.Unmarshal(b, &usw); err != nil {
+		return err
+	}
+
+	return sw.unpack(enc, usw.NS, usw.WS)
+}
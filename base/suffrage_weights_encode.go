@@ -0,0 +1,21 @@
+package base
+
+import (
+	"github.com/spikeekips/mitum/util/encoder"
+)
+
+func (sw *SuffrageWeights) unpack(enc encoder.Encoder, bnodes []AddressDecoder, weights []uint) error {
+	nodes := make([]Address, len(bnodes))
+	for i := range bnodes {
+		a, err := bnodes[i].Encode(enc)
+		if err != nil {
+			return err
+		}
+		nodes[i] = a
+	}
+
+	sw.nodes = nodes
+	sw.weights = weights
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package base
+
+import (
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+type SuffrageWeightsJSONPacker struct {
+	jsonenc.HintedHead
+	NS []Address `json:"nodes"`
+	WS []uint    `json:"weights"`
+}
+
+func (sw SuffrageWeights) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(SuffrageWeightsJSONPacker{
+		HintedHead: jsonenc.NewHintedHead(sw.Hint()),
+		NS:         sw.nodes,
+		WS:         sw.weights,
+	})
+}
+
+type SuffrageWeightsJSONUnpacker struct {
+	NS []AddressDecoder `json:"nodes"`
+	WS []uint           `json:"weights"`
+}
+
+func (sw *SuffrageWeights) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var usw SuffrageWeightsJSONUnpacker
+	if err := enc.Unmarshal(b, &usw); err != nil {
+		return err
+	}
+
+	return sw.unpack(enc, usw.NS, usw.WS)
+}
@@ -0,0 +1,106 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSuffrageWeights struct {
+	suite.Suite
+}
+
+func (t *testSuffrageWeights) TestNew() {
+	n0 := RandomStringAddress()
+	n1 := RandomStringAddress()
+	n2 := RandomStringAddress()
+
+	sw := NewSuffrageWeights(map[Address]uint{n0: 3, n1: 1, n2: 2})
+	t.NoError(sw.IsValid(nil))
+
+	t.Equal(uint(6), sw.Total())
+	t.Equal(uint(3), sw.Weight(n0))
+	t.Equal(uint(1), sw.Weight(n1))
+	t.Equal(uint(2), sw.Weight(n2))
+	t.Equal(uint(0), sw.Weight(RandomStringAddress()))
+
+	t.True(sw.Exists(n0))
+	t.False(sw.Exists(RandomStringAddress()))
+
+	nodes := []Address{n0, n1, n2}
+	SortAddresses(nodes)
+	t.Equal(nodes, sw.Nodes())
+}
+
+func (t *testSuffrageWeights) TestEqual() {
+	nodes := []Address{RandomStringAddress(), RandomStringAddress()}
+
+	sw := NewEqualSuffrageWeights(nodes)
+	t.NoError(sw.IsValid(nil))
+	t.Equal(uint(2), sw.Total())
+
+	t.True(sw.Equal(NewSuffrageWeights(map[Address]uint{nodes[0]: 1, nodes[1]: 1})))
+	t.False(sw.Equal(NewSuffrageWeights(map[Address]uint{nodes[0]: 1, nodes[1]: 2})))
+	t.False(sw.Hash().Equal(NewSuffrageWeights(map[Address]uint{nodes[0]: 1, nodes[1]: 2}).Hash()))
+}
+
+func (t *testSuffrageWeights) TestInvalid() {
+	sw := NewSuffrageWeights(nil)
+	err := sw.IsValid(nil)
+	t.Contains(err.Error(), "empty nodes")
+
+	sw = NewSuffrageWeights(map[Address]uint{RandomStringAddress(): 0})
+	err = sw.IsValid(nil)
+	t.Contains(err.Error(), "zero weight")
+}
+
+func TestSuffrageWeights(t *testing.T) {
+	suite.Run(t, new(testSuffrageWeights))
+}
+
+type testSuffrageWeightsEncode struct {
+	suite.Suite
+
+	enc encoder.Encoder
+}
+
+func (t *testSuffrageWeightsEncode) SetupSuite() {
+	encs := encoder.NewEncoders()
+	_ = encs.AddEncoder(t.enc)
+
+	_ = encs.TestAddHinter(StringAddressHinter)
+	_ = encs.TestAddHinter(SuffrageWeightsHinter)
+}
+
+func (t *testSuffrageWeightsEncode) TestMarshal() {
+	sw := NewSuffrageWeights(map[Address]uint{
+		RandomStringAddress(): 3,
+		RandomStringAddress(): 1,
+	})
+	t.NoError(sw.IsValid(nil))
+
+	b, err := t.enc.Marshal(sw)
+	t.NoError(err)
+
+	hinter, err := t.enc.Decode(b)
+	t.NoError(err)
+
+	usw, ok := hinter.(SuffrageWeights)
+	t.True(ok)
+
+	t.NoError(usw.IsValid(nil))
+	t.True(sw.Equal(usw))
+	t.True(sw.Hint().Equal(usw.Hint()))
+	t.True(sw.Hash().Equal(usw.Hash()))
+}
+
+func TestSuffrageWeightsEncodeJSON(t *testing.T) {
+	suite.Run(t, &testSuffrageWeightsEncode{enc: jsonenc.NewEncoder()})
+}
+
+func TestSuffrageWeightsEncodeBSON(t *testing.T) {
+	suite.Run(t, &testSuffrageWeightsEncode{enc: bsonenc.NewEncoder()})
+}
@@ -48,12 +48,18 @@ func FindMajority(total, threshold uint, set ...uint) int {
 }
 
 func FindMajorityFromSlice(total, threshold uint, s []string) (VoteResultType, string) {
-	keys := map[uint]string{}
 	counts := map[string]uint{}
 	for _, k := range s {
 		counts[k]++
 	}
 
+	return FindMajorityFromCounts(total, threshold, counts)
+}
+
+// FindMajorityFromCounts finds majority from the counts of each key. The count
+// can be the sum of voting weights.
+func FindMajorityFromCounts(total, threshold uint, counts map[string]uint) (VoteResultType, string) {
+	keys := map[uint]string{}
 	set := make([]uint, len(counts))
 	var i int
 	for k, c := range counts {
@@ -152,3 +152,63 @@ func TestFindMajorityFromSlice(t *testing.T) {
 		)
 	}
 }
+
+func TestFindMajorityFromCounts(t *testing.T) {
+	cases := []struct {
+		name      string
+		total     uint
+		threshold uint
+		counts    map[string]uint
+		expected  string
+		result    VoteResultType
+	}{
+		{
+			name:  "over threshold",
+			total: 10, threshold: 7,
+			counts:   map[string]uint{"a": 7, "b": 1},
+			expected: "a",
+			result:   VoteResultMajority,
+		},
+		{
+			name:  "heavy single node",
+			total: 10, threshold: 7,
+			counts:   map[string]uint{"a": 8},
+			expected: "a",
+			result:   VoteResultMajority,
+		},
+		{
+			name:  "draw",
+			total: 10, threshold: 7,
+			counts:   map[string]uint{"a": 4, "b": 4},
+			expected: "",
+			result:   VoteResultDraw,
+		},
+		{
+			name:  "not yet",
+			total: 10, threshold: 7,
+			counts:   map[string]uint{"a": 3, "b": 3},
+			expected: "",
+			result:   VoteResultNotYet,
+		},
+		{
+			name:  "empty",
+			total: 10, threshold: 7,
+			counts:   map[string]uint{},
+			expected: "",
+			result:   VoteResultNotYet,
+		},
+	}
+
+	for i, c := range cases {
+		i := i
+		c := c
+		t.Run(
+			c.name,
+			func(*testing.T) {
+				result, key := FindMajorityFromCounts(c.total, c.threshold, c.counts)
+				assert.Equal(t, c.expected, key, "%d: %v; %v != %v", i, c.name, c.expected, key)
+				assert.Equal(t, c.result, result, "%d: %v; %v != %v", i, c.name, c.expected, result)
+			},
+		)
+	}
+}
@@ -427,6 +427,92 @@ func (t *testVoteproof) TestMajorityButNot() {
 	t.Contains(err.Error(), " result=MAJORITY")
 }
 
+func (t *testVoteproof) TestWeightedMajority() {
+	threshold, _ := NewThreshold(4, 67)
+
+	n0 := RandomNode("n0")
+	n1 := RandomNode("n1")
+
+	fact := NewDummyBallotFact()
+	fs0 := t.signFact(n0.Address(), n0.Privatekey(), fact, nil)
+
+	vp := VoteproofV0{
+		stage:          StageINIT,
+		suffrages:      []Address{n0.Address(), n1.Address()},
+		weights:        []uint{3, 1},
+		thresholdRatio: threshold.Ratio,
+		result:         VoteResultMajority,
+		majority:       fact,
+		facts:          []BallotFact{fact},
+		votes: []SignedBallotFact{
+			NewBaseSignedBallotFact(fact, fs0),
+		},
+		finishedAt: localtime.UTCNow(),
+	}
+	t.NoError(vp.IsValid(nil))
+
+	// NOTE without weights, 1 vote of 2 nodes is not enough
+	vp.weights = nil
+	err := vp.IsValid(nil)
+	t.Contains(err.Error(), "result should be not-yet")
+}
+
+func (t *testVoteproof) TestWeightedNotEnough() {
+	threshold, _ := NewThreshold(4, 67)
+
+	n0 := RandomNode("n0")
+	n1 := RandomNode("n1")
+
+	fact := NewDummyBallotFact()
+	fs1 := t.signFact(n1.Address(), n1.Privatekey(), fact, nil)
+
+	vp := VoteproofV0{
+		stage:          StageINIT,
+		suffrages:      []Address{n0.Address(), n1.Address()},
+		weights:        []uint{3, 1},
+		thresholdRatio: threshold.Ratio,
+		result:         VoteResultMajority,
+		majority:       fact,
+		facts:          []BallotFact{fact},
+		votes: []SignedBallotFact{
+			NewBaseSignedBallotFact(fact, fs1),
+		},
+		finishedAt: localtime.UTCNow(),
+	}
+	err := vp.IsValid(nil)
+	t.Contains(err.Error(), "result should be not-yet")
+}
+
+func (t *testVoteproof) TestWeightsMismatch() {
+	threshold, _ := NewThreshold(4, 67)
+
+	n0 := RandomNode("n0")
+	n1 := RandomNode("n1")
+
+	fact := NewDummyBallotFact()
+	fs0 := t.signFact(n0.Address(), n0.Privatekey(), fact, nil)
+
+	vp := VoteproofV0{
+		stage:          StageINIT,
+		suffrages:      []Address{n0.Address(), n1.Address()},
+		weights:        []uint{3},
+		thresholdRatio: threshold.Ratio,
+		result:         VoteResultMajority,
+		majority:       fact,
+		facts:          []BallotFact{fact},
+		votes: []SignedBallotFact{
+			NewBaseSignedBallotFact(fact, fs0),
+		},
+		finishedAt: localtime.UTCNow(),
+	}
+	err := vp.IsValid(nil)
+	t.Contains(err.Error(), "weights does not match with suffrages")
+
+	vp.weights = []uint{3, 0}
+	err = vp.IsValid(nil)
+	t.Contains(err.Error(), "zero weight found")
+}
+
 func TestVoteproof(t *testing.T) {
 	suite.Run(t, new(testVoteproof))
 }
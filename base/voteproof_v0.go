@@ -24,6 +24,7 @@ type VoteproofV0 struct {
 	height         Height
 	round          Round
 	suffrages      []Address
+	weights        []uint
 	thresholdRatio ThresholdRatio
 	result         VoteResultType
 	closed         bool
@@ -108,6 +109,18 @@ func (vp VoteproofV0) Suffrages() []Address {
 	return vp.suffrages
 }
 
+// Weights returns the voting weights of suffrages by same order. If empty,
+// every node has same weight.
+func (vp VoteproofV0) Weights() []uint {
+	return vp.weights
+}
+
+func (vp *VoteproofV0) SetWeights(weights []uint) *VoteproofV0 {
+	vp.weights = weights
+
+	return vp
+}
+
 func (vp VoteproofV0) ThresholdRatio() ThresholdRatio {
 	return vp.thresholdRatio
 }
@@ -192,6 +205,19 @@ func (vp VoteproofV0) suffragesBytes() []byte {
 	return util.ConcatBytesSlice(bs...)
 }
 
+func (vp VoteproofV0) weightsBytes() []byte {
+	if len(vp.weights) < 1 {
+		return nil
+	}
+
+	bs := make([][]byte, len(vp.weights))
+	for i := range vp.weights {
+		bs[i] = util.UintToBytes(vp.weights[i])
+	}
+
+	return util.ConcatBytesSlice(bs...)
+}
+
 func (vp VoteproofV0) Bytes() []byte {
 	var m []byte
 	if vp.majority != nil {
@@ -208,6 +234,7 @@ func (vp VoteproofV0) Bytes() []byte {
 		vp.factsBytes(),
 		vp.votesBytes(),
 		vp.suffragesBytes(),
+		vp.weightsBytes(),
 		localtime.NewTime(vp.finishedAt).Bytes(),
 	)
 }
@@ -221,10 +248,17 @@ func (vp VoteproofV0) IsValid(networkID []byte) error {
 		return err
 	}
 
+	if err := vp.isValidWeights(); err != nil {
+		return err
+	}
+
 	// check majority
-	if t, err := NewThreshold(uint(len(vp.suffrages)), vp.thresholdRatio); err != nil {
+	threshold, err := vp.threshold()
+	if err != nil {
 		return isvalid.InvalidError.Wrap(err)
-	} else if len(vp.votes) < int(t.Threshold) {
+	}
+
+	if vp.voted() < threshold.Threshold {
 		if vp.result != VoteResultNotYet {
 			return isvalid.InvalidError.Errorf("result should be not-yet: %s", vp.result)
 		}
@@ -232,42 +266,94 @@ func (vp VoteproofV0) IsValid(networkID []byte) error {
 		return nil
 	}
 
-	return vp.isValidCheckMajority()
+	return vp.isValidCheckMajority(threshold)
 }
 
-func (vp VoteproofV0) isValidCheckMajority() error {
-	threshold, err := NewThreshold(uint(len(vp.suffrages)), vp.thresholdRatio)
-	if err != nil {
-		return isvalid.InvalidError.Errorf("invalid threshold: %w", err)
+func (vp VoteproofV0) isValidWeights() error {
+	if len(vp.weights) < 1 {
+		return nil
 	}
 
-	counts := map[string]uint{}
+	if len(vp.weights) != len(vp.suffrages) {
+		return isvalid.InvalidError.Errorf(
+			"weights does not match with suffrages; %d != %d", len(vp.weights), len(vp.suffrages))
+	}
+
+	for i := range vp.weights {
+		if vp.weights[i] < 1 {
+			return isvalid.InvalidError.Errorf("zero weight found, %q", vp.suffrages[i])
+		}
+	}
+
+	ws := vp.weightsMap()
+	for i := range vp.votes {
+		n := vp.votes[i].FactSign().Node()
+		if _, found := ws[n.String()]; !found {
+			return isvalid.InvalidError.Errorf("vote from unknown node found, %q", n)
+		}
+	}
+
+	return nil
+}
+
+// weightsMap returns the weights by node address; if not weighted, returns nil.
+func (vp VoteproofV0) weightsMap() map[string]uint {
+	if len(vp.weights) < 1 {
+		return nil
+	}
+
+	m := map[string]uint{}
+	for i := range vp.suffrages {
+		m[vp.suffrages[i].String()] = vp.weights[i]
+	}
+
+	return m
+}
+
+func (vp VoteproofV0) threshold() (Threshold, error) {
+	if len(vp.weights) < 1 {
+		return NewThreshold(uint(len(vp.suffrages)), vp.thresholdRatio)
+	}
+
+	var total uint
+	for i := range vp.weights {
+		total += vp.weights[i]
+	}
+
+	return NewThreshold(total, vp.thresholdRatio)
+}
+
+// voted returns the sum of weights of votes.
+func (vp VoteproofV0) voted() uint {
+	ws := vp.weightsMap()
+	if ws == nil {
+		return uint(len(vp.votes))
+	}
+
+	var voted uint
 	for i := range vp.votes {
-		counts[vp.votes[i].Fact().Hash().String()]++
+		voted += ws[vp.votes[i].FactSign().Node().String()]
 	}
 
-	set := make([]uint, len(counts))
-	byCount := map[uint]string{}
+	return voted
+}
+
+func (vp VoteproofV0) isValidCheckMajority(threshold Threshold) error {
+	ws := vp.weightsMap()
 
-	var index int
-	for h, c := range counts {
-		set[index] = c
-		index++
-		byCount[c] = h
+	counts := map[string]uint{}
+	for i := range vp.votes {
+		w := uint(1)
+		if ws != nil {
+			w = ws[vp.votes[i].FactSign().Node().String()]
+		}
+
+		counts[vp.votes[i].Fact().Hash().String()] += w
 	}
 
 	var fact Fact
-	var factHash string
-	var result VoteResultType
-	switch index := FindMajority(threshold.Total, threshold.Threshold, set...); index {
-	case -1:
-		result = VoteResultNotYet
-	case -2:
-		result = VoteResultDraw
-	default:
-		result = VoteResultMajority
-		factHash = byCount[set[index]]
-
+	result, factHash := FindMajorityFromCounts(threshold.Total, threshold.Threshold, counts)
+	if result == VoteResultMajority {
 		for _, f := range vp.facts {
 			if factHash == f.Hash().String() {
 				fact = f
@@ -62,6 +62,10 @@ func (vp VoteproofV0) MarshalBSON() ([]byte, error) {
 		m["majority"] = vp.majority
 	}
 
+	if len(vp.weights) > 0 {
+		m["weights"] = vp.weights
+	}
+
 	return bsonenc.Marshal(bsonenc.MergeBSONM(
 		bsonenc.NewHintedDoc(vp.Hint()),
 		m,
@@ -72,6 +76,7 @@ type VoteproofV0UnpackBSON struct { // nolint
 	HT Height           `bson:"height"`
 	RD Round            `bson:"round"`
 	SS []AddressDecoder `bson:"suffrages"`
+	WS []uint           `bson:"weights,omitempty"`
 	TH ThresholdRatio   `bson:"threshold"`
 	RS VoteResultType   `bson:"result"`
 	ST Stage            `bson:"stage"`
@@ -93,6 +98,7 @@ func (vp *VoteproofV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
 		vpp.HT,
 		vpp.RD,
 		vpp.SS,
+		vpp.WS,
 		vpp.TH,
 		vpp.RS,
 		vpp.ST,
@@ -23,6 +23,7 @@ func (vp *VoteproofV0) unpack( // nolint
 	height Height,
 	round Round,
 	bSuffrages []AddressDecoder,
+	weights []uint,
 	thresholdRatio ThresholdRatio,
 	result VoteResultType,
 	stage Stage,
@@ -73,6 +74,7 @@ func (vp *VoteproofV0) unpack( // nolint
 
 	vp.height = height
 	vp.round = round
+	vp.weights = weights
 	vp.thresholdRatio = thresholdRatio
 	vp.result = result
 	vp.stage = stage
@@ -111,6 +111,50 @@ func (t *testVoteproofEncode) TestMarshal() {
 	}
 }
 
+func (t *testVoteproofEncode) TestMarshalWeights() {
+	threshold, _ := NewThreshold(4, 67)
+
+	n0 := RandomNode("n0")
+	n1 := RandomNode("n1")
+
+	fact := NewDummyBallotFact()
+	fact.S = StageINIT
+	fact.HT = Height(33)
+	fact.R = Round(3)
+
+	fs0, _ := NewBaseBallotFactSignFromFact(fact, n0.Address(), n0.Privatekey(), nil)
+
+	i := NewVoteproofV0(
+		Height(33),
+		Round(3),
+		[]Address{n0.Address(), n1.Address()},
+		threshold.Ratio,
+		StageINIT,
+	)
+
+	vp := &i
+	vp = vp.SetWeights([]uint{3, 1}).
+		SetResult(VoteResultMajority).
+		SetFacts([]BallotFact{fact}).
+		SetMajority(fact).
+		SetVotes([]SignedBallotFact{
+			NewBaseSignedBallotFact(fact, fs0),
+		}).
+		Finish()
+	t.NoError(vp.IsValid(nil))
+
+	b, err := t.enc.Marshal(vp)
+	t.NoError(err)
+	t.NotNil(b)
+
+	var uvp VoteproofV0
+	t.NoError(encoder.Decode(b, t.enc, &uvp))
+
+	t.NoError(uvp.IsValid(nil))
+	t.Equal(vp.Weights(), uvp.Weights())
+	t.Equal(vp.Bytes(), uvp.Bytes())
+}
+
 func TestVoteproofEncodeJSON(t *testing.T) {
 	b := new(testVoteproofEncode)
 	b.enc = jsonenc.NewEncoder()
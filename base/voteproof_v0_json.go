@@ -49,6 +49,7 @@ type VoteproofV0PackJSON struct {
 	HT Height             `json:"height"`
 	RD Round              `json:"round"`
 	SS []Address          `json:"suffrages"`
+	WS []uint             `json:"weights,omitempty"`
 	TH ThresholdRatio     `json:"threshold"`
 	RS VoteResultType     `json:"result"`
 	ST Stage              `json:"stage"`
@@ -72,6 +73,7 @@ func (vp VoteproofV0) MarshalJSON() ([]byte, error) {
 		HT:         vp.height,
 		RD:         vp.round,
 		SS:         vp.suffrages,
+		WS:         vp.weights,
 		TH:         vp.thresholdRatio,
 		RS:         vp.result,
 		ST:         vp.stage,
@@ -87,6 +89,7 @@ type VoteproofV0UnpackJSON struct {
 	HT Height           `json:"height"`
 	RD Round            `json:"round"`
 	SS []AddressDecoder `json:"suffrages"`
+	WS []uint           `json:"weights,omitempty"`
 	TH ThresholdRatio   `json:"threshold"`
 	RS VoteResultType   `json:"result"`
 	ST Stage            `json:"stage"`
@@ -108,6 +111,7 @@ func (vp *VoteproofV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
 		vpp.HT,
 		vpp.RD,
 		vpp.SS,
+		vpp.WS,
 		vpp.TH,
 		vpp.RS,
 		vpp.ST,
@@ -78,8 +78,16 @@ func (bc *BallotChecker) InTimespan() (bool, error) {
 	return true, nil
 }
 
-// InSuffrage checks BallotFactSign.Node() is inside suffrage
+// InSuffrage checks BallotFactSign.Node() is inside suffrage. If the suffrage
+// weights of ballot height are not yet known, the current suffrage is used.
 func (bc *BallotChecker) InSuffrage() (bool, error) {
+	switch sw, found, err := suffrageWeights(bc.suffrage, bc.ballot.RawFact().Height()); {
+	case err != nil:
+		return false, err
+	case found:
+		return sw.Exists(bc.factSign.Node()), nil
+	}
+
 	if !bc.suffrage.IsInside(bc.factSign.Node()) {
 		return false, nil
 	}
@@ -177,11 +185,8 @@ func (bc *BallotChecker) CheckVoteproof() (bool, error) {
 	vc := NewVoteProofChecker(voteproof, bc.policy, bc.suffrage)
 	_ = vc.SetLogging(bc.Logging)
 
-	if err := util.NewChecker("ballot-voteproof-checker", []util.CheckerFunc{
-		vc.IsValid,
-		vc.NodeIsInSuffrage,
-		vc.CheckThreshold,
-	}).Check(); err != nil {
+	// NOTE the failed check of voteproof stops the ballot with error
+	if err := vc.Check(); err != nil {
 		return false, err
 	}
 
@@ -208,6 +208,78 @@ func (t *testBallotChecker) TestIsInSuffrage() {
 	}
 }
 
+type testWeightedSuffrage struct {
+	base.Suffrage
+	weights func(base.Height) (base.SuffrageWeights, error)
+}
+
+func (sf testWeightedSuffrage) Weights(height base.Height) (base.SuffrageWeights, error) {
+	return sf.weights(height)
+}
+
+func (t *testBallotChecker) TestWeightedSuffrageHigherHeight() {
+	last := t.LastManifest(t.local.Database()).Height()
+
+	sw := base.NewEqualSuffrageWeights(t.suf.Nodes())
+	suf := testWeightedSuffrage{
+		Suffrage: t.suf,
+		weights: func(height base.Height) (base.SuffrageWeights, error) {
+			if height-1 > last {
+				return base.SuffrageWeights{}, util.NotFoundError.Errorf("suffrage weights of height, %d not found", height)
+			}
+
+			return sw, nil
+		},
+	}
+
+	newACCEPTBallot := func(height base.Height) base.ACCEPTBallot {
+		ivp, err := t.NewVoteproof(base.StageINIT, ballot.NewINITFact(height, base.Round(0), valuehash.RandomSHA256()), t.local, t.remote)
+		t.NoError(err)
+
+		ab, err := ballot.NewACCEPT(
+			ballot.NewACCEPTFact(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256()),
+			t.remote.Node().Address(),
+			ivp,
+			t.remote.Node().Privatekey(), t.remote.Policy().NetworkID(),
+		)
+		t.NoError(err)
+
+		return ab
+	}
+
+	{ // NOTE weights of ballot height is not yet known; the ballot of node, which is behind, is not rejected
+		ab := newACCEPTBallot(last + 3)
+
+		bc := NewBallotChecker(ab, t.local.Database(), t.local.Policy(), suf, t.local.Nodes(), t.local.Database().LastVoteproof(base.StageINIT))
+
+		var finished bool
+		err := util.NewChecker("test-ballot-checker", []util.CheckerFunc{
+			bc.InSuffrage,
+			bc.CheckVoteproof,
+			func() (bool, error) {
+				finished = true
+
+				return true, nil
+			},
+		}).Check()
+		t.NoError(err)
+		t.True(finished)
+	}
+
+	{ // NOTE weights of ballot height is known; voteproof without weights is rejected
+		ab := newACCEPTBallot(last + 1)
+
+		bc := NewBallotChecker(ab, t.local.Database(), t.local.Policy(), suf, t.local.Nodes(), t.local.Database().LastVoteproof(base.StageINIT))
+
+		err := util.NewChecker("test-ballot-checker", []util.CheckerFunc{
+			bc.InSuffrage,
+			bc.CheckVoteproof,
+		}).Check()
+		t.Error(err)
+		t.Contains(err.Error(), "invalid voteproof")
+	}
+}
+
 func (t *testBallotChecker) TestCheckWithLastVoteproof() {
 	avp := t.local.Database().LastVoteproof(base.StageACCEPT)
 	t.NotNil(avp)
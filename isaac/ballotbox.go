@@ -17,6 +17,8 @@ type Ballotbox struct {
 	vrs           *sync.Map
 	suffragesFunc func() []base.Address
 	thresholdFunc func() base.Threshold
+	weightsFunc   func(base.Height) (base.SuffrageWeights, error)
+	equivocationF func(base.EquivocationV0)
 	latestBallot  base.Ballot
 }
 
@@ -31,16 +33,64 @@ func NewBallotbox(suffragesFunc func() []base.Address, thresholdFunc func() base
 	}
 }
 
+// SetWeightsFunc makes Ballotbox to count the votes by the weight of node.
+// The suffrage nodes of height are also decided by weightsFunc instead of
+// suffragesFunc.
+func (bb *Ballotbox) SetWeightsFunc(weightsFunc func(base.Height) (base.SuffrageWeights, error)) *Ballotbox {
+	bb.Lock()
+	defer bb.Unlock()
+
+	bb.weightsFunc = weightsFunc
+
+	return bb
+}
+
+// SetEquivocationFunc sets the callback, which is called when the node votes
+// twice with the different ballot facts at the same height, round and stage.
+func (bb *Ballotbox) SetEquivocationFunc(f func(base.EquivocationV0)) *Ballotbox {
+	bb.Lock()
+	defer bb.Unlock()
+
+	bb.equivocationF = f
+
+	return bb
+}
+
 // Vote receives Ballot and returns VoteRecords, which has VoteRecords.Result()
 // and VoteRecords.Majority().
 func (bb *Ballotbox) Vote(blt base.Ballot) (base.Voteproof, error) {
-	if err := bb.canVote(blt); err != nil {
+	newVoteRecords, err := bb.newVoteRecordsFunc(blt)
+	if err != nil {
 		return nil, err
 	}
 
-	vrs := bb.loadVoteRecords(blt, true)
+	vp, ev, equivocated := bb.loadVoteRecords(blt, newVoteRecords).voteWithEquivocation(blt)
+	if equivocated {
+		bb.Equivocated(ev)
+	}
+
+	metricBallots.WithLabelValues(blt.RawFact().Stage().String()).Inc()
+	if vp.IsFinished() && !vp.IsClosed() { // NOTE newly finished voteproof
+		metricVoteproofs.WithLabelValues(vp.Stage().String(), vp.Result().String()).Inc()
+	}
+
+	return vp, nil
+}
+
+// Equivocated reports the evidence of double vote; not only the double vote of
+// ballot, but also the conflicting proposals are reported.
+func (bb *Ballotbox) Equivocated(ev base.EquivocationV0) {
+	metricEquivocations.WithLabelValues(ev.Stage().String()).Inc()
+
+	bb.Log().Warn().Object("equivocation", ev).Msg("double vote found")
+
+	bb.RLock()
+	f := bb.equivocationF
+	bb.RUnlock()
 
-	return vrs.Vote(blt), nil
+	if f != nil {
+		f(ev)
+	}
 }
 
 func (bb *Ballotbox) Clean(height base.Height) error {
@@ -115,7 +165,9 @@ func (bb *Ballotbox) Empty() {
 	bb.latestBallot = nil
 }
 
-func (bb *Ballotbox) loadVoteRecords(blt base.Ballot, ifNotCreate bool) *VoteRecords {
+// loadVoteRecords returns the VoteRecords of ballot; if not found and
+// newVoteRecords is not nil, new VoteRecords is created.
+func (bb *Ballotbox) loadVoteRecords(blt base.Ballot, newVoteRecords func() *VoteRecords) *VoteRecords {
 	bb.Lock()
 	defer bb.Unlock()
 
@@ -144,23 +196,59 @@ func (bb *Ballotbox) loadVoteRecords(blt base.Ballot, ifNotCreate bool) *VoteRec
 	var vrs *VoteRecords
 	if i, found := bb.vrs.Load(key); found {
 		vrs = i.(*VoteRecords)
-	} else if ifNotCreate {
-		vrs = NewVoteRecords(fact.Height(), fact.Round(), fact.Stage(), bb.suffragesFunc(), bb.thresholdFunc())
+	} else if newVoteRecords != nil {
+		vrs = newVoteRecords()
 		bb.vrs.Store(key, vrs)
 	}
 
 	return vrs
 }
 
+func (bb *Ballotbox) newVoteRecordsFunc(blt base.Ballot) (func() *VoteRecords, error) {
+	if !blt.RawFact().Stage().CanVote() {
+		return nil, errors.Errorf("this ballot is not for voting; stage=%s", blt.RawFact().Stage())
+	}
+
+	bb.RLock()
+	weightsFunc := bb.weightsFunc
+	bb.RUnlock()
+
+	fact := blt.RawFact()
+
+	if weightsFunc == nil {
+		if err := bb.canVote(blt); err != nil {
+			return nil, err
+		}
+
+		return func() *VoteRecords {
+			return NewVoteRecords(fact.Height(), fact.Round(), fact.Stage(), bb.suffragesFunc(), bb.thresholdFunc())
+		}, nil
+	}
+
+	sw, err := weightsFunc(fact.Height())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get suffrage weights")
+	}
+
+	if !sw.Exists(blt.FactSign().Node()) {
+		return nil, errors.Errorf("this ballot is not in suffrages")
+	}
+
+	threshold, err := base.NewThreshold(sw.Total(), bb.thresholdFunc().Ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() *VoteRecords {
+		return NewWeightedVoteRecords(fact.Height(), fact.Round(), fact.Stage(), sw, threshold)
+	}, nil
+}
+
 func (*Ballotbox) vrsKey(blt base.BallotFact) string {
 	return fmt.Sprintf("%d-%d-%d", blt.Height(), blt.Round(), blt.Stage())
 }
 
 func (bb *Ballotbox) canVote(blt base.Ballot) error {
-	if !blt.RawFact().Stage().CanVote() {
-		return errors.Errorf("this ballot is not for voting; stage=%s", blt.RawFact().Stage())
-	}
-
 	var found bool
 	for _, a := range bb.suffragesFunc() {
 		if a.Equal(blt.FactSign().Node()) {
@@ -35,7 +35,7 @@ func (t *testBallotbox) thresholdFunc(total uint, ratio float64) func() base.Thr
 	t.NoError(ls.Initialize())
 
 	r := base.ThresholdRatio(ratio)
-	_ = ls.Policy().SetThresholdRatio(r)
+	_, _ = ls.Policy().SetThresholdRatio(r)
 
 	threshold, _ := base.NewThreshold(total, r)
 
@@ -144,6 +144,53 @@ func (t *testBallotbox) TestVoteRace() {
 	<-checkDone
 }
 
+func (t *testBallotbox) TestEquivocation() {
+	n0 := base.RandomStringAddress()
+	n1 := base.RandomStringAddress()
+	bb := NewBallotbox(t.suffragesFunc(n0, n1), t.thresholdFunc(2, 67))
+
+	var evs []base.EquivocationV0
+	_ = bb.SetEquivocationFunc(func(ev base.EquivocationV0) {
+		evs = append(evs, ev)
+	})
+
+	previousBlock := valuehash.RandomSHA256()
+
+	first := t.newINITBallot(base.Height(10), base.Round(0), n0, previousBlock)
+	_, err := bb.Vote(first)
+	t.NoError(err)
+
+	// NOTE same fact is not double vote
+	_, err = bb.Vote(t.newINITBallot(base.Height(10), base.Round(0), n0, previousBlock))
+	t.NoError(err)
+	t.Empty(evs)
+
+	// NOTE different round is not double vote
+	_, err = bb.Vote(t.newINITBallot(base.Height(10), base.Round(1), n0, nil))
+	t.NoError(err)
+	t.Empty(evs)
+
+	second := t.newINITBallot(base.Height(10), base.Round(0), n0, nil)
+	vp, err := bb.Vote(second)
+	t.NoError(err)
+	t.Equal(base.VoteResultNotYet, vp.Result())
+
+	t.Equal(1, len(evs))
+	t.NoError(evs[0].IsValid(nil))
+	t.True(n0.Equal(evs[0].Node()))
+	t.True(first.Fact().Hash().Equal(evs[0].First().Fact().Hash()))
+	t.True(second.Fact().Hash().Equal(evs[0].Second().Fact().Hash()))
+
+	// NOTE evidence is reported once
+	_, err = bb.Vote(t.newINITBallot(base.Height(10), base.Round(0), n0, nil))
+	t.NoError(err)
+	t.Equal(1, len(evs))
+
+	// NOTE second vote is not counted
+	vrs := bb.loadVoteRecords(first, nil)
+	t.True(first.Fact().Hash().Equal(vrs.votes[n0.String()]))
+}
+
 func (t *testBallotbox) TestINITVoteResultNotYet() {
 	node := base.RandomStringAddress()
 	bb := NewBallotbox(t.suffragesFunc(node), t.thresholdFunc(2, 67))
@@ -157,7 +204,7 @@ func (t *testBallotbox) TestINITVoteResultNotYet() {
 	t.Equal(ba.Fact().Round(), vp.Round())
 	t.Equal(ba.Fact().Stage(), vp.Stage())
 
-	vrs := bb.loadVoteRecords(ba, false)
+	vrs := bb.loadVoteRecords(ba, nil)
 	t.NotNil(vrs)
 
 	ib, found := vrs.ballots[ba.FactSign().Node().String()]
@@ -366,7 +413,7 @@ func (t *testBallotbox) TestACCEPTVoteResultNotYet() {
 	t.Equal(ba.Fact().Round(), vp.Round())
 	t.Equal(ba.Fact().Stage(), vp.Stage())
 
-	vrs := bb.loadVoteRecords(ba, false)
+	vrs := bb.loadVoteRecords(ba, nil)
 	t.NotNil(vrs)
 
 	ib, found := vrs.ballots[ba.FactSign().Node().String()]
@@ -491,6 +538,70 @@ func (t *testBallotbox) TestLatestBallot() {
 	t.True(bb.LatestBallot().Hash().Equal(ba2.Hash()))
 }
 
+func (t *testBallotbox) TestWeightedVoteResultMajority() {
+	n0 := base.RandomStringAddress()
+	n1 := base.RandomStringAddress()
+	n2 := base.RandomStringAddress()
+
+	sw := base.NewSuffrageWeights(map[base.Address]uint{n0: 5, n1: 1, n2: 1})
+
+	bb := NewBallotbox(t.suffragesFunc(n0, n1, n2), t.thresholdFunc(3, 67))
+	_ = bb.SetWeightsFunc(func(base.Height) (base.SuffrageWeights, error) {
+		return sw, nil
+	})
+
+	previousBlock := valuehash.RandomSHA256()
+
+	ba1 := t.newINITBallot(base.Height(10), base.Round(0), n1, previousBlock)
+	vp, err := bb.Vote(ba1)
+	t.NoError(err)
+	t.Equal(base.VoteResultNotYet, vp.Result())
+
+	// NOTE n0 has enough weight
+	ba0 := t.newINITBallot(base.Height(10), base.Round(0), n0, previousBlock)
+	vp, err = bb.Vote(ba0)
+	t.NoError(err)
+	t.Equal(base.VoteResultMajority, vp.Result())
+	t.True(vp.Majority().Hash().Equal(ba0.Fact().Hash()))
+	t.Equal(sw.Nodes(), vp.Suffrages())
+	t.Equal(sw.Weights(), vp.(base.VoteproofV0).Weights())
+	t.NoError(vp.IsValid(nil))
+}
+
+func (t *testBallotbox) TestWeightedVoteResultNotYet() {
+	n0 := base.RandomStringAddress()
+	n1 := base.RandomStringAddress()
+	n2 := base.RandomStringAddress()
+
+	sw := base.NewSuffrageWeights(map[base.Address]uint{n0: 5, n1: 1, n2: 1})
+
+	bb := NewBallotbox(t.suffragesFunc(n0, n1, n2), t.thresholdFunc(3, 67))
+	_ = bb.SetWeightsFunc(func(base.Height) (base.SuffrageWeights, error) {
+		return sw, nil
+	})
+
+	previousBlock := valuehash.RandomSHA256()
+
+	// NOTE 2 of 3 nodes voted, but weights are not enough
+	for _, n := range []base.Address{n1, n2} {
+		vp, err := bb.Vote(t.newINITBallot(base.Height(10), base.Round(0), n, previousBlock))
+		t.NoError(err)
+		t.Equal(base.VoteResultNotYet, vp.Result())
+	}
+}
+
+func (t *testBallotbox) TestWeightedNotInSuffrage() {
+	n0 := base.RandomStringAddress()
+
+	bb := NewBallotbox(t.suffragesFunc(n0), t.thresholdFunc(1, 67))
+	_ = bb.SetWeightsFunc(func(base.Height) (base.SuffrageWeights, error) {
+		return base.NewSuffrageWeights(map[base.Address]uint{n0: 1}), nil
+	})
+
+	_, err := bb.Vote(t.newINITBallot(base.Height(10), base.Round(0), base.RandomStringAddress(), nil))
+	t.Contains(err.Error(), "not in suffrages")
+}
+
 func TestBallotbox(t *testing.T) {
 	suite.Run(t, new(testBallotbox))
 }
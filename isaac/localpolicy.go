@@ -67,10 +67,14 @@ func (lp *LocalPolicy) ThresholdRatio() base.ThresholdRatio {
 	return lp.thresholdRatio.Value().(base.ThresholdRatio)
 }
 
-func (lp *LocalPolicy) SetThresholdRatio(ratio base.ThresholdRatio) *LocalPolicy {
+func (lp *LocalPolicy) SetThresholdRatio(ratio base.ThresholdRatio) (*LocalPolicy, error) {
+	if err := ratio.IsValid(nil); err != nil {
+		return nil, err
+	}
+
 	_ = lp.thresholdRatio.Set(ratio)
 
-	return lp
+	return lp, nil
 }
 
 func (lp *LocalPolicy) TimeoutWaitingProposal() time.Duration {
@@ -31,11 +31,16 @@ func (t *testPolicy) TestSet() {
 	p := NewLocalPolicy(nil)
 
 	th := base.ThresholdRatio(66.6)
-	_ = p.SetThresholdRatio(th)
+	_, err := p.SetThresholdRatio(th)
+	t.NoError(err)
+	t.Equal(th, p.ThresholdRatio())
+
+	_, err = p.SetThresholdRatio(base.ThresholdRatio(101))
+	t.Error(err)
 	t.Equal(th, p.ThresholdRatio())
 
 	maxOperationsInSeal := uint(33)
-	_, err := p.SetMaxOperationsInSeal(maxOperationsInSeal)
+	_, err = p.SetMaxOperationsInSeal(maxOperationsInSeal)
 	t.NoError(err)
 
 	t.Equal(maxOperationsInSeal, p.MaxOperationsInSeal())
@@ -0,0 +1,445 @@
+package isaac
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	DefaultMempoolTTL                = time.Hour
+	DefaultMempoolSenderLimit   uint = 100
+	DefaultMempoolSize          uint = 10000
+	DefaultMempoolEvictInterval      = time.Minute
+	MempoolFullError                 = util.NewError("mempool is full")
+	MempoolSenderLimitError          = util.NewError("too many pending operations of sender")
+	MempoolKnownOperationError       = util.NewError("known operation")
+)
+
+// OperationPriorityFunc returns the priority of operation; the operation,
+// which has higher priority, is included in proposal first.
+type OperationPriorityFunc func(operation.Operation) uint64
+
+type mempoolItem struct {
+	fact     valuehash.Hash
+	opHint   hint.Hint
+	sender   key.Publickey
+	priority uint64
+	addedAt  time.Time
+	seq      uint64
+}
+
+// Mempool keeps the index of the staged operations. The operations are still
+// stored in storage.Database; Mempool decides which operation can be staged
+// and which one goes to the next proposal.
+//
+// - the operation, which stays longer than ttl, is unstaged.
+// - the number of pending operations of one sender is limited by senderLimit.
+// - the total number of pending operations is limited by size.
+// - the operation, which has the known fact, is rejected.
+// - the operations are ordered by priority and the incoming order.
+//
+// The sender is the signer of the first fact sign of operation. The zero ttl,
+// senderLimit and size mean no limit.
+type Mempool struct {
+	sync.RWMutex
+	*logging.Logging
+	database    storage.Database
+	ttl         time.Duration
+	senderLimit uint
+	size        uint
+	priorities  *hint.Hintmap
+	items       map[string]*mempoolItem
+	senders     map[string]uint
+	seq         uint64
+}
+
+func NewMempool(db storage.Database, ttl time.Duration, senderLimit, size uint) *Mempool {
+	return &Mempool{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "mempool")
+		}),
+		database:    db,
+		ttl:         ttl,
+		senderLimit: senderLimit,
+		size:        size,
+		priorities:  hint.NewHintmap(),
+		items:       map[string]*mempoolItem{},
+		senders:     map[string]uint{},
+	}
+}
+
+// Initialize loads the staged operations of database into Mempool. The time,
+// when the operation was staged, is loaded from database; if not found, the
+// current time is used.
+func (mp *Mempool) Initialize() error {
+	mp.Lock()
+	defer mp.Unlock()
+
+	now := localtime.UTCNow()
+
+	return mp.database.StagedOperations(func(op operation.Operation) (bool, error) {
+		fh := op.Fact().Hash()
+		if _, found := mp.items[fh.String()]; found {
+			return true, nil
+		}
+
+		addedAt := now
+		switch t, found, err := mp.database.StagedOperationTime(fh); {
+		case err != nil:
+			return false, err
+		case found:
+			addedAt = t
+		}
+
+		mp.add(mp.newItem(op, addedAt))
+
+		return true, nil
+	}, true)
+}
+
+func (mp *Mempool) TTL() time.Duration {
+	return mp.ttl
+}
+
+func (mp *Mempool) SenderLimit() uint {
+	return mp.senderLimit
+}
+
+func (mp *Mempool) Size() uint {
+	return mp.size
+}
+
+// SetPriorityFunc sets the OperationPriorityFunc for the operation type of
+// hinter. The operation without OperationPriorityFunc has zero priority.
+func (mp *Mempool) SetPriorityFunc(ht hint.Hinter, f OperationPriorityFunc) error {
+	return mp.priorities.Add(ht, f)
+}
+
+// Len returns the number of pending operations.
+func (mp *Mempool) Len() int {
+	mp.RLock()
+	defer mp.RUnlock()
+
+	return len(mp.items)
+}
+
+// Add stages the admitted operations into database. The rejected operations
+// are ignored and Add returns the admitted operations only.
+func (mp *Mempool) Add(ops ...operation.Operation) ([]operation.Operation, error) {
+	mp.Lock()
+	defer mp.Unlock()
+
+	return mp.addOperations(ops, func(admitted []operation.Operation) error {
+		return mp.database.NewOperations(admitted)
+	})
+}
+
+// AddSeal stages the admitted operations of seal into database. If all the
+// operations are admitted, the seal is stored by
+// storage.Database.NewOperationSeals; if not, only the admitted operations are
+// stored. AddSeal returns the admitted operations only.
+func (mp *Mempool) AddSeal(sl operation.Seal) ([]operation.Operation, error) {
+	mp.Lock()
+	defer mp.Unlock()
+
+	ops := sl.Operations()
+
+	return mp.addOperations(ops, func(admitted []operation.Operation) error {
+		if len(admitted) == len(ops) {
+			return mp.database.NewOperationSeals([]operation.Seal{sl})
+		}
+
+		return mp.database.NewOperations(admitted)
+	})
+}
+
+// Evict unstages the expired operations.
+func (mp *Mempool) Evict() error {
+	mp.Lock()
+	defer mp.Unlock()
+
+	return mp.evict()
+}
+
+// Operations returns the fact hashes of pending operations for the next
+// proposal by priority. The operations, which are already stored in block or
+// expired, are unstaged.
+func (mp *Mempool) Operations(max uint) ([]valuehash.Hash, error) {
+	mp.Lock()
+	defer mp.Unlock()
+
+	if err := mp.evict(); err != nil {
+		return nil, err
+	}
+
+	items := mp.sorted()
+
+	var facts, uselesses []valuehash.Hash
+	for i := range items {
+		fh := items[i].fact
+
+		switch found, err := mp.database.HasOperationFact(fh); {
+		case err != nil:
+			return nil, err
+		case found:
+			uselesses = append(uselesses, fh)
+
+			continue
+		}
+
+		switch found, err := mp.database.HasStagedOperation(fh); {
+		case err != nil:
+			return nil, err
+		case !found:
+			mp.remove(fh.String())
+
+			continue
+		}
+
+		facts = append(facts, fh)
+		if max > 0 && uint(len(facts)) == max {
+			break
+		}
+	}
+
+	if err := mp.unstage(uselesses); err != nil {
+		return nil, err
+	}
+
+	return facts, nil
+}
+
+// RemoveFacts removes the facts from Mempool; the operations of the facts are
+// not unstaged from database.
+func (mp *Mempool) RemoveFacts(facts []valuehash.Hash) {
+	mp.Lock()
+	defer mp.Unlock()
+
+	for i := range facts {
+		mp.remove(facts[i].String())
+	}
+}
+
+// Pending returns the pending operations by priority.
+func (mp *Mempool) Pending(limit uint) []network.PendingOperationV0 {
+	mp.RLock()
+	defer mp.RUnlock()
+
+	items := mp.sorted()
+	if limit > 0 && uint(len(items)) > limit {
+		items = items[:limit]
+	}
+
+	pos := make([]network.PendingOperationV0, len(items))
+	for i := range items {
+		item := items[i]
+		pos[i] = network.NewPendingOperationV0(item.fact, item.opHint, item.sender, item.priority, item.addedAt)
+	}
+
+	return pos
+}
+
+func (mp *Mempool) addOperations(
+	ops []operation.Operation,
+	store func([]operation.Operation) error,
+) ([]operation.Operation, error) {
+	if err := mp.evict(); err != nil {
+		return nil, err
+	}
+
+	now := localtime.UTCNow()
+
+	var admitted []operation.Operation
+	items := make([]*mempoolItem, 0, len(ops))
+	for i := range ops {
+		op := ops[i]
+
+		item := mp.newItem(op, now)
+		if err := mp.admit(item); err != nil {
+			mp.Log().Debug().Err(err).Stringer("fact", item.fact).Msg("operation rejected by mempool")
+
+			continue
+		}
+
+		mp.add(item)
+
+		admitted = append(admitted, op)
+		items = append(items, item)
+	}
+
+	if len(admitted) < 1 {
+		return nil, nil
+	}
+
+	if err := store(admitted); err != nil {
+		for i := range items {
+			mp.remove(items[i].fact.String())
+		}
+
+		return nil, err
+	}
+
+	return admitted, nil
+}
+
+func (mp *Mempool) newItem(op operation.Operation, addedAt time.Time) *mempoolItem {
+	var sender key.Publickey
+	if fs := op.Signs(); len(fs) > 0 {
+		sender = fs[0].Signer()
+	}
+
+	return &mempoolItem{
+		fact:     op.Fact().Hash(),
+		opHint:   op.Hint(),
+		sender:   sender,
+		priority: mp.priority(op),
+		addedAt:  addedAt,
+	}
+}
+
+func (mp *Mempool) priority(op operation.Operation) uint64 {
+	i, err := mp.priorities.Compatible(op)
+	if err != nil || i == nil {
+		return 0
+	}
+
+	return i.(OperationPriorityFunc)(op)
+}
+
+func (mp *Mempool) admit(item *mempoolItem) error {
+	if _, found := mp.items[item.fact.String()]; found {
+		return MempoolKnownOperationError.Errorf("already in mempool")
+	}
+
+	if mp.size > 0 && uint(len(mp.items)) >= mp.size {
+		return MempoolFullError.Errorf("size=%d", mp.size)
+	}
+
+	if mp.senderLimit > 0 && item.sender != nil && mp.senders[item.sender.String()] >= mp.senderLimit {
+		return MempoolSenderLimitError.Errorf("sender=%s limit=%d", item.sender, mp.senderLimit)
+	}
+
+	switch found, err := mp.database.HasOperationFact(item.fact); {
+	case err != nil:
+		return err
+	case found:
+		return MempoolKnownOperationError.Errorf("already stored in block")
+	}
+
+	switch found, err := mp.database.HasStagedOperation(item.fact); {
+	case err != nil:
+		return err
+	case found:
+		return MempoolKnownOperationError.Errorf("already staged")
+	}
+
+	return nil
+}
+
+func (mp *Mempool) add(item *mempoolItem) {
+	mp.seq++
+	item.seq = mp.seq
+
+	mp.items[item.fact.String()] = item
+
+	if item.sender != nil {
+		mp.senders[item.sender.String()]++
+	}
+}
+
+func (mp *Mempool) remove(k string) {
+	item, found := mp.items[k]
+	if !found {
+		return
+	}
+
+	delete(mp.items, k)
+
+	if item.sender == nil {
+		return
+	}
+
+	s := item.sender.String()
+	switch n := mp.senders[s]; {
+	case n < 2:
+		delete(mp.senders, s)
+	default:
+		mp.senders[s] = n - 1
+	}
+}
+
+func (mp *Mempool) unstage(facts []valuehash.Hash) error {
+	if len(facts) < 1 {
+		return nil
+	}
+
+	if err := mp.database.UnstagedOperations(facts); err != nil {
+		return errors.Wrap(err, "failed to unstage operations")
+	}
+
+	for i := range facts {
+		mp.remove(facts[i].String())
+	}
+
+	return nil
+}
+
+func (mp *Mempool) evict() error {
+	if mp.ttl < 1 {
+		return nil
+	}
+
+	now := localtime.UTCNow()
+
+	var expired []valuehash.Hash
+	for k := range mp.items {
+		if item := mp.items[k]; now.Sub(item.addedAt) > mp.ttl {
+			expired = append(expired, item.fact)
+		}
+	}
+
+	if len(expired) < 1 {
+		return nil
+	}
+
+	if err := mp.unstage(expired); err != nil {
+		return err
+	}
+
+	mp.Log().Debug().Int("expired", len(expired)).Msg("expired operations evicted")
+
+	return nil
+}
+
+func (mp *Mempool) sorted() []*mempoolItem {
+	items := make([]*mempoolItem, len(mp.items))
+	var i int
+	for k := range mp.items {
+		items[i] = mp.items[k]
+		i++
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		switch {
+		case items[i].priority != items[j].priority:
+			return items[i].priority > items[j].priority
+		default:
+			return items[i].seq < items[j].seq
+		}
+	})
+
+	return items
+}
@@ -0,0 +1,261 @@
+package isaac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testMempool struct {
+	BaseTest
+}
+
+func (t *testMempool) TestAdd() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), 0, 0, 0)
+
+	ops := t.NewOperations(local, 3)
+
+	admitted, err := mp.Add(ops...)
+	t.NoError(err)
+	t.Equal(len(ops), len(admitted))
+	t.Equal(len(ops), mp.Len())
+
+	for i := range ops {
+		found, err := local.Database().HasStagedOperation(ops[i].Fact().Hash())
+		t.NoError(err)
+		t.True(found)
+	}
+}
+
+func (t *testMempool) TestAddSeal() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), 0, 0, 2)
+
+	sl, ops := t.NewOperationSeal(local, 2)
+
+	admitted, err := mp.AddSeal(sl)
+	t.NoError(err)
+	t.Equal(len(ops), len(admitted))
+
+	for i := range ops {
+		found, err := local.Database().HasStagedOperation(ops[i].Fact().Hash())
+		t.NoError(err)
+		t.True(found)
+	}
+
+	// NOTE mempool is full; none of operations is admitted
+	sl, ops = t.NewOperationSeal(local, 1)
+
+	admitted, err = mp.AddSeal(sl)
+	t.NoError(err)
+	t.Empty(admitted)
+
+	found, err := local.Database().HasStagedOperation(ops[0].Fact().Hash())
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testMempool) TestInitialize() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), 0, 0, 0)
+
+	ops := t.NewOperations(local, 2)
+	_, err := mp.Add(ops...)
+	t.NoError(err)
+
+	added := map[string]time.Time{}
+	for _, po := range mp.Pending(0) {
+		added[po.Fact().String()] = po.AddedAt()
+	}
+
+	<-time.After(time.Millisecond * 100)
+
+	// NOTE the added time is loaded from database
+	nmp := NewMempool(local.Database(), 0, 0, 0)
+	t.NoError(nmp.Initialize())
+	t.Equal(len(ops), nmp.Len())
+
+	for _, po := range nmp.Pending(0) {
+		a, found := added[po.Fact().String()]
+		t.True(found)
+		t.True(po.AddedAt().Sub(a) < time.Millisecond*100)
+	}
+}
+
+func (t *testMempool) TestKnownFact() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), 0, 0, 0)
+
+	ops := t.NewOperations(local, 2)
+
+	// NOTE already staged
+	t.NoError(local.Database().NewOperations(ops[:1]))
+
+	admitted, err := mp.Add(ops...)
+	t.NoError(err)
+	t.Equal(1, len(admitted))
+	t.True(ops[1].Fact().Hash().Equal(admitted[0].Fact().Hash()))
+
+	// NOTE already in mempool
+	admitted, err = mp.Add(ops[1])
+	t.NoError(err)
+	t.Empty(admitted)
+	t.Equal(1, mp.Len())
+}
+
+func (t *testMempool) TestSenderLimit() {
+	ls := t.Locals(2)
+	local, other := ls[0], ls[1]
+
+	mp := NewMempool(local.Database(), 0, 2, 0)
+
+	admitted, err := mp.Add(t.NewOperations(local, 3)...)
+	t.NoError(err)
+	t.Equal(2, len(admitted))
+
+	// NOTE other sender is not affected
+	admitted, err = mp.Add(t.NewOperations(other, 1)...)
+	t.NoError(err)
+	t.Equal(1, len(admitted))
+
+	// NOTE removed facts release the quota of sender
+	mp.RemoveFacts([]valuehash.Hash{mp.Pending(1)[0].Fact()})
+
+	admitted, err = mp.Add(t.NewOperations(local, 2)...)
+	t.NoError(err)
+	t.Equal(1, len(admitted))
+	t.Equal(3, mp.Len())
+}
+
+func (t *testMempool) TestSize() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), 0, 0, 2)
+
+	admitted, err := mp.Add(t.NewOperations(local, 3)...)
+	t.NoError(err)
+	t.Equal(2, len(admitted))
+	t.Equal(2, mp.Len())
+}
+
+func (t *testMempool) TestExpire() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), time.Millisecond*100, 0, 0)
+
+	ops := t.NewOperations(local, 2)
+	_, err := mp.Add(ops...)
+	t.NoError(err)
+
+	<-time.After(time.Millisecond * 200)
+
+	facts, err := mp.Operations(0)
+	t.NoError(err)
+	t.Empty(facts)
+	t.Equal(0, mp.Len())
+
+	for i := range ops {
+		found, err := local.Database().HasStagedOperation(ops[i].Fact().Hash())
+		t.NoError(err)
+		t.False(found)
+	}
+}
+
+func (t *testMempool) TestEvict() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), time.Millisecond*100, 0, 0)
+
+	ops := t.NewOperations(local, 2)
+	_, err := mp.Add(ops...)
+	t.NoError(err)
+
+	t.NoError(mp.Evict())
+	t.Equal(len(ops), mp.Len())
+
+	<-time.After(time.Millisecond * 200)
+
+	t.NoError(mp.Evict())
+	t.Equal(0, mp.Len())
+
+	for i := range ops {
+		found, err := local.Database().HasStagedOperation(ops[i].Fact().Hash())
+		t.NoError(err)
+		t.False(found)
+
+		_, found, err = local.Database().StagedOperationTime(ops[i].Fact().Hash())
+		t.NoError(err)
+		t.False(found)
+	}
+}
+
+func (t *testMempool) TestPriority() {
+	local := t.Locals(1)[0]
+
+	mp := NewMempool(local.Database(), 0, 0, 0)
+
+	ops := t.NewOperations(local, 4)
+
+	priorities := map[string]uint64{
+		ops[2].Fact().Hash().String(): 10,
+		ops[3].Fact().Hash().String(): 5,
+	}
+
+	t.NoError(mp.SetPriorityFunc(KVOperation{}, func(op operation.Operation) uint64 {
+		return priorities[op.Fact().Hash().String()]
+	}))
+
+	_, err := mp.Add(ops...)
+	t.NoError(err)
+
+	facts, err := mp.Operations(3)
+	t.NoError(err)
+	t.Equal(3, len(facts))
+
+	for i, j := range []int{2, 3, 0} {
+		t.True(ops[j].Fact().Hash().Equal(facts[i]))
+	}
+
+	pending := mp.Pending(0)
+	t.Equal(len(ops), len(pending))
+	t.Equal(uint64(10), pending[0].Priority())
+	t.True(local.Node().Publickey().Equal(pending[0].Sender()))
+}
+
+func (t *testMempool) TestProposalMaker() {
+	local := t.Locals(1)[0]
+
+	var maxOperations uint = 2
+	_, _ = local.Policy().SetMaxOperationsInProposal(maxOperations)
+
+	mp := NewMempool(local.Database(), 0, 0, 0)
+
+	ops := t.NewOperations(local, 3)
+	_, err := mp.Add(ops...)
+	t.NoError(err)
+
+	proposalMaker := NewProposalMaker(local.Node(), local.Database(), local.Policy()).SetMempool(mp)
+
+	proposal, err := proposalMaker.Proposal(base.Height(33), base.Round(1), nil)
+	t.NoError(err)
+
+	facts := proposal.Fact().Operations()
+	t.Equal(int(maxOperations), len(facts))
+
+	for i := range facts {
+		t.True(ops[i].Fact().Hash().Equal(facts[i]))
+	}
+}
+
+func TestMempool(t *testing.T) {
+	suite.Run(t, new(testMempool))
+}
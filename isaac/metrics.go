@@ -0,0 +1,31 @@
+package isaac
+
+import (
+	"github.com/spikeekips/mitum/util/metrics"
+)
+
+var (
+	metricBallots = metrics.NewCounterVec(
+		"mitum_ballotbox_ballots_total", "number of ballots voted in ballotbox", "stage")
+	metricVoteproofs = metrics.NewCounterVec(
+		"mitum_ballotbox_voteproofs_total", "number of voteproofs finished in ballotbox", "stage", "result")
+	metricEquivocations = metrics.NewCounterVec(
+		"mitum_ballotbox_equivocations_total", "number of double votes found in ballotbox", "stage")
+	metricSyncerTargetHeight = metrics.NewGaugeVec(
+		"mitum_syncers_target_height", "target height of syncers")
+	metricSyncerSyncedHeight = metrics.NewGaugeVec(
+		"mitum_syncers_synced_height", "last height saved by syncers")
+	metricSyncerSavedBlocks = metrics.NewCounterVec(
+		"mitum_syncers_saved_blocks_total", "number of blocks saved by syncers")
+)
+
+func init() {
+	metrics.MustRegister(
+		metricBallots,
+		metricVoteproofs,
+		metricEquivocations,
+		metricSyncerTargetHeight,
+		metricSyncerSyncedHeight,
+		metricSyncerSavedBlocks,
+	)
+}
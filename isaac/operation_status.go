@@ -0,0 +1,74 @@
+package isaac
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// StagedOperationStatuses returns the staged status of operations.
+func StagedOperationStatuses(ops []operation.Operation) []operation.StatusV0 {
+	now := localtime.UTCNow()
+
+	statuses := make([]operation.StatusV0, len(ops))
+	for i := range ops {
+		statuses[i] = operation.NewStatusV0(
+			ops[i].Fact().Hash(), operation.StatusStaged, base.NilHeight, base.Round(0), "", now)
+	}
+
+	return statuses
+}
+
+// ProposedOperationStatuses returns the proposed status of the operations in
+// proposal.
+func ProposedOperationStatuses(fact base.ProposalFact) []operation.StatusV0 {
+	now := localtime.UTCNow()
+
+	facts := fact.Operations()
+	statuses := make([]operation.StatusV0, len(facts))
+	for i := range facts {
+		statuses[i] = operation.NewStatusV0(
+			facts[i], operation.StatusProposed, fact.Height(), fact.Round(), "", now)
+	}
+
+	return statuses
+}
+
+// BlockOperationStatuses returns the final status of the operations in block
+// from the operations tree; the operation, which has reason, is rejected.
+func BlockOperationStatuses(blk block.Block) ([]operation.StatusV0, error) {
+	tr := blk.OperationsTree()
+	if tr.Len() < 1 {
+		return nil, nil
+	}
+
+	now := localtime.UTCNow()
+
+	statuses := make([]operation.StatusV0, tr.Len())
+	if err := tr.Traverse(func(no tree.FixedTreeNode) (bool, error) {
+		ono, ok := no.(operation.FixedTreeNode)
+		if !ok {
+			return false, errors.Errorf("not operation.FixedTreeNode, %T", no)
+		}
+
+		status := operation.StatusIncluded
+		var reason string
+		if ono.Reason() != nil {
+			status = operation.StatusRejected
+			reason = ono.Reason().Msg()
+		}
+
+		statuses[no.Index()] = operation.NewStatusV0(
+			valuehash.NewBytes(no.Key()), status, blk.Height(), blk.Round(), reason, now)
+
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,50 @@
+package isaac
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testOperationStatus struct {
+	suite.Suite
+}
+
+func (t *testOperationStatus) TestBlockOperationStatuses() {
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(2), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	facts := []valuehash.Hash{valuehash.RandomSHA256(), valuehash.RandomSHA256()}
+
+	tg := tree.NewFixedTreeGenerator(uint64(len(facts)))
+	t.NoError(tg.Add(operation.NewFixedTreeNode(0, facts[0].Bytes(), true, nil)))
+	t.NoError(tg.Add(operation.NewFixedTreeNode(1, facts[1].Bytes(), false, operation.NewBaseReasonError("showme"))))
+
+	tr, err := tg.Tree()
+	t.NoError(err)
+
+	statuses, err := BlockOperationStatuses(blk.SetOperationsTree(tr).(block.Block))
+	t.NoError(err)
+	t.Equal(len(facts), len(statuses))
+
+	for i := range statuses {
+		t.NoError(statuses[i].IsValid(nil))
+		t.True(facts[i].Equal(statuses[i].Fact()))
+		t.Equal(blk.Height(), statuses[i].Height())
+		t.Equal(blk.Round(), statuses[i].Round())
+	}
+
+	t.Equal(operation.StatusIncluded, statuses[0].Status())
+	t.Empty(statuses[0].Reason())
+	t.Equal(operation.StatusRejected, statuses[1].Status())
+	t.Contains(statuses[1].Reason(), "showme")
+}
+
+func TestOperationStatus(t *testing.T) {
+	suite.Run(t, new(testOperationStatus))
+}
@@ -1,15 +1,32 @@
 package isaac
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
+// ConflictingProposalError means the proposer signed the different proposals
+// for the same height and round; it carries the evidence.
+type ConflictingProposalError struct {
+	ev base.EquivocationV0
+}
+
+func (err ConflictingProposalError) Error() string {
+	return fmt.Sprintf("conflicting proposal, %s", err.ev.Key())
+}
+
+func (err ConflictingProposalError) Equivocation() base.EquivocationV0 {
+	return err.ev
+}
+
 type ProposalChecker struct {
 	*logging.Logging
 	database storage.Database
@@ -81,6 +98,29 @@ func (pvc *ProposalChecker) IsProposer() (bool, error) {
 	return true, nil
 }
 
+// IsConflicting checks the proposer already signed the different proposal for
+// the same height and round; the proposal in database is the first one.
+func (pvc *ProposalChecker) IsConflicting() (bool, error) {
+	pr, found, err := pvc.database.ProposalByPoint(pvc.fact.Height(), pvc.fact.Round(), pvc.fact.Proposer())
+	switch {
+	case err != nil:
+		return false, err
+	case !found:
+		return true, nil
+	case pr.Fact().Hash().Equal(pvc.fact.Hash()):
+		return true, nil
+	}
+
+	ev, ok := base.NewEquivocationV0FromBallots(pr, pvc.proposal, localtime.UTCNow())
+	if !ok {
+		return true, nil
+	}
+
+	pvc.Log().Warn().Stringer("first_proposal", pr.Fact().Hash()).Msg("conflicting proposal found")
+
+	return false, ConflictingProposalError{ev: ev}
+}
+
 func (pvc *ProposalChecker) SaveProposal() (bool, error) {
 	switch err := pvc.database.NewProposal(pvc.proposal); {
 	case err == nil:
@@ -7,6 +7,7 @@ import (
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/ballot"
 	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -53,6 +54,56 @@ func (t *testProposalChecker) TestIsKnown() {
 	}
 }
 
+func (t *testProposalChecker) TestIsConflicting() {
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	initFact := ib.Fact()
+
+	vp, err := t.NewVoteproof(base.StageINIT, initFact, t.local, t.remote)
+	t.NoError(err)
+
+	pr := t.NewProposal(t.remote, initFact.Round(), nil, vp)
+
+	{
+		pvc, err := NewProposalValidationChecker(t.local.Database(), t.suf, t.local.Nodes(), pr, nil)
+		t.NoError(err)
+
+		keep, err := pvc.IsConflicting()
+		t.True(keep)
+		t.NoError(err)
+	}
+
+	t.NoError(t.local.Database().NewProposal(pr))
+
+	{ // NOTE same proposal
+		pvc, err := NewProposalValidationChecker(t.local.Database(), t.suf, t.local.Nodes(), pr, nil)
+		t.NoError(err)
+
+		keep, err := pvc.IsConflicting()
+		t.True(keep)
+		t.NoError(err)
+	}
+
+	// NOTE different proposal at same height and round
+	npr := t.NewProposal(t.remote, initFact.Round(), []valuehash.Hash{valuehash.RandomSHA256()}, vp)
+	t.False(pr.Fact().Hash().Equal(npr.Fact().Hash()))
+
+	pvc, err := NewProposalValidationChecker(t.local.Database(), t.suf, t.local.Nodes(), npr, nil)
+	t.NoError(err)
+
+	keep, err := pvc.IsConflicting()
+	t.False(keep)
+
+	var cerr ConflictingProposalError
+	t.True(errors.As(err, &cerr))
+
+	ev := cerr.Equivocation()
+	t.NoError(ev.IsValid(t.local.Policy().NetworkID()))
+	t.Equal(base.StageProposal, ev.Stage())
+	t.True(t.remote.Node().Address().Equal(ev.Node()))
+	t.True(pr.Fact().Hash().Equal(ev.First().Fact().Hash()))
+	t.True(npr.Fact().Hash().Equal(ev.Second().Fact().Hash()))
+}
+
 func (t *testProposalChecker) TestCheckSigning() {
 	ib := t.NewINITBallot(t.local, base.Round(0), nil)
 	initFact := ib.Fact()
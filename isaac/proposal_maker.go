@@ -17,6 +17,7 @@ type ProposalMaker struct {
 	database storage.Database
 	policy   *LocalPolicy
 	proposed base.Proposal
+	mempool  *Mempool
 }
 
 func NewProposalMaker(
@@ -27,7 +28,21 @@ func NewProposalMaker(
 	return &ProposalMaker{local: local, database: db, policy: policy}
 }
 
+// SetMempool makes ProposalMaker to select the operations by Mempool.
+func (pm *ProposalMaker) SetMempool(mp *Mempool) *ProposalMaker {
+	pm.Lock()
+	defer pm.Unlock()
+
+	pm.mempool = mp
+
+	return pm
+}
+
 func (pm *ProposalMaker) operations() ([]valuehash.Hash, error) {
+	if pm.mempool != nil {
+		return pm.mempool.Operations(pm.policy.MaxOperationsInProposal())
+	}
+
 	founds := map[ /* Operation.Fact().Hash() */ string]struct{}{}
 
 	maxOperations := pm.policy.MaxOperationsInProposal()
@@ -0,0 +1,147 @@
+package isaac
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/tree"
+)
+
+var (
+	SnapshotV0Type        = hint.Type("snapshot")
+	SnapshotV0Hint        = hint.NewHint(SnapshotV0Type, "v0.0.1")
+	SnapshotV0Hinter      = SnapshotV0{BaseHinter: hint.NewBaseHinter(SnapshotV0Hint)}
+	SnapshotStateV0Type   = hint.Type("snapshot-state")
+	SnapshotStateV0Hint   = hint.NewHint(SnapshotStateV0Type, "v0.0.1")
+	SnapshotStateV0Hinter = SnapshotStateV0{BaseHinter: hint.NewBaseHinter(SnapshotStateV0Hint)}
+)
+
+// SnapshotV0 is the header of state snapshot. The snapshot has all the states
+// at the height of block. The block carries the ACCEPT voteproof, which
+// confirms the block. The manifests from the block down to the "from" height
+// follow the header, so the states tree root, Manifest.StatesHash() of every
+// state can be traced to the block.
+type SnapshotV0 struct {
+	hint.BaseHinter
+	blk       block.Block
+	from      base.Height
+	states    uint64
+	createdAt time.Time
+}
+
+func NewSnapshotV0(blk block.Block, from base.Height, states uint64) SnapshotV0 {
+	return SnapshotV0{
+		BaseHinter: hint.NewBaseHinter(SnapshotV0Hint),
+		blk:        blk,
+		from:       from,
+		states:     states,
+		createdAt:  localtime.UTCNow(),
+	}
+}
+
+func (sn SnapshotV0) String() string {
+	return jsonenc.ToString(sn)
+}
+
+func (sn SnapshotV0) IsValid(networkID []byte) error {
+	if err := isvalid.Check(networkID, false, sn.BaseHinter, sn.blk); err != nil {
+		return err
+	}
+
+	if sn.from < base.PreGenesisHeight || sn.from > sn.blk.Height() {
+		return isvalid.InvalidError.Errorf("invalid from height, %d; block=%d", sn.from, sn.blk.Height())
+	}
+
+	avp := sn.blk.ConsensusInfo().ACCEPTVoteproof()
+	switch {
+	case avp == nil:
+		return isvalid.InvalidError.Errorf("empty accept voteproof")
+	case avp.Stage() != base.StageACCEPT:
+		return isvalid.InvalidError.Errorf("not accept voteproof, %v", avp.Stage())
+	case avp.Height() != sn.blk.Height():
+		return isvalid.InvalidError.Errorf(
+			"accept voteproof has different height; %d != block=%d", avp.Height(), sn.blk.Height())
+	case avp.Result() != base.VoteResultMajority:
+		return isvalid.InvalidError.Errorf("accept voteproof is not majority, %v", avp.Result())
+	}
+
+	fact, ok := avp.Majority().(base.ACCEPTBallotFact)
+	if !ok {
+		return isvalid.InvalidError.Errorf("not ACCEPTBallotFact, %T", avp.Majority())
+	}
+
+	if !fact.NewBlock().Equal(sn.blk.Hash()) {
+		return isvalid.InvalidError.Errorf("new block of accept voteproof does not match with block")
+	}
+
+	return nil
+}
+
+// Block is the last block of snapshot.
+func (sn SnapshotV0) Block() block.Block {
+	return sn.blk
+}
+
+func (sn SnapshotV0) Height() base.Height {
+	return sn.blk.Height()
+}
+
+// From is the lowest height of the manifests in snapshot.
+func (sn SnapshotV0) From() base.Height {
+	return sn.from
+}
+
+// States is the number of states in snapshot.
+func (sn SnapshotV0) States() uint64 {
+	return sn.states
+}
+
+func (sn SnapshotV0) CreatedAt() time.Time {
+	return sn.createdAt
+}
+
+// SnapshotStateV0 is the state of snapshot with the proof of states tree.
+type SnapshotStateV0 struct {
+	hint.BaseHinter
+	state state.State
+	proof []tree.FixedTreeNode
+}
+
+func NewSnapshotStateV0(st state.State, proof []tree.FixedTreeNode) SnapshotStateV0 {
+	return SnapshotStateV0{
+		BaseHinter: hint.NewBaseHinter(SnapshotStateV0Hint),
+		state:      st,
+		proof:      proof,
+	}
+}
+
+func (ss SnapshotStateV0) IsValid(networkID []byte) error {
+	if err := isvalid.Check(networkID, false, ss.BaseHinter, ss.state); err != nil {
+		return err
+	}
+
+	if len(ss.proof) < 1 {
+		return isvalid.InvalidError.Errorf("empty proof")
+	}
+
+	return nil
+}
+
+// Verify checks the state is in the states tree of manifest.
+func (ss SnapshotStateV0) Verify(manifest block.Manifest) error {
+	return block.VerifyStateProof(manifest, ss.state, ss.proof)
+}
+
+func (ss SnapshotStateV0) State() state.State {
+	return ss.state
+}
+
+func (ss SnapshotStateV0) Proof() []tree.FixedTreeNode {
+	return ss.proof
+}
@@ -0,0 +1,56 @@
+package isaac
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/spikeekips/mitum/base"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+)
+
+func (sn SnapshotV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(sn.Hint()), bson.M{
+		"block":      sn.blk,
+		"from":       sn.from,
+		"states":     sn.states,
+		"created_at": sn.createdAt,
+	}))
+}
+
+type SnapshotV0UnpackerBSON struct {
+	BK bson.Raw    `bson:"block"`
+	FR base.Height `bson:"from"`
+	ST uint64      `bson:"states"`
+	CA time.Time   `bson:"created_at"`
+}
+
+func (sn *SnapshotV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var usn SnapshotV0UnpackerBSON
+	if err := enc.Unmarshal(b, &usn); err != nil {
+		return err
+	}
+
+	return sn.unpack(enc, usn.BK, usn.FR, usn.ST, usn.CA)
+}
+
+func (ss SnapshotStateV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(ss.Hint()), bson.M{
+		"state": ss.state,
+		"proof": ss.proof,
+	}))
+}
+
+type SnapshotStateV0UnpackerBSON struct {
+	ST bson.Raw `bson:"state"`
+	PR bson.Raw `bson:"proof"`
+}
+
+func (ss *SnapshotStateV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uss SnapshotStateV0UnpackerBSON
+	if err := enc.Unmarshal(b, &uss); err != nil {
+		return err
+	}
+
+	return ss.unpack(enc, uss.ST, uss.PR)
+}
@@ -0,0 +1,35 @@
+package isaac
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util/encoder"
+)
+
+func (sn *SnapshotV0) unpack(enc encoder.Encoder, bbk []byte, from base.Height, states uint64, createdAt time.Time) error {
+	if err := encoder.Decode(bbk, enc, &sn.blk); err != nil {
+		return err
+	}
+
+	sn.from = from
+	sn.states = states
+	sn.createdAt = createdAt
+
+	return nil
+}
+
+func (ss *SnapshotStateV0) unpack(enc encoder.Encoder, bst, bpr []byte) error {
+	if err := encoder.Decode(bst, enc, &ss.state); err != nil {
+		return err
+	}
+
+	pr, err := network.DecodeProofNodes(enc, bpr)
+	if err != nil {
+		return err
+	}
+	ss.proof = pr
+
+	return nil
+}
@@ -0,0 +1,524 @@
+package isaac
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/tree"
+)
+
+// ExportSnapshot writes the gzipped snapshot of the states at the given
+// height. The snapshot is the lines of encoded items; SnapshotV0 header, the
+// manifests by descending height and SnapshotStateV0 of every state.
+func ExportSnapshot(
+	ctx context.Context,
+	w io.Writer,
+	db storage.Database,
+	bd blockdata.Blockdata,
+	height base.Height,
+) (SnapshotV0, error) {
+	var bdm block.BlockdataMap
+	switch i, found, err := db.BlockdataMap(height); {
+	case err != nil:
+		return SnapshotV0{}, err
+	case !found:
+		return SnapshotV0{}, util.NotFoundError.Errorf("block data map, %d not found", height)
+	default:
+		bdm = i
+	}
+
+	blk, err := blockdata.LoadBlock(ctx, bd, bdm)
+	if err != nil {
+		return SnapshotV0{}, err
+	} else if !blk.Hash().Equal(bdm.Block()) {
+		return SnapshotV0{}, errors.Errorf("block hash does not match with block data map, %d", height)
+	}
+
+	// NOTE collect the keys of states by height
+	keys := map[base.Height][]string{}
+	var count uint64
+	from := height
+	if err := db.States(height, func(st state.State) (bool, error) {
+		keys[st.Height()] = append(keys[st.Height()], st.Key())
+		count++
+
+		if st.Height() < from {
+			from = st.Height()
+		}
+
+		return true, nil
+	}); err != nil {
+		return SnapshotV0{}, err
+	}
+
+	sn := NewSnapshotV0(blk, from, count)
+
+	gw := gzip.NewWriter(w)
+	enc := db.Encoder()
+
+	if err := writeSnapshotLine(gw, enc, sn); err != nil {
+		return SnapshotV0{}, err
+	}
+
+	for h := height - 1; h >= from; h-- {
+		switch m, found, err := db.ManifestByHeight(h); {
+		case err != nil:
+			return SnapshotV0{}, err
+		case !found:
+			return SnapshotV0{}, util.NotFoundError.Errorf("manifest, %d not found", h)
+		default:
+			if err := writeSnapshotLine(gw, enc, m); err != nil {
+				return SnapshotV0{}, err
+			}
+		}
+	}
+
+	heights := make([]base.Height, len(keys))
+	var i int
+	for h := range keys {
+		heights[i] = h
+		i++
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	for i := range heights {
+		if err := exportSnapshotStates(ctx, gw, db, bd, height, heights[i], keys[heights[i]]); err != nil {
+			return SnapshotV0{}, err
+		}
+	}
+
+	if err := gw.Close(); err != nil {
+		return SnapshotV0{}, err
+	}
+
+	return sn, nil
+}
+
+func exportSnapshotStates(
+	ctx context.Context,
+	w io.Writer,
+	db storage.Database,
+	bd blockdata.Blockdata,
+	height, stateHeight base.Height,
+	keys []string,
+) error {
+	var bdm block.BlockdataMap
+	switch i, found, err := db.BlockdataMap(stateHeight); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("block data map, %d not found", stateHeight)
+	default:
+		bdm = i
+	}
+
+	r, err := blockdata.OpenItem(ctx, bd, bdm.StatesTree())
+	if err != nil {
+		return err
+	}
+
+	tr, err := bd.Writer().ReadStatesTree(r)
+	_ = r.Close()
+	if err != nil {
+		return err
+	}
+
+	indexes := map[string]uint64{}
+	if err := tr.Traverse(func(n tree.FixedTreeNode) (bool, error) {
+		indexes[string(n.Key())] = n.Index()
+
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	for i := range keys {
+		st, found, err := db.StateAt(keys[i], height)
+		switch {
+		case err != nil:
+			return err
+		case !found:
+			return util.NotFoundError.Errorf("state, %q not found", keys[i])
+		case st.Height() != stateHeight:
+			return errors.Errorf("state, %q was updated; %d != %d", keys[i], st.Height(), stateHeight)
+		}
+
+		index, found := indexes[string(st.Hash().Bytes())]
+		if !found {
+			return util.NotFoundError.Errorf("state, %q not found in states tree of %d", keys[i], stateHeight)
+		}
+
+		pr, err := tr.Proof(index)
+		if err != nil {
+			return err
+		}
+
+		if err := writeSnapshotLine(w, db.Encoder(), NewSnapshotStateV0(st, pr)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeSnapshotLine(w io.Writer, enc encoder.Encoder, i interface{}) error {
+	b, err := enc.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(b, '\n'))
+
+	return err
+}
+
+// ImportSnapshot verifies the snapshot and stores it into the empty database
+// and block data. The ACCEPT voteproof of snapshot block is checked with the
+// policy and suffrage, the manifests should be chained to the block and every
+// state should be proved by the states tree root of the manifest at it's
+// height. The states of the snapshot block should be same with the states tree
+// of block. The states under the block height are stored directly and the last
+// block is stored with the block data, so after importing, the last block of
+// database becomes the snapshot block.
+//
+// NOTE the proof of state only proves that the state was stored at it's own
+// height; block does not have the hash of whole states, so the snapshot can not
+// prove that the states under the block height are not updated later and that
+// no state is missing. Snapshot should be exported by trusted node.
+func ImportSnapshot(
+	ctx context.Context,
+	r io.Reader,
+	db storage.Database,
+	bd blockdata.Blockdata,
+	policy *LocalPolicy,
+	suffrage base.Suffrage,
+) (block.Block, error) {
+	switch _, found, err := db.LastManifest(); {
+	case err != nil:
+		return nil, err
+	case found:
+		return nil, errors.Errorf("database is not empty")
+	}
+
+	su, ok := db.(storage.StateUpdater)
+	if !ok {
+		return nil, errors.Errorf("database does not support to store state, %T", db)
+	}
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = gr.Close()
+	}()
+
+	im := &snapshotImporter{
+		db:        db,
+		su:        su,
+		policy:    policy,
+		suffrage:  suffrage,
+		manifests: map[base.Height]block.Manifest{},
+		keys:      map[string]struct{}{},
+		blkStates: map[string]struct{}{},
+	}
+
+	blk, err := im.importStates(gr)
+	if err == nil {
+		err = saveSnapshotBlock(ctx, db, bd, blk)
+	}
+
+	if err != nil {
+		if cerr := db.Clean(); cerr != nil {
+			return nil, errors.Wrapf(err, "failed to clean database: %v", cerr)
+		}
+
+		return nil, err
+	}
+
+	return blk, nil
+}
+
+type snapshotImporter struct {
+	db        storage.Database
+	su        storage.StateUpdater
+	policy    *LocalPolicy
+	suffrage  base.Suffrage
+	header    *SnapshotV0
+	last      block.Manifest
+	manifests map[base.Height]block.Manifest
+	keys      map[string]struct{}
+	blkStates map[string]struct{}
+	states    uint64
+	// NOTE weightsState is the suffrage weights state carried in snapshot
+	weightsState state.State
+}
+
+// weightsStateSuffrage is the base.WeightedSuffrage, which keeps the suffrage
+// weights in the state of StateKey(); before the state is stored, Genesis()
+// is used.
+type weightsStateSuffrage interface {
+	base.WeightedSuffrage
+	StateKey() string
+	Genesis() base.SuffrageWeights
+}
+
+// snapshotSuffrage returns the weights from snapshot for the snapshot height.
+type snapshotSuffrage struct {
+	weightsStateSuffrage
+	height  base.Height
+	weights base.SuffrageWeights
+}
+
+func (sf snapshotSuffrage) Weights(height base.Height) (base.SuffrageWeights, error) {
+	if height != sf.height {
+		return sf.weightsStateSuffrage.Weights(height)
+	}
+
+	return sf.weights, nil
+}
+
+func (im *snapshotImporter) importStates(r io.Reader) (block.Block, error) {
+	if err := util.Readlines(r, func(b []byte) error {
+		switch {
+		case im.header == nil:
+			return im.readHeader(b)
+		case im.last.Height() > im.header.From():
+			return im.readManifest(b)
+		default:
+			return im.readState(b)
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case im.header == nil:
+		return nil, errors.Errorf("empty snapshot")
+	case im.last.Height() != im.header.From():
+		return nil, errors.Errorf("not enough manifests; %d != from, %d", im.last.Height(), im.header.From())
+	case im.states != im.header.States():
+		return nil, errors.Errorf("not enough states; %d != %d", im.states, im.header.States())
+	case len(im.blkStates) > 0:
+		return nil, errors.Errorf(
+			"not enough states of snapshot block; %d states in states tree missing", len(im.blkStates))
+	}
+
+	if err := im.checkACCEPTVoteproof(); err != nil {
+		return nil, err
+	}
+
+	return im.header.Block(), nil
+}
+
+// checkACCEPTVoteproof checks the ACCEPT voteproof of snapshot block by the
+// suffrage. The database is empty, so the suffrage weights of snapshot
+// height can not be loaded from the database; if suffrage keeps the weights
+// in state, the weights are decided by the weights state carried in snapshot.
+func (im *snapshotImporter) checkACCEPTVoteproof() error {
+	avp := im.header.Block().ConsensusInfo().ACCEPTVoteproof()
+
+	suffrage := im.suffrage
+	if sf, ok := im.suffrage.(weightsStateSuffrage); ok {
+		sw, err := im.snapshotWeights(sf)
+		if err != nil {
+			return err
+		}
+
+		suffrage = snapshotSuffrage{weightsStateSuffrage: sf, height: avp.Height(), weights: sw}
+	}
+
+	if err := NewVoteProofChecker(avp, im.policy, suffrage).CheckStored(); err != nil {
+		return errors.Wrap(err, "invalid accept voteproof of snapshot")
+	}
+
+	return nil
+}
+
+// snapshotWeights returns the suffrage weights of snapshot height, which are
+// decided by the weights state of the previous height. If the weights state
+// is not in snapshot, the genesis weights are used.
+func (im *snapshotImporter) snapshotWeights(sf weightsStateSuffrage) (base.SuffrageWeights, error) {
+	st := im.weightsState
+	switch {
+	case st == nil:
+		return sf.Genesis(), nil
+	case st.Height() >= im.header.Height():
+		// NOTE the weights state was updated by the snapshot block, so the
+		// weights of previous height are not in snapshot.
+		return base.SuffrageWeights{}, errors.Errorf(
+			"suffrage weights of snapshot block can not be verified; weights state was updated at %d", st.Height())
+	}
+
+	sw, ok := st.Value().Interface().(base.SuffrageWeights)
+	if !ok {
+		return base.SuffrageWeights{}, errors.Errorf(
+			"invalid suffrage weights state value, %T", st.Value().Interface())
+	}
+
+	return sw, nil
+}
+
+func (im *snapshotImporter) readHeader(b []byte) error {
+	var sn SnapshotV0
+	if err := encoder.Decode(b, im.db.Encoder(), &sn); err != nil {
+		return errors.Wrap(err, "failed to decode snapshot header")
+	}
+
+	if err := sn.IsValid(im.policy.NetworkID()); err != nil {
+		return errors.Wrap(err, "invalid snapshot header")
+	}
+
+	avp := sn.Block().ConsensusInfo().ACCEPTVoteproof()
+
+	// NOTE threshold ratio of policy can be changed after snapshot block, so
+	// the threshold ratio of voteproof should not be lower than the local
+	// policy. The voters of voteproof are checked after the states are read by
+	// checkACCEPTVoteproof.
+	if tr := im.policy.ThresholdRatio(); avp.ThresholdRatio() < tr {
+		return errors.Errorf(
+			"threshold ratio of accept voteproof of snapshot is lower than policy; %v < %v", avp.ThresholdRatio(), tr)
+	}
+
+	if err := sn.Block().StatesTree().Traverse(func(n tree.FixedTreeNode) (bool, error) {
+		im.blkStates[string(n.Key())] = struct{}{}
+
+		return true, nil
+	}); err != nil {
+		return errors.Wrap(err, "failed to load states tree of snapshot block")
+	}
+
+	im.header = &sn
+	im.setManifest(sn.Block().Manifest())
+
+	return nil
+}
+
+func (im *snapshotImporter) readManifest(b []byte) error {
+	var m block.Manifest
+	if err := encoder.Decode(b, im.db.Encoder(), &m); err != nil {
+		return errors.Wrap(err, "failed to decode manifest")
+	}
+
+	switch err := m.IsValid(im.policy.NetworkID()); {
+	case err != nil:
+		return errors.Wrapf(err, "invalid manifest, %d", m.Height())
+	case m.Height() != im.last.Height()-1:
+		return errors.Errorf("unexpected manifest height; %d != %d", m.Height(), im.last.Height()-1)
+	case !im.last.PreviousBlock().Equal(m.Hash()):
+		return errors.Errorf("manifest, %d does not match with previous block of %d", m.Height(), im.last.Height())
+	}
+
+	im.setManifest(m)
+
+	return nil
+}
+
+func (im *snapshotImporter) setManifest(m block.Manifest) {
+	im.last = m
+
+	// NOTE the manifest without states is not needed to verify states
+	if h := m.StatesHash(); h != nil && !h.IsEmpty() {
+		im.manifests[m.Height()] = m
+	}
+}
+
+func (im *snapshotImporter) readState(b []byte) error {
+	var ss SnapshotStateV0
+	if err := encoder.Decode(b, im.db.Encoder(), &ss); err != nil {
+		return errors.Wrap(err, "failed to decode snapshot state")
+	}
+
+	if err := ss.IsValid(im.policy.NetworkID()); err != nil {
+		return errors.Wrap(err, "invalid snapshot state")
+	}
+
+	st := ss.State()
+
+	if st.Height() > im.header.Height() {
+		return errors.Errorf("state, %q is higher than snapshot block; %d > %d", st.Key(), st.Height(), im.header.Height())
+	}
+
+	m, found := im.manifests[st.Height()]
+	if !found {
+		return errors.Errorf("manifest of state, %q not found; height=%d", st.Key(), st.Height())
+	}
+
+	if err := ss.Verify(m); err != nil {
+		return errors.Wrapf(err, "failed to verify state, %q", st.Key())
+	}
+
+	if _, found := im.keys[st.Key()]; found {
+		return errors.Errorf("duplicated state, %q", st.Key())
+	}
+	im.keys[st.Key()] = struct{}{}
+
+	if sf, ok := im.suffrage.(weightsStateSuffrage); ok && st.Key() == sf.StateKey() {
+		im.weightsState = st
+	}
+
+	im.states++
+
+	// NOTE the states of last block are stored with block
+	if st.Height() == im.header.Height() {
+		k := string(st.Hash().Bytes())
+		if _, found := im.blkStates[k]; !found {
+			return errors.Errorf("state, %q not found in states tree of snapshot block", st.Key())
+		}
+
+		delete(im.blkStates, k)
+
+		return nil
+	}
+
+	return im.su.NewState(st)
+}
+
+func saveSnapshotBlock(ctx context.Context, db storage.Database, bd blockdata.Blockdata, blk block.Block) error {
+	bs, err := bd.NewSession(blk.Height())
+	if err != nil {
+		return err
+	}
+
+	if err := bs.SetBlock(blk); err != nil {
+		_ = bs.Cancel()
+
+		return err
+	}
+
+	bdm, err := bd.SaveSession(bs)
+	if err != nil {
+		_ = bs.Cancel()
+
+		return err
+	}
+
+	ds, err := db.NewSession(blk)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = ds.Close()
+	}()
+
+	if err := ds.SetBlock(ctx, blk); err != nil {
+		return err
+	}
+
+	return ds.Commit(ctx, bdm)
+}
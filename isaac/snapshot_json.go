@@ -0,0 +1,74 @@
+package isaac
+
+import (
+	"encoding/json"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/tree"
+)
+
+type SnapshotV0PackerJSON struct {
+	jsonenc.HintedHead
+	BK block.Block    `json:"block"`
+	FR base.Height    `json:"from"`
+	ST uint64         `json:"states"`
+	CA localtime.Time `json:"created_at"`
+}
+
+func (sn SnapshotV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(SnapshotV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(sn.Hint()),
+		BK:         sn.blk,
+		FR:         sn.from,
+		ST:         sn.states,
+		CA:         localtime.NewTime(sn.createdAt),
+	})
+}
+
+type SnapshotV0UnpackerJSON struct {
+	BK json.RawMessage `json:"block"`
+	FR base.Height     `json:"from"`
+	ST uint64          `json:"states"`
+	CA localtime.Time  `json:"created_at"`
+}
+
+func (sn *SnapshotV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var usn SnapshotV0UnpackerJSON
+	if err := enc.Unmarshal(b, &usn); err != nil {
+		return err
+	}
+
+	return sn.unpack(enc, usn.BK, usn.FR, usn.ST, usn.CA.Time)
+}
+
+type SnapshotStateV0PackerJSON struct {
+	jsonenc.HintedHead
+	ST state.State          `json:"state"`
+	PR []tree.FixedTreeNode `json:"proof"`
+}
+
+func (ss SnapshotStateV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(SnapshotStateV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(ss.Hint()),
+		ST:         ss.state,
+		PR:         ss.proof,
+	})
+}
+
+type SnapshotStateV0UnpackerJSON struct {
+	ST json.RawMessage `json:"state"`
+	PR json.RawMessage `json:"proof"`
+}
+
+func (ss *SnapshotStateV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uss SnapshotStateV0UnpackerJSON
+	if err := enc.Unmarshal(b, &uss); err != nil {
+		return err
+	}
+
+	return ss.unpack(enc, uss.ST, uss.PR)
+}
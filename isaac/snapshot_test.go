@@ -0,0 +1,488 @@
+//go:build test
+// +build test
+
+package isaac
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSnapshot struct {
+	BaseTest
+	local *Local
+	ops   []operation.Operation
+}
+
+func (t *testSnapshot) SetupSuite() {
+	t.BaseTest.SetupSuite()
+
+	_ = t.Encs.TestAddHinter(SnapshotV0Hinter)
+	_ = t.Encs.TestAddHinter(SnapshotStateV0Hinter)
+}
+
+func (t *testSnapshot) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	t.local = t.Locals(1)[0]
+	t.NoError(blockdata.Clean(t.local.Database(), t.local.Blockdata(), false))
+
+	// NOTE genesis block has states
+	t.ops = make([]operation.Operation, 3)
+	for i := range t.ops {
+		op, err := NewKVOperation(
+			t.local.Node().Privatekey(),
+			util.UUID().Bytes(),
+			fmt.Sprintf("key-%d", i),
+			util.UUID().Bytes(),
+			nil,
+		)
+		t.NoError(err)
+
+		t.ops[i] = op
+	}
+
+	gg, err := NewGenesisBlockV0Generator(
+		t.local.Node(), t.local.Database(), t.local.Blockdata(), t.local.Policy(), t.ops)
+	t.NoError(err)
+
+	_, err = gg.Generate()
+	t.NoError(err)
+
+	t.GenerateBlocks([]*Local{t.local}, base.Height(3))
+}
+
+func (t *testSnapshot) emptyLocal() *Local {
+	uid := util.UUID().String()
+
+	root, err := os.MkdirTemp(t.Root, "localfs-")
+	t.NoError(err)
+
+	bd := localfs.NewBlockdata(root, t.JSONEnc)
+	t.NoError(bd.Initialize())
+
+	local, err := NewLocal(t.Database(t.Encs, t.JSONEnc), bd, node.RandomLocal(uid), channetwork.RandomChannel(uid), TestNetworkID)
+	t.NoError(err)
+	t.NoError(local.Initialize())
+
+	// NOTE the blocks of BlockV0DummyGenerator have the threshold ratio, 67
+	_, err = local.Policy().SetThresholdRatio(base.ThresholdRatio(67))
+	t.NoError(err)
+
+	t.ls = append(t.ls, local)
+
+	return local
+}
+
+func (t *testSnapshot) export(height base.Height) (SnapshotV0, []byte) {
+	buf := bytes.NewBuffer(nil)
+
+	sn, err := ExportSnapshot(context.Background(), buf, t.local.Database(), t.local.Blockdata(), height)
+	t.NoError(err)
+
+	return sn, buf.Bytes()
+}
+
+func (t *testSnapshot) lines(b []byte) [][]byte {
+	gr, err := gzip.NewReader(bytes.NewReader(b))
+	t.NoError(err)
+
+	var lines [][]byte
+	t.NoError(util.Readlines(gr, func(l []byte) error {
+		lines = append(lines, l)
+
+		return nil
+	}))
+
+	return lines
+}
+
+func (t *testSnapshot) compress(lines [][]byte) []byte {
+	buf := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buf)
+
+	w := bufio.NewWriter(gw)
+	for i := range lines {
+		_, err := w.Write(lines[i])
+		t.NoError(err)
+	}
+
+	t.NoError(w.Flush())
+	t.NoError(gw.Close())
+
+	return buf.Bytes()
+}
+
+func (t *testSnapshot) importSnapshot(local *Local, b []byte) (base.Height, error) {
+	blk, err := ImportSnapshot(
+		context.Background(),
+		bytes.NewReader(b),
+		local.Database(),
+		local.Blockdata(),
+		local.Policy(),
+		t.Suffrage(t.local, t.local),
+	)
+	if err != nil {
+		return base.NilHeight, err
+	}
+
+	return blk.Height(), nil
+}
+
+func (t *testSnapshot) TestExport() {
+	sn, b := t.export(base.Height(3))
+
+	t.NoError(sn.IsValid(TestNetworkID))
+	t.Equal(base.Height(3), sn.Height())
+	t.Equal(base.GenesisHeight, sn.From())
+	t.Equal(uint64(len(t.ops)), sn.States())
+
+	lines := t.lines(b)
+	t.Equal(1+3+len(t.ops), len(lines))
+
+	var usn SnapshotV0
+	t.NoError(encoder.Decode(lines[0], t.JSONEnc, &usn))
+	t.NoError(usn.IsValid(TestNetworkID))
+	t.True(sn.Block().Hash().Equal(usn.Block().Hash()))
+	t.Equal(sn.From(), usn.From())
+	t.Equal(sn.States(), usn.States())
+
+	for i := range t.ops {
+		var ss SnapshotStateV0
+		t.NoError(encoder.Decode(lines[4+i], t.JSONEnc, &ss))
+		t.NoError(ss.IsValid(TestNetworkID))
+
+		m, found, err := t.local.Database().ManifestByHeight(ss.State().Height())
+		t.NoError(err)
+		t.True(found)
+
+		t.NoError(ss.Verify(m))
+	}
+}
+
+func (t *testSnapshot) TestExportUnknownHeight() {
+	_, err := ExportSnapshot(context.Background(), io.Discard, t.local.Database(), t.local.Blockdata(), base.Height(4))
+	t.Error(err)
+	t.Contains(err.Error(), "not found")
+}
+
+func (t *testSnapshot) TestImport() {
+	_, b := t.export(base.Height(3))
+
+	other := t.emptyLocal()
+
+	height, err := t.importSnapshot(other, b)
+	t.NoError(err)
+	t.Equal(base.Height(3), height)
+
+	lm := t.LastManifest(t.local.Database())
+	t.CompareManifest(lm, t.LastManifest(other.Database()))
+
+	for i := range t.ops {
+		k := t.ops[i].(KVOperation).Key()
+
+		a, found, err := t.local.Database().State(k)
+		t.NoError(err)
+		t.True(found)
+
+		b, found, err := other.Database().State(k)
+		t.NoError(err)
+		t.True(found)
+
+		t.True(a.Hash().Equal(b.Hash()))
+	}
+
+	avp, err := other.Database().Voteproof(height, base.StageACCEPT)
+	t.NoError(err)
+	t.NotNil(avp)
+
+	_, err = blockdata.CheckBlock(other.Database(), other.Blockdata(), TestNetworkID)
+	t.NoError(err)
+
+	// NOTE syncing continues from snapshot block
+	t.SetupNodes(t.local, nil)
+	t.GenerateBlocks([]*Local{t.local}, height+2)
+
+	cs, err := NewGeneralSyncer(other.Database(), other.Blockdata(), other.Policy(),
+		func() map[string]network.Channel {
+			return map[string]network.Channel{t.local.Node().Address().String(): t.local.Channel()}
+		},
+		lm, height+2)
+	t.NoError(err)
+	defer cs.Close()
+
+	stateChan := make(chan SyncerStateChangedContext)
+	finishedChan := make(chan struct{})
+
+	go func() {
+		for ctx := range stateChan {
+			if ctx.State() == SyncerSaved {
+				finishedChan <- struct{}{}
+
+				break
+			}
+		}
+	}()
+
+	cs.SetStateChan(stateChan)
+
+	t.NoError(cs.Prepare())
+
+	select {
+	case <-time.After(time.Second * 10):
+		t.NoError(errors.Errorf("timeout to wait to be finished"))
+	case <-finishedChan:
+	}
+
+	t.Equal(height+2, t.LastManifest(other.Database()).Height())
+}
+
+func (t *testSnapshot) TestImportNotEmpty() {
+	_, b := t.export(base.Height(3))
+
+	_, err := t.importSnapshot(t.Locals(1)[0], b)
+	t.Error(err)
+	t.Contains(err.Error(), "database is not empty")
+}
+
+func (t *testSnapshot) TestImportUnknownSuffrage() {
+	_, b := t.export(base.Height(3))
+
+	other := t.emptyLocal()
+
+	_, err := ImportSnapshot(
+		context.Background(),
+		bytes.NewReader(b),
+		other.Database(),
+		other.Blockdata(),
+		other.Policy(),
+		t.Suffrage(other, other),
+	)
+	t.Error(err)
+	t.Contains(err.Error(), "invalid accept voteproof of snapshot")
+}
+
+func (t *testSnapshot) TestImportMissingManifest() {
+	_, b := t.export(base.Height(3))
+
+	lines := t.lines(b)
+	lines = append(lines[:2], lines[3:]...)
+
+	other := t.emptyLocal()
+
+	_, err := t.importSnapshot(other, t.compress(lines))
+	t.Error(err)
+	t.Contains(err.Error(), "unexpected manifest height")
+
+	_, found, err := other.Database().LastManifest()
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testSnapshot) TestImportWrongState() {
+	_, b := t.export(base.Height(3))
+
+	lines := t.lines(b)
+
+	var ss SnapshotStateV0
+	t.NoError(encoder.Decode(lines[len(lines)-1], t.JSONEnc, &ss))
+
+	v, err := state.NewBytesValue(util.UUID().Bytes())
+	t.NoError(err)
+
+	st, err := ss.State().SetValue(v)
+	t.NoError(err)
+	st, err = st.SetHash(st.GenerateHash())
+	t.NoError(err)
+
+	wrong, err := t.JSONEnc.Marshal(NewSnapshotStateV0(st, ss.Proof()))
+	t.NoError(err)
+	lines[len(lines)-1] = append(wrong, '\n')
+
+	other := t.emptyLocal()
+
+	_, err = t.importSnapshot(other, t.compress(lines))
+	t.Error(err)
+	t.Contains(err.Error(), "failed to verify state")
+
+	_, found, err := other.Database().State(ss.State().Key())
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testSnapshot) TestImportMissingState() {
+	_, b := t.export(base.Height(3))
+
+	lines := t.lines(b)
+
+	other := t.emptyLocal()
+
+	_, err := t.importSnapshot(other, t.compress(lines[:len(lines)-1]))
+	t.Error(err)
+	t.Contains(err.Error(), "not enough states")
+}
+
+func (t *testSnapshot) TestImportLowerThresholdRatio() {
+	_, b := t.export(base.Height(3))
+
+	other := t.emptyLocal()
+	_, err := other.Policy().SetThresholdRatio(base.ThresholdRatio(68))
+	t.NoError(err)
+
+	_, err = t.importSnapshot(other, b)
+	t.Error(err)
+	t.Contains(err.Error(), "lower than policy")
+}
+
+func (t *testSnapshot) TestImportStateHigherThanBlock() {
+	_, b := t.export(base.Height(3))
+
+	lines := t.lines(b)
+
+	var ss SnapshotStateV0
+	t.NoError(encoder.Decode(lines[len(lines)-1], t.JSONEnc, &ss))
+
+	higher, err := t.JSONEnc.Marshal(NewSnapshotStateV0(ss.State().SetHeight(base.Height(4)), ss.Proof()))
+	t.NoError(err)
+	lines[len(lines)-1] = append(higher, '\n')
+
+	other := t.emptyLocal()
+
+	_, err = t.importSnapshot(other, t.compress(lines))
+	t.Error(err)
+	t.Contains(err.Error(), "higher than snapshot block")
+}
+
+func (t *testSnapshot) TestImportMissingBlockState() {
+	// NOTE genesis block has states
+	sn, b := t.export(base.GenesisHeight)
+
+	lines := t.lines(b)
+	t.Equal(1+len(t.ops), len(lines))
+
+	// NOTE the number of states in header is also changed
+	header, err := t.JSONEnc.Marshal(NewSnapshotV0(sn.Block(), sn.From(), sn.States()-1))
+	t.NoError(err)
+	lines[0] = append(header, '\n')
+
+	other := t.emptyLocal()
+
+	_, err = t.importSnapshot(other, t.compress(lines[:len(lines)-1]))
+	t.Error(err)
+	t.Contains(err.Error(), "not enough states of snapshot block")
+}
+
+type testWeightsStateSuffrage struct {
+	testWeightedSuffrage
+	genesis base.SuffrageWeights
+}
+
+func (testWeightsStateSuffrage) StateKey() string {
+	return "suffrage-weights"
+}
+
+func (sf testWeightsStateSuffrage) Genesis() base.SuffrageWeights {
+	return sf.genesis
+}
+
+func (t *testSnapshot) TestImportWeightedSuffrage() {
+	sn, b := t.export(base.Height(3))
+
+	// NOTE the accept voteproof of snapshot block has the weights
+	avp, ok := sn.Block().ConsensusInfo().ACCEPTVoteproof().(base.VoteproofV0)
+	t.True(ok)
+	avp = *avp.SetWeights([]uint{1})
+
+	blk, ok := sn.Block().(block.BlockV0).SetACCEPTVoteproof(avp).(block.Block)
+	t.True(ok)
+
+	lines := t.lines(b)
+	header, err := t.JSONEnc.Marshal(NewSnapshotV0(blk, sn.From(), sn.States()))
+	t.NoError(err)
+	lines[0] = append(header, '\n')
+
+	newSuffrage := func(genesis base.SuffrageWeights) base.Suffrage {
+		return testWeightsStateSuffrage{
+			testWeightedSuffrage: testWeightedSuffrage{
+				Suffrage: t.Suffrage(t.local, t.local),
+				weights: func(height base.Height) (base.SuffrageWeights, error) {
+					// NOTE the weights of empty database are unknown
+					return base.SuffrageWeights{}, util.NotFoundError.Errorf("suffrage weights of height, %d not found", height)
+				},
+			},
+			genesis: genesis,
+		}
+	}
+
+	importSnapshot := func(suffrage base.Suffrage) (*Local, error) {
+		other := t.emptyLocal()
+
+		_, err := ImportSnapshot(
+			context.Background(),
+			bytes.NewReader(t.compress(lines)),
+			other.Database(),
+			other.Blockdata(),
+			other.Policy(),
+			suffrage,
+		)
+
+		return other, err
+	}
+
+	{ // NOTE the weights state is not in snapshot, so the genesis weights are used
+		other, err := importSnapshot(newSuffrage(base.NewSuffrageWeights(map[base.Address]uint{
+			t.local.Node().Address(): 1,
+		})))
+		t.NoError(err)
+		t.Equal(base.Height(3), t.LastManifest(other.Database()).Height())
+	}
+
+	{ // NOTE different weight
+		other, err := importSnapshot(newSuffrage(base.NewSuffrageWeights(map[base.Address]uint{
+			t.local.Node().Address(): 2,
+		})))
+		t.Error(err)
+		t.Contains(err.Error(), "invalid accept voteproof of snapshot")
+
+		_, found, err := other.Database().LastManifest()
+		t.NoError(err)
+		t.False(found)
+	}
+
+	{ // NOTE unknown node
+		other, err := importSnapshot(newSuffrage(base.NewSuffrageWeights(map[base.Address]uint{
+			base.RandomStringAddress(): 1,
+		})))
+		t.Error(err)
+		t.Contains(err.Error(), "invalid accept voteproof of snapshot")
+
+		_, found, err := other.Database().LastManifest()
+		t.NoError(err)
+		t.False(found)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	suite.Run(t, new(testSnapshot))
+}
@@ -900,7 +900,7 @@ func (cs *GeneralSyncer) fetchManifests(ch network.Channel, heights []base.Heigh
 		for i := range maps {
 			bd := maps[i]
 			if err := wk.NewJob(func(ctx context.Context, _ uint64) error {
-				r, err := ch.Blockdata(ctx, bd.Manifest())
+				r, err := network.FetchBlockdata(ctx, ch, bd.Manifest())
 				if err != nil {
 					return err
 				}
@@ -1469,17 +1469,9 @@ func (cs *GeneralSyncer) fetchBlockdata(
 	item block.BlockdataMapItem,
 	ss blockdata.Session,
 ) (io.ReadSeeker, error) {
-	var r io.ReadCloser
-	if block.IsLocalBlockdataItem(item.URL()) {
-		i, err := ch.Blockdata(cs.lifeCtx, item)
-		if err != nil {
-			return nil, err
-		}
-		r = i
-	} else if i, err := network.FetchBlockdataFromRemote(cs.lifeCtx, item); err != nil {
+	r, err := network.FetchBlockdata(cs.lifeCtx, ch, item)
+	if err != nil {
 		return nil, err
-	} else {
-		r = i
 	}
 
 	defer func() {
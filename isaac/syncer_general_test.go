@@ -231,7 +231,7 @@ func (t *testGeneralSyncer) TestFetchBlocksButSomeNodesFailed() {
 
 	t.SetupNodes(local, []*Local{local, rn0, rn1, rn2})
 
-	_ = local.Policy().SetThresholdRatio(base.ThresholdRatio(100))
+	_, _ = local.Policy().SetThresholdRatio(base.ThresholdRatio(100))
 
 	bm := t.LastManifest(local.Database())
 	baseHeight := bm.Height()
@@ -283,7 +283,7 @@ func (t *testGeneralSyncer) TestFetchBlocksButAllNodesFailed() {
 
 	t.SetupNodes(local, []*Local{local, rn0, rn1, rn2})
 
-	_ = local.Policy().SetThresholdRatio(base.ThresholdRatio(100))
+	_, _ = local.Policy().SetThresholdRatio(base.ThresholdRatio(100))
 
 	bm := t.LastManifest(local.Database())
 	baseHeight := bm.Height()
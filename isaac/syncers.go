@@ -119,6 +119,8 @@ func (sy *Syncers) Add(to base.Height, sourceNodes []base.Node) (bool, error) {
 	sy.targetHeight = to
 	sy.mergeSourceNodes(sourceNodes)
 
+	metricSyncerTargetHeight.WithLabelValues().Set(float64(to.Int64()))
+
 	if !isFinished {
 		l.Debug().Msg("target height updated")
 
@@ -351,6 +353,9 @@ func (sy *Syncers) stateChangedSaved(ctx SyncerStateChangedContext) error {
 		}
 	}
 
+	metricSyncerSyncedHeight.WithLabelValues().Set(float64(syncer.HeightTo().Int64()))
+	metricSyncerSavedBlocks.WithLabelValues().Add(float64(len(ctx.Blocks())))
+
 	sy.whenBlockSaved(ctx.Blocks())
 
 	if sy.isFinished() {
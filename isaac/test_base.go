@@ -5,7 +5,9 @@ package isaac
 
 import (
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -196,6 +198,47 @@ func (t *BaseTest) Locals(n int) []*Local {
 	return ls
 }
 
+// CloneLocals creates the new locals, which have the same nodes, database and
+// block data with the given locals, but the different channels.
+func (t *BaseTest) CloneLocals(ls []*Local) []*Local {
+	nls := make([]*Local, len(ls))
+	for i := range ls {
+		l := ls[i]
+
+		lst := t.Database(t.Encs, t.JSONEnc)
+		t.NoError(lst.Copy(l.Database()))
+
+		root, err := os.MkdirTemp(t.Root, "localfs-")
+		t.NoError(err)
+		t.NoError(copyDirectory(l.Blockdata().(*localfs.Blockdata).Root(), root))
+
+		blockdata := localfs.NewBlockdata(root, t.JSONEnc)
+		t.NoError(blockdata.Initialize())
+
+		ch := channetwork.RandomChannel(util.UUID().String())
+
+		local, err := NewLocal(lst, blockdata, l.Node(), ch, TestNetworkID)
+		t.NoError(err)
+		t.NoError(local.Initialize())
+
+		nls[i] = local
+	}
+
+	for _, l := range nls {
+		for _, r := range nls {
+			if l.Node().Address().Equal(r.Node().Address()) {
+				continue
+			}
+
+			t.NoError(l.Nodes().Add(r.Node(), r.Channel()))
+		}
+	}
+
+	t.ls = append(t.ls, nls...)
+
+	return nls
+}
+
 func (t *BaseTest) EmptyLocal() *Local {
 	lst := t.Database(nil, nil)
 	uid := util.UUID().String()
@@ -540,3 +583,28 @@ func (t *BaseTest) Ballotbox(suffrage base.Suffrage, policy *LocalPolicy) *Ballo
 		},
 	)
 }
+
+func copyDirectory(source, target string) error {
+	return filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+
+		n := filepath.Join(target, rel)
+		if d.IsDir() {
+			return os.MkdirAll(n, localfs.DefaultDirectoryPermission)
+		}
+
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(n, b, localfs.DefaultFilePermission)
+	})
+}
@@ -1,8 +1,10 @@
 package isaac
 
 import (
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
@@ -37,10 +39,21 @@ func (vc *VoteProofChecker) IsValid() (bool, error) {
 	return true, nil
 }
 
+// NodeIsInSuffrage checks the voters of voteproof are inside suffrage. If the
+// suffrage weights of voteproof height are not yet known, the current suffrage
+// is used.
 func (vc *VoteProofChecker) NodeIsInSuffrage() (bool, error) {
+	isInside := vc.suffrage.IsInside
+	switch sw, found, err := suffrageWeights(vc.suffrage, vc.voteproof.Height()); {
+	case err != nil:
+		return false, err
+	case found:
+		isInside = sw.Exists
+	}
+
 	for i := range vc.voteproof.Votes() {
 		nf := vc.voteproof.Votes()[i]
-		if !vc.suffrage.IsInside(nf.FactSign().Node()) {
+		if !isInside(nf.FactSign().Node()) {
 			vc.Log().Debug().Stringer("node", nf.FactSign().Node()).Msg("voteproof has the vote from unknown node")
 
 			return false, nil
@@ -63,3 +76,132 @@ func (vc *VoteProofChecker) CheckThreshold() (bool, error) {
 
 	return true, nil
 }
+
+// CheckWeights checks the weights of voteproof is same with the suffrage
+// weights of the voteproof height. If suffrage is not base.WeightedSuffrage,
+// voteproof should not have weights.
+func (vc *VoteProofChecker) CheckWeights() (bool, error) {
+	var weights []uint
+	if i, ok := vc.voteproof.(interface{ Weights() []uint }); ok {
+		weights = i.Weights()
+	}
+
+	ws, ok := vc.suffrage.(base.WeightedSuffrage)
+	if !ok {
+		if len(weights) > 0 {
+			vc.Log().Debug().Msg("voteproof has weights, but suffrage is not weighted")
+
+			return false, nil
+		}
+
+		return true, nil
+	}
+
+	sw, err := ws.Weights(vc.voteproof.Height())
+	switch {
+	case err == nil:
+	case errors.Is(err, util.NotFoundError):
+		// NOTE the weights of the height, which is higher than the next
+		// height of local, are not yet known; the voteproof of higher height
+		// should be accepted to move to syncing.
+		vc.Log().Debug().Err(err).Msg("suffrage weights of voteproof height not yet known; weights not checked")
+
+		return true, nil
+	default:
+		return false, errors.Wrap(err, "failed to get suffrage weights")
+	}
+
+	suffrages := vc.voteproof.Suffrages()
+	if len(suffrages) != len(weights) || len(suffrages) != len(sw.Nodes()) {
+		vc.Log().Debug().Interface("weights", weights).Msg("voteproof has different weights")
+
+		return false, nil
+	}
+
+	for i := range suffrages {
+		if sw.Weight(suffrages[i]) != weights[i] {
+			vc.Log().Debug().
+				Stringer("node", suffrages[i]).
+				Uint("weight", weights[i]).
+				Uint("expected", sw.Weight(suffrages[i])).
+				Msg("voteproof has different weight")
+
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// CheckThresholdNotLower checks the threshold ratio of voteproof is not lower
+// than the local policy. The threshold ratio of policy can be changed after
+// the block was stored, so unlike CheckThreshold, the voteproof of the stored
+// block does not need to have the same threshold ratio.
+func (vc *VoteProofChecker) CheckThresholdNotLower() (bool, error) {
+	tr := vc.policy.ThresholdRatio()
+	if vc.voteproof.ThresholdRatio() < tr {
+		vc.Log().Debug().
+			Interface("threshold_ratio", vc.voteproof.ThresholdRatio()).
+			Interface("expected", tr).
+			Msg("voteproof has lower threshold ratio")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Check runs all the checks of VoteProofChecker for the voteproof, which does
+// not come thru the ballotbox, like the voteproof fetched from the other node.
+// Unlike util.Checker, false is not allowed.
+func (vc *VoteProofChecker) Check() error {
+	return vc.check(
+		vc.IsValid,
+		vc.NodeIsInSuffrage,
+		vc.CheckThreshold,
+		vc.CheckWeights,
+	)
+}
+
+// CheckStored is similar with Check, but it is for the voteproof of the
+// already stored block, like the ACCEPT voteproof of BlockdataMap; the
+// threshold ratio is checked by CheckThresholdNotLower.
+func (vc *VoteProofChecker) CheckStored() error {
+	return vc.check(
+		vc.IsValid,
+		vc.NodeIsInSuffrage,
+		vc.CheckThresholdNotLower,
+		vc.CheckWeights,
+	)
+}
+
+func (*VoteProofChecker) check(fs ...util.CheckerFunc) error {
+	for _, f := range fs {
+		switch ok, err := f(); {
+		case err != nil:
+			return errors.Wrap(err, "invalid voteproof")
+		case !ok:
+			return errors.Errorf("invalid voteproof")
+		}
+	}
+
+	return nil
+}
+
+// suffrageWeights returns the weights of height, if suffrage is
+// base.WeightedSuffrage. If suffrage is not base.WeightedSuffrage or the
+// weights of height are not yet known, found is false.
+func suffrageWeights(suffrage base.Suffrage, height base.Height) (base.SuffrageWeights, bool, error) {
+	ws, ok := suffrage.(base.WeightedSuffrage)
+	if !ok {
+		return base.SuffrageWeights{}, false, nil
+	}
+
+	switch sw, err := ws.Weights(height); {
+	case err == nil:
+		return sw, true, nil
+	case errors.Is(err, util.NotFoundError):
+		return base.SuffrageWeights{}, false, nil
+	default:
+		return base.SuffrageWeights{}, false, err
+	}
+}
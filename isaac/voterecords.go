@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
 
@@ -24,9 +25,11 @@ var (
 		vrs.facts = nil
 		vrs.votes = nil
 		vrs.ballots = nil
+		vrs.equivocations = nil
 		vrs.voteproof = base.VoteproofV0{}
 		vrs.threshold = base.Threshold{}
 		vrs.set = nil
+		vrs.weights = nil
 
 		voteRecordsPool.Put(vrs)
 	}
@@ -34,12 +37,14 @@ var (
 
 type VoteRecords struct {
 	sync.RWMutex
-	facts     map[string]base.BallotFact
-	votes     map[string]valuehash.Hash // {node Address: fact hash}
-	ballots   map[string]base.Ballot    // {node Address: ballot}
-	voteproof base.VoteproofV0
-	threshold base.Threshold
-	set       []string
+	facts         map[string]base.BallotFact
+	votes         map[string]valuehash.Hash // {node Address: fact hash}
+	ballots       map[string]base.Ballot    // {node Address: ballot}
+	equivocations map[string]struct{}       // {node Address}; the nodes, which voted twice
+	voteproof     base.VoteproofV0
+	threshold     base.Threshold
+	set           []string
+	weights       map[string]uint // {node Address: weight}; if nil, same weight
 }
 
 func NewVoteRecords(
@@ -54,6 +59,7 @@ func NewVoteRecords(
 	vrs.facts = map[string]base.BallotFact{}
 	vrs.votes = map[string]valuehash.Hash{}
 	vrs.ballots = map[string]base.Ballot{}
+	vrs.equivocations = map[string]struct{}{}
 	vrs.voteproof = base.NewVoteproofV0(
 		height,
 		round,
@@ -63,6 +69,23 @@ func NewVoteRecords(
 	)
 	vrs.threshold = threshold
 	vrs.set = nil
+	vrs.weights = nil
+
+	return vrs
+}
+
+// NewWeightedVoteRecords counts the votes by the weight of node. The threshold
+// should be based on the total weights, SuffrageWeights.Total().
+func NewWeightedVoteRecords(
+	height base.Height,
+	round base.Round,
+	stage base.Stage,
+	sw base.SuffrageWeights,
+	threshold base.Threshold,
+) *VoteRecords {
+	vrs := NewVoteRecords(height, round, stage, sw.Nodes(), threshold)
+	_ = vrs.voteproof.SetWeights(sw.Weights())
+	vrs.weights = sw.Map()
 
 	return vrs
 }
@@ -98,12 +121,46 @@ func (*VoteRecords) sanitizeHash(h valuehash.Hash) valuehash.Hash {
 // Vote votes by Ballot and keep track the vote records. If getting result is
 // done, Voteproof will not be updated.
 func (vrs *VoteRecords) Vote(blt base.Ballot) base.Voteproof {
+	vp, _, _ := vrs.voteWithEquivocation(blt)
+
+	return vp
+}
+
+// voteWithEquivocation votes like Vote. If the node of ballot already voted
+// with the different ballot fact, the evidence of double vote is returned;
+// the second vote is not counted and the evidence is returned only once for
+// each node.
+func (vrs *VoteRecords) voteWithEquivocation(blt base.Ballot) (base.Voteproof, base.EquivocationV0, bool) {
 	vrs.Lock()
 	defer vrs.Unlock()
 
+	ev, found := vrs.checkEquivocation(blt)
+
 	vrs.voteproof = vrs.vote(blt)
 
-	return vrs.voteproof
+	return vrs.voteproof, ev, found
+}
+
+func (vrs *VoteRecords) checkEquivocation(blt base.Ballot) (base.EquivocationV0, bool) {
+	n := blt.FactSign().Node().String()
+
+	first, found := vrs.ballots[n]
+	if !found {
+		return base.EquivocationV0{}, false
+	}
+
+	if _, found := vrs.equivocations[n]; found {
+		return base.EquivocationV0{}, false
+	}
+
+	ev, found := base.NewEquivocationV0FromBallots(first, blt, localtime.UTCNow())
+	if !found {
+		return base.EquivocationV0{}, false
+	}
+
+	vrs.equivocations[n] = struct{}{}
+
+	return ev, true
 }
 
 func (vrs *VoteRecords) vote(blt base.Ballot) base.VoteproofV0 {
@@ -125,15 +182,33 @@ func (vrs *VoteRecords) vote(blt base.Ballot) base.VoteproofV0 {
 
 	vrs.set = append(vrs.set, vrs.sanitizeHash(blt.RawFact().Hash()).String())
 
-	if len(vrs.set) < int(vrs.threshold.Threshold) {
-		return *voteproof
-	}
+	var result base.VoteResultType
+	var key string
+	if vrs.weights == nil {
+		if len(vrs.set) < int(vrs.threshold.Threshold) {
+			return *voteproof
+		}
 
-	result, key := base.FindMajorityFromSlice(
-		vrs.threshold.Total,
-		vrs.threshold.Threshold,
-		vrs.set,
-	)
+		result, key = base.FindMajorityFromSlice(
+			vrs.threshold.Total,
+			vrs.threshold.Threshold,
+			vrs.set,
+		)
+	} else {
+		var voted uint
+		counts := map[string]uint{}
+		for n := range vrs.votes {
+			w := vrs.weights[n]
+			voted += w
+			counts[vrs.votes[n].String()] += w
+		}
+
+		if voted < vrs.threshold.Threshold {
+			return *voteproof
+		}
+
+		result, key = base.FindMajorityFromCounts(vrs.threshold.Total, vrs.threshold.Threshold, counts)
+	}
 
 	if result == base.VoteResultMajority {
 		_ = voteproof.SetMajority(vrs.facts[key])
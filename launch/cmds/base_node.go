@@ -3,6 +3,7 @@ package cmds
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/pm"
 	"github.com/spikeekips/mitum/launch/process"
@@ -52,3 +53,28 @@ func (cmd *BaseRunCommand) prepare() error {
 
 	return nil
 }
+
+// newStorageBaseRunCommand prepares the processes to handle only the database
+// and block data; consensus states and network are disabled.
+func newStorageBaseRunCommand(name string) *BaseRunCommand {
+	cmd := NewBaseRunCommand(false, name)
+
+	ps := cmd.Processes()
+	if ps == nil {
+		panic(errors.Errorf("processes not prepared"))
+	}
+
+	for _, i := range []pm.Process{
+		process.ProcessorConsensusStates,
+		process.ProcessorNetwork,
+		process.ProcessorProposalProcessor,
+	} {
+		if err := ps.AddProcess(pm.NewDisabledProcess(i), true); err != nil {
+			panic(err)
+		}
+	}
+
+	_ = cmd.SetProcesses(ps)
+
+	return cmd
+}
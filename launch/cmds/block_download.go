@@ -421,11 +421,7 @@ func (cmd *BlockDownloadCommand) requestBlockdata(item block.BlockdataMapItem) (
 	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
 	defer cancel()
 
-	if block.IsLocalBlockdataItem(item.URL()) {
-		return cmd.channel.Blockdata(ctx, item)
-	}
-
-	return network.FetchBlockdataFromRemote(ctx, item)
+	return network.FetchBlockdata(ctx, cmd.channel, item)
 }
 
 func getItemBlockdataMap(m block.BlockdataMap, dataType string) (block.BlockdataMapItem, error) {
@@ -0,0 +1,243 @@
+package cmds
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+)
+
+type BlockdataArchiveCommand struct {
+	Export BlockdataArchiveExportCommand `cmd:"" help:"export blockdata archive"`
+	Import BlockdataArchiveImportCommand `cmd:"" help:"import blockdata archive"`
+}
+
+func NewBlockdataArchiveCommand() BlockdataArchiveCommand {
+	return BlockdataArchiveCommand{
+		Export: NewBlockdataArchiveExportCommand(),
+		Import: NewBlockdataArchiveImportCommand(),
+	}
+}
+
+type BlockdataArchiveExportCommand struct {
+	*BaseRunCommand
+	Output string `arg:"" name:"output" help:"archive file" required:"true"`
+	From   int64  `name:"from" help:"from height; default is pre-genesis height" default:"-1"`
+	To     int64  `name:"to" help:"to height; default is last height" default:"-2"`
+}
+
+func NewBlockdataArchiveExportCommand() BlockdataArchiveExportCommand {
+	return BlockdataArchiveExportCommand{
+		BaseRunCommand: newStorageBaseRunCommand("blockdata-archive-export"),
+	}
+}
+
+func (cmd *BlockdataArchiveExportCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+	defer cmd.Done()
+
+	if err := cmd.prepare(); err != nil {
+		return err
+	}
+
+	ps := cmd.Processes()
+	if err := ps.Run(); err != nil {
+		return err
+	}
+
+	var db storage.Database
+	if err := process.LoadDatabaseContextValue(ps.Context(), &db); err != nil {
+		return err
+	}
+
+	var bd *localfs.Blockdata
+	if err := util.LoadFromContextValue(ps.Context(), process.ContextValueBlockdata, &bd); err != nil {
+		return err
+	}
+
+	from, to := base.Height(cmd.From), base.Height(cmd.To)
+	if to <= base.NilHeight {
+		switch m, found, err := db.LastManifest(); {
+		case err != nil:
+			return err
+		case !found:
+			return util.NotFoundError.Errorf("empty blocks")
+		default:
+			to = m.Height()
+		}
+	}
+
+	if from > to {
+		return errors.Errorf("from height is higher than to; %d > %d", from, to)
+	}
+
+	bdms := make([]block.BlockdataMap, (to - from + 1).Int64())
+	for h := from; h <= to; h++ {
+		switch i, found, err := db.BlockdataMap(h); {
+		case err != nil:
+			return err
+		case !found:
+			return util.NotFoundError.Errorf("block data map, %d not found", h)
+		default:
+			bdms[h-from] = i
+		}
+	}
+
+	s := time.Now()
+	cmd.Log().Info().Interface("from_to", []base.Height{from, to}).Str("output", cmd.Output).
+		Msg("trying to export blockdata archive")
+
+	f, err := os.OpenFile(filepath.Clean(cmd.Output), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create archive file")
+	}
+
+	index, err := localfs.WriteArchive(f, bd, bdms)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		_ = os.Remove(cmd.Output)
+
+		return errors.Wrap(err, "failed to export blockdata archive")
+	}
+
+	cmd.Log().Info().
+		Interface("from_to", []base.Height{index.From, index.To}).
+		Int("items", len(index.Items)).
+		Dur("elapsed", time.Since(s)).
+		Msg("blockdata archive exported")
+
+	return nil
+}
+
+// BlockdataArchiveImportCommand extracts the blockdata archive into the block
+// data and restores it into the database like RestoreCommand does.
+type BlockdataArchiveImportCommand struct {
+	*BaseRunCommand
+	Archive     string `arg:"" name:"archive" help:"archive file" required:"true"`
+	Concurrency uint64 `help:"how many blocks are handled at same time default: 10" default:"10"`
+	Dryrun      bool   `help:"just check archive and blockdata default: false" default:"false"`
+	restore     RestoreCommand
+}
+
+func NewBlockdataArchiveImportCommand() BlockdataArchiveImportCommand {
+	restore := NewRestoreCommand()
+
+	cmd := NewBaseRunCommand(false, "blockdata-archive-import")
+	_ = cmd.SetProcesses(restore.Processes())
+	restore.BaseRunCommand = cmd
+
+	return BlockdataArchiveImportCommand{
+		BaseRunCommand: restore.BaseRunCommand,
+		restore:        restore,
+	}
+}
+
+func (cmd *BlockdataArchiveImportCommand) Run(version util.Version) error {
+	s := time.Now()
+
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+	defer cmd.Done()
+	defer func() {
+		<-time.After(time.Second * 1)
+	}()
+
+	cmd.restore.Concurrency = cmd.Concurrency
+	cmd.restore.Dryrun = cmd.Dryrun
+
+	cmd.Log().Info().
+		Str("archive", cmd.Archive).
+		Bool("dryrun", cmd.Dryrun).
+		Uint64("concurrency", cmd.Concurrency).
+		Msg("started")
+
+	if err := cmd.restore.prepare(); err != nil {
+		return err
+	}
+
+	ps := cmd.Processes()
+	_ = ps.SetContext(context.WithValue(ps.ContextSource(), process.ContextValueGenesisBlockForceCreate, false))
+	_ = cmd.SetProcesses(ps)
+
+	if err := ps.Run(); err != nil {
+		return err
+	}
+
+	if err := cmd.restore.load(ps.Context()); err != nil {
+		return err
+	}
+
+	index, err := cmd.extract()
+	if err != nil {
+		return err
+	}
+
+	// NOTE the extracted blockdata is removed when it is not restored
+	if err := cmd.checkArchive(index); err != nil || cmd.Dryrun {
+		for h := index.From; h <= index.To; h++ {
+			_ = cmd.restore.blockdata.RemoveAll(h)
+		}
+
+		return err
+	}
+
+	if err := cmd.restore.restoreBlockdata(); err != nil {
+		return err
+	}
+
+	cmd.Log().Info().Dur("elapsed", time.Since(s)).Msg("blockdata archive imported")
+
+	return nil
+}
+
+func (cmd *BlockdataArchiveImportCommand) extract() (localfs.ArchiveIndex, error) {
+	s := time.Now()
+
+	f, err := os.Open(filepath.Clean(cmd.Archive))
+	if err != nil {
+		return localfs.ArchiveIndex{}, errors.Wrap(err, "failed to open archive file")
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	index, err := localfs.ReadArchive(f, cmd.restore.blockdata)
+	if err != nil {
+		return index, errors.Wrap(err, "failed to extract blockdata archive")
+	}
+
+	cmd.Log().Debug().
+		Interface("from_to", []base.Height{index.From, index.To}).
+		Dur("elapsed", time.Since(s)).
+		Msg("blockdata archive extracted")
+
+	return index, nil
+}
+
+func (cmd *BlockdataArchiveImportCommand) checkArchive(index localfs.ArchiveIndex) error {
+	last, _, err := cmd.restore.checkLastBlockInDatabase()
+	if err != nil {
+		return err
+	}
+
+	if index.From != last+1 {
+		return errors.Errorf("archive should start from next of last block; %d != %d", index.From, last+1)
+	}
+
+	return cmd.restore.checkBlockdata()
+}
@@ -1,19 +1,17 @@
 package cmds
 
 import (
-	"context"
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
-	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/storage/blockdata/localfs"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
 )
 
 type BlockdataVerifyCommand struct {
@@ -35,7 +33,12 @@ func (cmd *BlockdataVerifyCommand) Run(version util.Version) error {
 
 	cmd.Log().Debug().Str("path", cmd.Path).Msg("trying to verify blockdata")
 
-	return cmd.verify()
+	err := cmd.verify()
+	if e := cmd.writeReport(err); e != nil {
+		return e
+	}
+
+	return err
 }
 
 func (cmd *BlockdataVerifyCommand) Initialize(flags interface{}, version util.Version) error {
@@ -62,30 +65,108 @@ func (cmd *BlockdataVerifyCommand) verify() error {
 		return nil
 	}
 
-	var hasError bool
-	if err := cmd.checkAllManifests(cmd.loadManifest); err != nil {
-		hasError = true
+	if err := cmd.prepareCheckpoint(cmd.checkpointTarget(), cmd.loadManifest); err != nil {
+		return err
 	}
 
-	if err := cmd.checkAllBlockFiles(); err != nil {
+	var hasError bool
+	if err := cmd.verifyHeights(cmd.loadManifest, cmd.checkHeight); err != nil {
 		hasError = true
 	}
 
-	if err := cmd.checkBlocks(); err != nil {
-		hasError = true
+	if !hasError && len(cmd.damagedHeights()) < 1 {
+		cmd.Log().Debug().Msg("blockdata verified")
+
+		return nil
 	}
 
-	if hasError {
-		cmd.Log().Error().Msg("failed to verify blockdata")
-	} else {
-		cmd.Log().Debug().Msg("blockdata verified")
+	cmd.logDamaged()
+	cmd.Log().Error().Msg("failed to verify blockdata")
+
+	if cmd.Repair {
+		return cmd.repair()
 	}
 
 	return nil
 }
 
+func (cmd *BlockdataVerifyCommand) repair() error {
+	heights := cmd.damagedHeights()
+	if len(heights) < 1 {
+		return errors.Errorf("damaged blocks not found; nothing to repair")
+	}
+
+	var failed []int64
+	for i := range heights {
+		height := heights[i]
+
+		if err := cmd.repairHeight(height); err != nil {
+			cmd.Log().Error().Err(err).Int64("height", height.Int64()).Msg("failed to repair block data")
+
+			failed = append(failed, height.Int64())
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("failed to repair block data, %v", failed)
+	}
+
+	cmd.Log().Info().Int("blocks", len(heights)).
+		Msg("block data repaired; database also should be repaired by database-verify")
+
+	return cmd.setRepaired(heights[0], cmd.loadManifest)
+}
+
+// repairHeight fetches the block data of height from the suffrage nodes. If
+// the manifest of next height is not damaged, the fetched block should be
+// it's previous block.
+func (cmd *BlockdataVerifyCommand) repairHeight(height base.Height) error {
+	var expected valuehash.Hash
+	if next := height + 1; next <= cmd.lastHeight {
+		cmd.damagedLock.Lock()
+		_, damaged := cmd.damaged[next]
+		cmd.damagedLock.Unlock()
+
+		if !damaged {
+			if m, err := cmd.loadManifest(next); err == nil {
+				expected = m.PreviousBlock()
+			}
+		}
+	}
+
+	bdm, ch, err := cmd.fetchBlockdataMap(cmd.bd.Writer(), height, expected)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = cmd.fetchBlock(cmd.bd, ch, bdm)
+
+	return err
+}
+
+// checkpointTarget returns the absolute blockdata path for checkpoint.
+func (cmd *BlockdataVerifyCommand) checkpointTarget() string {
+	if i, err := filepath.Abs(cmd.Path); err == nil {
+		return i
+	}
+
+	return cmd.Path
+}
+
+// checkLastHeight finds the last height of blockdata; the search starts from
+// the verified height of checkpoint if it exists.
 func (cmd *BlockdataVerifyCommand) checkLastHeight() error {
 	var height base.Height = base.PreGenesisHeight
+
+	switch cp, err := cmd.loadCheckpoint(); {
+	case err != nil:
+		return err
+	case cp != nil && cp.Verified > height:
+		if found, err := cmd.bd.Exists(cp.Verified); err == nil && found {
+			height = cp.Verified
+		}
+	}
+
 	for {
 		if found, err := cmd.bd.Exists(height); err != nil {
 			return errors.Wrapf(err, "failed to check blockdata of height, %d", height)
@@ -130,27 +211,16 @@ func (cmd *BlockdataVerifyCommand) loadManifest(height base.Height) (block.Manif
 	return manifest, nil
 }
 
-func (cmd *BlockdataVerifyCommand) checkBlocks() error {
-	wk := util.NewErrgroupWorker(context.Background(), 100)
-	defer wk.Close()
-
-	go func() {
-		defer wk.Done()
-
-		for i := base.PreGenesisHeight; i <= cmd.lastHeight; i++ {
-			height := i
+// checkHeight checks the block data files of height and the block loaded
+// from them.
+func (cmd *BlockdataVerifyCommand) checkHeight(height base.Height) error {
+	if err := cmd.checkBlockFiles(height); err != nil {
+		return err
+	}
 
-			if err := wk.NewJob(func(context.Context, uint64) error {
-				_, err := cmd.loadBlock(height)
+	_, err := cmd.loadBlock(height)
 
-				return err
-			}); err != nil {
-				return
-			}
-		}
-	}()
-
-	return wk.Wait()
+	return err
 }
 
 func (cmd *BlockdataVerifyCommand) loadBlock(height base.Height) (block.Block, error) {
@@ -171,27 +241,6 @@ func (cmd *BlockdataVerifyCommand) loadBlock(height base.Height) (block.Block, e
 	}
 }
 
-func (cmd *BlockdataVerifyCommand) checkAllBlockFiles() error {
-	wk := util.NewErrgroupWorker(context.Background(), 100)
-	defer wk.Close()
-
-	go func() {
-		defer wk.Done()
-
-		for i := base.PreGenesisHeight; i <= cmd.lastHeight; i++ {
-			height := i
-
-			if err := wk.NewJob(func(context.Context, uint64) error {
-				return cmd.checkBlockFiles(height)
-			}); err != nil {
-				return
-			}
-		}
-	}()
-
-	return wk.Wait()
-}
-
 func (cmd *BlockdataVerifyCommand) checkBlockFiles(height base.Height) error {
 	l := cmd.Log().With().Int64("height", height.Int64()).Logger()
 
@@ -223,30 +272,5 @@ func (cmd *BlockdataVerifyCommand) checkBlockFiles(height base.Height) error {
 }
 
 func (cmd *BlockdataVerifyCommand) checkBlockFile(height base.Height, dataType string) error {
-	g := filepath.Join(cmd.Path, localfs.HeightDirectory(height), fmt.Sprintf("%d-%s-*.jsonld.gz", height, dataType))
-
-	var f string
-	switch matches, err := filepath.Glob(g); {
-	case err != nil:
-		return storage.MergeStorageError(err)
-	case len(matches) < 1:
-		return util.NotFoundError.Errorf("block data, %q(%d) not found", dataType, height)
-	case len(matches) > 1:
-		return errors.Errorf("block data, %q(%d) multiple files found", dataType, height)
-	default:
-		f = matches[0]
-	}
-
-	_, _, checksum, err := localfs.ParseDataFileName(f)
-	if err != nil {
-		return err
-	}
-
-	if i, err := util.GenerateFileChecksum(f); err != nil {
-		return err
-	} else if checksum != i {
-		return errors.Errorf("file checksum does not match; %s != %s", checksum, i)
-	}
-
-	return nil
+	return checkLocalBlockFile(cmd.Path, height, dataType)
 }
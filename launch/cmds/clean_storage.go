@@ -77,7 +77,9 @@ func (cmd *CleanStorageCommand) Run(version util.Version) error {
 		return err
 	}
 
+	// NOTE the unmigrated database also can be cleaned
 	ps := cmd.Processes()
+	_ = ps.SetContext(context.WithValue(ps.ContextSource(), process.ContextValueSkipSchemaVersionCheck, true))
 	_ = cmd.SetProcesses(ps)
 
 	if cmd.dryrun {
@@ -2,6 +2,8 @@ package cmds
 
 import (
 	"context"
+	"net/url"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
@@ -12,6 +14,7 @@ import (
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
 	"github.com/spikeekips/mitum/util"
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 	"github.com/spikeekips/mitum/util/hint"
@@ -74,7 +77,12 @@ func (cmd *DatabaseVerifyCommand) Run(version util.Version) error {
 
 	cmd.Log().Debug().Str("uri", cmd.URI).Str("path", cmd.Path).Msg("trying to verify database")
 
-	return cmd.verify()
+	err := cmd.verify()
+	if e := cmd.writeReport(err); e != nil {
+		return e
+	}
+
+	return err
 }
 
 func (cmd *DatabaseVerifyCommand) Initialize(flags interface{}, version util.Version) error {
@@ -95,11 +103,208 @@ func (cmd *DatabaseVerifyCommand) Initialize(flags interface{}, version util.Ver
 
 func (cmd *DatabaseVerifyCommand) verify() error {
 	cmd.Log().Debug().Msg("verifying database")
-	if err := cmd.checkAllManifests(cmd.loadManifest); err != nil {
+
+	if err := cmd.prepareCheckpoint(cmd.checkpointTarget(), cmd.loadManifest); err != nil {
+		return err
+	}
+
+	err := cmd.verifyHeights(cmd.loadManifest, cmd.checkBlockdataMap)
+
+	if err == nil && len(cmd.damagedHeights()) < 1 {
+		cmd.Log().Info().Msg("database verified")
+
+		return nil
+	}
+
+	cmd.logDamaged()
+
+	switch {
+	case cmd.Repair:
+		return cmd.repair()
+	case err != nil:
+		return err
+	default:
+		return errors.Errorf("damaged blocks found")
+	}
+}
+
+// checkpointTarget returns the database uri without password and the absolute
+// blockdata path for checkpoint.
+func (cmd *DatabaseVerifyCommand) checkpointTarget() string {
+	uri := cmd.URI
+	if u, err := url.Parse(cmd.URI); err == nil {
+		uri = u.Redacted()
+	}
+
+	p := cmd.Path
+	if i, err := filepath.Abs(cmd.Path); err == nil {
+		p = i
+	}
+
+	return uri + " " + p
+}
+
+// checkBlockdataMap checks the BlockdataMap of height in database points the
+// manifest and it's local block data files exist.
+func (cmd *DatabaseVerifyCommand) checkBlockdataMap(height base.Height) error {
+	var bdm block.BlockdataMap
+	switch i, found, err := cmd.database.BlockdataMap(height); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("block data map, %d not found", height)
+	default:
+		bdm = i
+	}
+
+	switch m, found, err := cmd.database.ManifestByHeight(height); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("manifest, %d not found", height)
+	case !m.Hash().Equal(bdm.Block()):
+		return errors.Errorf("block data map does not match with manifest; %s != %s", bdm.Block(), m.Hash())
+	}
+
+	bd, ok := cmd.blockdata.(*localfs.Blockdata)
+	if !ok || !block.IsLocalBlockdataItem(bdm.Manifest().URL()) {
+		return nil
+	}
+
+	if i, ok := bdm.(block.BaseBlockdataMap); ok {
+		return i.Exists(bd.Root())
+	}
+
+	return nil
+}
+
+// repair rebuilds the database records from the first damaged height. Before
+// database is changed, the block data from the first damaged height are
+// checked and the damaged block data are fetched from the suffrage nodes.
+func (cmd *DatabaseVerifyCommand) repair() error {
+	heights := cmd.damagedHeights()
+	if len(heights) < 1 {
+		return errors.Errorf("damaged blocks not found; nothing to repair")
+	}
+
+	from := heights[0]
+
+	cmd.Log().Info().Int64("from", from.Int64()).Int64("to", cmd.lastHeight.Int64()).Msg("trying to repair database")
+
+	if err := cmd.repairBlockdata(from); err != nil {
+		return errors.Wrap(err, "failed to repair block data")
+	}
+
+	if err := cmd.database.CleanByHeight(from); err != nil {
+		return errors.Wrapf(err, "failed to clean database by height, %d", from)
+	}
+
+	for height := from; height <= cmd.lastHeight; height++ {
+		bdm, blk, err := localfs.LoadBlock(cmd.blockdata.(*localfs.Blockdata), height)
+		if err != nil {
+			return err
+		}
+
+		if err := cmd.saveBlock(blk, bdm); err != nil {
+			return errors.Wrapf(err, "failed to save block, %d", height)
+		}
+	}
+
+	if db, ok := cmd.database.(storage.LastBlockSaver); ok {
+		if err := db.SaveLastBlock(cmd.lastHeight); err != nil {
+			return err
+		}
+	}
+
+	cmd.Log().Info().Int64("from", from.Int64()).Int64("to", cmd.lastHeight.Int64()).Msg("database repaired")
+
+	return cmd.setRepaired(from, cmd.loadManifest)
+}
+
+// repairBlockdata checks the local block data from the given height and
+// replaces the damaged ones with the block data from the suffrage nodes. The
+// blocks should be chained from the previous block of given height.
+func (cmd *DatabaseVerifyCommand) repairBlockdata(from base.Height) error {
+	var prev block.Manifest
+	if from > base.PreGenesisHeight {
+		i, err := cmd.loadManifest(from - 1)
+		if err != nil {
+			return err
+		}
+		prev = i
+	}
+
+	for height := from; height <= cmd.lastHeight; height++ {
+		l := cmd.Log().With().Int64("height", height.Int64()).Logger()
+
+		blk, err := cmd.checkLocalBlock(height)
+		if err != nil {
+			l.Warn().Err(err).Msg("damaged block data found; will be fetched")
+
+			bdm, ch, err := cmd.fetchBlockdataMap(cmd.blockdata.Writer(), height, nil)
+			if err != nil {
+				return err
+			}
+
+			i, _, err := cmd.fetchBlock(cmd.blockdata, ch, bdm)
+			if err != nil {
+				return err
+			}
+			blk = i
+		}
+
+		if prev != nil && !blk.PreviousBlock().Equal(prev.Hash()) {
+			return errors.Errorf("block, %d is not chained with previous block; %s != %s",
+				height, blk.PreviousBlock(), prev.Hash())
+		}
+
+		prev = blk.Manifest()
+	}
+
+	return nil
+}
+
+func (cmd *DatabaseVerifyCommand) checkLocalBlock(height base.Height) (block.Block, error) {
+	bd, ok := cmd.blockdata.(*localfs.Blockdata)
+	if !ok {
+		return nil, errors.Errorf("only local block data can be repaired, not %T", cmd.blockdata)
+	}
+
+	for i := range block.Blockdata {
+		if err := checkLocalBlockFile(bd.Root(), height, block.Blockdata[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	_, blk, err := localfs.LoadBlock(bd, height)
+	if err != nil {
+		return nil, err
+	} else if err := blk.IsValid(cmd.networkID); err != nil {
+		return nil, err
+	}
+
+	return blk, nil
+}
+
+func (cmd *DatabaseVerifyCommand) saveBlock(blk block.Block, bdm block.BlockdataMap) error {
+	sst, err := cmd.database.NewSyncerSession()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = sst.Close()
+	}()
+
+	if err := sst.SetBlocks([]block.Block{blk}, []block.BlockdataMap{bdm}); err != nil {
+		return err
+	}
+
+	if err := sst.Commit(); err != nil {
 		return err
 	}
 
-	cmd.Log().Info().Msg("database verified")
+	cmd.Log().Debug().Int64("height", blk.Height().Int64()).Msg("block rebuilt")
 
 	return nil
 }
@@ -0,0 +1,83 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type OperationStatusCommand struct {
+	*BaseCommand
+	URL        *url.URL      `arg:"" name:"node url" help:"remote mitum url" required:"true"`
+	Fact       string        `arg:"" name:"fact" help:"operation fact hash" required:"true"`
+	Timeout    time.Duration `name:"timeout" help:"timeout; default is 5 seconds"`
+	TLSInscure bool          `name:"tls-insecure" help:"allow inseucre TLS connection; default is false"`
+}
+
+func NewOperationStatusCommand() OperationStatusCommand {
+	return OperationStatusCommand{
+		BaseCommand: NewBaseCommand("operation_status"),
+	}
+}
+
+func (cmd *OperationStatusCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+
+	if cmd.Timeout < 1 {
+		cmd.Timeout = time.Second * 5
+	}
+
+	fact := valuehash.NewBytesFromString(strings.TrimSpace(cmd.Fact))
+	if err := fact.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid fact hash")
+	}
+
+	cmd.Log().Debug().Interface("node_url", cmd.URL).Stringer("fact", fact).Msg("trying to get operation status")
+
+	encs := cmd.Encoders()
+	if encs == nil {
+		i, err := cmd.LoadEncoders(nil, nil)
+		if err != nil {
+			return err
+		}
+		encs = i
+	}
+
+	connInfo := network.NewHTTPConnInfo(network.NormalizeURL(cmd.URL), cmd.TLSInscure)
+	channel, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout)
+	if err != nil {
+		return err
+	}
+	cmd.Log().Debug().Msg("network channel loaded")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	so, found, err := channel.OperationStatus(ctx, fact)
+	switch {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("operation status, %q not found", fact)
+	}
+
+	if err := so.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid operation status")
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, jsonenc.ToString(so))
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+)
+
+type OperationsCommand struct {
+	Signer OperationsBySignerCommand `cmd:"" help:"query operations by signer"`
+	Hint   OperationsByHintCommand   `cmd:"" help:"query operations by hint type"`
+}
+
+func NewOperationsCommand() OperationsCommand {
+	return OperationsCommand{
+		Signer: NewOperationsBySignerCommand(),
+		Hint:   NewOperationsByHintCommand(),
+	}
+}
+
+type OperationsBySignerCommand struct {
+	*BaseCommand
+	URL        *url.URL      `arg:"" name:"node url" help:"remote mitum url" required:"true"`
+	Signer     string        `arg:"" name:"publickey" help:"publickey of signer" required:"true"`
+	Cursor     string        `name:"cursor" help:"next cursor of previous result"`
+	Limit      int64         `name:"limit" help:"maximum number of operations"`
+	Timeout    time.Duration `name:"timeout" help:"timeout; default is 5 seconds"`
+	TLSInscure bool          `name:"tls-insecure" help:"allow inseucre TLS connection; default is false"`
+}
+
+func NewOperationsBySignerCommand() OperationsBySignerCommand {
+	return OperationsBySignerCommand{
+		BaseCommand: NewBaseCommand("operations_by_signer"),
+	}
+}
+
+func (cmd *OperationsBySignerCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+
+	cursor, err := storage.ParseOperationCursor(cmd.Cursor)
+	if err != nil {
+		return errors.Wrap(err, "invalid cursor")
+	}
+
+	channel, encs, err := loadOperationsChannel(cmd.BaseCommand, cmd.URL, cmd.TLSInscure, &cmd.Timeout)
+	if err != nil {
+		return err
+	}
+
+	je, err := encs.Encoder(jsonenc.JSONEncoderType, "")
+	if err != nil {
+		return err
+	}
+
+	signer, err := key.DecodePublickeyFromString(strings.TrimSpace(cmd.Signer), je)
+	if err != nil {
+		return errors.Wrap(err, "invalid publickey")
+	}
+
+	cmd.Log().Debug().Interface("node_url", cmd.URL).Stringer("signer", signer).Stringer("cursor", cursor).
+		Msg("trying to get operations by signer")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	op, err := channel.OperationsBySigner(ctx, signer, cursor, cmd.Limit)
+	if err != nil {
+		return err
+	}
+
+	return printOperationsPage(op)
+}
+
+type OperationsByHintCommand struct {
+	*BaseCommand
+	URL        *url.URL      `arg:"" name:"node url" help:"remote mitum url" required:"true"`
+	Hint       string        `arg:"" name:"hint" help:"hint type of operation" required:"true"`
+	From       int64         `name:"from" help:"from height; default is pre-genesis height" default:"-1"`
+	To         int64         `name:"to" help:"to height; default is last height" default:"-2"`
+	Cursor     string        `name:"cursor" help:"next cursor of previous result"`
+	Limit      int64         `name:"limit" help:"maximum number of operations"`
+	Timeout    time.Duration `name:"timeout" help:"timeout; default is 5 seconds"`
+	TLSInscure bool          `name:"tls-insecure" help:"allow inseucre TLS connection; default is false"`
+}
+
+func NewOperationsByHintCommand() OperationsByHintCommand {
+	return OperationsByHintCommand{
+		BaseCommand: NewBaseCommand("operations_by_hint"),
+	}
+}
+
+func (cmd *OperationsByHintCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+
+	ht := hint.Type(strings.TrimSpace(cmd.Hint))
+	if err := ht.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid hint type")
+	}
+
+	from, to := base.Height(cmd.From), base.Height(cmd.To)
+	if to > base.NilHeight && from > to {
+		return errors.Errorf("from height is higher than to; %d > %d", from, to)
+	}
+
+	cursor, err := storage.ParseOperationCursor(cmd.Cursor)
+	if err != nil {
+		return errors.Wrap(err, "invalid cursor")
+	}
+
+	channel, _, err := loadOperationsChannel(cmd.BaseCommand, cmd.URL, cmd.TLSInscure, &cmd.Timeout)
+	if err != nil {
+		return err
+	}
+
+	cmd.Log().Debug().Interface("node_url", cmd.URL).Stringer("hint", ht).
+		Interface("from_to", []base.Height{from, to}).Stringer("cursor", cursor).
+		Msg("trying to get operations by hint")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	op, err := channel.OperationsByHint(ctx, ht, from, to, cursor, cmd.Limit)
+	if err != nil {
+		return err
+	}
+
+	return printOperationsPage(op)
+}
+
+func loadOperationsChannel(
+	cmd *BaseCommand,
+	u *url.URL,
+	tlsInsecure bool,
+	timeout *time.Duration,
+) (network.Channel, *encoder.Encoders, error) {
+	if *timeout < 1 {
+		*timeout = time.Second * 5
+	}
+
+	encs := cmd.Encoders()
+	if encs == nil {
+		i, err := cmd.LoadEncoders(nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		encs = i
+	}
+
+	connInfo := network.NewHTTPConnInfo(network.NormalizeURL(u), tlsInsecure)
+	channel, err := process.LoadNodeChannel(connInfo, encs, *timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd.Log().Debug().Msg("network channel loaded")
+
+	return channel, encs, nil
+}
+
+func printOperationsPage(op network.OperationsPageV0) error {
+	if err := op.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid operations")
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, jsonenc.ToString(op))
+
+	return nil
+}
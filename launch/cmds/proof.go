@@ -0,0 +1,148 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type baseProofCommand struct {
+	*BaseCommand
+	URL        *url.URL      `arg:"" name:"node url" help:"remote mitum url" required:"true"`
+	Height     int64         `arg:"" name:"height" help:"block height" required:"true"`
+	Timeout    time.Duration `name:"timeout" help:"timeout; default is 5 seconds"`
+	TLSInscure bool          `name:"tls-insecure" help:"allow inseucre TLS connection; default is false"`
+}
+
+func (cmd *baseProofCommand) prepare(i interface{}, version util.Version) (network.Channel, error) {
+	if err := cmd.Initialize(i, version); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize command")
+	}
+
+	if cmd.Timeout < 1 {
+		cmd.Timeout = time.Second * 5
+	}
+
+	if err := base.Height(cmd.Height).IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	encs := cmd.Encoders()
+	if encs == nil {
+		i, err := cmd.LoadEncoders(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		encs = i
+	}
+
+	connInfo := network.NewHTTPConnInfo(network.NormalizeURL(cmd.URL), cmd.TLSInscure)
+	channel, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Log().Debug().Msg("network channel loaded")
+
+	return channel, nil
+}
+
+type ProofOperationCommand struct {
+	baseProofCommand
+	Fact string `arg:"" name:"fact" help:"operation fact hash" required:"true"`
+}
+
+func NewProofOperationCommand() ProofOperationCommand {
+	return ProofOperationCommand{
+		baseProofCommand: baseProofCommand{BaseCommand: NewBaseCommand("proof_operation")},
+	}
+}
+
+func (cmd *ProofOperationCommand) Run(version util.Version) error {
+	channel, err := cmd.prepare(cmd, version)
+	if err != nil {
+		return err
+	}
+
+	fact := valuehash.NewBytesFromString(strings.TrimSpace(cmd.Fact))
+	if err := fact.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid fact hash")
+	}
+
+	cmd.Log().Debug().Int64("height", cmd.Height).Stringer("fact", fact).Msg("trying to get operation proof")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	op, found, err := channel.OperationProof(ctx, base.Height(cmd.Height), fact)
+	switch {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("operation, %q not found in %d", fact, cmd.Height)
+	}
+
+	if err := op.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid operation proof")
+	}
+	cmd.Log().Debug().Msg("operation proof verified")
+
+	_, _ = fmt.Fprintln(os.Stdout, jsonenc.ToString(op))
+
+	return nil
+}
+
+type ProofStateCommand struct {
+	baseProofCommand
+	Key string `arg:"" name:"key" help:"state key" required:"true"`
+}
+
+func NewProofStateCommand() ProofStateCommand {
+	return ProofStateCommand{
+		baseProofCommand: baseProofCommand{BaseCommand: NewBaseCommand("proof_state")},
+	}
+}
+
+func (cmd *ProofStateCommand) Run(version util.Version) error {
+	channel, err := cmd.prepare(cmd, version)
+	if err != nil {
+		return err
+	}
+
+	cmd.Key = strings.TrimSpace(cmd.Key)
+	if len(cmd.Key) < 1 {
+		return errors.Errorf("empty state key")
+	}
+
+	cmd.Log().Debug().Int64("height", cmd.Height).Str("key", cmd.Key).Msg("trying to get state proof")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	sp, found, err := channel.StatePathProof(ctx, base.Height(cmd.Height), cmd.Key)
+	switch {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("state, %q not found at %d", cmd.Key, cmd.Height)
+	}
+
+	if err := sp.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid state proof")
+	}
+	cmd.Log().Debug().Msg("state proof verified")
+
+	_, _ = fmt.Fprintln(os.Stdout, jsonenc.ToString(sp))
+
+	return nil
+}
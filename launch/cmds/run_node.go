@@ -20,14 +20,20 @@ import (
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery/memberlist"
+	"github.com/spikeekips/mitum/network/events"
 	"github.com/spikeekips/mitum/states"
+	"github.com/spikeekips/mitum/storage/outbox"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
 )
 
 var defaultRunProcesses = []pm.Process{
+	process.ProcessorMetrics,
 	process.ProcessorDiscovery,
 	process.ProcessorConsensusStates,
+	process.ProcessorEvents,
+	process.ProcessorOutbox,
 }
 
 var defaultRunHooks = []pm.Hook{
@@ -51,10 +57,14 @@ type RunCommand struct {
 	Discovery         []*url.URL    `name:"discovery" help:"discovery node"`
 	ExitAfter         time.Duration `name:"exit-after" help:"exit after the given duration"`
 	NetworkLogFile    []string      `name:"network-log" help:"network log file"`
+	Snapshot          string        `name:"snapshot" help:"import state snapshot when blocks are empty; file or http(s) url"` // revive:disable-line:line-length-limit
 	afterStartedHooks *pm.Hooks
 	cs                states.States
 	nt                network.Server
 	dis               *memberlist.Discovery
+	ms                *metrics.Server
+	es                *events.Server
+	ow                *outbox.Worker
 }
 
 func NewRunCommand(dryrun bool) RunCommand {
@@ -132,6 +142,10 @@ func (cmd *RunCommand) prepare() error {
 	ctx := context.WithValue(cmd.processes.ContextSource(), config.ContextValueNetworkLog, networkLogger)
 	ctx = context.WithValue(ctx, config.ContextValueDiscoveryURLs, cmd.Discovery)
 
+	if len(cmd.Snapshot) > 0 {
+		ctx = context.WithValue(ctx, process.ContextValueSnapshot, SnapshotSource(cmd.Snapshot))
+	}
+
 	_ = cmd.processes.SetContext(ctx)
 
 	return nil
@@ -144,6 +158,18 @@ func (cmd *RunCommand) run() error {
 		return errors.Wrap(err, "failed to run")
 	}
 
+	if err := cmd.runMetrics(ps.Context()); err != nil {
+		return errors.Wrap(err, "failed to run metrics server")
+	}
+
+	if err := cmd.runEvents(ps.Context()); err != nil {
+		return errors.Wrap(err, "failed to run events server")
+	}
+
+	if err := cmd.runOutbox(ps.Context()); err != nil {
+		return errors.Wrap(err, "failed to run outbox worker")
+	}
+
 	if err := cmd.runNetwork(ps.Context()); err != nil {
 		return errors.Wrap(err, "failed to run network")
 	}
@@ -159,6 +185,48 @@ func (cmd *RunCommand) run() error {
 	return cmd.runStates(ps.Context())
 }
 
+func (cmd *RunCommand) runMetrics(ctx context.Context) error {
+	var ms *metrics.Server
+	switch err := process.LoadMetricsServerContextValue(ctx, &ms); {
+	case errors.Is(err, util.ContextValueNotFoundError):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	cmd.ms = ms
+
+	return ms.Start()
+}
+
+func (cmd *RunCommand) runEvents(ctx context.Context) error {
+	var es *events.Server
+	switch err := process.LoadEventsServerContextValue(ctx, &es); {
+	case errors.Is(err, util.ContextValueNotFoundError):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	cmd.es = es
+
+	return es.Start()
+}
+
+func (cmd *RunCommand) runOutbox(ctx context.Context) error {
+	var ow *outbox.Worker
+	switch err := process.LoadOutboxWorkerContextValue(ctx, &ow); {
+	case errors.Is(err, util.ContextValueNotFoundError):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	cmd.ow = ow
+
+	return ow.Start()
+}
+
 func (*RunCommand) runNetwork(ctx context.Context) error {
 	var nt network.Server
 	if err := process.LoadNetworkContextValue(ctx, &nt); err != nil {
@@ -298,5 +366,23 @@ func (cmd *RunCommand) whenExited() error {
 		}
 	}
 
+	if cmd.ms != nil {
+		if err := cmd.ms.Stop(); err != nil {
+			return errors.Wrap(err, "failed to stop metrics server")
+		}
+	}
+
+	if cmd.es != nil {
+		if err := cmd.es.Stop(); err != nil {
+			return errors.Wrap(err, "failed to stop events server")
+		}
+	}
+
+	if cmd.ow != nil {
+		if err := cmd.ow.Stop(); err != nil {
+			return errors.Wrap(err, "failed to stop outbox worker")
+		}
+	}
+
 	return nil
 }
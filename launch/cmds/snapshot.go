@@ -0,0 +1,209 @@
+package cmds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+)
+
+type SnapshotCommand struct {
+	Export SnapshotExportCommand `cmd:"" help:"export state snapshot"`
+	Import SnapshotImportCommand `cmd:"" help:"import state snapshot"`
+}
+
+func NewSnapshotCommand() SnapshotCommand {
+	return SnapshotCommand{
+		Export: NewSnapshotExportCommand(),
+		Import: NewSnapshotImportCommand(),
+	}
+}
+
+type SnapshotExportCommand struct {
+	*BaseRunCommand
+	Output string `arg:"" name:"output" help:"snapshot file" required:"true"`
+	Height int64  `name:"height" help:"height of snapshot; default is last height" default:"-1"`
+}
+
+func NewSnapshotExportCommand() SnapshotExportCommand {
+	return SnapshotExportCommand{
+		BaseRunCommand: newStorageBaseRunCommand("snapshot-export"),
+	}
+}
+
+func (cmd *SnapshotExportCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+	defer cmd.Done()
+
+	if err := cmd.prepare(); err != nil {
+		return err
+	}
+
+	ps := cmd.Processes()
+	if err := ps.Run(); err != nil {
+		return err
+	}
+
+	var db storage.Database
+	if err := process.LoadDatabaseContextValue(ps.Context(), &db); err != nil {
+		return err
+	}
+
+	var bd blockdata.Blockdata
+	if err := process.LoadBlockdataContextValue(ps.Context(), &bd); err != nil {
+		return err
+	}
+
+	height := base.Height(cmd.Height)
+	if height <= base.NilHeight {
+		switch m, found, err := db.LastManifest(); {
+		case err != nil:
+			return err
+		case !found:
+			return util.NotFoundError.Errorf("empty blocks")
+		default:
+			height = m.Height()
+		}
+	}
+
+	s := time.Now()
+	cmd.Log().Info().Int64("height", height.Int64()).Str("output", cmd.Output).Msg("trying to export snapshot")
+
+	f, err := os.OpenFile(filepath.Clean(cmd.Output), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create snapshot file")
+	}
+
+	sn, err := isaac.ExportSnapshot(context.Background(), f, db, bd, height)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		_ = os.Remove(cmd.Output)
+
+		return errors.Wrap(err, "failed to export snapshot")
+	}
+
+	cmd.Log().Info().
+		Int64("height", sn.Height().Int64()).
+		Int64("from", sn.From().Int64()).
+		Uint64("states", sn.States()).
+		Dur("elapsed", time.Since(s)).
+		Msg("snapshot exported")
+
+	return nil
+}
+
+type SnapshotImportCommand struct {
+	*BaseRunCommand
+	Source string `arg:"" name:"snapshot" help:"snapshot file or http(s) url" required:"true"`
+}
+
+func NewSnapshotImportCommand() SnapshotImportCommand {
+	return SnapshotImportCommand{
+		BaseRunCommand: newStorageBaseRunCommand("snapshot-import"),
+	}
+}
+
+func (cmd *SnapshotImportCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+	defer cmd.Done()
+
+	if err := cmd.prepare(); err != nil {
+		return err
+	}
+
+	ps := cmd.Processes()
+	if err := ps.Run(); err != nil {
+		return err
+	}
+
+	var policy *isaac.LocalPolicy
+	if err := process.LoadPolicyContextValue(ps.Context(), &policy); err != nil {
+		return err
+	}
+
+	var suffrage base.Suffrage
+	if err := process.LoadSuffrageContextValue(ps.Context(), &suffrage); err != nil {
+		return err
+	}
+
+	var db storage.Database
+	if err := process.LoadDatabaseContextValue(ps.Context(), &db); err != nil {
+		return err
+	}
+
+	var bd blockdata.Blockdata
+	if err := process.LoadBlockdataContextValue(ps.Context(), &bd); err != nil {
+		return err
+	}
+
+	s := time.Now()
+	cmd.Log().Info().Str("snapshot", cmd.Source).Msg("trying to import snapshot")
+
+	r, err := SnapshotSource(cmd.Source)()
+	if err != nil {
+		return errors.Wrap(err, "failed to open snapshot")
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	blk, err := isaac.ImportSnapshot(context.Background(), r, db, bd, policy, suffrage)
+	if err != nil {
+		return errors.Wrap(err, "failed to import snapshot")
+	}
+
+	cmd.Log().Info().Object("block", blk).Dur("elapsed", time.Since(s)).Msg("snapshot imported")
+
+	return nil
+}
+
+// SnapshotSource returns the function to open snapshot from the local file or
+// http(s) url.
+func SnapshotSource(s string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+
+		switch u.Scheme {
+		case "http", "https":
+		case "", "file":
+			return os.Open(filepath.Clean(u.Path))
+		default:
+			return nil, errors.Errorf("%q not yet supported", u.Scheme)
+		}
+
+		res, err := http.Get(u.String()) // nolint:gosec,noctx
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode != http.StatusOK {
+			_ = res.Body.Close()
+
+			return nil, errors.Errorf("failed to request snapshot: %q", res.Status)
+		}
+
+		return res.Body, nil
+	}
+}
@@ -0,0 +1,99 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+type StateCommand struct {
+	*BaseCommand
+	URL        *url.URL      `arg:"" name:"node url" help:"remote mitum url" required:"true"`
+	Key        string        `arg:"" name:"key" help:"state key" required:"true"`
+	Proof      bool          `name:"proof" help:"get state with proof path of states tree and verify it; default is false"`
+	Timeout    time.Duration `name:"timeout" help:"timeout; default is 5 seconds"`
+	TLSInscure bool          `name:"tls-insecure" help:"allow inseucre TLS connection; default is false"`
+}
+
+func NewStateCommand() StateCommand {
+	return StateCommand{
+		BaseCommand: NewBaseCommand("state"),
+	}
+}
+
+func (cmd *StateCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+
+	if cmd.Timeout < 1 {
+		cmd.Timeout = time.Second * 5
+	}
+
+	cmd.Key = strings.TrimSpace(cmd.Key)
+	if len(cmd.Key) < 1 {
+		return errors.Errorf("empty state key")
+	}
+
+	cmd.Log().Debug().Interface("node_url", cmd.URL).Str("key", cmd.Key).Msg("trying to get state")
+
+	encs := cmd.Encoders()
+	if encs == nil {
+		i, err := cmd.LoadEncoders(nil, nil)
+		if err != nil {
+			return err
+		}
+		encs = i
+	}
+
+	connInfo := network.NewHTTPConnInfo(network.NormalizeURL(cmd.URL), cmd.TLSInscure)
+	channel, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout)
+	if err != nil {
+		return err
+	}
+	cmd.Log().Debug().Msg("network channel loaded")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	var output interface{}
+	if cmd.Proof {
+		sp, found, err := channel.StateProof(ctx, cmd.Key)
+		switch {
+		case err != nil:
+			return err
+		case !found:
+			return util.NotFoundError.Errorf("state, %q not found", cmd.Key)
+		}
+
+		if err := sp.IsValid(nil); err != nil {
+			return errors.Wrap(err, "invalid state proof")
+		}
+		cmd.Log().Debug().Msg("state proof verified")
+
+		output = sp
+	} else {
+		st, found, err := channel.State(ctx, cmd.Key)
+		switch {
+		case err != nil:
+			return err
+		case !found:
+			return util.NotFoundError.Errorf("state, %q not found", cmd.Key)
+		}
+
+		output = st
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, jsonenc.ToString(output))
+
+	return nil
+}
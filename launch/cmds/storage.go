@@ -0,0 +1,155 @@
+package cmds
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	leveldbstorage "github.com/spikeekips/mitum/storage/leveldb"
+	mongodbstorage "github.com/spikeekips/mitum/storage/mongodb"
+	"github.com/spikeekips/mitum/util"
+)
+
+type StorageCommand struct {
+	Migrate StorageMigrateCommand `cmd:"" help:"migrate database into the latest schema version"`
+	Reindex StorageReindexCommand `cmd:"" help:"index operations of blocks stored before operation indexes"`
+}
+
+func NewStorageCommand() StorageCommand {
+	return StorageCommand{
+		Migrate: NewStorageMigrateCommand(),
+		Reindex: NewStorageReindexCommand(),
+	}
+}
+
+// StorageMigrateCommand runs the registered migrations of database, which are
+// not applied yet.
+type StorageMigrateCommand struct {
+	*BaseRunCommand
+	Dryrun bool `help:"just show the migrations to be applied default: false" default:"false"`
+}
+
+func NewStorageMigrateCommand() StorageMigrateCommand {
+	return StorageMigrateCommand{
+		BaseRunCommand: newStorageBaseRunCommand("storage-migrate"),
+	}
+}
+
+func (cmd *StorageMigrateCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+	defer cmd.Done()
+
+	if err := cmd.prepare(); err != nil {
+		return err
+	}
+
+	ps := cmd.Processes()
+	_ = ps.SetContext(context.WithValue(ps.ContextSource(), process.ContextValueSkipSchemaVersionCheck, true))
+	_ = cmd.SetProcesses(ps)
+
+	if err := ps.Run(); err != nil {
+		return err
+	}
+
+	var db storage.Database
+	if err := process.LoadDatabaseContextValue(ps.Context(), &db); err != nil {
+		return err
+	}
+
+	st, ok := db.(*mongodbstorage.Database)
+	if !ok {
+		return errors.Errorf("migration is supported only for mongodb database, not %T", db)
+	}
+
+	var bd blockdata.Blockdata
+	if err := process.LoadBlockdataContextValue(ps.Context(), &bd); err != nil {
+		return err
+	}
+
+	current, _, err := st.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	cmd.Log().Info().Bool("dryrun", cmd.Dryrun).
+		Uint64("current", current).Uint64("latest", mongodbstorage.LatestSchemaVersion()).
+		Int("migrations", len(mongodbstorage.Migrations(current))).
+		Msg("trying to migrate database")
+
+	s := time.Now()
+	if err := st.Migrate(context.Background(), bd, cmd.Dryrun, func(m mongodbstorage.Migration, changed uint64) error {
+		cmd.Log().Info().Bool("dryrun", cmd.Dryrun).
+			Uint64("version", m.Version).Str("description", m.Description).Uint64("changed", changed).
+			Msg("migrated")
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	cmd.Log().Info().Bool("dryrun", cmd.Dryrun).Dur("elapsed", time.Since(s)).Msg("database migrated")
+
+	return nil
+}
+
+// StorageReindexCommand stores and indexes the operations of the blocks, which
+// were stored before the operations are indexed by signers and hint type, for
+// leveldb database. For mongodb database, the operations are reindexed by the
+// migration.
+type StorageReindexCommand struct {
+	*BaseRunCommand
+	Dryrun bool `help:"just count the operations to be reindexed default: false" default:"false"`
+}
+
+func NewStorageReindexCommand() StorageReindexCommand {
+	return StorageReindexCommand{
+		BaseRunCommand: newStorageBaseRunCommand("storage-reindex"),
+	}
+}
+
+func (cmd *StorageReindexCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+	defer cmd.Done()
+
+	if err := cmd.prepare(); err != nil {
+		return err
+	}
+
+	ps := cmd.Processes()
+	if err := ps.Run(); err != nil {
+		return err
+	}
+
+	var db storage.Database
+	if err := process.LoadDatabaseContextValue(ps.Context(), &db); err != nil {
+		return err
+	}
+
+	var bd blockdata.Blockdata
+	if err := process.LoadBlockdataContextValue(ps.Context(), &bd); err != nil {
+		return err
+	}
+
+	st, ok := db.(*leveldbstorage.Database)
+	if !ok {
+		return errors.Errorf("reindex is supported only for leveldb database, not %T; run `storage migrate`", db)
+	}
+
+	s := time.Now()
+	reindexed, err := st.ReindexOperations(context.Background(), bd, cmd.Dryrun)
+	if err != nil {
+		return err
+	}
+
+	cmd.Log().Info().Bool("dryrun", cmd.Dryrun).Uint64("operations", reindexed).Dur("elapsed", time.Since(s)).
+		Msg("operations reindexed")
+
+	return nil
+}
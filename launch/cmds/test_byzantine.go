@@ -0,0 +1,17 @@
+//go:build test
+// +build test
+
+package cmds
+
+import (
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/launch/process"
+)
+
+func init() {
+	// NOTE byzantine hook is only for test build
+	defaultHooks = append(defaultHooks,
+		pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
+			process.HookNameByzantine, process.HookByzantine),
+	)
+}
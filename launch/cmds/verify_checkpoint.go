@@ -0,0 +1,265 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+// VerifyDamagedHeight is the damaged height found by verification.
+type VerifyDamagedHeight struct {
+	Height base.Height `json:"height"`
+	Error  string      `json:"error"`
+}
+
+// VerifyReport is the summary of verification, which is written by
+// --report.
+type VerifyReport struct {
+	Command    string                `json:"command"`
+	Target     string                `json:"target"`
+	From       base.Height           `json:"from"`
+	To         base.Height           `json:"to"`
+	Resumed    bool                  `json:"resumed"`
+	Verified   base.Height           `json:"verified"`
+	Damaged    []VerifyDamagedHeight `json:"damaged"`
+	Repaired   bool                  `json:"repaired"`
+	Error      string                `json:"error,omitempty"`
+	StartedAt  time.Time             `json:"started_at"`
+	FinishedAt time.Time             `json:"finished_at"`
+	Elapsed    float64               `json:"elapsed"`
+}
+
+// verifyCheckpoint keeps the progress of verification. The heights until
+// Verified are all verified; the damaged heights found under Verified are
+// kept to be reported and repaired by the next run.
+type verifyCheckpoint struct {
+	Command      string                `json:"command"`
+	Target       string                `json:"target"`
+	Verified     base.Height           `json:"verified"`
+	VerifiedHash string                `json:"verified_hash,omitempty"`
+	Damaged      []VerifyDamagedHeight `json:"damaged"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+// loadVerifyCheckpoint loads checkpoint file; if file does not exist, nil is
+// returned.
+func loadVerifyCheckpoint(f string) (*verifyCheckpoint, error) {
+	b, err := os.ReadFile(filepath.Clean(f))
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to read checkpoint, %q", f)
+	}
+
+	var cp verifyCheckpoint
+	if err := jsonenc.Unmarshal(b, &cp); err != nil {
+		return nil, errors.Wrapf(err, "failed to load checkpoint, %q", f)
+	}
+
+	return &cp, nil
+}
+
+// save writes checkpoint to the temporary file and renames it, so the
+// interrupted write does not break the previous checkpoint.
+func (cp verifyCheckpoint) save(f string) error {
+	b, err := jsonenc.MarshalIndent(cp)
+	if err != nil {
+		return err
+	}
+
+	temp := f + ".tmp"
+	if err := os.WriteFile(temp, b, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write checkpoint, %q", f)
+	}
+
+	return os.Rename(temp, f)
+}
+
+// loadCheckpoint loads the checkpoint file once; the loaded checkpoint is
+// updated by saveCheckpoint.
+func (cmd *BaseVerifyCommand) loadCheckpoint() (*verifyCheckpoint, error) {
+	if len(cmd.Checkpoint) < 1 || cmd.checkpoint != nil {
+		return cmd.checkpoint, nil
+	}
+
+	i, err := loadVerifyCheckpoint(cmd.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	cmd.checkpoint = i
+
+	return i, nil
+}
+
+// prepareCheckpoint decides the height to start verification from the
+// checkpoint. If the verified block of checkpoint was changed, verification
+// starts from the beginning.
+func (cmd *BaseVerifyCommand) prepareCheckpoint(target string, get func(base.Height) (block.Manifest, error)) error {
+	cmd.target = target
+	cmd.verified = base.NilHeight
+	cmd.resumed = false
+	cmd.repaired = false
+
+	if len(cmd.Checkpoint) < 1 {
+		return nil
+	}
+
+	cp, err := cmd.loadCheckpoint()
+	switch {
+	case err != nil:
+		return err
+	case cp == nil:
+		cmd.Log().Debug().Str("checkpoint", cmd.Checkpoint).Msg("checkpoint not found; verify from the beginning")
+
+		return nil
+	}
+
+	if cp.Command != cmd.name || cp.Target != target {
+		return errors.Errorf("checkpoint, %q is for %s of %q", cmd.Checkpoint, cp.Command, cp.Target)
+	}
+
+	l := cmd.Log().With().Str("checkpoint", cmd.Checkpoint).Int64("verified", cp.Verified.Int64()).Logger()
+
+	if cp.Verified > cmd.lastHeight {
+		l.Warn().Int64("last_height", cmd.lastHeight.Int64()).
+			Msg("checkpoint is higher than last height; verify from the beginning")
+
+		return nil
+	}
+
+	if len(cp.VerifiedHash) > 0 {
+		switch m, err := get(cp.Verified); {
+		case err != nil:
+			l.Warn().Err(err).Msg("failed to load verified manifest; verify from the beginning")
+
+			return nil
+		case m.Hash().String() != cp.VerifiedHash:
+			l.Warn().Msg("verified block was changed; verify from the beginning")
+
+			return nil
+		}
+	}
+
+	for i := range cp.Damaged {
+		if d := cp.Damaged[i]; d.Height <= cp.Verified {
+			cmd.setDamaged(d.Height, errors.New(d.Error))
+		}
+	}
+
+	cmd.verified = cp.Verified
+	cmd.resumed = true
+
+	l.Info().Int("damaged", len(cp.Damaged)).Msg("verification resumed from checkpoint")
+
+	return nil
+}
+
+// saveCheckpoint stores the verified height and the damaged heights into
+// checkpoint file.
+func (cmd *BaseVerifyCommand) saveCheckpoint(verified base.Height, get func(base.Height) (block.Manifest, error)) error {
+	if len(cmd.Checkpoint) < 1 {
+		return nil
+	}
+
+	cp := verifyCheckpoint{
+		Command:   cmd.name,
+		Target:    cmd.target,
+		Verified:  verified,
+		UpdatedAt: localtime.UTCNow(),
+	}
+
+	if !cmd.repaired {
+		cp.Damaged = cmd.damagedList()
+	}
+
+	if verified >= base.PreGenesisHeight {
+		if m, err := get(verified); err == nil {
+			cp.VerifiedHash = m.Hash().String()
+		}
+	}
+
+	if err := cp.save(cmd.Checkpoint); err != nil {
+		return err
+	}
+
+	cmd.checkpoint = &cp
+
+	cmd.Log().Debug().Int64("verified", verified.Int64()).Msg("checkpoint saved")
+
+	return nil
+}
+
+// damagedList returns the damaged heights with errors, sorted by height.
+func (cmd *BaseVerifyCommand) damagedList() []VerifyDamagedHeight {
+	cmd.damagedLock.Lock()
+	defer cmd.damagedLock.Unlock()
+
+	l := make([]VerifyDamagedHeight, 0, len(cmd.damaged))
+	for height := range cmd.damaged {
+		l = append(l, VerifyDamagedHeight{Height: height, Error: cmd.damaged[height].Error()})
+	}
+
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Height < l[j].Height
+	})
+
+	return l
+}
+
+func (cmd *BaseVerifyCommand) report(err error) VerifyReport {
+	finished := localtime.UTCNow()
+
+	r := VerifyReport{
+		Command:    cmd.name,
+		Target:     cmd.target,
+		From:       cmd.from,
+		To:         cmd.lastHeight,
+		Resumed:    cmd.resumed,
+		Verified:   cmd.verified,
+		Damaged:    cmd.damagedList(),
+		Repaired:   cmd.repaired,
+		StartedAt:  cmd.startedAt,
+		FinishedAt: finished,
+		Elapsed:    finished.Sub(cmd.startedAt).Seconds(),
+	}
+
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	return r
+}
+
+// writeReport writes the json report of verification into the --report
+// file; "-" is stdout.
+func (cmd *BaseVerifyCommand) writeReport(err error) error {
+	if len(cmd.Report) < 1 {
+		return nil
+	}
+
+	b, e := jsonenc.MarshalIndent(cmd.report(err))
+	if e != nil {
+		return e
+	}
+
+	if cmd.Report == "-" {
+		_, _ = fmt.Fprintln(os.Stdout, string(b))
+
+		return nil
+	}
+
+	if e := os.WriteFile(cmd.Report, b, 0o600); e != nil {
+		return errors.Wrapf(e, "failed to write report, %q", cmd.Report)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:build test
+// +build test
+
+package cmds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testVerifyCheckpoint struct {
+	isaac.BaseTest
+	local      *isaac.Local
+	checkpoint string
+}
+
+func (t *testVerifyCheckpoint) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	t.local = t.Locals(1)[0]
+	t.checkpoint = filepath.Join(t.T().TempDir(), "checkpoint.json")
+}
+
+func (t *testVerifyCheckpoint) newCommand() BlockdataVerifyCommand {
+	cmd := NewBlockdataVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	cmd.networkID = t.local.Policy().NetworkID()
+	cmd.bd = t.local.Blockdata()
+	cmd.Path = t.local.Blockdata().(*localfs.Blockdata).Root()
+	cmd.Checkpoint = t.checkpoint
+	cmd.RangeSize = 1
+	cmd.Workers = 2
+
+	return cmd
+}
+
+func (t *testVerifyCheckpoint) loadCheckpoint() *verifyCheckpoint {
+	cp, err := loadVerifyCheckpoint(t.checkpoint)
+	t.NoError(err)
+	t.NotNil(cp)
+
+	return cp
+}
+
+func (t *testVerifyCheckpoint) TestVerifyRanges() {
+	t.Equal([][2]base.Height{{-1, 1}, {2, 4}, {5, 5}}, verifyRanges(base.PreGenesisHeight, base.Height(5), 3))
+	t.Equal([][2]base.Height{{3, 5}}, verifyRanges(base.Height(3), base.Height(5), 10))
+	t.Empty(verifyRanges(base.Height(6), base.Height(5), 3))
+}
+
+func (t *testVerifyCheckpoint) TestResume() {
+	cmd := t.newCommand()
+	t.NoError(cmd.verify())
+	t.False(cmd.resumed)
+	t.Equal(base.PreGenesisHeight, cmd.from)
+
+	cp := t.loadCheckpoint()
+	t.Equal("blockdata-verify", cp.Command)
+	t.Equal(cmd.lastHeight, cp.Verified)
+	t.Empty(cp.Damaged)
+
+	m, err := cmd.loadManifest(cp.Verified)
+	t.NoError(err)
+	t.Equal(m.Hash().String(), cp.VerifiedHash)
+
+	// NOTE resumed; nothing to verify
+	cmd = t.newCommand()
+	t.NoError(cmd.verify())
+	t.True(cmd.resumed)
+	t.Equal(cp.Verified+1, cmd.from)
+	t.Equal(cp.Verified, cmd.verified)
+}
+
+func (t *testVerifyCheckpoint) TestResumeWithDamaged() {
+	f, err := os.Create(filepath.Join(
+		t.local.Blockdata().(*localfs.Blockdata).Root(),
+		localfs.HeightDirectory(base.Height(1)),
+		"1-"+block.BlockdataOperations+"-showme.jsonld.gz",
+	))
+	t.NoError(err)
+	t.NoError(f.Close())
+
+	cmd := t.newCommand()
+	t.NoError(cmd.verify())
+	t.Equal([]base.Height{1}, cmd.damagedHeights())
+
+	cp := t.loadCheckpoint()
+	t.Equal(cmd.lastHeight, cp.Verified)
+	t.Equal(1, len(cp.Damaged))
+	t.Equal(base.Height(1), cp.Damaged[0].Height)
+
+	// NOTE damaged heights are restored from checkpoint
+	cmd = t.newCommand()
+	t.NoError(cmd.verify())
+	t.True(cmd.resumed)
+	t.Equal([]base.Height{1}, cmd.damagedHeights())
+}
+
+func (t *testVerifyCheckpoint) TestDifferentTarget() {
+	cmd := t.newCommand()
+	t.NoError(cmd.verify())
+
+	cp := t.loadCheckpoint()
+	cp.Target = "/showme"
+	t.NoError(cp.save(t.checkpoint))
+
+	cmd = t.newCommand()
+	err := cmd.verify()
+	t.Error(err)
+	t.Contains(err.Error(), "is for blockdata-verify of \"/showme\"")
+}
+
+func (t *testVerifyCheckpoint) TestChangedBlock() {
+	cmd := t.newCommand()
+	t.NoError(cmd.verify())
+
+	cp := t.loadCheckpoint()
+	cp.VerifiedHash = "showme"
+	t.NoError(cp.save(t.checkpoint))
+
+	cmd = t.newCommand()
+	t.NoError(cmd.verify())
+	t.False(cmd.resumed)
+	t.Equal(base.PreGenesisHeight, cmd.from)
+}
+
+func (t *testVerifyCheckpoint) TestReport() {
+	cmd := t.newCommand()
+	cmd.Report = filepath.Join(t.T().TempDir(), "report.json")
+
+	t.NoError(cmd.verify())
+	t.NoError(cmd.writeReport(nil))
+
+	b, err := os.ReadFile(cmd.Report)
+	t.NoError(err)
+
+	var r VerifyReport
+	t.NoError(jsonenc.Unmarshal(b, &r))
+
+	t.Equal("blockdata-verify", r.Command)
+	t.Equal(cmd.checkpointTarget(), r.Target)
+	t.Equal(base.PreGenesisHeight, r.From)
+	t.Equal(cmd.lastHeight, r.To)
+	t.Equal(cmd.lastHeight, r.Verified)
+	t.False(r.Resumed)
+	t.False(r.Repaired)
+	t.Empty(r.Damaged)
+	t.Empty(r.Error)
+	t.False(r.FinishedAt.Before(r.StartedAt))
+}
+
+func TestVerifyCheckpoint(t *testing.T) {
+	suite.Run(t, new(testVerifyCheckpoint))
+}
@@ -0,0 +1,262 @@
+package cmds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// fetchBlockdataMap requests the BlockdataMap of height to the suffrage
+// nodes. The ACCEPT voteproof of each map is checked by the suffrage and the
+// map with invalid voteproof is ignored. The valid maps from the nodes, which
+// have the block, should point the same block. If expected is not nil, the
+// block of map should be same with it.
+func (cmd *BaseVerifyCommand) fetchBlockdataMap(
+	writer blockdata.Writer,
+	height base.Height,
+	expected valuehash.Hash,
+) (block.BlockdataMap, network.Channel, error) {
+	var bdm block.BlockdataMap
+	var ch network.Channel
+
+	for i := range cmd.channels {
+		c := cmd.channels[i]
+		l := cmd.Log().With().Int64("height", height.Int64()).Stringer("node", c.ConnInfo()).Logger()
+
+		m, err := cmd.requestBlockdataMap(writer, c, height)
+		if err != nil {
+			l.Warn().Err(err).Msg("failed to request block data map")
+
+			continue
+		}
+
+		switch {
+		case expected != nil && !m.Block().Equal(expected):
+			return nil, nil, errors.Errorf(
+				"block data map of height, %d from %q does not match; %s != %s",
+				height, c.ConnInfo(), m.Block(), expected)
+		case bdm == nil:
+			bdm = m
+			ch = c
+		case !bdm.Block().Equal(m.Block()):
+			return nil, nil, errors.Errorf("suffrage nodes have different blocks of height, %d", height)
+		}
+	}
+
+	if bdm == nil {
+		return nil, nil, util.NotFoundError.Errorf("block data map of height, %d not found in suffrage nodes", height)
+	}
+
+	return bdm, ch, nil
+}
+
+func (cmd *BaseVerifyCommand) requestBlockdataMap(
+	writer blockdata.Writer,
+	ch network.Channel,
+	height base.Height,
+) (block.BlockdataMap, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	m, err := network.FetchBlockdataMap(ctx, ch, height, cmd.networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.checkBlockdataMapVoteproof(ctx, writer, ch, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// checkBlockdataMapVoteproof checks the ACCEPT voteproof of BlockdataMap is
+// valid by the suffrage and it points the block of map.
+func (cmd *BaseVerifyCommand) checkBlockdataMapVoteproof(
+	ctx context.Context,
+	writer blockdata.Writer,
+	ch network.Channel,
+	m block.BlockdataMap,
+) error {
+	r, err := network.FetchBlockdata(ctx, ch, m.ACCEPTVoteproof())
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch accept voteproof")
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	vp, err := writer.ReadACCEPTVoteproof(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to read accept voteproof")
+	}
+
+	// NOTE the threshold ratio of policy can be changed after the block, so the
+	// voteproof of the stored block is checked by CheckStored.
+	if err := isaac.NewVoteProofChecker(vp, cmd.policy, cmd.suffrage).CheckStored(); err != nil {
+		return err
+	}
+
+	fact, ok := vp.Majority().(base.ACCEPTBallotFact)
+	switch {
+	case vp.Height() != m.Height():
+		return errors.Errorf("accept voteproof has different height, %d", vp.Height())
+	case !ok:
+		return errors.Errorf("accept voteproof has not accept ballot fact, %T", vp.Majority())
+	case !fact.NewBlock().Equal(m.Block()):
+		return errors.Errorf("accept voteproof has different block, %s != %s", fact.NewBlock(), m.Block())
+	}
+
+	return nil
+}
+
+// fetchBlock fetches the block data items of the BlockdataMap from the
+// suffrage node and stores them into blockdata; the existing block data of
+// height is replaced. The checksums of items are checked against the
+// BlockdataMap while fetching.
+func (cmd *BaseVerifyCommand) fetchBlock(
+	bd blockdata.Blockdata,
+	ch network.Channel,
+	bdm block.BlockdataMap,
+) (block.Block, block.BlockdataMap, error) {
+	height := bdm.Height()
+
+	ss, err := bd.NewSession(height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		_ = ss.Cancel()
+	}()
+
+	blk := (interface{})(block.EmptyBlockV0()).(block.BlockUpdater)
+	for i := range block.Blockdata {
+		dataType := block.Blockdata[i]
+
+		item, err := getItemBlockdataMap(bdm, dataType)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		j, err := cmd.fetchBlockdataItem(bd.Writer(), ch, item, ss, blk)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to fetch block data, %q of height, %d", dataType, height)
+		}
+		blk = j
+	}
+
+	if err := blk.IsValid(cmd.networkID); err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid block of height, %d fetched", height)
+	} else if err := block.CompareManifestWithMap(blk, bdm); err != nil {
+		return nil, nil, err
+	}
+
+	switch found, err := bd.Exists(height); {
+	case err != nil:
+		return nil, nil, err
+	case found:
+		if err := bd.RemoveAll(height); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	m, err := bd.SaveSession(ss)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cmd.Log().Info().Int64("height", height.Int64()).Stringer("node", ch.ConnInfo()).Msg("block data repaired")
+
+	return blk, m, nil
+}
+
+func (cmd *BaseVerifyCommand) fetchBlockdataItem(
+	writer blockdata.Writer,
+	ch network.Channel,
+	item block.BlockdataMapItem,
+	ss blockdata.Session,
+	blk block.BlockUpdater,
+) (block.BlockUpdater, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	r, err := network.FetchBlockdata(ctx, ch, item)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE manifest is set by SetManifest to set the block hash of session
+	if item.Type() != block.BlockdataManifest {
+		if _, err := ss.Import(item.Type(), bytes.NewReader(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	i, err := blockdata.ReadItem(writer, blk, item.Type(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Type() == block.BlockdataManifest {
+		if err := ss.SetManifest(i.Manifest()); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// checkLocalBlockFile checks the checksum of block data file, which is
+// stored under root by localfs.Blockdata.
+func checkLocalBlockFile(root string, height base.Height, dataType string) error {
+	g := filepath.Join(root, localfs.HeightDirectory(height), fmt.Sprintf("%d-%s-*.jsonld.gz", height, dataType))
+
+	var f string
+	switch matches, err := filepath.Glob(g); {
+	case err != nil:
+		return storage.MergeStorageError(err)
+	case len(matches) < 1:
+		return util.NotFoundError.Errorf("block data, %q(%d) not found", dataType, height)
+	case len(matches) > 1:
+		return errors.Errorf("block data, %q(%d) multiple files found", dataType, height)
+	default:
+		f = matches[0]
+	}
+
+	_, _, checksum, err := localfs.ParseDataFileName(f)
+	if err != nil {
+		return err
+	}
+
+	if i, err := util.GenerateFileChecksum(f); err != nil {
+		return err
+	} else if checksum != i {
+		return errors.Errorf("file checksum does not match; %s != %s", checksum, i)
+	}
+
+	return nil
+}
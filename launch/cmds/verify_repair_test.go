@@ -0,0 +1,236 @@
+//go:build test
+// +build test
+
+package cmds
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch"
+	"github.com/spikeekips/mitum/network"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/stretchr/testify/suite"
+)
+
+type testVerifyRepair struct {
+	isaac.BaseTest
+	local  *isaac.Local
+	remote *isaac.Local
+}
+
+func (t *testVerifyRepair) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	ls := t.Locals(2)
+	t.local, t.remote = ls[0], ls[1]
+
+	ch := t.remote.Channel().(*channetwork.Channel)
+	ch.SetBlockdataMapsHandler(func(heights []base.Height) ([]block.BlockdataMap, error) {
+		var maps []block.BlockdataMap
+		for i := range heights {
+			bdm, found, err := t.remote.Database().BlockdataMap(heights[i])
+			if err != nil {
+				return nil, err
+			} else if !found {
+				break
+			}
+
+			maps = append(maps, bdm)
+		}
+
+		return maps, nil
+	})
+	ch.SetBlockdataHandler(func(p string) (io.Reader, func() error, error) {
+		f, err := t.remote.Blockdata().FS().Open(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return f, f.Close, nil
+	})
+}
+
+func (t *testVerifyRepair) prepareBase(cmd *BaseVerifyCommand) {
+	cmd.networkID = t.local.Policy().NetworkID()
+	cmd.lastHeight = t.LastManifest(t.local.Database()).Height()
+	cmd.Repair = true
+	cmd.Timeout = time.Second
+	cmd.channels = []network.Channel{t.remote.Channel()}
+
+	policy, err := isaac.NewLocalPolicy(cmd.networkID).SetThresholdRatio(67)
+	t.NoError(err)
+	cmd.policy = policy
+	cmd.suffrage = t.Suffrage(t.local, t.local, t.remote)
+}
+
+func (t *testVerifyRepair) blockFile(height base.Height, dataType string) string {
+	root := t.local.Blockdata().(*localfs.Blockdata).Root()
+
+	matches, err := filepath.Glob(filepath.Join(root, localfs.HeightDirectory(height), "*-"+dataType+"-*"))
+	t.NoError(err)
+	t.Equal(1, len(matches))
+
+	return matches[0]
+}
+
+func (t *testVerifyRepair) corrupt(height base.Height, dataType string) {
+	f, err := os.Create(t.blockFile(height, dataType))
+	t.NoError(err)
+
+	gw := gzip.NewWriter(f)
+	_, err = gw.Write([]byte("showme"))
+	t.NoError(err)
+	t.NoError(gw.Close())
+	t.NoError(f.Close())
+}
+
+func (t *testVerifyRepair) TestRepairBlockdata() {
+	cmd := NewBlockdataVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	t.prepareBase(cmd.BaseVerifyCommand)
+	cmd.bd = t.local.Blockdata()
+	cmd.Path = t.local.Blockdata().(*localfs.Blockdata).Root()
+
+	t.corrupt(base.Height(1), block.BlockdataOperations)
+
+	t.NoError(cmd.verify())
+	t.Equal([]base.Height{1}, cmd.damagedHeights())
+
+	for i := range block.Blockdata {
+		t.NoError(cmd.checkBlockFile(base.Height(1), block.Blockdata[i]))
+	}
+
+	blk, err := cmd.loadBlock(base.Height(1))
+	t.NoError(err)
+
+	m, found, err := t.remote.Database().ManifestByHeight(base.Height(1))
+	t.NoError(err)
+	t.True(found)
+	t.True(m.Hash().Equal(blk.Hash()))
+}
+
+func (t *testVerifyRepair) TestRepairBlockdataWithoutNodes() {
+	cmd := NewBlockdataVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	t.prepareBase(cmd.BaseVerifyCommand)
+	cmd.bd = t.local.Blockdata()
+	cmd.Path = t.local.Blockdata().(*localfs.Blockdata).Root()
+	cmd.channels = nil
+
+	t.corrupt(base.Height(1), block.BlockdataOperations)
+
+	err := cmd.verify()
+	t.Error(err)
+	t.Contains(err.Error(), "failed to repair block data")
+}
+
+func (t *testVerifyRepair) TestRepairBlockdataUnknownSuffrage() {
+	cmd := NewBlockdataVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	t.prepareBase(cmd.BaseVerifyCommand)
+	cmd.bd = t.local.Blockdata()
+	cmd.Path = t.local.Blockdata().(*localfs.Blockdata).Root()
+
+	// NOTE the voteproofs of blocks have the votes from local
+	cmd.suffrage = t.Suffrage(t.remote, t.remote)
+
+	t.corrupt(base.Height(1), block.BlockdataOperations)
+
+	err := cmd.verify()
+	t.Error(err)
+	t.Contains(err.Error(), "failed to repair block data")
+}
+
+func (t *testVerifyRepair) TestRepairBlockdataDifferentThreshold() {
+	cmd := NewBlockdataVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	t.prepareBase(cmd.BaseVerifyCommand)
+	cmd.bd = t.local.Blockdata()
+	cmd.Path = t.local.Blockdata().(*localfs.Blockdata).Root()
+
+	policy, err := isaac.NewLocalPolicy(cmd.networkID).SetThresholdRatio(100)
+	t.NoError(err)
+	cmd.policy = policy
+
+	t.corrupt(base.Height(1), block.BlockdataOperations)
+
+	err = cmd.verify()
+	t.Error(err)
+	t.Contains(err.Error(), "failed to repair block data")
+}
+
+func (t *testVerifyRepair) TestRepairBlockdataLowerThreshold() {
+	cmd := NewBlockdataVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	t.prepareBase(cmd.BaseVerifyCommand)
+	cmd.bd = t.local.Blockdata()
+	cmd.Path = t.local.Blockdata().(*localfs.Blockdata).Root()
+
+	// NOTE threshold ratio of policy is changed after the blocks were stored
+	policy, err := isaac.NewLocalPolicy(cmd.networkID).SetThresholdRatio(60)
+	t.NoError(err)
+	cmd.policy = policy
+
+	t.corrupt(base.Height(1), block.BlockdataOperations)
+
+	t.NoError(cmd.verify())
+	t.True(cmd.repaired)
+	t.Equal([]base.Height{1}, cmd.damagedHeights())
+}
+
+func (t *testVerifyRepair) TestRepairCheckpoint() {
+	cmd := NewBlockdataVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	t.prepareBase(cmd.BaseVerifyCommand)
+	cmd.bd = t.local.Blockdata()
+	cmd.Path = t.local.Blockdata().(*localfs.Blockdata).Root()
+	cmd.Checkpoint = filepath.Join(t.T().TempDir(), "checkpoint.json")
+
+	t.corrupt(base.Height(1), block.BlockdataOperations)
+
+	t.NoError(cmd.verify())
+	t.True(cmd.repaired)
+	t.Equal([]base.Height{1}, cmd.damagedHeights())
+
+	// NOTE checkpoint is rewound before the repaired height
+	cp, err := loadVerifyCheckpoint(cmd.Checkpoint)
+	t.NoError(err)
+	t.Equal(base.GenesisHeight, cp.Verified)
+	t.Empty(cp.Damaged)
+}
+
+func (t *testVerifyRepair) TestRepairDatabase() {
+	cmd := NewDatabaseVerifyCommand(launch.EncoderTypes, launch.EncoderHinters)
+	t.prepareBase(cmd.BaseVerifyCommand)
+	cmd.database = t.local.Database()
+	cmd.blockdata = t.local.Blockdata()
+
+	t.NoError(os.Remove(t.blockFile(base.Height(1), block.BlockdataStates)))
+
+	t.NoError(cmd.verify())
+	t.Equal([]base.Height{1}, cmd.damagedHeights())
+
+	// NOTE verify again
+	cmd.damaged = map[base.Height]error{}
+	t.NoError(cmd.verify())
+	t.Empty(cmd.damagedHeights())
+
+	for i := base.PreGenesisHeight; i <= cmd.lastHeight; i++ {
+		a, found, err := t.local.Database().ManifestByHeight(i)
+		t.NoError(err)
+		t.True(found)
+
+		b, found, err := t.remote.Database().ManifestByHeight(i)
+		t.NoError(err)
+		t.True(found)
+
+		t.True(a.Hash().Equal(b.Hash()))
+	}
+}
+
+func TestVerifyRepair(t *testing.T) {
+	suite.Run(t, new(testVerifyRepair))
+}
@@ -2,22 +2,56 @@ package cmds
 
 import (
 	"context"
-	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+var (
+	DefaultVerifyWorkers   uint = 1
+	DefaultVerifyRangeSize uint = 1000
+	verifyManifestsLimit        = base.Height(50)
 )
 
 type BaseVerifyCommand struct {
 	*BaseCommand
-	NetworkID  NetworkIDFlag `name:"network-id"`
-	networkID  base.NetworkID
-	lastHeight base.Height
+	NetworkID   NetworkIDFlag `name:"network-id"`
+	Repair      bool          `name:"repair" help:"repair the damaged blocks from the suffrage nodes; default is false"`
+	Nodes       []*url.URL    `name:"node" help:"remote mitum url of suffrage node for repair; all the suffrage nodes should be given"` // nolint:lll
+	Threshold   float64       `name:"threshold" help:"threshold ratio of suffrage for repair; default is 100"`
+	Timeout     time.Duration `name:"timeout" help:"timeout for requesting suffrage node; default is 5 seconds"`
+	TLSInscure  bool          `name:"tls-insecure" help:"allow inseucre TLS connection; default is false"`
+	Checkpoint  string        `name:"checkpoint" help:"checkpoint file to resume verification"`
+	Workers     uint          `name:"workers" help:"number of height ranges verified in parallel; default is 1"`
+	RangeSize   uint          `name:"range-size" help:"number of heights in one range; default is 1000"`
+	Report      string        `name:"report" help:"write summary report in json to file; '-' is stdout"`
+	name        string
+	networkID   base.NetworkID
+	lastHeight  base.Height
+	channels    []network.Channel
+	policy      *isaac.LocalPolicy
+	suffrage    base.Suffrage
+	damagedLock sync.Mutex
+	damaged     map[base.Height]error
+	checkpoint  *verifyCheckpoint
+	target      string
+	from        base.Height
+	verified    base.Height
+	resumed     bool
+	repaired    bool
+	startedAt   time.Time
 }
 
 func NewBaseVerifyCommand(name string, types []hint.Type, hinters []hint.Hinter) *BaseVerifyCommand {
@@ -28,6 +62,13 @@ func NewBaseVerifyCommand(name string, types []hint.Type, hinters []hint.Hinter)
 
 	return &BaseVerifyCommand{
 		BaseCommand: b,
+		name:        name,
+		damaged:     map[base.Height]error{},
+		Workers:     DefaultVerifyWorkers,
+		RangeSize:   DefaultVerifyRangeSize,
+		from:        base.NilHeight,
+		verified:    base.NilHeight,
+		startedAt:   localtime.UTCNow(),
 	}
 }
 
@@ -42,39 +83,333 @@ func (cmd *BaseVerifyCommand) Initialize(flags interface{}, version util.Version
 		cmd.networkID = cmd.NetworkID.NetworkID()
 	}
 
+	cmd.startedAt = localtime.UTCNow()
+
+	if cmd.Workers < 1 {
+		cmd.Workers = DefaultVerifyWorkers
+	}
+
+	if cmd.RangeSize < 1 {
+		cmd.RangeSize = DefaultVerifyRangeSize
+	}
+
+	if _, err := cmd.loadCheckpoint(); err != nil {
+		return err
+	}
+
+	if cmd.Repair {
+		if err := cmd.prepareChannels(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *BaseVerifyCommand) prepareChannels() error {
+	if len(cmd.Nodes) < 1 {
+		return errors.Errorf("empty suffrage nodes for repair; set --node")
+	}
+
+	if cmd.Timeout < 1 {
+		cmd.Timeout = time.Second * 5
+	}
+
+	cmd.channels = make([]network.Channel, len(cmd.Nodes))
+	for i := range cmd.Nodes {
+		connInfo := network.NewHTTPConnInfo(network.NormalizeURL(cmd.Nodes[i]), cmd.TLSInscure)
+		ch, err := process.LoadNodeChannel(connInfo, cmd.Encoders(), cmd.Timeout)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load channel of suffrage node, %q", cmd.Nodes[i])
+		}
+		cmd.channels[i] = ch
+	}
+
+	cmd.Log().Debug().Int("channels", len(cmd.channels)).Msg("channels for repair loaded")
+
+	return cmd.prepareSuffrage()
+}
+
+// prepareSuffrage prepares the suffrage and policy to check the voteproofs of
+// the fetched blocks; the suffrage consists of the given suffrage nodes.
+func (cmd *BaseVerifyCommand) prepareSuffrage() error {
+	threshold := isaac.DefaultPolicyThresholdRatio
+	if cmd.Threshold > 0 {
+		threshold = base.ThresholdRatio(cmd.Threshold)
+	}
+
+	policy, err := isaac.NewLocalPolicy(cmd.networkID).SetThresholdRatio(threshold)
+	if err != nil {
+		return errors.Wrap(err, "invalid threshold")
+	}
+
+	nodes := make([]base.Address, len(cmd.channels))
+	for i := range cmd.channels {
+		ni, err := cmd.requestNodeInfo(cmd.channels[i])
+		if err != nil {
+			return errors.Wrapf(err, "failed to request node info of suffrage node, %q", cmd.Nodes[i])
+		}
+		nodes[i] = ni.Address()
+	}
+
+	suffrage := base.NewFixedSuffrage(nodes[0], nodes)
+	if err := suffrage.Initialize(); err != nil {
+		return err
+	}
+
+	cmd.policy = policy
+	cmd.suffrage = suffrage
+
+	cmd.Log().Debug().Interface("suffrage", nodes).Interface("threshold", threshold).Msg("suffrage for repair loaded")
+
 	return nil
 }
 
-func (cmd *BaseVerifyCommand) checkAllManifests(
+func (cmd *BaseVerifyCommand) requestNodeInfo(ch network.Channel) (network.NodeInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	ni, err := ch.NodeInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ni.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	if !ni.NetworkID().Equal(cmd.networkID) {
+		return nil, errors.Errorf("different network id, %q", ni.NetworkID())
+	}
+
+	return ni, nil
+}
+
+// setDamaged marks the height as damaged; the first error of height is kept.
+func (cmd *BaseVerifyCommand) setDamaged(height base.Height, err error) {
+	cmd.damagedLock.Lock()
+	defer cmd.damagedLock.Unlock()
+
+	if _, found := cmd.damaged[height]; !found {
+		cmd.damaged[height] = err
+	}
+}
+
+// damagedHeights returns the sorted damaged heights.
+func (cmd *BaseVerifyCommand) damagedHeights() []base.Height {
+	cmd.damagedLock.Lock()
+	defer cmd.damagedLock.Unlock()
+
+	heights := make([]base.Height, 0, len(cmd.damaged))
+	for height := range cmd.damaged {
+		heights = append(heights, height)
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	return heights
+}
+
+// hasDamaged checks whether damaged heights exist between s and e.
+func (cmd *BaseVerifyCommand) hasDamaged(s, e base.Height) bool {
+	cmd.damagedLock.Lock()
+	defer cmd.damagedLock.Unlock()
+
+	for height := range cmd.damaged {
+		if height >= s && height <= e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setRepaired rewinds the checkpoint before the repaired heights, so they are
+// verified again by the next run. The damaged heights are still reported, but
+// not kept in checkpoint.
+func (cmd *BaseVerifyCommand) setRepaired(from base.Height, get func(base.Height) (block.Manifest, error)) error {
+	cmd.repaired = true
+
+	if cmd.verified >= from {
+		cmd.verified = from - 1
+	}
+
+	return cmd.saveCheckpoint(cmd.verified, get)
+}
+
+func (cmd *BaseVerifyCommand) logDamaged() {
+	heights := cmd.damagedHeights()
+	if len(heights) < 1 {
+		return
+	}
+
+	cmd.damagedLock.Lock()
+	defer cmd.damagedLock.Unlock()
+
+	for i := range heights {
+		cmd.Log().Error().Err(cmd.damaged[heights[i]]).Int64("height", heights[i].Int64()).Msg("damaged block found")
+	}
+}
+
+// verifyHeights verifies the heights after the checkpoint by ranges; the
+// manifests of range are checked and every height of range is checked by
+// check. The ranges are verified in parallel by Workers and the checkpoint is
+// saved when all the lower ranges are verified.
+func (cmd *BaseVerifyCommand) verifyHeights(
 	get func(base.Height) (block.Manifest, error),
+	check func(base.Height) error,
 ) error {
-	cmd.Log().Info().Msg("checking manifests")
+	cmd.from = cmd.verified + 1
+	if cmd.from > cmd.lastHeight {
+		cmd.Log().Info().Int64("verified", cmd.verified.Int64()).Msg("already verified")
 
-	lh := cmd.lastHeight.Int64() + 1
-	limit := int64(50)
-	c := int64(math.Ceil(float64(lh) / float64(limit)))
+		return nil
+	}
 
+	ranges := verifyRanges(cmd.from, cmd.lastHeight, cmd.RangeSize)
+
+	cmd.Log().Info().
+		Ints64("heights", []int64{cmd.from.Int64(), cmd.lastHeight.Int64()}).
+		Int("ranges", len(ranges)).
+		Uint("workers", cmd.Workers).
+		Msg("verifying heights")
+
+	var lock sync.Mutex
+	done := make([]bool, len(ranges))
+	var next int
+	var failed error
+
+	doneRange := func(i int) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		done[i] = true
+
+		var verified base.Height = base.NilHeight
+		for ; next < len(done) && done[next]; next++ {
+			verified = ranges[next][1]
+		}
+
+		if verified == base.NilHeight {
+			return nil
+		}
+
+		cmd.verified = verified
+
+		return cmd.saveCheckpoint(verified, get)
+	}
+
+	wk := util.NewErrgroupWorker(context.Background(), int64(cmd.Workers))
+	defer wk.Close()
+
+	go func() {
+		defer wk.Done()
+
+		for i := range ranges {
+			i := i
+
+			if err := wk.NewJob(func(context.Context, uint64) error {
+				if err := cmd.verifyRange(ranges[i][0], ranges[i][1], get, check); err != nil {
+					lock.Lock()
+					failed = err
+					lock.Unlock()
+
+					// NOTE the failed range without damaged heights should be
+					// verified again by the next run.
+					if !cmd.hasDamaged(ranges[i][0], ranges[i][1]) {
+						return nil
+					}
+				}
+
+				return doneRange(i)
+			}); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := wk.Wait(); err != nil {
+		return err
+	}
+
+	return failed
+}
+
+func (cmd *BaseVerifyCommand) verifyRange(
+	s, e base.Height,
+	get func(base.Height) (block.Manifest, error),
+	check func(base.Height) error,
+) error {
 	var baseManifest block.Manifest
-	for i := int64(0); i < c; i++ {
-		s := i * limit
-		e := (i + 1) * limit
-		if i == 0 {
-			s = -1
-			e = limit
+	if s > base.PreGenesisHeight {
+		if i, err := get(s - 1); err == nil {
+			baseManifest = i
 		}
+	}
 
-		if e > lh {
-			e = lh
+	var failed error
+	for i := s; i <= e; i += verifyManifestsLimit {
+		j := i + verifyManifestsLimit
+		if j > e+1 {
+			j = e + 1
 		}
 
-		j, err := cmd.checkManifests(baseManifest, base.Height(s), base.Height(e), get)
+		m, err := cmd.checkManifests(baseManifest, i, j, get)
 		if err != nil {
-			return err
+			// NOTE keep checking the next manifests to find all the damaged
+			// heights
+			failed = err
 		}
-		baseManifest = j
+		baseManifest = m
 	}
 
-	return nil
+	wk := util.NewErrgroupWorker(context.Background(), 100)
+	defer wk.Close()
+
+	go func() {
+		defer wk.Done()
+
+		for i := s; i <= e; i++ {
+			height := i
+
+			if err := wk.NewJob(func(context.Context, uint64) error {
+				if err := check(height); err != nil {
+					cmd.Log().Error().Err(err).Int64("height", height.Int64()).Msg("failed to check height")
+
+					cmd.setDamaged(height, err)
+				}
+
+				return nil
+			}); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := wk.Wait(); err != nil {
+		return err
+	}
+
+	cmd.Log().Debug().Ints64("heights", []int64{s.Int64(), e.Int64()}).Msg("range verified")
+
+	return failed
+}
+
+// verifyRanges splits the heights into the ranges of size.
+func verifyRanges(from, to base.Height, size uint) [][2]base.Height {
+	var ranges [][2]base.Height
+	for s := from; s <= to; s += base.Height(size) {
+		e := s + base.Height(size) - 1
+		if e > to {
+			e = to
+		}
+
+		ranges = append(ranges, [2]base.Height{s, e})
+	}
+
+	return ranges
 }
 
 func (cmd *BaseVerifyCommand) checkManifests(
@@ -105,6 +440,12 @@ func (cmd *BaseVerifyCommand) checkManifests(
 		copy(manifests[1:], i)
 	}
 
+	for i := range manifests {
+		if manifests[i] == nil {
+			return nil, errors.Errorf("failed to load manifests, %d-%d", s, e)
+		}
+	}
+
 	l.Debug().Msg("manifests loaded")
 
 	checker := isaac.NewManifestsValidationChecker(cmd.networkID, manifests)
@@ -113,6 +454,8 @@ func (cmd *BaseVerifyCommand) checkManifests(
 	}).Check(); err != nil {
 		l.Error().Err(err).Msg("failed to verify manifests")
 
+		cmd.setDamagedManifests(manifests, err)
+
 		return nil, err
 	}
 
@@ -138,6 +481,8 @@ func (cmd *BaseVerifyCommand) loadManifests(
 			if err := wk.NewJob(func(context.Context, uint64) error {
 				if j, err := get(height); err != nil {
 					cmd.Log().Error().Err(err).Int64("height", height.Int64()).Msg("failed to load manifest")
+
+					cmd.setDamaged(height, err)
 				} else {
 					mch <- j
 				}
@@ -151,12 +496,31 @@ func (cmd *BaseVerifyCommand) loadManifests(
 
 	manifests := make([]block.Manifest, (e - s).Int64())
 
+	runch := wk.RunChan()
+
 	for {
 		select {
-		case err := <-wk.RunChan():
+		case err := <-runch:
 			return manifests, err
 		case i := <-mch:
 			manifests[(i.Height() - s).Int64()] = i
 		}
 	}
 }
+
+// setDamagedManifests finds the damaged manifest from the error of
+// ManifestsValidationChecker.
+func (cmd *BaseVerifyCommand) setDamagedManifests(manifests []block.Manifest, err error) {
+	var ierr *isaac.BlockIntegrityError
+	if errors.As(err, &ierr) {
+		cmd.setDamaged(ierr.From.Height()+1, err)
+
+		return
+	}
+
+	for i := range manifests {
+		if e := manifests[i].IsValid(cmd.networkID); e != nil {
+			cmd.setDamaged(manifests[i].Height(), e)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage/outbox"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
 )
@@ -105,6 +106,12 @@ func (cc *checker) CheckStorage() (bool, error) {
 		}
 	}
 
+	if conf.Outbox().RetryInterval() < 1 {
+		if err := conf.Outbox().SetRetryInterval(outbox.DefaultRetryInterval.String()); err != nil {
+			return false, err
+		}
+	}
+
 	return true, nil
 }
 
@@ -1,7 +1,14 @@
 package config
 
 import (
+	"net"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/isaac"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util/hint"
 )
 
 var (
@@ -14,17 +21,50 @@ type LocalConfig interface {
 	SetSyncInterval(string) error
 	TimeServer() string
 	SetTimeServer(string) error
+	MetricsBind() string
+	SetMetricsBind(string) error
+	EventsBind() string
+	SetEventsBind(string) error
+	MempoolTTL() time.Duration
+	SetMempoolTTL(string) error
+	MempoolSenderLimit() uint
+	SetMempoolSenderLimit(uint) error
+	MempoolSize() uint
+	SetMempoolSize(uint) error
+	MempoolPriorities() map[hint.Hint]uint64
+	SetMempoolPriorities(map[string]uint64) error
+	StuckEndure() time.Duration
+	SetStuckEndure(string) error
+	StuckRecoveryInterval() time.Duration
+	SetStuckRecoveryInterval(string) error
+	StuckRecoveryActions() []basicstate.StuckRecoveryAction
+	SetStuckRecoveryActions([]string) error
 }
 
 type DefaultLocalConfig struct {
 	syncInterval time.Duration
 	timeServer   string
+	metricsBind  string
+	eventsBind   string
+	mempoolTTL   time.Duration
+	mempoolSL    uint
+	mempoolSize  uint
+	mempoolPrs   map[hint.Hint]uint64
+	stuckEndure  time.Duration
+	stuckIntv    time.Duration
+	stuckActions []basicstate.StuckRecoveryAction
 }
 
 func EmptyDefaultLocalConfig() *DefaultLocalConfig {
 	return &DefaultLocalConfig{
 		syncInterval: DefaultSyncInterval,
 		timeServer:   DefaultTimeServer,
+		mempoolTTL:   isaac.DefaultMempoolTTL,
+		mempoolSL:    isaac.DefaultMempoolSenderLimit,
+		mempoolSize:  isaac.DefaultMempoolSize,
+		stuckEndure:  basicstate.DefaultStuckRecoveryEndure,
+		stuckIntv:    basicstate.DefaultStuckRecoveryInterval,
+		stuckActions: basicstate.DefaultStuckRecoveryActions,
 	}
 }
 
@@ -51,3 +91,164 @@ func (no *DefaultLocalConfig) SetTimeServer(s string) error {
 
 	return nil
 }
+
+// MetricsBind is the local address of metrics http server; if empty, metrics
+// server is disabled.
+func (no *DefaultLocalConfig) MetricsBind() string {
+	return no.metricsBind
+}
+
+func (no *DefaultLocalConfig) SetMetricsBind(s string) error {
+	if len(s) > 0 {
+		if _, err := net.ResolveTCPAddr("tcp", s); err != nil {
+			return errors.Wrapf(err, "invalid metrics bind, %q", s)
+		}
+	}
+
+	no.metricsBind = s
+
+	return nil
+}
+
+// EventsBind is the local address of events http server, which streams the
+// saved blocks; if empty, events server is disabled.
+func (no *DefaultLocalConfig) EventsBind() string {
+	return no.eventsBind
+}
+
+func (no *DefaultLocalConfig) SetEventsBind(s string) error {
+	if len(s) > 0 {
+		if _, err := net.ResolveTCPAddr("tcp", s); err != nil {
+			return errors.Wrapf(err, "invalid events bind, %q", s)
+		}
+	}
+
+	no.eventsBind = s
+
+	return nil
+}
+
+// MempoolTTL is the maximum duration of pending operation in mempool; zero
+// means no expiry.
+func (no *DefaultLocalConfig) MempoolTTL() time.Duration {
+	return no.mempoolTTL
+}
+
+func (no *DefaultLocalConfig) SetMempoolTTL(s string) error {
+	t, err := parseTimeDuration(s, true)
+	if err != nil {
+		return err
+	}
+
+	no.mempoolTTL = t
+
+	return nil
+}
+
+// MempoolSenderLimit is the maximum number of pending operations of one
+// sender; zero means no limit.
+func (no *DefaultLocalConfig) MempoolSenderLimit() uint {
+	return no.mempoolSL
+}
+
+func (no *DefaultLocalConfig) SetMempoolSenderLimit(i uint) error {
+	no.mempoolSL = i
+
+	return nil
+}
+
+// MempoolSize is the maximum number of pending operations; zero means no
+// limit.
+func (no *DefaultLocalConfig) MempoolSize() uint {
+	return no.mempoolSize
+}
+
+func (no *DefaultLocalConfig) SetMempoolSize(i uint) error {
+	no.mempoolSize = i
+
+	return nil
+}
+
+// MempoolPriorities is the priorities of operation types in mempool; the
+// operation, which is not in MempoolPriorities, has zero priority.
+func (no *DefaultLocalConfig) MempoolPriorities() map[hint.Hint]uint64 {
+	return no.mempoolPrs
+}
+
+func (no *DefaultLocalConfig) SetMempoolPriorities(m map[string]uint64) error {
+	prs := map[hint.Hint]uint64{}
+	for s, p := range m {
+		ht, err := hint.ParseHint(strings.TrimSpace(s))
+		if err != nil {
+			return errors.Wrapf(err, "invalid mempool priority hint, %q", s)
+		} else if err := ht.IsValid(nil); err != nil {
+			return errors.Wrapf(err, "invalid mempool priority hint, %q", s)
+		}
+
+		prs[ht] = p
+	}
+
+	no.mempoolPrs = prs
+
+	return nil
+}
+
+// StuckEndure is the duration, how long the last voteproof can be old; if
+// longer, consensus is regarded as stuck.
+func (no *DefaultLocalConfig) StuckEndure() time.Duration {
+	return no.stuckEndure
+}
+
+func (no *DefaultLocalConfig) SetStuckEndure(s string) error {
+	t, err := parseTimeDuration(s, false)
+	if err != nil {
+		return errors.Wrapf(err, "invalid stuck endure, %q", s)
+	} else if t <= 0 {
+		return errors.Errorf("invalid stuck endure, %q; should be positive", s)
+	}
+
+	no.stuckEndure = t
+
+	return nil
+}
+
+// StuckRecoveryInterval is the interval to try the recovery actions again
+// while consensus is stuck.
+func (no *DefaultLocalConfig) StuckRecoveryInterval() time.Duration {
+	return no.stuckIntv
+}
+
+func (no *DefaultLocalConfig) SetStuckRecoveryInterval(s string) error {
+	t, err := parseTimeDuration(s, false)
+	if err != nil {
+		return errors.Wrapf(err, "invalid stuck recovery interval, %q", s)
+	} else if t <= 0 {
+		return errors.Errorf("invalid stuck recovery interval, %q; should be positive", s)
+	}
+
+	no.stuckIntv = t
+
+	return nil
+}
+
+// StuckRecoveryActions is the recovery actions for stuck consensus; empty
+// actions only reports the stuck.
+func (no *DefaultLocalConfig) StuckRecoveryActions() []basicstate.StuckRecoveryAction {
+	return no.stuckActions
+}
+
+func (no *DefaultLocalConfig) SetStuckRecoveryActions(s []string) error {
+	actions := make([]basicstate.StuckRecoveryAction, len(s))
+	for i := range s {
+		actions[i] = basicstate.StuckRecoveryAction(strings.TrimSpace(s[i]))
+	}
+
+	policy := basicstate.NewStuckRecoveryPolicy(actions...)
+	if err := policy.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid stuck recovery actions")
+	}
+
+	no.stuckActions = actions
+
+	return nil
+}
@@ -1,17 +1,44 @@
 package config
 
 import (
+	basicstate "github.com/spikeekips/mitum/states/basic"
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 )
 
 type BaseLocalConfigJSONPacker struct {
-	SyncInterval string `json:"sync-interval,omitempty"`
-	TimeServer   string `json:"time_server,omitempty"`
+	SyncInterval string                           `json:"sync-interval,omitempty"`
+	TimeServer   string                           `json:"time_server,omitempty"`
+	MetricsBind  string                           `json:"metrics_bind,omitempty"`
+	EventsBind   string                           `json:"events_bind,omitempty"`
+	MempoolTTL   string                           `json:"mempool_ttl,omitempty"`
+	MempoolSL    uint                             `json:"mempool_sender_limit"`
+	MempoolSize  uint                             `json:"mempool_size"`
+	MempoolPrs   map[string]uint64                `json:"mempool_priorities,omitempty"`
+	StuckEndure  string                           `json:"stuck_endure,omitempty"`
+	StuckIntv    string                           `json:"stuck_recovery_interval,omitempty"`
+	StuckActions []basicstate.StuckRecoveryAction `json:"stuck_recovery_actions"`
 }
 
 func (no DefaultLocalConfig) MarshalJSON() ([]byte, error) {
+	var prs map[string]uint64
+	if len(no.mempoolPrs) > 0 {
+		prs = map[string]uint64{}
+		for ht, p := range no.mempoolPrs {
+			prs[ht.String()] = p
+		}
+	}
+
 	return jsonenc.Marshal(BaseLocalConfigJSONPacker{
 		SyncInterval: no.syncInterval.String(),
 		TimeServer:   no.timeServer,
+		MetricsBind:  no.metricsBind,
+		EventsBind:   no.eventsBind,
+		MempoolTTL:   no.mempoolTTL.String(),
+		MempoolSL:    no.mempoolSL,
+		MempoolSize:  no.mempoolSize,
+		MempoolPrs:   prs,
+		StuckEndure:  no.stuckEndure.String(),
+		StuckIntv:    no.stuckIntv.String(),
+		StuckActions: no.stuckActions,
 	})
 }
@@ -5,11 +5,15 @@ import "time"
 type BaseLocalConfigYAMLPacker struct {
 	SyncInterval time.Duration `yaml:"sync-interval,omitempty"`
 	TimeServer   string        `yaml:"time-server,omitempty"`
+	MetricsBind  string        `yaml:"metrics-bind,omitempty"`
+	EventsBind   string        `yaml:"events-bind,omitempty"`
 }
 
 func (no DefaultLocalConfig) MarshalYAML() (interface{}, error) {
 	return BaseLocalConfigYAMLPacker{
 		SyncInterval: no.syncInterval,
 		TimeServer:   no.timeServer,
+		MetricsBind:  no.metricsBind,
+		EventsBind:   no.eventsBind,
 	}, nil
 }
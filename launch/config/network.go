@@ -96,7 +96,7 @@ func (no BaseLocalNetwork) Cache() *url.URL {
 func (no *BaseLocalNetwork) SetCache(s string) error {
 	if u, err := network.ParseURL(s, true); err != nil {
 		return err
-	} else if _, err := cache.NewCacheFromURI(u.String()); err != nil {
+	} else if err := cache.CheckURI(u.String()); err != nil {
 		return err
 	} else {
 		no.cache = u
@@ -112,7 +112,7 @@ func (no BaseLocalNetwork) SealCache() *url.URL {
 func (no *BaseLocalNetwork) SetSealCache(s string) error {
 	if u, err := network.ParseURL(s, true); err != nil {
 		return err
-	} else if _, err := cache.NewCacheFromURI(u.String()); err != nil {
+	} else if err := cache.CheckURI(u.String()); err != nil {
 		return err
 	} else {
 		no.sealCache = u
@@ -12,27 +12,51 @@ import (
 )
 
 var RateLimitHandlerMap = map[string]string{
-	"operations":     quicnetwork.QuicHandlerPathGetStagedOperations,
-	"send-seal":      quicnetwork.QuicHandlerPathSendSeal,
-	"blockdata-maps": quicnetwork.QuicHandlerPathGetBlockdataMaps,
-	"blockdata":      quicnetwork.QuicHandlerPathGetBlockdataPattern,
-	"node-info":      quicnetwork.QuicHandlerPathNodeInfo,
+	"operations":         quicnetwork.QuicHandlerPathGetStagedOperations,
+	"send-seal":          quicnetwork.QuicHandlerPathSendSeal,
+	"blockdata-maps":     quicnetwork.QuicHandlerPathGetBlockdataMaps,
+	"blockdata":          quicnetwork.QuicHandlerPathGetBlockdataPattern,
+	"node-info":          quicnetwork.QuicHandlerPathNodeInfo,
+	"state":              quicnetwork.QuicHandlerPathGetStatePattern,
+	"proof-operation":    quicnetwork.QuicHandlerPathGetOperationProofPattern,
+	"proof-state":        quicnetwork.QuicHandlerPathGetStatePathProofPattern,
+	"pending-operations": quicnetwork.QuicHandlerPathGetPendingOperations,
+	"operation-status":   quicnetwork.QuicHandlerPathGetOperationStatusPattern,
+	"operations-signer":  quicnetwork.QuicHandlerPathGetOperationsBySignerPattern,
+	"operations-hint":    quicnetwork.QuicHandlerPathGetOperationsByHintPattern,
+	"equivocations":      quicnetwork.QuicHandlerPathGetEquivocations,
 }
 
 var DefaultWorldRateLimit = map[string]limiter.Rate{
-	"operations":     {Period: time.Second * 10, Limit: 30},
-	"send-seal":      {Period: time.Second * 10, Limit: 100},
-	"blockdata-maps": {Period: time.Minute * 1, Limit: 60 * 9},
-	"blockdata":      {Period: time.Minute * 1, Limit: 60 * 9},
-	"node-info":      {Period: time.Second * 10, Limit: 10},
+	"operations":         {Period: time.Second * 10, Limit: 30},
+	"send-seal":          {Period: time.Second * 10, Limit: 100},
+	"blockdata-maps":     {Period: time.Minute * 1, Limit: 60 * 9},
+	"blockdata":          {Period: time.Minute * 1, Limit: 60 * 9},
+	"node-info":          {Period: time.Second * 10, Limit: 10},
+	"state":              {Period: time.Second * 10, Limit: 30},
+	"proof-operation":    {Period: time.Second * 10, Limit: 30},
+	"proof-state":        {Period: time.Second * 10, Limit: 30},
+	"pending-operations": {Period: time.Second * 10, Limit: 10},
+	"operation-status":   {Period: time.Second * 10, Limit: 30},
+	"operations-signer":  {Period: time.Second * 10, Limit: 10},
+	"operations-hint":    {Period: time.Second * 10, Limit: 10},
+	"equivocations":      {Period: time.Second * 10, Limit: 10},
 }
 
 var DefaultSuffrageRateLimit = map[string]limiter.Rate{
-	"operations":     {Period: time.Second * 10, Limit: 100},
-	"send-seal":      {Period: time.Second * 10, Limit: 1000},
-	"blockdata-maps": {Period: time.Second * 10, Limit: 1000},
-	"blockdata":      {Period: time.Second * 10, Limit: 1000},
-	"node-info":      {Period: time.Second * 10, Limit: 50},
+	"operations":         {Period: time.Second * 10, Limit: 100},
+	"send-seal":          {Period: time.Second * 10, Limit: 1000},
+	"blockdata-maps":     {Period: time.Second * 10, Limit: 1000},
+	"blockdata":          {Period: time.Second * 10, Limit: 1000},
+	"node-info":          {Period: time.Second * 10, Limit: 50},
+	"state":              {Period: time.Second * 10, Limit: 100},
+	"proof-operation":    {Period: time.Second * 10, Limit: 100},
+	"proof-state":        {Period: time.Second * 10, Limit: 100},
+	"pending-operations": {Period: time.Second * 10, Limit: 50},
+	"operation-status":   {Period: time.Second * 10, Limit: 100},
+	"operations-signer":  {Period: time.Second * 10, Limit: 50},
+	"operations-hint":    {Period: time.Second * 10, Limit: 50},
+	"equivocations":      {Period: time.Second * 10, Limit: 50},
 }
 
 var DefaultRateLimitTargetRules []RateLimitTargetRule
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage/outbox"
 	"github.com/spikeekips/mitum/util/cache"
 )
 
@@ -32,10 +35,13 @@ func init() {
 type Blockdata interface {
 	Path() string
 	SetPath(string) error
+	URI() *url.URL
+	SetURI(string) error
 }
 
 type BaseBlockdata struct {
 	path string
+	uri  *url.URL
 }
 
 func (no BaseBlockdata) Path() string {
@@ -48,6 +54,31 @@ func (no *BaseBlockdata) SetPath(s string) error {
 	return nil
 }
 
+// URI is the uri of remote block data storage like s3. If URI is nil, block
+// data is stored under Path.
+func (no BaseBlockdata) URI() *url.URL {
+	return no.uri
+}
+
+func (no *BaseBlockdata) SetURI(s string) error {
+	u, err := network.ParseURL(s, true)
+	if err != nil {
+		return err
+	}
+
+	if u != nil {
+		switch u.Scheme {
+		case "s3", "s3+http":
+		default:
+			return errors.Errorf("unsupported blockdata uri, %q", u.Scheme)
+		}
+	}
+
+	no.uri = u
+
+	return nil
+}
+
 type Database interface {
 	URI() *url.URL
 	SetURI(string) error
@@ -81,7 +112,7 @@ func (no BaseDatabase) Cache() *url.URL {
 func (no *BaseDatabase) SetCache(s string) error {
 	if u, err := network.ParseURL(s, true); err != nil {
 		return err
-	} else if _, err := cache.NewCacheFromURI(u.String()); err != nil {
+	} else if err := cache.CheckURI(u.String()); err != nil {
 		return err
 	} else {
 		no.cache = u
@@ -90,16 +121,70 @@ func (no *BaseDatabase) SetCache(s string) error {
 	}
 }
 
+// Outbox is the sinks, which receive the saved blocks from outbox.
+type Outbox interface {
+	Sinks() map[string]*url.URL
+	SetSinks(map[string]string) error
+	RetryInterval() time.Duration
+	SetRetryInterval(string) error
+}
+
+type BaseOutbox struct {
+	sinks         map[string]*url.URL
+	retryInterval time.Duration
+}
+
+func (no BaseOutbox) Sinks() map[string]*url.URL {
+	return no.sinks
+}
+
+func (no *BaseOutbox) SetSinks(m map[string]string) error {
+	sinks := map[string]*url.URL{}
+	for name := range m {
+		u, err := network.ParseURL(m[name], false)
+		if err != nil {
+			return errors.Wrapf(err, "invalid outbox sink, %q", name)
+		}
+
+		if _, err := outbox.NewSinkFromURI(name, u); err != nil {
+			return err
+		}
+
+		sinks[name] = u
+	}
+
+	no.sinks = sinks
+
+	return nil
+}
+
+func (no BaseOutbox) RetryInterval() time.Duration {
+	return no.retryInterval
+}
+
+func (no *BaseOutbox) SetRetryInterval(s string) error {
+	t, err := parseTimeDuration(s, true)
+	if err != nil {
+		return err
+	}
+	no.retryInterval = t
+
+	return nil
+}
+
 type Storage interface {
 	Database() Database
 	SetDatabase(Database) error
 	Blockdata() Blockdata
 	SetBlockdata(Blockdata) error
+	Outbox() Outbox
+	SetOutbox(Outbox) error
 }
 
 type BaseStorage struct {
 	database  Database
 	blockdata Blockdata
+	outbox    Outbox
 }
 
 func EmptyBaseStorage() *BaseStorage {
@@ -108,6 +193,7 @@ func EmptyBaseStorage() *BaseStorage {
 			cache: DefaultDatabaseCacheURL,
 		},
 		blockdata: &BaseBlockdata{},
+		outbox:    &BaseOutbox{},
 	}
 }
 
@@ -130,3 +216,27 @@ func (no *BaseStorage) SetBlockdata(bs Blockdata) error {
 
 	return nil
 }
+
+func (no BaseStorage) Outbox() Outbox {
+	return no.outbox
+}
+
+func (no *BaseStorage) SetOutbox(ob Outbox) error {
+	no.outbox = ob
+
+	return nil
+}
+
+func redactedOutboxSinks(ob Outbox) map[string]string {
+	sinks := ob.Sinks()
+	if len(sinks) < 1 {
+		return nil
+	}
+
+	m := map[string]string{}
+	for name := range sinks {
+		m[name] = sinks[name].Redacted()
+	}
+
+	return m
+}
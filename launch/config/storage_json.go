@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 )
 
@@ -11,14 +13,26 @@ type DatabasePackerJSON struct {
 
 type BlockdataPackerJSON struct {
 	Path string `json:"path"`
+	URI  string `json:"uri,omitempty"`
+}
+
+type OutboxPackerJSON struct {
+	Sinks         map[string]string `json:"sinks,omitempty"`
+	RetryInterval time.Duration     `json:"retry_interval"`
 }
 
 type BaseStoragePackerJSON struct {
 	Database  DatabasePackerJSON  `json:"database"`
 	Blockdata BlockdataPackerJSON `json:"blockdata"`
+	Outbox    OutboxPackerJSON    `json:"outbox"`
 }
 
 func (no BaseStorage) MarshalJSON() ([]byte, error) {
+	var bdURI string
+	if u := no.blockdata.URI(); u != nil {
+		bdURI = u.Redacted()
+	}
+
 	return jsonenc.Marshal(BaseStoragePackerJSON{
 		Database: DatabasePackerJSON{
 			URI:   no.database.URI().String(),
@@ -26,6 +40,11 @@ func (no BaseStorage) MarshalJSON() ([]byte, error) {
 		},
 		Blockdata: BlockdataPackerJSON{
 			Path: no.blockdata.Path(),
+			URI:  bdURI,
+		},
+		Outbox: OutboxPackerJSON{
+			Sinks:         redactedOutboxSinks(no.outbox),
+			RetryInterval: no.outbox.RetryInterval(),
 		},
 	})
 }
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type DatabasePackerYAML struct {
 	URI   string `yaml:",omitempty"`
 	Cache string `yaml:",omitempty"`
@@ -7,14 +9,26 @@ type DatabasePackerYAML struct {
 
 type BlockdataPackerYAML struct {
 	Path string
+	URI  string `yaml:",omitempty"`
+}
+
+type OutboxPackerYAML struct {
+	Sinks         map[string]string `yaml:",omitempty"`
+	RetryInterval time.Duration     `yaml:"retry-interval,omitempty"`
 }
 
 type BaseStoragePackerYAML struct {
 	Database  DatabasePackerYAML  `yaml:"database"`
 	Blockdata BlockdataPackerYAML `yaml:"blockdata"`
+	Outbox    OutboxPackerYAML    `yaml:"outbox,omitempty"`
 }
 
 func (no BaseStorage) MarshalYAML() (interface{}, error) {
+	var bdURI string
+	if u := no.blockdata.URI(); u != nil {
+		bdURI = u.Redacted()
+	}
+
 	return BaseStoragePackerYAML{
 		Database: DatabasePackerYAML{
 			URI:   no.database.URI().String(),
@@ -22,6 +36,11 @@ func (no BaseStorage) MarshalYAML() (interface{}, error) {
 		},
 		Blockdata: BlockdataPackerYAML{
 			Path: no.blockdata.Path(),
+			URI:  bdURI,
+		},
+		Outbox: OutboxPackerYAML{
+			Sinks:         redactedOutboxSinks(no.outbox),
+			RetryInterval: no.outbox.RetryInterval(),
 		},
 	}, nil
 }
@@ -102,3 +102,87 @@ func (fd RoundrobinSuffrage) IsValid([]byte) error {
 
 	return nil
 }
+
+// DefaultWeightedSuffrageStateKey is the state key of the suffrage weights.
+var DefaultWeightedSuffrageStateKey = "suffrage-weights"
+
+type WeightedSuffrage struct {
+	nodes     []base.Address
+	weights   map[string]uint // {node address: weight}
+	StateKey  string
+	CacheSize int
+}
+
+func NewWeightedSuffrage(nodes []base.Address, weights map[string]uint) WeightedSuffrage {
+	return WeightedSuffrage{
+		nodes:     nodes,
+		weights:   weights,
+		StateKey:  DefaultWeightedSuffrageStateKey,
+		CacheSize: defaultCacheSize,
+	}
+}
+
+func (WeightedSuffrage) SuffrageType() string {
+	return "weighted"
+}
+
+func (fd WeightedSuffrage) Nodes() []base.Address {
+	return fd.nodes
+}
+
+// NumberOfActing of WeightedSuffrage is the number of genesis nodes; all the
+// suffrage nodes are acting.
+func (fd WeightedSuffrage) NumberOfActing() uint {
+	return uint(len(fd.nodes))
+}
+
+// Weights returns the genesis SuffrageWeights. If weight of node is not
+// given, it's weight is 1.
+func (fd WeightedSuffrage) Weights() (base.SuffrageWeights, error) {
+	m := map[base.Address]uint{}
+	for i := range fd.nodes {
+		n := fd.nodes[i]
+
+		w, found := fd.weights[n.String()]
+		if !found {
+			w = 1
+		}
+
+		m[n] = w
+	}
+
+	sw := base.NewSuffrageWeights(m)
+
+	return sw, sw.IsValid(nil)
+}
+
+func (fd WeightedSuffrage) IsValid([]byte) error {
+	if len(fd.nodes) < 1 {
+		return isvalid.InvalidError.Errorf("empty nodes in weighted suffrage")
+	}
+
+	if len(fd.StateKey) < 1 {
+		return isvalid.InvalidError.Errorf("empty state key in weighted suffrage")
+	}
+
+	for k := range fd.weights {
+		var found bool
+		for i := range fd.nodes {
+			if fd.nodes[i].String() == k {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return isvalid.InvalidError.Errorf("unknown node, %q found in weights of weighted suffrage", k)
+		}
+	}
+
+	if _, err := fd.Weights(); err != nil {
+		return isvalid.InvalidError.Wrap(err)
+	}
+
+	return nil
+}
@@ -8,8 +8,21 @@ import (
 )
 
 type LocalConfig struct {
-	SyncInterval *string `yaml:"sync-interval"`
-	TimeServer   *string `yaml:"time-server,omitempty"`
+	SyncInterval  *string           `yaml:"sync-interval"`
+	TimeServer    *string           `yaml:"time-server,omitempty"`
+	MetricsBind   *string           `yaml:"metrics-bind,omitempty"`
+	EventsBind    *string           `yaml:"events-bind,omitempty"`
+	MempoolTTL    *string           `yaml:"mempool-ttl,omitempty"`
+	MempoolSL     *uint             `yaml:"mempool-sender-limit,omitempty"`
+	MempoolSize   *uint             `yaml:"mempool-size,omitempty"`
+	MempoolPrs    map[string]uint64 `yaml:"mempool-priorities,omitempty"`
+	StuckRecovery *StuckRecovery    `yaml:"stuck-recovery,omitempty"`
+}
+
+type StuckRecovery struct {
+	Endure   *string   `yaml:"endure,omitempty"`
+	Interval *string   `yaml:"interval,omitempty"`
+	Actions  *[]string `yaml:"actions,omitempty"`
 }
 
 func (no LocalConfig) Set(ctx context.Context) (context.Context, error) {
@@ -31,5 +44,69 @@ func (no LocalConfig) Set(ctx context.Context) (context.Context, error) {
 		}
 	}
 
+	if no.MetricsBind != nil {
+		if err := conf.SetMetricsBind(strings.TrimSpace(*no.MetricsBind)); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.EventsBind != nil {
+		if err := conf.SetEventsBind(strings.TrimSpace(*no.EventsBind)); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.MempoolTTL != nil {
+		if err := conf.SetMempoolTTL(*no.MempoolTTL); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.MempoolSL != nil {
+		if err := conf.SetMempoolSenderLimit(*no.MempoolSL); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.MempoolSize != nil {
+		if err := conf.SetMempoolSize(*no.MempoolSize); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.MempoolPrs != nil {
+		if err := conf.SetMempoolPriorities(no.MempoolPrs); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.StuckRecovery != nil {
+		if err := no.StuckRecovery.set(conf); err != nil {
+			return ctx, err
+		}
+	}
+
 	return ctx, nil
 }
+
+func (no StuckRecovery) set(conf config.LocalConfig) error {
+	if no.Endure != nil {
+		if err := conf.SetStuckEndure(*no.Endure); err != nil {
+			return err
+		}
+	}
+
+	if no.Interval != nil {
+		if err := conf.SetStuckRecoveryInterval(*no.Interval); err != nil {
+			return err
+		}
+	}
+
+	if no.Actions != nil {
+		if err := conf.SetStuckRecoveryActions(*no.Actions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
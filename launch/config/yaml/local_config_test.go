@@ -3,6 +3,8 @@ package yamlconfig
 import (
 	"testing"
 
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/yaml.v3"
 )
@@ -45,6 +47,75 @@ sync-interval: 3s
 	t.Equal("3s", *n.SyncInterval)
 }
 
+func (t *testLocalConfig) TestMetricsBind() {
+	y := `
+metrics-bind: 127.0.0.1:9090
+`
+
+	var n LocalConfig
+	err := yaml.Unmarshal([]byte(y), &n)
+	t.NoError(err)
+
+	t.Equal("127.0.0.1:9090", *n.MetricsBind)
+}
+
+func (t *testLocalConfig) TestEventsBind() {
+	y := `
+events-bind: 127.0.0.1:9091
+`
+
+	var n LocalConfig
+	err := yaml.Unmarshal([]byte(y), &n)
+	t.NoError(err)
+
+	t.Equal("127.0.0.1:9091", *n.EventsBind)
+}
+
+func (t *testLocalConfig) TestMempool() {
+	y := `
+mempool-ttl: 10m
+mempool-sender-limit: 3
+mempool-size: 0
+mempool-priorities:
+  showme-v0.0.1: 3
+`
+
+	var n LocalConfig
+	err := yaml.Unmarshal([]byte(y), &n)
+	t.NoError(err)
+
+	t.Equal("10m", *n.MempoolTTL)
+	t.Equal(uint(3), *n.MempoolSL)
+	t.Equal(uint(0), *n.MempoolSize)
+	t.Equal(map[string]uint64{"showme-v0.0.1": 3}, n.MempoolPrs)
+
+	conf := config.EmptyDefaultLocalConfig()
+	t.NoError(conf.SetMempoolPriorities(n.MempoolPrs))
+	t.Equal(map[hint.Hint]uint64{hint.NewHint(hint.Type("showme"), "v0.0.1"): 3}, conf.MempoolPriorities())
+
+	t.Error(conf.SetMempoolPriorities(map[string]uint64{"showme": 3}))
+}
+
+func (t *testLocalConfig) TestStuckRecovery() {
+	y := `
+stuck-recovery:
+  endure: 2m
+  interval: 10s
+  actions:
+    - fetch-voteproofs
+    - alert
+`
+
+	var n LocalConfig
+	err := yaml.Unmarshal([]byte(y), &n)
+	t.NoError(err)
+
+	t.NotNil(n.StuckRecovery)
+	t.Equal("2m", *n.StuckRecovery.Endure)
+	t.Equal("10s", *n.StuckRecovery.Interval)
+	t.Equal([]string{"fetch-voteproofs", "alert"}, *n.StuckRecovery.Actions)
+}
+
 func TestLocalConfig(t *testing.T) {
 	suite.Run(t, new(testLocalConfig))
 }
@@ -8,6 +8,7 @@ import (
 
 type Blockdata struct {
 	Path *string
+	URI  *string `yaml:",omitempty"`
 }
 
 type Database struct {
@@ -36,9 +37,15 @@ func (no Database) Set(ctx context.Context) (context.Context, error) {
 	return ctx, nil
 }
 
+type Outbox struct {
+	Sinks         map[string]string `yaml:",omitempty"`
+	RetryInterval *string           `yaml:"retry-interval,omitempty"`
+}
+
 type Storage struct {
 	Database  *Database  `yaml:"database,omitempty"`
 	Blockdata *Blockdata `yaml:"blockdata,omitempty"`
+	Outbox    *Outbox    `yaml:"outbox,omitempty"`
 }
 
 func (no Storage) Set(ctx context.Context) (context.Context, error) {
@@ -62,6 +69,26 @@ func (no Storage) Set(ctx context.Context) (context.Context, error) {
 				return ctx, err
 			}
 		}
+
+		if no.Blockdata.URI != nil {
+			if err := conf.Blockdata().SetURI(*no.Blockdata.URI); err != nil {
+				return ctx, err
+			}
+		}
+	}
+
+	if no.Outbox != nil {
+		if no.Outbox.Sinks != nil {
+			if err := conf.Outbox().SetSinks(no.Outbox.Sinks); err != nil {
+				return ctx, err
+			}
+		}
+
+		if no.Outbox.RetryInterval != nil {
+			if err := conf.Outbox().SetRetryInterval(*no.Outbox.RetryInterval); err != nil {
+				return ctx, err
+			}
+		}
 	}
 
 	return ctx, nil
@@ -56,10 +56,12 @@ func NewSetBlockdataMapsHandler(
 			i++
 		}
 
-		b, err := jsonenc.Marshal(map[string]interface{}{
-			"heights":       heights,
-			"expected_time": localtime.UTCNow().Add(bc.RemoveAfter()),
-		})
+		m := map[string]interface{}{"heights": heights}
+		if bc != nil {
+			m["expected_time"] = localtime.UTCNow().Add(bc.RemoveAfter())
+		}
+
+		b, err := jsonenc.Marshal(m)
 		if err != nil {
 			network.WriteProblemWithError(w, http.StatusInternalServerError, err)
 			return
@@ -153,7 +155,7 @@ func commitBlockdataMaps(db storage.Database, bc *BlockdataCleaner, bdms []block
 
 		for i := range bdms {
 			bdm := bdms[i]
-			if bdm.IsLocal() { // NOTE local blockdata will not be removed
+			if bc == nil || bdm.IsLocal() { // NOTE local blockdata will not be removed
 				continue
 			}
 
@@ -2,8 +2,8 @@ package deploy
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/storage/blockdata"
@@ -24,7 +24,10 @@ func HookBlockdataCleaner(ctx context.Context) (context.Context, error) {
 	if err := process.LoadBlockdataContextValue(ctx, &bd); err != nil {
 		return ctx, err
 	} else if i, ok := bd.(*localfs.Blockdata); !ok {
-		return ctx, errors.Errorf("to clean blockdata, needs localfs.Blockdata, not %T", bd)
+		// NOTE remote blockdata, like s3 does not have local files to be cleaned
+		log.Log().Debug().Str("blockdata", fmt.Sprintf("%T", bd)).Msg("BlockdataCleaner disabled")
+
+		return ctx, nil
 	} else {
 		lbd = i
 	}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/network"
 	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
@@ -56,7 +58,9 @@ func hookDefaultDeployHandlers(ctx context.Context, qnt *quicnetwork.Server) (co
 
 	var bc *BlockdataCleaner
 	if err := LoadBlockdataCleanerContextValue(ctx, &bc); err != nil {
-		return ctx, err
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return ctx, err
+		}
 	}
 
 	setBlockdataMapsHandler := http.HandlerFunc(NewSetBlockdataMapsHandler(qnt.Encoder(), db, bc))
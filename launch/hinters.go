@@ -5,9 +5,12 @@ import (
 	"github.com/spikeekips/mitum/base/ballot"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/membership"
 	"github.com/spikeekips/mitum/base/node"
 	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/policy"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/tree"
@@ -18,20 +21,28 @@ var EncoderTypes = []hint.Type{
 	base.ACCEPTBallotType,
 	base.BallotFactSignType,
 	base.BaseFactSignType,
+	base.EquivocationType,
 	base.INITBallotFactType,
 	base.INITBallotType,
 	base.ProposalFactType,
 	base.ProposalType,
 	base.SignedBallotFactType,
 	base.StringAddressType,
+	base.SuffrageWeightsType,
 	base.VoteproofV0Type,
 	block.BaseBlockdataMapType,
 	block.BlockConsensusInfoV0Type,
 	block.BlockV0Type,
 	block.ManifestV0Type,
 	block.SuffrageInfoV0Type,
+	isaac.SnapshotV0Type,
+	isaac.SnapshotStateV0Type,
 	key.BasePrivatekeyType,
 	key.BasePublickeyType,
+	membership.JoinSuffrageFactType,
+	membership.JoinSuffrageType,
+	membership.LeaveSuffrageFactType,
+	membership.LeaveSuffrageType,
 	network.EndHandoverSealV0Type,
 	network.HTTPConnInfoType,
 	network.NilConnInfoType,
@@ -39,10 +50,18 @@ var EncoderTypes = []hint.Type{
 	network.PingHandoverSealV0Type,
 	network.ProblemType,
 	network.StartHandoverSealV0Type,
+	network.OperationProofType,
+	network.StatePathProofType,
+	network.PendingOperationType,
+	network.OperationsPageType,
 	node.BaseV0Type,
 	operation.BaseReasonErrorType,
 	operation.FixedTreeNodeType,
+	operation.StatusType,
 	operation.SealType,
+	policy.PolicyV0Type,
+	policy.SetPolicyFactType,
+	policy.SetPolicyType,
 	state.BytesValueType,
 	state.DurationValueType,
 	state.HintedValueType,
@@ -63,16 +82,24 @@ var EncoderHinters = []hint.Hinter{
 	ballot.ProposalHinter,
 	base.BallotFactSignHinter,
 	base.BaseFactSignHinter,
+	base.EquivocationV0Hinter,
 	base.SignedBallotFactHinter,
 	base.StringAddressHinter,
+	base.SuffrageWeightsHinter,
 	base.VoteproofV0Hinter,
 	block.BaseBlockdataMapHinter,
 	block.BlockV0Hinter,
 	block.BlockConsensusInfoV0Hinter,
 	block.ManifestV0Hinter,
 	block.SuffrageInfoV0Hinter,
+	isaac.SnapshotV0Hinter,
+	isaac.SnapshotStateV0Hinter,
 	key.BasePrivatekey{},
 	key.BasePublickey{},
+	membership.JoinSuffrageFactHinter,
+	membership.JoinSuffrageHinter,
+	membership.LeaveSuffrageFactHinter,
+	membership.LeaveSuffrageHinter,
 	network.EndHandoverSealV0Hinter,
 	network.HTTPConnInfoHinter,
 	network.NilConnInfoHinter,
@@ -80,10 +107,18 @@ var EncoderHinters = []hint.Hinter{
 	network.PingHandoverSealV0Hinter,
 	network.ProblemHinter,
 	network.StartHandoverSealV0Hinter,
+	network.OperationProofV0Hinter,
+	network.StatePathProofV0Hinter,
+	network.PendingOperationV0Hinter,
+	network.OperationsPageV0Hinter,
 	node.BaseV0Hinter,
 	operation.BaseReasonError{},
 	operation.FixedTreeNodeHinter,
+	operation.StatusV0Hinter,
 	operation.SealHinter,
+	policy.PolicyV0Hinter,
+	policy.SetPolicyFactHinter,
+	policy.SetPolicyHinter,
 	state.BytesValueHinter,
 	state.DurationValueHinter,
 	state.FixedTreeNodeHinter,
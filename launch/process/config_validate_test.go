@@ -559,6 +559,69 @@ suffrage:
 	t.IsType(config.RoundrobinSuffrage{}, conf.Suffrage())
 }
 
+func (t *testConfigValidator) TestWeighted() {
+	y := `
+address: n0sas
+
+nodes:
+  - address: n1sas
+    publickey: soGEtYqyFcKwwdU9FpeqywSMqRYbuwWhdeoREAgWYjU8mpu
+
+suffrage:
+  type: weighted
+  state-key: showme
+  nodes:
+    - n0sas
+    - n1sas
+  weights:
+    n0sas: 3
+`
+	ctx := t.loadConfig(y)
+
+	ctx, err := HookSuffrageConfigFunc(DefaultHookHandlersSuffrageConfig)(ctx)
+	t.NoError(err)
+
+	va, err := config.NewValidator(ctx)
+	t.NoError(err)
+	_, err = va.CheckSuffrage()
+	t.NoError(err)
+
+	var conf config.LocalNode
+	t.NoError(config.LoadConfigContextValue(ctx, &conf))
+
+	t.IsType(config.WeightedSuffrage{}, conf.Suffrage())
+
+	ws := conf.Suffrage().(config.WeightedSuffrage)
+	t.Equal("showme", ws.StateKey)
+
+	sw, err := ws.Weights()
+	t.NoError(err)
+	t.Equal(uint(4), sw.Total())
+	t.Equal(uint(3), sw.Weight(conf.Address()))
+}
+
+func (t *testConfigValidator) TestWeightedUnknownNodeInWeights() {
+	y := `
+address: n0sas
+
+suffrage:
+  type: weighted
+  nodes:
+    - n0sas
+  weights:
+    n1sas: 3
+`
+	ctx := t.loadConfig(y)
+
+	ctx, err := HookSuffrageConfigFunc(DefaultHookHandlersSuffrageConfig)(ctx)
+	t.NoError(err)
+
+	va, err := config.NewValidator(ctx)
+	t.NoError(err)
+	_, err = va.CheckSuffrage()
+	t.Contains(err.Error(), "unknown node")
+}
+
 func (t *testConfigValidator) TestEmptyProposalProcessor() {
 	y := `
 proposal-processor:
@@ -761,6 +824,42 @@ sync-interval: 9s
 	t.Equal(time.Second*9, conf.LocalConfig().SyncInterval())
 }
 
+func (t *testConfigValidator) TestLocalConfigMetricsBind() {
+	y := `
+metrics-bind: 127.0.0.1:9090
+`
+
+	ctx := t.loadConfig(y)
+
+	va, err := config.NewValidator(ctx)
+	t.NoError(err)
+	_, err = va.CheckLocalConfig()
+	t.NoError(err)
+
+	var conf config.LocalNode
+	t.NoError(config.LoadConfigContextValue(ctx, &conf))
+
+	t.Equal("127.0.0.1:9090", conf.LocalConfig().MetricsBind())
+}
+
+func (t *testConfigValidator) TestLocalConfigEventsBind() {
+	y := `
+events-bind: 127.0.0.1:9091
+`
+
+	ctx := t.loadConfig(y)
+
+	va, err := config.NewValidator(ctx)
+	t.NoError(err)
+	_, err = va.CheckLocalConfig()
+	t.NoError(err)
+
+	var conf config.LocalNode
+	t.NoError(config.LoadConfigContextValue(ctx, &conf))
+
+	t.Equal("127.0.0.1:9091", conf.LocalConfig().EventsBind())
+}
+
 func (t *testConfigValidator) TestLocalConfigEmptTimeServer() {
 	{
 		y := ""
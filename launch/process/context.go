@@ -2,6 +2,7 @@ package process
 
 import (
 	"context"
+	"io"
 
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
@@ -10,11 +11,14 @@ import (
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery"
+	"github.com/spikeekips/mitum/network/events"
 	"github.com/spikeekips/mitum/states"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/outbox"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/metrics"
 	"github.com/ulule/limiter/v3"
 )
 
@@ -38,6 +42,12 @@ var (
 	ContextValueRateLimitHandlerMap     util.ContextKey = "ratelimit-handler-map"
 	ContextValueDiscovery               util.ContextKey = "discovery"
 	ContextValueDiscoveryConnInfos      util.ContextKey = "discovery-conninfos"
+	ContextValueMetricsServer           util.ContextKey = "metrics-server"
+	ContextValueEventsServer            util.ContextKey = "events-server"
+	ContextValueOutboxWorker            util.ContextKey = "outbox-worker"
+	ContextValueMempool                 util.ContextKey = "mempool"
+	ContextValueSnapshot                util.ContextKey = "snapshot"
+	ContextValueSkipSchemaVersionCheck  util.ContextKey = "skip_schema_version_check"
 )
 
 func LoadConfigSourceContextValue(ctx context.Context, l *[]byte) error {
@@ -115,3 +125,23 @@ func LoadDiscoveryContextValue(ctx context.Context, l *discovery.Discovery) erro
 func LoadDiscoveryConnInfosContextValue(ctx context.Context, l *[]network.ConnInfo) error {
 	return util.LoadFromContextValue(ctx, ContextValueDiscoveryConnInfos, l)
 }
+
+func LoadMetricsServerContextValue(ctx context.Context, l **metrics.Server) error {
+	return util.LoadFromContextValue(ctx, ContextValueMetricsServer, l)
+}
+
+func LoadEventsServerContextValue(ctx context.Context, l **events.Server) error {
+	return util.LoadFromContextValue(ctx, ContextValueEventsServer, l)
+}
+
+func LoadOutboxWorkerContextValue(ctx context.Context, l **outbox.Worker) error {
+	return util.LoadFromContextValue(ctx, ContextValueOutboxWorker, l)
+}
+
+func LoadMempoolContextValue(ctx context.Context, l **isaac.Mempool) error {
+	return util.LoadFromContextValue(ctx, ContextValueMempool, l)
+}
+
+func LoadSnapshotContextValue(ctx context.Context, l *func() (io.ReadCloser, error)) error {
+	return util.LoadFromContextValue(ctx, ContextValueSnapshot, l)
+}
@@ -0,0 +1,42 @@
+package process
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/network/events"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage/outbox"
+	"github.com/spikeekips/mitum/util"
+)
+
+// BlockEventsHook returns the block saved hook, which publishes the saved
+// blocks to the events consumers.
+func BlockEventsHook(broker *events.Broker) pm.ProcessFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		evs := make([]events.BlockEvent, len(blks))
+		for i := range blks {
+			evs[i] = events.NewBlockEvent(blks[i])
+		}
+
+		broker.Publish(evs...)
+
+		return ctx, nil
+	}
+}
+
+// OutboxHook returns the block saved hook, which wakes up the outbox worker.
+// The saved blocks are already in outbox.
+func OutboxHook(wk *outbox.Worker) pm.ProcessFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		wk.Notify()
+
+		return ctx, nil
+	}
+}
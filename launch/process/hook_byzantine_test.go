@@ -0,0 +1,97 @@
+package process
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/launch/config"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type testHookByzantine struct {
+	suite.Suite
+}
+
+func (t *testHookByzantine) source(y string) map[string]interface{} {
+	var m map[string]interface{}
+	t.NoError(yaml.Unmarshal([]byte(y), &m))
+
+	return m
+}
+
+func (t *testHookByzantine) TestParse() {
+	m := t.source(`
+byzantine:
+  - type: conflicting-ballot
+    stage: init
+    from: 3
+    to: 10
+  - type: withhold-vote
+  - type: delay
+    delay: 3s
+`)
+
+	rules, err := parseByzantineRules(m["byzantine"])
+	t.NoError(err)
+	t.Equal(3, len(rules))
+
+	t.Equal(basicstate.ByzantineConflictingBallot, rules[0].Type)
+	t.Equal(base.StageINIT, rules[0].Stage)
+	t.Equal(base.Height(3), rules[0].From)
+	t.Equal(base.Height(10), rules[0].To)
+
+	t.Equal(basicstate.NewByzantineRule(basicstate.ByzantineWithholdVote), rules[1])
+
+	t.Equal(basicstate.ByzantineDelay, rules[2].Type)
+	t.Equal(time.Second*3, rules[2].Delay)
+}
+
+func (t *testHookByzantine) TestParseInvalid() {
+	cases := []string{
+		`
+byzantine:
+  - type: findme
+`,
+		`
+byzantine:
+  - type: delay
+`,
+		`
+byzantine:
+  - type: withhold-vote
+    stage: SIGN
+`,
+		`
+byzantine:
+  - type: invalid-operations
+    stage: ACCEPT
+`,
+		`
+byzantine:
+  type: withhold-vote
+`,
+	}
+
+	for i := range cases {
+		m := t.source(cases[i])
+
+		_, err := parseByzantineRules(m["byzantine"])
+		t.Error(err, "%d: %s", i, cases[i])
+	}
+}
+
+func (t *testHookByzantine) TestHookWithoutByzantine() {
+	conf := config.NewBaseLocalNode(nil, t.source(`network-id: show me`))
+	ctx := context.WithValue(context.Background(), config.ContextValueConfig, conf)
+
+	_, err := HookByzantine(ctx)
+	t.NoError(err)
+}
+
+func TestHookByzantine(t *testing.T) {
+	suite.Run(t, new(testHookByzantine))
+}
@@ -16,6 +16,7 @@ type HookHandlerSuffrageConfig func(context.Context, map[string]interface{}, []b
 var DefaultHookHandlersSuffrageConfig = map[string]HookHandlerSuffrageConfig{
 	"fixed-suffrage": SuffrageConfigHandlerFixedProposer,
 	"roundrobin":     SuffrageConfigHandlerRoundrobin,
+	"weighted":       SuffrageConfigHandlerWeighted,
 }
 
 func HookSuffrageConfigFunc(handlers map[string]HookHandlerSuffrageConfig) pm.ProcessFunc {
@@ -130,6 +131,63 @@ func SuffrageConfigHandlerRoundrobin(
 	return config.NewRoundrobinSuffrage(nodes, numberOfActing), nil
 }
 
+func SuffrageConfigHandlerWeighted(
+	ctx context.Context,
+	m map[string]interface{},
+	nodes []base.Address,
+) (config.Suffrage, error) {
+	var enc *jsonenc.Encoder
+	if err := config.LoadJSONEncoderContextValue(ctx, &enc); err != nil {
+		return nil, err
+	}
+
+	weights := map[string]uint{}
+	switch i, found := m["weights"]; {
+	case !found || i == nil:
+	default:
+		n, ok := i.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid weights map, %T", i)
+		}
+
+		for k := range n {
+			a, err := parseAddress(k, enc)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid node address for weights")
+			}
+
+			var w uint
+			switch j := n[k].(type) {
+			case int:
+				if j < 1 {
+					return nil, errors.Errorf("weight should be over zero, %q", k)
+				}
+
+				w = uint(j)
+			case uint:
+				w = j
+			default:
+				return nil, errors.Errorf("invalid type for weight, %T", n[k])
+			}
+
+			weights[a.String()] = w
+		}
+	}
+
+	conf := config.NewWeightedSuffrage(nodes, weights)
+
+	if i, found := m["state-key"]; found {
+		s, ok := i.(string)
+		if !ok {
+			return nil, errors.Errorf("invalid type for state-key, %T", i)
+		}
+
+		conf.StateKey = s
+	}
+
+	return conf, nil
+}
+
 func parseSuffrageNodes(ctx context.Context, m map[string]interface{}) ([]base.Address, error) {
 	var enc *jsonenc.Encoder
 	if err := config.LoadJSONEncoderContextValue(ctx, &enc); err != nil {
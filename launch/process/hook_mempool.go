@@ -0,0 +1,37 @@
+package process
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/pm"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// MempoolHook returns the block saved hook, which removes the operations of
+// the saved blocks from mempool.
+func MempoolHook(mp *isaac.Mempool) pm.ProcessFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		var facts []valuehash.Hash
+		for i := range blks {
+			ops := blks[i].Operations()
+			for j := range ops {
+				facts = append(facts, ops[j].Fact().Hash())
+			}
+		}
+
+		if len(facts) > 0 {
+			mp.RemoveFacts(facts)
+		}
+
+		return ctx, nil
+	}
+}
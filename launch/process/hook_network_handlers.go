@@ -1,6 +1,7 @@
 package process
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"sort"
@@ -9,8 +10,10 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/network"
@@ -21,12 +24,26 @@ import (
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/tree"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
 
 const HookNameSetNetworkHandlers = "set_network_handlers"
 
+var (
+	// DefaultEquivocationsLimit is the maximum number of evidences of double
+	// vote in one response.
+	DefaultEquivocationsLimit uint = 100
+	// NodeInfoEquivocationsLimit is the number of recent evidences of double
+	// vote in node info.
+	NodeInfoEquivocationsLimit uint = 10
+	// NodeInfoStuckRecoveriesLimit is the number of recent recovery actions of
+	// stuck consensus in node info.
+	NodeInfoStuckRecoveriesLimit uint = 10
+)
+
 func HookSetNetworkHandlers(ctx context.Context) (context.Context, error) {
 	if sn, err := SettingNetworkHandlersFromContext(ctx); err != nil {
 		return ctx, err
@@ -50,6 +67,7 @@ type SettingNetworkHandlers struct {
 	sealCache cache.Cache
 	logger    *zerolog.Logger
 	encs      *encoder.Encoders
+	mempool   *isaac.Mempool
 }
 
 func SettingNetworkHandlersFromContext(ctx context.Context) (*SettingNetworkHandlers, error) {
@@ -90,6 +108,11 @@ func (sn *SettingNetworkHandlers) loadFromContext(ctx context.Context) error {
 	if err := config.LoadEncodersContextValue(ctx, &sn.encs); err != nil {
 		return err
 	}
+	if err := LoadMempoolContextValue(ctx, &sn.mempool); err != nil {
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return err
+		}
+	}
 
 	i, err := cache.NewCacheFromURI(sn.conf.Network().SealCache().String())
 	if err != nil {
@@ -120,6 +143,15 @@ func (sn *SettingNetworkHandlers) Set() error {
 	sn.network.SetPingHandoverHandler(sn.handlerPingHandover())
 	sn.network.SetEndHandoverHandler(sn.handlerEndHandover())
 	sn.network.SetGetProposalHandler(sn.handlerGetProposal())
+	sn.network.SetGetStateHandler(sn.handlerGetState())
+	sn.network.SetGetStateProofHandler(sn.handlerGetStateProof())
+	sn.network.SetGetOperationProofHandler(sn.handlerGetOperationProof())
+	sn.network.SetGetStatePathProofHandler(sn.handlerGetStatePathProof())
+	sn.network.SetGetPendingOperationsHandler(sn.handlerGetPendingOperations())
+	sn.network.SetGetOperationStatusHandler(sn.handlerGetOperationStatus())
+	sn.network.SetGetOperationsBySignerHandler(sn.handlerGetOperationsBySigner())
+	sn.network.SetGetOperationsByHintHandler(sn.handlerGetOperationsByHint())
+	sn.network.SetGetEquivocationsHandler(sn.handlerGetEquivocations())
 
 	lc := sn.nodepool.LocalChannel().(*network.DummyChannel)
 	lc.SetNewSealHandler(sn.handlerNewSeal())
@@ -127,6 +159,15 @@ func (sn *SettingNetworkHandlers) Set() error {
 	lc.SetNodeInfoHandler(sn.handlerNodeInfo())
 	lc.SetBlockdataMapsHandler(sn.handlerBlockdataMaps())
 	lc.SetBlockdataHandler(sn.handlerBlockdata())
+	lc.SetGetStateHandler(sn.handlerGetState())
+	lc.SetGetStateProofHandler(sn.handlerGetStateProof())
+	lc.SetGetOperationProofHandler(sn.handlerGetOperationProof())
+	lc.SetGetStatePathProofHandler(sn.handlerGetStatePathProof())
+	lc.SetGetPendingOperationsHandler(sn.handlerGetPendingOperations())
+	lc.SetGetOperationStatusHandler(sn.handlerGetOperationStatus())
+	lc.SetGetOperationsBySignerHandler(sn.handlerGetOperationsBySigner())
+	lc.SetGetOperationsByHintHandler(sn.handlerGetOperationsByHint())
+	lc.SetGetEquivocationsHandler(sn.handlerGetEquivocations())
 
 	sn.logger.Debug().Msg("local channel handlers binded")
 
@@ -217,6 +258,11 @@ func (sn *SettingNetworkHandlers) handlerNodeInfo() network.NodeInfoHandler {
 			nodes[i] = network.NewRemoteNode(n, connInfo)
 		}
 
+		evs, err := sn.recentEquivocations(NodeInfoEquivocationsLimit)
+		if err != nil {
+			return nil, err
+		}
+
 		return network.NewNodeInfoV0(
 			sn.nodepool.LocalNode(),
 			sn.policy.NetworkID(),
@@ -227,7 +273,7 @@ func (sn *SettingNetworkHandlers) handlerNodeInfo() network.NodeInfoHandler {
 			nodes,
 			sn.suffrage,
 			sn.conf.Network().ConnInfo(),
-		), nil
+		).SetEquivocations(evs).SetStuckRecoveries(sn.recentStuckRecoveries(NodeInfoStuckRecoveriesLimit)), nil
 	}
 }
 
@@ -356,3 +402,255 @@ func (sn *SettingNetworkHandlers) handlerGetProposal() network.GetProposalHandle
 		}
 	}
 }
+
+func (sn *SettingNetworkHandlers) handlerGetState() network.GetStateHandler {
+	return func(key string) (state.State, bool, error) {
+		return sn.database.State(key)
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetStateProof() network.GetStateProofHandler {
+	pathProof := sn.handlerGetStatePathProof()
+
+	return func(key string) (network.StatePathProofV0, bool, error) {
+		switch st, found, err := sn.database.State(key); {
+		case err != nil:
+			return network.StatePathProofV0{}, false, err
+		case !found:
+			return network.StatePathProofV0{}, false, nil
+		default:
+			return pathProof(st.Height(), key)
+		}
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetOperationProof() network.GetOperationProofHandler {
+	return func(height base.Height, fact valuehash.Hash) (network.OperationProofV0, bool, error) {
+		var manifest block.Manifest
+		switch i, found, err := sn.database.ManifestByHeight(height); {
+		case err != nil:
+			return network.OperationProofV0{}, false, err
+		case !found:
+			return network.OperationProofV0{}, false, nil
+		default:
+			manifest = i
+		}
+
+		tr, err := sn.loadTree(height, block.BlockdataMap.OperationsTree, sn.blockdata.Writer().ReadOperationsTree)
+		if err != nil {
+			return network.OperationProofV0{}, false, err
+		}
+
+		switch pr, found, err := proofFromTree(tr, fact.Bytes()); {
+		case err != nil:
+			return network.OperationProofV0{}, false, err
+		case !found:
+			return network.OperationProofV0{}, false, nil
+		default:
+			return network.NewOperationProofV0(manifest, fact, pr), true, nil
+		}
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetStatePathProof() network.GetStatePathProofHandler {
+	return func(height base.Height, key string) (network.StatePathProofV0, bool, error) {
+		var st state.State
+		switch i, found, err := sn.database.StateAt(key, height); {
+		case err != nil:
+			return network.StatePathProofV0{}, false, err
+		case !found:
+			return network.StatePathProofV0{}, false, nil
+		default:
+			st = i
+		}
+
+		var manifest block.Manifest
+		switch i, found, err := sn.database.ManifestByHeight(st.Height()); {
+		case err != nil:
+			return network.StatePathProofV0{}, false, err
+		case !found:
+			return network.StatePathProofV0{}, false, util.NotFoundError.Errorf("manifest of state, %d not found", st.Height())
+		default:
+			manifest = i
+		}
+
+		tr, err := sn.loadTree(st.Height(), block.BlockdataMap.StatesTree, sn.blockdata.Writer().ReadStatesTree)
+		if err != nil {
+			return network.StatePathProofV0{}, false, err
+		}
+
+		switch pr, found, err := proofFromTree(tr, st.Hash().Bytes()); {
+		case err != nil:
+			return network.StatePathProofV0{}, false, err
+		case !found:
+			return network.StatePathProofV0{}, false, util.NotFoundError.Errorf(
+				"state, %q not found in states tree of %d", key, st.Height())
+		default:
+			return network.NewStatePathProofV0(manifest, st, pr), true, nil
+		}
+	}
+}
+
+func (sn *SettingNetworkHandlers) loadTree(
+	height base.Height,
+	itemf func(block.BlockdataMap) block.BlockdataMapItem,
+	read func(io.Reader) (tree.FixedTree, error),
+) (tree.FixedTree, error) {
+	var bdm block.BlockdataMap
+	switch i, found, err := sn.database.BlockdataMap(height); {
+	case err != nil:
+		return tree.FixedTree{}, err
+	case !found:
+		return tree.FixedTree{}, util.NotFoundError.Errorf("block data map, %d not found", height)
+	default:
+		bdm = i
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), network.ChannelTimeoutBlockdata)
+	defer cancel()
+
+	r, err := blockdata.OpenItem(ctx, sn.blockdata, itemf(bdm))
+	if err != nil {
+		return tree.FixedTree{}, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return read(r)
+}
+
+func proofFromTree(tr tree.FixedTree, key []byte) ([]tree.FixedTreeNode, bool, error) {
+	index := -1
+	if err := tr.Traverse(func(n tree.FixedTreeNode) (bool, error) {
+		if bytes.Equal(n.Key(), key) {
+			index = int(n.Index())
+
+			return false, nil
+		}
+
+		return true, nil
+	}); err != nil {
+		return nil, false, err
+	}
+
+	if index < 0 {
+		return nil, false, nil
+	}
+
+	pr, err := tr.Proof(uint64(index))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return pr, true, nil
+}
+
+func (sn *SettingNetworkHandlers) handlerGetPendingOperations() network.GetPendingOperationsHandler {
+	if sn.mempool == nil {
+		return nil
+	}
+
+	return func(limit uint) ([]network.PendingOperationV0, error) {
+		return sn.mempool.Pending(limit), nil
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetOperationStatus() network.GetOperationStatusHandler {
+	return func(fact valuehash.Hash) (operation.StatusV0, bool, error) {
+		return sn.database.OperationStatus(fact)
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetOperationsBySigner() network.GetOperationsBySignerHandler {
+	return func(signer key.Publickey, cursor storage.OperationCursor, limit int64) (network.OperationsPageV0, error) {
+		ops, next, err := sn.database.OperationsBySigner(signer, cursor, sn.operationsLimit(limit))
+		if err != nil {
+			return network.OperationsPageV0{}, err
+		}
+
+		return network.NewOperationsPageV0(ops, next), nil
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetOperationsByHint() network.GetOperationsByHintHandler {
+	return func(
+		ht hint.Type,
+		from, to base.Height,
+		cursor storage.OperationCursor,
+		limit int64,
+	) (network.OperationsPageV0, error) {
+		// NOTE empty to height means the last height
+		if to <= base.NilHeight {
+			switch m, found, err := sn.database.LastManifest(); {
+			case err != nil:
+				return network.OperationsPageV0{}, err
+			case !found:
+				return network.NewOperationsPageV0(nil, storage.NilOperationCursor), nil
+			default:
+				to = m.Height()
+			}
+		}
+
+		ops, next, err := sn.database.OperationsByHint(ht, from, to, cursor, sn.operationsLimit(limit))
+		if err != nil {
+			return network.OperationsPageV0{}, err
+		}
+
+		return network.NewOperationsPageV0(ops, next), nil
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetEquivocations() network.GetEquivocationsHandler {
+	return func(limit uint) ([]base.EquivocationV0, error) {
+		if limit < 1 || limit > DefaultEquivocationsLimit {
+			limit = DefaultEquivocationsLimit
+		}
+
+		return sn.recentEquivocations(limit)
+	}
+}
+
+// recentEquivocations returns the evidences of double vote from the highest
+// height.
+func (sn *SettingNetworkHandlers) recentEquivocations(limit uint) ([]base.EquivocationV0, error) {
+	var evs []base.EquivocationV0
+	if err := sn.database.Equivocations(func(ev base.EquivocationV0) (bool, error) {
+		evs = append(evs, ev)
+
+		return uint(len(evs)) < limit, nil
+	}, false); err != nil {
+		return nil, err
+	}
+
+	return evs, nil
+}
+
+// recentStuckRecoveries returns the recent recovery actions of stuck consensus,
+// the oldest first; states, which does not recover stuck consensus, returns
+// nothing.
+func (sn *SettingNetworkHandlers) recentStuckRecoveries(limit uint) []network.StuckRecoveryRecord {
+	i, ok := sn.states.(interface {
+		StuckRecoveryRecords() []network.StuckRecoveryRecord
+	})
+	if !ok {
+		return nil
+	}
+
+	records := i.StuckRecoveryRecords()
+	if n := len(records) - int(limit); n > 0 {
+		records = records[n:]
+	}
+
+	return records
+}
+
+// operationsLimit prevents too many operations in one response.
+func (*SettingNetworkHandlers) operationsLimit(limit int64) int64 {
+	if limit < 1 || limit > storage.DefaultOperationsLimit {
+		return storage.DefaultOperationsLimit
+	}
+
+	return limit
+}
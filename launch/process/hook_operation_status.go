@@ -0,0 +1,40 @@
+package process
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/pm"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+)
+
+// OperationStatusHook returns the block saved hook, which stores the final
+// status of the operations of the saved blocks.
+func OperationStatusHook(db storage.Database) pm.ProcessFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		var statuses []operation.StatusV0
+		for i := range blks {
+			l, err := isaac.BlockOperationStatuses(blks[i])
+			if err != nil {
+				return ctx, err
+			}
+
+			statuses = append(statuses, l...)
+		}
+
+		if len(statuses) < 1 {
+			return ctx, nil
+		}
+
+		return ctx, db.SetOperationStatuses(statuses)
+	}
+}
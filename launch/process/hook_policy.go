@@ -3,12 +3,24 @@ package process
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/policy"
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/states"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/logging"
 )
 
-const HookNameSetPolicy = "set_policy"
+const (
+	HookNameSetPolicy             = "set_policy"
+	HookNameUpdatePolicyFromState = "update_policy_from_state"
+)
 
 func HookSetPolicy(ctx context.Context) (context.Context, error) {
 	var networkID base.NetworkID
@@ -22,7 +34,9 @@ func HookSetPolicy(ctx context.Context) (context.Context, error) {
 
 	policy := isaac.NewLocalPolicy(networkID)
 
-	_ = policy.SetThresholdRatio(conf.ThresholdRatio())
+	if _, err := policy.SetThresholdRatio(conf.ThresholdRatio()); err != nil {
+		return ctx, err
+	}
 	if _, err := policy.SetMaxOperationsInSeal(conf.MaxOperationsInSeal()); err != nil {
 		return ctx, err
 	}
@@ -53,3 +67,157 @@ func HookSetPolicy(ctx context.Context) (context.Context, error) {
 
 	return context.WithValue(ctx, ContextValuePolicy, policy), nil
 }
+
+// UpdateLocalPolicy sets the values of the policy from state to LocalPolicy.
+func UpdateLocalPolicy(lp *isaac.LocalPolicy, po policy.PolicyV0) error {
+	if err := po.IsValid(nil); err != nil {
+		return err
+	}
+
+	if _, err := lp.SetThresholdRatio(po.ThresholdRatio()); err != nil {
+		return err
+	}
+	if _, err := lp.SetMaxOperationsInSeal(po.MaxOperationsInSeal()); err != nil {
+		return err
+	}
+	if _, err := lp.SetMaxOperationsInProposal(po.MaxOperationsInProposal()); err != nil {
+		return err
+	}
+	if _, err := lp.SetTimeoutWaitingProposal(po.TimeoutWaitingProposal()); err != nil {
+		return err
+	}
+	if _, err := lp.SetIntervalBroadcastingINITBallot(po.IntervalBroadcastingINITBallot()); err != nil {
+		return err
+	}
+	if _, err := lp.SetIntervalBroadcastingProposal(po.IntervalBroadcastingProposal()); err != nil {
+		return err
+	}
+	if _, err := lp.SetWaitBroadcastingACCEPTBallot(po.WaitBroadcastingACCEPTBallot()); err != nil {
+		return err
+	}
+	if _, err := lp.SetIntervalBroadcastingACCEPTBallot(po.IntervalBroadcastingACCEPTBallot()); err != nil {
+		return err
+	}
+	if _, err := lp.SetTimespanValidBallot(po.TimespanValidBallot()); err != nil {
+		return err
+	}
+	if _, err := lp.SetNetworkConnectionTimeout(po.NetworkConnectionTimeout()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LoadPolicyFromState returns the policy, which takes effect at the given
+// height. If the last policy state is not yet effective, the previous policy
+// state is used.
+func LoadPolicyFromState(
+	db storage.Database,
+	stateKey string,
+	height base.Height,
+) (policy.PolicyV0, bool, error) {
+	st, found, err := db.State(stateKey)
+
+	for {
+		switch {
+		case err != nil:
+			return policy.PolicyV0{}, false, err
+		case !found || st.Value() == nil:
+			return policy.PolicyV0{}, false, nil
+		}
+
+		po, ok := st.Value().Interface().(policy.PolicyV0)
+		if !ok {
+			return policy.PolicyV0{}, false, errors.Errorf("invalid policy state value, %T", st.Value().Interface())
+		}
+
+		if po.Height() <= height {
+			return po, true, nil
+		}
+
+		prev := st.Height() - 1
+		if prev <= base.PreGenesisHeight {
+			return policy.PolicyV0{}, false, nil
+		}
+
+		st, found, err = db.StateAt(stateKey, prev)
+	}
+}
+
+// HookUpdatePolicyFromState returns the block saved hook, which updates
+// LocalPolicy by the policy state for the next height of the saved blocks.
+func HookUpdatePolicyFromState(
+	db storage.Database,
+	stateKey string,
+	lp *isaac.LocalPolicy,
+	log *logging.Logging,
+) pm.ProcessFunc {
+	var last *policy.PolicyV0
+
+	return func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		if len(blks) < 1 {
+			return ctx, nil
+		}
+
+		height := blks[len(blks)-1].Height() + 1
+
+		switch po, found, err := LoadPolicyFromState(db, stateKey, height); {
+		case err != nil:
+			return ctx, err
+		case !found:
+			return ctx, nil
+		case last != nil && last.Hash().Equal(po.Hash()):
+			return ctx, nil
+		default:
+			if err := UpdateLocalPolicy(lp, po); err != nil {
+				return ctx, err
+			}
+
+			last = &po
+
+			log.Log().Info().Int64("height", height.Int64()).Interface("policy", lp.Config()).
+				Msg("local policy updated from state")
+
+			return ctx, nil
+		}
+	}
+}
+
+// setPolicyFromState updates LocalPolicy by the policy state of the next block
+// and adds the hook to update LocalPolicy when new blocks are saved.
+func setPolicyFromState(
+	ss states.States,
+	db storage.Database,
+	lp *isaac.LocalPolicy,
+	log *logging.Logging,
+) error {
+	height := base.PreGenesisHeight
+	switch m, found, err := db.LastManifest(); {
+	case err != nil:
+		return err
+	case found:
+		height = m.Height()
+	}
+
+	switch po, found, err := LoadPolicyFromState(db, policy.DefaultStateKey, height+1); {
+	case err != nil:
+		return err
+	case found:
+		if err := UpdateLocalPolicy(lp, po); err != nil {
+			return err
+		}
+
+		log.Log().Debug().Interface("policy", lp.Config()).Msg("local policy loaded from state")
+	}
+
+	return ss.BlockSavedHook().Add(
+		HookNameUpdatePolicyFromState,
+		HookUpdatePolicyFromState(db, policy.DefaultStateKey, lp, log),
+		false,
+	)
+}
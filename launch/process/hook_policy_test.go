@@ -0,0 +1,58 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/policy"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/stretchr/testify/suite"
+)
+
+type testHookPolicy struct {
+	suite.Suite
+}
+
+func (t *testHookPolicy) TestUpdateLocalPolicy() {
+	lp := isaac.NewLocalPolicy(base.NetworkID([]byte("show me")))
+
+	po := policy.NewPolicyV0(
+		base.Height(33),
+		base.ThresholdRatio(80),
+		11,
+		22,
+		time.Second*6,
+		time.Second*7,
+		time.Second*8,
+		time.Second*9,
+		time.Second*10,
+		time.Minute*2,
+		time.Second*4,
+	)
+	t.NoError(UpdateLocalPolicy(lp, po))
+
+	t.Equal(po.ThresholdRatio(), lp.ThresholdRatio())
+	t.Equal(po.MaxOperationsInSeal(), lp.MaxOperationsInSeal())
+	t.Equal(po.MaxOperationsInProposal(), lp.MaxOperationsInProposal())
+	t.Equal(po.TimeoutWaitingProposal(), lp.TimeoutWaitingProposal())
+	t.Equal(po.IntervalBroadcastingINITBallot(), lp.IntervalBroadcastingINITBallot())
+	t.Equal(po.IntervalBroadcastingProposal(), lp.IntervalBroadcastingProposal())
+	t.Equal(po.WaitBroadcastingACCEPTBallot(), lp.WaitBroadcastingACCEPTBallot())
+	t.Equal(po.IntervalBroadcastingACCEPTBallot(), lp.IntervalBroadcastingACCEPTBallot())
+	t.Equal(po.TimespanValidBallot(), lp.TimespanValidBallot())
+	t.Equal(po.NetworkConnectionTimeout(), lp.NetworkConnectionTimeout())
+}
+
+func (t *testHookPolicy) TestUpdateLocalPolicyInvalid() {
+	lp := isaac.NewLocalPolicy(base.NetworkID([]byte("show me")))
+
+	po := policy.NewPolicyV0(base.Height(33), base.ThresholdRatio(80), 0, 22, 1, 1, 1, 1, 1, 1, time.Second)
+	t.Error(UpdateLocalPolicy(lp, po))
+
+	t.Equal(isaac.DefaultPolicyThresholdRatio, lp.ThresholdRatio())
+}
+
+func TestHookPolicy(t *testing.T) {
+	suite.Run(t, new(testHookPolicy))
+}
@@ -0,0 +1,25 @@
+package process
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/spikeekips/mitum/util/metrics"
+)
+
+var metricRateLimitRejected = metrics.NewCounterVec(
+	"mitum_network_ratelimit_rejected_total", "number of requests rejected by rate limit", "handler")
+
+func init() {
+	metrics.MustRegister(metricRateLimitRejected)
+}
+
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if i, err := route.GetPathTemplate(); err == nil {
+			return i
+		}
+	}
+
+	return r.URL.Path
+}
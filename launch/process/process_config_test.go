@@ -132,6 +132,8 @@ suffrage:
 	t.Equal(isaac.DefaultPolicyThresholdRatio, conf.Policy().ThresholdRatio())
 	t.Equal(isaac.DefaultPolicyWaitBroadcastingACCEPTBallot, conf.Policy().WaitBroadcastingACCEPTBallot())
 	t.Empty(conf.LocalConfig().TimeServer())
+	t.Empty(conf.LocalConfig().MetricsBind())
+	t.Empty(conf.LocalConfig().EventsBind())
 }
 
 func (t *testConfig) TestInValidSuffrage() {
@@ -3,14 +3,17 @@ package process
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/prprocessor"
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/pm"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/network/discovery"
 	"github.com/spikeekips/mitum/network/discovery/memberlist"
 	"github.com/spikeekips/mitum/states"
 	basicstate "github.com/spikeekips/mitum/states/basic"
@@ -18,6 +21,7 @@ import (
 	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
@@ -74,6 +78,30 @@ func ProcessConsensusStates(ctx context.Context) (context.Context, error) {
 		return ctx, err
 	}
 
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return ctx, err
+	}
+
+	lc := conf.LocalConfig()
+	mp := isaac.NewMempool(db, lc.MempoolTTL(), lc.MempoolSenderLimit(), lc.MempoolSize())
+	_ = mp.SetLogging(log)
+
+	for ht, p := range lc.MempoolPriorities() {
+		p := p
+		if err := mp.SetPriorityFunc(hint.NewBaseHinter(ht), func(operation.Operation) uint64 {
+			return p
+		}); err != nil {
+			return ctx, err
+		}
+	}
+
+	if err := mp.Initialize(); err != nil {
+		return ctx, err
+	}
+
+	ctx = context.WithValue(ctx, ContextValueMempool, mp)
+
 	cs, err := processConsensusStates(ctx, db, bd, policy, nodepool, suffrage)
 	if err != nil {
 		return ctx, err
@@ -99,6 +127,11 @@ func processConsensusStates(
 		return nil, err
 	}
 
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return nil, err
+	}
+
 	var pps *prprocessor.Processors
 	if err := LoadProposalProcessorContextValue(ctx, &pps); err != nil {
 		if !errors.Is(err, util.ContextValueNotFoundError) {
@@ -106,7 +139,17 @@ func processConsensusStates(
 		}
 	}
 
+	var mp *isaac.Mempool
+	if err := LoadMempoolContextValue(ctx, &mp); err != nil {
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return nil, err
+		}
+	}
+
 	proposalMaker := isaac.NewProposalMaker(nodepool.LocalNode(), db, policy)
+	if mp != nil {
+		_ = proposalMaker.SetMempool(mp)
+	}
 
 	ballotbox := isaac.NewBallotbox(
 		suffrage.Nodes,
@@ -123,6 +166,12 @@ func processConsensusStates(
 	)
 	_ = ballotbox.SetLogging(log)
 
+	if ws, ok := suffrage.(base.WeightedSuffrage); ok {
+		_ = ballotbox.SetWeightsFunc(ws.Weights)
+	}
+
+	_ = ballotbox.SetEquivocationFunc(equivocationFunc(db, suffrage, log))
+
 	joiner, err := createDiscoveryJoiner(ctx, nodepool, suffrage)
 	if err != nil {
 		return nil, err
@@ -138,9 +187,18 @@ func processConsensusStates(
 	joining := basicstate.NewJoiningState(nodepool.LocalNode(), db, policy, suffrage, ballotbox)
 	consensus := basicstate.NewConsensusState(db, policy, nodepool, suffrage, proposalMaker, pps)
 	syncing := basicstate.NewSyncingState(db, bd, policy, nodepool, suffrage)
+
+	var snapshot func() (io.ReadCloser, error)
+	switch err := LoadSnapshotContextValue(ctx, &snapshot); {
+	case err == nil:
+		_ = syncing.SetSnapshot(snapshot)
+	case !errors.Is(err, util.ContextValueNotFoundError):
+		return nil, err
+	}
+
 	handover := basicstate.NewHandoverState(db, policy, nodepool, suffrage, pps)
 
-	return basicstate.NewStates(
+	ss, err := basicstate.NewStates(
 		db,
 		policy,
 		nodepool,
@@ -155,6 +213,78 @@ func processConsensusStates(
 		joiner,
 		hd,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setPolicyFromState(ss, db, policy, log); err != nil {
+		return nil, err
+	}
+
+	_ = ss.SetBlockdata(bd)
+
+	lc := conf.LocalConfig()
+	stuckPolicy := basicstate.NewStuckRecoveryPolicy(lc.StuckRecoveryActions()...)
+	stuckPolicy.Endure = lc.StuckEndure()
+	stuckPolicy.Interval = lc.StuckRecoveryInterval()
+
+	if err := ss.SetStuckRecoveryPolicy(stuckPolicy); err != nil {
+		return nil, err
+	}
+
+	if err := ss.BlockSavedHook().Add("operation-status", OperationStatusHook(db), false); err != nil {
+		return nil, err
+	}
+
+	if mp != nil {
+		_ = ss.SetMempool(mp)
+
+		if err := ss.BlockSavedHook().Add("mempool", MempoolHook(mp), false); err != nil {
+			return nil, err
+		}
+	}
+
+	if ws, ok := suffrage.(*WeightedSuffrage); ok {
+		hook, err := WeightedSuffrageNodepoolHook(ws, nodepool, db.States, discoveredNodeChannelFunc(ctx, policy))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ss.BlockSavedHook().Add("weighted-suffrage-nodepool", hook, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return ss, nil
+}
+
+// discoveredNodeChannelFunc returns the function, which loads the channel of
+// node from the conn info of discovery. If discovery is not enabled or the node
+// is not discovered, the function returns nil channel.
+func discoveredNodeChannelFunc(
+	ctx context.Context,
+	policy *isaac.LocalPolicy,
+) func(base.Address) (network.Channel, error) {
+	var encs *encoder.Encoders
+	if err := config.LoadEncodersContextValue(ctx, &encs); err != nil {
+		return nil
+	}
+
+	var dis discovery.Discovery
+	if err := LoadDiscoveryContextValue(ctx, &dis); err != nil {
+		return nil
+	}
+
+	return func(a base.Address) (network.Channel, error) {
+		nodes := dis.Nodes()
+		for i := range nodes {
+			if nodes[i].Node().Equal(a) {
+				return LoadNodeChannel(nodes[i], encs, policy.NetworkConnectionTimeout())
+			}
+		}
+
+		return nil, nil
+	}
 }
 
 func createDiscoveryJoiner(
@@ -241,3 +371,25 @@ func createHandover(
 
 	return handover, nil
 }
+
+// equivocationFunc stores the evidence of double vote and notifies it to the
+// suffrage, which implements base.EquivocationSuffrage.
+func equivocationFunc(db storage.Database, suffrage base.Suffrage, log *logging.Logging) func(base.EquivocationV0) {
+	es, notify := suffrage.(base.EquivocationSuffrage)
+
+	return func(ev base.EquivocationV0) {
+		l := log.Log().With().Str("equivocation", ev.Key()).Logger()
+
+		if err := db.SetEquivocation(ev); err != nil {
+			l.Error().Err(err).Msg("failed to store equivocation")
+		}
+
+		if !notify {
+			return
+		}
+
+		if err := es.Equivocated(ev); err != nil {
+			l.Error().Err(err).Msg("failed to notify equivocation to suffrage")
+		}
+	}
+}
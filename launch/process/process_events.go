@@ -0,0 +1,108 @@
+package process
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/network/events"
+	"github.com/spikeekips/mitum/states"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const ProcessNameEvents = "events"
+
+var ProcessorEvents pm.Process
+
+func init() {
+	if i, err := pm.NewProcess(
+		ProcessNameEvents,
+		[]string{
+			ProcessNameConfig,
+			ProcessNameDatabase,
+			ProcessNameBlockdata,
+			ProcessNameConsensusStates,
+		},
+		ProcessEvents,
+	); err != nil {
+		panic(err)
+	} else {
+		ProcessorEvents = i
+	}
+}
+
+// ProcessEvents prepares the events server and adds the block saved hook,
+// which publishes the saved blocks; the server is not started.
+func ProcessEvents(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return ctx, err
+	}
+
+	bind := conf.LocalConfig().EventsBind()
+	if len(bind) < 1 {
+		log.Log().Debug().Msg("empty events bind; events server disabled")
+
+		return ctx, nil
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var bd blockdata.Blockdata
+	if err := LoadBlockdataContextValue(ctx, &bd); err != nil {
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	broker := events.NewBroker(events.DefaultBrokerBufferSize)
+
+	if err := cs.BlockSavedHook().Add("block-events", BlockEventsHook(broker), false); err != nil {
+		return ctx, err
+	}
+
+	sv := events.NewServer(bind, broker, blockEventLoader(db, bd))
+	_ = sv.SetLogging(log)
+
+	return context.WithValue(ctx, ContextValueEventsServer, sv), nil
+}
+
+func blockEventLoader(db storage.Database, bd blockdata.Blockdata) events.BlockEventLoader {
+	return func(ctx context.Context, height base.Height) (events.BlockEvent, bool, error) {
+		switch m, found, err := db.LastManifest(); {
+		case err != nil:
+			return events.BlockEvent{}, false, err
+		case !found || height > m.Height():
+			return events.BlockEvent{}, false, nil
+		}
+
+		bdm, found, err := db.BlockdataMap(height)
+		switch {
+		case err != nil:
+			return events.BlockEvent{}, false, err
+		case !found:
+			return events.BlockEvent{}, false, nil
+		}
+
+		blk, err := blockdata.LoadBlock(ctx, bd, bdm)
+		if err != nil {
+			return events.BlockEvent{}, false, err
+		}
+
+		return events.NewBlockEvent(blk), true, nil
+	}
+}
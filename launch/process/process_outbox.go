@@ -0,0 +1,93 @@
+package process
+
+import (
+	"context"
+	"sort"
+
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/states"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/outbox"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const ProcessNameOutbox = "outbox"
+
+var ProcessorOutbox pm.Process
+
+func init() {
+	if i, err := pm.NewProcess(
+		ProcessNameOutbox,
+		[]string{
+			ProcessNameConfig,
+			ProcessNameDatabase,
+			ProcessNameBlockdata,
+			ProcessNameConsensusStates,
+		},
+		ProcessOutbox,
+	); err != nil {
+		panic(err)
+	} else {
+		ProcessorOutbox = i
+	}
+}
+
+// ProcessOutbox prepares the outbox worker and adds the block saved hook,
+// which wakes up the worker; the worker is not started. Without sinks, the
+// worker just empties the outbox.
+func ProcessOutbox(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return ctx, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var bd blockdata.Blockdata
+	if err := LoadBlockdataContextValue(ctx, &bd); err != nil {
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	oc := conf.Storage().Outbox()
+
+	names := make([]string, 0, len(oc.Sinks()))
+	for name := range oc.Sinks() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sinks := make([]outbox.Sink, len(names))
+	for i := range names {
+		sink, err := outbox.NewSinkFromURI(names[i], oc.Sinks()[names[i]])
+		if err != nil {
+			return ctx, err
+		}
+		sinks[i] = sink
+	}
+
+	wk := outbox.NewWorker(db, blockEventLoader(db, bd), sinks, outbox.DefaultInterval, oc.RetryInterval())
+	_ = wk.SetLogging(log)
+
+	if err := cs.BlockSavedHook().Add("outbox", OutboxHook(wk), false); err != nil {
+		return ctx, err
+	}
+
+	log.Log().Debug().Strs("sinks", names).Msg("outbox worker prepared")
+
+	return context.WithValue(ctx, ContextValueOutboxWorker, wk), nil
+}
@@ -3,7 +3,12 @@ package process
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/membership"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/policy"
 	"github.com/spikeekips/mitum/base/prprocessor"
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/launch/config"
@@ -11,6 +16,7 @@ import (
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
 )
@@ -115,8 +121,8 @@ func processDefaultProposalProcessor(ctx context.Context) (prprocessor.Processor
 		return nil, err
 	}
 
-	var oprs *hint.Hintmap
-	if err := LoadOperationProcessorsContextValue(ctx, &oprs); err != nil {
+	oprs, err := loadOperationProcessors(ctx)
+	if err != nil {
 		return nil, err
 	}
 
@@ -164,8 +170,8 @@ func processErrorProposalProcessor(
 		return nil, err
 	}
 
-	var oprs *hint.Hintmap
-	if err := LoadOperationProcessorsContextValue(ctx, &oprs); err != nil {
+	oprs, err := loadOperationProcessors(ctx)
+	if err != nil {
 		return nil, err
 	}
 
@@ -179,3 +185,164 @@ func processErrorProposalProcessor(
 		conf.WhenSavePoints,
 	), nil
 }
+
+// loadOperationProcessors loads the operation processors from context. The
+// policy operation processor is added for SetPolicy, and if WeightedSuffrage is
+// used, the membership operation processor is added for JoinSuffrage and
+// LeaveSuffrage.
+func loadOperationProcessors(ctx context.Context) (*hint.Hintmap, error) {
+	var oprs *hint.Hintmap
+	switch err := LoadOperationProcessorsContextValue(ctx, &oprs); {
+	case err == nil:
+	case errors.Is(err, util.ContextValueNotFoundError):
+		oprs = hint.NewHintmap()
+	default:
+		return nil, err
+	}
+
+	var suffrage base.Suffrage
+	if err := LoadSuffrageContextValue(ctx, &suffrage); err != nil {
+		return nil, err
+	}
+
+	var nodepool *network.Nodepool
+	if err := LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return nil, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return nil, err
+	}
+
+	var lp *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &lp); err != nil {
+		return nil, err
+	}
+
+	var l config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &l); err != nil {
+		return nil, err
+	}
+
+	// NOTE before the policy state is stored, the threshold ratio of config is
+	// used.
+	configRatio := l.Policy().ThresholdRatio()
+	ratioFunc := func(height base.Height) (base.ThresholdRatio, error) {
+		switch po, found, err := LoadPolicyFromState(db, policy.DefaultStateKey, height); {
+		case err != nil:
+			return 0, err
+		case !found:
+			return configRatio, nil
+		default:
+			return po.ThresholdRatio(), nil
+		}
+	}
+
+	var signersFunc policy.SignersFunc
+	var mopr *membership.OperationProcessor
+
+	if sf, ok := suffrage.(*WeightedSuffrage); ok {
+		genesisNodes, err := loadGenesisSuffrageNodes(ctx, sf.Genesis())
+		if err != nil {
+			return nil, err
+		}
+
+		mopr = membership.NewOperationProcessor(
+			sf.StateKey(),
+			sf.Genesis(),
+			genesisNodes,
+			lp.ThresholdRatio(),
+			db.HasOperationFact,
+		)
+
+		signersFunc = mopr.Signers
+	} else {
+		signersFunc = fixedSuffrageSignersFunc(suffrage, nodepool)
+	}
+
+	if err := addOperationProcessor(
+		oprs,
+		policy.NewOperationProcessor(policy.DefaultStateKey, signersFunc, ratioFunc, db.HasOperationFact),
+		policy.SetPolicyHinter,
+	); err != nil {
+		return nil, err
+	}
+
+	if mopr == nil {
+		return oprs, nil
+	}
+
+	if err := addOperationProcessor(
+		oprs,
+		mopr,
+		membership.JoinSuffrageHinter, membership.LeaveSuffrageHinter,
+	); err != nil {
+		return nil, err
+	}
+
+	return oprs, nil
+}
+
+// fixedSuffrageSignersFunc returns the policy.SignersFunc for the suffrage,
+// which is not changed by operations; the suffrage nodes have same weight and
+// their publickeys are loaded from the nodepool.
+func fixedSuffrageSignersFunc(suffrage base.Suffrage, nodepool *network.Nodepool) policy.SignersFunc {
+	publickeyFunc := func(a base.Address) (key.Publickey, bool, error) {
+		n, _, found := nodepool.Node(a)
+		if !found {
+			return nil, false, nil
+		}
+
+		return n.Publickey(), true, nil
+	}
+
+	return func(*storage.Statepool) (base.SuffrageWeights, func(base.Address) (key.Publickey, bool, error), error) {
+		return base.NewEqualSuffrageWeights(suffrage.Nodes()), publickeyFunc, nil
+	}
+}
+
+// loadGenesisSuffrageNodes loads the genesis suffrage nodes from the local node
+// and the node configs, not from nodepool; nodepool can be updated by the
+// joined nodes.
+func loadGenesisSuffrageNodes(ctx context.Context, genesis base.SuffrageWeights) ([]base.Node, error) {
+	var local node.Local
+	if err := LoadLocalNodeContextValue(ctx, &local); err != nil {
+		return nil, err
+	}
+
+	var l config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &l); err != nil {
+		return nil, err
+	}
+
+	var nodes []base.Node
+	if genesis.Exists(local.Address()) {
+		nodes = append(nodes, local)
+	}
+
+	confs := l.Nodes()
+	for i := range confs {
+		if genesis.Exists(confs[i].Address()) {
+			nodes = append(nodes, node.NewRemote(confs[i].Address(), confs[i].Publickey()))
+		}
+	}
+
+	return nodes, nil
+}
+
+// addOperationProcessor adds the operation processor for hinters; if the
+// operation processor for hinter is already added, it is skipped.
+func addOperationProcessor(oprs *hint.Hintmap, opr prprocessor.OperationProcessor, hinters ...hint.Hinter) error {
+	for i := range hinters {
+		if _, err := oprs.Compatible(hinters[i]); err == nil {
+			continue
+		}
+
+		if err := oprs.Add(hinters[i], opr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -6,7 +6,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	s3blockdata "github.com/spikeekips/mitum/storage/blockdata/s3"
+	leveldbstorage "github.com/spikeekips/mitum/storage/leveldb"
 	mongodbstorage "github.com/spikeekips/mitum/storage/mongodb"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
@@ -62,8 +65,18 @@ func ProcessBlockdata(ctx context.Context) (context.Context, error) {
 		return ctx, err
 	}
 
-	blockdata := localfs.NewBlockdata(conf.Path(), enc)
-	if err := blockdata.Initialize(); err != nil {
+	var bd blockdata.Blockdata
+	if u := conf.URI(); u != nil {
+		i, err := s3blockdata.NewBlockdataFromURI(u.String(), conf.Path(), enc)
+		if err != nil {
+			return ctx, err
+		}
+		bd = i
+	} else {
+		bd = localfs.NewBlockdata(conf.Path(), enc)
+	}
+
+	if err := bd.Initialize(); err != nil {
 		return nil, err
 	}
 
@@ -74,7 +87,7 @@ func ProcessBlockdata(ctx context.Context) (context.Context, error) {
 		}
 	}
 
-	return context.WithValue(ctx, ContextValueBlockdata, blockdata), nil
+	return context.WithValue(ctx, ContextValueBlockdata, bd), nil
 }
 
 func ProcessDatabase(ctx context.Context) (context.Context, error) {
@@ -87,6 +100,8 @@ func ProcessDatabase(ctx context.Context) (context.Context, error) {
 	switch {
 	case conf.URI().Scheme == "mongodb", conf.URI().Scheme == "mongodb+srv":
 		return processMongodbDatabase(ctx, l)
+	case conf.URI().Scheme == "leveldb", conf.URI().Scheme == "leveldb+mem":
+		return processLeveldbDatabase(ctx, l)
 	default:
 		return ctx, errors.Errorf("unsupported database type, %q", conf.URI().Scheme)
 	}
@@ -114,5 +129,38 @@ func processMongodbDatabase(ctx context.Context, l config.LocalNode) (context.Co
 		return ctx, err
 	}
 
+	var skipCheck bool
+	if err := util.LoadFromContextValue(ctx, ContextValueSkipSchemaVersionCheck, &skipCheck); err != nil {
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return ctx, err
+		}
+	}
+
+	if !skipCheck {
+		if err := st.CheckSchemaVersion(); err != nil {
+			return ctx, errors.Wrap(err, "run `storage migrate` to migrate database")
+		}
+	}
+
+	return context.WithValue(ctx, ContextValueDatabase, st), nil
+}
+
+func processLeveldbDatabase(ctx context.Context, l config.LocalNode) (context.Context, error) {
+	conf := l.Storage().Database()
+
+	var encs *encoder.Encoders
+	if err := config.LoadEncodersContextValue(ctx, &encs); err != nil {
+		return ctx, err
+	}
+
+	st, err := leveldbstorage.NewDatabaseFromURI(conf.URI().String(), encs)
+	if err != nil {
+		return ctx, err
+	}
+
+	if err := st.Initialize(); err != nil {
+		return ctx, err
+	}
+
 	return context.WithValue(ctx, ContextValueDatabase, st), nil
 }
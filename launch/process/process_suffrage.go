@@ -59,6 +59,12 @@ func ProcessSuffrage(ctx context.Context) (context.Context, error) {
 			return ctx, err
 		}
 		sf = s
+	case config.WeightedSuffrage:
+		s, err := processWeightedSuffrage(ctx, t)
+		if err != nil {
+			return ctx, err
+		}
+		sf = s
 	case config.EmptySuffrage:
 		sf = EmptySuffrage{}
 	}
@@ -167,3 +173,57 @@ func processRoundrobinSuffrage(ctx context.Context, conf config.RoundrobinSuffra
 		},
 	)
 }
+
+func processWeightedSuffrage(ctx context.Context, conf config.WeightedSuffrage) (base.Suffrage, error) {
+	var nodepool *network.Nodepool
+	if err := LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return nil, err
+	}
+
+	if len(conf.Nodes()) < 1 {
+		return nil, errors.Errorf("empty nodes for suffrage")
+	}
+	for i := range conf.Nodes() {
+		c := conf.Nodes()[i]
+		if !nodepool.Exists(c) {
+			return nil, errors.Errorf("unknown node of weighted suffrage found, %q", c)
+		}
+	}
+
+	genesis, err := conf.Weights()
+	if err != nil {
+		return nil, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return nil, err
+	}
+
+	return NewWeightedSuffrage(
+		conf.StateKey,
+		genesis,
+		conf.CacheSize,
+		db.StateAt,
+		func() (base.Height, error) {
+			switch m, found, err := db.LastManifest(); {
+			case err != nil:
+				return base.NilHeight, err
+			case !found:
+				return base.PreGenesisHeight, nil
+			default:
+				return m.Height(), nil
+			}
+		},
+		func(height base.Height) (valuehash.Hash, error) {
+			switch m, found, err := db.ManifestByHeight(height); {
+			case err != nil:
+				return nil, err
+			case !found:
+				return nil, util.NotFoundError.Errorf("manifest not found for suffrage")
+			default:
+				return m.Hash(), nil
+			}
+		},
+	)
+}
@@ -127,6 +127,8 @@ func (mw *RateLimitMiddleware) limit(w http.ResponseWriter, r *http.Request) boo
 func (mw *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if mw.limit(w, r) { // nolint:contextcheck
+			metricRateLimitRejected.WithLabelValues(routeTemplate(r)).Inc()
+
 			return
 		}
 
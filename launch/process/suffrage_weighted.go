@@ -0,0 +1,446 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/membership"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/network"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// WeightedSuffrage reads the suffrage nodes and their weights from the state
+// of stateKey. The suffrage weights of height are decided by the state of
+// previous height, so the changes by operations take effect from the next
+// height. Before the state is stored, the genesis weights are used.
+//
+// All the suffrage nodes are acting, and the proposer is selected by the
+// weights.
+type WeightedSuffrage struct {
+	*logging.Logging
+	stateKey        string
+	genesis         base.SuffrageWeights
+	cacheSize       int
+	cache           *lru.TwoQueueCache
+	getStateFunc    func(string, base.Height) (state.State, bool, error)
+	lastHeightFunc  func() (base.Height, error)
+	getManifestFunc func(base.Height) (valuehash.Hash, error)
+}
+
+func NewWeightedSuffrage(
+	stateKey string,
+	genesis base.SuffrageWeights,
+	cacheSize int,
+	getStateFunc func(string, base.Height) (state.State, bool, error),
+	lastHeightFunc func() (base.Height, error),
+	getManifestFunc func(base.Height) (valuehash.Hash, error),
+) (*WeightedSuffrage, error) {
+	if len(stateKey) < 1 {
+		return nil, errors.Errorf("empty state key")
+	}
+
+	if err := genesis.IsValid(nil); err != nil {
+		return nil, errors.Wrap(err, "invalid genesis suffrage weights")
+	}
+
+	var cache *lru.TwoQueueCache
+	if cacheSize > 0 {
+		cache, _ = lru.New2Q(cacheSize)
+	}
+
+	return &WeightedSuffrage{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "weighted-suffrage")
+		}),
+		stateKey:        stateKey,
+		genesis:         genesis,
+		cacheSize:       cacheSize,
+		cache:           cache,
+		getStateFunc:    getStateFunc,
+		lastHeightFunc:  lastHeightFunc,
+		getManifestFunc: getManifestFunc,
+	}, nil
+}
+
+func (*WeightedSuffrage) Initialize() error {
+	return nil
+}
+
+func (*WeightedSuffrage) Name() string {
+	return "weighted-suffrage"
+}
+
+func (sf *WeightedSuffrage) StateKey() string {
+	return sf.stateKey
+}
+
+func (sf *WeightedSuffrage) Genesis() base.SuffrageWeights {
+	return sf.genesis
+}
+
+func (sf *WeightedSuffrage) CacheSize() int {
+	return sf.cacheSize
+}
+
+// Weights returns the SuffrageWeights of height. If the previous height of
+// height is not yet stored, util.NotFoundError is returned.
+func (sf *WeightedSuffrage) Weights(height base.Height) (base.SuffrageWeights, error) {
+	prev := height - 1
+	if prev <= base.PreGenesisHeight || sf.getStateFunc == nil {
+		return sf.genesis, nil
+	}
+
+	if sf.cache != nil {
+		if i, found := sf.cache.Get(prev.String()); found {
+			return i.(base.SuffrageWeights), nil
+		}
+	}
+
+	// NOTE the weights of the not yet stored height are unknown; the state of
+	// them can be changed.
+	if sf.lastHeightFunc != nil {
+		switch last, err := sf.lastHeightFunc(); {
+		case err != nil:
+			return base.SuffrageWeights{}, err
+		case prev > last:
+			return base.SuffrageWeights{}, util.NotFoundError.Errorf(
+				"suffrage weights of height, %d not found; last height is %d", height, last)
+		}
+	}
+
+	sw, err := sf.loadWeights(prev)
+	if err != nil {
+		return base.SuffrageWeights{}, err
+	}
+
+	if sf.cache != nil {
+		sf.cache.Add(prev.String(), sw)
+	}
+
+	return sw, nil
+}
+
+func (sf *WeightedSuffrage) NumberOfActing() uint {
+	return uint(len(sf.Nodes()))
+}
+
+func (sf *WeightedSuffrage) Acting(height base.Height, round base.Round) (base.ActingSuffrage, error) {
+	sw, err := sf.Weights(height)
+	if err != nil {
+		return base.ActingSuffrage{}, err
+	}
+
+	proposer, err := sf.proposer(height, round, sw)
+	if err != nil {
+		return base.ActingSuffrage{}, err
+	}
+
+	return base.NewActingSuffrage(height, round, proposer, sw.Nodes()), nil
+}
+
+func (sf *WeightedSuffrage) IsInside(a base.Address) bool {
+	return sf.current().Exists(a)
+}
+
+func (sf *WeightedSuffrage) IsActing(height base.Height, round base.Round, n base.Address) (bool, error) {
+	af, err := sf.Acting(height, round)
+	if err != nil {
+		return false, err
+	}
+
+	return af.Exists(n), nil
+}
+
+func (sf *WeightedSuffrage) IsProposer(height base.Height, round base.Round, n base.Address) (bool, error) {
+	af, err := sf.Acting(height, round)
+	if err != nil {
+		return false, err
+	}
+
+	return af.Proposer().Equal(n), nil
+}
+
+// Nodes returns the suffrage nodes of next height.
+func (sf *WeightedSuffrage) Nodes() []base.Address {
+	return sf.current().Nodes()
+}
+
+func (sf *WeightedSuffrage) Verbose() string {
+	m := map[string]interface{}{
+		"type":       sf.Name(),
+		"cache_size": sf.CacheSize(),
+		"state_key":  sf.stateKey,
+		"genesis":    sf.genesis,
+	}
+
+	b, err := jsonenc.Marshal(m)
+	if err != nil {
+		_, _ = fmt.Fprintf(
+			os.Stderr,
+			"%+v\n",
+			errors.Wrap(err, "failed to marshal WeightedSuffrage.Verbose()").Error(),
+		)
+
+		return sf.Name()
+	}
+	return string(b)
+}
+
+func (sf *WeightedSuffrage) current() base.SuffrageWeights {
+	height := base.GenesisHeight
+	if sf.lastHeightFunc != nil {
+		switch i, err := sf.lastHeightFunc(); {
+		case err != nil:
+			sf.Log().Error().Err(err).Msg("failed to get last height")
+		default:
+			height = i + 1
+		}
+	}
+
+	sw, err := sf.Weights(height)
+	if err != nil {
+		sf.Log().Error().Err(err).Int64("height", height.Int64()).Msg("failed to get suffrage weights")
+
+		return sf.genesis
+	}
+
+	return sw
+}
+
+func (sf *WeightedSuffrage) loadWeights(height base.Height) (base.SuffrageWeights, error) {
+	switch st, found, err := sf.getStateFunc(sf.stateKey, height); {
+	case err != nil:
+		return base.SuffrageWeights{}, errors.Wrap(err, "failed to load suffrage weights state")
+	case !found:
+		return sf.genesis, nil
+	default:
+		sw, ok := st.Value().Interface().(base.SuffrageWeights)
+		if !ok {
+			return base.SuffrageWeights{}, errors.Errorf(
+				"invalid suffrage weights state value, %T", st.Value().Interface())
+		}
+
+		return sw, nil
+	}
+}
+
+// proposer selects the proposer by weights; the node, which has more weight,
+// has more chance to be proposer.
+func (sf *WeightedSuffrage) proposer(
+	height base.Height,
+	round base.Round,
+	sw base.SuffrageWeights,
+) (base.Address, error) {
+	nodes := sw.Nodes()
+	if h := height - 1; h <= base.PreGenesisHeight {
+		return nodes[0], nil
+	}
+
+	var sum uint64
+
+	// NOTE get manifest of previous height
+	if sf.getManifestFunc != nil {
+		switch h, err := sf.getManifestFunc(height - 1); {
+		case err != nil:
+			return nil, err
+		default:
+			for _, b := range h.Bytes() {
+				sum += uint64(b)
+			}
+		}
+	}
+
+	sum += uint64(height.Int64()) + round.Uint64()
+
+	pos := sum % uint64(sw.Total())
+
+	weights := sw.Weights()
+
+	var cumulative uint64
+	for i := range weights {
+		cumulative += uint64(weights[i])
+		if pos < cumulative {
+			return nodes[i], nil
+		}
+	}
+
+	return nodes[len(nodes)-1], nil
+}
+
+// WeightedSuffrageNodepoolHook returns the block saved hook, which updates
+// the nodepool by the suffrage states of the saved blocks. The nodes joined
+// by JoinSuffrage are added to the nodepool, and when they leave suffrage,
+// they are removed from the nodepool; the nodes, which are already in nodepool
+// like the nodes of config, are not removed.
+//
+// Before the hook is returned, the joined nodes of the current suffrage are
+// loaded from the node states of the last block by statesFunc, so the nodes
+// joined before restarting are also in the nodepool.
+//
+// The channel of joined node is loaded by channelFunc. If channelFunc returns
+// nil channel, for example the node is not yet discovered, the channel will be
+// set by discovery when the node joins.
+func WeightedSuffrageNodepoolHook(
+	sf *WeightedSuffrage,
+	nodepool *network.Nodepool,
+	statesFunc func(base.Height, func(state.State) (bool, error)) error,
+	channelFunc func(base.Address) (network.Channel, error),
+) (pm.ProcessFunc, error) {
+	wn := &weightedSuffrageNodepool{
+		sf:          sf,
+		nodepool:    nodepool,
+		channelFunc: channelFunc,
+		nodePrefix:  membership.NodeStateKeyPrefix(sf.StateKey()),
+	}
+
+	if err := wn.load(statesFunc); err != nil {
+		return nil, errors.Wrap(err, "failed to load joined nodes")
+	}
+
+	return wn.hook, nil
+}
+
+type weightedSuffrageNodepool struct {
+	sf          *WeightedSuffrage
+	nodepool    *network.Nodepool
+	channelFunc func(base.Address) (network.Channel, error)
+	nodePrefix  string
+	added       sync.Map
+}
+
+func (wn *weightedSuffrageNodepool) load(statesFunc func(base.Height, func(state.State) (bool, error)) error) error {
+	if statesFunc == nil || wn.sf.lastHeightFunc == nil {
+		return nil
+	}
+
+	last, err := wn.sf.lastHeightFunc()
+	switch {
+	case err != nil:
+		return err
+	case last <= base.PreGenesisHeight:
+		return nil
+	}
+
+	sw := wn.sf.current()
+
+	return statesFunc(last, func(st state.State) (bool, error) {
+		if st.Value() == nil || !strings.HasPrefix(st.Key(), wn.nodePrefix) {
+			return true, nil
+		}
+
+		n, ok := st.Value().Interface().(base.Node)
+		if !ok || !sw.Exists(n.Address()) {
+			return true, nil
+		}
+
+		if err := wn.add(n); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	})
+}
+
+func (wn *weightedSuffrageNodepool) add(n base.Node) error {
+	if wn.nodepool.Exists(n.Address()) {
+		return nil
+	}
+
+	var ch network.Channel
+	if wn.channelFunc != nil {
+		i, err := wn.channelFunc(n.Address())
+		if err != nil {
+			wn.sf.Log().Error().Err(err).Stringer("node", n.Address()).Msg("failed to load channel of joined node")
+		}
+		ch = i
+	}
+
+	if err := wn.nodepool.Add(node.NewRemote(n.Address(), n.Publickey()), ch); err != nil {
+		return err
+	}
+
+	wn.added.Store(n.Address().String(), n.Address())
+
+	wn.sf.Log().Debug().Stringer("node", n.Address()).Msg("joined node added to nodepool")
+
+	return nil
+}
+
+func (wn *weightedSuffrageNodepool) hook(ctx context.Context) (context.Context, error) {
+	var blks []block.Block
+	if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+		return ctx, err
+	}
+
+	var sw *base.SuffrageWeights
+	for i := range blks {
+		sts := blks[i].States()
+		for j := range sts {
+			st := sts[j]
+			if st.Value() == nil {
+				continue
+			}
+
+			switch {
+			case st.Key() == wn.sf.StateKey():
+				if i, ok := st.Value().Interface().(base.SuffrageWeights); ok {
+					sw = &i
+				}
+			case strings.HasPrefix(st.Key(), wn.nodePrefix):
+				n, ok := st.Value().Interface().(base.Node)
+				if !ok {
+					continue
+				}
+
+				if err := wn.add(n); err != nil {
+					return ctx, err
+				}
+			}
+		}
+	}
+
+	if sw == nil {
+		return ctx, nil
+	}
+
+	var removes []base.Address
+	wn.added.Range(func(k, v interface{}) bool {
+		a := v.(base.Address)
+		if !sw.Exists(a) && wn.nodepool.Exists(a) {
+			removes = append(removes, a)
+		}
+
+		return true
+	})
+
+	if len(removes) < 1 {
+		return ctx, nil
+	}
+
+	if err := wn.nodepool.Remove(removes...); err != nil {
+		return ctx, err
+	}
+
+	for i := range removes {
+		wn.added.Delete(removes[i].String())
+	}
+
+	wn.sf.Log().Debug().Interface("nodes", removes).Msg("left nodes removed from nodepool")
+
+	return ctx, nil
+}
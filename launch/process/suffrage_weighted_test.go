@@ -0,0 +1,272 @@
+package process
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/membership"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testWeightedSuffrage struct {
+	suite.Suite
+}
+
+func (t *testWeightedSuffrage) nodes(n int) []base.Address {
+	nodes := make([]base.Address, n)
+	for i := 0; i < n; i++ {
+		nodes[i] = base.RandomStringAddress()
+	}
+
+	return nodes
+}
+
+func (t *testWeightedSuffrage) newState(key string, sw base.SuffrageWeights, height base.Height) state.State {
+	v, err := state.NewHintedValue(sw)
+	t.NoError(err)
+
+	st, err := state.NewStateV0(key, v, height)
+	t.NoError(err)
+
+	return st
+}
+
+func (t *testWeightedSuffrage) TestNew() {
+	sf, err := NewWeightedSuffrage("sw", base.NewEqualSuffrageWeights(t.nodes(3)), 1, nil, nil, nil)
+	t.NoError(err)
+	t.NotNil(sf)
+
+	t.Implements((*base.Suffrage)(nil), sf)
+	t.Implements((*base.WeightedSuffrage)(nil), sf)
+}
+
+func (t *testWeightedSuffrage) TestInvalidGenesis() {
+	_, err := NewWeightedSuffrage("sw", base.NewSuffrageWeights(nil), 1, nil, nil, nil)
+	t.Contains(err.Error(), "invalid genesis suffrage weights")
+}
+
+func (t *testWeightedSuffrage) TestWeightsFromState() {
+	nodes := t.nodes(3)
+	genesis := base.NewEqualSuffrageWeights(nodes)
+	changed := base.NewSuffrageWeights(map[base.Address]uint{nodes[0]: 3, nodes[1]: 1})
+
+	last := base.Height(10)
+	sf, err := NewWeightedSuffrage(
+		"sw",
+		genesis,
+		10,
+		func(key string, height base.Height) (state.State, bool, error) {
+			if height < base.Height(5) { // NOTE changed at height 5
+				return nil, false, nil
+			}
+
+			return t.newState(key, changed, base.Height(5)), true, nil
+		},
+		func() (base.Height, error) {
+			return last, nil
+		},
+		nil,
+	)
+	t.NoError(err)
+
+	sw, err := sf.Weights(base.Height(5))
+	t.NoError(err)
+	t.True(genesis.Equal(sw))
+
+	sw, err = sf.Weights(base.Height(6))
+	t.NoError(err)
+	t.True(changed.Equal(sw))
+
+	t.True(sf.IsInside(nodes[0]))
+	t.False(sf.IsInside(nodes[2]))
+	t.Equal(changed.Nodes(), sf.Nodes())
+	t.Equal(uint(2), sf.NumberOfActing())
+
+	af, err := sf.Acting(base.Height(5), base.Round(0))
+	t.NoError(err)
+	t.Equal(genesis.Nodes(), af.Nodes())
+
+	af, err = sf.Acting(base.Height(6), base.Round(0))
+	t.NoError(err)
+	t.Equal(changed.Nodes(), af.Nodes())
+
+	{ // NOTE next height of last height
+		sw, err := sf.Weights(last + 1)
+		t.NoError(err)
+		t.True(changed.Equal(sw))
+	}
+
+	{ // NOTE previous height of height is higher than last height
+		_, err := sf.Weights(last + 2)
+		t.True(errors.Is(err, util.NotFoundError))
+
+		_, err = sf.Acting(last+2, base.Round(0))
+		t.True(errors.Is(err, util.NotFoundError))
+	}
+}
+
+func (t *testWeightedSuffrage) TestProposerByWeights() {
+	nodes := t.nodes(2)
+	sw := base.NewSuffrageWeights(map[base.Address]uint{nodes[0]: 9, nodes[1]: 1})
+
+	sf, err := NewWeightedSuffrage("sw", sw, 10, nil, nil, func(base.Height) (valuehash.Hash, error) {
+		return valuehash.NewBytes([]byte("showme")), nil
+	})
+	t.NoError(err)
+
+	counts := map[string]int{}
+	for i := 0; i < 100; i++ {
+		af, err := sf.Acting(base.Height(33), base.Round(i))
+		t.NoError(err)
+
+		counts[af.Proposer().String()]++
+	}
+
+	heavy := sw.Nodes()[0]
+	if sw.Weight(heavy) != 9 {
+		heavy = sw.Nodes()[1]
+	}
+
+	t.Equal(90, counts[heavy.String()])
+}
+
+func (t *testWeightedSuffrage) TestNodepoolHook() {
+	nodes := t.nodes(2)
+	genesis := base.NewEqualSuffrageWeights(nodes[:1])
+
+	sf, err := NewWeightedSuffrage("sw", genesis, 0, nil, nil, nil)
+	t.NoError(err)
+
+	joined := node.NewBaseV0(nodes[1], key.NewBasePrivatekey().Publickey())
+	v, err := state.NewHintedValue(joined)
+	t.NoError(err)
+
+	st, err := state.NewStateV0(membership.NodeStateKey(sf.StateKey(), joined.Address()), v, base.Height(33))
+	t.NoError(err)
+
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+	blk = blk.SetStates([]state.State{st}).(block.BlockV0)
+
+	nodepool := network.NewNodepool(node.RandomLocal("local"), nil)
+
+	ch := network.NewDummyChannel(network.NewNilConnInfo("joined"))
+	hook, err := WeightedSuffrageNodepoolHook(sf, nodepool, nil, func(a base.Address) (network.Channel, error) {
+		if !a.Equal(joined.Address()) {
+			return nil, nil
+		}
+
+		return ch, nil
+	})
+	t.NoError(err)
+
+	ctx := context.WithValue(context.Background(), basicstate.ContextValueBlockSaved, []block.Block{blk})
+	_, err = hook(ctx)
+	t.NoError(err)
+
+	// NOTE joined node is added with the channel of channelFunc
+	n, nch, found := nodepool.Node(joined.Address())
+	t.True(found)
+	t.True(joined.Publickey().Equal(n.Publickey()))
+	t.Equal(ch, nch)
+}
+
+// TestNodepoolHookRestart tests, the joined nodes are loaded from the node
+// states of database when the hook is created, like after restarting.
+func (t *testWeightedSuffrage) TestNodepoolHookRestart() {
+	nodes := t.nodes(3)
+	genesis := base.NewEqualSuffrageWeights(nodes[:1])
+
+	joined := node.NewBaseV0(nodes[1], key.NewBasePrivatekey().Publickey())
+	left := node.NewBaseV0(nodes[2], key.NewBasePrivatekey().Publickey())
+
+	// NOTE nodes[2] joined and left before restarting
+	sw := base.NewEqualSuffrageWeights(nodes[:2])
+
+	last := base.Height(33)
+
+	var sts []state.State
+	for _, n := range []node.BaseV0{joined, left} {
+		v, err := state.NewHintedValue(n)
+		t.NoError(err)
+
+		st, err := state.NewStateV0(membership.NodeStateKey("sw", n.Address()), v, base.Height(30))
+		t.NoError(err)
+
+		sts = append(sts, st)
+	}
+
+	sts = append(sts, t.newState("sw", sw, last))
+
+	sf, err := NewWeightedSuffrage(
+		"sw",
+		genesis,
+		0,
+		func(key string, height base.Height) (state.State, bool, error) {
+			for i := range sts {
+				if sts[i].Key() == key {
+					return sts[i], true, nil
+				}
+			}
+
+			return nil, false, nil
+		},
+		func() (base.Height, error) {
+			return last, nil
+		},
+		nil,
+	)
+	t.NoError(err)
+
+	statesFunc := func(height base.Height, callback func(state.State) (bool, error)) error {
+		t.Equal(last, height)
+
+		for i := range sts {
+			if keep, err := callback(sts[i]); err != nil {
+				return err
+			} else if !keep {
+				break
+			}
+		}
+
+		return nil
+	}
+
+	nodepool := network.NewNodepool(node.RandomLocal("local"), nil)
+
+	hook, err := WeightedSuffrageNodepoolHook(sf, nodepool, statesFunc, nil)
+	t.NoError(err)
+
+	// NOTE the joined node of current suffrage is loaded
+	n, _, found := nodepool.Node(joined.Address())
+	t.True(found)
+	t.True(joined.Publickey().Equal(n.Publickey()))
+
+	// NOTE the left node is not loaded
+	t.False(nodepool.Exists(left.Address()))
+
+	// NOTE the loaded node is removed by hook, when it leaves suffrage
+	blk, err := block.NewTestBlockV0(last+1, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+	blk = blk.SetStates([]state.State{t.newState("sw", genesis, last+1)}).(block.BlockV0)
+
+	ctx := context.WithValue(context.Background(), basicstate.ContextValueBlockSaved, []block.Block{blk})
+	_, err = hook(ctx)
+	t.NoError(err)
+
+	t.False(nodepool.Exists(joined.Address()))
+}
+
+func TestWeightedSuffrage(t *testing.T) {
+	suite.Run(t, new(testWeightedSuffrage))
+}
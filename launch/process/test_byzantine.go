@@ -0,0 +1,127 @@
+//go:build test
+// +build test
+
+package process
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/states"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util/logging"
+	"gopkg.in/yaml.v3"
+)
+
+const HookNameByzantine = "byzantine"
+
+// HookByzantine makes the local node act maliciously by the "byzantine" of node
+// design. This is only for testing, so it is only available in test build.
+func HookByzantine(ctx context.Context) (context.Context, error) {
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return ctx, err
+	}
+
+	v, found := conf.Source()["byzantine"]
+	if !found || v == nil {
+		return ctx, nil
+	}
+
+	rules, err := parseByzantineRules(v)
+	if err != nil {
+		return ctx, errors.Wrap(err, "invalid byzantine")
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	ss, ok := cs.(*basicstate.States)
+	if !ok {
+		return ctx, errors.Errorf("byzantine needs *basicstates.States, not %T", cs)
+	}
+
+	if err := ss.SetByzantine(rules); err != nil {
+		return ctx, errors.Wrap(err, "invalid byzantine")
+	}
+
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	log.Log().Warn().Interface("byzantine", v).Msg("local node acts as byzantine node")
+
+	return ctx, nil
+}
+
+type byzantineRuleYAML struct {
+	Type  string
+	Stage string
+	From  *int64
+	To    *int64
+	Delay string
+}
+
+// parseByzantineRules parses the list of byzantine rules,
+//
+//	byzantine:
+//	  - type: conflicting-ballot
+//	    stage: INIT
+//	    from: 3
+//	    to: 10
+//	  - type: delay
+//	    delay: 3s
+func parseByzantineRules(v interface{}) ([]basicstate.ByzantineRule, error) {
+	var brs []byzantineRuleYAML
+
+	if b, err := yaml.Marshal(v); err != nil {
+		return nil, err
+	} else if err := yaml.Unmarshal(b, &brs); err != nil {
+		return nil, errors.Wrap(err, "invalid []ByzantineRule")
+	}
+
+	rules := make([]basicstate.ByzantineRule, len(brs))
+	for i := range brs {
+		br := brs[i]
+
+		rule := basicstate.NewByzantineRule(basicstate.ByzantineType(strings.TrimSpace(br.Type)))
+
+		if s := strings.TrimSpace(br.Stage); len(s) > 0 {
+			if err := rule.Stage.UnmarshalText([]byte(strings.ToUpper(s))); err != nil {
+				return nil, errors.Wrapf(err, "invalid stage, %q", s)
+			}
+		}
+
+		if br.From != nil {
+			rule.From = base.Height(*br.From)
+		}
+
+		if br.To != nil {
+			rule.To = base.Height(*br.To)
+		}
+
+		if s := strings.TrimSpace(br.Delay); len(s) > 0 {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid delay, %q", s)
+			}
+
+			rule.Delay = d
+		}
+
+		if err := rule.IsValid(nil); err != nil {
+			return nil, err
+		}
+
+		rules[i] = rule
+	}
+
+	return rules, nil
+}
@@ -4,16 +4,52 @@ import (
 	"bytes"
 	"context"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"path/filepath"
 
 	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
 )
 
+// FetchBlockdataMap requests the BlockdataMap of height thru channel and checks
+// it is valid.
+func FetchBlockdataMap(
+	ctx context.Context,
+	ch Channel,
+	height base.Height,
+	networkID base.NetworkID,
+) (block.BlockdataMap, error) {
+	maps, err := ch.BlockdataMaps(ctx, []base.Height{height})
+	switch {
+	case err != nil:
+		return nil, err
+	case len(maps) != 1 || maps[0] == nil:
+		return nil, util.NotFoundError.Errorf("block data map of height, %d not found", height)
+	case maps[0].Height() != height:
+		return nil, errors.Errorf("block data map has wrong height, %d != %d", maps[0].Height(), height)
+	}
+
+	if err := maps[0].IsValid(networkID); err != nil {
+		return nil, err
+	}
+
+	return maps[0], nil
+}
+
+// FetchBlockdata fetches the block data item; the local item of node is
+// requested thru channel and the remote item, like the item of s3 blockdata,
+// is fetched directly from it's url.
+func FetchBlockdata(ctx context.Context, ch Channel, item block.BlockdataMapItem) (io.ReadCloser, error) {
+	if block.IsLocalBlockdataItem(item.URL()) {
+		return ch.Blockdata(ctx, item)
+	}
+
+	return FetchBlockdataFromRemote(ctx, item)
+}
+
 func FetchBlockdataThruChannel(handler BlockdataHandler, item block.BlockdataMapItem) (io.ReadCloser, error) {
 	u, err := ParseURL(item.URL(), false)
 	if err != nil {
@@ -42,30 +78,7 @@ func FetchBlockdataThruChannel(handler BlockdataHandler, item block.BlockdataMap
 		return nil, errors.Errorf("%q not yet supported", u.Scheme)
 	}
 
-	b, err := io.ReadAll(ro)
-	if err != nil {
-		return nil, storage.MergeFSError(err)
-	}
-	bo := bytes.NewReader(b)
-
-	// NOTE check checksum
-	if i, err := util.GenerateChecksum(bo); err != nil {
-		return nil, err
-	} else if item.Checksum() != i {
-		return nil, errors.Errorf("block data, %q checksum does not match; %q != %q", item.Type(), item.Checksum(), i)
-	} else if _, err := bo.Seek(0, 0); err != nil {
-		return nil, err
-	}
-
-	rc := util.NewNilReadCloser(bo)
-
-	// NOTE is compressed?
-	switch ext := filepath.Ext(item.URL()); ext {
-	case ".gz":
-		return util.NewGzipReader(rc)
-	default:
-		return rc, nil
-	}
+	return readBlockdataItem(item, ro)
 }
 
 func FetchBlockdataFromRemote(ctx context.Context, item block.BlockdataMapItem) (io.ReadCloser, error) {
@@ -114,12 +127,37 @@ func FetchBlockdataFromHTTP(ctx context.Context, item block.BlockdataMapItem) (i
 
 	switch res.StatusCode {
 	case http.StatusOK:
-		i, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to request blockdata")
-		}
-		return io.NopCloser(bytes.NewBuffer(i)), nil
+		return readBlockdataItem(item, res.Body)
 	default:
 		return nil, errors.Errorf("failed to request blockdata: %q", res.Status)
 	}
 }
+
+// readBlockdataItem reads the raw block data and checks the checksum of item.
+// The compressed block data is decompressed.
+func readBlockdataItem(item block.BlockdataMapItem, r io.Reader) (io.ReadCloser, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, storage.MergeFSError(err)
+	}
+	bo := bytes.NewReader(b)
+
+	// NOTE check checksum
+	if i, err := util.GenerateChecksum(bo); err != nil {
+		return nil, err
+	} else if item.Checksum() != i {
+		return nil, errors.Errorf("block data, %q checksum does not match; %q != %q", item.Type(), item.Checksum(), i)
+	} else if _, err := bo.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	rc := util.NewNilReadCloser(bo)
+
+	// NOTE is compressed?
+	switch ext := filepath.Ext(item.URL()); ext {
+	case ".gz":
+		return util.NewGzipReader(rc)
+	default:
+		return rc, nil
+	}
+}
@@ -3,8 +3,11 @@
 package network
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/spikeekips/mitum/base/block"
@@ -16,8 +19,37 @@ type testBlockdataFetchRemote struct {
 	suite.Suite
 }
 
+func (t *testBlockdataFetchRemote) newServer(body []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unknown" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write(body)
+	}))
+}
+
+func (t *testBlockdataFetchRemote) compressed(s string) []byte {
+	buf := bytes.NewBuffer(nil)
+	w := util.NewGzipWriter(buf)
+	_, err := w.Write([]byte(s))
+	t.NoError(err)
+	t.NoError(w.Close())
+
+	return buf.Bytes()
+}
+
 func (t *testBlockdataFetchRemote) TestNew() {
-	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, util.UUID().String(), "http://google.com")
+	body := t.compressed(util.UUID().String())
+	checksum, err := util.GenerateChecksum(bytes.NewReader(body))
+	t.NoError(err)
+
+	ts := t.newServer(body)
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, checksum, ts.URL+"/33-manifest-a.jsonld.gz")
 	r, err := FetchBlockdataFromRemote(context.Background(), item)
 	t.NoError(err)
 
@@ -26,10 +58,42 @@ func (t *testBlockdataFetchRemote) TestNew() {
 	t.NotNil(b)
 }
 
-func (t *testBlockdataFetchRemote) TestHTTPS() {
-	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, util.UUID().String(), "https://google.com")
-	_, err := FetchBlockdataFromRemote(context.Background(), item)
+func (t *testBlockdataFetchRemote) TestDecompressed() {
+	s := util.UUID().String()
+	body := t.compressed(s)
+	checksum, err := util.GenerateChecksum(bytes.NewReader(body))
 	t.NoError(err)
+
+	ts := t.newServer(body)
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, checksum, ts.URL+"/33-manifest-a.jsonld.gz")
+	r, err := FetchBlockdataFromRemote(context.Background(), item)
+	t.NoError(err)
+
+	b, err := ioutil.ReadAll(r)
+	t.NoError(err)
+	t.Equal(s, string(b))
+}
+
+func (t *testBlockdataFetchRemote) TestChecksumMismatch() {
+	ts := t.newServer(t.compressed(util.UUID().String()))
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(
+		block.BlockdataManifest, util.UUID().String(), ts.URL+"/33-manifest-a.jsonld.gz",
+	)
+	_, err := FetchBlockdataFromRemote(context.Background(), item)
+	t.Contains(err.Error(), "checksum does not match")
+}
+
+func (t *testBlockdataFetchRemote) TestNotFound() {
+	ts := t.newServer(nil)
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, util.UUID().String(), ts.URL+"/unknown")
+	_, err := FetchBlockdataFromRemote(context.Background(), item)
+	t.Contains(err.Error(), "404")
 }
 
 func (t *testBlockdataFetchRemote) TestUnknownScheme() {
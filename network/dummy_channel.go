@@ -7,9 +7,12 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
 
@@ -19,6 +22,14 @@ type DummyChannel struct {
 	newSealHandler             NewSealHandler
 	getProposalHandler         GetProposalHandler
 	getStateHandler            GetStateHandler
+	getStateProofHandler       GetStateProofHandler
+	getOperationProofHandler   GetOperationProofHandler
+	getStatePathProofHandler   GetStatePathProofHandler
+	getPendingOperations       GetPendingOperationsHandler
+	getOperationStatus         GetOperationStatusHandler
+	getEquivocations           GetEquivocationsHandler
+	getOperationsBySigner      GetOperationsBySignerHandler
+	getOperationsByHint        GetOperationsByHintHandler
 	nodeInfoHandler            NodeInfoHandler
 	blockdataMapsHandler       BlockdataMapsHandler
 	blockdataHandler           BlockdataHandler
@@ -83,6 +94,115 @@ func (ch *DummyChannel) SetGetStateHandler(f GetStateHandler) {
 	ch.getStateHandler = f
 }
 
+func (ch *DummyChannel) StateProof(_ context.Context, key string) (StatePathProofV0, bool, error) {
+	if ch.getStateProofHandler == nil {
+		return StatePathProofV0{}, false, ch.notSupported()
+	}
+
+	return ch.getStateProofHandler(key)
+}
+
+func (ch *DummyChannel) SetGetStateProofHandler(f GetStateProofHandler) {
+	ch.getStateProofHandler = f
+}
+
+func (ch *DummyChannel) OperationProof(
+	_ context.Context, height base.Height, fact valuehash.Hash,
+) (OperationProofV0, bool, error) {
+	if ch.getOperationProofHandler == nil {
+		return OperationProofV0{}, false, ch.notSupported()
+	}
+
+	return ch.getOperationProofHandler(height, fact)
+}
+
+func (ch *DummyChannel) SetGetOperationProofHandler(f GetOperationProofHandler) {
+	ch.getOperationProofHandler = f
+}
+
+func (ch *DummyChannel) StatePathProof(_ context.Context, height base.Height, key string) (StatePathProofV0, bool, error) {
+	if ch.getStatePathProofHandler == nil {
+		return StatePathProofV0{}, false, ch.notSupported()
+	}
+
+	return ch.getStatePathProofHandler(height, key)
+}
+
+func (ch *DummyChannel) SetGetStatePathProofHandler(f GetStatePathProofHandler) {
+	ch.getStatePathProofHandler = f
+}
+
+func (ch *DummyChannel) PendingOperations(_ context.Context, limit uint) ([]PendingOperationV0, error) {
+	if ch.getPendingOperations == nil {
+		return nil, ch.notSupported()
+	}
+
+	return ch.getPendingOperations(limit)
+}
+
+func (ch *DummyChannel) SetGetPendingOperationsHandler(f GetPendingOperationsHandler) {
+	ch.getPendingOperations = f
+}
+
+func (ch *DummyChannel) OperationStatus(_ context.Context, fact valuehash.Hash) (operation.StatusV0, bool, error) {
+	if ch.getOperationStatus == nil {
+		return operation.StatusV0{}, false, ch.notSupported()
+	}
+
+	return ch.getOperationStatus(fact)
+}
+
+func (ch *DummyChannel) SetGetOperationStatusHandler(f GetOperationStatusHandler) {
+	ch.getOperationStatus = f
+}
+
+func (ch *DummyChannel) Equivocations(_ context.Context, limit uint) ([]base.EquivocationV0, error) {
+	if ch.getEquivocations == nil {
+		return nil, ch.notSupported()
+	}
+
+	return ch.getEquivocations(limit)
+}
+
+func (ch *DummyChannel) SetGetEquivocationsHandler(f GetEquivocationsHandler) {
+	ch.getEquivocations = f
+}
+
+func (ch *DummyChannel) OperationsBySigner(
+	_ context.Context,
+	signer key.Publickey,
+	cursor storage.OperationCursor,
+	limit int64,
+) (OperationsPageV0, error) {
+	if ch.getOperationsBySigner == nil {
+		return OperationsPageV0{}, ch.notSupported()
+	}
+
+	return ch.getOperationsBySigner(signer, cursor, limit)
+}
+
+func (ch *DummyChannel) SetGetOperationsBySignerHandler(f GetOperationsBySignerHandler) {
+	ch.getOperationsBySigner = f
+}
+
+func (ch *DummyChannel) OperationsByHint(
+	_ context.Context,
+	ht hint.Type,
+	from, to base.Height,
+	cursor storage.OperationCursor,
+	limit int64,
+) (OperationsPageV0, error) {
+	if ch.getOperationsByHint == nil {
+		return OperationsPageV0{}, ch.notSupported()
+	}
+
+	return ch.getOperationsByHint(ht, from, to, cursor, limit)
+}
+
+func (ch *DummyChannel) SetGetOperationsByHintHandler(f GetOperationsByHintHandler) {
+	ch.getOperationsByHint = f
+}
+
 func (ch *DummyChannel) NodeInfo(_ context.Context) (NodeInfo, error) {
 	if ch.nodeInfoHandler == nil {
 		return nil, ch.notSupported()
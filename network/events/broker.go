@@ -0,0 +1,82 @@
+package events
+
+import (
+	"sync"
+)
+
+var DefaultBrokerBufferSize = 100
+
+// Broker delivers the events to the subscribers. Publish does not wait the
+// slow subscriber; when the buffer of subscriber is full, the subscriber is
+// closed, so the consumer can reconnect and resume from the last height.
+type Broker struct {
+	sync.RWMutex
+	size int
+	subs map[uint64]chan BlockEvent
+	last uint64
+}
+
+func NewBroker(size int) *Broker {
+	if size < 1 {
+		size = DefaultBrokerBufferSize
+	}
+
+	return &Broker{
+		size: size,
+		subs: map[uint64]chan BlockEvent{},
+	}
+}
+
+// Subscribe returns the event channel and the cancel function, which should be
+// called when the subscriber is done.
+func (br *Broker) Subscribe() (<-chan BlockEvent, func()) {
+	br.Lock()
+	defer br.Unlock()
+
+	br.last++
+	id := br.last
+
+	ch := make(chan BlockEvent, br.size)
+	br.subs[id] = ch
+
+	return ch, func() {
+		br.Lock()
+		defer br.Unlock()
+
+		if i, found := br.subs[id]; found {
+			delete(br.subs, id)
+			close(i)
+		}
+	}
+}
+
+func (br *Broker) Publish(evs ...BlockEvent) {
+	br.Lock()
+	defer br.Unlock()
+
+	for id, ch := range br.subs {
+		if !sendEvents(ch, evs) {
+			delete(br.subs, id)
+			close(ch)
+		}
+	}
+}
+
+func (br *Broker) Len() int {
+	br.RLock()
+	defer br.RUnlock()
+
+	return len(br.subs)
+}
+
+func sendEvents(ch chan BlockEvent, evs []BlockEvent) bool {
+	for i := range evs {
+		select {
+		case ch <- evs[i]:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+func newTestBlockEvent(t *suite.Suite, height base.Height) BlockEvent {
+	blk, err := block.NewTestBlockV0(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	return NewBlockEvent(blk)
+}
+
+type testBroker struct {
+	suite.Suite
+}
+
+func (t *testBroker) TestPublish() {
+	br := NewBroker(10)
+
+	cha, cancela := br.Subscribe()
+	defer cancela()
+	chb, cancelb := br.Subscribe()
+
+	t.Equal(2, br.Len())
+
+	br.Publish(newTestBlockEvent(&t.Suite, 33), newTestBlockEvent(&t.Suite, 34))
+
+	for _, ch := range []<-chan BlockEvent{cha, chb} {
+		t.Equal(base.Height(33), (<-ch).Height())
+		t.Equal(base.Height(34), (<-ch).Height())
+	}
+
+	cancelb()
+	cancelb()
+	t.Equal(1, br.Len())
+
+	_, ok := <-chb
+	t.False(ok)
+}
+
+func (t *testBroker) TestSlowSubscriber() {
+	br := NewBroker(2)
+
+	ch, cancel := br.Subscribe()
+	defer cancel()
+
+	br.Publish(newTestBlockEvent(&t.Suite, 33), newTestBlockEvent(&t.Suite, 34))
+	t.Equal(1, br.Len())
+
+	// NOTE buffer is full
+	br.Publish(newTestBlockEvent(&t.Suite, 35))
+	t.Equal(0, br.Len())
+
+	t.Equal(base.Height(33), (<-ch).Height())
+	t.Equal(base.Height(34), (<-ch).Height())
+
+	_, ok := <-ch
+	t.False(ok)
+}
+
+func TestBroker(t *testing.T) {
+	suite.Run(t, new(testBroker))
+}
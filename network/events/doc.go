@@ -0,0 +1,4 @@
+/*
+Package events streams the saved blocks to the external consumers.
+*/
+package events
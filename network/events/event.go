@@ -0,0 +1,70 @@
+package events
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// BlockEvent is the event of saved block; it has the manifest, the facts of
+// the included operations and the keys of the changed states.
+type BlockEvent struct {
+	manifest   block.Manifest
+	operations []valuehash.Hash
+	states     []string
+}
+
+func NewBlockEvent(blk block.Block) BlockEvent {
+	ops := blk.Operations()
+	facts := make([]valuehash.Hash, len(ops))
+	for i := range ops {
+		facts[i] = ops[i].Fact().Hash()
+	}
+
+	sts := blk.States()
+	keys := make([]string, len(sts))
+	for i := range sts {
+		keys[i] = sts[i].Key()
+	}
+
+	return BlockEvent{
+		manifest:   blk.Manifest(),
+		operations: facts,
+		states:     keys,
+	}
+}
+
+func (ev BlockEvent) Height() base.Height {
+	return ev.manifest.Height()
+}
+
+func (ev BlockEvent) Manifest() block.Manifest {
+	return ev.manifest
+}
+
+// Operations returns the fact hashes of the included operations.
+func (ev BlockEvent) Operations() []valuehash.Hash {
+	return ev.operations
+}
+
+// States returns the keys of the changed states.
+func (ev BlockEvent) States() []string {
+	return ev.states
+}
+
+type BlockEventJSONPacker struct {
+	H  base.Height      `json:"height"`
+	MF block.Manifest   `json:"manifest"`
+	OP []valuehash.Hash `json:"operations"`
+	ST []string         `json:"states"`
+}
+
+func (ev BlockEvent) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(BlockEventJSONPacker{
+		H:  ev.Height(),
+		MF: ev.manifest,
+		OP: ev.operations,
+		ST: ev.states,
+	})
+}
@@ -0,0 +1,288 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const DefaultPath = "/events"
+
+var DefaultHeartbeat = time.Second * 15
+
+// BlockEventLoader loads the event of the stored block by height.
+type BlockEventLoader func(context.Context, base.Height) (BlockEvent, bool, error)
+
+// Server streams the block events thru server-sent events. With "from" query
+// or "Last-Event-ID" header, the events of the stored blocks are sent first,
+// so the consumer can resume from the given height.
+type Server struct {
+	*logging.Logging
+	*util.ContextDaemon
+	bind      string
+	broker    *Broker
+	loader    BlockEventLoader
+	heartbeat time.Duration
+	listener  net.Listener
+}
+
+func NewServer(bind string, broker *Broker, loader BlockEventLoader) *Server {
+	sv := &Server{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "events-server")
+		}),
+		bind:      bind,
+		broker:    broker,
+		loader:    loader,
+		heartbeat: DefaultHeartbeat,
+	}
+
+	sv.ContextDaemon = util.NewContextDaemon("events-server", sv.run)
+
+	return sv
+}
+
+func (sv *Server) SetLogging(l *logging.Logging) *logging.Logging {
+	_ = sv.ContextDaemon.SetLogging(l)
+
+	return sv.Logging.SetLogging(l)
+}
+
+func (sv *Server) Bind() string {
+	return sv.bind
+}
+
+func (sv *Server) Broker() *Broker {
+	return sv.broker
+}
+
+func (sv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(DefaultPath, sv.handleEvents)
+
+	return mux
+}
+
+// Start opens the listener before the daemon starts, so the failure of bind
+// is returned.
+func (sv *Server) Start() error {
+	if sv.ContextDaemon.IsStarted() {
+		return util.DaemonAlreadyStartedError
+	}
+
+	listener, err := sv.listen()
+	if err != nil {
+		return err
+	}
+
+	sv.listener = listener
+
+	if err := sv.ContextDaemon.Start(); err != nil {
+		_ = listener.Close()
+
+		return err
+	}
+
+	return nil
+}
+
+func (sv *Server) listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", sv.bind)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open events server, %q", sv.bind)
+	}
+
+	return listener, nil
+}
+
+func (sv *Server) run(ctx context.Context) error {
+	listener := sv.listener
+	sv.listener = nil
+
+	if listener == nil {
+		i, err := sv.listen()
+		if err != nil {
+			return err
+		}
+
+		listener = i
+	}
+
+	server := &http.Server{Handler: sv.Handler(), ReadHeaderTimeout: time.Second * 3}
+
+	sv.Log().Debug().Str("bind", sv.bind).Msg("events server started")
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			sv.Log().Error().Err(err).Msg("events server failed")
+
+			errChan <- err
+		}
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		sctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		return server.Shutdown(sctx) // nolint:contextcheck
+	}
+}
+
+func (sv *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	next, err := parseFromHeight(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	// NOTE subscribe before replaying, so the events while replaying are not
+	// missed.
+	ch, cancel := sv.broker.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	l := sv.Log().With().Str("remote", r.RemoteAddr).Logger()
+	l.Debug().Int64("from", next.Int64()).Msg("events consumer connected")
+
+	ctx := r.Context()
+
+	if next > base.NilHeight {
+		if next, err = sv.replay(ctx, w, next, base.NilHeight); err != nil {
+			l.Error().Err(err).Msg("failed to replay events")
+
+			return
+		}
+		flusher.Flush()
+	}
+
+	ticker := time.NewTicker(sv.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case ev, ok := <-ch:
+			if !ok {
+				l.Debug().Msg("events consumer is too slow; closed")
+
+				return
+			}
+
+			switch {
+			case next <= base.NilHeight:
+			case ev.Height() < next:
+				continue
+			case ev.Height() > next:
+				// NOTE fill the missing events from the stored blocks
+				if next, err = sv.replay(ctx, w, next, ev.Height()-1); err != nil {
+					l.Error().Err(err).Msg("failed to replay events")
+
+					return
+				}
+			}
+
+			if err := writeEvent(w, ev); err != nil {
+				return
+			}
+
+			next = ev.Height() + 1
+		}
+
+		flusher.Flush()
+	}
+}
+
+// replay sends the events of the stored blocks from the given height to the
+// given height. If to is NilHeight, sends until the last stored block. It
+// returns the next height.
+func (sv *Server) replay(ctx context.Context, w io.Writer, from, to base.Height) (base.Height, error) {
+	next := from
+	for to <= base.NilHeight || next <= to {
+		ev, found, err := sv.loader(ctx, next)
+		switch {
+		case err != nil:
+			return next, err
+		case !found:
+			return next, nil
+		}
+
+		if err := writeEvent(w, ev); err != nil {
+			return next, err
+		}
+
+		next++
+	}
+
+	return next, nil
+}
+
+func writeEvent(w io.Writer, ev BlockEvent) error {
+	b, err := jsonenc.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: block\ndata: %s\n\n", ev.Height(), b)
+
+	return err
+}
+
+// parseFromHeight returns the height to be resumed from. "from" query is the
+// first height to be sent and "Last-Event-ID" header is the last height
+// received. Without both, NilHeight is returned and only the new events are
+// sent.
+func parseFromHeight(r *http.Request) (base.Height, error) {
+	if s := strings.TrimSpace(r.URL.Query().Get("from")); len(s) > 0 {
+		h, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || h < base.PreGenesisHeight.Int64() {
+			return base.NilHeight, errors.Errorf("invalid from height, %q", s)
+		}
+
+		return base.Height(h), nil
+	}
+
+	if s := strings.TrimSpace(r.Header.Get("Last-Event-ID")); len(s) > 0 {
+		h, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || h < base.PreGenesisHeight.Int64() {
+			return base.NilHeight, errors.Errorf("invalid Last-Event-ID, %q", s)
+		}
+
+		return base.Height(h + 1), nil
+	}
+
+	return base.NilHeight, nil
+}
@@ -0,0 +1,214 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testServer struct {
+	suite.Suite
+	sync.RWMutex
+	blocks map[base.Height]BlockEvent
+}
+
+func (t *testServer) SetupTest() {
+	t.blocks = map[base.Height]BlockEvent{}
+}
+
+func (t *testServer) save(heights ...base.Height) []BlockEvent {
+	t.Lock()
+	defer t.Unlock()
+
+	evs := make([]BlockEvent, len(heights))
+	for i := range heights {
+		evs[i] = newTestBlockEvent(&t.Suite, heights[i])
+		t.blocks[heights[i]] = evs[i]
+	}
+
+	return evs
+}
+
+func (t *testServer) loader(_ context.Context, height base.Height) (BlockEvent, bool, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	ev, found := t.blocks[height]
+
+	return ev, found, nil
+}
+
+func (t *testServer) newServer() (*Server, *httptest.Server) {
+	sv := NewServer("", NewBroker(10), t.loader)
+
+	return sv, httptest.NewServer(sv.Handler())
+}
+
+func (t *testServer) connect(ts *httptest.Server, query string, header http.Header) (*http.Response, *bufio.Reader) {
+	req, err := http.NewRequest(http.MethodGet, ts.URL+DefaultPath+query, nil)
+	t.NoError(err)
+
+	for k := range header {
+		req.Header.Set(k, header.Get(k))
+	}
+
+	res, err := ts.Client().Do(req)
+	t.NoError(err)
+
+	return res, bufio.NewReader(res.Body)
+}
+
+// readIDs reads the ids of the events.
+func (t *testServer) readIDs(r *bufio.Reader, n int) []string {
+	var ids []string
+
+	for len(ids) < n {
+		line, err := r.ReadString('\n')
+		t.NoError(err)
+
+		if strings.HasPrefix(line, "id: ") {
+			ids = append(ids, strings.TrimSpace(strings.TrimPrefix(line, "id: ")))
+		}
+	}
+
+	return ids
+}
+
+func (t *testServer) waitSubscribed(sv *Server, n int) {
+	t.NoError(util.Retry(30, time.Millisecond*10, func(int) error {
+		if sv.Broker().Len() != n {
+			return util.NotFoundError
+		}
+
+		return nil
+	}))
+}
+
+func (t *testServer) TestLive() {
+	sv, ts := t.newServer()
+	defer ts.Close()
+
+	res, r := t.connect(ts, "", nil)
+	defer res.Body.Close()
+
+	t.Equal(http.StatusOK, res.StatusCode)
+	t.Equal("text/event-stream", res.Header.Get("Content-Type"))
+
+	t.waitSubscribed(sv, 1)
+
+	t.save(30) // NOTE not published
+
+	sv.Broker().Publish(t.save(33, 34)...)
+
+	t.Equal([]string{"33", "34"}, t.readIDs(r, 2))
+}
+
+func (t *testServer) TestResumeFrom() {
+	t.save(30, 31, 32)
+
+	sv, ts := t.newServer()
+	defer ts.Close()
+
+	res, r := t.connect(ts, "?from=31", nil)
+	defer res.Body.Close()
+
+	t.Equal([]string{"31", "32"}, t.readIDs(r, 2))
+
+	t.waitSubscribed(sv, 1)
+
+	// NOTE 33 is missed and 32 is already sent
+	evs := t.save(32, 33, 34)
+	sv.Broker().Publish(evs[0], evs[2])
+
+	t.Equal([]string{"33", "34"}, t.readIDs(r, 2))
+}
+
+func (t *testServer) TestResumeLastEventID() {
+	t.save(30, 31, 32)
+
+	_, ts := t.newServer()
+	defer ts.Close()
+
+	header := http.Header{}
+	header.Set("Last-Event-ID", "30")
+
+	res, r := t.connect(ts, "", header)
+	defer res.Body.Close()
+
+	t.Equal([]string{"31", "32"}, t.readIDs(r, 2))
+}
+
+func (t *testServer) TestEventData() {
+	sv, ts := t.newServer()
+	defer ts.Close()
+
+	res, r := t.connect(ts, "", nil)
+	defer res.Body.Close()
+
+	t.waitSubscribed(sv, 1)
+
+	evs := t.save(33)
+	sv.Broker().Publish(evs...)
+
+	var data string
+	for {
+		line, err := r.ReadString('\n')
+		t.NoError(err)
+
+		if strings.HasPrefix(line, "data: ") {
+			data = strings.TrimPrefix(line, "data: ")
+
+			break
+		}
+	}
+
+	t.Contains(data, `"height":33`)
+	t.Contains(data, evs[0].Manifest().Hash().String())
+}
+
+func (t *testServer) TestInvalidFrom() {
+	_, ts := t.newServer()
+	defer ts.Close()
+
+	res, _ := t.connect(ts, "?from=showme", nil)
+	defer res.Body.Close()
+
+	t.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func (t *testServer) TestBindFailed() {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	t.NoError(err)
+	defer l.Close()
+
+	sv := NewServer(l.Addr().String(), NewBroker(10), t.loader)
+
+	err = sv.Start()
+	t.Error(err)
+	t.Contains(err.Error(), "failed to open events server")
+	t.False(sv.IsStarted())
+}
+
+func (t *testServer) TestStart() {
+	sv := NewServer("127.0.0.1:0", NewBroker(10), t.loader)
+
+	t.NoError(sv.Start())
+	t.True(sv.IsStarted())
+	t.True(errors.Is(sv.Start(), util.DaemonAlreadyStartedError))
+	t.NoError(sv.Stop())
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(testServer))
+}
@@ -8,10 +8,13 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
@@ -23,6 +26,14 @@ type Channel struct {
 	getStagedOperationsHandler network.GetStagedOperationsHandler
 	getProposalHandler         network.GetProposalHandler
 	getState                   network.GetStateHandler
+	getStateProof              network.GetStateProofHandler
+	getOperationProof          network.GetOperationProofHandler
+	getStatePathProof          network.GetStatePathProofHandler
+	getPendingOperations       network.GetPendingOperationsHandler
+	getOperationStatus         network.GetOperationStatusHandler
+	getEquivocations           network.GetEquivocationsHandler
+	getOperationsBySigner      network.GetOperationsBySignerHandler
+	getOperationsByHint        network.GetOperationsByHintHandler
 	nodeInfo                   network.NodeInfoHandler
 	getBlockdataMaps           network.BlockdataMapsHandler
 	getBlockdata               network.BlockdataHandler
@@ -84,9 +95,128 @@ func (ch *Channel) SetGetProposalHandler(f network.GetProposalHandler) {
 }
 
 func (ch *Channel) State(_ context.Context, key string) (state.State, bool, error) {
+	if ch.getState == nil {
+		return nil, false, errors.Errorf("not supported")
+	}
+
 	return ch.getState(key)
 }
 
+func (ch *Channel) SetGetStateHandler(f network.GetStateHandler) {
+	ch.getState = f
+}
+
+func (ch *Channel) StateProof(_ context.Context, key string) (network.StatePathProofV0, bool, error) {
+	if ch.getStateProof == nil {
+		return network.StatePathProofV0{}, false, errors.Errorf("not supported")
+	}
+
+	return ch.getStateProof(key)
+}
+
+func (ch *Channel) SetGetStateProofHandler(f network.GetStateProofHandler) {
+	ch.getStateProof = f
+}
+
+func (ch *Channel) OperationProof(
+	_ context.Context, height base.Height, fact valuehash.Hash,
+) (network.OperationProofV0, bool, error) {
+	if ch.getOperationProof == nil {
+		return network.OperationProofV0{}, false, errors.Errorf("not supported")
+	}
+
+	return ch.getOperationProof(height, fact)
+}
+
+func (ch *Channel) SetGetOperationProofHandler(f network.GetOperationProofHandler) {
+	ch.getOperationProof = f
+}
+
+func (ch *Channel) StatePathProof(
+	_ context.Context, height base.Height, key string,
+) (network.StatePathProofV0, bool, error) {
+	if ch.getStatePathProof == nil {
+		return network.StatePathProofV0{}, false, errors.Errorf("not supported")
+	}
+
+	return ch.getStatePathProof(height, key)
+}
+
+func (ch *Channel) SetGetStatePathProofHandler(f network.GetStatePathProofHandler) {
+	ch.getStatePathProof = f
+}
+
+func (ch *Channel) PendingOperations(_ context.Context, limit uint) ([]network.PendingOperationV0, error) {
+	if ch.getPendingOperations == nil {
+		return nil, errors.Errorf("not supported")
+	}
+
+	return ch.getPendingOperations(limit)
+}
+
+func (ch *Channel) SetGetPendingOperationsHandler(f network.GetPendingOperationsHandler) {
+	ch.getPendingOperations = f
+}
+
+func (ch *Channel) OperationStatus(_ context.Context, fact valuehash.Hash) (operation.StatusV0, bool, error) {
+	if ch.getOperationStatus == nil {
+		return operation.StatusV0{}, false, errors.Errorf("not supported")
+	}
+
+	return ch.getOperationStatus(fact)
+}
+
+func (ch *Channel) SetGetOperationStatusHandler(f network.GetOperationStatusHandler) {
+	ch.getOperationStatus = f
+}
+
+func (ch *Channel) Equivocations(_ context.Context, limit uint) ([]base.EquivocationV0, error) {
+	if ch.getEquivocations == nil {
+		return nil, errors.Errorf("not supported")
+	}
+
+	return ch.getEquivocations(limit)
+}
+
+func (ch *Channel) SetGetEquivocationsHandler(f network.GetEquivocationsHandler) {
+	ch.getEquivocations = f
+}
+
+func (ch *Channel) OperationsBySigner(
+	_ context.Context,
+	signer key.Publickey,
+	cursor storage.OperationCursor,
+	limit int64,
+) (network.OperationsPageV0, error) {
+	if ch.getOperationsBySigner == nil {
+		return network.OperationsPageV0{}, errors.Errorf("not supported")
+	}
+
+	return ch.getOperationsBySigner(signer, cursor, limit)
+}
+
+func (ch *Channel) SetGetOperationsBySignerHandler(f network.GetOperationsBySignerHandler) {
+	ch.getOperationsBySigner = f
+}
+
+func (ch *Channel) OperationsByHint(
+	_ context.Context,
+	ht hint.Type,
+	from, to base.Height,
+	cursor storage.OperationCursor,
+	limit int64,
+) (network.OperationsPageV0, error) {
+	if ch.getOperationsByHint == nil {
+		return network.OperationsPageV0{}, errors.Errorf("not supported")
+	}
+
+	return ch.getOperationsByHint(ht, from, to, cursor, limit)
+}
+
+func (ch *Channel) SetGetOperationsByHintHandler(f network.GetOperationsByHintHandler) {
+	ch.getOperationsByHint = f
+}
+
 func (ch *Channel) NodeInfo(_ context.Context) (network.NodeInfo, error) {
 	if ch.nodeInfo == nil {
 		return nil, nil
@@ -53,6 +53,18 @@ func (sv *Server) SetNewSealHandler(f network.NewSealHandler) {
 }
 func (*Server) SetGetProposalHandler(network.GetProposalHandler) {}
 
+func (*Server) SetGetStateHandler(network.GetStateHandler)           {}
+func (*Server) SetGetStateProofHandler(network.GetStateProofHandler) {}
+
+func (*Server) SetGetOperationProofHandler(network.GetOperationProofHandler) {}
+func (*Server) SetGetStatePathProofHandler(network.GetStatePathProofHandler) {}
+
+func (*Server) SetGetPendingOperationsHandler(network.GetPendingOperationsHandler)   {}
+func (*Server) SetGetOperationStatusHandler(network.GetOperationStatusHandler)       {}
+func (*Server) SetGetEquivocationsHandler(network.GetEquivocationsHandler)           {}
+func (*Server) SetGetOperationsBySignerHandler(network.GetOperationsBySignerHandler) {}
+func (*Server) SetGetOperationsByHintHandler(network.GetOperationsByHintHandler)     {}
+
 func (*Server) SetNodeInfoHandler(network.NodeInfoHandler)           {}
 func (*Server) NodeInfoHandler() network.NodeInfoHandler             { return nil }
 func (*Server) SetBlockdataMapsHandler(network.BlockdataMapsHandler) {}
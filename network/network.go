@@ -7,24 +7,40 @@ import (
 
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
 
 type (
-	NewSealHandler             func(seal.Seal) error
-	GetStagedOperationsHandler func([]valuehash.Hash) ([]operation.Operation, error)
-	GetProposalHandler         func(valuehash.Hash) (base.Proposal, error)
-	GetStateHandler            func(string) (state.State, bool, error)
-	NodeInfoHandler            func() (NodeInfo, error)
-	BlockdataMapsHandler       func([]base.Height) ([]block.BlockdataMap, error)
-	BlockdataHandler           func(string) (io.Reader, func() error, error)
-	StartHandoverHandler       func(StartHandoverSeal) (bool, error)
-	PingHandoverHandler        func(PingHandoverSeal) (bool, error)
-	EndHandoverHandler         func(EndHandoverSeal) (bool, error)
+	NewSealHandler              func(seal.Seal) error
+	GetStagedOperationsHandler  func([]valuehash.Hash) ([]operation.Operation, error)
+	GetProposalHandler          func(valuehash.Hash) (base.Proposal, error)
+	GetStateHandler             func(string) (state.State, bool, error)
+	GetStateProofHandler        func(string) (StatePathProofV0, bool, error)
+	GetOperationProofHandler    func(base.Height, valuehash.Hash) (OperationProofV0, bool, error)
+	GetStatePathProofHandler    func(base.Height, string) (StatePathProofV0, bool, error)
+	GetPendingOperationsHandler func(uint /* limit */) ([]PendingOperationV0, error)
+	GetOperationStatusHandler   func(valuehash.Hash) (operation.StatusV0, bool, error)
+	GetEquivocationsHandler     func(uint /* limit */) ([]base.EquivocationV0, error)
+	NodeInfoHandler             func() (NodeInfo, error)
+	BlockdataMapsHandler        func([]base.Height) ([]block.BlockdataMap, error)
+	BlockdataHandler            func(string) (io.Reader, func() error, error)
+	StartHandoverHandler        func(StartHandoverSeal) (bool, error)
+	PingHandoverHandler         func(PingHandoverSeal) (bool, error)
+	EndHandoverHandler          func(EndHandoverSeal) (bool, error)
+)
+
+type (
+	GetOperationsBySignerHandler func(key.Publickey, storage.OperationCursor, int64 /* limit */) (OperationsPageV0, error)
+	GetOperationsByHintHandler   func(
+		hint.Type, base.Height /* from */, base.Height /* to */, storage.OperationCursor, int64, /* limit */
+	) (OperationsPageV0, error)
 )
 
 type Server interface {
@@ -33,6 +49,15 @@ type Server interface {
 	SetNewSealHandler(NewSealHandler)
 	SetGetStagedOperationsHandler(GetStagedOperationsHandler)
 	SetGetProposalHandler(GetProposalHandler)
+	SetGetStateHandler(GetStateHandler)
+	SetGetStateProofHandler(GetStateProofHandler)
+	SetGetOperationProofHandler(GetOperationProofHandler)
+	SetGetStatePathProofHandler(GetStatePathProofHandler)
+	SetGetPendingOperationsHandler(GetPendingOperationsHandler)
+	SetGetOperationStatusHandler(GetOperationStatusHandler)
+	SetGetEquivocationsHandler(GetEquivocationsHandler)
+	SetGetOperationsBySignerHandler(GetOperationsBySignerHandler)
+	SetGetOperationsByHintHandler(GetOperationsByHintHandler)
 	NodeInfoHandler() NodeInfoHandler
 	SetNodeInfoHandler(NodeInfoHandler)
 	SetBlockdataMapsHandler(BlockdataMapsHandler)
@@ -52,6 +77,7 @@ var (
 	ChannelTimeoutOperation    = time.Second * 7
 	ChannelTimeoutSendSeal     = time.Second * 7
 	ChannelTimeoutNodeInfo     = time.Second * 7
+	ChannelTimeoutState        = time.Second * 7
 	ChannelTimeoutBlockdataMap = time.Second * 7
 	ChannelTimeoutBlockdata    = time.Minute
 	ChannelTimeoutHandover     = time.Second * 7
@@ -63,6 +89,19 @@ type Channel interface {
 	StagedOperations(context.Context, []valuehash.Hash) ([]operation.Operation, error)
 	SendSeal(context.Context, ConnInfo /* from ConnInfo */, seal.Seal) error
 	Proposal(context.Context, valuehash.Hash) (base.Proposal, error)
+	State(context.Context, string /* key */) (state.State, bool, error)
+	StateProof(context.Context, string /* key */) (StatePathProofV0, bool, error)
+	OperationProof(context.Context, base.Height, valuehash.Hash /* fact hash */) (OperationProofV0, bool, error)
+	StatePathProof(context.Context, base.Height, string /* key */) (StatePathProofV0, bool, error)
+	PendingOperations(context.Context, uint /* limit */) ([]PendingOperationV0, error)
+	OperationStatus(context.Context, valuehash.Hash /* fact hash */) (operation.StatusV0, bool, error)
+	Equivocations(context.Context, uint /* limit */) ([]base.EquivocationV0, error)
+	OperationsBySigner(
+		context.Context, key.Publickey, storage.OperationCursor, int64, /* limit */
+	) (OperationsPageV0, error)
+	OperationsByHint(
+		context.Context, hint.Type, base.Height /* from */, base.Height /* to */, storage.OperationCursor, int64, /* limit */
+	) (OperationsPageV0, error)
 	NodeInfo(context.Context) (NodeInfo, error)
 	BlockdataMaps(context.Context, []base.Height) ([]block.BlockdataMap, error)
 	Blockdata(context.Context, block.BlockdataMapItem) (io.ReadCloser, error)
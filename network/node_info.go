@@ -1,6 +1,9 @@
 package network
 
 import (
+	"bytes"
+	"sort"
+
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/key"
@@ -27,6 +30,8 @@ type NodeInfo interface {
 	ConnInfo() ConnInfo
 	Policy() map[string]interface{}
 	Nodes() []RemoteNode // Only contains suffrage nodes
+	Equivocations() []base.EquivocationV0
+	StuckRecoveries() []StuckRecoveryRecord
 }
 
 type NodeInfoV0 struct {
@@ -39,6 +44,8 @@ type NodeInfoV0 struct {
 	policy    map[string]interface{}
 	nodes     []RemoteNode
 	ci        ConnInfo
+	evs       []base.EquivocationV0
+	stuck     []StuckRecoveryRecord
 }
 
 func NewNodeInfoV0(
@@ -82,7 +89,17 @@ func (ni NodeInfoV0) IsValid([]byte) error {
 		return err
 	}
 
-	return isvalid.Check(nil, true, ni.lastBlock)
+	if err := isvalid.Check(nil, true, ni.lastBlock); err != nil {
+		return err
+	}
+
+	for i := range ni.evs {
+		if err := ni.evs[i].IsValid(ni.networkID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (ni NodeInfoV0) Address() base.Address {
@@ -121,6 +138,60 @@ func (ni NodeInfoV0) Nodes() []RemoteNode {
 	return ni.nodes
 }
 
+// Equivocations returns the recent evidences of double vote found by node.
+func (ni NodeInfoV0) Equivocations() []base.EquivocationV0 {
+	return ni.evs
+}
+
+func (ni NodeInfoV0) SetEquivocations(evs []base.EquivocationV0) NodeInfoV0 {
+	ni.evs = evs
+
+	return ni
+}
+
+// StuckRecoveries returns the recent recovery actions tried by node when
+// consensus looked stuck, the oldest first.
+func (ni NodeInfoV0) StuckRecoveries() []StuckRecoveryRecord {
+	return ni.stuck
+}
+
+func (ni NodeInfoV0) SetStuckRecoveries(records []StuckRecoveryRecord) NodeInfoV0 {
+	ni.stuck = records
+
+	return ni
+}
+
+// DiffPolicy returns the keys of local policy, whose values are different from
+// remote policy. The values are compared by their json representation, because
+// the values of the decoded policy can have different types.
+func DiffPolicy(local, remote map[string]interface{}) []string {
+	var keys []string
+	for k := range local {
+		r, found := remote[k]
+		if !found {
+			keys = append(keys, k)
+
+			continue
+		}
+
+		a, err := jsonenc.Marshal(local[k])
+		if err != nil {
+			keys = append(keys, k)
+
+			continue
+		}
+
+		b, err := jsonenc.Marshal(r)
+		if err != nil || !bytes.Equal(a, b) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 type RemoteNode struct {
 	Address   base.Address
 	Publickey key.Publickey
@@ -10,7 +10,7 @@ import (
 )
 
 func (ni NodeInfoV0) MarshalBSON() ([]byte, error) {
-	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(ni.Hint()), bson.M{
+	m := bson.M{
 		"node":       ni.node,
 		"network_id": ni.networkID,
 		"state":      ni.state,
@@ -19,7 +19,17 @@ func (ni NodeInfoV0) MarshalBSON() ([]byte, error) {
 		"policy":     ni.policy,
 		"suffrage":   ni.nodes,
 		"conninfo":   ni.ci,
-	}))
+	}
+
+	if len(ni.evs) > 0 {
+		m["equivocations"] = ni.evs
+	}
+
+	if len(ni.stuck) > 0 {
+		m["stuck_recoveries"] = ni.stuck
+	}
+
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(ni.Hint()), m))
 }
 
 type NodeInfoV0UnpackerBSON struct {
@@ -31,6 +41,8 @@ type NodeInfoV0UnpackerBSON struct {
 	PO  map[string]interface{} `bson:"policy"`
 	SF  []bson.Raw             `bson:"suffrage"`
 	CI  bson.Raw               `bson:"conninfo"`
+	EV  bson.Raw               `bson:"equivocations,omitempty"`
+	SR  []StuckRecoveryRecord  `bson:"stuck_recoveries,omitempty"`
 }
 
 func (ni *NodeInfoV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
@@ -49,7 +61,7 @@ func (ni *NodeInfoV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
 		sf[i] = r
 	}
 
-	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI)
+	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI, nni.EV, nni.SR)
 }
 
 func (no RemoteNode) MarshalBSON() ([]byte, error) {
@@ -21,6 +21,7 @@ type NodeInfoChecker struct {
 	interval      time.Duration
 	lastHeight    base.Height
 	whenNewHeight func(base.Height) error
+	policyFunc    func() map[string]interface{}
 }
 
 func NewNodeInfoChecker(
@@ -54,6 +55,18 @@ func (nc *NodeInfoChecker) SetLogging(l *logging.Logging) *logging.Logging {
 	return nc.Logging.SetLogging(l)
 }
 
+// SetPolicyFunc sets the function, which returns the local policy. If set, the
+// policy of remote node is compared with local policy, and the warning is
+// logged when they do not match.
+func (nc *NodeInfoChecker) SetPolicyFunc(f func() map[string]interface{}) *NodeInfoChecker {
+	nc.Lock()
+	defer nc.Unlock()
+
+	nc.policyFunc = f
+
+	return nc
+}
+
 func (nc *NodeInfoChecker) start(ctx context.Context) error {
 	if nc.interval < time.Second {
 		n := time.Second * 2
@@ -146,11 +159,28 @@ func (nc *NodeInfoChecker) request(ctx context.Context, no base.Node, ch Channel
 		l.Error().Err(err).Msg("failed to validate nodeinfo")
 
 		i = nil
+	} else {
+		nc.checkPolicy(no, i)
 	}
 
 	return i
 }
 
+func (nc *NodeInfoChecker) checkPolicy(no base.Node, ni NodeInfo) {
+	nc.RLock()
+	f := nc.policyFunc
+	nc.RUnlock()
+
+	if f == nil {
+		return
+	}
+
+	if keys := DiffPolicy(f(), ni.Policy()); len(keys) > 0 {
+		nc.Log().Warn().Stringer("node", no.Address()).Strs("keys", keys).
+			Interface("remote_policy", ni.Policy()).Msg("policy of remote node does not match with local policy")
+	}
+}
+
 func (nc *NodeInfoChecker) validateNodeInfo(no base.Node, ni NodeInfo) error {
 	if ni == nil {
 		return errors.Errorf("empty nodeinfo")
@@ -17,6 +17,8 @@ func (ni *NodeInfoV0) unpack(
 	co map[string]interface{},
 	sf []RemoteNode,
 	bci []byte,
+	bevs []byte,
+	sr []StuckRecoveryRecord,
 ) error {
 	if err := encoder.Decode(bnode, enc, &ni.node); err != nil {
 		return err
@@ -34,8 +36,33 @@ func (ni *NodeInfoV0) unpack(
 	ni.version = vs
 	ni.policy = co
 	ni.nodes = sf
+	ni.stuck = sr
 
-	return encoder.Decode(bci, enc, &ni.ci)
+	if err := encoder.Decode(bci, enc, &ni.ci); err != nil {
+		return err
+	}
+
+	if len(bevs) < 1 {
+		return nil
+	}
+
+	hevs, err := enc.DecodeSlice(bevs)
+	if err != nil {
+		return err
+	}
+
+	evs := make([]base.EquivocationV0, len(hevs))
+	for i := range hevs {
+		j, ok := hevs[i].(base.EquivocationV0)
+		if !ok {
+			return util.WrongTypeError.Errorf("expected EquivocationV0, not %T", hevs[i])
+		}
+		evs[i] = j
+	}
+
+	ni.evs = evs
+
+	return nil
 }
 
 func (no *RemoteNode) unpack(
@@ -20,6 +20,8 @@ type NodeInfoV0PackerJSON struct {
 	PO  map[string]interface{} `json:"policy"`
 	SF  []RemoteNode           `json:"suffrage"`
 	CI  ConnInfo               `json:"conninfo"`
+	EV  []base.EquivocationV0  `json:"equivocations,omitempty"`
+	SR  []StuckRecoveryRecord  `json:"stuck_recoveries,omitempty"`
 }
 
 func (ni NodeInfoV0) JSONPacker() NodeInfoV0PackerJSON {
@@ -33,6 +35,8 @@ func (ni NodeInfoV0) JSONPacker() NodeInfoV0PackerJSON {
 		PO:         ni.policy,
 		SF:         ni.nodes,
 		CI:         ni.ci,
+		EV:         ni.evs,
+		SR:         ni.stuck,
 	}
 }
 
@@ -49,6 +53,8 @@ type NodeInfoV0UnpackerJSON struct {
 	PO  map[string]interface{} `json:"policy"`
 	SF  []json.RawMessage      `json:"suffrage"`
 	CI  json.RawMessage        `json:"conninfo"`
+	EV  json.RawMessage        `json:"equivocations,omitempty"`
+	SR  []StuckRecoveryRecord  `json:"stuck_recoveries,omitempty"`
 }
 
 func (ni *NodeInfoV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
@@ -67,7 +73,7 @@ func (ni *NodeInfoV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
 		sf[i] = r
 	}
 
-	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI)
+	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI, nni.EV, nni.SR)
 }
 
 func (no RemoteNode) MarshalJSON() ([]byte, error) {
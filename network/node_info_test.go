@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
@@ -17,6 +18,7 @@ import (
 	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/stretchr/testify/suite"
 )
@@ -48,6 +50,10 @@ func (t *testNodeInfo) SetupTest() {
 	_ = t.encs.TestAddHinter(key.BasePrivatekey{})
 	_ = t.encs.TestAddHinter(key.BasePublickey{})
 	_ = t.encs.TestAddHinter(node.BaseV0Hinter)
+	_ = t.encs.TestAddHinter(base.BallotFactSignHinter)
+	_ = t.encs.TestAddHinter(base.SignedBallotFactHinter)
+	_ = t.encs.TestAddHinter(base.DummyBallotFact{})
+	_ = t.encs.TestAddHinter(base.EquivocationV0Hinter)
 }
 
 func (t *testNodeInfo) newConnInfo(name string, insecure bool) ConnInfo {
@@ -266,6 +272,100 @@ func (t *testNodeInfo) TestBSON() {
 	CompareNodeInfo(t.T(), ni, uni)
 }
 
+func (t *testNodeInfo) newEquivocation(n base.Node, height base.Height) base.EquivocationV0 {
+	pk := key.NewBasePrivatekey()
+	n = node.NewBaseV0(n.Address(), pk.Publickey())
+
+	signed := func() base.SignedBallotFact {
+		fact := base.NewDummyBallotFact()
+		fact.HT = height
+		fact.S = base.StageACCEPT
+
+		sf, err := base.NewBaseSignedBallotFactFromFact(fact, n.Address(), pk, t.nid)
+		t.NoError(err)
+
+		return sf
+	}
+
+	return base.NewEquivocationV0(signed(), signed(), localtime.UTCNow())
+}
+
+func (t *testNodeInfo) TestEquivocations() {
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	n0, _ := t.newNode("n0")
+
+	ni := NewNodeInfoV0(
+		node.RandomNode("n0"),
+		t.nid,
+		base.StateBooting,
+		blk.Manifest(),
+		util.Version("1.2.3"),
+		map[string]interface{}{"showme": 1.1},
+		nil,
+		nil,
+		t.newConnInfo("n0", true),
+	).SetEquivocations([]base.EquivocationV0{
+		t.newEquivocation(n0, base.Height(33)),
+		t.newEquivocation(n0, base.Height(32)),
+	})
+	t.NoError(ni.IsValid(nil))
+	t.Equal(2, len(ni.Equivocations()))
+
+	for _, enc := range []encoder.Encoder{t.encJSON, t.encBSON} {
+		b, err := enc.Marshal(ni)
+		t.NoError(err)
+
+		var uni NodeInfoV0
+		t.NoError(encoder.Decode(b, enc, &uni))
+		t.NoError(uni.IsValid(nil))
+
+		CompareNodeInfo(t.T(), ni, uni)
+	}
+
+	// NOTE evidence of different network id
+	ni = ni.SetEquivocations([]base.EquivocationV0{t.newEquivocation(n0, base.Height(33))})
+	ni.networkID = []byte("findme")
+	t.Error(ni.IsValid(nil))
+}
+
+func (t *testNodeInfo) TestStuckRecoveries() {
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	ni := NewNodeInfoV0(
+		node.RandomNode("n0"),
+		t.nid,
+		base.StateConsensus,
+		blk.Manifest(),
+		util.Version("1.2.3"),
+		map[string]interface{}{"showme": 1.1},
+		nil,
+		nil,
+		t.newConnInfo("n0", true),
+	).SetStuckRecoveries([]StuckRecoveryRecord{
+		{Action: "rebroadcast-init", Height: base.Height(33), Round: base.Round(1), Result: "done", At: localtime.UTCNow()},
+		{
+			Action: "fetch-voteproofs", Height: base.Height(33), Round: base.Round(1), Result: "skipped",
+			Message: "no voteproof agreed", At: localtime.UTCNow(),
+		},
+	})
+	t.NoError(ni.IsValid(nil))
+	t.Equal(2, len(ni.StuckRecoveries()))
+
+	for _, enc := range []encoder.Encoder{t.encJSON, t.encBSON} {
+		b, err := enc.Marshal(ni)
+		t.NoError(err)
+
+		var uni NodeInfoV0
+		t.NoError(encoder.Decode(b, enc, &uni))
+		t.NoError(uni.IsValid(nil))
+
+		CompareNodeInfo(t.T(), ni, uni)
+	}
+}
+
 func (t *testNodeInfo) TestSuffrage() {
 	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
 	t.NoError(err)
@@ -294,6 +394,46 @@ func (t *testNodeInfo) TestSuffrage() {
 	t.Equal(b, a)
 }
 
+func (t *testNodeInfo) TestDiffPolicy() {
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	newPolicy := func() map[string]interface{} {
+		return map[string]interface{}{
+			"threshold":                base.ThresholdRatio(67),
+			"max_operations_in_seal":   uint(100),
+			"timeout_waiting_proposal": time.Second * 5,
+		}
+	}
+
+	ni := NewNodeInfoV0(
+		node.RandomNode("n0"),
+		t.nid,
+		base.StateBooting,
+		blk.Manifest(),
+		util.Version("1.2.3"),
+		newPolicy(),
+		nil,
+		base.NewFixedSuffrage(base.RandomStringAddress(), nil),
+		t.newConnInfo("n0", true),
+	)
+
+	for _, enc := range []encoder.Encoder{t.encJSON, t.encBSON} {
+		b, err := enc.Marshal(ni)
+		t.NoError(err)
+
+		var uni NodeInfoV0
+		t.NoError(encoder.Decode(b, enc, &uni))
+
+		local := newPolicy()
+		t.Empty(DiffPolicy(local, uni.Policy()))
+
+		local["threshold"] = base.ThresholdRatio(100)
+		local["unknown"] = 1
+		t.Equal([]string{"threshold", "unknown"}, DiffPolicy(local, uni.Policy()))
+	}
+}
+
 func TestNodeInfo(t *testing.T) {
 	suite.Run(t, new(testNodeInfo))
 }
@@ -0,0 +1,64 @@
+package network
+
+import (
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+)
+
+var (
+	OperationsPageType     = hint.Type("operations-page")
+	OperationsPageV0Hint   = hint.NewHint(OperationsPageType, "v0.0.1")
+	OperationsPageV0Hinter = OperationsPageV0{BaseHinter: hint.NewBaseHinter(OperationsPageV0Hint)}
+)
+
+// OperationsPageV0 is the part of operations of stored blocks. Next is the
+// cursor to request the next page; empty next means no more operations.
+type OperationsPageV0 struct {
+	hint.BaseHinter
+	ops  []operation.Operation
+	next string
+}
+
+func NewOperationsPageV0(ops []operation.Operation, next storage.OperationCursor) OperationsPageV0 {
+	return OperationsPageV0{
+		BaseHinter: hint.NewBaseHinter(OperationsPageV0Hint),
+		ops:        ops,
+		next:       next.String(),
+	}
+}
+
+// IsValid does not check the signatures of operations; the operations were
+// already checked when they were stored.
+func (op OperationsPageV0) IsValid([]byte) error {
+	if err := op.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	for i := range op.ops {
+		if op.ops[i] == nil {
+			return isvalid.InvalidError.Errorf("empty operation found")
+		}
+
+		if err := isvalid.Check(nil, false, op.ops[i].Hint(), op.ops[i].Hash()); err != nil {
+			return err
+		}
+	}
+
+	if _, err := storage.ParseOperationCursor(op.next); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (op OperationsPageV0) Operations() []operation.Operation {
+	return op.ops
+}
+
+func (op OperationsPageV0) Next() storage.OperationCursor {
+	i, _ := storage.ParseOperationCursor(op.next)
+
+	return i
+}
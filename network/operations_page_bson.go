@@ -0,0 +1,28 @@
+package network
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+)
+
+func (op OperationsPageV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(op.Hint()), bson.M{
+		"operations": op.ops,
+		"next":       op.next,
+	}))
+}
+
+type OperationsPageV0UnpackerBSON struct {
+	OPS bson.Raw `bson:"operations"`
+	NX  string   `bson:"next"`
+}
+
+func (op *OperationsPageV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uop OperationsPageV0UnpackerBSON
+	if err := enc.Unmarshal(b, &uop); err != nil {
+		return err
+	}
+
+	return op.unpack(enc, uop.OPS, uop.NX)
+}
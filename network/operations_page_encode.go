@@ -0,0 +1,29 @@
+package network
+
+import (
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+)
+
+func (op *OperationsPageV0) unpack(enc encoder.Encoder, bops []byte, next string) error {
+	hops, err := enc.DecodeSlice(bops)
+	if err != nil {
+		return err
+	}
+
+	ops := make([]operation.Operation, len(hops))
+	for i := range hops {
+		j, ok := hops[i].(operation.Operation)
+		if !ok {
+			return util.WrongTypeError.Errorf("expected operation.Operation, not %T", hops[i])
+		}
+
+		ops[i] = j
+	}
+
+	op.ops = ops
+	op.next = next
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package network
+
+import (
+	"encoding/json"
+
+	"github.com/spikeekips/mitum/base/operation"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+type OperationsPageV0PackerJSON struct {
+	jsonenc.HintedHead
+	OPS []operation.Operation `json:"operations"`
+	NX  string                `json:"next"`
+}
+
+func (op OperationsPageV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(OperationsPageV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(op.Hint()),
+		OPS:        op.ops,
+		NX:         op.next,
+	})
+}
+
+type OperationsPageV0UnpackerJSON struct {
+	OPS json.RawMessage `json:"operations"`
+	NX  string          `json:"next"`
+}
+
+func (op *OperationsPageV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uop OperationsPageV0UnpackerJSON
+	if err := enc.Unmarshal(b, &uop); err != nil {
+		return err
+	}
+
+	return op.unpack(enc, uop.OPS, uop.NX)
+}
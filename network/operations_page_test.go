@@ -0,0 +1,110 @@
+//go:build test
+// +build test
+
+package network
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testOperationsPage struct {
+	suite.Suite
+	encs    *encoder.Encoders
+	encJSON encoder.Encoder
+	encBSON encoder.Encoder
+	pk      key.Privatekey
+}
+
+func (t *testOperationsPage) SetupTest() {
+	t.encs = encoder.NewEncoders()
+	t.encJSON = jsonenc.NewEncoder()
+	t.encBSON = bsonenc.NewEncoder()
+
+	_ = t.encs.AddEncoder(t.encJSON)
+	_ = t.encs.AddEncoder(t.encBSON)
+
+	_ = t.encs.TestAddHinter(OperationsPageV0Hinter)
+	_ = t.encs.TestAddHinter(key.BasePublickey{})
+	_ = t.encs.TestAddHinter(base.BaseFactSignHinter)
+	_ = t.encs.TestAddHinter(operation.KVOperationFact{})
+	_ = t.encs.TestAddHinter(operation.KVOperation{})
+
+	t.pk = key.NewBasePrivatekey()
+}
+
+func (t *testOperationsPage) newOperation() operation.Operation {
+	op, err := operation.NewKVOperation(t.pk, []byte("this-is-token"), util.UUID().String(), util.UUID().Bytes(), nil)
+	t.NoError(err)
+
+	return op
+}
+
+func (t *testOperationsPage) TestIsValid() {
+	op := NewOperationsPageV0([]operation.Operation{t.newOperation()}, storage.NewOperationCursor(base.Height(3), 1))
+	t.NoError(op.IsValid(nil))
+	t.Equal(storage.NewOperationCursor(base.Height(3), 1), op.Next())
+
+	op.next = "showme"
+	t.Error(op.IsValid(nil))
+}
+
+func (t *testOperationsPage) testEncode(enc encoder.Encoder) {
+	op := NewOperationsPageV0(
+		[]operation.Operation{t.newOperation(), t.newOperation()},
+		storage.NewOperationCursor(base.Height(3), 1),
+	)
+
+	b, err := enc.Marshal(op)
+	t.NoError(err)
+
+	hinter, err := enc.Decode(b)
+	t.NoError(err)
+
+	uop, ok := hinter.(OperationsPageV0)
+	t.True(ok)
+
+	t.NoError(uop.IsValid(nil))
+	t.Equal(op.Next(), uop.Next())
+	t.Equal(len(op.Operations()), len(uop.Operations()))
+
+	for i := range op.Operations() {
+		t.True(op.Operations()[i].Hash().Equal(uop.Operations()[i].Hash()))
+	}
+}
+
+func (t *testOperationsPage) TestEncodeJSON() {
+	t.testEncode(t.encJSON)
+}
+
+func (t *testOperationsPage) TestEncodeBSON() {
+	t.testEncode(t.encBSON)
+}
+
+func (t *testOperationsPage) TestEncodeEmpty() {
+	op := NewOperationsPageV0(nil, storage.NilOperationCursor)
+
+	b, err := t.encJSON.Marshal(op)
+	t.NoError(err)
+
+	hinter, err := t.encJSON.Decode(b)
+	t.NoError(err)
+
+	uop, ok := hinter.(OperationsPageV0)
+	t.True(ok)
+	t.Empty(uop.Operations())
+	t.True(uop.Next().IsNil())
+}
+
+func TestOperationsPage(t *testing.T) {
+	suite.Run(t, new(testOperationsPage))
+}
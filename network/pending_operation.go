@@ -0,0 +1,76 @@
+package network
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base/key"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	PendingOperationType     = hint.Type("pending-operation")
+	PendingOperationV0Hint   = hint.NewHint(PendingOperationType, "v0.0.1")
+	PendingOperationV0Hinter = PendingOperationV0{BaseHinter: hint.NewBaseHinter(PendingOperationV0Hint)}
+)
+
+// PendingOperationV0 describes the operation, which is waiting in mempool to
+// be proposed.
+type PendingOperationV0 struct {
+	hint.BaseHinter
+	fact     valuehash.Hash
+	opHint   hint.Hint
+	sender   key.Publickey
+	priority uint64
+	addedAt  time.Time
+}
+
+func NewPendingOperationV0(
+	fact valuehash.Hash,
+	opHint hint.Hint,
+	sender key.Publickey,
+	priority uint64,
+	addedAt time.Time,
+) PendingOperationV0 {
+	return PendingOperationV0{
+		BaseHinter: hint.NewBaseHinter(PendingOperationV0Hint),
+		fact:       fact,
+		opHint:     opHint,
+		sender:     sender,
+		priority:   priority,
+		addedAt:    addedAt,
+	}
+}
+
+func (po PendingOperationV0) String() string {
+	return jsonenc.ToString(po)
+}
+
+func (po PendingOperationV0) IsValid([]byte) error {
+	return isvalid.Check(nil, false, po.BaseHinter, po.fact, po.opHint, po.sender)
+}
+
+// Fact is the fact hash of operation.
+func (po PendingOperationV0) Fact() valuehash.Hash {
+	return po.fact
+}
+
+// OperationHint is the hint of operation.
+func (po PendingOperationV0) OperationHint() hint.Hint {
+	return po.opHint
+}
+
+// Sender is the publickey of the first signer of operation.
+func (po PendingOperationV0) Sender() key.Publickey {
+	return po.sender
+}
+
+func (po PendingOperationV0) Priority() uint64 {
+	return po.priority
+}
+
+func (po PendingOperationV0) AddedAt() time.Time {
+	return po.addedAt
+}
@@ -0,0 +1,39 @@
+package network
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/spikeekips/mitum/base/key"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (po PendingOperationV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(po.Hint()), bson.M{
+		"fact":           po.fact,
+		"operation_hint": po.opHint,
+		"sender":         po.sender,
+		"priority":       po.priority,
+		"added_at":       po.addedAt,
+	}))
+}
+
+type PendingOperationV0UnpackerBSON struct {
+	FC valuehash.Bytes      `bson:"fact"`
+	OH hint.Hint            `bson:"operation_hint"`
+	SD key.PublickeyDecoder `bson:"sender"`
+	PR uint64               `bson:"priority"`
+	AA time.Time            `bson:"added_at"`
+}
+
+func (po *PendingOperationV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var upo PendingOperationV0UnpackerBSON
+	if err := enc.Unmarshal(b, &upo); err != nil {
+		return err
+	}
+
+	return po.unpack(enc, upo.FC, upo.OH, upo.SD, upo.PR, upo.AA)
+}
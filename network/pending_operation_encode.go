@@ -0,0 +1,32 @@
+package network
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (po *PendingOperationV0) unpack(
+	enc encoder.Encoder,
+	fact valuehash.Hash,
+	opHint hint.Hint,
+	bsender key.PublickeyDecoder,
+	priority uint64,
+	addedAt time.Time,
+) error {
+	sender, err := bsender.Encode(enc)
+	if err != nil {
+		return err
+	}
+
+	po.fact = fact
+	po.opHint = opHint
+	po.sender = sender
+	po.priority = priority
+	po.addedAt = addedAt
+
+	return nil
+}
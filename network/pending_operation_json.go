@@ -0,0 +1,46 @@
+package network
+
+import (
+	"github.com/spikeekips/mitum/base/key"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type PendingOperationV0PackerJSON struct {
+	jsonenc.HintedHead
+	FC valuehash.Hash `json:"fact"`
+	OH hint.Hint      `json:"operation_hint"`
+	SD key.Publickey  `json:"sender"`
+	PR uint64         `json:"priority"`
+	AA localtime.Time `json:"added_at"`
+}
+
+func (po PendingOperationV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(PendingOperationV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(po.Hint()),
+		FC:         po.fact,
+		OH:         po.opHint,
+		SD:         po.sender,
+		PR:         po.priority,
+		AA:         localtime.NewTime(po.addedAt),
+	})
+}
+
+type PendingOperationV0UnpackerJSON struct {
+	FC valuehash.Bytes      `json:"fact"`
+	OH hint.Hint            `json:"operation_hint"`
+	SD key.PublickeyDecoder `json:"sender"`
+	PR uint64               `json:"priority"`
+	AA localtime.Time       `json:"added_at"`
+}
+
+func (po *PendingOperationV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var upo PendingOperationV0UnpackerJSON
+	if err := enc.Unmarshal(b, &upo); err != nil {
+		return err
+	}
+
+	return po.unpack(enc, upo.FC, upo.OH, upo.SD, upo.PR, upo.AA.Time)
+}
@@ -0,0 +1,103 @@
+//go:build test
+// +build test
+
+package network
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testPendingOperation struct {
+	suite.Suite
+	encs    *encoder.Encoders
+	encJSON encoder.Encoder
+	encBSON encoder.Encoder
+}
+
+func (t *testPendingOperation) SetupTest() {
+	t.encs = encoder.NewEncoders()
+	t.encJSON = jsonenc.NewEncoder()
+	t.encBSON = bsonenc.NewEncoder()
+
+	_ = t.encs.AddEncoder(t.encJSON)
+	_ = t.encs.AddEncoder(t.encBSON)
+
+	_ = t.encs.TestAddHinter(PendingOperationV0Hinter)
+	_ = t.encs.TestAddHinter(key.BasePublickey{})
+}
+
+func (t *testPendingOperation) newPendingOperation() PendingOperationV0 {
+	return NewPendingOperationV0(
+		valuehash.RandomSHA256(),
+		hint.NewHint(hint.Type("showme"), "v1.2.3"),
+		key.NewBasePrivatekey().Publickey(),
+		33,
+		localtime.UTCNow(),
+	)
+}
+
+func (t *testPendingOperation) TestIsValid() {
+	po := t.newPendingOperation()
+	t.NoError(po.IsValid(nil))
+
+	po.fact = nil
+	t.Error(po.IsValid(nil))
+}
+
+func (t *testPendingOperation) testEncode(enc encoder.Encoder) {
+	po := t.newPendingOperation()
+
+	b, err := enc.Marshal(po)
+	t.NoError(err)
+
+	hinter, err := enc.Decode(b)
+	t.NoError(err)
+
+	upo, ok := hinter.(PendingOperationV0)
+	t.True(ok)
+
+	t.NoError(upo.IsValid(nil))
+	t.True(po.Fact().Equal(upo.Fact()))
+	t.True(po.OperationHint().Equal(upo.OperationHint()))
+	t.True(po.Sender().Equal(upo.Sender()))
+	t.Equal(po.Priority(), upo.Priority())
+	t.True(localtime.Equal(po.AddedAt(), upo.AddedAt()))
+}
+
+func (t *testPendingOperation) TestEncodeJSON() {
+	t.testEncode(t.encJSON)
+}
+
+func (t *testPendingOperation) TestEncodeBSON() {
+	t.testEncode(t.encBSON)
+}
+
+func (t *testPendingOperation) TestEncodeSliceJSON() {
+	pos := []PendingOperationV0{t.newPendingOperation(), t.newPendingOperation()}
+
+	b, err := t.encJSON.Marshal(pos)
+	t.NoError(err)
+
+	hinters, err := t.encJSON.DecodeSlice(b)
+	t.NoError(err)
+	t.Equal(len(pos), len(hinters))
+
+	for i := range pos {
+		upo, ok := hinters[i].(PendingOperationV0)
+		t.True(ok)
+		t.True(pos[i].Fact().Equal(upo.Fact()))
+	}
+}
+
+func TestPendingOperation(t *testing.T) {
+	suite.Run(t, new(testPendingOperation))
+}
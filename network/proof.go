@@ -0,0 +1,125 @@
+package network
+
+import (
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	OperationProofType     = hint.Type("operation-proof")
+	OperationProofV0Hint   = hint.NewHint(OperationProofType, "v0.0.1")
+	OperationProofV0Hinter = OperationProofV0{BaseHinter: hint.NewBaseHinter(OperationProofV0Hint)}
+	StatePathProofType     = hint.Type("state-path-proof")
+	StatePathProofV0Hint   = hint.NewHint(StatePathProofType, "v0.0.1")
+	StatePathProofV0Hinter = StatePathProofV0{BaseHinter: hint.NewBaseHinter(StatePathProofV0Hint)}
+)
+
+// OperationProofV0 contains the proof path of operation fact in the operations
+// tree of block.
+type OperationProofV0 struct {
+	hint.BaseHinter
+	manifest block.Manifest
+	fact     valuehash.Hash
+	proof    []tree.FixedTreeNode
+}
+
+func NewOperationProofV0(manifest block.Manifest, fact valuehash.Hash, proof []tree.FixedTreeNode) OperationProofV0 {
+	return OperationProofV0{
+		BaseHinter: hint.NewBaseHinter(OperationProofV0Hint),
+		manifest:   manifest,
+		fact:       fact,
+		proof:      proof,
+	}
+}
+
+func (op OperationProofV0) String() string {
+	return jsonenc.ToString(op)
+}
+
+func (op OperationProofV0) IsValid(networkID []byte) error {
+	if err := isvalid.Check(networkID, false, op.BaseHinter, op.manifest, op.fact); err != nil {
+		return err
+	}
+
+	if _, err := op.Verify(); err != nil {
+		return isvalid.InvalidError.Wrap(err)
+	}
+
+	return nil
+}
+
+// Verify checks the proof against Manifest.OperationsHash() and returns the
+// proved operation tree node.
+func (op OperationProofV0) Verify() (operation.FixedTreeNode, error) {
+	return block.VerifyOperationProof(op.manifest, op.fact, op.proof)
+}
+
+func (op OperationProofV0) Manifest() block.Manifest {
+	return op.manifest
+}
+
+func (op OperationProofV0) Fact() valuehash.Hash {
+	return op.fact
+}
+
+func (op OperationProofV0) Proof() []tree.FixedTreeNode {
+	return op.proof
+}
+
+// StatePathProofV0 contains the state and it's proof path in the states tree
+// of block. The proof only proves that the state was stored in the block of
+// the state height, not that it is the latest state.
+type StatePathProofV0 struct {
+	hint.BaseHinter
+	manifest block.Manifest
+	state    state.State
+	proof    []tree.FixedTreeNode
+}
+
+func NewStatePathProofV0(manifest block.Manifest, st state.State, proof []tree.FixedTreeNode) StatePathProofV0 {
+	return StatePathProofV0{
+		BaseHinter: hint.NewBaseHinter(StatePathProofV0Hint),
+		manifest:   manifest,
+		state:      st,
+		proof:      proof,
+	}
+}
+
+func (sp StatePathProofV0) String() string {
+	return jsonenc.ToString(sp)
+}
+
+func (sp StatePathProofV0) IsValid(networkID []byte) error {
+	if err := isvalid.Check(networkID, false, sp.BaseHinter, sp.manifest, sp.state); err != nil {
+		return err
+	}
+
+	if err := sp.Verify(); err != nil {
+		return isvalid.InvalidError.Wrap(err)
+	}
+
+	return nil
+}
+
+// Verify checks the proof against Manifest.StatesHash().
+func (sp StatePathProofV0) Verify() error {
+	return block.VerifyStateProof(sp.manifest, sp.state, sp.proof)
+}
+
+func (sp StatePathProofV0) Manifest() block.Manifest {
+	return sp.manifest
+}
+
+func (sp StatePathProofV0) State() state.State {
+	return sp.state
+}
+
+func (sp StatePathProofV0) Proof() []tree.FixedTreeNode {
+	return sp.proof
+}
@@ -0,0 +1,54 @@
+package network
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (op OperationProofV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(op.Hint()), bson.M{
+		"manifest": op.manifest,
+		"fact":     op.fact,
+		"proof":    op.proof,
+	}))
+}
+
+type OperationProofV0UnpackerBSON struct {
+	MF bson.Raw        `bson:"manifest"`
+	FC valuehash.Bytes `bson:"fact"`
+	PR bson.Raw        `bson:"proof"`
+}
+
+func (op *OperationProofV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uop OperationProofV0UnpackerBSON
+	if err := enc.Unmarshal(b, &uop); err != nil {
+		return err
+	}
+
+	return op.unpack(enc, uop.MF, uop.FC, uop.PR)
+}
+
+func (sp StatePathProofV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(sp.Hint()), bson.M{
+		"manifest": sp.manifest,
+		"state":    sp.state,
+		"proof":    sp.proof,
+	}))
+}
+
+type StatePathProofV0UnpackerBSON struct {
+	MF bson.Raw `bson:"manifest"`
+	ST bson.Raw `bson:"state"`
+	PR bson.Raw `bson:"proof"`
+}
+
+func (sp *StatePathProofV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var usp StatePathProofV0UnpackerBSON
+	if err := enc.Unmarshal(b, &usp); err != nil {
+		return err
+	}
+
+	return sp.unpack(enc, usp.MF, usp.ST, usp.PR)
+}
@@ -0,0 +1,66 @@
+package network
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (op *OperationProofV0) unpack(enc encoder.Encoder, bmf []byte, fact valuehash.Hash, bpr []byte) error {
+	if err := encoder.Decode(bmf, enc, &op.manifest); err != nil {
+		return err
+	}
+
+	op.fact = fact
+
+	pr, err := DecodeProofNodes(enc, bpr)
+	if err != nil {
+		return err
+	}
+	op.proof = pr
+
+	return nil
+}
+
+func (sp *StatePathProofV0) unpack(enc encoder.Encoder, bmf, bst, bpr []byte) error {
+	if err := encoder.Decode(bmf, enc, &sp.manifest); err != nil {
+		return err
+	}
+
+	if err := encoder.Decode(bst, enc, &sp.state); err != nil {
+		return err
+	}
+
+	pr, err := DecodeProofNodes(enc, bpr)
+	if err != nil {
+		return err
+	}
+	sp.proof = pr
+
+	return nil
+}
+
+// DecodeProofNodes decodes the nodes of proof; unlike tree.FixedTree, the
+// missing nodes in proof are nil.
+func DecodeProofNodes(enc encoder.Encoder, b []byte) ([]tree.FixedTreeNode, error) {
+	hinters, err := enc.DecodeSlice(b)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]tree.FixedTreeNode, len(hinters))
+	for i := range hinters {
+		if hinters[i] == nil {
+			continue
+		}
+
+		j, ok := hinters[i].(tree.FixedTreeNode)
+		if !ok {
+			return nil, errors.Errorf("not FixedTreeNode, %T", hinters[i])
+		}
+		nodes[i] = j
+	}
+
+	return nodes, nil
+}
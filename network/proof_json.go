@@ -0,0 +1,73 @@
+package network
+
+import (
+	"encoding/json"
+
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type OperationProofV0PackerJSON struct {
+	jsonenc.HintedHead
+	MF block.Manifest       `json:"manifest"`
+	FC valuehash.Hash       `json:"fact"`
+	PR []tree.FixedTreeNode `json:"proof"`
+}
+
+func (op OperationProofV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(OperationProofV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(op.Hint()),
+		MF:         op.manifest,
+		FC:         op.fact,
+		PR:         op.proof,
+	})
+}
+
+type OperationProofV0UnpackerJSON struct {
+	MF json.RawMessage `json:"manifest"`
+	FC valuehash.Bytes `json:"fact"`
+	PR json.RawMessage `json:"proof"`
+}
+
+func (op *OperationProofV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uop OperationProofV0UnpackerJSON
+	if err := enc.Unmarshal(b, &uop); err != nil {
+		return err
+	}
+
+	return op.unpack(enc, uop.MF, uop.FC, uop.PR)
+}
+
+type StatePathProofV0PackerJSON struct {
+	jsonenc.HintedHead
+	MF block.Manifest       `json:"manifest"`
+	ST state.State          `json:"state"`
+	PR []tree.FixedTreeNode `json:"proof"`
+}
+
+func (sp StatePathProofV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(StatePathProofV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(sp.Hint()),
+		MF:         sp.manifest,
+		ST:         sp.state,
+		PR:         sp.proof,
+	})
+}
+
+type StatePathProofV0UnpackerJSON struct {
+	MF json.RawMessage `json:"manifest"`
+	ST json.RawMessage `json:"state"`
+	PR json.RawMessage `json:"proof"`
+}
+
+func (sp *StatePathProofV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var usp StatePathProofV0UnpackerJSON
+	if err := enc.Unmarshal(b, &usp); err != nil {
+		return err
+	}
+
+	return sp.unpack(enc, usp.MF, usp.ST, usp.PR)
+}
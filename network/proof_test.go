@@ -0,0 +1,251 @@
+//go:build test
+// +build test
+
+package network
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testProof struct {
+	suite.Suite
+	encs    *encoder.Encoders
+	encJSON encoder.Encoder
+	encBSON encoder.Encoder
+}
+
+func (t *testProof) SetupTest() {
+	t.encs = encoder.NewEncoders()
+	t.encJSON = jsonenc.NewEncoder()
+	t.encBSON = bsonenc.NewEncoder()
+
+	_ = t.encs.AddEncoder(t.encJSON)
+	_ = t.encs.AddEncoder(t.encBSON)
+
+	_ = t.encs.TestAddHinter(OperationProofV0Hinter)
+	_ = t.encs.TestAddHinter(StatePathProofV0Hinter)
+	_ = t.encs.TestAddHinter(block.ManifestV0Hinter)
+	_ = t.encs.TestAddHinter(operation.FixedTreeNodeHinter)
+	_ = t.encs.TestAddHinter(state.BytesValueHinter)
+	_ = t.encs.TestAddHinter(state.FixedTreeNodeHinter)
+	_ = t.encs.TestAddHinter(state.StateV0{})
+}
+
+func (t *testProof) newOperations(n int) ([]valuehash.Hash, tree.FixedTree) {
+	facts := make([]valuehash.Hash, n)
+	trg := tree.NewFixedTreeGenerator(uint64(n))
+	for i := 0; i < n; i++ {
+		facts[i] = valuehash.RandomSHA256()
+
+		t.NoError(trg.Add(operation.NewFixedTreeNode(uint64(i), facts[i].Bytes(), true, nil)))
+	}
+
+	tr, err := trg.Tree()
+	t.NoError(err)
+
+	return facts, tr
+}
+
+func (t *testProof) newStates(height base.Height, n int) ([]state.State, tree.FixedTree) {
+	sts := make([]state.State, n)
+	trg := tree.NewFixedTreeGenerator(uint64(n))
+	for i := 0; i < n; i++ {
+		v, err := state.NewBytesValue([]byte(fmt.Sprintf("value-%d", i)))
+		t.NoError(err)
+
+		st, err := state.NewStateV0(fmt.Sprintf("key-%d", i), v, height)
+		t.NoError(err)
+
+		j, err := st.SetHash(st.GenerateHash())
+		t.NoError(err)
+		sts[i] = j
+
+		t.NoError(trg.Add(state.NewFixedTreeNode(uint64(i), j.Hash().Bytes())))
+	}
+
+	tr, err := trg.Tree()
+	t.NoError(err)
+
+	return sts, tr
+}
+
+func (t *testProof) newManifest(height base.Height, operationsHash, statesHash valuehash.Hash) block.Manifest {
+	blk, err := block.NewBlockV0(
+		block.SuffrageInfoV0{},
+		height,
+		base.Round(0),
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		operationsHash,
+		statesHash,
+		time.Now(),
+	)
+	t.NoError(err)
+
+	return blk.Manifest()
+}
+
+func (t *testProof) TestOperationProof() {
+	facts, tr := t.newOperations(10)
+	manifest := t.newManifest(base.Height(33), valuehash.NewBytes(tr.Root()), nil)
+
+	for i := range facts {
+		pr, err := tr.Proof(uint64(i))
+		t.NoError(err)
+
+		op := NewOperationProofV0(manifest, facts[i], pr)
+		t.NoError(op.IsValid(nil))
+
+		n, err := op.Verify()
+		t.NoError(err)
+		t.Equal(uint64(i), n.Index())
+		t.True(n.InState())
+	}
+}
+
+func (t *testProof) TestOperationProofWrongFact() {
+	facts, tr := t.newOperations(10)
+	manifest := t.newManifest(base.Height(33), valuehash.NewBytes(tr.Root()), nil)
+
+	pr, err := tr.Proof(3)
+	t.NoError(err)
+
+	op := NewOperationProofV0(manifest, facts[4], pr)
+	err = op.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+}
+
+func (t *testProof) TestOperationProofWrongOperationsHash() {
+	facts, tr := t.newOperations(10)
+	manifest := t.newManifest(base.Height(33), valuehash.RandomSHA256(), nil)
+
+	pr, err := tr.Proof(3)
+	t.NoError(err)
+
+	op := NewOperationProofV0(manifest, facts[3], pr)
+	err = op.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "root of proof does not match")
+}
+
+func (t *testProof) TestStatePathProof() {
+	height := base.Height(33)
+	sts, tr := t.newStates(height, 10)
+	manifest := t.newManifest(height, nil, valuehash.NewBytes(tr.Root()))
+
+	for i := range sts {
+		pr, err := tr.Proof(uint64(i))
+		t.NoError(err)
+
+		sp := NewStatePathProofV0(manifest, sts[i], pr)
+		t.NoError(sp.IsValid(nil))
+	}
+}
+
+func (t *testProof) TestStatePathProofWrongState() {
+	height := base.Height(33)
+	sts, tr := t.newStates(height, 10)
+	manifest := t.newManifest(height, nil, valuehash.NewBytes(tr.Root()))
+
+	pr, err := tr.Proof(3)
+	t.NoError(err)
+
+	sp := NewStatePathProofV0(manifest, sts[4], pr)
+	err = sp.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+}
+
+func (t *testProof) TestStatePathProofWrongHeight() {
+	height := base.Height(33)
+	sts, tr := t.newStates(height, 10)
+	manifest := t.newManifest(height+1, nil, valuehash.NewBytes(tr.Root()))
+
+	pr, err := tr.Proof(3)
+	t.NoError(err)
+
+	sp := NewStatePathProofV0(manifest, sts[3], pr)
+	err = sp.IsValid(nil)
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "state height does not match")
+}
+
+func (t *testProof) testEncodeOperationProof(enc encoder.Encoder) {
+	facts, tr := t.newOperations(10)
+	manifest := t.newManifest(base.Height(33), valuehash.NewBytes(tr.Root()), nil)
+
+	// NOTE proof of leaf node contains nil children
+	pr, err := tr.Proof(9)
+	t.NoError(err)
+
+	op := NewOperationProofV0(manifest, facts[9], pr)
+	t.NoError(op.IsValid(nil))
+
+	b, err := enc.Marshal(op)
+	t.NoError(err)
+
+	hinter, err := enc.Decode(b)
+	t.NoError(err)
+
+	uop, ok := hinter.(OperationProofV0)
+	t.True(ok)
+
+	t.NoError(uop.IsValid(nil))
+	t.True(op.Manifest().Hash().Equal(uop.Manifest().Hash()))
+	t.True(op.Fact().Equal(uop.Fact()))
+	t.Equal(len(op.Proof()), len(uop.Proof()))
+}
+
+func (t *testProof) testEncodeStatePathProof(enc encoder.Encoder) {
+	height := base.Height(33)
+	sts, tr := t.newStates(height, 10)
+	manifest := t.newManifest(height, nil, valuehash.NewBytes(tr.Root()))
+
+	pr, err := tr.Proof(3)
+	t.NoError(err)
+
+	sp := NewStatePathProofV0(manifest, sts[3], pr)
+	t.NoError(sp.IsValid(nil))
+
+	b, err := enc.Marshal(sp)
+	t.NoError(err)
+
+	hinter, err := enc.Decode(b)
+	t.NoError(err)
+
+	usp, ok := hinter.(StatePathProofV0)
+	t.True(ok)
+
+	t.NoError(usp.IsValid(nil))
+	t.True(sp.Manifest().Hash().Equal(usp.Manifest().Hash()))
+	t.True(sp.State().Hash().Equal(usp.State().Hash()))
+	t.Equal(len(sp.Proof()), len(usp.Proof()))
+}
+
+func (t *testProof) TestEncodeJSON() {
+	t.testEncodeOperationProof(t.encJSON)
+	t.testEncodeStatePathProof(t.encJSON)
+}
+
+func (t *testProof) TestEncodeBSON() {
+	t.testEncodeOperationProof(t.encBSON)
+	t.testEncodeStatePathProof(t.encBSON)
+}
+
+func TestProof(t *testing.T) {
+	suite.Run(t, new(testProof))
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,9 +16,12 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
 	"github.com/spikeekips/mitum/util/hint"
@@ -34,7 +38,15 @@ type Channel struct {
 	enc                    encoder.Encoder
 	sendSealURL            string
 	getStagedOperationsURL string
+	getPendingOperations   url.URL
+	getOperationStatusURL  url.URL
+	getOperationsBySigner  url.URL
+	getOperationsByHint    url.URL
+	getEquivocations       url.URL
 	getProposalURL         url.URL
+	getStateURL            url.URL
+	getOperationProofURL   url.URL
+	getStatePathProofURL   url.URL
 	nodeInfoURL            string
 	getBlockdataMaps       string
 	getBlockdata           url.URL
@@ -65,10 +77,42 @@ func NewChannel(
 	ch.nodeInfoURL, _ = mustQuicURL(addr, QuicHandlerPathNodeInfo)
 	ch.sendSealURL, _ = mustQuicURL(addr, QuicHandlerPathSendSeal)
 	ch.getStagedOperationsURL, _ = mustQuicURL(addr, QuicHandlerPathGetStagedOperations)
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetPendingOperations)
+		ch.getPendingOperations = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetOperationStatus)
+		ch.getOperationStatusURL = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetEquivocations)
+		ch.getEquivocations = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetOperationsBySigner)
+		ch.getOperationsBySigner = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetOperationsByHint)
+		ch.getOperationsByHint = *u
+	}
 	{
 		_, u := mustQuicURL(addr, QuicHandlerPathGetProposal)
 		ch.getProposalURL = *u
 	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetState)
+		ch.getStateURL = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetOperationProof)
+		ch.getOperationProofURL = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetStatePathProof)
+		ch.getStatePathProofURL = *u
+	}
 	ch.getBlockdataMaps, _ = mustQuicURL(addr, QuicHandlerPathGetBlockdataMaps)
 	{
 		_, u := mustQuicURL(addr, QuicHandlerPathGetBlockdata)
@@ -139,6 +183,158 @@ func (ch *Channel) StagedOperations(ctx context.Context, hs []valuehash.Hash) ([
 	return ops, nil
 }
 
+func (ch *Channel) PendingOperations(ctx context.Context, limit uint) ([]network.PendingOperationV0, error) {
+	ch.Log().Trace().Uint("limit", limit).Msg("request pending operations")
+
+	u := ch.getPendingOperations
+	if limit > 0 {
+		u.RawQuery = url.Values{"limit": []string{strconv.FormatUint(uint64(limit), 10)}}.Encode()
+	}
+
+	b, enc, err := ch.requestGet(ctx, network.ChannelTimeoutOperation, u)
+	if err != nil {
+		return nil, err
+	}
+
+	hinters, err := enc.DecodeSlice(b)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := make([]network.PendingOperationV0, len(hinters))
+	for i := range hinters {
+		j, ok := hinters[i].(network.PendingOperationV0)
+		if !ok {
+			return nil, errors.Errorf("decoded, but not network.PendingOperationV0; %T", hinters[i])
+		}
+
+		pos[i] = j
+	}
+
+	return pos, nil
+}
+
+func (ch *Channel) Equivocations(ctx context.Context, limit uint) ([]base.EquivocationV0, error) {
+	ch.Log().Trace().Uint("limit", limit).Msg("request equivocations")
+
+	u := ch.getEquivocations
+	if limit > 0 {
+		u.RawQuery = url.Values{"limit": []string{strconv.FormatUint(uint64(limit), 10)}}.Encode()
+	}
+
+	b, enc, err := ch.requestGet(ctx, network.ChannelTimeoutOperation, u)
+	if err != nil {
+		return nil, err
+	}
+
+	hinters, err := enc.DecodeSlice(b)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := make([]base.EquivocationV0, len(hinters))
+	for i := range hinters {
+		j, ok := hinters[i].(base.EquivocationV0)
+		if !ok {
+			return nil, errors.Errorf("decoded, but not base.EquivocationV0; %T", hinters[i])
+		}
+
+		evs[i] = j
+	}
+
+	return evs, nil
+}
+
+func (ch *Channel) OperationStatus(ctx context.Context, fact valuehash.Hash) (operation.StatusV0, bool, error) {
+	var so operation.StatusV0
+
+	ch.Log().Trace().Stringer("fact", fact).Msg("request operation status")
+
+	u := ch.getOperationStatusURL
+	u.Path = u.Path + "/" + fact.String()
+
+	b, enc, err := ch.requestGet(ctx, network.ChannelTimeoutOperation, u)
+	switch {
+	case errors.Is(err, util.NotFoundError):
+		return so, false, nil
+	case err != nil:
+		return so, false, err
+	}
+
+	if err := encoder.Decode(b, enc, &so); err != nil {
+		return so, false, err
+	}
+
+	return so, true, nil
+}
+
+func (ch *Channel) OperationsBySigner(
+	ctx context.Context,
+	signer key.Publickey,
+	cursor storage.OperationCursor,
+	limit int64,
+) (network.OperationsPageV0, error) {
+	ch.Log().Trace().Stringer("signer", signer).Stringer("cursor", cursor).Int64("limit", limit).
+		Msg("request operations by signer")
+
+	u := ch.getOperationsBySigner
+	u.Path = u.Path + "/" + signer.String()
+	u.RawQuery = operationsPageQuery(cursor, limit).Encode()
+
+	return ch.requestOperationsPage(ctx, u)
+}
+
+func (ch *Channel) OperationsByHint(
+	ctx context.Context,
+	ht hint.Type,
+	from, to base.Height,
+	cursor storage.OperationCursor,
+	limit int64,
+) (network.OperationsPageV0, error) {
+	ch.Log().Trace().Stringer("hint", ht).Int64("from", from.Int64()).Int64("to", to.Int64()).
+		Stringer("cursor", cursor).Int64("limit", limit).Msg("request operations by hint")
+
+	u := ch.getOperationsByHint
+	u.Path = u.Path + "/" + ht.String()
+
+	q := operationsPageQuery(cursor, limit)
+	q.Set("from", from.String())
+	if !to.IsEmpty() {
+		q.Set("to", to.String())
+	}
+	u.RawQuery = q.Encode()
+
+	return ch.requestOperationsPage(ctx, u)
+}
+
+func (ch *Channel) requestOperationsPage(ctx context.Context, u url.URL) (network.OperationsPageV0, error) {
+	var op network.OperationsPageV0
+
+	b, enc, err := ch.requestGet(ctx, network.ChannelTimeoutOperation, u)
+	if err != nil {
+		return op, err
+	}
+
+	if err := encoder.Decode(b, enc, &op); err != nil {
+		return op, err
+	}
+
+	return op, nil
+}
+
+func operationsPageQuery(cursor storage.OperationCursor, limit int64) url.Values {
+	q := url.Values{}
+	if !cursor.IsNil() {
+		q.Set("cursor", cursor.String())
+	}
+
+	if limit > 0 {
+		q.Set("limit", strconv.FormatInt(limit, 10))
+	}
+
+	return q
+}
+
 func (ch *Channel) SendSeal(ctx context.Context, ci network.ConnInfo, sl seal.Seal) error {
 	l := ch.Log().With().Stringer("cid", util.UUID()).Stringer("seal_hash", sl.Hash()).Logger()
 
@@ -216,6 +412,91 @@ func (ch *Channel) Proposal(ctx context.Context, h valuehash.Hash) (base.Proposa
 	return pr, err
 }
 
+func (ch *Channel) State(ctx context.Context, key string) (state.State, bool, error) {
+	b, enc, err := ch.requestState(ctx, key, false)
+	switch {
+	case errors.Is(err, util.NotFoundError):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+
+	var st state.State
+	if err := encoder.Decode(b, enc, &st); err != nil {
+		return nil, false, err
+	}
+
+	return st, true, nil
+}
+
+func (ch *Channel) StateProof(ctx context.Context, key string) (network.StatePathProofV0, bool, error) {
+	var sp network.StatePathProofV0
+
+	b, enc, err := ch.requestState(ctx, key, true)
+	switch {
+	case errors.Is(err, util.NotFoundError):
+		return sp, false, nil
+	case err != nil:
+		return sp, false, err
+	}
+
+	if err := encoder.Decode(b, enc, &sp); err != nil {
+		return sp, false, err
+	}
+
+	return sp, true, nil
+}
+
+func (ch *Channel) OperationProof(
+	ctx context.Context, height base.Height, fact valuehash.Hash,
+) (network.OperationProofV0, bool, error) {
+	var op network.OperationProofV0
+
+	ch.Log().Trace().Int64("height", height.Int64()).Stringer("fact", fact).Msg("request operation proof")
+
+	u := ch.getOperationProofURL
+	u.Path = u.Path + "/" + height.String() + "/" + fact.String()
+
+	b, enc, err := ch.requestGet(ctx, network.ChannelTimeoutState, u)
+	switch {
+	case errors.Is(err, util.NotFoundError):
+		return op, false, nil
+	case err != nil:
+		return op, false, err
+	}
+
+	if err := encoder.Decode(b, enc, &op); err != nil {
+		return op, false, err
+	}
+
+	return op, true, nil
+}
+
+func (ch *Channel) StatePathProof(
+	ctx context.Context, height base.Height, key string,
+) (network.StatePathProofV0, bool, error) {
+	var sp network.StatePathProofV0
+
+	ch.Log().Trace().Int64("height", height.Int64()).Str("key", key).Msg("request state path proof")
+
+	u := ch.getStatePathProofURL
+	u = appendEscapedPath(u, height.String(), key)
+
+	b, enc, err := ch.requestGet(ctx, network.ChannelTimeoutState, u)
+	switch {
+	case errors.Is(err, util.NotFoundError):
+		return sp, false, nil
+	case err != nil:
+		return sp, false, err
+	}
+
+	if err := encoder.Decode(b, enc, &sp); err != nil {
+		return sp, false, err
+	}
+
+	return sp, true, nil
+}
+
 func (ch *Channel) NodeInfo(ctx context.Context) (network.NodeInfo, error) {
 	timeout := network.ChannelTimeoutNodeInfo
 	ctx, cancel := ch.timeoutContext(ctx, timeout)
@@ -348,6 +629,54 @@ func (ch *Channel) blockdata(ctx context.Context, p string) (io.ReadCloser, func
 	return response.Body(), closeFunc, nil
 }
 
+func (ch *Channel) requestState(ctx context.Context, key string, proof bool) ([]byte, encoder.Encoder, error) {
+	ch.Log().Trace().Str("key", key).Bool("proof", proof).Msg("request state")
+
+	u := appendEscapedPath(ch.getStateURL, key)
+	if proof {
+		u.RawQuery = url.Values{"proof": []string{"true"}}.Encode()
+	}
+
+	return ch.requestGet(ctx, network.ChannelTimeoutState, u)
+}
+
+func (ch *Channel) requestGet(ctx context.Context, timeout time.Duration, u url.URL) ([]byte, encoder.Encoder, error) {
+	ctx, cancel := ch.timeoutContext(ctx, timeout)
+	defer cancel()
+
+	headers := http.Header{}
+	headers.Set(QuicEncoderHintHeader, ch.enc.Hint().String())
+
+	response, err := ch.client.Get(ctx, timeout, u.String(), nil, headers)
+	defer func() {
+		if response == nil {
+			return
+		}
+
+		_ = response.Close()
+	}()
+
+	if err != nil {
+		return nil, nil, err
+	} else if err = response.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	enc, err := EncoderFromHeader(response.Header, ch.encs, ch.enc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, err := response.Bytes()
+	if err != nil {
+		ch.Log().Error().Err(err).Msg("failed to get bytes from response body")
+
+		return nil, nil, err
+	}
+
+	return b, enc, nil
+}
+
 func (ch *Channel) doRequestHinters(
 	ctx context.Context,
 	f clientDoRequestFunc,
@@ -479,3 +808,16 @@ func stripSlashFilePath(p string) string {
 
 	return string(b)
 }
+
+// appendEscapedPath appends the path elements to url. Each element is escaped,
+// so element like state key can have '/', '?' or '%'.
+func appendEscapedPath(u url.URL, elems ...string) url.URL {
+	raw := u.EscapedPath()
+	for i := range elems {
+		u.Path += "/" + elems[i]
+		raw += "/" + url.PathEscape(elems[i])
+	}
+	u.RawPath = raw
+
+	return u
+}
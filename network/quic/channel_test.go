@@ -0,0 +1,34 @@
+package quicnetwork
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testChannelURL struct {
+	suite.Suite
+}
+
+func (t *testChannelURL) TestAppendEscapedPath() {
+	u, err := url.Parse("https://localhost:54321/state")
+	t.NoError(err)
+
+	key := "showme/state?a=%2F:state"
+
+	nu := appendEscapedPath(*u, "33", key)
+	t.Equal("/state/33/"+key, nu.Path)
+	t.Equal("https://localhost:54321/state/33/showme%2Fstate%3Fa=%252F:state", nu.String())
+
+	pu, err := url.Parse(nu.String())
+	t.NoError(err)
+	t.Equal(nu.Path, pu.Path)
+	t.Empty(pu.RawQuery)
+
+	t.Equal("https://localhost:54321/state", u.String()) // NOTE original url is not changed
+}
+
+func TestChannelURL(t *testing.T) {
+	suite.Run(t, new(testChannelURL))
+}
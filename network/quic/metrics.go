@@ -0,0 +1,36 @@
+package quicnetwork
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/hlog"
+	"github.com/spikeekips/mitum/util/metrics"
+)
+
+var metricHandlerDuration = metrics.NewHistogramVec(
+	"mitum_network_handler_duration_seconds",
+	"latency of quic network handlers",
+	nil,
+	"handler", "method", "status",
+)
+
+func init() {
+	metrics.MustRegister(metricHandlerDuration)
+}
+
+// metricsMiddleware observes the latency of the matched handler.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return hlog.AccessHandler(func(r *http.Request, status, _ int, duration time.Duration) {
+		handler := "unknown"
+		if route := mux.CurrentRoute(r); route != nil {
+			if i, err := route.GetPathTemplate(); err == nil {
+				handler = i
+			}
+		}
+
+		metricHandlerDuration.WithLabelValues(handler, r.Method, strconv.Itoa(status)).Observe(duration.Seconds())
+	})(next)
+}
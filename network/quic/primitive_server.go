@@ -52,6 +52,8 @@ func NewPrimitiveQuicServer(
 		httpLog:     httpLog,
 	}
 
+	qs.router.Use(metricsMiddleware)
+
 	root := qs.router.Name("root")
 	root.Path("/").HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -10,35 +10,54 @@ import (
 	"net/url"
 	"path"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"golang.org/x/sync/singleflight"
 )
 
 var (
-	DefaultPort                         = "54321"
-	QuicHandlerPathGetStagedOperations  = "/operations"
-	QuicHandlerPathSendSeal             = "/seal"
-	QuicHandlerPathGetProposal          = "/proposal"
-	QuicHandlerPathGetProposalPattern   = "/proposal" + "/{hash:.*}"
-	QuicHandlerPathGetBlockdataMaps     = "/blockdatamaps"
-	QuicHandlerPathGetBlockdata         = "/blockdata"
-	QuicHandlerPathGetBlockdataPattern  = QuicHandlerPathGetBlockdata + "/{path:.*}"
-	QuicHandlerPathPingHandoverPattern  = "/handover"
-	QuicHandlerPathStartHandoverPattern = QuicHandlerPathPingHandoverPattern + "/start"
-	QuicHandlerPathEndHandoverPattern   = QuicHandlerPathPingHandoverPattern + "/end"
-	QuicHandlerPathNodeInfo             = "/"
+	DefaultPort                                 = "54321"
+	QuicHandlerPathGetStagedOperations          = "/operations"
+	QuicHandlerPathGetPendingOperations         = "/operations/pending"
+	QuicHandlerPathGetOperationStatus           = "/operation/status"
+	QuicHandlerPathGetOperationStatusPattern    = QuicHandlerPathGetOperationStatus + "/{fact:.*}"
+	QuicHandlerPathGetOperationsBySigner        = "/operations/signer"
+	QuicHandlerPathGetOperationsBySignerPattern = QuicHandlerPathGetOperationsBySigner + "/{publickey:.*}"
+	QuicHandlerPathGetOperationsByHint          = "/operations/hint"
+	QuicHandlerPathGetOperationsByHintPattern   = QuicHandlerPathGetOperationsByHint + "/{hint:.*}"
+	QuicHandlerPathGetEquivocations             = "/equivocations"
+	QuicHandlerPathSendSeal                     = "/seal"
+	QuicHandlerPathGetProposal                  = "/proposal"
+	QuicHandlerPathGetProposalPattern           = "/proposal" + "/{hash:.*}"
+	QuicHandlerPathGetState                     = "/state"
+	QuicHandlerPathGetStatePattern              = QuicHandlerPathGetState + "/{key:.*}"
+	QuicHandlerPathGetOperationProof            = "/proof/operation"
+	QuicHandlerPathGetOperationProofPattern     = QuicHandlerPathGetOperationProof + "/{height:[0-9]+}/{fact:.*}"
+	QuicHandlerPathGetStatePathProof            = "/proof/state"
+	QuicHandlerPathGetStatePathProofPattern     = QuicHandlerPathGetStatePathProof + "/{height:[0-9]+}/{key:.*}"
+	QuicHandlerPathGetBlockdataMaps             = "/blockdatamaps"
+	QuicHandlerPathGetBlockdata                 = "/blockdata"
+	QuicHandlerPathGetBlockdataPattern          = QuicHandlerPathGetBlockdata + "/{path:.*}"
+	QuicHandlerPathPingHandoverPattern          = "/handover"
+	QuicHandlerPathStartHandoverPattern         = QuicHandlerPathPingHandoverPattern + "/start"
+	QuicHandlerPathEndHandoverPattern           = QuicHandlerPathPingHandoverPattern + "/end"
+	QuicHandlerPathNodeInfo                     = "/"
 )
 
 var (
@@ -62,6 +81,15 @@ type Server struct {
 	getStagedOperationsHandler network.GetStagedOperationsHandler
 	newSealHandler             network.NewSealHandler
 	getProposalHandler         network.GetProposalHandler
+	getStateHandler            network.GetStateHandler
+	getStateProofHandler       network.GetStateProofHandler
+	getOperationProofHandler   network.GetOperationProofHandler
+	getStatePathProofHandler   network.GetStatePathProofHandler
+	getPendingOperations       network.GetPendingOperationsHandler
+	getOperationStatus         network.GetOperationStatusHandler
+	getOperationsBySigner      network.GetOperationsBySignerHandler
+	getOperationsByHint        network.GetOperationsByHintHandler
+	getEquivocations           network.GetEquivocationsHandler
 	nodeInfoHandler            network.NodeInfoHandler
 	blockdataMapsHandler       network.BlockdataMapsHandler
 	blockdataHandler           network.BlockdataHandler
@@ -138,6 +166,42 @@ func (sv *Server) SetGetProposalHandler(fn network.GetProposalHandler) {
 	sv.getProposalHandler = fn
 }
 
+func (sv *Server) SetGetStateHandler(fn network.GetStateHandler) {
+	sv.getStateHandler = fn
+}
+
+func (sv *Server) SetGetStateProofHandler(fn network.GetStateProofHandler) {
+	sv.getStateProofHandler = fn
+}
+
+func (sv *Server) SetGetOperationProofHandler(fn network.GetOperationProofHandler) {
+	sv.getOperationProofHandler = fn
+}
+
+func (sv *Server) SetGetStatePathProofHandler(fn network.GetStatePathProofHandler) {
+	sv.getStatePathProofHandler = fn
+}
+
+func (sv *Server) SetGetPendingOperationsHandler(fn network.GetPendingOperationsHandler) {
+	sv.getPendingOperations = fn
+}
+
+func (sv *Server) SetGetOperationStatusHandler(fn network.GetOperationStatusHandler) {
+	sv.getOperationStatus = fn
+}
+
+func (sv *Server) SetGetOperationsBySignerHandler(fn network.GetOperationsBySignerHandler) {
+	sv.getOperationsBySigner = fn
+}
+
+func (sv *Server) SetGetOperationsByHintHandler(fn network.GetOperationsByHintHandler) {
+	sv.getOperationsByHint = fn
+}
+
+func (sv *Server) SetGetEquivocationsHandler(fn network.GetEquivocationsHandler) {
+	sv.getEquivocations = fn
+}
+
 func (sv *Server) NodeInfoHandler() network.NodeInfoHandler {
 	return sv.nodeInfoHandler
 }
@@ -168,8 +232,16 @@ func (sv *Server) SetEndHandoverHandler(fn network.EndHandoverHandler) {
 
 func (sv *Server) setHandlers() {
 	_ = sv.SetHandlerFunc(QuicHandlerPathGetStagedOperations, sv.handleGetStagedOperations).Methods("POST")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetPendingOperations, sv.handleGetPendingOperations).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetOperationStatusPattern, sv.handleGetOperationStatus).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetOperationsBySignerPattern, sv.handleGetOperationsBySigner).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetOperationsByHintPattern, sv.handleGetOperationsByHint).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetEquivocations, sv.handleGetEquivocations).Methods("GET")
 	_ = sv.SetHandlerFunc(QuicHandlerPathSendSeal, sv.handleNewSeal).Methods("POST")
 	_ = sv.SetHandlerFunc(QuicHandlerPathGetProposalPattern, sv.handleGetProposal).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetStatePattern, sv.handleGetState).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetOperationProofPattern, sv.handleGetOperationProof).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetStatePathProofPattern, sv.handleGetStatePathProof).Methods("GET")
 	_ = sv.SetHandlerFunc(QuicHandlerPathGetBlockdataMaps, sv.handleGetBlockdataMaps).Methods("POST")
 	_ = sv.SetHandlerFunc(QuicHandlerPathGetBlockdataPattern, sv.handleGetBlockdata).Methods("GET")
 	_ = sv.SetHandlerFunc(QuicHandlerPathNodeInfo, sv.handleNodeInfo)
@@ -229,6 +301,194 @@ func (sv *Server) handleGetStagedOperations(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+func (sv *Server) handleGetPendingOperations(w http.ResponseWriter, r *http.Request) {
+	if sv.getPendingOperations == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	limit, err := parseLimitQuery(r)
+	if err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	v, err, _ := sv.rg.Do("GetPendingOperations-"+strconv.FormatUint(uint64(limit), 10), func() (interface{}, error) {
+		i, err := sv.getPendingOperations(limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return sv.enc.Marshal(i)
+	})
+	if err != nil {
+		sv.Log().Error().Err(err).Msg("failed to get pending operations")
+
+		handleError(w, err)
+
+		return
+	}
+
+	w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+	_, _ = w.Write(v.([]byte))
+}
+
+func (sv *Server) handleGetEquivocations(w http.ResponseWriter, r *http.Request) {
+	if sv.getEquivocations == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	limit, err := parseLimitQuery(r)
+	if err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	v, err, _ := sv.rg.Do("GetEquivocations-"+strconv.FormatUint(uint64(limit), 10), func() (interface{}, error) {
+		i, err := sv.getEquivocations(limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return sv.enc.Marshal(i)
+	})
+	if err != nil {
+		sv.Log().Error().Err(err).Msg("failed to get equivocations")
+
+		handleError(w, err)
+
+		return
+	}
+
+	w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+	_, _ = w.Write(v.([]byte))
+}
+
+func (sv *Server) handleGetOperationStatus(w http.ResponseWriter, r *http.Request) {
+	if sv.getOperationStatus == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	fact := valuehash.NewBytesFromString(strings.TrimSpace(mux.Vars(r)["fact"]))
+	if err := fact.IsValid(nil); err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	sv.writeProof(w, "GetOperationStatus-"+fact.String(), func() (interface{}, bool, error) {
+		return sv.getOperationStatus(fact)
+	})
+}
+
+func (sv *Server) handleGetOperationsBySigner(w http.ResponseWriter, r *http.Request) {
+	if sv.getOperationsBySigner == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	signer, err := key.DecodePublickeyFromString(strings.TrimSpace(mux.Vars(r)["publickey"]), sv.enc)
+	if err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	cursor, limit, err := parseOperationsPageQuery(r)
+	if err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	sv.writeOperationsPage(
+		w,
+		fmt.Sprintf("GetOperationsBySigner-%s-%s-%d", signer, cursor, limit),
+		func() (network.OperationsPageV0, error) {
+			return sv.getOperationsBySigner(signer, cursor, limit)
+		},
+	)
+}
+
+func (sv *Server) handleGetOperationsByHint(w http.ResponseWriter, r *http.Request) {
+	if sv.getOperationsByHint == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	ht := hint.Type(strings.TrimSpace(mux.Vars(r)["hint"]))
+	if err := ht.IsValid(nil); err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	cursor, limit, err := parseOperationsPageQuery(r)
+	if err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	from, to := base.PreGenesisHeight, base.NilHeight
+	for _, i := range []struct {
+		k string
+		h *base.Height
+	}{{"from", &from}, {"to", &to}} {
+		s := strings.TrimSpace(r.URL.Query().Get(i.k))
+		if len(s) < 1 {
+			continue
+		}
+
+		h, err := base.NewHeightFromString(s)
+		if err != nil {
+			network.HTTPError(w, http.StatusBadRequest)
+
+			return
+		}
+
+		*i.h = h
+	}
+
+	sv.writeOperationsPage(
+		w,
+		fmt.Sprintf("GetOperationsByHint-%s-%d-%d-%s-%d", ht, from, to, cursor, limit),
+		func() (network.OperationsPageV0, error) {
+			return sv.getOperationsByHint(ht, from, to, cursor, limit)
+		},
+	)
+}
+
+func (sv *Server) writeOperationsPage(w http.ResponseWriter, key string, f func() (network.OperationsPageV0, error)) {
+	v, err, _ := sv.rg.Do(key, func() (interface{}, error) {
+		i, err := f()
+		if err != nil {
+			return nil, err
+		}
+
+		return sv.enc.Marshal(i)
+	})
+	if err != nil {
+		sv.Log().Error().Str("key", key).Err(err).Msg("failed to get operations")
+
+		handleError(w, err)
+
+		return
+	}
+
+	w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+	_, _ = w.Write(v.([]byte))
+}
+
 func (sv *Server) handleNewSeal(w http.ResponseWriter, r *http.Request) {
 	body := &bytes.Buffer{}
 	if _, err := io.Copy(body, r.Body); err != nil {
@@ -324,6 +584,155 @@ func (sv *Server) handleGetProposal(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(v.([]byte))
 }
 
+func (sv *Server) handleGetState(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	key := strings.TrimSpace(vars["key"])
+	if len(key) < 1 {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	var withProof bool
+	if i := strings.TrimSpace(r.URL.Query().Get("proof")); len(i) > 0 {
+		j, err := strconv.ParseBool(i)
+		if err != nil {
+			network.HTTPError(w, http.StatusBadRequest)
+
+			return
+		}
+		withProof = j
+	}
+
+	if (withProof && sv.getStateProofHandler == nil) || (!withProof && sv.getStateHandler == nil) {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	v, err, _ := sv.rg.Do(fmt.Sprintf("GetState-%v-%s", withProof, key), func() (interface{}, error) {
+		var i interface{}
+		var found bool
+		var err error
+		if withProof {
+			i, found, err = sv.getStateProofHandler(key)
+		} else {
+			i, found, err = sv.getStateHandler(key)
+		}
+
+		switch {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, nil
+		default:
+			return sv.enc.Marshal(i)
+		}
+	})
+	if err != nil {
+		sv.Log().Error().Str("key", key).Bool("proof", withProof).Err(err).Msg("failed to get state")
+
+		handleError(w, err)
+
+		return
+	}
+
+	if v == nil {
+		network.HTTPError(w, http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+	_, _ = w.Write(v.([]byte))
+}
+
+func (sv *Server) handleGetOperationProof(w http.ResponseWriter, r *http.Request) {
+	if sv.getOperationProofHandler == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	height, err := base.NewHeightFromString(strings.TrimSpace(vars["height"]))
+	if err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	fact := valuehash.NewBytesFromString(strings.TrimSpace(vars["fact"]))
+	if err := fact.IsValid(nil); err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	sv.writeProof(w, "GetOperationProof-"+height.String()+"-"+fact.String(), func() (interface{}, bool, error) {
+		return sv.getOperationProofHandler(height, fact)
+	})
+}
+
+func (sv *Server) handleGetStatePathProof(w http.ResponseWriter, r *http.Request) {
+	if sv.getStatePathProofHandler == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	height, err := base.NewHeightFromString(strings.TrimSpace(vars["height"]))
+	if err != nil {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	key := strings.TrimSpace(vars["key"])
+	if len(key) < 1 {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	sv.writeProof(w, "GetStatePathProof-"+height.String()+"-"+key, func() (interface{}, bool, error) {
+		return sv.getStatePathProofHandler(height, key)
+	})
+}
+
+func (sv *Server) writeProof(w http.ResponseWriter, key string, f func() (interface{}, bool, error)) {
+	v, err, _ := sv.rg.Do(key, func() (interface{}, error) {
+		switch i, found, err := f(); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, nil
+		default:
+			return sv.enc.Marshal(i)
+		}
+	})
+	if err != nil {
+		sv.Log().Error().Str("key", key).Err(err).Msg("failed to get proof")
+
+		handleError(w, err)
+
+		return
+	}
+
+	if v == nil {
+		network.HTTPError(w, http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+	_, _ = w.Write(v.([]byte))
+}
+
 func (sv *Server) handleNodeInfo(w http.ResponseWriter, _ *http.Request) {
 	if sv.nodeInfoHandler == nil {
 		network.HTTPError(w, http.StatusInternalServerError)
@@ -552,6 +961,15 @@ func (sv *Server) logNilHanders() {
 	handlers := [][2]interface{}{
 		{sv.getStagedOperationsHandler, "getStagedOperationsHandler"},
 		{sv.newSealHandler, "newSealHandler"},
+		{sv.getStateHandler, "getStateHandler"},
+		{sv.getStateProofHandler, "getStateProofHandler"},
+		{sv.getOperationProofHandler, "getOperationProofHandler"},
+		{sv.getStatePathProofHandler, "getStatePathProofHandler"},
+		{sv.getPendingOperations, "getPendingOperations"},
+		{sv.getOperationStatus, "getOperationStatus"},
+		{sv.getOperationsBySigner, "getOperationsBySigner"},
+		{sv.getOperationsByHint, "getOperationsByHint"},
+		{sv.getEquivocations, "getEquivocations"},
 		{sv.nodeInfoHandler, "nodeInfoHandler"},
 		{sv.blockdataMapsHandler, "blockdataMapsHandler"},
 		{sv.blockdataHandler, "blockdataHandler"},
@@ -609,6 +1027,39 @@ func mustQuicURL(u, p string) (string, *url.URL) {
 	return uu.String(), uu
 }
 
+func parseLimitQuery(r *http.Request) (uint, error) {
+	s := strings.TrimSpace(r.URL.Query().Get("limit"))
+	if len(s) < 1 {
+		return 0, nil
+	}
+
+	i, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(i), nil
+}
+
+func parseOperationsPageQuery(r *http.Request) (storage.OperationCursor, int64, error) {
+	cursor, err := storage.ParseOperationCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return storage.NilOperationCursor, 0, err
+	}
+
+	var limit int64
+	if s := strings.TrimSpace(r.URL.Query().Get("limit")); len(s) > 0 {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return storage.NilOperationCursor, 0, err
+		}
+
+		limit = i
+	}
+
+	return cursor, limit, nil
+}
+
 func handleError(w http.ResponseWriter, err error) {
 	status := http.StatusInternalServerError
 	if errors.Is(err, util.NotFoundError) {
@@ -308,6 +308,55 @@ func (t *testQuicServer) TestGetProposal() {
 	}
 }
 
+func (t *testQuicServer) TestGetState() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	v, err := state.NewBytesValue(util.UUID().Bytes())
+	t.NoError(err)
+
+	// NOTE key has characters, which should be escaped in url path
+	st, err := state.NewStateV0("showme/state?a=%2F:state", v, base.Height(33))
+	t.NoError(err)
+	i, err := st.SetHash(st.GenerateHash())
+	t.NoError(err)
+	st = i.(state.StateV0)
+
+	qn.SetGetStateHandler(func(key string) (state.State, bool, error) {
+		if key != st.Key() {
+			return nil, false, nil
+		}
+
+		return st, true, nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+
+	{ // normal
+		ust, found, err := qc.State(context.TODO(), st.Key())
+		t.NoError(err)
+		t.True(found)
+		t.NoError(ust.IsValid(nil))
+
+		t.Equal(st.Key(), ust.Key())
+		t.True(st.Hash().Equal(ust.Hash()))
+		t.Equal(st.Height(), ust.Height())
+	}
+
+	{ // unknown
+		ust, found, err := qc.State(context.TODO(), "unknown")
+		t.NoError(err)
+		t.False(found)
+		t.Nil(ust)
+	}
+
+	{ // proof handler is missing
+		_, _, err := qc.StateProof(context.TODO(), st.Key())
+		t.Error(err)
+	}
+}
+
 func (t *testQuicServer) TestNodeInfo() {
 	qn := t.readyServer()
 	defer qn.Stop()
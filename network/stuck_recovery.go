@@ -0,0 +1,19 @@
+package network
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+)
+
+// StuckRecoveryRecord is the result of recovery action, which node tried when
+// consensus looked stuck; Height and Round are of the last voteproof when the
+// action was tried.
+type StuckRecoveryRecord struct {
+	Action  string      `json:"action" bson:"action"`
+	Height  base.Height `json:"height" bson:"height"`
+	Round   base.Round  `json:"round" bson:"round"`
+	Result  string      `json:"result" bson:"result"`
+	Message string      `json:"message,omitempty" bson:"message,omitempty"`
+	At      time.Time   `json:"at" bson:"at"`
+}
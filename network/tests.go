@@ -8,6 +8,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,6 +46,29 @@ func CompareNodeInfo(t *testing.T, a, b NodeInfo) {
 	}
 
 	assert.True(t, a.ConnInfo().Equal(b.ConnInfo()))
+
+	aevs := a.Equivocations()
+	bevs := b.Equivocations()
+	assert.Equal(t, len(aevs), len(bevs))
+
+	for i := range aevs {
+		assert.Equal(t, aevs[i].Key(), bevs[i].Key())
+		assert.True(t, aevs[i].First().Fact().Hash().Equal(bevs[i].First().Fact().Hash()))
+		assert.True(t, aevs[i].Second().Fact().Hash().Equal(bevs[i].Second().Fact().Hash()))
+	}
+
+	asr := a.StuckRecoveries()
+	bsr := b.StuckRecoveries()
+	assert.Equal(t, len(asr), len(bsr))
+
+	for i := range asr {
+		assert.Equal(t, asr[i].Action, bsr[i].Action)
+		assert.Equal(t, asr[i].Height, bsr[i].Height)
+		assert.Equal(t, asr[i].Round, bsr[i].Round)
+		assert.Equal(t, asr[i].Result, bsr[i].Result)
+		assert.Equal(t, asr[i].Message, bsr[i].Message)
+		assert.True(t, localtime.Normalize(asr[i].At).Equal(localtime.Normalize(bsr[i].At)))
+	}
 }
 
 func NilConnInfoChannel(s string) *DummyChannel {
@@ -0,0 +1,357 @@
+//go:build test
+// +build test
+
+package basicstates
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/ballot"
+	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type ByzantineType string
+
+const (
+	// ByzantineConflictingBallot sends the ballot to the half of nodes and
+	// the conflicting ballot of same height and round to the other half.
+	ByzantineConflictingBallot ByzantineType = "conflicting-ballot"
+	// ByzantineWithholdVote does not send the ballot to the other nodes.
+	ByzantineWithholdVote ByzantineType = "withhold-vote"
+	// ByzantineInvalidOperations sends the proposal with the unknown
+	// operations.
+	ByzantineInvalidOperations ByzantineType = "invalid-operations"
+	// ByzantineForgeVoteproof sends the ballot with the forged voteproof,
+	// which has only the vote of local.
+	ByzantineForgeVoteproof ByzantineType = "forge-voteproof"
+	// ByzantineDelay sends the ballot after delay.
+	ByzantineDelay ByzantineType = "delay"
+)
+
+func (bt ByzantineType) IsValid([]byte) error {
+	switch bt {
+	case ByzantineConflictingBallot,
+		ByzantineWithholdVote,
+		ByzantineInvalidOperations,
+		ByzantineForgeVoteproof,
+		ByzantineDelay:
+		return nil
+	default:
+		return isvalid.InvalidError.Errorf("unknown ByzantineType, %q", bt)
+	}
+}
+
+// ByzantineRule decides which ballots are handled by the byzantine behavior.
+// Zero Stage means all the stages, NilHeight of From and To means no limit.
+type ByzantineRule struct {
+	Type  ByzantineType
+	Stage base.Stage
+	From  base.Height
+	To    base.Height
+	Delay time.Duration
+}
+
+func NewByzantineRule(t ByzantineType) ByzantineRule {
+	return ByzantineRule{Type: t, From: base.NilHeight, To: base.NilHeight}
+}
+
+func (br ByzantineRule) IsValid([]byte) error {
+	if err := br.Type.IsValid(nil); err != nil {
+		return err
+	}
+
+	if br.Stage != 0 {
+		if err := br.Stage.IsValid(nil); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case br.Type == ByzantineInvalidOperations && br.Stage != 0 && br.Stage != base.StageProposal:
+		return isvalid.InvalidError.Errorf("invalid-operations only for proposal, not %s", br.Stage)
+	case br.Type == ByzantineDelay && br.Delay <= 0:
+		return isvalid.InvalidError.Errorf("empty delay")
+	case br.From != base.NilHeight && br.To != base.NilHeight && br.From > br.To:
+		return isvalid.InvalidError.Errorf("from is higher than to; %d > %d", br.From, br.To)
+	default:
+		return nil
+	}
+}
+
+func (br ByzantineRule) match(blt base.Ballot) bool {
+	fact := blt.RawFact()
+
+	switch {
+	case br.Stage != 0 && br.Stage != fact.Stage():
+		return false
+	case br.From != base.NilHeight && fact.Height() < br.From:
+		return false
+	case br.To != base.NilHeight && fact.Height() > br.To:
+		return false
+	case br.Type == ByzantineInvalidOperations && fact.Stage() != base.StageProposal:
+		return false
+	default:
+		return true
+	}
+}
+
+// SetByzantine makes States to broadcast ballots by the byzantine rules; the
+// first matched rule is applied to the ballot. Empty rules stops the byzantine
+// behavior. SetByzantine should be called before States starts.
+func (ss *States) SetByzantine(rules []ByzantineRule) error {
+	for i := range rules {
+		if err := rules[i].IsValid(nil); err != nil {
+			return err
+		}
+	}
+
+	if len(rules) < 1 {
+		ss.byzantine = nil
+
+		return nil
+	}
+
+	ss.byzantine = &byzantine{ss: ss, rules: rules}
+
+	return nil
+}
+
+// byzantineStates keeps the byzantine behavior of States; see
+// byzantine_none.go for the non-test build.
+type byzantineStates struct {
+	byzantine *byzantine
+}
+
+// broadcast broadcasts the seal; if the byzantine behavior takes over the
+// seal, the seal is not broadcasted by States.
+func (ss *States) broadcast(sl seal.Seal, toLocal bool, filter func(base.Node) bool) {
+	if ss.byzantine != nil && ss.byzantine.broadcast(sl, toLocal, filter) {
+		return
+	}
+
+	ss.sendSeal(sl, toLocal, filter)
+}
+
+type byzantine struct {
+	ss    *States
+	rules []ByzantineRule
+}
+
+func (bz *byzantine) broadcast(sl seal.Seal, toLocal bool, filter func(base.Node) bool) bool {
+	blt, ok := sl.(base.Ballot)
+	if !ok {
+		return false
+	}
+
+	var rule ByzantineRule
+	var found bool
+	for i := range bz.rules {
+		if bz.rules[i].match(blt) {
+			rule, found = bz.rules[i], true
+
+			break
+		}
+	}
+
+	if !found {
+		return false
+	}
+
+	l := bz.ss.Log().With().Str("byzantine", string(rule.Type)).Dict("seal", LogSeal(sl)).Logger()
+
+	var err error
+	switch rule.Type {
+	case ByzantineConflictingBallot:
+		err = bz.conflicting(blt, toLocal, filter)
+	case ByzantineWithholdVote:
+		bz.ss.sendSeal(blt, toLocal, noneNodes)
+	case ByzantineInvalidOperations:
+		err = bz.invalidOperations(blt, toLocal, filter)
+	case ByzantineForgeVoteproof:
+		err = bz.forge(blt, toLocal, filter)
+	case ByzantineDelay:
+		<-localtime.After(rule.Delay)
+
+		bz.ss.sendSeal(blt, toLocal, filter)
+	}
+
+	if err != nil {
+		l.Error().Err(err).Msg("failed byzantine behavior; ballot will be broadcasted")
+
+		return false
+	}
+
+	l.Debug().Msg("byzantine behavior")
+
+	return true
+}
+
+func (bz *byzantine) conflicting(blt base.Ballot, toLocal bool, filter func(base.Node) bool) error {
+	fact := blt.RawFact()
+
+	var other base.Ballot
+	var err error
+
+	switch t := blt.RawFact().(type) {
+	case base.INITBallotFact:
+		other, err = bz.newBallot(blt,
+			ballot.NewINITFact(fact.Height(), fact.Round(), valuehash.RandomSHA256()), blt.BaseVoteproof())
+	case base.ProposalFact:
+		// NOTE the fact of same operations can be same with original
+		var ops []valuehash.Hash
+		if len(t.Operations()) < 1 {
+			ops = []valuehash.Hash{valuehash.RandomSHA256()}
+		}
+
+		other, err = bz.newBallot(blt,
+			ballot.NewProposalFact(fact.Height(), fact.Round(), t.Proposer(), ops), blt.BaseVoteproof())
+	case base.ACCEPTBallotFact:
+		other, err = bz.newBallot(blt,
+			ballot.NewACCEPTFact(fact.Height(), fact.Round(), t.Proposal(), valuehash.RandomSHA256()),
+			blt.BaseVoteproof())
+	default:
+		return errors.Errorf("unknown ballot fact, %T", fact)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	bz.ss.sendSeal(blt, toLocal, bz.half(filter, 0))
+	bz.ss.sendSeal(other, false, bz.half(filter, 1))
+
+	return nil
+}
+
+func (bz *byzantine) invalidOperations(blt base.Ballot, toLocal bool, filter func(base.Node) bool) error {
+	fact, ok := blt.RawFact().(base.ProposalFact)
+	if !ok {
+		return errors.Errorf("not proposal fact, %T", blt.RawFact())
+	}
+
+	ops := make([]valuehash.Hash, len(fact.Operations())+1)
+	copy(ops, fact.Operations())
+	ops[len(ops)-1] = valuehash.RandomSHA256()
+
+	pr, err := bz.newBallot(blt,
+		ballot.NewProposalFact(fact.Height(), fact.Round(), fact.Proposer(), ops), blt.BaseVoteproof())
+	if err != nil {
+		return err
+	}
+
+	bz.ss.sendSeal(blt, toLocal, noneNodes)
+	bz.ss.sendSeal(pr, false, filter)
+
+	return nil
+}
+
+func (bz *byzantine) forge(blt base.Ballot, toLocal bool, filter func(base.Node) bool) error {
+	vp := blt.BaseVoteproof()
+	if vp == nil {
+		return errors.Errorf("empty base voteproof")
+	}
+
+	var majority base.BallotFact
+	switch vp.Stage() {
+	case base.StageINIT:
+		majority = ballot.NewINITFact(vp.Height(), vp.Round(), valuehash.RandomSHA256())
+	case base.StageACCEPT:
+		majority = ballot.NewACCEPTFact(vp.Height(), vp.Round(), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	default:
+		return errors.Errorf("unknown stage of voteproof, %s", vp.Stage())
+	}
+
+	local := bz.ss.nodepool.LocalNode()
+
+	sf, err := base.NewBaseSignedBallotFactFromFact(
+		majority, local.Address(), local.Privatekey(), bz.ss.policy.NetworkID())
+	if err != nil {
+		return err
+	}
+
+	forged := base.NewTestVoteproofV0(
+		vp.Height(),
+		vp.Round(),
+		vp.Suffrages(),
+		vp.ThresholdRatio(),
+		base.VoteResultMajority,
+		vp.IsClosed(),
+		vp.Stage(),
+		majority,
+		[]base.BallotFact{majority},
+		[]base.SignedBallotFact{sf},
+		vp.FinishedAt(),
+	)
+
+	nb, err := bz.newBallot(blt, blt.RawFact(), forged)
+	if err != nil {
+		return err
+	}
+
+	bz.ss.sendSeal(blt, toLocal, noneNodes)
+	bz.ss.sendSeal(nb, false, filter)
+
+	return nil
+}
+
+// newBallot signs the new ballot of fact by local.
+func (bz *byzantine) newBallot(blt base.Ballot, fact base.BallotFact, vp base.Voteproof) (base.Ballot, error) {
+	local := bz.ss.nodepool.LocalNode()
+	priv := local.Privatekey()
+	networkID := bz.ss.policy.NetworkID()
+
+	var nb base.Ballot
+	var err error
+
+	switch t := fact.(type) {
+	case ballot.INITFact:
+		// NOTE ACCEPTVoteproof() returns the base voteproof of ACCEPT stage
+		avp := blt.ACCEPTVoteproof()
+		if vp != nil && vp.Stage() == base.StageACCEPT {
+			avp = nil
+		}
+
+		nb, err = ballot.NewINIT(t, local.Address(), vp, avp, priv, networkID)
+	case ballot.ProposalFact:
+		nb, err = ballot.NewProposal(t, local.Address(), vp, priv, networkID)
+	case ballot.ACCEPTFact:
+		nb, err = ballot.NewACCEPT(t, local.Address(), vp, priv, networkID)
+	default:
+		return nil, errors.Errorf("unknown ballot fact, %T", fact)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return nb, nil
+}
+
+// half splits the nodes by the order in suffrage; the nodes out of suffrage
+// belong to the first half.
+func (bz *byzantine) half(filter func(base.Node) bool, h int) func(base.Node) bool {
+	nodes := bz.ss.suffrage.Nodes()
+
+	return func(n base.Node) bool {
+		if !filter(n) {
+			return false
+		}
+
+		for i := range nodes {
+			if nodes[i].Equal(n.Address()) {
+				return i%2 == h
+			}
+		}
+
+		return h == 0
+	}
+}
+
+func noneNodes(base.Node) bool {
+	return false
+}
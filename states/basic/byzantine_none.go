@@ -0,0 +1,17 @@
+//go:build !test
+// +build !test
+
+package basicstates
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/seal"
+)
+
+// byzantineStates is empty in the non-test build, so the byzantine behavior
+// can not be set; see byzantine.go.
+type byzantineStates struct{}
+
+func (ss *States) broadcast(sl seal.Seal, toLocal bool, filter func(base.Node) bool) {
+	ss.sendSeal(sl, toLocal, filter)
+}
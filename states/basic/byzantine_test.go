@@ -0,0 +1,208 @@
+package basicstates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/seal"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testByzantine struct {
+	baseTestState
+}
+
+func (t *testByzantine) newStates(rules ...ByzantineRule) (*States, *channetwork.Channel) {
+	remotech := channetwork.NewChannel(1, t.remote.Channel().ConnInfo())
+	_ = t.remote.SetChannel(remotech)
+	t.NoError(t.local.Nodes().SetChannel(t.remote.Node().Address(), remotech))
+
+	// NOTE remote is the second half of suffrage
+	suffrage := t.Suffrage(t.local, t.local, t.remote)
+	ss, err := NewStates(
+		t.local.Database(),
+		t.local.Policy(),
+		t.local.Nodes(),
+		suffrage,
+		t.Ballotbox(suffrage, t.local.Policy()),
+		NewBaseState(base.StateStopped),
+		NewBaseState(base.StateBooting),
+		NewBaseState(base.StateJoining),
+		NewBaseState(base.StateConsensus),
+		NewBaseState(base.StateSyncing),
+		NewBaseState(base.StateHandover),
+		nil,
+		nil,
+	)
+	t.NoError(err)
+	t.NoError(ss.SetByzantine(rules))
+
+	return ss, remotech
+}
+
+func (*testByzantine) broadcast(ss *States, sl seal.Seal) {
+	go ss.broadcast(sl, false, func(n base.Node) bool {
+		return ss.suffrage.IsInside(n.Address())
+	})
+}
+
+func (*testByzantine) receive(ch *channetwork.Channel, timeout time.Duration) seal.Seal {
+	select {
+	case <-time.After(timeout):
+		return nil
+	case sl := <-ch.ReceiveSeal():
+		return sl.Seal
+	}
+}
+
+func (t *testByzantine) TestRuleIsValid() {
+	t.NoError(NewByzantineRule(ByzantineWithholdVote).IsValid(nil))
+	t.Error(NewByzantineRule(ByzantineType("findme")).IsValid(nil))
+
+	r := NewByzantineRule(ByzantineDelay)
+	t.Error(r.IsValid(nil))
+	r.Delay = time.Second
+	t.NoError(r.IsValid(nil))
+
+	r = NewByzantineRule(ByzantineInvalidOperations)
+	r.Stage = base.StageINIT
+	t.Error(r.IsValid(nil))
+
+	r = NewByzantineRule(ByzantineConflictingBallot)
+	r.From, r.To = base.Height(3), base.Height(2)
+	t.Error(r.IsValid(nil))
+
+	ss, _ := t.newStates()
+	t.Error(ss.SetByzantine([]ByzantineRule{NewByzantineRule(ByzantineDelay)}))
+}
+
+func (t *testByzantine) TestNotMatched() {
+	r := NewByzantineRule(ByzantineWithholdVote)
+	r.From = base.Height(100)
+
+	ss, remotech := t.newStates(r)
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	t.broadcast(ss, ib)
+
+	sl := t.receive(remotech, time.Second*2)
+	t.NotNil(sl)
+	t.True(ib.Hash().Equal(sl.Hash()))
+}
+
+func (t *testByzantine) TestWithholdVote() {
+	ss, remotech := t.newStates(NewByzantineRule(ByzantineWithholdVote))
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	t.broadcast(ss, ib)
+
+	t.Nil(t.receive(remotech, time.Millisecond*300))
+}
+
+func (t *testByzantine) TestConflictingBallot() {
+	ss, remotech := t.newStates(NewByzantineRule(ByzantineConflictingBallot))
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	t.broadcast(ss, ib)
+
+	sl := t.receive(remotech, time.Second*2)
+	t.NotNil(sl)
+	t.NoError(sl.IsValid(t.local.Policy().NetworkID()))
+
+	rib, ok := sl.(base.INITBallot)
+	t.True(ok)
+
+	t.True(t.local.Node().Address().Equal(rib.FactSign().Node()))
+	t.Equal(ib.Fact().Height(), rib.Fact().Height())
+	t.Equal(ib.Fact().Round(), rib.Fact().Round())
+	t.False(ib.Fact().Hash().Equal(rib.Fact().Hash()))
+}
+
+func (t *testByzantine) TestInvalidOperations() {
+	ss, remotech := t.newStates(NewByzantineRule(ByzantineInvalidOperations))
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	vp, err := t.NewVoteproof(base.StageINIT, ib.Fact(), t.local, t.remote)
+	t.NoError(err)
+
+	op := valuehash.RandomSHA256()
+	pr := t.NewProposal(t.local, vp.Round(), []valuehash.Hash{op}, vp)
+
+	// NOTE INIT ballot is not affected
+	t.broadcast(ss, ib)
+
+	sl := t.receive(remotech, time.Second*2)
+	t.NotNil(sl)
+	t.True(ib.Hash().Equal(sl.Hash()))
+
+	t.broadcast(ss, pr)
+
+	sl = t.receive(remotech, time.Second*2)
+	t.NotNil(sl)
+	t.NoError(sl.IsValid(t.local.Policy().NetworkID()))
+
+	rpr, ok := sl.(base.Proposal)
+	t.True(ok)
+
+	t.Equal(2, len(rpr.Fact().Operations()))
+	t.True(op.Equal(rpr.Fact().Operations()[0]))
+	t.False(pr.Fact().Hash().Equal(rpr.Fact().Hash()))
+}
+
+func (t *testByzantine) TestForgeVoteproof() {
+	ss, remotech := t.newStates(NewByzantineRule(ByzantineForgeVoteproof))
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	vp, err := t.NewVoteproof(base.StageINIT, ib.Fact(), t.local, t.remote)
+	t.NoError(err)
+
+	ab := t.NewACCEPTBallot(t.local, vp.Round(), valuehash.RandomSHA256(), valuehash.RandomSHA256(), vp)
+	t.broadcast(ss, ab)
+
+	sl := t.receive(remotech, time.Second*2)
+	t.NotNil(sl)
+
+	rab, ok := sl.(base.ACCEPTBallot)
+	t.True(ok)
+
+	t.True(ab.Fact().Hash().Equal(rab.Fact().Hash()))
+
+	forged := rab.BaseVoteproof()
+	t.Equal(vp.Height(), forged.Height())
+	t.Equal(vp.Round(), forged.Round())
+	t.False(vp.Majority().Hash().Equal(forged.Majority().Hash()))
+	t.Equal(1, len(forged.Votes()))
+
+	// NOTE forged voteproof does not have enough votes
+	err = forged.IsValid(t.local.Policy().NetworkID())
+	t.Error(err)
+}
+
+func (t *testByzantine) TestDelay() {
+	r := NewByzantineRule(ByzantineDelay)
+	r.Delay = time.Millisecond * 500
+
+	ss, remotech := t.newStates(r)
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	t.broadcast(ss, ib)
+
+	t.Nil(t.receive(remotech, time.Millisecond*200))
+
+	sl := t.receive(remotech, time.Second*2)
+	if sl == nil {
+		t.NoError(errors.Errorf("delayed ballot not received"))
+
+		return
+	}
+
+	t.True(ib.Hash().Equal(sl.Hash()))
+}
+
+func TestByzantine(t *testing.T) {
+	suite.Run(t, new(testByzantine))
+}
@@ -0,0 +1,54 @@
+package basicstates
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/metrics"
+)
+
+var (
+	metricRoundDuration = metrics.NewHistogramVec(
+		"mitum_consensus_round_duration_seconds",
+		"time from init voteproof to the end of round",
+		[]float64{.1, .25, .5, 1, 2, 3, 5, 10, 20, 30, 60},
+		"result",
+	)
+	metricHeight        = metrics.NewGaugeVec("mitum_consensus_height", "height of last init voteproof")
+	metricRound         = metrics.NewGaugeVec("mitum_consensus_round", "round of last init voteproof")
+	metricStuck         = metrics.NewGaugeVec("mitum_consensus_stuck", "1 if consensus looks stuck")
+	metricStuckRecovery = metrics.NewCounterVec(
+		"mitum_consensus_stuck_recovery_total",
+		"recovery actions tried while consensus is stuck",
+		"action", "result",
+	)
+)
+
+func init() {
+	metrics.MustRegister(metricRoundDuration, metricHeight, metricRound, metricStuck, metricStuckRecovery)
+}
+
+type roundStarted struct {
+	height  base.Height
+	round   base.Round
+	started time.Time
+}
+
+func (st *BaseConsensusState) startRound(voteproof base.Voteproof) {
+	metricHeight.WithLabelValues().Set(float64(voteproof.Height().Int64()))
+	metricRound.WithLabelValues().Set(float64(voteproof.Round().Uint64()))
+
+	_ = st.rs.Set(roundStarted{height: voteproof.Height(), round: voteproof.Round(), started: time.Now()})
+}
+
+// finishRound observes the duration of the round, which the voteproof closes.
+func (st *BaseConsensusState) finishRound(voteproof base.Voteproof, result string) {
+	i, ok := st.rs.Value().(roundStarted)
+	if !ok || i.height != voteproof.Height() || i.round != voteproof.Round() {
+		return
+	}
+
+	_ = st.rs.Set(nil)
+
+	metricRoundDuration.WithLabelValues(result).Since(i.started)
+}
@@ -39,6 +39,7 @@ type BaseState struct {
 	processVoteproofFunc  func(base.Voteproof) error
 	syncableChannelsFunc  func() map[string]network.Channel
 	resetBallotboxFunc    func()
+	conflictingFunc       func(base.Height, base.Round) bool
 	exiting               *util.LockedItem
 }
 
@@ -207,6 +208,14 @@ func (st *BaseState) underHandover() bool {
 	return st.States != nil && st.States.underHandover()
 }
 
+func (st *BaseState) isConflictingProposal(height base.Height, round base.Round) bool {
+	if st.conflictingFunc != nil {
+		return st.conflictingFunc(height, round)
+	}
+
+	return st.States != nil && st.States.isConflictingProposal(height, round)
+}
+
 func (st *BaseState) resetBallotbox() {
 	if st.resetBallotboxFunc != nil {
 		st.resetBallotboxFunc()
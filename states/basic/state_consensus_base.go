@@ -39,6 +39,8 @@ type BaseConsensusState struct {
 	broadcastNewINITBallot func(base.Voteproof) error
 	prepareProposal        func(base.Height, base.Round, base.Voteproof) (base.Proposal, error)
 	lib                    *util.LockedItem // last broadcasted INIT Ballot
+	rs                     *util.LockedItem // started time of current round
+	refused                *util.LockedItem // last init voteproof, which refused conflicting proposals
 }
 
 func NewBaseConsensusState(
@@ -62,6 +64,8 @@ func NewBaseConsensusState(
 		proposalMaker: proposalMaker,
 		pps:           pps,
 		lib:           util.NewLockedItem(nil),
+		rs:            util.NewLockedItem(nil),
+		refused:       util.NewLockedItem(nil),
 	}
 
 	bc.broadcastACCEPTBallot = func(valuehash.Hash, valuehash.Hash, base.Voteproof, time.Duration) error {
@@ -127,6 +131,8 @@ func (st *BaseConsensusState) ProcessVoteproof(voteproof base.Voteproof) error {
 	if voteproof.Result() == base.VoteResultDraw { // NOTE moves to next round
 		st.Log().Debug().Str("voteproof_id", voteproof.ID()).Msg("draw voteproof found; moves to next round")
 
+		st.finishRound(voteproof, "draw")
+
 		return st.nextRound(voteproof)
 	}
 
@@ -223,14 +229,18 @@ func (st *BaseConsensusState) nextRound(voteproof base.Voteproof) error {
 
 // ProcessProposal processes incoming proposal, not from local
 func (st *BaseConsensusState) ProcessProposal(proposal base.Proposal) error {
+	if fact := proposal.Fact(); st.isConflictingProposal(fact.Height(), fact.Round()) {
+		return st.refuseConflictingProposal(proposal)
+	}
+
 	if err := st.broadcastProposal(proposal); err != nil {
 		return err
 	}
 
-	started := time.Now()
+	started := localtime.UTCNow()
 	switch voteproof, newBlock, _ := st.processProposal(proposal); {
 	case newBlock != nil:
-		initialDelay := st.policy.WaitBroadcastingACCEPTBallot() - (time.Since(started))
+		initialDelay := st.policy.WaitBroadcastingACCEPTBallot() - localtime.UTCNow().Sub(started)
 		if initialDelay < 0 {
 			initialDelay = time.Nanosecond
 		}
@@ -241,6 +251,53 @@ func (st *BaseConsensusState) ProcessProposal(proposal base.Proposal) error {
 	}
 }
 
+// refuseConflictingProposal does not vote for the conflicting proposals; the
+// accept ballot is not broadcasted any more and after timeout, moves to next
+// round.
+func (st *BaseConsensusState) refuseConflictingProposal(proposal base.Proposal) error {
+	fact := proposal.Fact()
+
+	// NOTE if last init voteproof is not for proposal, voteproof of proposal
+	// will be used.
+	voteproof := st.LastINITVoteproof()
+	if voteproof == nil || voteproof.Height() != fact.Height() || voteproof.Round() != fact.Round() {
+		voteproof = proposal.BaseVoteproof()
+	}
+
+	if voteproof.Stage() != base.StageINIT || voteproof.Height() != fact.Height() || voteproof.Round() != fact.Round() {
+		return nil
+	}
+
+	if i := st.refused.Value(); i != nil && i.(string) == voteproof.ID() {
+		return nil
+	}
+
+	_ = st.refused.Set(voteproof.ID())
+
+	st.Log().Warn().Str("voteproof_id", voteproof.ID()).Stringer("proposal_fact", fact.Hash()).
+		Msg("conflicting proposals found; will not vote and moves to next round")
+
+	timer, err := st.broadcastNextRoundINITBallot(
+		voteproof, nil,
+		func(i int) time.Duration {
+			if i < 1 {
+				return st.policy.TimeoutWaitingProposal()
+			}
+
+			return st.policy.IntervalBroadcastingINITBallot()
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := st.Timers().SetTimer(timer); err != nil {
+		return err
+	}
+
+	return st.Timers().StartTimers([]localtime.TimerID{TimerIDBroadcastINITBallot}, true)
+}
+
 func (st *BaseConsensusState) newINITVoteproof(voteproof base.Voteproof) error {
 	if err := st.Timers().StopTimers([]localtime.TimerID{TimerIDBroadcastProposal}); err != nil {
 		return err
@@ -254,6 +311,8 @@ func (st *BaseConsensusState) newINITVoteproof(voteproof base.Voteproof) error {
 
 	l.Debug().Msg("processing new init voteproof; propose proposal")
 
+	st.startRound(voteproof)
+
 	actingSuffrage, err := st.suffrage.Acting(voteproof.Height(), voteproof.Round())
 	if err != nil {
 		l.Error().Err(err).Msg("failed to get acting suffrage")
@@ -342,6 +401,8 @@ func (st *BaseConsensusState) processACCEPTVoteproof(voteproof base.Voteproof) e
 		if err != nil {
 			l.Error().Err(err).Msg("failed to save block from accept voteproof; moves to syncing")
 
+			st.finishRound(voteproof, "failed")
+
 			// NOTE if failed to save block, moves to syncing
 			return st.NewStateSwitchContext(base.StateSyncing).
 				SetVoteproof(voteproof).
@@ -351,6 +412,8 @@ func (st *BaseConsensusState) processACCEPTVoteproof(voteproof base.Voteproof) e
 
 	l.Info().Object("block", newBlock).Dur("elapsed", time.Since(s)).Msg("new block stored")
 
+	st.finishRound(voteproof, "accept")
+
 	return st.NewBlocks([]block.Block{newBlock})
 }
 
@@ -463,6 +526,10 @@ func (st *BaseConsensusState) processProposal(proposal base.Proposal) (base.Vote
 
 	l.Debug().Msg("processing proposal")
 
+	if err := st.database.SetOperationStatuses(isaac.ProposedOperationStatuses(proposal.Fact())); err != nil {
+		l.Error().Err(err).Msg("failed to set proposed operation statuses")
+	}
+
 	voteproof := st.LastINITVoteproof()
 
 	// NOTE if last init voteproof is not for proposal, voteproof of proposal
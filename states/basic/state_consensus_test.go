@@ -247,6 +247,54 @@ func (t *testStateConsensus) TestFindProposal() {
 	}
 }
 
+// TestRefuseConflictingProposal tests,
+// - the proposer signed the conflicting proposals
+// - state does not vote for the proposal
+// - after timeout, moves to next round
+func (t *testStateConsensus) TestRefuseConflictingProposal() {
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	initFact := ib.Fact()
+
+	vp, err := t.NewVoteproof(base.StageINIT, initFact, t.local, t.remote)
+	t.NoError(err)
+
+	pr := t.NewProposal(t.remote, initFact.Round(), nil, vp)
+	t.NoError(t.local.Database().NewProposal(pr))
+
+	st, done := t.newState(nil, nil) // NOTE set local is not proposer
+	defer done()
+
+	st.SetConflictingProposalFunc(func(height base.Height, round base.Round) bool {
+		return height == vp.Height() && round == vp.Round()
+	})
+
+	sealch := make(chan seal.Seal, 1)
+	st.SetBroadcastSealsFunc(func(sl seal.Seal, toLocal bool) error {
+		switch sl.(type) {
+		case base.ACCEPTBallot, base.INITBallot:
+			sealch <- sl
+		}
+
+		return nil
+	})
+
+	t.local.Policy().SetTimeoutWaitingProposal(time.Millisecond * 10)
+	f, err := st.Enter(NewStateSwitchContext(base.StateJoining, base.StateConsensus).SetVoteproof(vp))
+	t.NoError(err)
+	t.NoError(f())
+
+	select {
+	case <-time.After(time.Second * 3):
+		t.NoError(errors.Errorf("timeout to wait init ballot"))
+	case sl := <-sealch:
+		bb, ok := sl.(base.INITBallot)
+		t.True(ok, "expected init ballot, not %T", sl)
+
+		t.Equal(vp.Height(), bb.Fact().Height())
+		t.Equal(vp.Round()+1, bb.Fact().Round())
+	}
+}
+
 // TestTimeoutWaitingProposal tests,
 // - ConsensusState receives init voteproof and wait proposal
 // - local is not proposer
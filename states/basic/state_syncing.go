@@ -2,6 +2,7 @@ package basicstates
 
 import (
 	"context"
+	"io"
 	"sort"
 	"sync"
 	"time"
@@ -59,6 +60,7 @@ type SyncingState struct {
 	nc                   *network.NodeInfoChecker
 	notifyNewBlockCancel func()
 	newBlockEventch      chan syncBlockEvent
+	snapshot             func() (io.ReadCloser, error)
 }
 
 func NewSyncingState(
@@ -83,6 +85,15 @@ func NewSyncingState(
 	}
 }
 
+// SetSnapshot sets the source of state snapshot. If database is empty, the
+// snapshot is imported before syncing, so only the blocks after snapshot are
+// synced.
+func (st *SyncingState) SetSnapshot(f func() (io.ReadCloser, error)) *SyncingState {
+	st.snapshot = f
+
+	return st
+}
+
 func (st *SyncingState) Enter(sctx StateSwitchContext) (func() error, error) {
 	callback := EmptySwitchFunc
 	if i, err := st.BaseState.Enter(sctx); err != nil {
@@ -172,6 +183,12 @@ func (st *SyncingState) enterCallback(voteproof base.Voteproof) error {
 		return err
 	} else if found {
 		baseManifest = m
+	} else if st.snapshot != nil {
+		m, err := st.importSnapshot()
+		if err != nil {
+			return err
+		}
+		baseManifest = m
 	}
 
 	var syncableChannels func() map[string]network.Channel
@@ -204,6 +221,7 @@ func (st *SyncingState) enterCallback(voteproof base.Voteproof) error {
 			},
 		)
 		_ = st.nc.SetLogging(st.Logging)
+		_ = st.nc.SetPolicyFunc(st.policy.Config)
 		if err := st.nc.Start(); err != nil {
 			return err
 		}
@@ -229,6 +247,34 @@ func (st *SyncingState) enterCallback(voteproof base.Voteproof) error {
 	return nil
 }
 
+func (st *SyncingState) importSnapshot() (block.Manifest, error) {
+	st.Log().Debug().Msg("empty blocks; trying to import snapshot")
+
+	r, err := st.snapshot()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open snapshot")
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	blk, err := isaac.ImportSnapshot(context.Background(), r, st.database, st.blockdata, st.policy, st.suffrage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to import snapshot")
+	}
+
+	st.Log().Debug().Object("block", blk).Msg("snapshot imported")
+
+	_ = st.SetLastVoteproof(blk.ConsensusInfo().INITVoteproof())
+
+	if err := st.NewBlocks([]block.Block{blk}); err != nil {
+		st.Log().Error().Err(err).Msg("new blocks hooks failed")
+	}
+
+	return blk.Manifest(), nil
+}
+
 func (st *SyncingState) syncers() *isaac.Syncers {
 	st.RLock()
 	defer st.RUnlock()
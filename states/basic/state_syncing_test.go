@@ -1,6 +1,9 @@
 package basicstates
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"testing"
 	"time"
 
@@ -8,6 +11,7 @@ import (
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/stretchr/testify/suite"
 )
@@ -244,6 +248,64 @@ func (t *testStateSyncing) TestNoneSuffrage() {
 	t.True(st.nc.IsStarted())
 }
 
+func (t *testStateSyncing) TestImportSnapshot() {
+	_ = t.Encs.TestAddHinter(isaac.SnapshotV0Hinter)
+	_ = t.Encs.TestAddHinter(isaac.SnapshotStateV0Hinter)
+
+	lm := t.LastManifest(t.remote.Database())
+
+	buf := bytes.NewBuffer(nil)
+	_, err := isaac.ExportSnapshot(context.Background(), buf, t.remote.Database(), t.remote.Blockdata(), lm.Height())
+	t.NoError(err)
+
+	t.NoError(blockdata.Clean(t.local.Database(), t.local.Blockdata(), false))
+
+	// NOTE the blocks of BlockV0DummyGenerator have the threshold ratio, 67
+	_, err = t.local.Policy().SetThresholdRatio(base.ThresholdRatio(67))
+	t.NoError(err)
+
+	st, done := t.newState(t.local, t.Suffrage(t.local, t.local, t.remote))
+	defer done()
+
+	timers := localtime.NewTimers([]localtime.TimerID{
+		TimerIDSyncingWaitVoteproof,
+	}, false)
+	st.SetTimers(timers)
+
+	var livp base.Voteproof
+	st.SetLastVoteproofFuncs(func() base.Voteproof {
+		return livp
+	}, func() base.Voteproof {
+		return livp
+	}, func(voteproof base.Voteproof) bool {
+		livp = voteproof
+
+		return true
+	})
+
+	var newBlocks []block.Block
+	st.SetNewBlocksFunc(func(blks []block.Block) error {
+		newBlocks = append(newBlocks, blks...)
+
+		return nil
+	})
+
+	_ = st.SetSnapshot(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+
+	f, err := st.Enter(NewStateSwitchContext(base.StateBooting, base.StateSyncing))
+	t.NoError(err)
+	t.NoError(f())
+
+	t.CompareManifest(lm, t.LastManifest(t.local.Database()))
+
+	t.Equal(1, len(newBlocks))
+	t.True(lm.Hash().Equal(newBlocks[0].Hash()))
+	t.NotNil(livp)
+	t.Equal(lm.Height(), livp.Height())
+}
+
 func (t *testStateSyncing) readyToFinish(local *isaac.Local, suffrage base.Suffrage, others ...*isaac.Local) (*SyncingState, chan bool) {
 	t.SetupNodes(local, others)
 
@@ -18,6 +18,7 @@ import (
 	"github.com/spikeekips/mitum/network/discovery/memberlist"
 	"github.com/spikeekips/mitum/states"
 	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/logging"
@@ -28,9 +29,11 @@ const (
 	ContextValueStateSwitchContext util.ContextKey = "state_switch_context"
 	ContextValueNewSealContext     util.ContextKey = "new_seal"
 	ContextValueBlockSaved         util.ContextKey = "block_saved"
+	ContextValueConsensusStuck     util.ContextKey = "consensus_stuck"
 )
 
 type States struct {
+	byzantineStates
 	sync.RWMutex
 	*logging.Logging
 	*util.ContextDaemon
@@ -54,6 +57,21 @@ type States struct {
 	hd                 *Handover
 	dis                *states.DiscoveryJoiner
 	joinDiscoveryFunc  func(int, chan error) error
+	mempool            *isaac.Mempool
+	conflictsLock      sync.RWMutex
+	conflicts          map[proposalPoint]struct{}
+	blockdata          blockdata.Blockdata
+	stuckPolicy        StuckRecoveryPolicy
+	stuckLock          sync.RWMutex
+	stuckRecords       []network.StuckRecoveryRecord
+	consensusStuckHook *pm.Hooks
+}
+
+// proposalPoint is the height and round, where the proposer signed the
+// conflicting proposals.
+type proposalPoint struct {
+	height base.Height
+	round  base.Round
 }
 
 func NewStates( // revive:disable-line:argument-limit
@@ -87,9 +105,12 @@ func NewStates( // revive:disable-line:argument-limit
 			TimerIDSyncingWaitVoteproof,
 			TimerIDFindProposal,
 		}, false),
-		blockSavedHook: pm.NewHooks("block-saved"),
-		dis:            dis,
-		hd:             hd,
+		blockSavedHook:     pm.NewHooks("block-saved"),
+		dis:                dis,
+		hd:                 hd,
+		conflicts:          map[proposalPoint]struct{}{},
+		stuckPolicy:        NewStuckRecoveryPolicy(),
+		consensusStuckHook: pm.NewHooks("consensus-stuck"),
 	}
 
 	sts := map[base.State]State{
@@ -293,6 +314,20 @@ func (ss *States) BroadcastBallot(blt base.Ballot, toLocal bool) {
 	})
 }
 
+// SetMempool makes the incoming operations to be admitted by Mempool.
+func (ss *States) SetMempool(mp *isaac.Mempool) *States {
+	ss.mempool = mp
+
+	return ss
+}
+
+// SetBlockdata is used to read the voteproofs fetched from the other nodes.
+func (ss *States) SetBlockdata(bd blockdata.Blockdata) *States {
+	ss.blockdata = bd
+
+	return ss
+}
+
 func (ss *States) BlockSavedHook() *pm.Hooks {
 	return ss.blockSavedHook
 }
@@ -315,6 +350,10 @@ func (ss *States) start(ctx context.Context) error {
 		go ss.cleanBallotbox(ctx)
 	}
 
+	if ss.mempool != nil {
+		go ss.evictMempool(ctx)
+	}
+
 	if err := ss.join(); err != nil {
 		return err
 	}
@@ -621,7 +660,17 @@ func (ss *States) newSealProposal(proposal base.Proposal) error {
 	}
 
 	if err := ss.validateProposal(proposal); err != nil {
-		return err
+		var cerr isaac.ConflictingProposalError
+		if !errors.As(err, &cerr) {
+			return err
+		}
+
+		ss.conflictingProposal(cerr.Equivocation())
+
+		// NOTE current state refuses to vote for the proposals of this point
+		go ss.NewProposal(proposal)
+
+		return nil
 	}
 
 	if err := ss.checkBallotVoteproof(proposal); err != nil {
@@ -655,6 +704,10 @@ func (ss *States) newSealBallot(blt base.Ballot) error {
 }
 
 func (ss *States) newOperationSeal(sl operation.Seal) error {
+	if ss.mempool != nil {
+		return ss.newOperationSealByMempool(sl)
+	}
+
 	// NOTE save seal
 	if err := ss.database.NewOperationSeals([]operation.Seal{sl}); err != nil {
 		if !errors.Is(err, util.DuplicatedError) {
@@ -662,6 +715,8 @@ func (ss *States) newOperationSeal(sl operation.Seal) error {
 		}
 	}
 
+	ss.setOperationStatuses(isaac.StagedOperationStatuses(sl.Operations()))
+
 	// NOTE none-suffrage node will broadcast operation seal to suffrage nodes
 	if ss.isNoneSuffrageNode {
 		go ss.broadcastOperationSealToSuffrageNodes(sl)
@@ -670,6 +725,32 @@ func (ss *States) newOperationSeal(sl operation.Seal) error {
 	return nil
 }
 
+func (ss *States) newOperationSealByMempool(sl operation.Seal) error {
+	admitted, err := ss.mempool.AddSeal(sl)
+	if err != nil {
+		return err
+	}
+
+	ss.setOperationStatuses(isaac.StagedOperationStatuses(admitted))
+
+	// NOTE if none of operations is admitted, seal is not broadcasted
+	if len(admitted) > 0 && ss.isNoneSuffrageNode {
+		go ss.broadcastOperationSealToSuffrageNodes(sl)
+	}
+
+	return nil
+}
+
+func (ss *States) setOperationStatuses(statuses []operation.StatusV0) {
+	if len(statuses) < 1 {
+		return
+	}
+
+	if err := ss.database.SetOperationStatuses(statuses); err != nil {
+		ss.Log().Error().Err(err).Msg("failed to set operation statuses")
+	}
+}
+
 func (ss *States) validateProposal(proposal base.Proposal) error {
 	pvc, err := isaac.NewProposalValidationChecker(
 		ss.database, ss.suffrage, ss.nodepool,
@@ -692,6 +773,7 @@ func (ss *States) validateProposal(proposal base.Proposal) error {
 		fns = []util.CheckerFunc{
 			pvc.IsKnown,
 			pvc.CheckSigning,
+			pvc.IsConflicting,
 			pvc.SaveProposal,
 			pvc.IsOlder,
 		}
@@ -725,7 +807,16 @@ func (ss *States) voteBallot(blt base.Ballot) (base.Voteproof, error) {
 	}
 
 	voteproof, err := ss.ballotbox.Vote(blt)
-	if err != nil {
+	switch {
+	case err == nil:
+	case errors.Is(err, util.NotFoundError):
+		// NOTE the suffrage weights of ballot height are not yet known, like
+		// the ballot of higher height than local; the voteproof of ballot can
+		// still move to syncing.
+		ss.Log().Debug().Err(err).Stringer("ballot", blt.Hash()).Msg("ballot can not be voted yet")
+
+		return nil, nil
+	default:
 		return nil, errors.Wrap(err, "failed to vote")
 	}
 
@@ -801,14 +892,11 @@ func (ss *States) checkBallotVoteproof(blt base.Ballot) error {
 }
 
 func (ss *States) cleanBallotbox(ctx context.Context) {
-	ticker := time.NewTicker(time.Second * 10)
-	defer ticker.Stop()
-
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-localtime.After(time.Second * 10):
 			var height base.Height
 			if vp := ss.LastVoteproof(); vp == nil {
 				continue
@@ -821,24 +909,71 @@ func (ss *States) cleanBallotbox(ctx context.Context) {
 			if err := ss.ballotbox.Clean(height); err != nil {
 				ss.Log().Error().Err(err).Msg("something wrong to clean Ballotbox")
 			}
+
+			ss.cleanConflictingProposals(height)
 		}
 	}
 }
 
-func (ss *States) detectStuck(ctx context.Context) {
-	ss.Log().Debug().Msg("detecting whether consensus is stuck")
+func (ss *States) evictMempool(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-localtime.After(isaac.DefaultMempoolEvictInterval):
+			if err := ss.mempool.Evict(); err != nil {
+				ss.Log().Error().Err(err).Msg("something wrong to evict expired operations of mempool")
+			}
+		}
+	}
+}
+
+// conflictingProposal records the point of conflicting proposals and reports
+// the evidence; the proposals of this point are not voted.
+func (ss *States) conflictingProposal(ev base.EquivocationV0) {
+	ss.conflictsLock.Lock()
+	ss.conflicts[proposalPoint{height: ev.Height(), round: ev.Round()}] = struct{}{}
+	ss.conflictsLock.Unlock()
 
-	endure := time.Minute
+	if ss.ballotbox != nil {
+		ss.ballotbox.Equivocated(ev)
+	}
+}
 
-	ticker := time.NewTicker(time.Second * 3)
-	defer ticker.Stop()
+func (ss *States) isConflictingProposal(height base.Height, round base.Round) bool {
+	ss.conflictsLock.RLock()
+	defer ss.conflictsLock.RUnlock()
+
+	_, found := ss.conflicts[proposalPoint{height: height, round: round}]
+
+	return found
+}
+
+func (ss *States) cleanConflictingProposals(height base.Height) {
+	ss.conflictsLock.Lock()
+	defer ss.conflictsLock.Unlock()
+
+	for p := range ss.conflicts {
+		if p.height <= height {
+			delete(ss.conflicts, p)
+		}
+	}
+}
+
+func (ss *States) detectStuck(ctx context.Context) {
+	policy := ss.stuckPolicy
+	endure := policy.Endure
+
+	ss.Log().Debug().Dur("endure", endure).Interface("actions", policy.Actions).
+		Msg("detecting whether consensus is stuck")
 
 	var stucked bool
+	var recovered time.Time
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-localtime.After(time.Second * 3):
 			lvp := ss.LastVoteproof()
 			state := ss.State()
 
@@ -862,11 +997,29 @@ func (ss *States) detectStuck(ctx context.Context) {
 			if changed {
 				l := ss.Log().With().Stringer("state", state).Dur("endure", endure).Object("last_voteproof", lvp).Logger()
 				if stucked {
+					metricStuck.WithLabelValues().Set(1)
+
 					l.Error().Err(err).Msg("consensus stuck")
 				} else {
+					metricStuck.WithLabelValues().Set(0)
+					recovered = time.Time{}
+
 					l.Info().Msg("consensus released from stuck")
 				}
 			}
+
+			// NOTE recovery is tried only in consensus state
+			if !stucked || err == nil || errors.Is(err, util.IgnoreError) {
+				continue
+			}
+
+			if len(policy.Actions) < 1 || localtime.UTCNow().Sub(recovered) < policy.Interval {
+				continue
+			}
+
+			recovered = localtime.UTCNow()
+
+			ss.recoverStuck(ctx, lvp)
 		}
 	}
 }
@@ -882,7 +1035,7 @@ func (ss *States) broadcastOperationSealToSuffrageNodes(sl operation.Seal) {
 	})
 }
 
-func (ss *States) broadcast(
+func (ss *States) sendSeal(
 	sl seal.Seal,
 	toLocal bool,
 	filter func(base.Node) bool,
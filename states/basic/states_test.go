@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/ballot"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/isaac"
@@ -563,6 +564,45 @@ func (t *testStates) TestNewBallotNoneSuffrage() {
 	t.Nil(lib)
 }
 
+func (t *testStates) TestNewSealConflictingProposal() {
+	ss := t.newStates()
+
+	evch := make(chan base.EquivocationV0, 1)
+	_ = ss.ballotbox.SetEquivocationFunc(func(ev base.EquivocationV0) {
+		evch <- ev
+	})
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	vp, err := t.NewVoteproof(base.StageINIT, ib.Fact(), t.local, t.remote)
+	t.NoError(err)
+
+	pr := t.NewProposal(t.remote, vp.Round(), nil, vp)
+	npr := t.NewProposal(t.remote, vp.Round(), []valuehash.Hash{valuehash.RandomSHA256()}, vp)
+
+	t.NoError(ss.NewSeal(pr))
+	t.False(ss.isConflictingProposal(vp.Height(), vp.Round()))
+
+	t.NoError(ss.NewSeal(npr))
+	t.True(ss.isConflictingProposal(vp.Height(), vp.Round()))
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.NoError(errors.Errorf("timeout to wait equivocation"))
+	case ev := <-evch:
+		t.Equal(base.StageProposal, ev.Stage())
+		t.True(pr.Fact().Hash().Equal(ev.First().Fact().Hash()))
+		t.True(npr.Fact().Hash().Equal(ev.Second().Fact().Hash()))
+	}
+
+	// NOTE the conflicting proposal is not stored
+	_, found, err := t.local.Database().Proposal(npr.Fact().Hash())
+	t.NoError(err)
+	t.False(found)
+
+	ss.cleanConflictingProposals(vp.Height())
+	t.False(ss.isConflictingProposal(vp.Height(), vp.Round()))
+}
+
 func (t *testStates) TestNewOperationSealNoneSuffrage() {
 	remotech := channetwork.NewChannel(0, t.remote.Channel().ConnInfo())
 	_ = t.remote.SetChannel(remotech)
@@ -1143,6 +1183,74 @@ func (t *testStates) TestFailedProcessingVoteproof() {
 	t.Nil(ss.ballotbox.LatestBallot())
 }
 
+// TestWeightedBallotOfHigherHeight tests, the node, which is behind 2 blocks,
+// moves to syncing by the ballot of higher height, even if the suffrage
+// weights of ballot height are not yet known.
+func (t *testStates) TestWeightedBallotOfHigherHeight() {
+	ss := t.newStates()
+	defer func() {
+		_ = ss.Stop()
+	}()
+
+	last := t.LastManifest(t.local.Database()).Height()
+
+	sw := base.NewEqualSuffrageWeights(ss.suffrage.Nodes())
+	_ = ss.ballotbox.SetWeightsFunc(func(height base.Height) (base.SuffrageWeights, error) {
+		if height-1 > last {
+			return base.SuffrageWeights{}, util.NotFoundError.Errorf("suffrage weights of height, %d not found", height)
+		}
+
+		return sw, nil
+	})
+
+	statech := make(chan StateSwitchContext, 1)
+	stateConsensus := NewBaseState(base.StateConsensus)
+	stateConsensus.SetEnterFunc(func(sctx StateSwitchContext) (func() error, error) {
+		statech <- sctx
+		return nil, nil
+	})
+
+	syncingch := make(chan StateSwitchContext, 1)
+	stateSyncing := NewBaseState(base.StateSyncing)
+	stateSyncing.SetEnterFunc(func(sctx StateSwitchContext) (func() error, error) {
+		syncingch <- sctx
+		return nil, nil
+	})
+
+	ss.states[base.StateConsensus] = stateConsensus
+	ss.states[base.StateSyncing] = stateSyncing
+
+	go ss.Start()
+
+	t.NoError(ss.SwitchState(NewStateSwitchContext(ss.State(), base.StateConsensus)))
+
+	<-statech
+
+	height := last + 3
+
+	ivp, err := t.NewVoteproof(base.StageINIT, ballot.NewINITFact(height, base.Round(0), valuehash.RandomSHA256()), t.local, t.remote)
+	t.NoError(err)
+
+	ab, err := ballot.NewACCEPT(
+		ballot.NewACCEPTFact(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256()),
+		t.remote.Node().Address(),
+		ivp,
+		t.remote.Node().Privatekey(), t.remote.Policy().NetworkID(),
+	)
+	t.NoError(err)
+
+	t.NoError(ss.NewSeal(ab))
+
+	select {
+	case <-time.After(time.Second * 3):
+		t.NoError(errors.Errorf("timeout to wait syncing state"))
+	case sctx := <-syncingch:
+		t.Equal(base.StateConsensus, sctx.FromState())
+		t.NotNil(sctx.Voteproof())
+		t.Equal(height, sctx.Voteproof().Height())
+	}
+}
+
 func TestStates(t *testing.T) {
 	suite.Run(t, new(testStates))
 }
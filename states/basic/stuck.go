@@ -0,0 +1,410 @@
+package basicstates
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+type StuckRecoveryAction string
+
+const (
+	// StuckRecoveryRebroadcastINIT broadcasts the INIT ballot of the last
+	// voteproof again.
+	StuckRecoveryRebroadcastINIT StuckRecoveryAction = "rebroadcast-init"
+	// StuckRecoveryFetchVoteproofs fetches the last voteproofs from the other
+	// nodes and processes the newest one, which is checked by suffrage and
+	// agreed by the multiple nodes.
+	StuckRecoveryFetchVoteproofs StuckRecoveryAction = "fetch-voteproofs"
+	// StuckRecoverySyncing moves to syncing state when the other nodes have
+	// the higher block.
+	StuckRecoverySyncing StuckRecoveryAction = "syncing"
+	// StuckRecoveryAlert runs the consensus stuck hook.
+	StuckRecoveryAlert StuckRecoveryAction = "alert"
+)
+
+const (
+	StuckRecoveryResultDone    = "done"
+	StuckRecoveryResultSkipped = "skipped"
+	StuckRecoveryResultFailed  = "failed"
+)
+
+var (
+	DefaultStuckRecoveryEndure   = time.Minute
+	DefaultStuckRecoveryInterval = time.Second * 30
+	DefaultStuckRecoveryActions  = []StuckRecoveryAction{
+		StuckRecoveryRebroadcastINIT,
+		StuckRecoveryFetchVoteproofs,
+		StuckRecoverySyncing,
+		StuckRecoveryAlert,
+	}
+)
+
+var (
+	maxStuckRecoveryRecords = 100
+	// NOTE the fetched voteproof should be agreed by at least
+	// minStuckVoteproofAgreement nodes.
+	minStuckVoteproofAgreement = 2
+)
+
+func (sa StuckRecoveryAction) IsValid([]byte) error {
+	switch sa {
+	case StuckRecoveryRebroadcastINIT,
+		StuckRecoveryFetchVoteproofs,
+		StuckRecoverySyncing,
+		StuckRecoveryAlert:
+		return nil
+	default:
+		return isvalid.InvalidError.Errorf("unknown StuckRecoveryAction, %q", sa)
+	}
+}
+
+// StuckRecoveryPolicy decides what States does when consensus looks stuck.
+// Consensus is regarded as stuck when the last voteproof is older than
+// Endure; the Actions are tried in order at every Interval until it is
+// released. Empty Actions only reports the stuck.
+type StuckRecoveryPolicy struct {
+	Endure   time.Duration
+	Interval time.Duration
+	Actions  []StuckRecoveryAction
+}
+
+func NewStuckRecoveryPolicy(actions ...StuckRecoveryAction) StuckRecoveryPolicy {
+	return StuckRecoveryPolicy{
+		Endure:   DefaultStuckRecoveryEndure,
+		Interval: DefaultStuckRecoveryInterval,
+		Actions:  actions,
+	}
+}
+
+func (sp StuckRecoveryPolicy) IsValid([]byte) error {
+	switch {
+	case sp.Endure <= 0:
+		return isvalid.InvalidError.Errorf("empty endure")
+	case sp.Interval <= 0:
+		return isvalid.InvalidError.Errorf("empty interval")
+	}
+
+	found := map[StuckRecoveryAction]struct{}{}
+	for i := range sp.Actions {
+		a := sp.Actions[i]
+		if err := a.IsValid(nil); err != nil {
+			return err
+		}
+
+		if _, ok := found[a]; ok {
+			return isvalid.InvalidError.Errorf("duplicated action, %q", a)
+		}
+
+		found[a] = struct{}{}
+	}
+
+	return nil
+}
+
+// stuckRecovery keeps the voteproof fetched from the other nodes during one
+// recovery, so the actions do not fetch it again.
+type stuckRecovery struct {
+	lvp     base.Voteproof
+	fetched bool
+	vp      base.Voteproof
+}
+
+// SetStuckRecoveryPolicy sets the policy for the stuck consensus; it should be
+// called before States starts.
+func (ss *States) SetStuckRecoveryPolicy(policy StuckRecoveryPolicy) error {
+	if err := policy.IsValid(nil); err != nil {
+		return err
+	}
+
+	ss.stuckPolicy = policy
+
+	return nil
+}
+
+// StuckRecoveryRecords returns the recent recovery actions tried by the local
+// node, the oldest first.
+func (ss *States) StuckRecoveryRecords() []network.StuckRecoveryRecord {
+	ss.stuckLock.RLock()
+	defer ss.stuckLock.RUnlock()
+
+	records := make([]network.StuckRecoveryRecord, len(ss.stuckRecords))
+	copy(records, ss.stuckRecords)
+
+	return records
+}
+
+// ConsensusStuckHook is run by the alert action of StuckRecoveryPolicy; the
+// last voteproof is in context by ContextValueConsensusStuck.
+func (ss *States) ConsensusStuckHook() *pm.Hooks {
+	return ss.consensusStuckHook
+}
+
+func (ss *States) recoverStuck(ctx context.Context, lvp base.Voteproof) {
+	sr := &stuckRecovery{lvp: lvp}
+
+	for i := range ss.stuckPolicy.Actions {
+		action := ss.stuckPolicy.Actions[i]
+
+		var message string
+		var err error
+		switch action {
+		case StuckRecoveryRebroadcastINIT:
+			message, err = ss.rebroadcastINITBallot(lvp)
+		case StuckRecoveryFetchVoteproofs:
+			message, err = ss.recoverByVoteproof(ctx, sr)
+		case StuckRecoverySyncing:
+			message, err = ss.recoverBySyncing(ctx, sr)
+		case StuckRecoveryAlert:
+			message, err = ss.alertStuck(lvp)
+		}
+
+		ss.recordStuckRecovery(action, lvp, message, err)
+	}
+}
+
+func (ss *States) rebroadcastINITBallot(lvp base.Voteproof) (string, error) {
+	if state := ss.State(); state != base.StateConsensus {
+		return "", util.IgnoreError.Errorf("not consensus state, %s", state)
+	} else if lvp == nil {
+		return "", util.IgnoreError.Errorf("empty last voteproof")
+	}
+
+	local := ss.nodepool.LocalNode()
+
+	var blt base.INITBallot
+	var err error
+	switch lvp.Stage() {
+	case base.StageACCEPT:
+		blt, err = NextINITBallotFromACCEPTVoteproof(ss.database, local, lvp, ss.policy.NetworkID())
+	case base.StageINIT:
+		blt, err = NextINITBallotFromINITVoteproof(ss.database, local, lvp, nil, ss.policy.NetworkID())
+	default:
+		return "", errors.Errorf("unknown stage of last voteproof, %s", lvp.Stage())
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	ss.BroadcastBallot(blt, false)
+
+	return fmt.Sprintf("init ballot of height=%d round=%d", blt.Fact().Height(), blt.Fact().Round()), nil
+}
+
+func (ss *States) recoverByVoteproof(ctx context.Context, sr *stuckRecovery) (string, error) {
+	vp, err := ss.fetchedVoteproof(ctx, sr)
+	if err != nil {
+		return "", err
+	}
+
+	if sr.lvp != nil && base.CompareVoteproof(vp, sr.lvp) < 1 {
+		return "", util.IgnoreError.Errorf("fetched voteproof, %q is not newer than last", vp.ID())
+	}
+
+	ss.NewVoteproof(vp)
+
+	return fmt.Sprintf("voteproof, %q processed", vp.ID()), nil
+}
+
+func (ss *States) recoverBySyncing(ctx context.Context, sr *stuckRecovery) (string, error) {
+	if state := ss.State(); state != base.StateConsensus {
+		return "", util.IgnoreError.Errorf("not consensus state, %s", state)
+	}
+
+	vp, err := ss.fetchedVoteproof(ctx, sr)
+	if err != nil {
+		return "", err
+	}
+
+	m, found, err := ss.database.LastManifest()
+	switch {
+	case err != nil:
+		return "", err
+	case !found:
+		return "", errors.Errorf("last manifest not found")
+	case vp.Height() <= m.Height():
+		return "", util.IgnoreError.Errorf("nodes are not ahead; last block=%d, nodes=%d", m.Height(), vp.Height())
+	}
+
+	if err := ss.SwitchState(NewStateSwitchContext(base.StateConsensus, base.StateSyncing).SetVoteproof(vp)); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("syncing from %d to %d", m.Height(), vp.Height()), nil
+}
+
+func (ss *States) alertStuck(lvp base.Voteproof) (string, error) {
+	ctx := context.WithValue(context.Background(), ContextValueConsensusStuck, lvp)
+	if err := ss.consensusStuckHook.Run(ctx); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+func (ss *States) recordStuckRecovery(action StuckRecoveryAction, lvp base.Voteproof, message string, err error) {
+	r := network.StuckRecoveryRecord{
+		Action:  string(action),
+		Height:  base.NilHeight,
+		Result:  StuckRecoveryResultDone,
+		Message: message,
+		At:      localtime.UTCNow(),
+	}
+
+	if lvp != nil {
+		r.Height, r.Round = lvp.Height(), lvp.Round()
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, util.IgnoreError):
+		r.Result, r.Message = StuckRecoveryResultSkipped, err.Error()
+	default:
+		r.Result, r.Message = StuckRecoveryResultFailed, err.Error()
+	}
+
+	ss.stuckLock.Lock()
+	ss.stuckRecords = append(ss.stuckRecords, r)
+	if i := len(ss.stuckRecords) - maxStuckRecoveryRecords; i > 0 {
+		ss.stuckRecords = ss.stuckRecords[i:]
+	}
+	ss.stuckLock.Unlock()
+
+	metricStuckRecovery.WithLabelValues(string(action), r.Result).Inc()
+
+	l := ss.Log().With().Str("action", string(action)).Str("result", r.Result).
+		Str("message", r.Message).Object("last_voteproof", lvp).Logger()
+
+	switch r.Result {
+	case StuckRecoveryResultFailed:
+		l.Error().Msg("failed to recover stuck consensus")
+	case StuckRecoveryResultSkipped:
+		l.Debug().Msg("recovery of stuck consensus skipped")
+	default:
+		if action == StuckRecoveryAlert {
+			l.Error().Msg("consensus stuck alert")
+		} else {
+			l.Info().Msg("tried to recover stuck consensus")
+		}
+	}
+}
+
+// fetchedVoteproof returns the newest ACCEPT voteproof of the last blocks of
+// the other nodes. The voteproof should be agreed by more than one node.
+func (ss *States) fetchedVoteproof(ctx context.Context, sr *stuckRecovery) (base.Voteproof, error) {
+	if !sr.fetched {
+		sr.fetched = true
+
+		vps, err := ss.fetchLastVoteproofs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		sr.vp = agreedVoteproof(vps, minStuckVoteproofAgreement)
+	}
+
+	if sr.vp == nil {
+		return nil, util.IgnoreError.Errorf("no voteproof agreed")
+	}
+
+	return sr.vp, nil
+}
+
+func (ss *States) fetchLastVoteproofs(ctx context.Context) ([]base.Voteproof, error) {
+	if ss.blockdata == nil {
+		return nil, errors.Errorf("empty blockdata")
+	}
+
+	var vps []base.Voteproof
+	ss.nodepool.TraverseAliveRemotes(func(no base.Node, ch network.Channel) bool {
+		switch vp, err := ss.fetchLastVoteproof(ctx, ch); {
+		case err != nil:
+			ss.Log().Debug().Err(err).Stringer("node", no.Address()).Msg("failed to fetch last voteproof")
+		case vp != nil:
+			vps = append(vps, vp)
+		}
+
+		return true
+	})
+
+	return vps, nil
+}
+
+func (ss *States) fetchLastVoteproof(ctx context.Context, ch network.Channel) (base.Voteproof, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	ni, err := ch.NodeInfo(ctx)
+	switch {
+	case err != nil:
+		return nil, err
+	case ni == nil || ni.LastBlock() == nil:
+		return nil, nil
+	}
+
+	height := ni.LastBlock().Height()
+
+	bdm, err := network.FetchBlockdataMap(ctx, ch, height, ss.policy.NetworkID())
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := network.FetchBlockdata(ctx, ch, bdm.ACCEPTVoteproof())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	vp, err := ss.blockdata.Writer().ReadACCEPTVoteproof(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := isaac.NewVoteProofChecker(vp, ss.policy, ss.suffrage).Check(); err != nil {
+		return nil, err
+	}
+
+	switch fact, ok := vp.Majority().(base.ACCEPTBallotFact); {
+	case !ok:
+		return nil, errors.Errorf("accept voteproof has not accept ballot fact, %T", vp.Majority())
+	case !fact.NewBlock().Equal(bdm.Block()):
+		return nil, errors.Errorf("accept voteproof has different block, %s != %s", fact.NewBlock(), bdm.Block())
+	}
+
+	return vp, nil
+}
+
+// agreedVoteproof returns the newest voteproof, which has the same majority
+// with the voteproofs of at least n nodes.
+func agreedVoteproof(vps []base.Voteproof, n int) base.Voteproof {
+	agreed := map[string]int{}
+	for i := range vps {
+		agreed[vps[i].Majority().Hash().String()]++
+	}
+
+	var vp base.Voteproof
+	for i := range vps {
+		if agreed[vps[i].Majority().Hash().String()] < n {
+			continue
+		}
+
+		if vp == nil || base.CompareVoteproof(vps[i], vp) > 0 {
+			vp = vps[i]
+		}
+	}
+
+	return vp
+}
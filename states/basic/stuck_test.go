@@ -0,0 +1,381 @@
+package basicstates
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/network"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testStuckRecovery struct {
+	baseTestState
+	other *isaac.Local
+}
+
+func (t *testStuckRecovery) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	ls := t.Locals(3)
+	t.local, t.remote, t.other = ls[0], ls[1], ls[2]
+
+	_, err := t.local.Policy().SetThresholdRatio(67)
+	t.NoError(err)
+}
+
+func (t *testStuckRecovery) newStates(actions ...StuckRecoveryAction) (*States, *channetwork.Channel) {
+	return t.newStatesWithSuffrage(t.Suffrage(t.local, t.local, t.remote, t.other), actions...)
+}
+
+func (t *testStuckRecovery) newStatesWithSuffrage(
+	suffrage base.Suffrage,
+	actions ...StuckRecoveryAction,
+) (*States, *channetwork.Channel) {
+	remotech := t.remote.Channel().(*channetwork.Channel)
+
+	ss, err := NewStates(
+		t.local.Database(),
+		t.local.Policy(),
+		t.local.Nodes(),
+		suffrage,
+		t.Ballotbox(suffrage, t.local.Policy()),
+		NewBaseState(base.StateStopped),
+		NewBaseState(base.StateBooting),
+		NewBaseState(base.StateJoining),
+		NewBaseState(base.StateConsensus),
+		NewBaseState(base.StateSyncing),
+		NewBaseState(base.StateHandover),
+		nil,
+		nil,
+	)
+	t.NoError(err)
+	t.NoError(ss.SetStuckRecoveryPolicy(NewStuckRecoveryPolicy(actions...)))
+
+	_ = ss.SetBlockdata(t.local.Blockdata())
+	ss.setState(base.StateConsensus)
+
+	return ss, remotech
+}
+
+// remoteAhead makes remote and other to have the higher blocks than local
+// and to serve their last block.
+func (t *testStuckRecovery) remoteAhead(n base.Height) block.Manifest {
+	m := t.LastManifest(t.remote.Database())
+	if n > 0 {
+		t.GenerateBlocks([]*isaac.Local{t.remote, t.other}, m.Height()+n)
+		m = t.LastManifest(t.remote.Database())
+	}
+
+	t.serveLastBlock(t.remote, m)
+	t.serveLastBlock(t.other, m)
+
+	return m
+}
+
+func (t *testStuckRecovery) serveLastBlock(l *isaac.Local, m block.Manifest) {
+	ch := l.Channel().(*channetwork.Channel)
+	ch.SetNodeInfoHandler(func() (network.NodeInfo, error) {
+		return network.NewNodeInfoV0(
+			l.Node(),
+			l.Policy().NetworkID(),
+			base.StateConsensus,
+			m,
+			util.Version("0.1.1"),
+			map[string]interface{}{},
+			nil,
+			nil,
+			ch.ConnInfo(),
+		), nil
+	})
+	ch.SetBlockdataMapsHandler(func(heights []base.Height) ([]block.BlockdataMap, error) {
+		var bds []block.BlockdataMap
+		for _, h := range heights {
+			bd, found, err := l.Database().BlockdataMap(h)
+			if err != nil {
+				return nil, err
+			} else if !found {
+				break
+			}
+
+			bds = append(bds, bd)
+		}
+
+		return bds, nil
+	})
+	ch.SetBlockdataHandler(func(p string) (io.Reader, func() error, error) {
+		i, err := l.Blockdata().FS().Open(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return i, i.Close, nil
+	})
+}
+
+func (t *testStuckRecovery) results(ss *States) []string {
+	records := ss.StuckRecoveryRecords()
+
+	results := make([]string, len(records))
+	for i := range records {
+		results[i] = records[i].Action + ":" + records[i].Result
+	}
+
+	return results
+}
+
+func (t *testStuckRecovery) TestPolicyIsValid() {
+	t.NoError(NewStuckRecoveryPolicy().IsValid(nil))
+	t.NoError(NewStuckRecoveryPolicy(DefaultStuckRecoveryActions...).IsValid(nil))
+	t.Error(NewStuckRecoveryPolicy(StuckRecoveryAction("findme")).IsValid(nil))
+	t.Error(NewStuckRecoveryPolicy(StuckRecoveryAlert, StuckRecoveryAlert).IsValid(nil))
+
+	p := NewStuckRecoveryPolicy()
+	p.Interval = 0
+	t.Error(p.IsValid(nil))
+
+	ss, _ := t.newStates()
+	t.Error(ss.SetStuckRecoveryPolicy(p))
+}
+
+func (t *testStuckRecovery) TestRebroadcastINIT() {
+	ss, remotech := t.newStates(StuckRecoveryRebroadcastINIT)
+
+	lvp := ss.LastVoteproof()
+	t.Equal(base.StageACCEPT, lvp.Stage())
+
+	ss.recoverStuck(context.Background(), lvp)
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.NoError(util.NotFoundError.Errorf("init ballot not broadcasted"))
+	case sl := <-remotech.ReceiveSeal():
+		ib, ok := sl.Seal.(base.INITBallot)
+		t.True(ok)
+
+		t.True(t.local.Node().Address().Equal(ib.FactSign().Node()))
+		t.Equal(lvp.Height()+1, ib.Fact().Height())
+		t.Equal(base.Round(0), ib.Fact().Round())
+	}
+
+	t.Equal([]string{"rebroadcast-init:done"}, t.results(ss))
+}
+
+func (t *testStuckRecovery) TestNotConsensusState() {
+	ss, _ := t.newStates(StuckRecoveryRebroadcastINIT, StuckRecoverySyncing)
+	ss.setState(base.StateSyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"rebroadcast-init:skipped", "syncing:skipped"}, t.results(ss))
+}
+
+func (t *testStuckRecovery) TestFetchVoteproofsAndSyncing() {
+	m := t.remoteAhead(2)
+
+	ss, _ := t.newStates(StuckRecoveryFetchVoteproofs, StuckRecoverySyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"fetch-voteproofs:done", "syncing:done"}, t.results(ss))
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.NoError(util.NotFoundError.Errorf("voteproof not processed"))
+	case vp := <-ss.voteproofch:
+		t.Equal(m.Height(), vp.Height())
+		t.Equal(base.StageACCEPT, vp.Stage())
+	}
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.NoError(util.NotFoundError.Errorf("not switched to syncing"))
+	case sctx := <-ss.statech:
+		t.Equal(base.StateConsensus, sctx.FromState())
+		t.Equal(base.StateSyncing, sctx.ToState())
+		t.Equal(m.Height(), sctx.Voteproof().Height())
+	}
+}
+
+func (t *testStuckRecovery) TestFetchVoteproofsWeightedSuffrage() {
+	last := t.LastManifest(t.local.Database()).Height()
+	m := t.remoteAhead(2)
+
+	suf := t.Suffrage(t.local, t.local, t.remote, t.other)
+	sw := base.NewEqualSuffrageWeights(suf.Nodes())
+
+	// NOTE the weights of the heights higher than the next height of local are
+	// not yet known
+	ss, _ := t.newStatesWithSuffrage(testStuckWeightedSuffrage{
+		Suffrage: suf,
+		weights: func(height base.Height) (base.SuffrageWeights, error) {
+			if height-1 > last {
+				return base.SuffrageWeights{}, util.NotFoundError.Errorf("suffrage weights of height, %d not found", height)
+			}
+
+			return sw, nil
+		},
+	}, StuckRecoveryFetchVoteproofs, StuckRecoverySyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"fetch-voteproofs:done", "syncing:done"}, t.results(ss))
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.NoError(util.NotFoundError.Errorf("not switched to syncing"))
+	case sctx := <-ss.statech:
+		t.Equal(base.StateSyncing, sctx.ToState())
+		t.Equal(m.Height(), sctx.Voteproof().Height())
+	}
+
+	// NOTE the known weights are checked; the voteproofs without weights are
+	// not accepted.
+	ss, _ = t.newStatesWithSuffrage(testStuckWeightedSuffrage{
+		Suffrage: suf,
+		weights: func(base.Height) (base.SuffrageWeights, error) {
+			return sw, nil
+		},
+	}, StuckRecoveryFetchVoteproofs, StuckRecoverySyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"fetch-voteproofs:skipped", "syncing:skipped"}, t.results(ss))
+}
+
+func (t *testStuckRecovery) TestFetchVoteproofsNotAgreed() {
+	m := t.LastManifest(t.remote.Database())
+	t.GenerateBlocks([]*isaac.Local{t.remote, t.other}, m.Height()+2)
+
+	// NOTE only remote serves it's last block
+	t.serveLastBlock(t.remote, t.LastManifest(t.remote.Database()))
+
+	ss, _ := t.newStates(StuckRecoveryFetchVoteproofs, StuckRecoverySyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"fetch-voteproofs:skipped", "syncing:skipped"}, t.results(ss))
+}
+
+func (t *testStuckRecovery) TestFetchVoteproofsInvalid() {
+	_ = t.remoteAhead(2)
+
+	// NOTE the threshold of voteproofs is different with local policy
+	_, err := t.local.Policy().SetThresholdRatio(100)
+	t.NoError(err)
+
+	ss, _ := t.newStates(StuckRecoveryFetchVoteproofs, StuckRecoverySyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"fetch-voteproofs:skipped", "syncing:skipped"}, t.results(ss))
+}
+
+func (t *testStuckRecovery) TestNodesNotAhead() {
+	_ = t.remoteAhead(0)
+
+	ss, _ := t.newStates(StuckRecoveryFetchVoteproofs, StuckRecoverySyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"fetch-voteproofs:skipped", "syncing:skipped"}, t.results(ss))
+}
+
+func (t *testStuckRecovery) TestFetchFailed() {
+	// NOTE remote does not serve block data
+	ss, _ := t.newStates(StuckRecoverySyncing)
+
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"syncing:skipped"}, t.results(ss))
+
+	_ = ss.SetBlockdata(nil)
+	ss.recoverStuck(context.Background(), ss.LastVoteproof())
+
+	t.Equal([]string{"syncing:skipped", "syncing:failed"}, t.results(ss))
+}
+
+func (t *testStuckRecovery) TestAlert() {
+	ss, _ := t.newStates(StuckRecoveryAlert)
+
+	var alerted base.Voteproof
+	t.NoError(ss.ConsensusStuckHook().Add("showme", func(ctx context.Context) (context.Context, error) {
+		var vp base.Voteproof
+		if err := util.LoadFromContextValue(ctx, ContextValueConsensusStuck, &vp); err != nil {
+			return ctx, err
+		}
+
+		alerted = vp
+
+		return ctx, nil
+	}, false))
+
+	lvp := ss.LastVoteproof()
+	ss.recoverStuck(context.Background(), lvp)
+
+	t.Equal([]string{"alert:done"}, t.results(ss))
+	t.NotNil(alerted)
+	t.Equal(lvp.ID(), alerted.ID())
+}
+
+func (t *testStuckRecovery) TestRecordsLimit() {
+	ss, _ := t.newStates(StuckRecoveryAlert)
+
+	lvp := ss.LastVoteproof()
+	for i := 0; i < maxStuckRecoveryRecords+3; i++ {
+		ss.recoverStuck(context.Background(), lvp)
+	}
+
+	records := ss.StuckRecoveryRecords()
+	t.Equal(maxStuckRecoveryRecords, len(records))
+	t.Equal(lvp.Height(), records[0].Height)
+}
+
+func (t *testStuckRecovery) TestDetectStuck() {
+	ss, _ := t.newStates(StuckRecoveryAlert)
+	ss.stuckPolicy.Endure = time.Millisecond
+	ss.stuckPolicy.Interval = time.Hour
+
+	alerted := make(chan struct{}, 3)
+	t.NoError(ss.ConsensusStuckHook().Add("showme", func(ctx context.Context) (context.Context, error) {
+		alerted <- struct{}{}
+
+		return ctx, nil
+	}, false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go ss.detectStuck(ctx)
+
+	select {
+	case <-time.After(time.Second * 5):
+		t.NoError(util.NotFoundError.Errorf("stuck not detected"))
+
+		return
+	case <-alerted:
+	}
+
+	// NOTE next recovery waits interval
+	<-time.After(time.Second * 4)
+	t.Equal([]string{"alert:done"}, t.results(ss))
+}
+
+type testStuckWeightedSuffrage struct {
+	base.Suffrage
+	weights func(base.Height) (base.SuffrageWeights, error)
+}
+
+func (sf testStuckWeightedSuffrage) Weights(height base.Height) (base.SuffrageWeights, error) {
+	return sf.weights(height)
+}
+
+func TestStuckRecovery(t *testing.T) {
+	suite.Run(t, new(testStuckRecovery))
+}
@@ -58,6 +58,10 @@ func (st *BaseState) SetExitFunc(fn func(StateSwitchContext) (func() error, erro
 	st.exitFunc = fn
 }
 
+func (st *BaseState) SetConflictingProposalFunc(fn func(base.Height, base.Round) bool) {
+	st.conflictingFunc = fn
+}
+
 type baseTestState struct {
 	sync.Mutex
 	isaac.BaseTest
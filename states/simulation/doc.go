@@ -0,0 +1,7 @@
+/*
+Package simulation runs the multiple consensus nodes in one process under the
+virtual clock. The seals between nodes are delayed, dropped, reordered and
+partitioned by the seeded network, and the simulation checks safety and
+liveness of consensus.
+*/
+package simulation
@@ -0,0 +1,282 @@
+//go:build test
+// +build test
+
+package simulation
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/network"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// LinkConfig decides how the seals are delivered between nodes. The delay is
+// chosen between MinDelay and MaxDelay; with ReorderRate, the seal is held
+// for ReorderDelay more, so the later seals can arrive before it.
+type LinkConfig struct {
+	MinDelay     time.Duration
+	MaxDelay     time.Duration
+	DropRate     float64
+	ReorderRate  float64
+	ReorderDelay time.Duration
+}
+
+// NetworkStats counts the seals sent through Network.
+type NetworkStats struct {
+	Sent      uint64
+	Delivered uint64
+	Dropped   uint64
+	Blocked   uint64
+}
+
+type link struct {
+	sync.Mutex
+	seed  int64
+	sends map[string]uint64
+}
+
+// Network delivers seals between nodes by the virtual clock. The decision for
+// a seal is derived from the seed, the link, the seal hash and how many times
+// the seal was sent through the link, so the same seed makes the same
+// decisions regardless of the order of seals sent by the nodes.
+type Network struct {
+	sync.RWMutex
+	clock     *localtime.VirtualClock
+	config    LinkConfig
+	links     map[[2]int]*link
+	groups    map[int]int
+	sent      uint64
+	delivered uint64
+	dropped   uint64
+	blocked   uint64
+}
+
+func NewNetwork(clock *localtime.VirtualClock, seed int64, n int, config LinkConfig) *Network {
+	links := map[[2]int]*link{}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+
+			links[[2]int{i, j}] = &link{
+				seed:  seed ^ int64((i+1)<<32|(j+1)),
+				sends: map[string]uint64{},
+			}
+		}
+	}
+
+	return &Network{
+		clock:  clock,
+		config: config,
+		links:  links,
+	}
+}
+
+func (nt *Network) Config() LinkConfig {
+	nt.RLock()
+	defer nt.RUnlock()
+
+	return nt.config
+}
+
+func (nt *Network) SetConfig(config LinkConfig) {
+	nt.Lock()
+	defer nt.Unlock()
+
+	nt.config = config
+}
+
+// Partition splits nodes into the groups; the nodes in the different groups
+// can not communicate. The nodes, which are not in any group, are isolated.
+func (nt *Network) Partition(groups ...[]int) {
+	nt.Lock()
+	defer nt.Unlock()
+
+	nt.groups = map[int]int{}
+	for i := range groups {
+		for _, j := range groups[i] {
+			nt.groups[j] = i
+		}
+	}
+}
+
+// Heal removes partitions.
+func (nt *Network) Heal() {
+	nt.Lock()
+	defer nt.Unlock()
+
+	nt.groups = nil
+}
+
+func (nt *Network) Connected(from, to int) bool {
+	nt.RLock()
+	defer nt.RUnlock()
+
+	if nt.groups == nil {
+		return true
+	}
+
+	a, found := nt.groups[from]
+	if !found {
+		return false
+	}
+
+	b, found := nt.groups[to]
+
+	return found && a == b
+}
+
+func (nt *Network) Stats() NetworkStats {
+	return NetworkStats{
+		Sent:      atomic.LoadUint64(&nt.sent),
+		Delivered: atomic.LoadUint64(&nt.delivered),
+		Dropped:   atomic.LoadUint64(&nt.dropped),
+		Blocked:   atomic.LoadUint64(&nt.blocked),
+	}
+}
+
+// send schedules the delivery. The partition is checked again at delivery,
+// so the seals in flight are lost when the link is partitioned.
+func (nt *Network) send(from, to int, h valuehash.Hash, deliver func()) {
+	atomic.AddUint64(&nt.sent, 1)
+
+	if !nt.Connected(from, to) {
+		atomic.AddUint64(&nt.blocked, 1)
+
+		return
+	}
+
+	delay, drop := nt.decide(from, to, h)
+	if drop {
+		atomic.AddUint64(&nt.dropped, 1)
+
+		return
+	}
+
+	nt.clock.AfterFunc(delay, func(time.Time) {
+		if !nt.Connected(from, to) {
+			atomic.AddUint64(&nt.blocked, 1)
+
+			return
+		}
+
+		atomic.AddUint64(&nt.delivered, 1)
+
+		go deliver()
+	})
+}
+
+func (nt *Network) decide(from, to int, h valuehash.Hash) (time.Duration, bool) {
+	config := nt.Config()
+
+	r := nt.links[[2]int{from, to}].rand(h)
+
+	if config.DropRate > 0 && r.Float64() < config.DropRate {
+		return 0, true
+	}
+
+	delay := config.MinDelay
+	if d := config.MaxDelay - config.MinDelay; d > 0 {
+		delay += time.Duration(r.Int63n(int64(d)))
+	}
+
+	if config.ReorderRate > 0 && r.Float64() < config.ReorderRate {
+		delay += config.ReorderDelay
+	}
+
+	return delay, false
+}
+
+// rand returns the random source for the seal hash; the resent seal gets
+// the different source.
+func (l *link) rand(h valuehash.Hash) *rand.Rand {
+	l.Lock()
+	defer l.Unlock()
+
+	k := h.String()
+	n := l.sends[k]
+	l.sends[k] = n + 1
+
+	f := fnv.New64a()
+	_, _ = f.Write(h.Bytes())
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	_, _ = f.Write(b)
+
+	return rand.New(rand.NewSource(l.seed ^ int64(f.Sum64()))) // nolint:gosec
+}
+
+// channel is the remote channel of node; the seals are sent through Network
+// and the requests are refused when the link is partitioned.
+type channel struct {
+	*channetwork.Channel
+	nt   *Network
+	from int
+	to   int
+}
+
+func newChannel(ch *channetwork.Channel, nt *Network, from, to int) *channel {
+	return &channel{Channel: ch, nt: nt, from: from, to: to}
+}
+
+func (ch *channel) SendSeal(_ context.Context, ci network.ConnInfo, sl seal.Seal) error {
+	ch.nt.send(ch.from, ch.to, sl.Hash(), func() {
+		_ = ch.Channel.SendSeal(context.Background(), ci, sl)
+	})
+
+	return nil
+}
+
+func (ch *channel) Proposal(ctx context.Context, h valuehash.Hash) (base.Proposal, error) {
+	if err := ch.check(); err != nil {
+		return nil, err
+	}
+
+	return ch.Channel.Proposal(ctx, h)
+}
+
+func (ch *channel) NodeInfo(ctx context.Context) (network.NodeInfo, error) {
+	if err := ch.check(); err != nil {
+		return nil, err
+	}
+
+	return ch.Channel.NodeInfo(ctx)
+}
+
+func (ch *channel) BlockdataMaps(ctx context.Context, hs []base.Height) ([]block.BlockdataMap, error) {
+	if err := ch.check(); err != nil {
+		return nil, err
+	}
+
+	return ch.Channel.BlockdataMaps(ctx, hs)
+}
+
+func (ch *channel) Blockdata(ctx context.Context, item block.BlockdataMapItem) (io.ReadCloser, error) {
+	if err := ch.check(); err != nil {
+		return nil, err
+	}
+
+	return ch.Channel.Blockdata(ctx, item)
+}
+
+func (ch *channel) check() error {
+	if !ch.nt.Connected(ch.from, ch.to) {
+		return network.NetworkError.Errorf("partitioned; %d -> %d", ch.from, ch.to)
+	}
+
+	return nil
+}
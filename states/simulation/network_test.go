@@ -0,0 +1,132 @@
+//go:build test
+// +build test
+
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testNetwork struct {
+	suite.Suite
+}
+
+func (t *testNetwork) hashes(n int) []valuehash.Hash {
+	hs := make([]valuehash.Hash, n)
+	for i := range hs {
+		hs[i] = valuehash.NewSHA256([]byte{byte(i)})
+	}
+
+	return hs
+}
+
+func (t *testNetwork) decisions(seed int64, hs []valuehash.Hash) map[string]time.Duration {
+	config := LinkConfig{
+		MinDelay:     time.Millisecond * 10,
+		MaxDelay:     time.Millisecond * 300,
+		DropRate:     0.3,
+		ReorderRate:  0.3,
+		ReorderDelay: time.Second,
+	}
+
+	nt := NewNetwork(localtime.NewVirtualClock(time.Now()), seed, 3, config)
+
+	ds := map[string]time.Duration{}
+	for i := range hs {
+		d, drop := nt.decide(0, 1, hs[i])
+		if drop {
+			d = -1
+		}
+
+		ds[hs[i].String()] = d
+	}
+
+	return ds
+}
+
+func (t *testNetwork) TestSeed() {
+	hs := t.hashes(100)
+
+	t.Equal(t.decisions(1, hs), t.decisions(1, hs))
+	t.NotEqual(t.decisions(1, hs), t.decisions(2, hs))
+}
+
+func (t *testNetwork) TestSeedOrder() {
+	hs := t.hashes(100)
+
+	reversed := make([]valuehash.Hash, len(hs))
+	for i := range hs {
+		reversed[len(hs)-1-i] = hs[i]
+	}
+
+	t.Equal(t.decisions(1, hs), t.decisions(1, reversed))
+}
+
+func (t *testNetwork) TestResend() {
+	nt := NewNetwork(localtime.NewVirtualClock(time.Now()), 1, 2, LinkConfig{
+		MinDelay: time.Millisecond,
+		MaxDelay: time.Second,
+	})
+
+	h := valuehash.RandomSHA256()
+
+	a, _ := nt.decide(0, 1, h)
+	b, _ := nt.decide(0, 1, h)
+	t.NotEqual(a, b)
+}
+
+func (t *testNetwork) TestDelay() {
+	clock := localtime.NewVirtualClock(time.Now())
+	nt := NewNetwork(clock, 1, 2, LinkConfig{MinDelay: time.Second, MaxDelay: time.Second})
+
+	delivered := make(chan struct{}, 1)
+	nt.send(0, 1, valuehash.RandomSHA256(), func() { delivered <- struct{}{} })
+
+	clock.Advance(time.Millisecond * 999)
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case <-delivered:
+		t.Fail("delivered before delay")
+	}
+
+	clock.Advance(time.Millisecond)
+	select {
+	case <-time.After(time.Second):
+		t.Fail("not delivered after delay")
+	case <-delivered:
+	}
+
+	t.Equal(NetworkStats{Sent: 1, Delivered: 1}, nt.Stats())
+}
+
+func (t *testNetwork) TestPartition() {
+	clock := localtime.NewVirtualClock(time.Now())
+	nt := NewNetwork(clock, 1, 4, LinkConfig{MinDelay: time.Second, MaxDelay: time.Second})
+
+	nt.Partition([]int{0, 1}, []int{2})
+	t.True(nt.Connected(0, 1))
+	t.False(nt.Connected(0, 2))
+	t.False(nt.Connected(3, 0)) // NOTE not in any group
+
+	nt.send(0, 2, valuehash.RandomSHA256(), func() {})
+	t.Equal(uint64(1), nt.Stats().Blocked)
+
+	// NOTE partitioned while in flight
+	nt.send(0, 1, valuehash.RandomSHA256(), func() {})
+	nt.Partition([]int{0}, []int{1})
+	clock.Advance(time.Second)
+	t.Equal(uint64(2), nt.Stats().Blocked)
+	t.Equal(uint64(0), nt.Stats().Delivered)
+
+	nt.Heal()
+	t.True(nt.Connected(3, 0))
+}
+
+func TestNetwork(t *testing.T) {
+	suite.Run(t, new(testNetwork))
+}
@@ -0,0 +1,252 @@
+//go:build test
+// +build test
+
+package simulation
+
+import (
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/prprocessor"
+	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/network"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	basicstates "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/cache"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// Node is the consensus node of simulation; it runs the States of local
+// with the real proposal processor.
+type Node struct {
+	index  int
+	local  *isaac.Local
+	ss     *basicstates.States
+	server *channetwork.Server
+	pps    *prprocessor.Processors
+}
+
+func newNode(index int, local *isaac.Local, suffrage base.Suffrage) (*Node, error) {
+	ch, ok := local.Channel().(*channetwork.Channel)
+	if !ok {
+		return nil, errors.Errorf("simulation needs gochan channel, not %T", local.Channel())
+	}
+
+	db := local.Database()
+	policy := local.Policy()
+	nodepool := local.Nodes()
+
+	setHandlers(local, ch)
+
+	pps := prprocessor.NewProcessors(
+		isaac.NewDefaultProcessorNewFunc(db, local.Blockdata(), nodepool, suffrage, nil),
+		nil,
+	)
+	if err := pps.Initialize(); err != nil {
+		return nil, err
+	}
+
+	ballotbox := isaac.NewBallotbox(
+		suffrage.Nodes,
+		func() base.Threshold {
+			t, err := base.NewThreshold(uint(len(suffrage.Nodes())), policy.ThresholdRatio())
+			if err != nil {
+				panic(err)
+			}
+
+			return t
+		},
+	)
+
+	ss, err := basicstates.NewStates(
+		db,
+		policy,
+		nodepool,
+		suffrage,
+		ballotbox,
+		basicstates.NewStoppedState(),
+		basicstates.NewBootingState(local.Node(), db, local.Blockdata(), policy, suffrage),
+		basicstates.NewJoiningState(local.Node(), db, policy, suffrage, ballotbox),
+		basicstates.NewConsensusState(
+			db, policy, nodepool, suffrage, isaac.NewProposalMaker(local.Node(), db, policy), pps),
+		basicstates.NewSyncingState(db, local.Blockdata(), policy, nodepool, suffrage),
+		basicstates.NewHandoverState(db, policy, nodepool, suffrage, pps),
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE the known seals are ignored like the network handler of node;
+	// without seal cache, the received proposals are broadcasted endlessly.
+	sealCache, err := cache.NewGCache("lru", 10000, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	server := channetwork.NewServer(ch, nil)
+	server.SetNewSealHandler(newSealHandler(local, suffrage, ss, sealCache))
+
+	return &Node{
+		index:  index,
+		local:  local,
+		ss:     ss,
+		server: server,
+		pps:    pps,
+	}, nil
+}
+
+func (no *Node) Index() int {
+	return no.index
+}
+
+func (no *Node) Local() *isaac.Local {
+	return no.local
+}
+
+func (no *Node) States() *basicstates.States {
+	return no.ss
+}
+
+// Height returns the height of last block.
+func (no *Node) Height() base.Height {
+	switch m, found, err := no.local.Database().LastManifest(); {
+	case err != nil, !found:
+		return base.NilHeight
+	default:
+		return m.Height()
+	}
+}
+
+func (no *Node) start() error {
+	if err := no.pps.Start(); err != nil {
+		return err
+	}
+
+	if err := no.server.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		_ = no.ss.Start()
+	}()
+
+	return no.ss.SwitchState(basicstates.NewStateSwitchContext(base.StateStopped, base.StateBooting))
+}
+
+func (no *Node) stop() error {
+	if err := no.ss.Stop(); err != nil {
+		return err
+	}
+
+	if err := no.server.Stop(); err != nil && !errors.Is(err, util.DaemonAlreadyStoppedError) {
+		return err
+	}
+
+	if err := no.pps.Stop(); err != nil && !errors.Is(err, util.DaemonAlreadyStoppedError) {
+		return err
+	}
+
+	return nil
+}
+
+// newSealHandler checks the incoming seal like the network handler of node
+// and passes it to States.
+func newSealHandler(
+	local *isaac.Local,
+	suffrage base.Suffrage,
+	ss *basicstates.States,
+	sealCache cache.Cache,
+) network.NewSealHandler {
+	return func(sl seal.Seal) error {
+		if i, ok := sl.(network.PassthroughedSeal); ok {
+			sl = i.Seal
+		}
+
+		sealChecker := isaac.NewSealChecker(sl, local.Database(), local.Policy(), sealCache)
+		if err := util.NewChecker("simulation-new-seal-checker", []util.CheckerFunc{
+			sealChecker.IsKnown,
+			sealChecker.IsValid,
+		}).Check(); err != nil {
+			if errors.Is(err, util.IgnoreError) {
+				return nil
+			}
+
+			return err
+		}
+
+		if t, ok := sl.(base.Ballot); ok {
+			checker := isaac.NewBallotChecker(
+				t,
+				local.Database(),
+				local.Policy(),
+				suffrage,
+				local.Nodes(),
+				ss.LastVoteproof(),
+			)
+			if err := util.NewChecker("simulation-new-ballot-checker", []util.CheckerFunc{
+				checker.IsFromLocal,
+				checker.InTimespan,
+				checker.InSuffrage,
+				checker.CheckSigning,
+				checker.CheckWithLastVoteproof,
+				checker.CheckProposalInACCEPTBallot,
+				checker.CheckVoteproof,
+			}).Check(); err != nil {
+				if errors.Is(err, util.IgnoreError) {
+					return nil
+				}
+
+				return err
+			}
+		}
+
+		return ss.NewSeal(sl)
+	}
+}
+
+// setHandlers sets the handlers for syncing and proposal to the channel of
+// local.
+func setHandlers(local *isaac.Local, ch *channetwork.Channel) {
+	db := local.Database()
+
+	ch.SetGetProposalHandler(func(h valuehash.Hash) (base.Proposal, error) {
+		switch pr, found, err := db.Proposal(h); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, util.NotFoundError.Errorf("proposal not found, %s", h)
+		default:
+			return pr, nil
+		}
+	})
+	ch.SetBlockdataMapsHandler(func(heights []base.Height) ([]block.BlockdataMap, error) {
+		var bds []block.BlockdataMap
+		for i := range heights {
+			bd, found, err := db.BlockdataMap(heights[i])
+			if err != nil {
+				return nil, err
+			} else if !found {
+				break
+			}
+
+			bds = append(bds, bd)
+		}
+
+		return bds, nil
+	})
+	ch.SetBlockdataHandler(func(p string) (io.Reader, func() error, error) {
+		f, err := local.Blockdata().FS().Open(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return f, f.Close, nil
+	})
+}
@@ -0,0 +1,390 @@
+//go:build test
+// +build test
+
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	basicstates "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	DefaultStep   = time.Millisecond * 100
+	DefaultSettle = time.Microsecond * 500
+)
+
+var (
+	SafetyError   = util.NewError("safety violated")
+	LivenessError = util.NewError("liveness violated")
+)
+
+type Config struct {
+	// Seed decides the delay, drop and reordering of seals.
+	Seed int64
+	Link LinkConfig
+	// Start is the start time of clock; by default, the current time.
+	Start time.Time
+	// Step is the maximum virtual duration, which the clock advances at once.
+	Step time.Duration
+	// Settle is the real duration, which the nodes should stay idle after
+	// each step; see Simulation.Step.
+	Settle time.Duration
+}
+
+// Simulation runs the nodes under VirtualClock. The clock is set to the
+// global clock of localtime while running, so only one simulation can run at
+// once.
+//
+// The seeded network decides the faults of seals and the clock moves only
+// after the nodes handle the fired timers and seals, so the same seed with the
+// same locals and start time gives the same blocks.
+type Simulation struct {
+	sync.RWMutex
+	clock      *localtime.VirtualClock
+	network    *Network
+	nodes      []*Node
+	step       time.Duration
+	settle     time.Duration
+	blocks     map[base.Height]valuehash.Hash
+	violations []error
+}
+
+// New creates the simulation from the locals, which have the same blocks.
+// The locals should use gochan channel, like isaac.BaseTest.Locals().
+func New(locals []*isaac.Local, config Config) (*Simulation, error) {
+	if len(locals) < 1 {
+		return nil, errors.Errorf("empty locals")
+	}
+
+	step, settle := config.Step, config.Settle
+	if step < 1 {
+		step = DefaultStep
+	}
+
+	if settle < 1 {
+		settle = DefaultSettle
+	}
+
+	start := config.Start
+	if start.IsZero() {
+		start = localtime.UTCNow()
+	}
+
+	clock := localtime.NewVirtualClock(start)
+
+	sm := &Simulation{
+		clock:   clock,
+		network: NewNetwork(clock, config.Seed, len(locals), config.Link),
+		step:    step,
+		settle:  settle,
+		blocks:  map[base.Height]valuehash.Hash{},
+	}
+
+	nodes := make([]base.Address, len(locals))
+	for i := range locals {
+		nodes[i] = locals[i].Node().Address()
+	}
+
+	suffrage := newRoundSuffrage(nodes)
+
+	sm.nodes = make([]*Node, len(locals))
+	for i := range locals {
+		if err := sm.connect(i, locals); err != nil {
+			return nil, err
+		}
+
+		no, err := newNode(i, locals[i], suffrage)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := no.ss.BlockSavedHook().Add("simulation", sm.blockSavedHook(i), false); err != nil {
+			return nil, err
+		}
+
+		sm.nodes[i] = no
+	}
+
+	return sm, nil
+}
+
+func (sm *Simulation) Clock() *localtime.VirtualClock {
+	return sm.clock
+}
+
+func (sm *Simulation) Network() *Network {
+	return sm.network
+}
+
+func (sm *Simulation) Nodes() []*Node {
+	return sm.nodes
+}
+
+func (sm *Simulation) Start() error {
+	localtime.SetClock(sm.clock)
+
+	for i := range sm.nodes {
+		if err := sm.nodes[i].start(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sm *Simulation) Stop() error {
+	defer localtime.SetClock(nil)
+
+	for i := range sm.nodes {
+		if err := sm.nodes[i].stop(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Step advances the clock to the next deadline of timers and seals, but not
+// more than one step, and waits for the nodes to handle them. The nodes are
+// regarded as idle when no goroutine is running and the goroutines, the
+// waiters of clock and the seals of network are not changed during Settle.
+func (sm *Simulation) Step() {
+	d := sm.step
+	if next, found := sm.clock.Next(); found {
+		if i := next.Sub(sm.clock.Now()); i < d {
+			d = i
+		}
+	}
+
+	sm.clock.Advance(d)
+
+	sm.waitIdle()
+}
+
+type activity struct {
+	goroutines int
+	registered uint64
+	waiters    int
+	stats      NetworkStats
+}
+
+func (sm *Simulation) activity() activity {
+	return activity{
+		goroutines: runtime.NumGoroutine(),
+		registered: sm.clock.Registered(),
+		waiters:    sm.clock.Waiters(),
+		stats:      sm.network.Stats(),
+	}
+}
+
+func (sm *Simulation) waitIdle() {
+	last := sm.activity()
+	idle := time.Now()
+
+	for time.Since(idle) < sm.settle {
+		runtime.Gosched()
+
+		if a := sm.activity(); a != last || busyGoroutines() {
+			last = a
+			idle = time.Now()
+		}
+	}
+}
+
+var busyGoroutineStates = [][]byte{[]byte("[running"), []byte("[runnable"), []byte("[syscall")}
+
+// busyGoroutines checks whether the other goroutines are running or waiting to
+// run.
+func busyGoroutines() bool {
+	b := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(b, true)
+		if n < len(b) {
+			b = b[:n]
+
+			break
+		}
+
+		b = make([]byte, len(b)*2)
+	}
+
+	// NOTE the first goroutine is the current one
+	for i, l := range bytes.Split(b, []byte("\n")) {
+		if i < 1 || !bytes.HasPrefix(l, []byte("goroutine ")) {
+			continue
+		}
+
+		for _, s := range busyGoroutineStates {
+			if bytes.Contains(l, s) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// RunFor runs the simulation for the virtual duration.
+func (sm *Simulation) RunFor(d time.Duration) {
+	end := sm.clock.Now().Add(d)
+
+	for sm.clock.Now().Before(end) {
+		sm.Step()
+	}
+}
+
+// RunUntil runs the simulation until f returns true. If f is not satisfied
+// within the virtual duration, LivenessError is returned.
+func (sm *Simulation) RunUntil(f func() bool, limit time.Duration) error {
+	end := sm.clock.Now().Add(limit)
+
+	for {
+		switch {
+		case f():
+			return nil
+		case !sm.clock.Now().Before(end):
+			return LivenessError.Errorf("not satisfied within %v", limit)
+		}
+
+		sm.Step()
+	}
+}
+
+// WaitHeight runs the simulation until the given nodes store the block of
+// height; if indexes are empty, all the nodes are checked.
+func (sm *Simulation) WaitHeight(height base.Height, limit time.Duration, indexes ...int) error {
+	if len(indexes) < 1 {
+		indexes = make([]int, len(sm.nodes))
+		for i := range sm.nodes {
+			indexes[i] = i
+		}
+	}
+
+	err := sm.RunUntil(func() bool {
+		for _, i := range indexes {
+			if sm.nodes[i].Height() < height {
+				return false
+			}
+		}
+
+		return true
+	}, limit)
+	if err != nil {
+		return LivenessError.Errorf("height %d not reached: %v", height, sm.Heights())
+	}
+
+	return nil
+}
+
+// Heights returns the last heights of nodes.
+func (sm *Simulation) Heights() []base.Height {
+	hs := make([]base.Height, len(sm.nodes))
+	for i := range sm.nodes {
+		hs[i] = sm.nodes[i].Height()
+	}
+
+	return hs
+}
+
+// CheckSafety checks that no different blocks are stored at the same height.
+// The blocks reported by the block saved hook and the stored manifests of
+// nodes are both checked.
+func (sm *Simulation) CheckSafety() error {
+	sm.RLock()
+	violations := sm.violations
+	sm.RUnlock()
+
+	if len(violations) > 0 {
+		return violations[0]
+	}
+
+	var top base.Height
+	for i := range sm.nodes {
+		if h := sm.nodes[i].Height(); h > top {
+			top = h
+		}
+	}
+
+	for height := base.PreGenesisHeight; height <= top; height++ {
+		var first valuehash.Hash
+		var firstNode int
+
+		for i := range sm.nodes {
+			switch m, found, err := sm.nodes[i].local.Database().ManifestByHeight(height); {
+			case err != nil:
+				return err
+			case !found:
+				continue
+			case first == nil:
+				first, firstNode = m.Hash(), i
+			case !first.Equal(m.Hash()):
+				return SafetyError.Errorf("different blocks at height %d; node%d=%s, node%d=%s",
+					height, firstNode, first, i, m.Hash())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (sm *Simulation) blockSavedHook(index int) func(context.Context) (context.Context, error) {
+	return func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstates.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		sm.Lock()
+		defer sm.Unlock()
+
+		for i := range blks {
+			blk := blks[i]
+
+			switch h, found := sm.blocks[blk.Height()]; {
+			case !found:
+				sm.blocks[blk.Height()] = blk.Hash()
+			case !h.Equal(blk.Hash()):
+				sm.violations = append(sm.violations, SafetyError.Errorf(
+					"different block saved at height %d by node%d; %s != %s", blk.Height(), index, blk.Hash(), h))
+			}
+		}
+
+		return ctx, nil
+	}
+}
+
+// connect replaces the channels of remote nodes in the nodepool of local with
+// the channels through Network.
+func (sm *Simulation) connect(index int, locals []*isaac.Local) error {
+	nodepool := locals[index].Nodes()
+
+	for i := range locals {
+		if i == index {
+			continue
+		}
+
+		ch, ok := locals[i].Channel().(*channetwork.Channel)
+		if !ok {
+			return errors.Errorf("simulation needs gochan channel, not %T", locals[i].Channel())
+		}
+
+		if err := nodepool.SetChannel(locals[i].Node().Address(), newChannel(ch, sm.network, index, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
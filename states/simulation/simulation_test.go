@@ -0,0 +1,203 @@
+//go:build test
+// +build test
+
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/isaac"
+	basicstates "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSimulation struct {
+	isaac.BaseTest
+}
+
+func (t *testSimulation) newSimulation(n int, config Config) (*Simulation, base.Height) {
+	return t.newSimulationWithLocals(t.Locals(n), config)
+}
+
+func (t *testSimulation) newSimulationWithLocals(ls []*isaac.Local, config Config) (*Simulation, base.Height) {
+	for i := range ls {
+		_, _ = ls[i].Policy().SetThresholdRatio(base.ThresholdRatio(67))
+	}
+
+	sm, err := New(ls, config)
+	t.NoError(err)
+
+	return sm, t.LastManifest(ls[0].Database()).Height()
+}
+
+func (t *testSimulation) run(sm *Simulation) func() {
+	t.NoError(sm.Start())
+
+	return func() {
+		t.NoError(sm.Stop())
+	}
+}
+
+func (t *testSimulation) TestHealthy() {
+	sm, last := t.newSimulation(4, Config{
+		Seed: 1,
+		Link: LinkConfig{MinDelay: time.Millisecond * 10, MaxDelay: time.Millisecond * 50},
+	})
+
+	defer t.run(sm)()
+
+	t.NoError(sm.WaitHeight(last+3, time.Minute))
+	t.NoError(sm.CheckSafety())
+}
+
+func (t *testSimulation) TestSameSeed() {
+	ls := t.Locals(4)
+	cls := t.CloneLocals(ls)
+
+	config := Config{
+		Seed:  6,
+		Start: localtime.UTCNow(),
+		Link: LinkConfig{
+			MinDelay:     time.Millisecond * 10,
+			MaxDelay:     time.Millisecond * 300,
+			DropRate:     0.05,
+			ReorderRate:  0.2,
+			ReorderDelay: time.Millisecond * 500,
+		},
+	}
+
+	blocks := func(sm *Simulation, last base.Height) []valuehash.Hash {
+		stop := t.run(sm)
+		defer stop()
+
+		t.NoError(sm.WaitHeight(last+3, time.Minute*3))
+
+		hs := make([]valuehash.Hash, 3)
+		for i := range hs {
+			m, found, err := sm.Nodes()[0].Local().Database().ManifestByHeight(last + base.Height(i+1))
+			t.NoError(err)
+			t.True(found)
+
+			hs[i] = m.Hash()
+		}
+
+		return hs
+	}
+
+	a := blocks(t.newSimulationWithLocals(ls, config))
+	b := blocks(t.newSimulationWithLocals(cls, config))
+
+	for i := range a {
+		t.True(a[i].Equal(b[i]), "block hash does not match; %s != %s", a[i], b[i])
+	}
+}
+
+func (t *testSimulation) TestFaults() {
+	sm, last := t.newSimulation(4, Config{
+		Seed: 2,
+		Link: LinkConfig{
+			MinDelay:     time.Millisecond * 10,
+			MaxDelay:     time.Millisecond * 300,
+			DropRate:     0.05,
+			ReorderRate:  0.2,
+			ReorderDelay: time.Millisecond * 500,
+		},
+	})
+
+	defer t.run(sm)()
+
+	t.NoError(sm.WaitHeight(last+3, time.Minute*3))
+	t.NoError(sm.CheckSafety())
+
+	stats := sm.Network().Stats()
+	t.True(stats.Dropped > 0)
+	t.True(stats.Delivered > 0)
+}
+
+func (t *testSimulation) TestSplitPartition() {
+	sm, last := t.newSimulation(4, Config{
+		Seed: 3,
+		Link: LinkConfig{MinDelay: time.Millisecond * 10, MaxDelay: time.Millisecond * 50},
+	})
+
+	// NOTE no majority in both sides
+	sm.Network().Partition([]int{0, 1}, []int{2, 3})
+
+	defer t.run(sm)()
+
+	sm.RunFor(time.Second * 30)
+
+	for _, h := range sm.Heights() {
+		t.Equal(last, h)
+	}
+
+	t.NoError(sm.CheckSafety())
+
+	sm.Network().Heal()
+
+	t.NoError(sm.WaitHeight(last+2, time.Minute*3))
+	t.NoError(sm.CheckSafety())
+}
+
+func (t *testSimulation) TestIsolatedNode() {
+	sm, last := t.newSimulation(4, Config{
+		Seed: 4,
+		Link: LinkConfig{MinDelay: time.Millisecond * 10, MaxDelay: time.Millisecond * 50},
+	})
+
+	sm.Network().Partition([]int{0, 1, 2})
+
+	defer t.run(sm)()
+
+	// NOTE majority keeps consensus without isolated node
+	t.NoError(sm.WaitHeight(last+2, time.Minute*2, 0, 1, 2))
+	t.Equal(last, sm.Nodes()[3].Height())
+
+	sm.Network().Heal()
+
+	// NOTE isolated node catches up
+	t.NoError(sm.WaitHeight(sm.Nodes()[0].Height()+1, time.Minute*3))
+	t.NoError(sm.CheckSafety())
+}
+
+func (t *testSimulation) TestByzantineNode() {
+	// NOTE the delay is shorter than the timeouts of policy, so the delayed
+	// ballots arrive before the next round.
+	delay := basicstates.NewByzantineRule(basicstates.ByzantineDelay)
+	delay.Delay = time.Millisecond * 500
+
+	rules := []basicstates.ByzantineRule{
+		basicstates.NewByzantineRule(basicstates.ByzantineConflictingBallot),
+		basicstates.NewByzantineRule(basicstates.ByzantineWithholdVote),
+		basicstates.NewByzantineRule(basicstates.ByzantineInvalidOperations),
+		basicstates.NewByzantineRule(basicstates.ByzantineForgeVoteproof),
+		delay,
+	}
+
+	for i := range rules {
+		rule := rules[i]
+
+		t.Run(string(rule.Type), func() {
+			sm, last := t.newSimulation(4, Config{
+				Seed: 5,
+				Link: LinkConfig{MinDelay: time.Millisecond * 10, MaxDelay: time.Millisecond * 50},
+			})
+
+			// NOTE 1 byzantine node of 4 nodes is under the threshold
+			t.NoError(sm.Nodes()[3].States().SetByzantine([]basicstates.ByzantineRule{rule}))
+
+			defer t.run(sm)()
+
+			t.NoError(sm.WaitHeight(last+3, time.Minute*3, 0, 1, 2))
+			t.NoError(sm.CheckSafety())
+		})
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	suite.Run(t, new(testSimulation))
+}
@@ -0,0 +1,69 @@
+//go:build test
+// +build test
+
+package simulation
+
+import (
+	"fmt"
+
+	"github.com/spikeekips/mitum/base"
+)
+
+// roundSuffrage selects the proposer by height and round in the order of
+// nodes, so the proposer is changed in the next round when the proposer is
+// partitioned.
+type roundSuffrage struct {
+	nodes []base.Address
+	all   map[string]struct{}
+}
+
+func newRoundSuffrage(nodes []base.Address) *roundSuffrage {
+	all := map[string]struct{}{}
+	for i := range nodes {
+		all[nodes[i].String()] = struct{}{}
+	}
+
+	return &roundSuffrage{nodes: nodes, all: all}
+}
+
+func (*roundSuffrage) Initialize() error {
+	return nil
+}
+
+func (*roundSuffrage) Name() string {
+	return "simulation-round-suffrage"
+}
+
+func (sf *roundSuffrage) NumberOfActing() uint {
+	return uint(len(sf.nodes))
+}
+
+func (sf *roundSuffrage) Acting(height base.Height, round base.Round) (base.ActingSuffrage, error) {
+	return base.NewActingSuffrage(height, round, sf.proposer(height, round), sf.nodes), nil
+}
+
+func (sf *roundSuffrage) IsInside(a base.Address) bool {
+	_, found := sf.all[a.String()]
+
+	return found
+}
+
+func (sf *roundSuffrage) IsActing(_ base.Height, _ base.Round, a base.Address) (bool, error) {
+	return sf.IsInside(a), nil
+}
+
+func (sf *roundSuffrage) IsProposer(height base.Height, round base.Round, a base.Address) (bool, error) {
+	return sf.proposer(height, round).Equal(a), nil
+}
+
+func (sf *roundSuffrage) Nodes() []base.Address {
+	return sf.nodes
+}
+
+func (sf *roundSuffrage) Verbose() string {
+	return fmt.Sprintf("%s: %v", sf.Name(), sf.nodes)
+}
+
+func (sf *roundSuffrage) proposer(height base.Height, round base.Round) base.Address {
+	return sf.nodes[(uint64(height.Int64())+round.Uint64())%uint64(len(sf.nodes))]
+}
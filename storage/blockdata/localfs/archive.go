@@ -0,0 +1,369 @@
+package localfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/storage"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+)
+
+// ArchiveIndexName is the name of index in blockdata archive. The index is
+// always the first entry of archive.
+var ArchiveIndexName = "index.json"
+
+// ArchiveItem is the block data file in archive. Checksum is same with
+// BlockdataMapItem.Checksum().
+type ArchiveItem struct {
+	Height   base.Height `json:"height"`
+	Type     string      `json:"type"`
+	Name     string      `json:"name"`
+	Checksum string      `json:"checksum"`
+	Size     int64       `json:"size"`
+}
+
+func NewArchiveItem(height base.Height, dataType, checksum string, size int64) ArchiveItem {
+	return ArchiveItem{
+		Height:   height,
+		Type:     dataType,
+		Name:     archiveItemName(height, dataType, checksum),
+		Checksum: checksum,
+		Size:     size,
+	}
+}
+
+func (ai ArchiveItem) IsValid([]byte) error {
+	switch {
+	case ai.Height < base.PreGenesisHeight:
+		return isvalid.InvalidError.Errorf("invalid height of archive item, %d", ai.Height)
+	case len(ai.Checksum) < 1:
+		return isvalid.InvalidError.Errorf("empty checksum of archive item")
+	case ai.Size < 1:
+		return isvalid.InvalidError.Errorf("empty archive item")
+	case ai.Name != archiveItemName(ai.Height, ai.Type, ai.Checksum):
+		return isvalid.InvalidError.Errorf("invalid name of archive item, %q", ai.Name)
+	}
+
+	for i := range block.Blockdata {
+		if block.Blockdata[i] == ai.Type {
+			return nil
+		}
+	}
+
+	return isvalid.InvalidError.Errorf("unknown data type of archive item, %q", ai.Type)
+}
+
+// ArchiveIndex describes the block data files in archive. The archive has all
+// the block data files from From to To height.
+type ArchiveIndex struct {
+	From  base.Height   `json:"from"`
+	To    base.Height   `json:"to"`
+	Items []ArchiveItem `json:"items"`
+}
+
+func (ai ArchiveIndex) IsValid([]byte) error {
+	switch {
+	case ai.From < base.PreGenesisHeight:
+		return isvalid.InvalidError.Errorf("invalid from height, %d", ai.From)
+	case ai.From > ai.To:
+		return isvalid.InvalidError.Errorf("from height is higher than to; %d > %d", ai.From, ai.To)
+	}
+
+	if n := (ai.To - ai.From + 1).Int64() * int64(len(block.Blockdata)); int64(len(ai.Items)) != n {
+		return isvalid.InvalidError.Errorf("wrong number of archive items; %d != %d", len(ai.Items), n)
+	}
+
+	found := map[string]struct{}{}
+	for i := range ai.Items {
+		item := ai.Items[i]
+		if err := item.IsValid(nil); err != nil {
+			return err
+		}
+
+		if item.Height < ai.From || item.Height > ai.To {
+			return isvalid.InvalidError.Errorf("archive item, %q out of range", item.Name)
+		}
+
+		k := fmt.Sprintf("%d-%s", item.Height, item.Type)
+		if _, ok := found[k]; ok {
+			return isvalid.InvalidError.Errorf("duplicated archive item, %q", item.Name)
+		}
+		found[k] = struct{}{}
+	}
+
+	return nil
+}
+
+// WriteArchive writes the local block data files of the given block data maps
+// into one tar archive. The block data maps should be sequential by height.
+// The checksum of every file is checked while writing.
+func WriteArchive(w io.Writer, st *Blockdata, bdms []block.BlockdataMap) (ArchiveIndex, error) {
+	if len(bdms) < 1 {
+		return ArchiveIndex{}, errors.Errorf("empty block data maps")
+	}
+
+	sort.Slice(bdms, func(i, j int) bool {
+		return bdms[i].Height() < bdms[j].Height()
+	})
+
+	index := ArchiveIndex{
+		From: bdms[0].Height(),
+		To:   bdms[len(bdms)-1].Height(),
+	}
+
+	var paths []string
+	for i := range bdms {
+		bdm := bdms[i]
+
+		switch {
+		case bdm.Height() != index.From+base.Height(int64(i)):
+			return ArchiveIndex{}, errors.Errorf("block data maps are not sequential, %d", bdm.Height())
+		case !bdm.IsLocal():
+			return ArchiveIndex{}, errors.Errorf("block data map, %d is not local", bdm.Height())
+		}
+
+		items := archiveMapItems(bdm)
+		for j := range items {
+			item := items[j]
+
+			p := strings.TrimPrefix(item.URL(), "file://")
+			fi, err := os.Stat(filepath.Join(st.Root(), p))
+			if err != nil {
+				return ArchiveIndex{}, storage.MergeFSError(err)
+			}
+
+			index.Items = append(index.Items, NewArchiveItem(bdm.Height(), item.Type(), item.Checksum(), fi.Size()))
+			paths = append(paths, p)
+		}
+	}
+
+	if err := index.IsValid(nil); err != nil {
+		return ArchiveIndex{}, err
+	}
+
+	tw := tar.NewWriter(w)
+
+	b, err := jsonenc.Marshal(index)
+	if err != nil {
+		return ArchiveIndex{}, err
+	}
+
+	if err := writeArchiveEntry(tw, ArchiveIndexName, int64(len(b)), bytes.NewReader(b)); err != nil {
+		return ArchiveIndex{}, err
+	}
+
+	for i := range index.Items {
+		if err := writeArchiveItem(tw, st, index.Items[i], paths[i]); err != nil {
+			return ArchiveIndex{}, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return ArchiveIndex{}, err
+	}
+
+	return index, nil
+}
+
+// ReadArchive extracts the archive into the block data. The checksum of every
+// file is checked with index and the block directories are moved into the
+// block data only when the whole archive is verified. The existing block
+// directory is not overwritten.
+func ReadArchive(r io.Reader, st *Blockdata) (ArchiveIndex, error) {
+	tr := tar.NewReader(r)
+
+	var index ArchiveIndex
+	switch h, err := tr.Next(); {
+	case err != nil:
+		return index, errors.Wrap(err, "failed to read index of archive")
+	case h.Name != ArchiveIndexName:
+		return index, errors.Errorf("first entry of archive is not index, %q", h.Name)
+	default:
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return index, err
+		}
+
+		if err := jsonenc.Unmarshal(b, &index); err != nil {
+			return index, errors.Wrap(err, "failed to decode index of archive")
+		}
+
+		if err := index.IsValid(nil); err != nil {
+			return index, errors.Wrap(err, "invalid index of archive")
+		}
+	}
+
+	for h := index.From; h <= index.To; h++ {
+		switch found, _, err := st.ExistsReal(h); {
+		case err != nil:
+			return index, err
+		case found:
+			return index, errors.Errorf("block data, %d already exists", h)
+		}
+	}
+
+	temp, err := os.MkdirTemp(st.Root(), ".archive")
+	if err != nil {
+		return index, storage.MergeFSError(err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(temp)
+	}()
+
+	items := map[string]ArchiveItem{}
+	for i := range index.Items {
+		items[index.Items[i].Name] = index.Items[i]
+	}
+
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return index, err
+		}
+
+		item, found := items[h.Name]
+		if !found {
+			return index, errors.Errorf("unknown archive entry, %q", h.Name)
+		}
+		delete(items, h.Name)
+
+		if err := readArchiveItem(tr, temp, item); err != nil {
+			return index, err
+		}
+	}
+
+	if len(items) > 0 {
+		return index, errors.Errorf("archive items missing; %d", len(items))
+	}
+
+	st.Lock()
+	defer st.Unlock()
+
+	for h := index.From; h <= index.To; h++ {
+		if err := st.moveArchiveDirectory(temp, h); err != nil {
+			for i := index.From; i < h; i++ {
+				_ = os.RemoveAll(st.heightDirectory(i, true))
+			}
+
+			return index, err
+		}
+	}
+
+	return index, nil
+}
+
+func (st *Blockdata) moveArchiveDirectory(temp string, height base.Height) error {
+	d := st.heightDirectory(height, true)
+	if err := os.MkdirAll(filepath.Dir(d), DefaultDirectoryPermission); err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	if err := os.Rename(filepath.Join(temp, HeightDirectory(height)), d); err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	return nil
+}
+
+func writeArchiveItem(tw *tar.Writer, st *Blockdata, item ArchiveItem, p string) error {
+	f, err := st.FS().Open(p)
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	sha := sha256.New()
+	if err := writeArchiveEntry(tw, item.Name, item.Size, io.TeeReader(f, sha)); err != nil {
+		return err
+	}
+
+	if i := fmt.Sprintf("%x", sha.Sum(nil)); i != item.Checksum {
+		return errors.Errorf("block data, %q checksum does not match; %q != %q", item.Name, item.Checksum, i)
+	}
+
+	return nil
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     int64(DefaultFilePermission),
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(tw, r)
+
+	return err
+}
+
+func readArchiveItem(r io.Reader, root string, item ArchiveItem) error {
+	p := filepath.Join(root, item.Name)
+	if err := os.MkdirAll(filepath.Dir(p), DefaultDirectoryPermission); err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(p), os.O_CREATE|os.O_EXCL|os.O_WRONLY, DefaultFilePermission)
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	sha := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, sha), r)
+	switch {
+	case err != nil:
+		return storage.MergeFSError(err)
+	case n != item.Size:
+		return errors.Errorf("block data, %q size does not match; %d != %d", item.Name, item.Size, n)
+	}
+
+	if i := fmt.Sprintf("%x", sha.Sum(nil)); i != item.Checksum {
+		return errors.Errorf("block data, %q checksum does not match; %q != %q", item.Name, item.Checksum, i)
+	}
+
+	return nil
+}
+
+func archiveItemName(height base.Height, dataType, checksum string) string {
+	return strings.TrimPrefix(
+		filepath.Join(HeightDirectory(height), fmt.Sprintf(BlockFileFormats, height, dataType, checksum)),
+		"/",
+	)
+}
+
+func archiveMapItems(bdm block.BlockdataMap) []block.BlockdataMapItem {
+	return []block.BlockdataMapItem{
+		bdm.Manifest(),
+		bdm.Operations(),
+		bdm.OperationsTree(),
+		bdm.States(),
+		bdm.StatesTree(),
+		bdm.INITVoteproof(),
+		bdm.ACCEPTVoteproof(),
+		bdm.SuffrageInfo(),
+		bdm.Proposal(),
+	}
+}
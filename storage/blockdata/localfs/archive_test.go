@@ -0,0 +1,253 @@
+package localfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testArchive struct {
+	suite.Suite
+	JSONEnc  *jsonenc.Encoder
+	baseRoot string
+}
+
+func (t *testArchive) SetupSuite() {
+	t.JSONEnc = jsonenc.NewEncoder()
+
+	p, err := os.MkdirTemp("", "localfs-")
+	if err != nil {
+		panic(err)
+	}
+
+	t.baseRoot = p
+}
+
+func (t *testArchive) TearDownSuite() {
+	_ = os.RemoveAll(t.baseRoot)
+}
+
+func (t *testArchive) newBlockdata() *Blockdata {
+	p, err := os.MkdirTemp(t.baseRoot, "localfs-")
+	t.NoError(err)
+
+	st := NewBlockdata(p, t.JSONEnc)
+	t.NoError(st.Initialize())
+
+	return st
+}
+
+func (t *testArchive) writeBlockdata(st *Blockdata, height base.Height) block.BlockdataMap {
+	d := st.HeightDirectory(height, true)
+	t.NoError(st.CreateDirectory(d))
+
+	bdm := block.NewBaseBlockdataMap(st.Writer().Hint(), height)
+	for i := range block.Blockdata {
+		buf := bytes.NewBuffer(nil)
+		gw := gzip.NewWriter(buf)
+		_, err := gw.Write(util.UUID().Bytes())
+		t.NoError(err)
+		t.NoError(gw.Close())
+
+		checksum, err := util.GenerateChecksum(bytes.NewReader(buf.Bytes()))
+		t.NoError(err)
+
+		f := filepath.Join(d, fmt.Sprintf(BlockFileFormats, height, block.Blockdata[i], checksum))
+		t.NoError(os.WriteFile(f, buf.Bytes(), DefaultFilePermission))
+
+		item, err := NewBaseBlockdataMapItem(f)
+		t.NoError(err)
+
+		bdm, err = bdm.SetItem(item)
+		t.NoError(err)
+	}
+
+	return bdm
+}
+
+func (t *testArchive) prepare(from, to base.Height) (*Blockdata, []block.BlockdataMap) {
+	st := t.newBlockdata()
+
+	var bdms []block.BlockdataMap
+	for h := from; h <= to; h++ {
+		bdms = append(bdms, t.writeBlockdata(st, h))
+	}
+
+	return st, bdms
+}
+
+func (t *testArchive) TestWriteAndRead() {
+	st, bdms := t.prepare(base.PreGenesisHeight, base.Height(2))
+
+	buf := bytes.NewBuffer(nil)
+	index, err := WriteArchive(buf, st, bdms)
+	t.NoError(err)
+	t.NoError(index.IsValid(nil))
+	t.Equal(base.PreGenesisHeight, index.From)
+	t.Equal(base.Height(2), index.To)
+	t.Equal(len(bdms)*len(block.Blockdata), len(index.Items))
+
+	other := t.newBlockdata()
+
+	uindex, err := ReadArchive(bytes.NewReader(buf.Bytes()), other)
+	t.NoError(err)
+	t.Equal(index, uindex)
+
+	for i := range bdms {
+		bdm := bdms[i]
+
+		found, err := other.Exists(bdm.Height())
+		t.NoError(err)
+		t.True(found)
+
+		items := archiveMapItems(bdm)
+		for j := range items {
+			p := filepath.Join(other.Root(), items[j].(block.BaseBlockdataMapItem).URLBody())
+
+			checksum, err := util.GenerateFileChecksum(p)
+			t.NoError(err)
+			t.Equal(items[j].Checksum(), checksum)
+		}
+	}
+
+	entries, err := os.ReadDir(other.Root())
+	t.NoError(err)
+	t.Equal(1, len(entries)) // NOTE temporary directory is removed
+}
+
+func (t *testArchive) TestWriteNotSequential() {
+	st, bdms := t.prepare(base.Height(0), base.Height(3))
+
+	_, err := WriteArchive(io.Discard, st, append(bdms[:1], bdms[2:]...))
+	t.Error(err)
+	t.Contains(err.Error(), "not sequential")
+}
+
+func (t *testArchive) TestWriteWrongChecksum() {
+	st, bdms := t.prepare(base.Height(0), base.Height(1))
+
+	item := bdms[1].States()
+	t.NoError(os.WriteFile(
+		filepath.Join(st.Root(), item.(block.BaseBlockdataMapItem).URLBody()),
+		util.UUID().Bytes(),
+		DefaultFilePermission,
+	))
+
+	_, err := WriteArchive(io.Discard, st, bdms)
+	t.Error(err)
+	t.Contains(err.Error(), "checksum does not match")
+}
+
+func (t *testArchive) TestReadExisting() {
+	st, bdms := t.prepare(base.Height(0), base.Height(1))
+
+	buf := bytes.NewBuffer(nil)
+	_, err := WriteArchive(buf, st, bdms)
+	t.NoError(err)
+
+	_, err = ReadArchive(bytes.NewReader(buf.Bytes()), st)
+	t.Error(err)
+	t.Contains(err.Error(), "already exists")
+}
+
+func (t *testArchive) TestReadWrongChecksum() {
+	st, bdms := t.prepare(base.Height(0), base.Height(1))
+
+	buf := bytes.NewBuffer(nil)
+	_, err := WriteArchive(buf, st, bdms)
+	t.NoError(err)
+
+	// NOTE rewrite the last entry with wrong body
+	var entries []*tar.Header
+	var bodies [][]byte
+
+	tr := tar.NewReader(bytes.NewReader(buf.Bytes()))
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		t.NoError(err)
+
+		b, err := io.ReadAll(tr)
+		t.NoError(err)
+
+		entries = append(entries, h)
+		bodies = append(bodies, b)
+	}
+
+	wrong := bytes.Repeat([]byte{0}, len(bodies[len(bodies)-1]))
+	bodies[len(bodies)-1] = wrong
+
+	wbuf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(wbuf)
+	for i := range entries {
+		t.NoError(tw.WriteHeader(entries[i]))
+		_, err := tw.Write(bodies[i])
+		t.NoError(err)
+	}
+	t.NoError(tw.Close())
+
+	other := t.newBlockdata()
+
+	_, err = ReadArchive(bytes.NewReader(wbuf.Bytes()), other)
+	t.Error(err)
+	t.Contains(err.Error(), "checksum does not match")
+
+	for i := range bdms {
+		found, err := other.Exists(bdms[i].Height())
+		t.NoError(err)
+		t.False(found)
+	}
+}
+
+func (t *testArchive) TestReadMissingItem() {
+	st, bdms := t.prepare(base.Height(0), base.Height(1))
+
+	buf := bytes.NewBuffer(nil)
+	_, err := WriteArchive(buf, st, bdms)
+	t.NoError(err)
+
+	// NOTE archive without the last entry
+	wbuf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(wbuf)
+
+	tr := tar.NewReader(bytes.NewReader(buf.Bytes()))
+	var i int
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		t.NoError(err)
+
+		i++
+		if i > len(bdms)*len(block.Blockdata) {
+			break
+		}
+
+		t.NoError(tw.WriteHeader(h))
+		_, err = io.Copy(tw, tr)
+		t.NoError(err)
+	}
+	t.NoError(tw.Close())
+
+	_, err = ReadArchive(bytes.NewReader(wbuf.Bytes()), t.newBlockdata())
+	t.Error(err)
+	t.Contains(err.Error(), "archive items missing")
+}
+
+func TestArchive(t *testing.T) {
+	suite.Run(t, new(testArchive))
+}
@@ -277,6 +277,12 @@ func (ss *Session) done() (block.BaseBlockdataMap, error) {
 	return mapData, nil
 }
 
+// Done closes the session and returns the BaseBlockdataMap; the items of
+// BaseBlockdataMap point the files under the session root.
+func (ss *Session) Done() (block.BaseBlockdataMap, error) {
+	return ss.done()
+}
+
 func (ss *Session) Cancel() error {
 	return ss.clean()
 }
@@ -0,0 +1,448 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+var (
+	S3BlockdataType = hint.Type("s3-blockdata")
+	S3BlockdataHint = hint.NewHint(S3BlockdataType, "v0.0.1")
+)
+
+var DefaultRequestTimeout = time.Second * 30
+
+var publicReadCheckKey = ".public-read-check"
+
+// Blockdata stores the block data into the bucket of S3 compatible storage.
+// Session writes the block data files under the local root directory like
+// localfs.Session and SaveSession uploads them. The items of BlockdataMap
+// have the remote url of object, so the other nodes can fetch the block data
+// directly from storage.
+//
+// The object key follows the directory layout of localfs.Blockdata,
+// "<prefix>/<height directory>/<block data file>".
+//
+// The other nodes fetch the item url without credential, so the objects should
+// be readable anonymously, by public bucket or public url; Initialize rejects
+// the bucket, which is not publicly readable.
+type Blockdata struct {
+	sync.RWMutex
+	client    *Client
+	prefix    string
+	publicURL string
+	root      string
+	encoder   *jsonenc.Encoder
+	writer    blockdata.Writer
+	fs        FS
+}
+
+// NewBlockdata creates new s3 Blockdata. root is the local directory for
+// sessions. If publicURL is empty, the item url of BlockdataMap is the object
+// url of client.
+func NewBlockdata(
+	client *Client,
+	prefix string,
+	publicURL string,
+	root string,
+	encoder *jsonenc.Encoder,
+) *Blockdata {
+	prefix = strings.Trim(prefix, "/")
+
+	return &Blockdata{
+		client:    client,
+		prefix:    prefix,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		root:      root,
+		encoder:   encoder,
+		writer:    blockdata.NewDefaultWriter(encoder),
+		fs:        NewFS(client, prefix),
+	}
+}
+
+// NewBlockdataFromURI creates new s3 Blockdata from uri,
+// "s3://<access key>:<secret key>@<host>/<bucket>/<prefix>?region=<region>&public=<public url>".
+// "s3" scheme connects to endpoint thru https and "s3+http" thru http.
+func NewBlockdataFromURI(uri string, root string, encoder *jsonenc.Encoder) (*Blockdata, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid s3 blockdata uri")
+	}
+
+	endpoint := &url.URL{Host: u.Host}
+	switch u.Scheme {
+	case "s3":
+		endpoint.Scheme = "https"
+	case "s3+http":
+		endpoint.Scheme = "http"
+	default:
+		return nil, errors.Errorf("not s3 blockdata uri, %q", u.Scheme)
+	}
+
+	var bucket, prefix string
+	if n := strings.SplitN(strings.TrimLeft(u.Path, "/"), "/", 2); len(n) > 0 {
+		bucket = n[0]
+		if len(n) > 1 {
+			prefix = n[1]
+		}
+	}
+
+	var accessKey, secretKey string
+	if u.User != nil {
+		accessKey = u.User.Username()
+		secretKey, _ = u.User.Password()
+	}
+
+	q := u.Query()
+
+	client, err := NewClient(endpoint, bucket, q.Get("region"), accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBlockdata(client, prefix, q.Get("public"), root, encoder), nil
+}
+
+func (st *Blockdata) Initialize() error {
+	i, err := filepath.Abs(st.root)
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+	st.root = i
+
+	if fi, err := os.Stat(st.root); err != nil {
+		return storage.MergeFSError(err)
+	} else if !fi.IsDir() {
+		return storage.FSError.Errorf("storage root, %q is not directory", st.root)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+
+	if err := st.client.List(ctx, st.prefix, 1, func(string) (bool, error) {
+		return false, nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to access bucket, %q", st.client.Bucket())
+	}
+
+	return st.checkPublicRead(ctx)
+}
+
+func (*Blockdata) Hint() hint.Hint {
+	return S3BlockdataHint
+}
+
+func (*Blockdata) IsLocal() bool {
+	return false
+}
+
+func (st *Blockdata) Writer() blockdata.Writer {
+	return st.writer
+}
+
+func (st *Blockdata) Client() *Client {
+	return st.client
+}
+
+func (st *Blockdata) Exists(height base.Height) (bool, error) {
+	st.RLock()
+	defer st.RUnlock()
+
+	switch found, removed, err := st.exists(height); {
+	case err != nil:
+		return found, err
+	case removed:
+		return false, nil
+	default:
+		return found, nil
+	}
+}
+
+// Remove puts the .removed object under the height directory like
+// localfs.Blockdata; the objects are not removed.
+func (st *Blockdata) Remove(height base.Height) error {
+	st.Lock()
+	defer st.Unlock()
+
+	switch found, removed, err := st.exists(height); {
+	case err != nil:
+		return err
+	case removed:
+		return nil
+	case !found:
+		return util.NotFoundError.Errorf("block directory not found, %v", height)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+
+	b := []byte(localtime.RFC3339(localtime.UTCNow()))
+
+	return st.client.Put(
+		ctx,
+		st.key(localfs.HeightDirectory(height), localfs.BlockDirectoryRemovedTouchFile),
+		strings.NewReader(string(b)),
+		int64(len(b)),
+	)
+}
+
+// RemoveAll removes all the objects under the height directory.
+func (st *Blockdata) RemoveAll(height base.Height) error {
+	st.Lock()
+	defer st.Unlock()
+
+	switch found, _, err := st.exists(height); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("block directory not found, %v", height)
+	}
+
+	return st.removeObjects(st.key(localfs.HeightDirectory(height)) + "/")
+}
+
+// Clean removes all the objects under prefix. If remove is true, the local
+// root directory is also removed.
+func (st *Blockdata) Clean(remove bool) error {
+	st.Lock()
+	defer st.Unlock()
+
+	var prefix string
+	if len(st.prefix) > 0 {
+		prefix = st.prefix + "/"
+	}
+
+	if err := st.removeObjects(prefix); err != nil {
+		return err
+	}
+
+	if remove {
+		if err := os.RemoveAll(st.root); err != nil {
+			return storage.MergeFSError(err)
+		}
+	}
+
+	return nil
+}
+
+func (st *Blockdata) NewSession(height base.Height) (blockdata.Session, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	i, err := os.MkdirTemp(st.root, ".session")
+	if err != nil {
+		return nil, storage.MergeFSError(err)
+	}
+
+	return localfs.NewSession(i, st.writer, height)
+}
+
+func (st *Blockdata) SaveSession(session blockdata.Session) (block.BlockdataMap, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	ss, ok := session.(*localfs.Session)
+	if !ok {
+		return nil, errors.Errorf("only localfs.Session only allowed for s3 blockdata, not %T", session)
+	}
+
+	defer func() {
+		_ = ss.Cancel()
+	}()
+
+	mapData, err := ss.Done()
+	if err != nil {
+		return nil, err
+	}
+
+	newMapData, err := st.uploadItemFiles(localfs.HeightDirectory(ss.Height()), mapData)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := newMapData.UpdateHash()
+	if err != nil {
+		return nil, err
+	}
+	newMapData = i
+
+	if err := newMapData.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	return newMapData, nil
+}
+
+func (st *Blockdata) FS() fs.FS {
+	return st.fs
+}
+
+func (st *Blockdata) Root() string {
+	return st.root
+}
+
+// ItemURL returns the url of block data item by the path under the height
+// directory.
+func (st *Blockdata) ItemURL(p string) string {
+	k := st.key(p)
+	if len(st.publicURL) < 1 {
+		return st.client.ObjectURL(k)
+	}
+
+	return st.publicURL + "/" + k
+}
+
+func (st *Blockdata) exists(height base.Height) (exists bool, removed bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+
+	d := st.key(localfs.HeightDirectory(height))
+	removedKey := path.Join(d, localfs.BlockDirectoryRemovedTouchFile)
+
+	if err := st.client.List(ctx, d+"/", 0, func(k string) (bool, error) {
+		exists = true
+		if k == removedKey {
+			removed = true
+
+			return false, nil
+		}
+
+		return true, nil
+	}); err != nil {
+		return false, false, err
+	}
+
+	return exists, removed, nil
+}
+
+func (st *Blockdata) removeObjects(prefix string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+
+	var keys []string
+	if err := st.client.List(ctx, prefix, 0, func(k string) (bool, error) {
+		keys = append(keys, k)
+
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	for i := range keys {
+		if err := st.client.Delete(ctx, keys[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (st *Blockdata) uploadItemFiles(b string, mapData block.BaseBlockdataMap) (block.BaseBlockdataMap, error) {
+	nm := mapData
+	for dataType := range mapData.Items() {
+		item := mapData.Items()[dataType]
+
+		p := path.Join(b, filepath.Base(item.URLBody()))
+		if err := st.uploadFile(item.URLBody(), st.key(p)); err != nil {
+			return nm, err
+		}
+
+		i, err := nm.SetItem(item.SetURL(st.ItemURL(p)))
+		if err != nil {
+			return nm, err
+		}
+		nm = i
+	}
+
+	return nm, nil
+}
+
+func (st *Blockdata) uploadFile(f, key string) error {
+	r, err := os.Open(filepath.Clean(f))
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	fi, err := r.Stat()
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+
+	return st.client.Put(ctx, key, r, fi.Size())
+}
+
+// checkPublicRead uploads the check object and reads it thru the item url
+// without credential.
+func (st *Blockdata) checkPublicRead(ctx context.Context) error {
+	k := st.key(publicReadCheckKey)
+	body := []byte(util.UUID().String())
+
+	if err := st.client.Put(ctx, k, bytes.NewReader(body), int64(len(body))); err != nil {
+		return errors.Wrap(err, "failed to upload public read check object")
+	}
+
+	defer func() {
+		_ = st.client.Delete(ctx, k)
+	}()
+
+	u := st.ItemURL(publicReadCheckKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return storage.ConnectionError.Merge(err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf(
+			"item url, %q can not be read without credential, %q; bucket should be public or public url should be given",
+			u, res.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, int64(len(body))+1))
+	if err != nil {
+		return storage.MergeFSError(err)
+	} else if !bytes.Equal(body, b) {
+		return errors.Errorf("item url, %q returns the different object from bucket", u)
+	}
+
+	return nil
+}
+
+func (st *Blockdata) key(p ...string) string {
+	return strings.TrimLeft(path.Join(append([]string{st.prefix}, p...)...), "/")
+}
@@ -0,0 +1,284 @@
+// +build test
+
+package s3
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlockdata struct {
+	suite.Suite
+	JSONEnc  *jsonenc.Encoder
+	baseRoot string
+	root     string
+	server   *TestServer
+	client   *Client
+}
+
+func (t *testBlockdata) SetupSuite() {
+	encs := encoder.NewEncoders()
+	t.JSONEnc = jsonenc.NewEncoder()
+	_ = encs.AddEncoder(t.JSONEnc)
+
+	_ = encs.TestAddHinter(block.ManifestV0Hinter)
+
+	p, err := os.MkdirTemp("", "s3-")
+	if err != nil {
+		panic(err)
+	}
+
+	t.baseRoot = p
+}
+
+func (t *testBlockdata) SetupTest() {
+	p, err := os.MkdirTemp(t.baseRoot, "s3-")
+	if err != nil {
+		panic(err)
+	}
+
+	t.root = p
+
+	t.server = NewTestServer("mitum", "showme")
+
+	client, err := NewClient(t.server.Endpoint(), "mitum", "", "showme", "findme")
+	t.NoError(err)
+	t.client = client
+}
+
+func (t *testBlockdata) TearDownTest() {
+	t.server.Close()
+}
+
+func (t *testBlockdata) TearDownSuite() {
+	_ = os.RemoveAll(t.baseRoot)
+}
+
+func (t *testBlockdata) newBlockdata(prefix, publicURL string) *Blockdata {
+	st := NewBlockdata(t.client, prefix, publicURL, t.root, t.JSONEnc)
+	t.NoError(st.Initialize())
+
+	return st
+}
+
+func (t *testBlockdata) saveSession(st *Blockdata, height base.Height) (block.BlockdataMap, map[string]string) {
+	blk, err := block.NewTestBlockV0(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	ss, err := st.NewSession(height)
+	t.NoError(err)
+
+	t.NoError(ss.SetManifest(blk.Manifest()))
+
+	bodies := map[string]string{}
+	for i := range block.Blockdata {
+		dataType := block.Blockdata[i]
+		if dataType == block.BlockdataManifest {
+			continue
+		}
+
+		bodies[dataType] = util.UUID().String()
+		_, err := ss.Import(dataType, strings.NewReader(bodies[dataType]))
+		t.NoError(err)
+	}
+
+	bd, err := st.SaveSession(ss)
+	t.NoError(err)
+
+	return bd, bodies
+}
+
+func (t *testBlockdata) TestNew() {
+	st := NewBlockdata(t.client, "", "", t.root, t.JSONEnc)
+	t.Implements((*blockdata.Blockdata)(nil), st)
+	t.NoError(st.Initialize())
+	t.False(st.IsLocal())
+}
+
+func (t *testBlockdata) TestUnknownBucket() {
+	client, err := NewClient(t.server.Endpoint(), "unknown", "", "showme", "findme")
+	t.NoError(err)
+
+	st := NewBlockdata(client, "", "", t.root, t.JSONEnc)
+	err = st.Initialize()
+	t.Error(err)
+	t.Contains(err.Error(), "failed to access bucket")
+}
+
+func (t *testBlockdata) TestPrivateBucket() {
+	t.server.SetPrivate(true)
+
+	st := NewBlockdata(t.client, "", "", t.root, t.JSONEnc)
+	err := st.Initialize()
+	t.Error(err)
+	t.Contains(err.Error(), "can not be read without credential")
+
+	// NOTE check object is removed
+	t.Empty(t.server.Keys())
+
+	// NOTE public url, which can not serve the objects
+	t.server.SetPrivate(false)
+
+	st = NewBlockdata(t.client, "", t.server.URL+"/unknown", t.root, t.JSONEnc)
+	err = st.Initialize()
+	t.Error(err)
+	t.Contains(err.Error(), "can not be read without credential")
+}
+
+func (t *testBlockdata) TestFromURI() {
+	u := t.server.Endpoint()
+	st, err := NewBlockdataFromURI(
+		"s3+http://showme:findme@"+u.Host+"/mitum/a/b?region=ap-northeast-2&public="+t.server.URL+"/mitum/",
+		t.root, t.JSONEnc,
+	)
+	t.NoError(err)
+	t.NoError(st.Initialize())
+
+	t.Equal("mitum", st.client.bucket)
+	t.Equal("a/b", st.prefix)
+	t.Equal("ap-northeast-2", st.client.region)
+	t.Equal("http", st.client.endpoint.Scheme)
+	t.Equal(t.server.URL+"/mitum/a/b/000/000/000/000/000/000/033/c", st.ItemURL("/000/000/000/000/000/000/033/c"))
+
+	_, err = NewBlockdataFromURI("ftp://"+u.Host+"/mitum", t.root, t.JSONEnc)
+	t.Contains(err.Error(), "not s3 blockdata uri")
+}
+
+func (t *testBlockdata) TestSaveSession() {
+	st := t.newBlockdata("blocks", "")
+
+	bd, bodies := t.saveSession(st, 33)
+	t.NoError(bd.IsValid(nil))
+	t.False(bd.IsLocal())
+
+	found, err := st.Exists(33)
+	t.NoError(err)
+	t.True(found)
+
+	// NOTE session directory is removed
+	files, err := os.ReadDir(st.Root())
+	t.NoError(err)
+	t.Empty(files)
+
+	for dataType := range bd.(block.BaseBlockdataMap).Items() {
+		item := bd.(block.BaseBlockdataMap).Items()[dataType]
+
+		t.True(strings.HasPrefix(item.URL(), t.server.URL+"/mitum/blocks"+localfs.HeightDirectory(33)+"/"))
+
+		r, err := network.FetchBlockdataFromRemote(context.Background(), item)
+		t.NoError(err, dataType)
+
+		b, err := io.ReadAll(r)
+		t.NoError(err)
+
+		if dataType == block.BlockdataManifest {
+			m, err := st.Writer().ReadManifest(strings.NewReader(string(b)))
+			t.NoError(err)
+			t.True(bd.Block().Equal(m.Hash()))
+
+			continue
+		}
+
+		t.Equal(bodies[dataType], string(b), dataType)
+	}
+}
+
+func (t *testBlockdata) TestFS() {
+	st := t.newBlockdata("blocks", "")
+
+	bd, _ := t.saveSession(st, 33)
+
+	item := bd.Manifest()
+	p := item.URL()[len(t.server.URL+"/mitum/blocks"):]
+
+	f, err := st.FS().Open(p)
+	t.NoError(err)
+	defer f.Close()
+
+	fi, err := f.Stat()
+	t.NoError(err)
+	t.True(fi.Size() > 0)
+
+	checksum, err := util.GenerateChecksum(f)
+	t.NoError(err)
+	t.Equal(item.Checksum(), checksum)
+
+	_, err = st.FS().Open("/unknown")
+	t.Error(err)
+}
+
+func (t *testBlockdata) TestRemove() {
+	st := t.newBlockdata("", "")
+
+	_, _ = t.saveSession(st, 33)
+
+	found, removed, err := st.exists(33)
+	t.NoError(err)
+	t.True(found)
+	t.False(removed)
+
+	t.NoError(st.Remove(33))
+	found, removed, err = st.exists(33)
+	t.NoError(err)
+	t.True(found)
+	t.True(removed)
+
+	found, err = st.Exists(33)
+	t.NoError(err)
+	t.False(found)
+
+	err = st.Remove(34)
+	t.True(errors.Is(err, util.NotFoundError))
+}
+
+func (t *testBlockdata) TestRemoveAll() {
+	st := t.newBlockdata("", "")
+
+	_, _ = t.saveSession(st, 33)
+	_, _ = t.saveSession(st, 34)
+
+	t.NoError(st.RemoveAll(33))
+	found, removed, err := st.exists(33)
+	t.NoError(err)
+	t.False(found)
+	t.False(removed)
+
+	found, err = st.Exists(34)
+	t.NoError(err)
+	t.True(found)
+}
+
+func (t *testBlockdata) TestClean() {
+	st := t.newBlockdata("blocks", "")
+
+	_, _ = t.saveSession(st, 33)
+	_, _ = t.saveSession(st, 34)
+
+	// NOTE object outside of prefix
+	t.NoError(st.client.Put(context.Background(), "others", strings.NewReader("findme"), 6))
+
+	t.NoError(st.Clean(false))
+	t.Equal([]string{"others"}, t.server.Keys())
+
+	_, err := os.Stat(st.Root())
+	t.NoError(err)
+}
+
+func TestBlockdata(t *testing.T) {
+	suite.Run(t, new(testBlockdata))
+}
@@ -0,0 +1,274 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+var DefaultRegion = "us-east-1"
+
+// Client is the minimal S3 client for block data; it supports the path style
+// addressing, so it works with the S3 compatible storages like MinIO.
+type Client struct {
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+func NewClient(endpoint *url.URL, bucket, region, accessKey, secretKey string) (*Client, error) {
+	switch {
+	case endpoint == nil:
+		return nil, errors.Errorf("empty endpoint")
+	case endpoint.Scheme != "http" && endpoint.Scheme != "https":
+		return nil, errors.Errorf("endpoint should be http or https, not %q", endpoint.Scheme)
+	case len(endpoint.Host) < 1:
+		return nil, errors.Errorf("empty endpoint host")
+	case len(strings.TrimSpace(bucket)) < 1:
+		return nil, errors.Errorf("empty bucket")
+	}
+
+	if len(region) < 1 {
+		region = DefaultRegion
+	}
+
+	return &Client{
+		endpoint:  &url.URL{Scheme: endpoint.Scheme, Host: endpoint.Host},
+		bucket:    strings.TrimSpace(bucket),
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{},
+	}, nil
+}
+
+func (cl *Client) Bucket() string {
+	return cl.bucket
+}
+
+// ObjectURL returns the url of object by key.
+func (cl *Client) ObjectURL(key string) string {
+	u := *cl.endpoint
+	u.Path = "/" + path.Join(cl.bucket, key)
+
+	return u.String()
+}
+
+func (cl *Client) Put(ctx context.Context, key string, r io.ReadSeeker, size int64) error {
+	sha := sha256.New()
+	if _, err := io.Copy(sha, r); err != nil {
+		return storage.MergeFSError(err)
+	} else if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	req, err := cl.newRequest(ctx, http.MethodPut, key, nil, io.NopCloser(r), hex.EncodeToString(sha.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+
+	res, err := cl.do(req)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (cl *Client) Get(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	req, err := cl.newRequest(ctx, http.MethodGet, key, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := cl.do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res.Body, res.ContentLength, nil
+}
+
+func (cl *Client) Exists(ctx context.Context, key string) (bool, error) {
+	req, err := cl.newRequest(ctx, http.MethodHead, key, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return false, err
+	}
+
+	switch res, err := cl.do(req); {
+	case err == nil:
+		_ = res.Body.Close()
+
+		return true, nil
+	case errors.Is(err, util.NotFoundError):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (cl *Client) Delete(ctx context.Context, key string) error {
+	req, err := cl.newRequest(ctx, http.MethodDelete, key, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+
+	switch res, err := cl.do(req); {
+	case err == nil:
+		return res.Body.Close()
+	case errors.Is(err, util.NotFoundError):
+		return nil
+	default:
+		return err
+	}
+}
+
+// List calls callback with the keys of objects, which start with prefix. If
+// callback returns false, List stops.
+func (cl *Client) List(ctx context.Context, prefix string, limit int, callback func(string) (bool, error)) error {
+	var token string
+	for {
+		q := url.Values{}
+		q.Set("list-type", "2")
+		q.Set("prefix", prefix)
+		if limit > 0 {
+			q.Set("max-keys", strconv.Itoa(limit))
+		}
+		if len(token) > 0 {
+			q.Set("continuation-token", token)
+		}
+
+		i, err := cl.list(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		for j := range i.Contents {
+			switch keep, err := callback(i.Contents[j].Key); {
+			case err != nil:
+				return err
+			case !keep:
+				return nil
+			}
+		}
+
+		if !i.IsTruncated || len(i.NextContinuationToken) < 1 {
+			return nil
+		}
+
+		token = i.NextContinuationToken
+	}
+}
+
+func (cl *Client) list(ctx context.Context, q url.Values) (listBucketResult, error) {
+	req, err := cl.newRequest(ctx, http.MethodGet, "", q, nil, emptyPayloadHash)
+	if err != nil {
+		return listBucketResult{}, err
+	}
+
+	res, err := cl.do(req)
+	if err != nil {
+		return listBucketResult{}, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var i listBucketResult
+	if err := xml.NewDecoder(res.Body).Decode(&i); err != nil {
+		return listBucketResult{}, errors.Wrap(err, "failed to decode list objects result")
+	}
+
+	return i, nil
+}
+
+func (cl *Client) newRequest(
+	ctx context.Context,
+	method, key string,
+	q url.Values,
+	body io.ReadCloser,
+	payloadHash string,
+) (*http.Request, error) {
+	u := *cl.endpoint
+	u.Path = "/" + cl.bucket
+	if len(key) > 0 {
+		u.Path = "/" + path.Join(cl.bucket, key)
+	}
+	if q != nil {
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cl.accessKey) > 0 {
+		signRequest(req, cl.region, cl.accessKey, cl.secretKey, payloadHash, localtime.UTCNow())
+	}
+
+	return req, nil
+}
+
+func (cl *Client) do(req *http.Request) (*http.Response, error) {
+	res, err := cl.client.Do(req)
+	if err != nil {
+		return nil, storage.ConnectionError.Merge(err)
+	}
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return res, nil
+	case res.StatusCode == http.StatusNotFound:
+		_ = res.Body.Close()
+
+		return nil, util.NotFoundError.Errorf("%s %q", req.Method, req.URL.Path)
+	default:
+		defer func() {
+			_ = res.Body.Close()
+		}()
+
+		var e responseError
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1<<16)) // nolint:gomnd
+		if xml.NewDecoder(bytes.NewReader(b)).Decode(&e) != nil || len(e.Code) < 1 {
+			return nil, storage.StorageError.Errorf("%s %q: %q", req.Method, req.URL.Path, res.Status)
+		}
+
+		return nil, storage.StorageError.Errorf("%s %q: %q; %s: %s", req.Method, req.URL.Path, res.Status, e.Code, e.Message)
+	}
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Contents              []listBucketObject
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+type listBucketObject struct {
+	Key  string
+	Size int64
+}
+
+type responseError struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string
+	Message string
+}
@@ -0,0 +1,81 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// FS opens the objects under prefix; the path is same with the path under the
+// root directory of localfs.Blockdata.
+type FS struct {
+	client *Client
+	prefix string
+}
+
+func NewFS(client *Client, prefix string) FS {
+	return FS{client: client, prefix: prefix}
+}
+
+func (f FS) Open(p string) (fs.File, error) {
+	k := strings.TrimLeft(path.Join(f.prefix, p), "/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+
+	r, size, err := f.client.Get(ctx, k)
+	if err != nil {
+		cancel()
+
+		return nil, &fs.PathError{Op: "open", Path: p, Err: err}
+	}
+
+	return &file{ReadCloser: r, cancel: cancel, info: fileInfo{name: path.Base(k), size: size}}, nil
+}
+
+type file struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+	info   fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *file) Close() error {
+	defer f.cancel()
+
+	return f.ReadCloser.Close()
+}
+
+type fileInfo struct {
+	name string
+	size int64
+}
+
+func (fi fileInfo) Name() string {
+	return fi.name
+}
+
+func (fi fileInfo) Size() int64 {
+	return fi.size
+}
+
+func (fileInfo) Mode() fs.FileMode {
+	return 0o444
+}
+
+func (fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (fileInfo) IsDir() bool {
+	return false
+}
+
+func (fileInfo) Sys() interface{} {
+	return nil
+}
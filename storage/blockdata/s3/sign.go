@@ -0,0 +1,143 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm     = "AWS4-HMAC-SHA256"
+	signService       = "s3"
+	signTimeFormat    = "20060102T150405Z"
+	signDateFormat    = "20060102"
+	emptyPayloadHash  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	headerContentHash = "X-Amz-Content-Sha256"
+	headerDate        = "X-Amz-Date"
+)
+
+// signRequest signs http request with AWS Signature Version 4. The host and
+// all the headers of request are signed; payloadHash is the hex encoded
+// sha256 of request body.
+func signRequest(r *http.Request, region, accessKey, secretKey, payloadHash string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format(signTimeFormat)
+	scope := strings.Join([]string{t.Format(signDateFormat), region, signService, "aws4_request"}, "/")
+
+	r.Header.Set(headerContentHash, payloadHash)
+	r.Header.Set(headerDate, amzDate)
+
+	signedHeaders, canonicalHeaders := canonicalHeaders(r)
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		canonicalPath(r.URL),
+		canonicalQuery(r.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), []byte(t.Format(signDateFormat)))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(signService))
+	key = hmacSHA256(key, []byte("aws4_request"))
+
+	r.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm,
+		accessKey,
+		scope,
+		signedHeaders,
+		hex.EncodeToString(hmacSHA256(key, []byte(stringToSign))),
+	))
+}
+
+func canonicalHeaders(r *http.Request) (string /* signed headers */, string /* canonical headers */) {
+	host := r.Host
+	if len(host) < 1 {
+		host = r.URL.Host
+	}
+
+	m := map[string]string{"host": host}
+	for k := range r.Header {
+		m[strings.ToLower(k)] = strings.TrimSpace(strings.Join(r.Header.Values(k), ","))
+	}
+
+	keys := make([]string, len(m))
+	var i int
+	for k := range m {
+		keys[i] = k
+		i++
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for i := range keys {
+		sb.WriteString(keys[i] + ":" + m[keys[i]] + "\n")
+	}
+
+	return strings.Join(keys, ";"), sb.String()
+}
+
+func canonicalPath(u *url.URL) string {
+	p := u.EscapedPath()
+	if len(p) < 1 {
+		return "/"
+	}
+
+	return p
+}
+
+func canonicalQuery(u *url.URL) string {
+	q := u.Query()
+
+	keys := make([]string, len(q))
+	var i int
+	for k := range q {
+		keys[i] = k
+		i++
+	}
+	sort.Strings(keys)
+
+	var l []string
+	for i := range keys {
+		vs := q[keys[i]]
+		sort.Strings(vs)
+		for j := range vs {
+			l = append(l, uriEncode(keys[i])+"="+uriEncode(vs[j]))
+		}
+	}
+
+	return strings.Join(l, "&")
+}
+
+func uriEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hexSHA256(b []byte) string {
+	h := sha256.Sum256(b)
+
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key, b []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write(b)
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,173 @@
+// +build test
+
+package s3
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TestServer is the in-memory S3 compatible storage for testing. The objects
+// can be read without credential like public bucket unless private is set.
+type TestServer struct {
+	*httptest.Server
+	sync.RWMutex
+	bucket    string
+	accessKey string
+	objects   map[string][]byte
+	private   bool
+}
+
+func NewTestServer(bucket, accessKey string) *TestServer {
+	ts := &TestServer{
+		bucket:    bucket,
+		accessKey: accessKey,
+		objects:   map[string][]byte{},
+	}
+	ts.Server = httptest.NewServer(http.HandlerFunc(ts.handle))
+
+	return ts
+}
+
+func (ts *TestServer) Endpoint() *url.URL {
+	u, _ := url.Parse(ts.URL)
+
+	return u
+}
+
+// SetPrivate makes the objects not to be read without credential.
+func (ts *TestServer) SetPrivate(private bool) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ts.private = private
+}
+
+func (ts *TestServer) isPrivate() bool {
+	ts.RLock()
+	defer ts.RUnlock()
+
+	return ts.private
+}
+
+func (ts *TestServer) Object(key string) ([]byte, bool) {
+	ts.RLock()
+	defer ts.RUnlock()
+
+	b, found := ts.objects[key]
+
+	return b, found
+}
+
+func (ts *TestServer) Keys() []string {
+	ts.RLock()
+	defer ts.RUnlock()
+
+	return ts.keys("")
+}
+
+func (ts *TestServer) handle(w http.ResponseWriter, r *http.Request) {
+	n := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if n[0] != ts.bucket {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	var key string
+	if len(n) > 1 {
+		key = n[1]
+	}
+
+	if r.Method != http.MethodGet || len(key) < 1 || ts.isPrivate() {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential="+ts.accessKey+"/") {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+	}
+
+	switch {
+	case len(key) < 1 && r.Method == http.MethodGet:
+		ts.list(w, r)
+	case r.Method == http.MethodPut:
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		ts.Lock()
+		ts.objects[key] = b
+		ts.Unlock()
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		b, found := ts.Object(key)
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	case r.Method == http.MethodDelete:
+		ts.Lock()
+		delete(ts.objects, key)
+		ts.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (ts *TestServer) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	ts.RLock()
+	keys := ts.keys(q.Get("prefix"))
+	ts.RUnlock()
+
+	if t := q.Get("continuation-token"); len(t) > 0 {
+		i := sort.SearchStrings(keys, t)
+		keys = keys[i:]
+	}
+
+	var result listBucketResult
+	if i, err := strconv.Atoi(q.Get("max-keys")); err == nil && i > 0 && len(keys) > i {
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[i]
+		keys = keys[:i]
+	}
+
+	result.Contents = make([]listBucketObject, len(keys))
+	for i := range keys {
+		result.Contents[i] = listBucketObject{Key: keys[i]}
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func (ts *TestServer) keys(prefix string) []string {
+	var keys []string
+	for k := range ts.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
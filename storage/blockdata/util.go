@@ -1,9 +1,14 @@
 package blockdata
 
 import (
+	"context"
+	"io"
+
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
 )
@@ -51,3 +56,164 @@ func CheckBlock(db storage.Database, blockdata Blockdata, networkID base.Network
 
 	return m, nil
 }
+
+// OpenItem opens the block data item; the local item is read from
+// Blockdata.FS() and the remote item is fetched from it's url. The returned
+// reader is already decompressed.
+func OpenItem(ctx context.Context, blockdata Blockdata, item block.BlockdataMapItem) (io.ReadCloser, error) {
+	if !block.IsLocalBlockdataItem(item.URL()) {
+		return network.FetchBlockdataFromRemote(ctx, item)
+	}
+
+	u, err := network.ParseURL(item.URL(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := blockdata.FS().Open(u.Path)
+	if err != nil {
+		return nil, storage.MergeFSError(err)
+	}
+
+	r, err := util.NewGzipReader(f)
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// LoadBlock loads the whole block from the items of block data map.
+func LoadBlock(ctx context.Context, blockdata Blockdata, bdm block.BlockdataMap) (block.Block, error) {
+	blk := (interface{})(block.EmptyBlockV0()).(block.BlockUpdater)
+
+	wr := blockdata.Writer()
+
+	items := []block.BlockdataMapItem{
+		bdm.Manifest(),
+		bdm.Operations(),
+		bdm.OperationsTree(),
+		bdm.States(),
+		bdm.StatesTree(),
+		bdm.INITVoteproof(),
+		bdm.ACCEPTVoteproof(),
+		bdm.SuffrageInfo(),
+		bdm.Proposal(),
+	}
+
+	for i := range items {
+		item := items[i]
+
+		if err := func() error {
+			r, err := OpenItem(ctx, blockdata, item)
+			if err != nil {
+				return errors.Wrapf(err, "failed to open block data item, %q", item.Type())
+			}
+
+			defer func() {
+				_ = r.Close()
+			}()
+
+			j, err := ReadItem(wr, blk, item.Type(), r)
+			if err != nil {
+				return err
+			}
+			blk = j
+
+			return nil
+		}(); err != nil {
+			return nil, err
+		}
+	}
+
+	return blk.(block.Block), nil
+}
+
+// LoadOperations loads the operations of block from the operations item of
+// block data map.
+func LoadOperations(ctx context.Context, blockdata Blockdata, bdm block.BlockdataMap) ([]operation.Operation, error) {
+	r, err := OpenItem(ctx, blockdata, bdm.Operations())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open block data item, %q", bdm.Operations().Type())
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return blockdata.Writer().ReadOperations(r)
+}
+
+// ReadItem reads the block data item of dataType by Writer and sets it to
+// the block. The empty item is not set.
+func ReadItem(wr Writer, blk block.BlockUpdater, dataType string, r io.Reader) (block.BlockUpdater, error) {
+	switch dataType {
+	case block.BlockdataManifest:
+		i, err := wr.ReadManifest(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return blk.SetManifest(i), nil
+	case block.BlockdataOperations:
+		i, err := wr.ReadOperations(r)
+		if err != nil || i == nil {
+			return blk, err
+		}
+
+		return blk.SetOperations(i), nil
+	case block.BlockdataOperationsTree:
+		i, err := wr.ReadOperationsTree(r)
+		if err != nil || i.Len() < 1 {
+			return blk, err
+		}
+
+		return blk.SetOperationsTree(i), nil
+	case block.BlockdataStates:
+		i, err := wr.ReadStates(r)
+		if err != nil || i == nil {
+			return blk, err
+		}
+
+		return blk.SetStates(i), nil
+	case block.BlockdataStatesTree:
+		i, err := wr.ReadStatesTree(r)
+		if err != nil || i.Len() < 1 {
+			return blk, err
+		}
+
+		return blk.SetStatesTree(i), nil
+	case block.BlockdataINITVoteproof:
+		i, err := wr.ReadINITVoteproof(r)
+		if err != nil || i == nil {
+			return blk, err
+		}
+
+		return blk.SetINITVoteproof(i), nil
+	case block.BlockdataACCEPTVoteproof:
+		i, err := wr.ReadACCEPTVoteproof(r)
+		if err != nil || i == nil {
+			return blk, err
+		}
+
+		return blk.SetACCEPTVoteproof(i), nil
+	case block.BlockdataSuffrageInfo:
+		i, err := wr.ReadSuffrageInfo(r)
+		if err != nil || i == nil {
+			return blk, err
+		}
+
+		return blk.SetSuffrageInfo(i), nil
+	case block.BlockdataProposal:
+		i, err := wr.ReadProposal(r)
+		if err != nil || i == nil {
+			return blk, err
+		}
+
+		return blk.SetProposal(i), nil
+	default:
+		return nil, errors.Errorf("unknown data type found, %q", dataType)
+	}
+}
@@ -1,19 +1,30 @@
 package leveldbstorage
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/tree"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/syndtr/goleveldb/leveldb"
 	leveldbStorage "github.com/syndtr/goleveldb/leveldb/storage"
@@ -38,6 +49,13 @@ var (
 	keyPrefixInfo                           []byte = []byte{0x00, 0x14}
 	keyPrefixStagedOperationFactHash        []byte = []byte{0x00, 0x15}
 	keyPrefixStagedOperationFactHashReverse []byte = []byte{0x00, 0x16}
+	keyPrefixOperationStatus                []byte = []byte{0x00, 0x17}
+	keyPrefixOperation                      []byte = []byte{0x00, 0x18}
+	keyPrefixOperationSigner                []byte = []byte{0x00, 0x19}
+	keyPrefixOperationHint                  []byte = []byte{0x00, 0x20}
+	keyPrefixOutbox                         []byte = []byte{0x00, 0x21}
+	keyPrefixEquivocation                   []byte = []byte{0x00, 0x22}
+	keyPrefixStagedOperationTime            []byte = []byte{0x00, 0x23}
 )
 
 type Database struct {
@@ -63,6 +81,46 @@ func NewMemDatabase(encs *encoder.Encoders, enc encoder.Encoder) *Database {
 	return NewDatabase(db, encs, enc)
 }
 
+// NewDatabaseFromURI opens leveldb database from uri. "leveldb:///path/to/dir"
+// opens(or creates) the file database in the given directory and
+// "leveldb+mem://" creates new in-memory database.
+func NewDatabaseFromURI(uri string, encs *encoder.Encoders) (*Database, error) {
+	if len(strings.TrimSpace(uri)) < 1 {
+		return nil, errors.Errorf("empty storage uri")
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid storage uri")
+	}
+
+	var je encoder.Encoder
+	if e, err := encs.Encoder(jsonenc.JSONEncoderType, ""); err != nil { // NOTE get latest json encoder
+		return nil, errors.Wrap(err, "json encoder needs for leveldb")
+	} else {
+		je = e
+	}
+
+	switch parsed.Scheme {
+	case "leveldb+mem":
+		return NewMemDatabase(encs, je), nil
+	case "leveldb":
+		p := parsed.Host + parsed.Path
+		if len(strings.TrimSpace(p)) < 1 {
+			return nil, errors.Errorf("empty path in leveldb uri: %q", uri)
+		}
+
+		db, err := leveldb.OpenFile(p, nil)
+		if err != nil {
+			return nil, errors.Wrapf(mergeError(err), "failed to open leveldb, %q", p)
+		}
+
+		return NewDatabase(db, encs, je), nil
+	default:
+		return nil, errors.Errorf("not leveldb uri: %q", uri)
+	}
+}
+
 func (st *Database) Initialize() error {
 	return nil
 }
@@ -102,32 +160,101 @@ func (st *Database) CleanByHeight(height base.Height) error {
 		return st.Clean()
 	}
 
-	// NOTE not perfectly working as intended :)
 	batch := &leveldb.Batch{}
 
-	h := height
-end:
-	for {
-		switch m, found, err := st.ManifestByHeight(h); {
-		case err != nil:
+	if err := st.iterRange(
+		leveldbHeightRange(keyPrefixBlockHeight, height),
+		func(key, value []byte) (bool, error) {
+			batch.Delete(key)
+
+			h, err := st.loadHash(value)
+			if err != nil {
+				return false, err
+			}
+
+			switch blk, found, err := st.block(h); {
+			case err != nil:
+				return false, err
+			case !found:
+				return true, nil
+			default:
+				return true, st.cleanBlock(batch, blk)
+			}
+		},
+		true,
+	); err != nil {
+		return err
+	}
+
+	for _, prefix := range [][]byte{
+		keyPrefixManifestHeight,
+		keyPrefixINITVoteproof,
+		keyPrefixACCEPTVoteproof,
+		keyPrefixBlockdataMap,
+		keyPrefixOutbox,
+	} {
+		if err := st.iterRange(
+			leveldbHeightRange(prefix, height),
+			func(key, _ []byte) (bool, error) {
+				batch.Delete(key)
+
+				return true, nil
+			},
+			true,
+		); err != nil {
 			return err
-		case !found:
-			break end
-		default:
-			batch.Delete(leveldbBlockHeightKey(h))
-			batch.Delete(leveldbBlockHashKey(m.Hash()))
-			batch.Delete(leveldbManifestHeightKey(h))
-			batch.Delete(leveldbManifestKey(m.Hash()))
-			batch.Delete(leveldbBlockOperationsKey(m))
-			batch.Delete(leveldbBlockStatesKey(m))
 		}
+	}
 
-		h++
+	if err := st.iterRange(
+		leveldbHeightRange(keyPrefixProposalFacts, height),
+		func(key, value []byte) (bool, error) {
+			batch.Delete(key)
+			batch.Delete(value)
+
+			return true, nil
+		},
+		true,
+	); err != nil {
+		return err
 	}
 
 	return mergeError(st.db.Write(batch, nil))
 }
 
+func (st *Database) cleanBlock(batch *leveldb.Batch, blk block.Block) error {
+	batch.Delete(leveldbBlockHashKey(blk.Hash()))
+	batch.Delete(leveldbManifestHeightKey(blk.Height()))
+	batch.Delete(leveldbManifestKey(blk.Hash()))
+	batch.Delete(leveldbBlockOperationsKey(blk))
+	batch.Delete(leveldbBlockStatesKey(blk))
+
+	if tr := blk.OperationsTree(); tr.Len() > 0 {
+		if err := tr.Traverse(func(no tree.FixedTreeNode) (bool, error) {
+			batch.Delete(leveldbOperationFactHashKey(valuehash.NewBytes(no.Key())))
+			batch.Delete(leveldbOperationStatusKey(valuehash.NewBytes(no.Key())))
+
+			return true, nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	ops := blk.Operations()
+	for i := range ops {
+		for _, k := range leveldbOperationKeys(ops[i], blk.Height(), uint64(i)) {
+			batch.Delete(k)
+		}
+	}
+
+	sts := blk.States()
+	for i := range sts {
+		batch.Delete(leveldbStateKey(sts[i].Key(), sts[i].Height()))
+	}
+
+	return nil
+}
+
 func (st *Database) Copy(source storage.Database) error {
 	var sst *Database
 	if s, ok := source.(*Database); !ok {
@@ -200,6 +327,30 @@ func (st *Database) LastManifest() (block.Manifest, bool, error) {
 	return st.Manifest(h)
 }
 
+// lastHeight returns the height of last stored manifest. If nothing stored,
+// returns base.NilHeight.
+func (st *Database) lastHeight() (base.Height, error) {
+	height := base.NilHeight
+
+	if err := st.iter(
+		keyPrefixManifestHeight,
+		func(key, _ []byte) (bool, error) {
+			i, err := base.NewHeightFromString(string(key[len(keyPrefixManifestHeight):]))
+			if err != nil {
+				return false, err
+			}
+			height = i
+
+			return false, nil
+		},
+		false,
+	); err != nil {
+		return base.NilHeight, err
+	}
+
+	return height, nil
+}
+
 func (st *Database) lastBlock() (block.Block, bool, error) {
 	var raw []byte
 
@@ -335,6 +486,13 @@ func (st *Database) newStagedOperationReverseKey(h valuehash.Hash) []byte {
 	)
 }
 
+func (st *Database) newStagedOperationTimeKey(h valuehash.Hash) []byte {
+	return util.ConcatBytesSlice(
+		keyPrefixStagedOperationTime,
+		h.Bytes(),
+	)
+}
+
 func (st *Database) proposalByKey(key []byte) (base.Proposal, bool, error) {
 	b, err := st.get(key)
 	if err != nil {
@@ -420,6 +578,10 @@ func (st *Database) newOperation(batch *leveldb.Batch, op operation.Operation) e
 	k := st.newStagedOperationKey(op.Fact().Hash())
 	batch.Put(k, encodeWithEncoder(raw, st.enc))
 	batch.Put(st.newStagedOperationReverseKey(op.Fact().Hash()), k)
+	batch.Put(
+		st.newStagedOperationTimeKey(op.Fact().Hash()),
+		[]byte(localtime.RFC3339(localtime.UTCNow())),
+	)
 
 	return nil
 }
@@ -550,7 +712,15 @@ func (st *Database) iter(
 	callback func([]byte /* key */, []byte /* value */) (bool, error),
 	sort bool,
 ) error {
-	iter := st.db.NewIterator(leveldbutil.BytesPrefix(prefix), nil)
+	return st.iterRange(leveldbutil.BytesPrefix(prefix), callback, sort)
+}
+
+func (st *Database) iterRange(
+	r *leveldbutil.Range,
+	callback func([]byte /* key */, []byte /* value */) (bool, error),
+	sort bool,
+) error {
+	iter := st.db.NewIterator(r, nil)
 	defer iter.Release()
 
 	var seek func() bool
@@ -587,6 +757,24 @@ func (st *Database) HasStagedOperation(fact valuehash.Hash) (bool, error) {
 	return found, mergeError(err)
 }
 
+func (st *Database) StagedOperationTime(fact valuehash.Hash) (time.Time, bool, error) {
+	b, err := st.get(st.newStagedOperationTimeKey(fact))
+	if err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return time.Time{}, false, nil
+		}
+
+		return time.Time{}, false, err
+	}
+
+	t, err := localtime.ParseRFC3339(string(b))
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, "invalid staged operation time")
+	}
+
+	return t, true, nil
+}
+
 func (st *Database) StagedOperationsByFact(facts []valuehash.Hash) ([]operation.Operation, error) {
 	var ops []operation.Operation
 	for i := range facts {
@@ -643,6 +831,123 @@ func (st *Database) UnstagedOperations(facts []valuehash.Hash) error {
 	return mergeError(st.db.Write(batch, nil))
 }
 
+func (st *Database) SetOperationStatuses(statuses []operation.StatusV0) error {
+	batch := &leveldb.Batch{}
+
+	for i := range statuses {
+		status := statuses[i]
+
+		switch old, found, err := st.OperationStatus(status.Fact()); {
+		case err != nil:
+			return err
+		case found && !old.Status().CanUpdate(status.Status()):
+			continue
+		}
+
+		raw, err := st.enc.Marshal(status)
+		if err != nil {
+			return err
+		}
+
+		batch.Put(leveldbOperationStatusKey(status.Fact()), encodeWithEncoder(raw, st.enc))
+	}
+
+	if batch.Len() < 1 {
+		return nil
+	}
+
+	return mergeError(st.db.Write(batch, nil))
+}
+
+func (st *Database) Outbox(callback func(base.Height) (bool, error)) error {
+	return st.iter(
+		keyPrefixOutbox,
+		func(key, _ []byte) (bool, error) {
+			height, err := base.NewHeightFromString(string(key[len(keyPrefixOutbox):]))
+			if err != nil {
+				return false, errors.Wrap(err, "invalid outbox key")
+			}
+
+			return callback(height)
+		},
+		true,
+	)
+}
+
+func (st *Database) RemoveOutbox(heights []base.Height) error {
+	if len(heights) < 1 {
+		return nil
+	}
+
+	batch := &leveldb.Batch{}
+	for i := range heights {
+		batch.Delete(leveldbOutboxKey(heights[i]))
+	}
+
+	return mergeError(st.db.Write(batch, nil))
+}
+
+func (st *Database) OperationStatus(fact valuehash.Hash) (operation.StatusV0, bool, error) {
+	b, err := st.get(leveldbOperationStatusKey(fact))
+	if err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return operation.StatusV0{}, false, nil
+		}
+
+		return operation.StatusV0{}, false, err
+	}
+
+	hinter, err := st.loadHinter(b)
+	if err != nil {
+		return operation.StatusV0{}, false, err
+	}
+
+	i, ok := hinter.(operation.StatusV0)
+	if !ok {
+		return operation.StatusV0{}, false, errors.Errorf("not operation.StatusV0: %T", hinter)
+	}
+
+	return i, true, nil
+}
+
+func (st *Database) SetEquivocation(ev base.EquivocationV0) error {
+	key := leveldbEquivocationKey(ev)
+
+	switch found, err := st.db.Has(key, nil); {
+	case err != nil:
+		return mergeError(err)
+	case found:
+		return nil
+	}
+
+	raw, err := st.enc.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	return mergeError(st.db.Put(key, encodeWithEncoder(raw, st.enc), nil))
+}
+
+func (st *Database) Equivocations(callback func(base.EquivocationV0) (bool, error), sort bool) error {
+	return st.iter(
+		keyPrefixEquivocation,
+		func(_, value []byte) (bool, error) {
+			hinter, err := st.loadHinter(value)
+			if err != nil {
+				return false, err
+			}
+
+			i, ok := hinter.(base.EquivocationV0)
+			if !ok {
+				return false, errors.Errorf("not base.EquivocationV0: %T", hinter)
+			}
+
+			return callback(i)
+		},
+		sort,
+	)
+}
+
 func (st *Database) Proposals(callback func(base.Proposal) (bool, error), sort bool) error {
 	return st.iter(
 		keyPrefixProposal,
@@ -666,7 +971,7 @@ func (st *Database) proposalKey(h valuehash.Hash) []byte {
 }
 
 func (st *Database) proposalFactsKey(height base.Height, round base.Round, proposer base.Address) []byte {
-	return util.ConcatBytesSlice(keyPrefixProposalFacts, height.Bytes(), round.Bytes(), proposer.Bytes())
+	return util.ConcatBytesSlice(keyPrefixProposalFacts, leveldbHeightBytes(height), round.Bytes(), proposer.Bytes())
 }
 
 func (st *Database) NewProposal(proposal base.Proposal) error {
@@ -711,24 +1016,170 @@ func (st *Database) ProposalByPoint(height base.Height, round base.Round, propos
 }
 
 func (st *Database) State(key string) (state.State, bool, error) {
-	b, err := st.get(leveldbStateKey(key))
+	var raw []byte
+	if err := st.iter(
+		leveldbStateKeyPrefix(key),
+		func(_, value []byte) (bool, error) {
+			raw = value
+
+			return false, nil
+		},
+		false,
+	); err != nil {
+		return nil, false, err
+	}
+
+	if raw == nil {
+		return nil, false, nil
+	}
+
+	stt, err := st.loadState(raw)
 	if err != nil {
-		if errors.Is(err, util.NotFoundError) {
-			return nil, false, nil
-		}
+		return nil, false, err
+	}
+
+	return stt, stt != nil, nil
+}
+
+func (st *Database) StateAt(key string, height base.Height) (state.State, bool, error) {
+	switch lastHeight, err := st.lastHeight(); {
+	case err != nil:
+		return nil, false, err
+	case height > lastHeight:
+		height = lastHeight
+	}
+
+	var raw []byte
+	if err := st.iterRange(
+		&leveldbutil.Range{
+			Start: leveldbStateKeyPrefix(key),
+			Limit: leveldbStateKey(key, height+1),
+		},
+		func(_, value []byte) (bool, error) {
+			raw = value
+
+			return false, nil
+		},
+		false,
+	); err != nil {
+		return nil, false, err
+	}
 
+	if raw == nil {
+		return nil, false, nil
+	}
+
+	stt, err := st.loadState(raw)
+	if err != nil {
 		return nil, false, err
 	}
 
-	stt, err := st.loadState(b)
+	return stt, stt != nil, nil
+}
+
+func (st *Database) StateHistory(
+	key string,
+	from, to base.Height,
+	callback func(state.State) (bool, error),
+) error {
+	if from > to {
+		return errors.Errorf("invalid height range; from, %d > to, %d", from, to)
+	}
+
+	switch lastHeight, err := st.lastHeight(); {
+	case err != nil:
+		return err
+	case to > lastHeight:
+		to = lastHeight
+	}
+
+	if from > to {
+		return nil
+	}
+
+	return st.iterRange(
+		&leveldbutil.Range{
+			Start: leveldbStateKey(key, from),
+			Limit: leveldbStateKey(key, to+1),
+		},
+		func(_, value []byte) (bool, error) {
+			stt, err := st.loadState(value)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(stt)
+		},
+		true,
+	)
+}
+
+func (st *Database) States(height base.Height, callback func(state.State) (bool, error)) error {
+	hl := len(leveldbHeightBytes(height))
+
+	var lastKey string
+	var last []byte
+	var stopped bool
+
+	flush := func() (bool, error) {
+		if last == nil {
+			return true, nil
+		}
+
+		stt, err := st.loadState(last)
+		last = nil
+		if err != nil {
+			return false, err
+		}
+
+		return callback(stt)
+	}
+
+	if err := st.iter(
+		keyPrefixState,
+		func(key, value []byte) (bool, error) {
+			if len(key) < len(keyPrefixState)+hl+1 {
+				return false, errors.Errorf("invalid state key, %q", key)
+			}
+
+			k := string(key[len(keyPrefixState) : len(key)-hl-1])
+			if k != lastKey {
+				switch keep, err := flush(); {
+				case err != nil:
+					return false, err
+				case !keep:
+					stopped = true
+
+					return false, nil
+				}
+
+				lastKey = k
+			}
+
+			if bytes.Compare(key, leveldbStateKey(k, height)) <= 0 {
+				last = value
+			}
+
+			return true, nil
+		},
+		true,
+	); err != nil {
+		return err
+	}
+
+	if stopped {
+		return nil
+	}
 
-	return stt, st != nil, err
+	_, err := flush()
+
+	return err
 }
 
 func (st *Database) NewState(sta state.State) error {
 	if b, err := marshal(sta, st.enc); err != nil {
 		return err
-	} else if err := st.db.Put(leveldbStateKey(sta.Key()), b, nil); err != nil {
+	} else if err := st.db.Put(leveldbStateKey(sta.Key(), sta.Height()), b, nil); err != nil {
 		return mergeError(err)
 	}
 
@@ -741,6 +1192,171 @@ func (st *Database) HasOperationFact(h valuehash.Hash) (bool, error) {
 	return found, mergeError(err)
 }
 
+func (st *Database) OperationsBySigner(
+	signer key.Publickey,
+	cursor storage.OperationCursor,
+	limit int64,
+) ([]operation.Operation, storage.OperationCursor, error) {
+	prefix := leveldbOperationSignerKeyPrefix(signer)
+
+	r := leveldbutil.BytesPrefix(prefix)
+	if !cursor.IsNil() {
+		r.Start = util.ConcatBytesSlice(prefix, leveldbOperationPositionBytes(cursor.Height, cursor.Index+1))
+	}
+
+	return st.operationsByIndex(r, limit)
+}
+
+func (st *Database) OperationsByHint(
+	ht hint.Type,
+	from, to base.Height,
+	cursor storage.OperationCursor,
+	limit int64,
+) ([]operation.Operation, storage.OperationCursor, error) {
+	if from > to {
+		return nil, storage.NilOperationCursor, errors.Errorf("invalid height range; from, %d > to, %d", from, to)
+	}
+
+	prefix := leveldbOperationHintKeyPrefix(ht)
+
+	start := leveldbOperationPositionBytes(from, 0)
+	if !cursor.IsNil() && cursor.Height >= from {
+		start = leveldbOperationPositionBytes(cursor.Height, cursor.Index+1)
+	}
+
+	return st.operationsByIndex(&leveldbutil.Range{
+		Start: util.ConcatBytesSlice(prefix, start),
+		Limit: util.ConcatBytesSlice(prefix, leveldbHeightBytes(to+1)),
+	}, limit)
+}
+
+// operationsByIndex loads the operations of the index keys in the given range.
+// The index key ends with the position of operation.
+func (st *Database) operationsByIndex(
+	r *leveldbutil.Range,
+	limit int64,
+) ([]operation.Operation, storage.OperationCursor, error) {
+	limit = storage.CheckOperationsLimit(limit)
+
+	var ops []operation.Operation
+	var last storage.OperationCursor
+	var more bool
+	if err := st.iterRange(
+		r,
+		func(key, _ []byte) (bool, error) {
+			if int64(len(ops)) == limit {
+				more = true
+
+				return false, nil
+			}
+
+			height, index, err := leveldbParseOperationPosition(key)
+			if err != nil {
+				return false, err
+			}
+
+			b, err := st.get(leveldbOperationKey(height, index))
+			if err != nil {
+				return false, err
+			}
+
+			op, err := st.loadOperation(b)
+			if err != nil {
+				return false, err
+			}
+
+			ops = append(ops, op)
+			last = storage.NewOperationCursor(height, index)
+
+			return true, nil
+		},
+		true,
+	); err != nil {
+		return nil, storage.NilOperationCursor, err
+	}
+
+	if !more {
+		return ops, storage.NilOperationCursor, nil
+	}
+
+	return ops, last, nil
+}
+
+// ReindexOperations stores and indexes the operations of the blocks, which
+// were stored before the operations are indexed by signers and hint type. The
+// operations are loaded from blockdata and the already indexed blocks are
+// skipped. With dryrun, database is not changed. It returns the number of
+// reindexed operations.
+func (st *Database) ReindexOperations(ctx context.Context, bd blockdata.Blockdata, dryrun bool) (uint64, error) {
+	var last base.Height
+	switch m, found, err := st.LastManifest(); {
+	case err != nil:
+		return 0, err
+	case !found:
+		return 0, nil
+	default:
+		last = m.Height()
+	}
+
+	var reindexed uint64
+	for height := base.PreGenesisHeight; height <= last; height++ {
+		// NOTE the blocks under the imported snapshot block are not stored
+		m, found, err := st.ManifestByHeight(height)
+		switch {
+		case err != nil:
+			return 0, err
+		case !found:
+			continue
+		}
+
+		switch hasOperations, err := st.db.Has(leveldbBlockOperationsKey(m), nil); {
+		case err != nil:
+			return 0, mergeError(err)
+		case !hasOperations:
+			continue
+		}
+
+		// NOTE the operations of block are indexed together, so the block,
+		// which has the first operation, is already indexed.
+		switch indexed, err := st.db.Has(leveldbOperationKey(height, 0), nil); {
+		case err != nil:
+			return 0, mergeError(err)
+		case indexed:
+			continue
+		}
+
+		bdm, found, err := st.BlockdataMap(height)
+		switch {
+		case err != nil:
+			return 0, err
+		case !found:
+			return 0, util.NotFoundError.Errorf("block data map, %d not found", height)
+		}
+
+		ops, err := blockdata.LoadOperations(ctx, bd, bdm)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to load operations of block, %d", height)
+		}
+
+		reindexed += uint64(len(ops))
+
+		if dryrun {
+			continue
+		}
+
+		batch := &leveldb.Batch{}
+		if err := leveldbPutOperations(batch, st.enc, ops, height); err != nil {
+			return 0, err
+		}
+
+		if err := st.db.Write(batch, nil); err != nil {
+			return 0, mergeError(err)
+		}
+	}
+
+	return reindexed, nil
+}
+
 func (st *Database) NewSession(blk block.Block) (storage.DatabaseSession, error) {
 	return NewSession(st, blk)
 }
@@ -804,6 +1420,10 @@ func (st *Database) LastVoteproof(stage base.Stage) base.Voteproof {
 func (st *Database) Voteproof(height base.Height, stage base.Stage) (base.Voteproof, error) {
 	var raw []byte
 	if b, err := st.get(leveldbVoteproofKey(height, stage)); err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return nil, nil
+		}
+
 		return nil, err
 	} else {
 		raw = b
@@ -901,17 +1521,32 @@ func (st *Database) newStagedOperationFilter() func(valuehash.Hash) (bool, error
 	}
 }
 
+// leveldbHeightBytes returns the sortable bytes of height. Height.Bytes() is
+// little endian, so it can not be used for ordered keys.
+func leveldbHeightBytes(height base.Height) []byte {
+	return []byte(fmt.Sprintf("%020d", height.Int64()))
+}
+
+// leveldbHeightRange returns the range of keys, which starts from the given
+// height under prefix.
+func leveldbHeightRange(prefix []byte, height base.Height) *leveldbutil.Range {
+	return &leveldbutil.Range{
+		Start: util.ConcatBytesSlice(prefix, leveldbHeightBytes(height)),
+		Limit: leveldbutil.BytesPrefix(prefix).Limit,
+	}
+}
+
 func leveldbBlockHeightKey(height base.Height) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixBlockHeight,
-		[]byte(fmt.Sprintf("%020d", height.Int64())),
+		leveldbHeightBytes(height),
 	)
 }
 
 func leveldbManifestHeightKey(height base.Height) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixManifestHeight,
-		[]byte(fmt.Sprintf("%020d", height.Int64())),
+		leveldbHeightBytes(height),
 	)
 }
 
@@ -932,21 +1567,31 @@ func leveldbManifestKey(h valuehash.Hash) []byte {
 func leveldbBlockOperationsKey(blk block.Manifest) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixBlockOperations,
-		[]byte(fmt.Sprintf("%020d", blk.Height().Int64())),
+		leveldbHeightBytes(blk.Height()),
 	)
 }
 
 func leveldbBlockStatesKey(blk block.Manifest) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixBlockStates,
-		[]byte(fmt.Sprintf("%020d", blk.Height().Int64())),
+		leveldbHeightBytes(blk.Height()),
 	)
 }
 
-func leveldbStateKey(key string) []byte {
+// leveldbStateKeyPrefix returns the prefix of all the states of key. The
+// states are kept by height, so the last one is the latest state.
+func leveldbStateKeyPrefix(key string) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixState,
 		[]byte(key),
+		[]byte{0x00}, // delimiter
+	)
+}
+
+func leveldbStateKey(key string, height base.Height) []byte {
+	return util.ConcatBytesSlice(
+		leveldbStateKeyPrefix(key),
+		leveldbHeightBytes(height),
 	)
 }
 
@@ -957,6 +1602,121 @@ func leveldbOperationFactHashKey(h valuehash.Hash) []byte {
 	)
 }
 
+func leveldbOperationStatusKey(h valuehash.Hash) []byte {
+	return util.ConcatBytesSlice(
+		keyPrefixOperationStatus,
+		h.Bytes(),
+	)
+}
+
+func leveldbEquivocationKey(ev base.EquivocationV0) []byte {
+	return util.ConcatBytesSlice(
+		keyPrefixEquivocation,
+		leveldbHeightBytes(ev.Height()),
+		ev.Round().Bytes(),
+		ev.Stage().Bytes(),
+		ev.Node().Bytes(),
+	)
+}
+
+// leveldbOperationPositionBytes returns the sortable bytes of the position of
+// operation in the stored blocks.
+func leveldbOperationPositionBytes(height base.Height, index uint64) []byte {
+	return util.ConcatBytesSlice(
+		leveldbHeightBytes(height),
+		[]byte(fmt.Sprintf("%020d", index)),
+	)
+}
+
+func leveldbParseOperationPosition(key []byte) (base.Height, uint64, error) {
+	hl := len(leveldbHeightBytes(base.PreGenesisHeight))
+	if len(key) < hl+20 {
+		return base.NilHeight, 0, errors.Errorf("too short operation index key")
+	}
+
+	b := key[len(key)-hl-20:]
+
+	height, err := base.NewHeightFromString(string(b[:hl]))
+	if err != nil {
+		return base.NilHeight, 0, errors.Wrap(err, "invalid height of operation index key")
+	}
+
+	index, err := strconv.ParseUint(string(b[hl:]), 10, 64)
+	if err != nil {
+		return base.NilHeight, 0, errors.Wrap(err, "invalid index of operation index key")
+	}
+
+	return height, index, nil
+}
+
+func leveldbOperationKey(height base.Height, index uint64) []byte {
+	return util.ConcatBytesSlice(
+		keyPrefixOperation,
+		leveldbOperationPositionBytes(height, index),
+	)
+}
+
+func leveldbOperationSignerKeyPrefix(signer key.Publickey) []byte {
+	return util.ConcatBytesSlice(
+		keyPrefixOperationSigner,
+		[]byte(signer.String()),
+		[]byte{0x00}, // delimiter
+	)
+}
+
+func leveldbOperationHintKeyPrefix(ht hint.Type) []byte {
+	return util.ConcatBytesSlice(
+		keyPrefixOperationHint,
+		ht.Bytes(),
+		[]byte{0x00}, // delimiter
+	)
+}
+
+// leveldbOperationKeys returns the keys of operation and it's indexes by
+// signers and hint type.
+func leveldbOperationKeys(op operation.Operation, height base.Height, index uint64) [][]byte {
+	position := leveldbOperationPositionBytes(height, index)
+
+	keys := [][]byte{
+		leveldbOperationKey(height, index),
+		util.ConcatBytesSlice(leveldbOperationHintKeyPrefix(op.Hint().Type()), position),
+	}
+
+	signed := map[string]struct{}{}
+	fs := op.Signs()
+	for i := range fs {
+		signer := fs[i].Signer()
+		if _, found := signed[signer.String()]; found {
+			continue
+		}
+		signed[signer.String()] = struct{}{}
+
+		keys = append(keys, util.ConcatBytesSlice(leveldbOperationSignerKeyPrefix(signer), position))
+	}
+
+	return keys
+}
+
+// leveldbPutOperations puts the operations of block by it's position and the
+// index keys of them into batch.
+func leveldbPutOperations(batch *leveldb.Batch, enc encoder.Encoder, ops []operation.Operation, height base.Height) error {
+	for i := range ops {
+		b, err := marshal(ops[i], enc)
+		if err != nil {
+			return err
+		}
+
+		keys := leveldbOperationKeys(ops[i], height, uint64(i))
+		batch.Put(keys[0], b)
+
+		for j := range keys[1:] {
+			batch.Put(keys[j+1], nil)
+		}
+	}
+
+	return nil
+}
+
 func leveldbVoteproofKey(height base.Height, stage base.Stage) []byte {
 	var prefix []byte
 	switch stage {
@@ -970,12 +1730,16 @@ func leveldbVoteproofKey(height base.Height, stage base.Stage) []byte {
 
 	return util.ConcatBytesSlice(
 		prefix,
-		[]byte(fmt.Sprintf("%020d", height.Int64())),
+		leveldbHeightBytes(height),
 	)
 }
 
 func leveldbBlockdataMapKey(height base.Height) []byte {
-	return util.ConcatBytesSlice(keyPrefixBlockdataMap, height.Bytes())
+	return util.ConcatBytesSlice(keyPrefixBlockdataMap, leveldbHeightBytes(height))
+}
+
+func leveldbOutboxKey(height base.Height) []byte {
+	return util.ConcatBytesSlice(keyPrefixOutbox, leveldbHeightBytes(height))
 }
 
 func leveldbUnstageOperations(st *Database, batch *leveldb.Batch, facts []valuehash.Hash) error {
@@ -995,6 +1759,7 @@ func leveldbUnstageOperations(st *Database, batch *leveldb.Batch, facts []valueh
 			return err
 		}
 		batch.Delete(b)
+		batch.Delete(st.newStagedOperationTimeKey(facts[i]))
 	}
 
 	return nil
@@ -2,64 +2,51 @@ package leveldbstorage
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/storage"
-	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/stretchr/testify/suite"
 )
 
 type testDatabase struct {
-	storage.BaseTestDatabase
+	storage.DatabaseTestSuite
 	database *Database
+	others   []*Database
 }
 
 func (t *testDatabase) SetupTest() {
 	t.database = NewMemDatabase(t.Encs, t.JSONEnc)
-}
+	t.others = nil
 
-func (t *testDatabase) TestNew() {
-	t.Implements((*storage.Database)(nil), t.database)
-}
+	t.Database = t.database
+	t.NewDatabase = func() storage.Database {
+		st := NewMemDatabase(t.Encs, t.JSONEnc)
+		t.others = append(t.others, st)
 
-func (t *testDatabase) saveBlockdataMap(st *Database, bd block.BlockdataMap) error {
-	if b, err := marshal(bd, st.enc); err != nil {
-		return err
-	} else {
-		return st.db.Put(leveldbBlockdataMapKey(bd.Height()), b, nil)
+		return st
 	}
 }
 
-func (t *testDatabase) TestLastBlock() {
-	// store first
-	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
-	t.NoError(err)
-
-	bs, err := t.database.NewSession(blk)
-	t.NoError(err)
-	t.NoError(bs.SetBlock(context.Background(), blk))
-
-	bd := t.NewBlockdataMap(blk.Height(), blk.Hash(), true)
-	t.NoError(bs.Commit(context.Background(), bd))
-
-	loaded, found, err := t.database.lastBlock()
-	t.NoError(err)
-	t.True(found)
-
-	t.CompareBlock(blk, loaded)
+func (t *testDatabase) TearDownTest() {
+	for i := range t.others {
+		_ = t.others[i].Close()
+	}
 
-	ubd, found, err := t.database.BlockdataMap(blk.Height())
-	t.NoError(err)
-	t.True(found)
+	_ = t.database.Close()
+}
 
-	block.CompareBlockdataMap(t.Assert(), bd, ubd)
+func (t *testDatabase) TestNew() {
+	t.Implements((*storage.Database)(nil), t.database)
 }
 
-func (t *testDatabase) TestLastManifest() {
+func (t *testDatabase) TestLoadLastBlock() {
 	// store first
 	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
 	t.NoError(err)
@@ -69,11 +56,11 @@ func (t *testDatabase) TestLastManifest() {
 	t.NoError(bs.SetBlock(context.Background(), blk))
 	t.NoError(bs.Commit(context.Background(), t.NewBlockdataMap(blk.Height(), blk.Hash(), true)))
 
-	loaded, found, err := t.database.LastManifest()
+	loaded, found, err := t.database.lastBlock()
 	t.NoError(err)
 	t.True(found)
 
-	t.CompareManifest(blk.Manifest(), loaded)
+	t.CompareBlock(blk, loaded)
 }
 
 func (t *testDatabase) TestLoadBlockByHash() {
@@ -99,48 +86,6 @@ func (t *testDatabase) TestLoadBlockByHash() {
 	t.CompareBlock(blk, loaded)
 }
 
-func (t *testDatabase) TestLoadManifestByHash() {
-	// store first
-	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
-	t.NoError(err)
-
-	bs, err := t.database.NewSession(blk)
-	t.NoError(err)
-	t.NoError(bs.SetBlock(context.Background(), blk))
-	t.NoError(bs.Commit(context.Background(), t.NewBlockdataMap(blk.Height(), blk.Hash(), true)))
-
-	loaded, found, err := t.database.Manifest(blk.Hash())
-	t.NoError(err)
-	t.True(found)
-
-	t.Implements((*block.Manifest)(nil), loaded)
-	_, isBlock := loaded.(block.Block)
-	t.False(isBlock)
-
-	t.CompareManifest(blk, loaded)
-}
-
-func (t *testDatabase) TestLoadManifestByHeight() {
-	// store first
-	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
-	t.NoError(err)
-
-	bs, err := t.database.NewSession(blk)
-	t.NoError(err)
-	t.NoError(bs.SetBlock(context.Background(), blk))
-	t.NoError(bs.Commit(context.Background(), t.NewBlockdataMap(blk.Height(), blk.Hash(), true)))
-
-	loaded, found, err := t.database.ManifestByHeight(blk.Height())
-	t.NoError(err)
-	t.True(found)
-
-	t.Implements((*block.Manifest)(nil), loaded)
-	_, isBlock := loaded.(block.Block)
-	t.False(isBlock)
-
-	t.CompareManifest(blk, loaded)
-}
-
 func (t *testDatabase) TestLoadBlockByHeight() {
 	// store first
 	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
@@ -158,265 +103,146 @@ func (t *testDatabase) TestLoadBlockByHeight() {
 	t.CompareBlock(blk, loaded)
 }
 
-func (t *testDatabase) TestNewOperationSeals() {
-	var ops []operation.Operation
-	var seals []operation.Seal
-	for i := 0; i < 10; i++ {
-		opsl := t.newOperationSeal()
-		seals = append(seals, opsl)
-
-		l := opsl.Operations()
-		for j := range l {
-			ops = append(ops, l[j])
-		}
-	}
-	t.NoError(t.database.NewOperationSeals(seals))
-
-	var collected []operation.Operation
-	t.NoError(t.database.StagedOperations(
-		func(op operation.Operation) (bool, error) {
-			collected = append(collected, op)
-
-			t.NoError(op.IsValid(nil))
-
-			return true, nil
-		},
-		true,
-	))
+func (t *testDatabase) TestUnstagedOperationsKeys() {
+	ops := t.NewOperationSeals(10)
 
-	t.Equal(len(ops), len(collected))
-
-	for i := range collected {
-		a := ops[i]
-		b := collected[i]
-
-		found, err := t.database.HasStagedOperation(b.Fact().Hash())
-		t.NoError(err)
-		t.True(found)
+	count := func() int {
+		var n int
+		_ = t.database.iter(
+			nil,
+			func(key, _ []byte) (bool, error) {
+				n++
+				return true, nil
+			},
+			false,
+		)
 
-		t.True(a.Hash().Equal(b.Hash()))
-		t.True(a.Fact().Hash().Equal(b.Fact().Hash()))
+		return n
 	}
-}
-
-func (t *testDatabase) TestStagedOperationsByFact() {
-	var ops []operation.Operation
-	var seals []operation.Seal
-	for i := 0; i < 10; i++ {
-		opsl := t.newOperationSeal()
-		seals = append(seals, opsl)
 
-		l := opsl.Operations()
-		for j := range l {
-			ops = append(ops, l[j])
-		}
-	}
-	t.NoError(t.database.NewOperationSeals(seals))
+	inserted := count()
 
 	var facts []valuehash.Hash
 	for i := range ops[:3] {
 		facts = append(facts, ops[i].Fact().Hash())
 	}
 
-	rops, err := t.database.StagedOperationsByFact(facts)
-	t.NoError(err)
-
-	t.Equal(3, len(rops))
-
-	for i := range rops {
-		op := rops[i]
-
-		t.True(facts[i].Equal(op.Fact().Hash()))
-	}
+	t.NoError(t.database.UnstagedOperations(facts))
 
-	l, err := t.database.StagedOperationsByFact([]valuehash.Hash{valuehash.RandomSHA256()})
-	t.NoError(err)
-	t.Equal(0, len(l))
+	// NOTE the staged operation, reverse and time keys are removed
+	t.Equal(inserted-9, count())
 }
 
-func (t *testDatabase) TestUnstagedOperations() {
-	var ops []operation.Operation
-	var seals []operation.Seal
-	for i := 0; i < 10; i++ {
-		opsl := t.newOperationSeal()
-		seals = append(seals, opsl)
+func (t *testDatabase) TestReindexOperations() {
+	bd := localfs.NewBlockdata(t.T().TempDir(), t.JSONEnc.(*jsonenc.Encoder))
+	t.NoError(bd.Initialize())
 
-		l := opsl.Operations()
-		for j := range l {
-			ops = append(ops, l[j])
-		}
-	}
-	t.NoError(t.database.NewOperationSeals(seals))
-
-	var inserted int
-	_ = t.database.iter(
-		nil,
-		func(key, _ []byte) (bool, error) {
-			inserted++
-			return true, nil
-		},
-		false,
-	)
+	ops := []operation.Operation{t.NewOperation(t.PK), t.NewOperation(t.PK)}
 
-	var facts []valuehash.Hash
-	for i := range ops[:3] {
-		facts = append(facts, ops[i].Fact().Hash())
+	facts := make([]valuehash.Hash, len(ops))
+	for i := range ops {
+		facts[i] = ops[i].Fact().Hash()
 	}
 
-	t.NoError(t.database.UnstagedOperations(facts))
+	blk := (interface{})(t.NewBlock(base.Height(1), nil, facts)).(block.BlockUpdater).SetOperations(ops).(block.Block)
 
-	for i := range facts {
-		found, err := t.database.HasStagedOperation(facts[i])
-		t.NoError(err)
-		t.False(found)
-	}
+	bs, err := bd.NewSession(blk.Height())
+	t.NoError(err)
+	t.NoError(bs.SetBlock(blk))
 
-	l, err := t.database.StagedOperationsByFact(facts)
+	bdm, err := bd.SaveSession(bs)
 	t.NoError(err)
-	t.Equal(0, len(l))
-
-	var lefts int
-	_ = t.database.iter(
-		nil,
-		func(key, _ []byte) (bool, error) {
-			lefts++
-			return true, nil
-		},
-		false,
-	)
-
-	t.Equal(inserted-6, lefts)
-}
 
-func (t *testDatabase) TestStagedOperationsLimit() {
-	var ops []operation.Operation
-	var seals []operation.Seal
-	for i := 0; i < 10; i++ {
-		opsl := t.newOperationSeal()
-		seals = append(seals, opsl)
+	ds, err := t.database.NewSession(blk)
+	t.NoError(err)
+	t.NoError(ds.SetBlock(context.Background(), blk))
+	t.NoError(ds.Commit(context.Background(), bdm))
 
-		l := opsl.Operations()
-		for j := range l {
-			ops = append(ops, l[j])
+	// NOTE remove the operations and their indexes like the block stored
+	// before operation indexes
+	for i := range ops {
+		for _, k := range leveldbOperationKeys(ops[i], blk.Height(), uint64(i)) {
+			t.NoError(t.database.db.Delete(k, nil))
 		}
 	}
-	t.NoError(t.database.NewOperationSeals(seals))
 
-	var collected []valuehash.Hash
-	t.NoError(t.database.StagedOperations(
-		func(op operation.Operation) (bool, error) {
-			if len(collected) == 3 {
-				return false, nil
-			}
-			collected = append(collected, op.Fact().Hash())
-
-			return true, nil
-		},
-		true,
-	))
+	uops, _, err := t.database.OperationsBySigner(t.PK.Publickey(), storage.NilOperationCursor, 0)
+	t.NoError(err)
+	t.Empty(uops)
 
-	t.Equal(3, len(collected))
+	// NOTE dryrun
+	reindexed, err := t.database.ReindexOperations(context.Background(), bd, true)
+	t.NoError(err)
+	t.Equal(uint64(len(ops)), reindexed)
 
-	for i := range collected {
-		a := ops[i].Fact().Hash()
-		b := collected[i]
+	uops, _, err = t.database.OperationsBySigner(t.PK.Publickey(), storage.NilOperationCursor, 0)
+	t.NoError(err)
+	t.Empty(uops)
 
-		t.True(a.Equal(b))
-	}
-}
+	reindexed, err = t.database.ReindexOperations(context.Background(), bd, false)
+	t.NoError(err)
+	t.Equal(uint64(len(ops)), reindexed)
 
-func (t *testDatabase) newOperationSeal() operation.Seal {
-	token := []byte("this-is-token")
-	op, err := operation.NewKVOperation(t.PK, token, util.UUID().String(), []byte(util.UUID().String()), nil)
+	uops, _, err = t.database.OperationsBySigner(t.PK.Publickey(), storage.NilOperationCursor, 0)
 	t.NoError(err)
+	t.CompareOperations(ops, uops)
 
-	sl, err := operation.NewBaseSeal(t.PK, []operation.Operation{op}, nil)
+	uops, _, err = t.database.OperationsByHint(ops[0].Hint().Type(), blk.Height(), blk.Height(), storage.NilOperationCursor, 0)
 	t.NoError(err)
-	t.NoError(sl.IsValid(nil))
+	t.CompareOperations(ops, uops)
 
-	return sl
+	// NOTE already reindexed
+	reindexed, err = t.database.ReindexOperations(context.Background(), bd, false)
+	t.NoError(err)
+	t.Equal(uint64(0), reindexed)
 }
 
-func (t *testDatabase) TestHasOperation() {
-	fact := valuehash.RandomSHA256()
-
-	{ // store
-		raw, err := t.database.enc.Marshal(fact)
+func (t *testDatabase) TestNewDatabaseFromURI() {
+	{ // NOTE memory
+		st, err := NewDatabaseFromURI("leveldb+mem://", t.Encs)
 		t.NoError(err)
-		t.database.db.Put(
-			leveldbOperationFactHashKey(fact),
-			encodeWithEncoder(raw, t.database.enc),
-			nil,
-		)
+		t.NoError(st.Close())
 	}
 
-	{
-		found, err := t.database.HasOperationFact(fact)
+	{ // NOTE file
+		p, err := os.MkdirTemp("", "leveldb-")
 		t.NoError(err)
-		t.True(found)
-	}
+		defer os.RemoveAll(p)
 
-	{ // unknown
-		found, err := t.database.HasOperationFact(valuehash.RandomSHA256())
+		st, err := NewDatabaseFromURI("leveldb://"+p, t.Encs)
 		t.NoError(err)
-		t.False(found)
-	}
-}
-
-func (t *testDatabase) TestInfo() {
-	key := util.UUID().String()
-	b := util.UUID().Bytes()
-
-	_, found, err := t.database.Info(key)
-	t.NoError(err)
-	t.False(found)
 
-	t.NoError(t.database.SetInfo(key, b))
+		t.NoError(st.SetInfo("findme", []byte("showme")))
+		t.NoError(st.Close())
 
-	ub, found, err := t.database.Info(key)
-	t.NoError(err)
-	t.True(found)
-	t.Equal(b, ub)
-
-	nb := util.UUID().Bytes()
-	t.NoError(t.database.SetInfo(key, nb))
+		st, err = NewDatabaseFromURI("leveldb://"+p, t.Encs)
+		t.NoError(err)
 
-	unb, found, err := t.database.Info(key)
-	t.NoError(err)
-	t.True(found)
-	t.Equal(nb, unb)
-}
+		defer st.Close()
 
-func (t *testDatabase) TestLocalBlockdataMapsByHeight() {
-	isLocal := func(height base.Height) bool {
-		switch height {
-		case 33, 36, 39:
-			return true
-		default:
-			return false
-		}
+		b, found, err := st.Info("findme")
+		t.NoError(err)
+		t.True(found)
+		t.Equal([]byte("showme"), b)
 	}
 
-	for i := base.Height(33); i < 40; i++ {
-		bd := t.NewBlockdataMap(i, valuehash.RandomSHA256(), isLocal(i))
-		t.NoError(t.saveBlockdataMap(t.database, bd))
+	{ // NOTE empty path
+		_, err := NewDatabaseFromURI("leveldb://", t.Encs)
+		t.Error(err)
+		t.Contains(err.Error(), "empty path")
 	}
 
-	for i := base.Height(33); i < 40; i++ {
-		bd, found, err := t.database.BlockdataMap(i)
-		t.NoError(err)
-		t.True(found)
-		t.NotNil(bd)
+	{ // NOTE unknown scheme
+		_, err := NewDatabaseFromURI("mongodb://localhost/mitum", t.Encs)
+		t.Error(err)
+		t.Contains(err.Error(), "not leveldb uri")
 	}
 
-	err := t.database.LocalBlockdataMapsByHeight(36, func(bd block.BlockdataMap) (bool, error) {
-		t.True(bd.Height() > 35)
-		t.True(isLocal(bd.Height()))
-		t.True(bd.IsLocal())
-
-		return true, nil
-	})
-	t.NoError(err)
+	{ // NOTE invalid uri
+		_, err := NewDatabaseFromURI("leveldb://%zz", t.Encs)
+		t.Error(err)
+		t.Contains(err.Error(), "invalid storage uri")
+	}
 }
 
 func TestLeveldbDatabase(t *testing.T) {
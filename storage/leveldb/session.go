@@ -32,7 +32,30 @@ func (bst *DatabaseSession) Block() block.Block {
 	return bst.block
 }
 
-func (bst *DatabaseSession) SetBlock(_ context.Context, blk block.Block) error {
+func (bst *DatabaseSession) SetBlock(ctx context.Context, blk block.Block) error {
+	if blk == nil {
+		return errors.Errorf("empty block")
+	}
+
+	batch := &leveldb.Batch{}
+	if err := bst.setBlock(batch, blk); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := batch.Replay(bst.batch); err != nil {
+		return err
+	}
+
+	bst.block = blk
+
+	return nil
+}
+
+func (bst *DatabaseSession) setBlock(batch *leveldb.Batch, blk block.Block) error {
 	if bst.block.Height() != blk.Height() {
 		return errors.Errorf(
 			"block has different height from initial block; initial=%d != block=%d",
@@ -52,41 +75,39 @@ func (bst *DatabaseSession) SetBlock(_ context.Context, blk block.Block) error {
 	if b, err := marshal(blk, bst.st.enc); err != nil {
 		return err
 	} else {
-		bst.batch.Put(leveldbBlockHashKey(blk.Hash()), b)
+		batch.Put(leveldbBlockHashKey(blk.Hash()), b)
 	}
 
 	if b, err := marshal(blk.Manifest(), bst.st.enc); err != nil {
 		return err
 	} else {
 		key := leveldbManifestKey(blk.Hash())
-		bst.batch.Put(key, b)
+		batch.Put(key, b)
 	}
 
 	if b, err := marshal(blk.Hash(), bst.st.enc); err != nil {
 		return err
 	} else {
-		bst.batch.Put(leveldbBlockHeightKey(blk.Height()), b)
-		bst.batch.Put(leveldbManifestHeightKey(blk.Height()), b)
+		batch.Put(leveldbBlockHeightKey(blk.Height()), b)
+		batch.Put(leveldbManifestHeightKey(blk.Height()), b)
 	}
 
-	if err := bst.setOperationsTree(blk.OperationsTree()); err != nil {
+	if err := bst.setOperationsTree(batch, blk, blk.OperationsTree()); err != nil {
 		return err
 	}
 
-	if err := bst.setStates(blk.States()); err != nil {
+	if err := bst.setOperations(batch, blk); err != nil {
 		return err
 	}
 
-	if err := bst.setVoteproofs(blk.ConsensusInfo().INITVoteproof(), blk.ConsensusInfo().ACCEPTVoteproof()); err != nil {
+	if err := bst.setStates(batch, blk.States()); err != nil {
 		return err
 	}
 
-	bst.block = blk
-
-	return nil
+	return bst.setVoteproofs(batch, blk.ConsensusInfo().INITVoteproof(), blk.ConsensusInfo().ACCEPTVoteproof())
 }
 
-func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
+func (bst *DatabaseSession) setOperationsTree(batch *leveldb.Batch, blk block.Block, tr tree.FixedTree) error {
 	if tr.Len() < 1 {
 		return nil
 	}
@@ -94,12 +115,12 @@ func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
 	if b, err := marshal(tr, bst.st.enc); err != nil { // block 1st
 		return err
 	} else {
-		bst.batch.Put(leveldbBlockOperationsKey(bst.block), b)
+		batch.Put(leveldbBlockOperationsKey(blk), b)
 	}
 
 	// store operation hashes
 	if err := tr.Traverse(func(no tree.FixedTreeNode) (bool, error) {
-		bst.batch.Put(leveldbOperationFactHashKey(valuehash.NewBytes(no.Key())), nil)
+		batch.Put(leveldbOperationFactHashKey(valuehash.NewBytes(no.Key())), nil)
 
 		return true, nil
 	}); err != nil {
@@ -109,30 +130,38 @@ func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
 	return nil
 }
 
-func (bst *DatabaseSession) setStates(sts []state.State) error {
+// setOperations stores the operations of block by it's position and indexes
+// them by signers and hint type.
+func (bst *DatabaseSession) setOperations(batch *leveldb.Batch, blk block.Block) error {
+	return leveldbPutOperations(batch, bst.st.enc, blk.Operations(), blk.Height())
+}
+
+func (bst *DatabaseSession) setStates(batch *leveldb.Batch, sts []state.State) error {
 	for i := range sts {
 		if b, err := marshal(sts[i], bst.st.enc); err != nil {
 			return err
 		} else {
-			bst.batch.Put(leveldbStateKey(sts[i].Key()), b)
+			batch.Put(leveldbStateKey(sts[i].Key(), sts[i].Height()), b)
 		}
 	}
 
 	return nil
 }
 
-func (bst *DatabaseSession) setVoteproofs(init, accept base.Voteproof) error {
+func (bst *DatabaseSession) setVoteproofs(batch *leveldb.Batch, init, accept base.Voteproof) error {
 	if init != nil {
 		if b, err := marshal(init, bst.st.enc); err != nil {
 			return err
 		} else {
-			bst.batch.Put(leveldbVoteproofKey(init.Height(), base.StageINIT), b)
+			batch.Put(leveldbVoteproofKey(init.Height(), base.StageINIT), b)
 		}
 	}
 
 	if accept != nil {
-		if err := bst.SetACCEPTVoteproof(accept); err != nil {
+		if b, err := bst.acceptVoteproof(accept); err != nil {
 			return err
+		} else {
+			batch.Put(leveldbVoteproofKey(accept.Height(), base.StageACCEPT), b)
 		}
 	}
 
@@ -152,23 +181,37 @@ func (bst *DatabaseSession) Commit(ctx context.Context, bd block.BlockdataMap) e
 		bst.batch.Put(leveldbBlockdataMapKey(bd.Height()), b)
 	}
 
-	return mergeError(bst.st.db.Write(bst.batch, nil))
+	// NOTE outbox is stored with block, so the saved block can be delivered to
+	// the sinks after crash.
+	bst.batch.Put(leveldbOutboxKey(bd.Height()), nil)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := bst.st.db.Write(bst.batch, nil); err != nil {
+		return mergeError(err)
+	}
+
+	bst.batch.Reset()
+
+	return nil
 }
 
 func (bst *DatabaseSession) Cancel() error {
+	bst.batch.Reset()
+
 	return nil
 }
 
 func (bst *DatabaseSession) Close() error {
+	bst.batch.Reset()
+
 	return nil
 }
 
 func (bst *DatabaseSession) SetACCEPTVoteproof(voteproof base.Voteproof) error {
-	if s := voteproof.Stage(); s != base.StageACCEPT {
-		return errors.Errorf("not accept voteproof, %v", s)
-	}
-
-	if b, err := marshal(voteproof, bst.st.enc); err != nil {
+	if b, err := bst.acceptVoteproof(voteproof); err != nil {
 		return err
 	} else {
 		bst.batch.Put(leveldbVoteproofKey(voteproof.Height(), base.StageACCEPT), b)
@@ -176,3 +219,11 @@ func (bst *DatabaseSession) SetACCEPTVoteproof(voteproof base.Voteproof) error {
 		return nil
 	}
 }
+
+func (bst *DatabaseSession) acceptVoteproof(voteproof base.Voteproof) ([]byte, error) {
+	if s := voteproof.Stage(); s != base.StageACCEPT {
+		return nil, errors.Errorf("not accept voteproof, %v", s)
+	}
+
+	return marshal(voteproof, bst.st.enc)
+}
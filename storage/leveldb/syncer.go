@@ -89,7 +89,9 @@ func (st *SyncerSession) SetManifests(manifests []block.Manifest) error {
 }
 
 func (st *SyncerSession) HasBlock(height base.Height) (bool, error) {
-	return st.database.db.Has(leveldbBlockHeightKey(height), nil)
+	found, err := st.database.db.Has(leveldbBlockHeightKey(height), nil)
+
+	return found, mergeError(err)
 }
 
 func (st *SyncerSession) block(height base.Height) (block.Block, bool, error) {
@@ -2,6 +2,7 @@ package mongodbstorage
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -9,14 +10,17 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/encoder"
 	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"go.mongodb.org/mongo-driver/bson"
@@ -33,6 +37,9 @@ const (
 	ColNameState           = "state"
 	ColNameVoteproof       = "voteproof"
 	ColNameBlockdataMap    = "blockdata_map"
+	ColNameOperationStatus = "operation_status"
+	ColNameOutbox          = "outbox"
+	ColNameEquivocation    = "equivocation"
 )
 
 var allCollections = []string{
@@ -44,6 +51,9 @@ var allCollections = []string{
 	ColNameState,
 	ColNameVoteproof,
 	ColNameBlockdataMap,
+	ColNameOperationStatus,
+	ColNameOutbox,
+	ColNameEquivocation,
 }
 
 type Database struct {
@@ -66,32 +76,13 @@ type Database struct {
 func NewDatabase(client *Client, encs *encoder.Encoders, enc encoder.Encoder, ca cache.Cache) (*Database, error) {
 	// NOTE call Initialize() later.
 	if ca == nil {
-		if c, err := cache.NewGCache("lru", 100*100*100, time.Minute*3); err != nil {
+		if c, err := newLocalCache(); err != nil {
 			return nil, err
 		} else {
 			ca = c
 		}
 	}
 
-	var stateCache, sealCache, operationFactCache cache.Cache
-	if ca, err := ca.New(); err != nil {
-		return nil, err
-	} else {
-		stateCache = ca
-	}
-
-	if ca, err := ca.New(); err != nil {
-		return nil, err
-	} else {
-		sealCache = ca
-	}
-
-	if ca, err := ca.New(); err != nil {
-		return nil, err
-	} else {
-		operationFactCache = ca
-	}
-
 	if enc == nil {
 		if e, err := encs.Encoder(bsonenc.BSONEncoderType, ""); err != nil {
 			return nil, err
@@ -100,6 +91,18 @@ func NewDatabase(client *Client, encs *encoder.Encoders, enc encoder.Encoder, ca
 		}
 	}
 
+	// NOTE the states and seals are stored in cache like redis by encoder
+	if i, ok := ca.(cache.EncoderSetter); ok {
+		if err := i.SetEncoder(enc); err != nil {
+			return nil, err
+		}
+	}
+
+	stateCache, sealCache, operationFactCache, err := newDatabaseCaches(client, ca)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Database{
 		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
 			return c.Str("module", "mongodb-database")
@@ -115,6 +118,48 @@ func NewDatabase(client *Client, encs *encoder.Encoders, enc encoder.Encoder, ca
 	}, nil
 }
 
+// newDatabaseCaches creates the caches for states, seals and operation facts.
+// If cache is shared with the other processes like redis, only the seals are
+// stored in it under the scope of database name; the states and operation
+// facts decide the result of consensus, so they are cached in local memory.
+func newDatabaseCaches(client *Client, ca cache.Cache) (cache.Cache, cache.Cache, cache.Cache, error) {
+	sc, ok := ca.(cache.Scoper)
+	if !ok {
+		var cs [3]cache.Cache
+		for i := range cs {
+			c, err := ca.New()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			cs[i] = c
+		}
+
+		return cs[0], cs[1], cs[2], nil
+	}
+
+	sealCache, err := sc.Scope(client.db.Name(), "seal")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	stateCache, err := newLocalCache()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	operationFactCache, err := newLocalCache()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return stateCache, sealCache, operationFactCache, nil
+}
+
+func newLocalCache() (cache.Cache, error) {
+	return cache.NewGCache("lru", 100*100*100, time.Minute*3)
+}
+
 func NewDatabaseFromURI(uri string, encs *encoder.Encoders, ca cache.Cache) (*Database, error) {
 	parsed, err := network.ParseURL(uri, false)
 	if err != nil {
@@ -362,6 +407,10 @@ func (st *Database) Clean() error {
 		return err
 	}
 
+	if err := st.SetSchemaVersion(LatestSchemaVersion()); err != nil {
+		return err
+	}
+
 	st.Lock()
 	defer st.Unlock()
 
@@ -708,6 +757,35 @@ func (st *Database) HasStagedOperation(h valuehash.Hash) (bool, error) {
 	return count > 0, nil
 }
 
+func (st *Database) StagedOperationTime(h valuehash.Hash) (time.Time, bool, error) {
+	var t time.Time
+	if err := st.client.GetByID(
+		ColNameStagedOperation,
+		h.String(),
+		func(res *mongo.SingleResult) error {
+			var doc struct {
+				InsertedAt time.Time `bson:"inserted_at"`
+			}
+			if err := res.Decode(&doc); err != nil {
+				return err
+			}
+
+			t = doc.InsertedAt
+
+			return nil
+		},
+		options.FindOne().SetProjection(bson.M{"inserted_at": 1}),
+	); err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return time.Time{}, false, nil
+		}
+
+		return time.Time{}, false, err
+	}
+
+	return t, true, nil
+}
+
 func (st *Database) Proposals(callback func(base.Proposal) (bool, error), sort bool) error {
 	var dir int
 	if sort {
@@ -834,6 +912,97 @@ func (st *Database) State(key string) (state.State, bool, error) {
 	return sta, sta != nil, nil
 }
 
+func (st *Database) StateAt(key string, height base.Height) (state.State, bool, error) {
+	if lastHeight := st.lastHeight(); height > lastHeight {
+		height = lastHeight
+	}
+
+	var sta state.State
+
+	if err := st.client.Find(
+		context.TODO(),
+		ColNameState,
+		util.NewBSONFilter("key", key).AddOp("height", height, "$lte").D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			i, err := loadStateFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+			sta = i
+
+			return false, nil
+		},
+		options.Find().SetSort(util.NewBSONFilter("height", -1).D()).SetLimit(1),
+	); err != nil {
+		return nil, false, err
+	}
+
+	return sta, sta != nil, nil
+}
+
+func (st *Database) StateHistory(
+	key string,
+	from, to base.Height,
+	callback func(state.State) (bool, error),
+) error {
+	if from > to {
+		return errors.Errorf("invalid height range; from, %d > to, %d", from, to)
+	}
+
+	if lastHeight := st.lastHeight(); to > lastHeight {
+		to = lastHeight
+	}
+
+	return st.client.Find(
+		context.TODO(),
+		ColNameState,
+		util.NewBSONFilter("key", key).Add("height", bson.D{
+			{Key: "$gte", Value: from},
+			{Key: "$lte", Value: to},
+		}).D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			i, err := loadStateFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(i)
+		},
+		options.Find().SetSort(util.NewBSONFilter("height", 1).D()),
+	)
+}
+
+func (st *Database) States(height base.Height, callback func(state.State) (bool, error)) error {
+	if lastHeight := st.lastHeight(); height > lastHeight {
+		height = lastHeight
+	}
+
+	var lastKey string
+	var found bool
+
+	return st.client.Find(
+		context.TODO(),
+		ColNameState,
+		util.EmptyBSONFilter().AddOp("height", height, "$lte").D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			i, err := loadStateFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			if found && i.Key() == lastKey {
+				return true, nil
+			}
+
+			lastKey = i.Key()
+			found = true
+
+			return callback(i)
+		},
+		options.Find().SetSort(util.NewBSONFilter("key", 1).Add("height", -1).D()),
+	)
+}
+
 func (st *Database) NewState(sta state.State) error {
 	if st.readonly {
 		return errors.Errorf("readonly mode")
@@ -872,6 +1041,357 @@ func (st *Database) HasOperationFact(h valuehash.Hash) (bool, error) {
 	return count > 0, nil
 }
 
+func (st *Database) OperationsBySigner(
+	signer key.Publickey,
+	cursor storage.OperationCursor,
+	limit int64,
+) ([]operation.Operation, storage.OperationCursor, error) {
+	return st.operationsByFilter(
+		util.NewBSONFilter("signers", signer.String()).AddOp("height", st.lastHeight(), "$lte"),
+		cursor,
+		limit,
+	)
+}
+
+func (st *Database) OperationsByHint(
+	ht hint.Type,
+	from, to base.Height,
+	cursor storage.OperationCursor,
+	limit int64,
+) ([]operation.Operation, storage.OperationCursor, error) {
+	if from > to {
+		return nil, storage.NilOperationCursor, errors.Errorf("invalid height range; from, %d > to, %d", from, to)
+	}
+
+	if lastHeight := st.lastHeight(); to > lastHeight {
+		to = lastHeight
+	}
+
+	return st.operationsByFilter(
+		util.NewBSONFilter("hint", ht.String()).Add("height", bson.D{
+			{Key: "$gte", Value: from},
+			{Key: "$lte", Value: to},
+		}),
+		cursor,
+		limit,
+	)
+}
+
+func (st *Database) operationsByFilter(
+	filter *util.BSONFilter,
+	cursor storage.OperationCursor,
+	limit int64,
+) ([]operation.Operation, storage.OperationCursor, error) {
+	limit = storage.CheckOperationsLimit(limit)
+
+	if !cursor.IsNil() {
+		filter = filter.Add("$or", bson.A{
+			util.NewBSONFilter("height", bson.D{{Key: "$gt", Value: cursor.Height}}).D(),
+			util.NewBSONFilter("height", cursor.Height).AddOp("index", cursor.Index, "$gt").D(),
+		})
+	}
+
+	var ops []operation.Operation
+	var last storage.OperationCursor
+	var more bool
+	if err := st.client.Find(
+		context.TODO(),
+		ColNameOperation,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			if int64(len(ops)) == limit {
+				more = true
+
+				return false, nil
+			}
+
+			op, err := loadOperationFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			height, index, err := loadOperationPositionFromDecoder(cursor.Decode)
+			if err != nil {
+				return false, err
+			}
+
+			ops = append(ops, op)
+			last = storage.NewOperationCursor(height, index)
+
+			return true, nil
+		},
+		options.Find().
+			SetSort(util.NewBSONFilter("height", 1).Add("index", 1).D()).
+			SetLimit(limit+1),
+	); err != nil {
+		return nil, storage.NilOperationCursor, err
+	}
+
+	if !more {
+		return ops, storage.NilOperationCursor, nil
+	}
+
+	return ops, last, nil
+}
+
+// ReindexOperations rewrites the operation documents, which were stored
+// before the operations are indexed by signers and hint type; they do not have
+// the "signers", "hint" and "index" fields. The operations are loaded from
+// blockdata. With dryrun, database is not changed. It returns the number of
+// rewritten documents.
+func (st *Database) ReindexOperations(ctx context.Context, bd blockdata.Blockdata, dryrun bool) (uint64, error) {
+	if st.readonly {
+		return 0, errors.Errorf("readonly mode")
+	}
+
+	facts := map[base.Height]map[string]struct{}{}
+	if err := st.client.Find(
+		ctx,
+		ColNameOperation,
+		util.NewBSONFilter("hint", bson.M{"$exists": false}).D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			var p struct {
+				H base.Height `bson:"height"`
+				F string      `bson:"fact"`
+			}
+
+			if err := cursor.Decode(&p); err != nil {
+				return false, err
+			}
+
+			if _, found := facts[p.H]; !found {
+				facts[p.H] = map[string]struct{}{}
+			}
+
+			facts[p.H][p.F] = struct{}{}
+
+			return true, nil
+		},
+	); err != nil {
+		return 0, err
+	}
+
+	heights := make([]base.Height, len(facts))
+	var i int
+	for h := range facts {
+		heights[i] = h
+		i++
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	var changed uint64
+	for i := range heights {
+		height := heights[i]
+
+		bdm, found, err := st.BlockdataMap(height)
+		switch {
+		case err != nil:
+			return 0, err
+		case !found:
+			return 0, util.NotFoundError.Errorf("block data map, %d not found", height)
+		}
+
+		ops, err := blockdata.LoadOperations(ctx, bd, bdm)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to load operations of block, %d", height)
+		}
+
+		var models []mongo.WriteModel
+		for j := range ops {
+			fact := ops[j].Fact().Hash().String()
+			if _, found := facts[height][fact]; !found {
+				continue
+			}
+
+			changed++
+
+			if dryrun {
+				continue
+			}
+
+			doc, err := NewOperationDocWithOperation(ops[j], uint64(j), st.enc, height)
+			if err != nil {
+				return 0, err
+			}
+
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(util.NewBSONFilter("height", height).Add("fact", fact).D()).
+				SetReplacement(doc))
+
+			if len(models) == defaultLimitWriteModels {
+				if err := st.client.Bulk(ctx, ColNameOperation, models, false); err != nil {
+					return 0, err
+				}
+
+				models = nil
+			}
+		}
+
+		if len(models) < 1 {
+			continue
+		}
+
+		if err := st.client.Bulk(ctx, ColNameOperation, models, false); err != nil {
+			return 0, err
+		}
+	}
+
+	return changed, nil
+}
+
+func (st *Database) SetOperationStatuses(statuses []operation.StatusV0) error {
+	if st.readonly {
+		return errors.Errorf("readonly mode")
+	}
+
+	var models []mongo.WriteModel
+	for i := range statuses {
+		status := statuses[i]
+
+		switch old, found, err := st.OperationStatus(status.Fact()); {
+		case err != nil:
+			return err
+		case found && !old.Status().CanUpdate(status.Status()):
+			continue
+		}
+
+		doc, err := NewOperationStatusDoc(status, st.enc)
+		if err != nil {
+			return err
+		}
+
+		m, err := doc.bsonM()
+		if err != nil {
+			return err
+		}
+		delete(m, "_id")
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(util.NewBSONFilter("_id", status.Fact().String()).D()).
+			SetUpdate(bson.D{{Key: "$set", Value: m}}).
+			SetUpsert(true),
+		)
+	}
+
+	if len(models) < 1 {
+		return nil
+	}
+
+	return st.client.Bulk(context.Background(), ColNameOperationStatus, models, true)
+}
+
+func (st *Database) SetEquivocation(ev base.EquivocationV0) error {
+	if st.readonly {
+		return errors.Errorf("readonly mode")
+	}
+
+	doc, err := NewEquivocationDoc(ev, st.enc)
+	if err != nil {
+		return err
+	}
+
+	if _, err := st.client.Add(ColNameEquivocation, doc); err != nil {
+		if errors.Is(err, util.DuplicatedError) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (st *Database) Equivocations(callback func(base.EquivocationV0) (bool, error), sort bool) error {
+	dir := 1
+	if !sort {
+		dir = -1
+	}
+
+	return st.client.Find(
+		context.TODO(),
+		ColNameEquivocation,
+		bson.D{},
+		func(cursor *mongo.Cursor) (bool, error) {
+			i, err := loadEquivocationFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(i)
+		},
+		options.Find().SetSort(bson.D{
+			{Key: "height", Value: dir},
+			{Key: "round", Value: dir},
+			{Key: "stage", Value: dir},
+		}),
+	)
+}
+
+func (st *Database) Outbox(callback func(base.Height) (bool, error)) error {
+	return st.client.Find(
+		context.TODO(),
+		ColNameOutbox,
+		bson.D{},
+		func(cursor *mongo.Cursor) (bool, error) {
+			height, err := loadOutboxHeightFromDecoder(cursor.Decode)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(height)
+		},
+		options.Find().SetSort(util.NewBSONFilter("height", 1).D()),
+	)
+}
+
+func (st *Database) RemoveOutbox(heights []base.Height) error {
+	if st.readonly {
+		return errors.Errorf("readonly mode")
+	}
+
+	if len(heights) < 1 {
+		return nil
+	}
+
+	_, err := st.client.Collection(ColNameOutbox).DeleteMany(
+		context.Background(),
+		bson.M{"height": bson.M{"$in": heights}},
+	)
+
+	return MergeError(err)
+}
+
+func (st *Database) OperationStatus(fact valuehash.Hash) (operation.StatusV0, bool, error) {
+	var status operation.StatusV0
+	var found bool
+	if err := st.client.GetByID(
+		ColNameOperationStatus,
+		fact.String(),
+		func(res *mongo.SingleResult) error {
+			i, err := loadOperationStatusFromDecoder(res.Decode, st.encs)
+			if err != nil {
+				return err
+			}
+
+			status = i
+			found = true
+
+			return nil
+		},
+	); err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return operation.StatusV0{}, false, nil
+		}
+
+		return operation.StatusV0{}, false, err
+	}
+
+	return status, found, nil
+}
+
 func (st *Database) NewSession(blk block.Block) (storage.DatabaseSession, error) {
 	if st.readonly {
 		return nil, errors.Errorf("readonly mode")
@@ -907,6 +1427,11 @@ func (st *Database) cleanByHeight(height base.Height) error {
 	removeByHeight := mongo.NewDeleteManyModel().SetFilter(bson.M{"height": bson.M{"$gte": height}})
 
 	for _, col := range allCollections {
+		// NOTE the evidences of double vote are not block data; they are kept
+		if col == ColNameEquivocation {
+			continue
+		}
+
 		res, err := st.client.Collection(col).BulkWrite(
 			context.Background(),
 			[]mongo.WriteModel{removeByHeight},
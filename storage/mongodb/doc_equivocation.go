@@ -0,0 +1,62 @@
+package mongodbstorage
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type EquivocationDoc struct {
+	BaseDoc
+	ev base.EquivocationV0
+}
+
+func NewEquivocationDoc(ev base.EquivocationV0, enc encoder.Encoder) (EquivocationDoc, error) {
+	b, err := NewBaseDoc(ev.Key(), ev, enc)
+	if err != nil {
+		return EquivocationDoc{}, err
+	}
+
+	return EquivocationDoc{
+		BaseDoc: b,
+		ev:      ev,
+	}, nil
+}
+
+func (ed EquivocationDoc) MarshalBSON() ([]byte, error) {
+	m, err := ed.BaseDoc.M()
+	if err != nil {
+		return nil, err
+	}
+
+	m["node"] = ed.ev.Node().String()
+	m["height"] = ed.ev.Height()
+	m["round"] = ed.ev.Round()
+	m["stage"] = ed.ev.Stage()
+
+	return bsonenc.Marshal(m)
+}
+
+func loadEquivocationFromDecoder(
+	decoder func(interface{}) error,
+	encs *encoder.Encoders,
+) (base.EquivocationV0, error) {
+	var b bson.Raw
+	if err := decoder(&b); err != nil {
+		return base.EquivocationV0{}, err
+	}
+
+	_, hinter, err := LoadDataFromDoc(b, encs)
+	if err != nil {
+		return base.EquivocationV0{}, err
+	}
+
+	i, ok := hinter.(base.EquivocationV0)
+	if !ok {
+		return base.EquivocationV0{}, errors.Errorf("not base.EquivocationV0: %T", hinter)
+	}
+
+	return i, nil
+}
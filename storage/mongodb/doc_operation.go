@@ -1,16 +1,21 @@
 package mongodbstorage
 
 import (
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/util/encoder"
 	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
 	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type OperationDoc struct {
 	BaseDoc
 	fact   valuehash.Hash
 	height base.Height
+	op     operation.Operation
+	index  uint64
 }
 
 func NewOperationDoc(fact valuehash.Hash, enc encoder.Encoder, height base.Height) (OperationDoc, error) {
@@ -26,6 +31,28 @@ func NewOperationDoc(fact valuehash.Hash, enc encoder.Encoder, height base.Heigh
 	}, nil
 }
 
+// NewOperationDocWithOperation keeps the operation with it's index in block
+// operations, so the operation can be found by signers and hint type.
+func NewOperationDocWithOperation(
+	op operation.Operation,
+	index uint64,
+	enc encoder.Encoder,
+	height base.Height,
+) (OperationDoc, error) {
+	b, err := NewBaseDoc(nil, op, enc)
+	if err != nil {
+		return OperationDoc{}, err
+	}
+
+	return OperationDoc{
+		BaseDoc: b,
+		height:  height,
+		fact:    op.Fact().Hash(),
+		op:      op,
+		index:   index,
+	}, nil
+}
+
 func (od OperationDoc) MarshalBSON() ([]byte, error) {
 	m, err := od.BaseDoc.M()
 	if err != nil {
@@ -35,5 +62,49 @@ func (od OperationDoc) MarshalBSON() ([]byte, error) {
 	m["fact"] = od.fact.String()
 	m["height"] = od.height
 
+	if od.op != nil {
+		fs := od.op.Signs()
+		signers := make([]string, len(fs))
+		for i := range fs {
+			signers[i] = fs[i].Signer().String()
+		}
+
+		m["signers"] = signers
+		m["hint"] = od.op.Hint().Type().String()
+		m["index"] = od.index
+	}
+
 	return bsonenc.Marshal(m)
 }
+
+func loadOperationFromDecoder(decoder func(interface{}) error, encs *encoder.Encoders) (operation.Operation, error) {
+	var b bson.Raw
+	if err := decoder(&b); err != nil {
+		return nil, err
+	}
+
+	_, hinter, err := LoadDataFromDoc(b, encs)
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := hinter.(operation.Operation)
+	if !ok {
+		return nil, errors.Errorf("not operation.Operation: %T", hinter)
+	}
+
+	return op, nil
+}
+
+func loadOperationPositionFromDecoder(decoder func(interface{}) error) (base.Height, uint64, error) {
+	var p struct {
+		H base.Height `bson:"height"`
+		I uint64      `bson:"index"`
+	}
+
+	if err := decoder(&p); err != nil {
+		return base.NilHeight, 0, err
+	}
+
+	return p.H, p.I, nil
+}
@@ -0,0 +1,69 @@
+package mongodbstorage
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type OperationStatusDoc struct {
+	BaseDoc
+	status operation.StatusV0
+}
+
+func NewOperationStatusDoc(status operation.StatusV0, enc encoder.Encoder) (OperationStatusDoc, error) {
+	b, err := NewBaseDoc(status.Fact().String(), status, enc)
+	if err != nil {
+		return OperationStatusDoc{}, err
+	}
+
+	return OperationStatusDoc{
+		BaseDoc: b,
+		status:  status,
+	}, nil
+}
+
+func (sd OperationStatusDoc) bsonM() (bson.M, error) {
+	m, err := sd.BaseDoc.M()
+	if err != nil {
+		return nil, err
+	}
+
+	m["status"] = sd.status.Status()
+	m["height"] = sd.status.Height()
+
+	return m, nil
+}
+
+func (sd OperationStatusDoc) MarshalBSON() ([]byte, error) {
+	m, err := sd.bsonM()
+	if err != nil {
+		return nil, err
+	}
+
+	return bsonenc.Marshal(m)
+}
+
+func loadOperationStatusFromDecoder(
+	decoder func(interface{}) error,
+	encs *encoder.Encoders,
+) (operation.StatusV0, error) {
+	var b bson.Raw
+	if err := decoder(&b); err != nil {
+		return operation.StatusV0{}, err
+	}
+
+	_, hinter, err := LoadDataFromDoc(b, encs)
+	if err != nil {
+		return operation.StatusV0{}, err
+	}
+
+	i, ok := hinter.(operation.StatusV0)
+	if !ok {
+		return operation.StatusV0{}, errors.Errorf("not operation.StatusV0: %T", hinter)
+	}
+
+	return i, nil
+}
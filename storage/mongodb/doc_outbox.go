@@ -0,0 +1,47 @@
+package mongodbstorage
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+)
+
+type OutboxDoc struct {
+	BaseDoc
+	height base.Height
+}
+
+func NewOutboxDoc(height base.Height, enc encoder.Encoder) (OutboxDoc, error) {
+	b, err := NewBaseDoc(height.Int64(), nil, enc)
+	if err != nil {
+		return OutboxDoc{}, err
+	}
+
+	return OutboxDoc{
+		BaseDoc: b,
+		height:  height,
+	}, nil
+}
+
+func (od OutboxDoc) MarshalBSON() ([]byte, error) {
+	m, err := od.BaseDoc.M()
+	if err != nil {
+		return nil, err
+	}
+
+	m["height"] = od.height
+
+	return bsonenc.Marshal(m)
+}
+
+func loadOutboxHeightFromDecoder(decoder func(interface{}) error) (base.Height, error) {
+	var o struct {
+		H base.Height `bson:"height"`
+	}
+
+	if err := decoder(&o); err != nil {
+		return base.NilHeight, err
+	}
+
+	return o.H, nil
+}
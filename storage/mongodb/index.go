@@ -32,6 +32,16 @@ var operationIndexModels = []mongo.IndexModel{
 		Options: options.Index().
 			SetName(indexName("operation_height")),
 	},
+	{
+		Keys: bson.D{bson.E{Key: "signers", Value: 1}, bson.E{Key: "height", Value: 1}, bson.E{Key: "index", Value: 1}},
+		Options: options.Index().
+			SetName(indexName("operation_signers_height_and_index")),
+	},
+	{
+		Keys: bson.D{bson.E{Key: "hint", Value: 1}, bson.E{Key: "height", Value: 1}, bson.E{Key: "index", Value: 1}},
+		Options: options.Index().
+			SetName(indexName("operation_hint_height_and_index")),
+	},
 }
 
 var stateIndexModels = []mongo.IndexModel{
@@ -99,6 +109,40 @@ var blockdataMapIndexModels = []mongo.IndexModel{
 	},
 }
 
+var operationStatusIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{bson.E{Key: "height", Value: 1}},
+		Options: options.Index().
+			SetName(indexName("operation_status_height")),
+	},
+}
+
+var outboxIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{bson.E{Key: "height", Value: 1}},
+		Options: options.Index().
+			SetName(indexName("outbox_height")).
+			SetUnique(true),
+	},
+}
+
+var equivocationIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "height", Value: 1},
+			bson.E{Key: "round", Value: 1},
+			bson.E{Key: "stage", Value: 1},
+		},
+		Options: options.Index().
+			SetName(indexName("equivocation_height_round_stage")),
+	},
+	{
+		Keys: bson.D{bson.E{Key: "node", Value: 1}},
+		Options: options.Index().
+			SetName(indexName("equivocation_node")),
+	},
+}
+
 var defaultIndexes = map[string] /* collection */ []mongo.IndexModel{
 	ColNameManifest:        manifestIndexModels,
 	ColNameOperation:       operationIndexModels,
@@ -107,6 +151,9 @@ var defaultIndexes = map[string] /* collection */ []mongo.IndexModel{
 	ColNameState:           stateIndexModels,
 	ColNameVoteproof:       voteproofIndexModels,
 	ColNameBlockdataMap:    blockdataMapIndexModels,
+	ColNameOperationStatus: operationStatusIndexModels,
+	ColNameOutbox:          outboxIndexModels,
+	ColNameEquivocation:    equivocationIndexModels,
 }
 
 func indexName(s string) string {
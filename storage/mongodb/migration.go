@@ -0,0 +1,249 @@
+package mongodbstorage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SchemaVersionInfoKey is the info key of schema version of database.
+var SchemaVersionInfoKey = "schema_version"
+
+var SchemaVersionError = util.NewError("schema version of database does not match")
+
+// MigrationFunc migrates database into the next schema version. The block
+// data is given for the migration, which needs the data of the stored blocks.
+// With dryrun, database should not be changed. It returns the number of
+// changed documents.
+type MigrationFunc func(ctx context.Context, st *Database, bd blockdata.Blockdata, dryrun bool) (uint64, error)
+
+// Migration rewrites database from the previous schema version into Version.
+type Migration struct {
+	Version     uint64
+	Description string
+	Migrate     MigrationFunc
+}
+
+// NOTE migrations are ordered by version. The database before the first
+// migration is version 0.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "add signers, hint and index to operation documents",
+		Migrate: func(ctx context.Context, st *Database, bd blockdata.Blockdata, dryrun bool) (uint64, error) {
+			return st.ReindexOperations(ctx, bd, dryrun)
+		},
+	},
+}
+
+// RegisterMigration adds new migration. The version of migration should be
+// the next of the last registered one.
+func RegisterMigration(m Migration) error {
+	if m.Migrate == nil {
+		return errors.Errorf("empty migration function, %d", m.Version)
+	}
+
+	if m.Version != LatestSchemaVersion()+1 {
+		return errors.Errorf("migration version should be the next of %d; %d", LatestSchemaVersion(), m.Version)
+	}
+
+	migrations = append(migrations, m)
+
+	return nil
+}
+
+// Migrations returns the registered migrations, which are after the given
+// version.
+func Migrations(from uint64) []Migration {
+	i := sort.Search(len(migrations), func(i int) bool {
+		return migrations[i].Version > from
+	})
+
+	ms := make([]Migration, len(migrations[i:]))
+	copy(ms, migrations[i:])
+
+	return ms
+}
+
+// LatestSchemaVersion is the schema version of the last migration.
+func LatestSchemaVersion() uint64 {
+	if len(migrations) < 1 {
+		return 0
+	}
+
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the stored schema version. The database without
+// schema version is version 0.
+func (st *Database) SchemaVersion() (uint64, bool, error) {
+	switch b, found, err := st.Info(SchemaVersionInfoKey); {
+	case err != nil:
+		return 0, false, err
+	case !found:
+		return 0, false, nil
+	default:
+		i, err := util.BytesToUint64(b)
+		if err != nil {
+			return 0, false, errors.Wrap(err, "invalid schema version")
+		}
+
+		return i, true, nil
+	}
+}
+
+func (st *Database) SetSchemaVersion(v uint64) error {
+	return st.SetInfo(SchemaVersionInfoKey, util.Uint64ToBytes(v))
+}
+
+// CheckSchemaVersion checks whether the database is migrated into the latest
+// schema version. The empty database without schema version is stamped with
+// the latest version.
+func (st *Database) CheckSchemaVersion() error {
+	v, found, err := st.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if !found && !st.readonly {
+		switch _, found, err := st.LastManifest(); {
+		case err != nil:
+			return err
+		case !found:
+			return st.SetSchemaVersion(LatestSchemaVersion())
+		}
+	}
+
+	switch latest := LatestSchemaVersion(); {
+	case v < latest:
+		return SchemaVersionError.Errorf("database should be migrated; %d < %d", v, latest)
+	case v > latest:
+		return SchemaVersionError.Errorf("database is newer than node; %d > %d", v, latest)
+	default:
+		return nil
+	}
+}
+
+// Migrate runs the migrations after the stored schema version by order. The
+// schema version is updated after each migration, so the failed migration
+// can be continued. With dryrun, the migrations do not change database.
+func (st *Database) Migrate(
+	ctx context.Context,
+	bd blockdata.Blockdata,
+	dryrun bool,
+	callback func(Migration, uint64 /* changed */) error,
+) error {
+	if st.readonly {
+		return errors.Errorf("readonly mode")
+	}
+
+	v, _, err := st.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if latest := LatestSchemaVersion(); v > latest {
+		return SchemaVersionError.Errorf("database is newer than node; %d > %d", v, latest)
+	}
+
+	ms := Migrations(v)
+	for i := range ms {
+		m := ms[i]
+
+		changed, err := m.Migrate(ctx, st, bd, dryrun)
+		if err != nil {
+			return errors.Wrapf(err, "failed to migrate into version %d", m.Version)
+		}
+
+		if !dryrun {
+			if err := st.SetSchemaVersion(m.Version); err != nil {
+				return err
+			}
+		}
+
+		if callback != nil {
+			if err := callback(m, changed); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RewriteCollection replaces the documents of collection in place. f returns
+// the new document and false if the document does not need to be changed;
+// the new document keeps the "_id" of the old one. With dryrun, the documents
+// are only counted.
+func (st *Database) RewriteCollection(
+	ctx context.Context,
+	col string,
+	filter interface{},
+	f func(bson.Raw) (bson.M, bool, error),
+	dryrun bool,
+) (uint64, error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	var changed uint64
+	var models []mongo.WriteModel
+
+	flush := func() error {
+		if len(models) < 1 {
+			return nil
+		}
+
+		if err := st.client.Bulk(ctx, col, models, false); err != nil {
+			return err
+		}
+
+		models = nil
+
+		return nil
+	}
+
+	if err := st.client.Find(ctx, col, filter, func(cursor *mongo.Cursor) (bool, error) {
+		raw := cursor.Current
+
+		doc, ok, err := f(raw)
+		switch {
+		case err != nil:
+			return false, err
+		case !ok:
+			return true, nil
+		}
+
+		changed++
+
+		if dryrun {
+			return true, nil
+		}
+
+		id := raw.Lookup("_id")
+		doc["_id"] = id
+
+		models = append(models, mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: id}}).SetReplacement(doc))
+
+		if len(models) == defaultLimitWriteModels {
+			if err := flush(); err != nil {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := flush(); err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,294 @@
+//go:build mongodb
+// +build mongodb
+
+package mongodbstorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/cache"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type testMigration struct {
+	storage.BaseTestDatabase
+	database   *Database
+	migrations []Migration
+}
+
+func (t *testMigration) SetupTest() {
+	client, err := NewClient(TestMongodbURI(), time.Second*2, time.Second*2)
+	t.NoError(err)
+
+	st, err := NewDatabase(client, t.Encs, nil, cache.Dummy{})
+	t.NoError(err)
+	t.NoError(st.Initialize())
+	t.database = st
+
+	t.migrations = migrations
+	migrations = nil
+}
+
+func (t *testMigration) TearDownTest() {
+	migrations = t.migrations
+
+	if t.database != nil {
+		t.database.Client().DropDatabase()
+		t.database.Close()
+	}
+}
+
+func (t *testMigration) setManifest() {
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	t.NoError(t.database.setLastManifest(blk.Manifest(), false, true))
+}
+
+func (t *testMigration) TestRegister() {
+	f := func(context.Context, *Database, blockdata.Blockdata, bool) (uint64, error) { return 0, nil }
+
+	t.NoError(RegisterMigration(Migration{Version: 1, Migrate: f}))
+	t.NoError(RegisterMigration(Migration{Version: 2, Migrate: f}))
+
+	err := RegisterMigration(Migration{Version: 2, Migrate: f})
+	t.Error(err)
+	t.Contains(err.Error(), "should be the next")
+
+	err = RegisterMigration(Migration{Version: 4, Migrate: f})
+	t.Error(err)
+	t.Contains(err.Error(), "should be the next")
+
+	err = RegisterMigration(Migration{Version: 3})
+	t.Error(err)
+	t.Contains(err.Error(), "empty migration function")
+
+	t.Equal(uint64(2), LatestSchemaVersion())
+	t.Equal(2, len(Migrations(0)))
+	t.Equal(1, len(Migrations(1)))
+	t.Equal(0, len(Migrations(2)))
+}
+
+func (t *testMigration) TestCheckEmptyDatabase() {
+	f := func(context.Context, *Database, blockdata.Blockdata, bool) (uint64, error) { return 0, nil }
+	t.NoError(RegisterMigration(Migration{Version: 1, Migrate: f}))
+
+	_, found, err := t.database.SchemaVersion()
+	t.NoError(err)
+	t.False(found)
+
+	// NOTE empty database is stamped with the latest version
+	t.NoError(t.database.CheckSchemaVersion())
+
+	v, found, err := t.database.SchemaVersion()
+	t.NoError(err)
+	t.True(found)
+	t.Equal(uint64(1), v)
+}
+
+func (t *testMigration) TestCheckUnmigrated() {
+	t.setManifest()
+
+	f := func(context.Context, *Database, blockdata.Blockdata, bool) (uint64, error) { return 0, nil }
+	t.NoError(RegisterMigration(Migration{Version: 1, Migrate: f}))
+
+	err := t.database.CheckSchemaVersion()
+	t.True(errors.Is(err, SchemaVersionError))
+	t.Contains(err.Error(), "should be migrated")
+
+	_, found, err := t.database.SchemaVersion()
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testMigration) TestCheckNewer() {
+	t.NoError(t.database.SetSchemaVersion(3))
+
+	err := t.database.CheckSchemaVersion()
+	t.True(errors.Is(err, SchemaVersionError))
+	t.Contains(err.Error(), "newer than node")
+}
+
+func (t *testMigration) TestMigrate() {
+	t.setManifest()
+
+	_, err := t.database.Client().Collection("showme").InsertMany(context.Background(), []interface{}{
+		bson.M{"a": 1}, bson.M{"a": 2}, bson.M{"a": 3},
+	})
+	t.NoError(err)
+
+	var applied []uint64
+	migrate := func(ctx context.Context, st *Database, _ blockdata.Blockdata, dryrun bool) (uint64, error) {
+		return st.RewriteCollection(ctx, "showme", bson.M{"a": bson.M{"$gt": 1}},
+			func(raw bson.Raw) (bson.M, bool, error) {
+				return bson.M{"a": raw.Lookup("a").Int32(), "b": true}, true, nil
+			},
+			dryrun,
+		)
+	}
+
+	t.NoError(RegisterMigration(Migration{Version: 1, Description: "add b", Migrate: migrate}))
+
+	callback := func(m Migration, changed uint64) error {
+		applied = append(applied, m.Version)
+		t.Equal(uint64(2), changed)
+
+		return nil
+	}
+
+	countB := func() int {
+		var n int
+		t.NoError(t.database.Client().Find(context.Background(), "showme", bson.M{"b": true},
+			func(*mongo.Cursor) (bool, error) {
+				n++
+
+				return true, nil
+			}))
+
+		return n
+	}
+
+	// NOTE dryrun does not change database
+	t.NoError(t.database.Migrate(context.Background(), nil, true, callback))
+	t.Equal([]uint64{1}, applied)
+	t.Equal(0, countB())
+	t.Error(t.database.CheckSchemaVersion())
+
+	applied = nil
+	t.NoError(t.database.Migrate(context.Background(), nil, false, callback))
+	t.Equal([]uint64{1}, applied)
+	t.Equal(2, countB())
+	t.NoError(t.database.CheckSchemaVersion())
+
+	// NOTE already migrated
+	applied = nil
+	t.NoError(t.database.Migrate(context.Background(), nil, false, callback))
+	t.Empty(applied)
+}
+
+func (t *testMigration) TestMigrateOperationDocuments() {
+	// NOTE the migrations of node
+	migrations = t.migrations
+
+	bd := localfs.NewBlockdata(t.T().TempDir(), t.JSONEnc.(*jsonenc.Encoder))
+	t.NoError(bd.Initialize())
+
+	ops := make([]operation.Operation, 2)
+	for i := range ops {
+		op, err := operation.NewKVOperation(t.PK, []byte("this-is-token"), util.UUID().String(), util.UUID().Bytes(), nil)
+		t.NoError(err)
+
+		ops[i] = op
+	}
+
+	tg := tree.NewFixedTreeGenerator(uint64(len(ops)))
+	for i := range ops {
+		t.NoError(tg.Add(operation.NewFixedTreeNode(uint64(i), ops[i].Fact().Hash().Bytes(), true, nil)))
+	}
+
+	tr, err := tg.Tree()
+	t.NoError(err)
+
+	b, err := block.NewTestBlockV0(base.Height(1), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	blk := (interface{})(b).(block.BlockUpdater).
+		SetINITVoteproof(base.NewVoteproofV0(b.Height(), b.Round(), nil, base.ThresholdRatio(100), base.StageINIT)).
+		SetACCEPTVoteproof(base.NewVoteproofV0(b.Height(), b.Round(), nil, base.ThresholdRatio(100), base.StageACCEPT)).
+		SetOperationsTree(tr).
+		SetOperations(ops).(block.Block)
+
+	bs, err := bd.NewSession(blk.Height())
+	t.NoError(err)
+	t.NoError(bs.SetBlock(blk))
+
+	bdm, err := bd.SaveSession(bs)
+	t.NoError(err)
+
+	ds, err := t.database.NewSession(blk)
+	t.NoError(err)
+	t.NoError(ds.SetBlock(context.Background(), blk))
+	t.NoError(ds.Commit(context.Background(), bdm))
+	t.NoError(ds.Close())
+
+	// NOTE rewrite the operation documents like the database written before
+	// the operations are indexed
+	for i := range ops {
+		doc, err := NewOperationDoc(ops[i].Fact().Hash(), t.database.enc, blk.Height())
+		t.NoError(err)
+
+		_, err = t.database.Client().Collection(ColNameOperation).ReplaceOne(
+			context.Background(),
+			bson.M{"fact": ops[i].Fact().Hash().String()},
+			doc,
+		)
+		t.NoError(err)
+	}
+
+	uops, _, err := t.database.OperationsBySigner(t.PK.Publickey(), storage.NilOperationCursor, 0)
+	t.NoError(err)
+	t.Empty(uops)
+
+	err = t.database.CheckSchemaVersion()
+	t.True(errors.Is(err, SchemaVersionError))
+
+	callback := func(m Migration, changed uint64) error {
+		t.Equal(uint64(1), m.Version)
+		t.Equal(uint64(len(ops)), changed)
+
+		return nil
+	}
+
+	// NOTE dryrun does not change database
+	t.NoError(t.database.Migrate(context.Background(), bd, true, callback))
+
+	uops, _, err = t.database.OperationsBySigner(t.PK.Publickey(), storage.NilOperationCursor, 0)
+	t.NoError(err)
+	t.Empty(uops)
+
+	t.NoError(t.database.Migrate(context.Background(), bd, false, callback))
+	t.NoError(t.database.CheckSchemaVersion())
+
+	uops, _, err = t.database.OperationsBySigner(t.PK.Publickey(), storage.NilOperationCursor, 0)
+	t.NoError(err)
+	t.Equal(len(ops), len(uops))
+
+	for i := range ops {
+		t.True(ops[i].Hash().Equal(uops[i].Hash()))
+	}
+
+	uops, _, err = t.database.OperationsByHint(ops[0].Hint().Type(), blk.Height(), blk.Height(), storage.NilOperationCursor, 0)
+	t.NoError(err)
+	t.Equal(len(ops), len(uops))
+}
+
+func (t *testMigration) TestClean() {
+	f := func(context.Context, *Database, blockdata.Blockdata, bool) (uint64, error) { return 0, nil }
+	t.NoError(RegisterMigration(Migration{Version: 1, Migrate: f}))
+
+	t.NoError(t.database.Clean())
+
+	v, found, err := t.database.SchemaVersion()
+	t.NoError(err)
+	t.True(found)
+	t.Equal(uint64(1), v)
+}
+
+func TestMigration(t *testing.T) {
+	suite.Run(t, new(testMigration))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
@@ -128,7 +129,7 @@ func (bst *DatabaseSession) setBlock(blk block.Block) error {
 		bst.manifestModel = mongo.NewInsertOneModel().SetDocument(doc)
 	}
 
-	if err := bst.setOperationsTree(blk.OperationsTree()); err != nil {
+	if err := bst.setOperationsTree(blk.OperationsTree(), blk.Operations()); err != nil {
 		return err
 	}
 
@@ -227,6 +228,16 @@ func (bst *DatabaseSession) commit(ctx context.Context, bd block.BlockdataMap) e
 		return errors.Errorf("blockdatamap not inserted")
 	}
 
+	// NOTE outbox is stored with block, so the saved block can be delivered to
+	// the sinks after crash.
+	if doc, err := NewOutboxDoc(bd.Height(), bst.st.enc); err != nil {
+		return err
+	} else if _, err := bst.writeModels(ctx, ColNameOutbox, []mongo.WriteModel{
+		mongo.NewReplaceOneModel().SetFilter(util.NewBSONFilter("_id", doc.ID()).D()).SetReplacement(doc).SetUpsert(true),
+	}); err != nil {
+		return MergeError(err)
+	}
+
 	if err := bst.ost.setLastBlock(bst.block, true, false); err != nil {
 		return err
 	}
@@ -236,7 +247,7 @@ func (bst *DatabaseSession) commit(ctx context.Context, bd block.BlockdataMap) e
 	return nil
 }
 
-func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
+func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree, ops []operation.Operation) error {
 	started := time.Now()
 	defer func() {
 		bst.statesValue.Store("set-operations-tree", time.Since(started))
@@ -246,9 +257,23 @@ func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
 		return nil
 	}
 
+	indices := map[string]int{}
+	for i := range ops {
+		indices[ops[i].Fact().Hash().String()] = i
+	}
+
 	var models []mongo.WriteModel
 	if err := tr.Traverse(func(no tree.FixedTreeNode) (bool, error) {
-		doc, err := NewOperationDoc(valuehash.NewBytes(no.Key()), bst.st.enc, bst.block.Height())
+		fact := valuehash.NewBytes(no.Key())
+
+		var doc OperationDoc
+		var err error
+		if i, found := indices[fact.String()]; found {
+			doc, err = NewOperationDocWithOperation(ops[i], uint64(i), bst.st.enc, bst.block.Height())
+		} else {
+			doc, err = NewOperationDoc(fact, bst.st.enc, bst.block.Height())
+		}
+
 		if err != nil {
 			return false, err
 		}
@@ -212,6 +212,7 @@ func (st *SyncerSession) Commit() error {
 		ColNameState,
 		ColNameVoteproof,
 		ColNameBlockdataMap,
+		ColNameOutbox,
 	} {
 		if err := moveWithinCol(st.session, col, st.main, col, bson.D{}); err != nil {
 			l.Error().Err(err).Str("collection", col).Msg("failed to move collection")
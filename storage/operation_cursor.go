@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/isvalid"
+)
+
+// DefaultOperationsLimit is the default number of operations, which are
+// returned at once by OperationsBySigner and OperationsByHint.
+var DefaultOperationsLimit int64 = 100
+
+// OperationCursor points the operation in the stored blocks by height and the
+// index of operation in block operations. The operations are ordered by height
+// and index, so the next operations of cursor can be found.
+type OperationCursor struct {
+	Height base.Height
+	Index  uint64
+}
+
+// NilOperationCursor points before the first operation.
+var NilOperationCursor = OperationCursor{Height: base.NilHeight}
+
+func NewOperationCursor(height base.Height, index uint64) OperationCursor {
+	return OperationCursor{Height: height, Index: index}
+}
+
+// ParseOperationCursor parses the cursor string, "<height>-<index>". The empty
+// string is NilOperationCursor.
+func ParseOperationCursor(s string) (OperationCursor, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 1 {
+		return NilOperationCursor, nil
+	}
+
+	i := strings.LastIndex(s, "-")
+	if i < 1 {
+		return OperationCursor{}, isvalid.InvalidError.Errorf("invalid operation cursor, %q", s)
+	}
+
+	height, err := base.NewHeightFromString(s[:i])
+	if err != nil {
+		return OperationCursor{}, isvalid.InvalidError.Wrap(err)
+	}
+
+	index, err := strconv.ParseUint(s[i+1:], 10, 64)
+	if err != nil {
+		return OperationCursor{}, isvalid.InvalidError.Wrap(err)
+	}
+
+	oc := NewOperationCursor(height, index)
+	if err := oc.IsValid(nil); err != nil {
+		return OperationCursor{}, err
+	}
+
+	return oc, nil
+}
+
+func (oc OperationCursor) IsValid([]byte) error {
+	if oc.IsNil() {
+		return nil
+	}
+
+	return oc.Height.IsValid(nil)
+}
+
+func (oc OperationCursor) IsNil() bool {
+	return oc.Height.IsEmpty()
+}
+
+func (oc OperationCursor) String() string {
+	if oc.IsNil() {
+		return ""
+	}
+
+	return fmt.Sprintf("%d-%d", oc.Height, oc.Index)
+}
+
+// CheckOperationsLimit returns DefaultOperationsLimit when the given limit is
+// not set.
+func CheckOperationsLimit(limit int64) int64 {
+	if limit < 1 {
+		return DefaultOperationsLimit
+	}
+
+	return limit
+}
@@ -0,0 +1,4 @@
+/*
+Package outbox delivers the committed blocks to the external sinks.
+*/
+package outbox
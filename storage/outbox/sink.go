@@ -0,0 +1,206 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+)
+
+var DefaultSinkTimeout = time.Second * 10
+
+// Sink receives the saved block. The block is json of events.BlockEvent.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, height base.Height, b []byte) error
+}
+
+// NewSinkFromURI creates Sink from uri,
+// - "http://" or "https://": the block is posted to the webhook url.
+// - "file:///path": the block is appended into file as one line.
+// - "exec:///path?arg=a&arg=b": the block is passed to the local command
+// thru stdin.
+//
+// "timeout" query sets the timeout of http request and command and
+// "tls_insecure=true" allows insecure TLS of webhook; they are removed from the
+// webhook url.
+func NewSinkFromURI(name string, u *url.URL) (Sink, error) {
+	name = strings.TrimSpace(name)
+	if len(name) < 1 {
+		return nil, errors.Errorf("empty sink name")
+	}
+
+	query := u.Query()
+
+	timeout := DefaultSinkTimeout
+	if s := query.Get("timeout"); len(s) > 0 {
+		i, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout of sink, %q", s)
+		}
+		timeout = i
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		tlsInsecure := query.Get("tls_insecure") == "true"
+		query.Del("timeout")
+		query.Del("tls_insecure")
+
+		nu := *u
+		nu.RawQuery = query.Encode()
+
+		return NewHTTPSink(name, &nu, timeout, tlsInsecure), nil
+	case "file":
+		if len(u.Path) < 1 {
+			return nil, errors.Errorf("empty file path of sink, %q", name)
+		}
+
+		return NewFileSink(name, u.Path), nil
+	case "exec":
+		if len(u.Path) < 1 {
+			return nil, errors.Errorf("empty command of sink, %q", name)
+		}
+
+		return NewCommandSink(name, u.Path, query["arg"], timeout), nil
+	default:
+		return nil, errors.Errorf("unsupported sink uri, %q", u.Scheme)
+	}
+}
+
+// HTTPSink posts the block to the webhook url. The response with 2xx status
+// is considered as delivered.
+type HTTPSink struct {
+	name   string
+	u      string
+	client *http.Client
+}
+
+func NewHTTPSink(name string, u *url.URL, timeout time.Duration, tlsInsecure bool) HTTPSink {
+	return HTTPSink{
+		name: name,
+		u:    u.String(),
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: tlsInsecure}, // nolint:gosec
+			},
+		},
+	}
+}
+
+func (sk HTTPSink) Name() string {
+	return sk.name
+}
+
+func (sk HTTPSink) Deliver(ctx context.Context, height base.Height, b []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sk.u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Mitum-Height", height.String())
+
+	res, err := sk.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to request webhook")
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("webhook failed; status=%d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// FileSink appends the block into file as one line.
+type FileSink struct {
+	sync.Mutex
+	name string
+	path string
+}
+
+func NewFileSink(name, path string) *FileSink {
+	return &FileSink{name: name, path: filepath.Clean(path)}
+}
+
+func (sk *FileSink) Name() string {
+	return sk.name
+}
+
+func (sk *FileSink) Deliver(_ context.Context, _ base.Height, b []byte) error {
+	sk.Lock()
+	defer sk.Unlock()
+
+	f, err := os.OpenFile(sk.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open sink file")
+	}
+
+	if _, err = f.Write(append(b, '\n')); err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return errors.Wrap(err, "failed to write sink file")
+}
+
+// CommandSink runs the local command with the block thru stdin. The height is
+// also set to "MITUM_HEIGHT" environment variable. The command exits with 0
+// is considered as delivered.
+type CommandSink struct {
+	name    string
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+func NewCommandSink(name, command string, args []string, timeout time.Duration) CommandSink {
+	return CommandSink{
+		name:    name,
+		command: command,
+		args:    args,
+		timeout: timeout,
+	}
+}
+
+func (sk CommandSink) Name() string {
+	return sk.name
+}
+
+func (sk CommandSink) Deliver(ctx context.Context, height base.Height, b []byte) error {
+	cctx, cancel := context.WithTimeout(ctx, sk.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cctx, sk.command, sk.args...) // nolint:gosec
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("MITUM_HEIGHT=%d", height))
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "failed to run sink command; output=%q", strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
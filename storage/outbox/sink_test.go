@@ -0,0 +1,109 @@
+package outbox
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSink struct {
+	suite.Suite
+}
+
+func (t *testSink) TestNewSinkFromURI() {
+	cases := []struct {
+		name string
+		uri  string
+		err  string
+	}{
+		{name: "http", uri: "https://localhost:8080/hook?timeout=3s&tls_insecure=true"},
+		{name: "file", uri: "file:///tmp/blocks.json"},
+		{name: "exec", uri: "exec:///bin/cat?arg=-"},
+		{name: "empty file", uri: "file://", err: "empty file path"},
+		{name: "unknown", uri: "ftp://localhost/", err: "unsupported sink uri"},
+		{name: "bad timeout", uri: "http://localhost/?timeout=1", err: "invalid timeout"},
+	}
+
+	for i, c := range cases {
+		u, err := url.Parse(c.uri)
+		t.NoError(err)
+
+		_, err = NewSinkFromURI(c.name, u)
+		if len(c.err) > 0 {
+			t.Error(err, "%d: %v", i, c.name)
+			t.Contains(err.Error(), c.err, "%d: %v", i, c.name)
+
+			continue
+		}
+
+		t.NoError(err, "%d: %v", i, c.name)
+	}
+}
+
+func (t *testSink) TestHTTP() {
+	var received []byte
+	var height string
+	status := http.StatusOK
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		height = r.Header.Get("X-Mitum-Height")
+
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL + "?timeout=1s")
+	sk, err := NewSinkFromURI("hook", u)
+	t.NoError(err)
+
+	t.NoError(sk.Deliver(context.Background(), base.Height(33), []byte("showme")))
+	t.Equal([]byte("showme"), received)
+	t.Equal("33", height)
+
+	status = http.StatusInternalServerError
+	err = sk.Deliver(context.Background(), base.Height(34), []byte("findme"))
+	t.Error(err)
+	t.Contains(err.Error(), "status=500")
+}
+
+func (t *testSink) TestFile() {
+	p := filepath.Join(t.T().TempDir(), "blocks.json")
+
+	sk := NewFileSink("file", p)
+	t.NoError(sk.Deliver(context.Background(), base.Height(33), []byte("showme")))
+	t.NoError(sk.Deliver(context.Background(), base.Height(34), []byte("findme")))
+
+	b, err := os.ReadFile(p)
+	t.NoError(err)
+	t.Equal("showme\nfindme\n", string(b))
+}
+
+func (t *testSink) TestCommand() {
+	p := filepath.Join(t.T().TempDir(), "blocks.json")
+
+	sk := NewCommandSink("cmd", "/bin/sh", []string{"-c", `cat > "$0"; echo "$MITUM_HEIGHT" >> "$0"`, p}, time.Second)
+	t.NoError(sk.Deliver(context.Background(), base.Height(33), []byte("showme")))
+
+	b, err := os.ReadFile(p)
+	t.NoError(err)
+	t.Equal("showme33\n", string(b))
+
+	sk = NewCommandSink("cmd", "/bin/sh", []string{"-c", "echo failed; exit 1"}, time.Second)
+	err = sk.Deliver(context.Background(), base.Height(33), []byte("showme"))
+	t.Error(err)
+	t.Contains(err.Error(), "failed")
+}
+
+func TestSink(t *testing.T) {
+	suite.Run(t, new(testSink))
+}
@@ -0,0 +1,211 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/network/events"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+var (
+	DefaultInterval      = time.Second * 10
+	DefaultRetryInterval = time.Second * 3
+	SinkInfoKeyPrefix    = "outbox_sink:"
+)
+
+// Worker delivers the blocks in outbox to the sinks by height order. The
+// block is removed from outbox after every sink receives it, so the sinks
+// receive the blocks at least once. The last delivered height of each sink is
+// stored, so the succeeded sink does not receive again when the other sink
+// fails.
+type Worker struct {
+	*logging.Logging
+	*util.ContextDaemon
+	db            storage.Database
+	loader        events.BlockEventLoader
+	sinks         []Sink
+	interval      time.Duration
+	retryInterval time.Duration
+	notifych      chan struct{}
+}
+
+func NewWorker(
+	db storage.Database,
+	loader events.BlockEventLoader,
+	sinks []Sink,
+	interval time.Duration,
+	retryInterval time.Duration,
+) *Worker {
+	if interval < 1 {
+		interval = DefaultInterval
+	}
+
+	if retryInterval < 1 {
+		retryInterval = DefaultRetryInterval
+	}
+
+	wk := &Worker{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "outbox-worker")
+		}),
+		db:            db,
+		loader:        loader,
+		sinks:         sinks,
+		interval:      interval,
+		retryInterval: retryInterval,
+		notifych:      make(chan struct{}, 1),
+	}
+
+	wk.ContextDaemon = util.NewContextDaemon("outbox-worker", wk.run)
+
+	return wk
+}
+
+func (wk *Worker) SetLogging(l *logging.Logging) *logging.Logging {
+	_ = wk.ContextDaemon.SetLogging(l)
+
+	return wk.Logging.SetLogging(l)
+}
+
+// Notify wakes up the worker to deliver the new blocks without waiting
+// interval.
+func (wk *Worker) Notify() {
+	select {
+	case wk.notifych <- struct{}{}:
+	default:
+	}
+}
+
+func (wk *Worker) run(ctx context.Context) error {
+	ticker := time.NewTicker(wk.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := wk.Deliver(ctx); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+
+			wk.Log().Error().Err(err).Msg("failed to deliver outbox")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-wk.notifych:
+		}
+	}
+}
+
+// Deliver delivers the blocks in outbox. When the sink fails, it retries until
+// the sink succeeds or the context is done.
+func (wk *Worker) Deliver(ctx context.Context) error {
+	var heights []base.Height
+	if err := wk.db.Outbox(func(height base.Height) (bool, error) {
+		heights = append(heights, height)
+
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	for i := range heights {
+		if err := wk.deliver(ctx, heights[i]); err != nil {
+			return err
+		}
+
+		if err := wk.db.RemoveOutbox([]base.Height{heights[i]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (wk *Worker) deliver(ctx context.Context, height base.Height) error {
+	l := wk.Log().With().Int64("height", height.Int64()).Logger()
+
+	if len(wk.sinks) < 1 {
+		return nil
+	}
+
+	ev, found, err := wk.loader(ctx, height)
+	switch {
+	case err != nil:
+		return err
+	case !found:
+		l.Warn().Msg("block of outbox not found; skipped")
+
+		return nil
+	}
+
+	b, err := jsonenc.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	for i := range wk.sinks {
+		sink := wk.sinks[i]
+
+		switch last, found, err := wk.lastDelivered(sink); {
+		case err != nil:
+			return err
+		case found && last >= height:
+			continue
+		}
+
+		if err := wk.deliverToSink(ctx, sink, height, b); err != nil {
+			return err
+		}
+
+		if err := wk.db.SetInfo(SinkInfoKeyPrefix+sink.Name(), util.Int64ToBytes(height.Int64())); err != nil {
+			return err
+		}
+
+		l.Debug().Str("sink", sink.Name()).Msg("block delivered")
+	}
+
+	return nil
+}
+
+func (wk *Worker) deliverToSink(ctx context.Context, sink Sink, height base.Height, b []byte) error {
+	for {
+		err := sink.Deliver(ctx, height, b)
+		if err == nil {
+			return nil
+		}
+
+		wk.Log().Error().Err(err).Str("sink", sink.Name()).Int64("height", height.Int64()).
+			Dur("retry_after", wk.retryInterval).Msg("failed to deliver block to sink; will retry")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wk.retryInterval):
+		}
+	}
+}
+
+func (wk *Worker) lastDelivered(sink Sink) (base.Height, bool, error) {
+	switch b, found, err := wk.db.Info(SinkInfoKeyPrefix + sink.Name()); {
+	case err != nil:
+		return base.NilHeight, false, err
+	case !found:
+		return base.NilHeight, false, nil
+	default:
+		i, err := util.BytesToInt64(b)
+		if err != nil {
+			return base.NilHeight, false, err
+		}
+
+		return base.Height(i), true, nil
+	}
+}
@@ -0,0 +1,223 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/network/events"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummyOutboxDatabase struct {
+	storage.Database
+	sync.Mutex
+	outbox map[base.Height]struct{}
+	info   map[string][]byte
+}
+
+func newDummyOutboxDatabase(heights ...base.Height) *dummyOutboxDatabase {
+	db := &dummyOutboxDatabase{
+		outbox: map[base.Height]struct{}{},
+		info:   map[string][]byte{},
+	}
+
+	for i := range heights {
+		db.outbox[heights[i]] = struct{}{}
+	}
+
+	return db
+}
+
+func (db *dummyOutboxDatabase) Outbox(callback func(base.Height) (bool, error)) error {
+	db.Lock()
+	heights := make([]base.Height, 0, len(db.outbox))
+	for h := range db.outbox {
+		heights = append(heights, h)
+	}
+	db.Unlock()
+
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	for i := range heights {
+		if keep, err := callback(heights[i]); err != nil {
+			return err
+		} else if !keep {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (db *dummyOutboxDatabase) RemoveOutbox(heights []base.Height) error {
+	db.Lock()
+	defer db.Unlock()
+
+	for i := range heights {
+		delete(db.outbox, heights[i])
+	}
+
+	return nil
+}
+
+func (db *dummyOutboxDatabase) Info(key string) ([]byte, bool, error) {
+	db.Lock()
+	defer db.Unlock()
+
+	b, found := db.info[key]
+
+	return b, found, nil
+}
+
+func (db *dummyOutboxDatabase) SetInfo(key string, b []byte) error {
+	db.Lock()
+	defer db.Unlock()
+
+	db.info[key] = b
+
+	return nil
+}
+
+func (db *dummyOutboxDatabase) len() int {
+	db.Lock()
+	defer db.Unlock()
+
+	return len(db.outbox)
+}
+
+type dummySink struct {
+	sync.Mutex
+	name    string
+	fails   int
+	heights []base.Height
+}
+
+func (sk *dummySink) Name() string {
+	return sk.name
+}
+
+func (sk *dummySink) Deliver(_ context.Context, height base.Height, _ []byte) error {
+	sk.Lock()
+	defer sk.Unlock()
+
+	if sk.fails > 0 {
+		sk.fails--
+
+		return errors.Errorf("showme")
+	}
+
+	sk.heights = append(sk.heights, height)
+
+	return nil
+}
+
+func (sk *dummySink) delivered() []base.Height {
+	sk.Lock()
+	defer sk.Unlock()
+
+	return sk.heights
+}
+
+type testWorker struct {
+	suite.Suite
+}
+
+func (t *testWorker) loader(_ context.Context, height base.Height) (events.BlockEvent, bool, error) {
+	blk, err := block.NewTestBlockV0(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	return events.NewBlockEvent(blk), true, nil
+}
+
+func (t *testWorker) TestDeliver() {
+	db := newDummyOutboxDatabase(3, 1, 2)
+
+	ska := &dummySink{name: "a"}
+	skb := &dummySink{name: "b"}
+
+	wk := NewWorker(db, t.loader, []Sink{ska, skb}, 0, time.Millisecond)
+	t.NoError(wk.Deliver(context.Background()))
+
+	t.Equal([]base.Height{1, 2, 3}, ska.delivered())
+	t.Equal([]base.Height{1, 2, 3}, skb.delivered())
+	t.Equal(0, db.len())
+}
+
+func (t *testWorker) TestRetry() {
+	db := newDummyOutboxDatabase(1, 2)
+
+	ska := &dummySink{name: "a"}
+	skb := &dummySink{name: "b", fails: 3}
+
+	wk := NewWorker(db, t.loader, []Sink{ska, skb}, 0, time.Millisecond)
+	t.NoError(wk.Deliver(context.Background()))
+
+	t.Equal([]base.Height{1, 2}, ska.delivered())
+	t.Equal([]base.Height{1, 2}, skb.delivered())
+	t.Equal(0, db.len())
+}
+
+func (t *testWorker) TestFailedSinkKeepsOutbox() {
+	db := newDummyOutboxDatabase(1, 2)
+
+	ska := &dummySink{name: "a"}
+	skb := &dummySink{name: "b", fails: 1 << 20}
+
+	wk := NewWorker(db, t.loader, []Sink{ska, skb}, 0, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err := wk.Deliver(ctx)
+	t.True(errors.Is(err, context.DeadlineExceeded))
+
+	t.Equal([]base.Height{1}, ska.delivered())
+	t.Empty(skb.delivered())
+	t.Equal(2, db.len())
+
+	// NOTE the succeeded sink does not receive the same block again
+	skb.fails = 0
+	t.NoError(wk.Deliver(context.Background()))
+
+	t.Equal([]base.Height{1, 2}, ska.delivered())
+	t.Equal([]base.Height{1, 2}, skb.delivered())
+	t.Equal(0, db.len())
+}
+
+func (t *testWorker) TestNotify() {
+	db := newDummyOutboxDatabase()
+
+	ska := &dummySink{name: "a"}
+
+	wk := NewWorker(db, t.loader, []Sink{ska}, time.Hour, time.Millisecond)
+	t.NoError(wk.Start())
+	defer func() {
+		_ = wk.Stop()
+	}()
+
+	<-time.After(time.Millisecond * 100)
+
+	db.Lock()
+	db.outbox[base.Height(3)] = struct{}{}
+	db.Unlock()
+
+	wk.Notify()
+
+	t.Eventually(func() bool {
+		return db.len() < 1
+	}, time.Second*2, time.Millisecond*10)
+
+	t.Equal([]base.Height{3}, ska.delivered())
+}
+
+func TestWorker(t *testing.T) {
+	suite.Run(t, new(testWorker))
+}
@@ -2,13 +2,16 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
 
@@ -38,14 +41,56 @@ type Database interface {
 	Proposals(func(base.Proposal) (bool, error), bool /* sort */) error
 
 	State(key string) (state.State, bool, error)
+	// StateAt returns the state, which was stored at or before the given height.
+	StateAt(key string, height base.Height) (state.State, bool, error)
+	// StateHistory iterates the states of key, which were stored between from
+	// and to heights, by height order.
+	StateHistory(key string, from, to base.Height, callback func(state.State) (bool, error)) error
+	// States iterates the last states of every key, which were stored at or
+	// before the given height, by key order.
+	States(height base.Height, callback func(state.State) (bool, error)) error
 	LastVoteproof(base.Stage) base.Voteproof
 	Voteproof(base.Height, base.Stage) (base.Voteproof, error)
 
 	HasOperationFact(valuehash.Hash) (bool, error)
+	// OperationsBySigner returns the operations of stored blocks, which are
+	// signed by the given publickey, after cursor by height and index order.
+	// The next cursor is empty when no more operations.
+	OperationsBySigner(
+		signer key.Publickey, cursor OperationCursor, limit int64,
+	) ([]operation.Operation, OperationCursor, error)
+	// OperationsByHint returns the operations of stored blocks, which have
+	// the given hint type and were stored between from and to heights, after
+	// cursor by height and index order.
+	OperationsByHint(
+		ht hint.Type, from, to base.Height, cursor OperationCursor, limit int64,
+	) ([]operation.Operation, OperationCursor, error)
+
+	// SetOperationStatuses stores the status of operations; the status, which
+	// can not be updated by operation.StatusKind.CanUpdate, is ignored.
+	SetOperationStatuses([]operation.StatusV0) error
+	OperationStatus(valuehash.Hash /* fact hash */) (operation.StatusV0, bool, error)
+
+	// SetEquivocation stores the evidence of double vote; the evidence of the
+	// same node, height, round and stage is stored only once.
+	SetEquivocation(base.EquivocationV0) error
+	// Equivocations iterates the evidences of double vote by height order; if
+	// sort is false, the evidences of higher height come first.
+	Equivocations(func(base.EquivocationV0) (bool, error), bool /* sort */) error
+
+	// Outbox iterates the heights of the committed blocks, which are not yet
+	// delivered to the outbox sinks, by height order. The height is added to
+	// outbox with the block in DatabaseSession.Commit.
+	Outbox(func(base.Height) (bool, error)) error
+	// RemoveOutbox removes the delivered heights from outbox.
+	RemoveOutbox([]base.Height) error
 
 	// NOTE StagedOperationOperations returns operation.Operation by incoming order.
 	StagedOperationsByFact(facts []valuehash.Hash) ([]operation.Operation, error)
 	HasStagedOperation(valuehash.Hash) (bool, error)
+	// StagedOperationTime returns the time when the operation of the fact hash
+	// was staged.
+	StagedOperationTime(valuehash.Hash) (time.Time, bool, error)
 	StagedOperations(func(operation.Operation) (bool, error), bool /* sort */) error
 	UnstagedOperations([]valuehash.Hash /* operation.Fact().Hash()s */) error
 
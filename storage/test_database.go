@@ -0,0 +1,904 @@
+//go:build test
+// +build test
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// DatabaseTestSuite is the common test suite of Database; the database
+// backends run it with their own Database. The backend should set Database
+// and NewDatabase before each test.
+type DatabaseTestSuite struct {
+	BaseTestDatabase
+	Database interface {
+		Database
+		StateUpdater
+	}
+	// NewDatabase returns new empty Database of same backend; it is used for
+	// the destination of Copy. The backend should close it after test.
+	NewDatabase func() Database
+}
+
+func (t *DatabaseTestSuite) NewBlock(height base.Height, sts []state.State, facts []valuehash.Hash) block.Block {
+	blk, err := block.NewTestBlockV0(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	i := (interface{})(blk).(block.BlockUpdater)
+	i = i.SetINITVoteproof(base.NewVoteproofV0(blk.Height(), blk.Round(), nil, base.ThresholdRatio(100), base.StageINIT))
+	i = i.SetACCEPTVoteproof(base.NewVoteproofV0(blk.Height(), blk.Round(), nil, base.ThresholdRatio(100), base.StageACCEPT))
+
+	if len(sts) > 0 {
+		i = i.SetStates(sts)
+	}
+
+	if len(facts) > 0 {
+		tg := tree.NewFixedTreeGenerator(uint64(len(facts)))
+		for j := range facts {
+			t.NoError(tg.Add(operation.NewFixedTreeNode(uint64(j), facts[j].Bytes(), true, nil)))
+		}
+
+		tr, err := tg.Tree()
+		t.NoError(err)
+
+		i = i.SetOperationsTree(tr)
+	}
+
+	return i.(block.BlockV0)
+}
+
+func (t *DatabaseTestSuite) SaveNewBlock(
+	height base.Height, sts []state.State, facts []valuehash.Hash,
+) (block.Block, block.BlockdataMap) {
+	blk := t.NewBlock(height, sts, facts)
+
+	bs, err := t.Database.NewSession(blk)
+	t.NoError(err)
+
+	t.NoError(bs.SetBlock(context.Background(), blk))
+	bd := t.NewBlockdataMap(blk.Height(), blk.Hash(), true)
+	t.NoError(bs.Commit(context.Background(), bd))
+
+	return blk, bd
+}
+
+func (t *DatabaseTestSuite) SaveBlockWithOperations(height base.Height, ops []operation.Operation) block.Block {
+	facts := make([]valuehash.Hash, len(ops))
+	for i := range ops {
+		facts[i] = ops[i].Fact().Hash()
+	}
+
+	blk := (interface{})(t.NewBlock(height, nil, facts)).(block.BlockUpdater).SetOperations(ops).(block.Block)
+
+	bs, err := t.Database.NewSession(blk)
+	t.NoError(err)
+
+	t.NoError(bs.SetBlock(context.Background(), blk))
+	t.NoError(bs.Commit(context.Background(), t.NewBlockdataMap(blk.Height(), blk.Hash(), true)))
+
+	return blk
+}
+
+func (t *DatabaseTestSuite) NewState(key string, height base.Height) state.State {
+	v, err := state.NewBytesValue(util.UUID().Bytes())
+	t.NoError(err)
+
+	st, err := state.NewStateV0(key, v, height)
+	t.NoError(err)
+
+	nst, err := st.SetHash(st.GenerateHash())
+	t.NoError(err)
+
+	return nst
+}
+
+func (t *DatabaseTestSuite) NewOperation(pk key.Privatekey) operation.Operation {
+	op, err := operation.NewKVOperation(pk, []byte("this-is-token"), util.UUID().String(), util.UUID().Bytes(), nil)
+	t.NoError(err)
+
+	return op
+}
+
+func (t *DatabaseTestSuite) NewOperationSeal() operation.Seal {
+	sl, err := operation.NewBaseSeal(t.PK, []operation.Operation{t.NewOperation(t.PK)}, nil)
+	t.NoError(err)
+	t.NoError(sl.IsValid(nil))
+
+	return sl
+}
+
+// NewOperationSeals stores new seals as staged operations and returns the
+// operations of them.
+func (t *DatabaseTestSuite) NewOperationSeals(n int) []operation.Operation {
+	var ops []operation.Operation
+	var seals []operation.Seal
+	for i := 0; i < n; i++ {
+		sl := t.NewOperationSeal()
+		seals = append(seals, sl)
+
+		ops = append(ops, sl.Operations()...)
+	}
+	t.NoError(t.Database.NewOperationSeals(seals))
+
+	return ops
+}
+
+func (t *DatabaseTestSuite) CompareOperations(a, b []operation.Operation) {
+	t.Equal(len(a), len(b))
+
+	for i := range a {
+		t.True(a[i].Fact().Hash().Equal(b[i].Fact().Hash()))
+	}
+}
+
+func (t *DatabaseTestSuite) TestLastBlock() {
+	blk, bd := t.SaveNewBlock(base.Height(33), nil, nil)
+
+	loaded, found, err := t.Database.LastManifest()
+	t.NoError(err)
+	t.True(found)
+
+	t.CompareManifest(blk.Manifest(), loaded)
+
+	ubd, found, err := t.Database.BlockdataMap(blk.Height())
+	t.NoError(err)
+	t.True(found)
+
+	block.CompareBlockdataMap(t.Assert(), bd, ubd)
+}
+
+func (t *DatabaseTestSuite) TestLoadManifestByHash() {
+	blk, _ := t.SaveNewBlock(base.Height(33), nil, nil)
+
+	loaded, found, err := t.Database.Manifest(blk.Hash())
+	t.NoError(err)
+	t.True(found)
+
+	t.Implements((*block.Manifest)(nil), loaded)
+	_, isBlock := loaded.(block.Block)
+	t.False(isBlock)
+
+	t.CompareManifest(blk, loaded)
+}
+
+func (t *DatabaseTestSuite) TestLoadManifestByHeight() {
+	blk, _ := t.SaveNewBlock(base.Height(33), nil, nil)
+
+	loaded, found, err := t.Database.ManifestByHeight(blk.Height())
+	t.NoError(err)
+	t.True(found)
+
+	t.Implements((*block.Manifest)(nil), loaded)
+	_, isBlock := loaded.(block.Block)
+	t.False(isBlock)
+
+	t.CompareManifest(blk, loaded)
+}
+
+func (t *DatabaseTestSuite) TestSetBlockContext() {
+	blk := t.NewBlock(base.Height(33), nil, nil)
+
+	bs, err := t.Database.NewSession(blk)
+	t.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+
+	<-ctx.Done()
+
+	err = bs.SetBlock(ctx, blk)
+	t.True(errors.Is(err, context.DeadlineExceeded))
+
+	_, found, err := t.Database.ManifestByHeight(blk.Height())
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *DatabaseTestSuite) TestSaveBlockContext() {
+	blk := t.NewBlock(base.Height(33), nil, nil)
+
+	bs, err := t.Database.NewSession(blk)
+	t.NoError(err)
+
+	t.NoError(bs.SetBlock(context.Background(), blk))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+
+	<-ctx.Done()
+
+	bd := t.NewBlockdataMap(blk.Height(), blk.Hash(), true)
+	err = bs.Commit(ctx, bd)
+	t.True(errors.Is(err, context.DeadlineExceeded))
+
+	_, found, err := t.Database.ManifestByHeight(blk.Height())
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *DatabaseTestSuite) TestNewOperationSeals() {
+	ops := t.NewOperationSeals(10)
+
+	var collected []operation.Operation
+	t.NoError(t.Database.StagedOperations(
+		func(op operation.Operation) (bool, error) {
+			collected = append(collected, op)
+
+			t.NoError(op.IsValid(nil))
+
+			return true, nil
+		},
+		true,
+	))
+
+	t.Equal(len(ops), len(collected))
+
+	for i := range collected {
+		a := ops[i]
+		b := collected[i]
+
+		found, err := t.Database.HasStagedOperation(b.Fact().Hash())
+		t.NoError(err)
+		t.True(found)
+
+		t.True(a.Hash().Equal(b.Hash()))
+		t.True(a.Fact().Hash().Equal(b.Fact().Hash()))
+	}
+}
+
+func (t *DatabaseTestSuite) TestStagedOperationsByFact() {
+	ops := t.NewOperationSeals(10)
+
+	var facts []valuehash.Hash
+	for i := range ops[:3] {
+		facts = append(facts, ops[i].Fact().Hash())
+	}
+
+	rops, err := t.Database.StagedOperationsByFact(facts)
+	t.NoError(err)
+
+	t.Equal(3, len(rops))
+
+	for i := range rops {
+		op := rops[i]
+
+		t.True(facts[i].Equal(op.Fact().Hash()))
+	}
+
+	l, err := t.Database.StagedOperationsByFact([]valuehash.Hash{valuehash.RandomSHA256()})
+	t.NoError(err)
+	t.Equal(0, len(l))
+}
+
+func (t *DatabaseTestSuite) TestUnstagedOperations() {
+	ops := t.NewOperationSeals(10)
+
+	var facts []valuehash.Hash
+	for i := range ops[:3] {
+		facts = append(facts, ops[i].Fact().Hash())
+	}
+
+	for i := range facts {
+		_, found, err := t.Database.StagedOperationTime(facts[i])
+		t.NoError(err)
+		t.True(found)
+	}
+
+	t.NoError(t.Database.UnstagedOperations(facts))
+
+	for i := range facts {
+		found, err := t.Database.HasStagedOperation(facts[i])
+		t.NoError(err)
+		t.False(found)
+
+		_, found, err = t.Database.StagedOperationTime(facts[i])
+		t.NoError(err)
+		t.False(found)
+	}
+
+	l, err := t.Database.StagedOperationsByFact(facts)
+	t.NoError(err)
+	t.Equal(0, len(l))
+
+	// NOTE the other operations are still staged
+	for i := range ops[3:] {
+		found, err := t.Database.HasStagedOperation(ops[3+i].Fact().Hash())
+		t.NoError(err)
+		t.True(found)
+	}
+}
+
+func (t *DatabaseTestSuite) TestStagedOperationsLimit() {
+	ops := t.NewOperationSeals(10)
+
+	var collected []valuehash.Hash
+	t.NoError(t.Database.StagedOperations(
+		func(op operation.Operation) (bool, error) {
+			if len(collected) == 3 {
+				return false, nil
+			}
+			collected = append(collected, op.Fact().Hash())
+
+			return true, nil
+		},
+		true,
+	))
+
+	t.Equal(3, len(collected))
+
+	for i := range collected {
+		a := ops[i].Fact().Hash()
+		b := collected[i]
+
+		t.True(a.Equal(b))
+	}
+}
+
+func (t *DatabaseTestSuite) TestHasOperation() {
+	op := t.NewOperation(t.PK)
+	_ = t.SaveBlockWithOperations(base.Height(33), []operation.Operation{op})
+
+	{
+		found, err := t.Database.HasOperationFact(op.Fact().Hash())
+		t.NoError(err)
+		t.True(found)
+	}
+
+	{ // unknown
+		found, err := t.Database.HasOperationFact(valuehash.RandomSHA256())
+		t.NoError(err)
+		t.False(found)
+	}
+}
+
+func (t *DatabaseTestSuite) TestOperationStatus() {
+	fact := valuehash.RandomSHA256()
+
+	_, found, err := t.Database.OperationStatus(fact)
+	t.NoError(err)
+	t.False(found)
+
+	staged := operation.NewStatusV0(fact, operation.StatusStaged, base.NilHeight, base.Round(0), "", localtime.UTCNow())
+	t.NoError(t.Database.SetOperationStatuses([]operation.StatusV0{staged}))
+
+	ust, found, err := t.Database.OperationStatus(fact)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(operation.StatusStaged, ust.Status())
+
+	rejected := operation.NewStatusV0(fact, operation.StatusRejected, base.Height(33), base.Round(1), "showme", localtime.UTCNow())
+	t.NoError(t.Database.SetOperationStatuses([]operation.StatusV0{rejected}))
+
+	ust, found, err = t.Database.OperationStatus(fact)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(operation.StatusRejected, ust.Status())
+	t.Equal(base.Height(33), ust.Height())
+	t.Equal(base.Round(1), ust.Round())
+	t.Equal("showme", ust.Reason())
+
+	// NOTE final status is not overwritten
+	proposed := operation.NewStatusV0(fact, operation.StatusProposed, base.Height(34), base.Round(0), "", localtime.UTCNow())
+	t.NoError(t.Database.SetOperationStatuses([]operation.StatusV0{proposed}))
+
+	ust, found, err = t.Database.OperationStatus(fact)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(operation.StatusRejected, ust.Status())
+}
+
+func (t *DatabaseTestSuite) TestOperationsBySigner() {
+	other := key.NewBasePrivatekey()
+
+	ops := []operation.Operation{t.NewOperation(t.PK), t.NewOperation(other), t.NewOperation(t.PK)}
+	_ = t.SaveBlockWithOperations(base.Height(1), ops)
+
+	nops := []operation.Operation{t.NewOperation(t.PK)}
+	_ = t.SaveBlockWithOperations(base.Height(2), nops)
+
+	uops, next, err := t.Database.OperationsBySigner(t.PK.Publickey(), NilOperationCursor, 2)
+	t.NoError(err)
+	t.CompareOperations([]operation.Operation{ops[0], ops[2]}, uops)
+	t.Equal(NewOperationCursor(base.Height(1), 2), next)
+
+	uops, next, err = t.Database.OperationsBySigner(t.PK.Publickey(), next, 2)
+	t.NoError(err)
+	t.CompareOperations(nops, uops)
+	t.True(next.IsNil())
+
+	uops, next, err = t.Database.OperationsBySigner(other.Publickey(), NilOperationCursor, 0)
+	t.NoError(err)
+	t.CompareOperations([]operation.Operation{ops[1]}, uops)
+	t.True(next.IsNil())
+
+	// NOTE operations of cleaned block are removed
+	t.NoError(t.Database.CleanByHeight(base.Height(2)))
+
+	uops, next, err = t.Database.OperationsBySigner(t.PK.Publickey(), NilOperationCursor, 0)
+	t.NoError(err)
+	t.CompareOperations([]operation.Operation{ops[0], ops[2]}, uops)
+	t.True(next.IsNil())
+}
+
+func (t *DatabaseTestSuite) TestOperationsByHint() {
+	ops := []operation.Operation{t.NewOperation(t.PK), t.NewOperation(t.PK), t.NewOperation(t.PK)}
+	_ = t.SaveBlockWithOperations(base.Height(1), ops)
+
+	nops := []operation.Operation{t.NewOperation(t.PK)}
+	_ = t.SaveBlockWithOperations(base.Height(2), nops)
+
+	ht := ops[0].Hint().Type()
+
+	uops, next, err := t.Database.OperationsByHint(ht, base.Height(1), base.Height(2), NilOperationCursor, 3)
+	t.NoError(err)
+	t.CompareOperations(ops, uops)
+	t.Equal(NewOperationCursor(base.Height(1), 2), next)
+
+	uops, next, err = t.Database.OperationsByHint(ht, base.Height(1), base.Height(2), next, 3)
+	t.NoError(err)
+	t.CompareOperations(nops, uops)
+	t.True(next.IsNil())
+
+	// NOTE by height range
+	uops, next, err = t.Database.OperationsByHint(ht, base.Height(2), base.Height(2), NilOperationCursor, 0)
+	t.NoError(err)
+	t.CompareOperations(nops, uops)
+	t.True(next.IsNil())
+
+	// NOTE unknown hint type
+	uops, next, err = t.Database.OperationsByHint(
+		hint.Type("unknown-operation"), base.Height(1), base.Height(2), NilOperationCursor, 0)
+	t.NoError(err)
+	t.Empty(uops)
+	t.True(next.IsNil())
+
+	_, _, err = t.Database.OperationsByHint(ht, base.Height(2), base.Height(1), NilOperationCursor, 0)
+	t.Error(err)
+	t.Contains(err.Error(), "invalid height range")
+}
+
+func (t *DatabaseTestSuite) TestInfo() {
+	key := util.UUID().String()
+	b := util.UUID().Bytes()
+
+	_, found, err := t.Database.Info(key)
+	t.NoError(err)
+	t.False(found)
+
+	t.NoError(t.Database.SetInfo(key, b))
+
+	ub, found, err := t.Database.Info(key)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(b, ub)
+
+	nb := util.UUID().Bytes()
+	t.NoError(t.Database.SetInfo(key, nb))
+
+	unb, found, err := t.Database.Info(key)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(nb, unb)
+}
+
+func (t *DatabaseTestSuite) TestLocalBlockdataMapsByHeight() {
+	isLocal := func(height base.Height) bool {
+		switch height {
+		case 33, 36, 39:
+			return true
+		default:
+			return false
+		}
+	}
+
+	var bds []block.BlockdataMap
+	for i := base.Height(33); i < 40; i++ {
+		bds = append(bds, t.NewBlockdataMap(i, valuehash.RandomSHA256(), isLocal(i)))
+	}
+
+	t.NoError(t.Database.SetBlockdataMaps(bds))
+
+	for i := base.Height(33); i < 40; i++ {
+		bd, found, err := t.Database.BlockdataMap(i)
+		t.NoError(err)
+		t.True(found)
+		t.NotNil(bd)
+	}
+
+	var heights []base.Height
+	err := t.Database.LocalBlockdataMapsByHeight(36, func(bd block.BlockdataMap) (bool, error) {
+		t.True(bd.Height() > 35)
+		t.True(isLocal(bd.Height()))
+		t.True(bd.IsLocal())
+
+		heights = append(heights, bd.Height())
+
+		return true, nil
+	})
+	t.NoError(err)
+	t.Equal([]base.Height{36, 39}, heights)
+}
+
+func (t *DatabaseTestSuite) TestState() {
+	key := util.UUID().String()
+
+	_, found, err := t.Database.State(key)
+	t.NoError(err)
+	t.False(found)
+
+	sta33 := t.NewState(key, base.Height(33))
+	_, _ = t.SaveNewBlock(base.Height(33), []state.State{sta33}, nil)
+
+	sta34 := t.NewState(key, base.Height(34))
+	_, _ = t.SaveNewBlock(base.Height(34), []state.State{sta34}, nil)
+
+	ust, found, err := t.Database.State(key)
+	t.NoError(err)
+	t.True(found)
+	t.True(sta34.Hash().Equal(ust.Hash()))
+	t.Equal(sta34.Height(), ust.Height())
+
+	t.NoError(t.Database.CleanByHeight(base.Height(34)))
+
+	ust, found, err = t.Database.State(key)
+	t.NoError(err)
+	t.True(found)
+	t.True(sta33.Hash().Equal(ust.Hash()))
+	t.Equal(sta33.Height(), ust.Height())
+
+	{ // NOTE similar key
+		_, found, err := t.Database.State(key[:len(key)-1])
+		t.NoError(err)
+		t.False(found)
+	}
+}
+
+func (t *DatabaseTestSuite) TestStateAt() {
+	key := util.UUID().String()
+
+	_, found, err := t.Database.StateAt(key, base.Height(33))
+	t.NoError(err)
+	t.False(found)
+
+	sts := map[base.Height]state.State{}
+	for _, height := range []base.Height{33, 36, 39} {
+		sta := t.NewState(key, height)
+		sts[height] = sta
+
+		_, _ = t.SaveNewBlock(height, []state.State{sta}, nil)
+	}
+
+	{ // NOTE before first state
+		_, found, err := t.Database.StateAt(key, base.Height(32))
+		t.NoError(err)
+		t.False(found)
+	}
+
+	cases := map[base.Height]base.Height{
+		33: 33,
+		34: 33,
+		35: 33,
+		36: 36,
+		38: 36,
+		39: 39,
+		40: 39,
+		99: 39,
+	}
+
+	for height, expected := range cases {
+		ust, found, err := t.Database.StateAt(key, height)
+		t.NoError(err)
+		t.True(found, "height=%d", height)
+		t.Equal(expected, ust.Height(), "height=%d", height)
+		t.True(sts[expected].Hash().Equal(ust.Hash()), "height=%d", height)
+	}
+
+	{ // NOTE similar key
+		_, found, err := t.Database.StateAt(key[:len(key)-1], base.Height(39))
+		t.NoError(err)
+		t.False(found)
+	}
+}
+
+func (t *DatabaseTestSuite) TestStateHistory() {
+	key := util.UUID().String()
+
+	sts := map[base.Height]state.State{}
+	for _, height := range []base.Height{33, 36, 39, 42} {
+		sta := t.NewState(key, height)
+		sts[height] = sta
+
+		_, _ = t.SaveNewBlock(height, []state.State{sta}, nil)
+	}
+
+	_, _ = t.SaveNewBlock(base.Height(43), []state.State{t.NewState(key+"0", base.Height(43))}, nil)
+
+	history := func(from, to base.Height) []base.Height {
+		var heights []base.Height
+		t.NoError(t.Database.StateHistory(key, from, to, func(st state.State) (bool, error) {
+			t.Equal(key, st.Key())
+			t.True(sts[st.Height()].Hash().Equal(st.Hash()))
+
+			heights = append(heights, st.Height())
+
+			return true, nil
+		}))
+
+		return heights
+	}
+
+	t.Equal([]base.Height{33, 36, 39, 42}, history(base.Height(0), base.Height(100)))
+	t.Equal([]base.Height{36, 39}, history(base.Height(34), base.Height(41)))
+	t.Equal([]base.Height{36, 39}, history(base.Height(36), base.Height(39)))
+	t.Equal([]base.Height{42}, history(base.Height(42), base.Height(42)))
+	t.Empty(history(base.Height(43), base.Height(100)))
+
+	{ // NOTE stop iteration
+		var heights []base.Height
+		t.NoError(t.Database.StateHistory(key, base.Height(0), base.Height(100), func(st state.State) (bool, error) {
+			heights = append(heights, st.Height())
+
+			return len(heights) < 2, nil
+		}))
+		t.Equal([]base.Height{33, 36}, heights)
+	}
+
+	{ // NOTE wrong range
+		err := t.Database.StateHistory(key, base.Height(40), base.Height(39), func(state.State) (bool, error) {
+			return true, nil
+		})
+		t.Error(err)
+		t.Contains(err.Error(), "invalid height range")
+	}
+}
+
+func (t *DatabaseTestSuite) TestStatesOverLastBlock() {
+	t.CheckStatesOverLastBlock(t.Database, func(height base.Height) {
+		_, _ = t.SaveNewBlock(height, nil, nil)
+	})
+}
+
+func (t *DatabaseTestSuite) TestStates() {
+	keys := []string{util.UUID().String(), util.UUID().String(), util.UUID().String()}
+	sort.Strings(keys)
+
+	sts := map[string]state.State{}
+	for i, height := range []base.Height{33, 34, 35, 36, 37} {
+		key := keys[i%len(keys)]
+		sta := t.NewState(key, height)
+		sts[fmt.Sprintf("%s-%d", key, height)] = sta
+
+		_, _ = t.SaveNewBlock(height, []state.State{sta}, nil)
+	}
+
+	states := func(height base.Height) []string {
+		var found []string
+		t.NoError(t.Database.States(height, func(st state.State) (bool, error) {
+			k := fmt.Sprintf("%s-%d", st.Key(), st.Height())
+			t.True(sts[k].Hash().Equal(st.Hash()))
+
+			found = append(found, k)
+
+			return true, nil
+		}))
+
+		return found
+	}
+
+	t.Empty(states(base.Height(32)))
+	t.Equal([]string{keys[0] + "-33"}, states(base.Height(33)))
+	t.Equal([]string{keys[0] + "-33", keys[1] + "-34", keys[2] + "-35"}, states(base.Height(35)))
+	t.Equal([]string{keys[0] + "-36", keys[1] + "-34", keys[2] + "-35"}, states(base.Height(36)))
+	t.Equal([]string{keys[0] + "-36", keys[1] + "-37", keys[2] + "-35"}, states(base.Height(99)))
+
+	{ // NOTE stop iteration
+		var found []string
+		t.NoError(t.Database.States(base.Height(99), func(st state.State) (bool, error) {
+			found = append(found, st.Key())
+
+			return len(found) < 2, nil
+		}))
+		t.Equal(keys[:2], found)
+	}
+}
+
+func (t *DatabaseTestSuite) TestVoteproof() {
+	blk, _ := t.SaveNewBlock(base.Height(33), nil, nil)
+
+	ivp, err := t.Database.Voteproof(blk.Height(), base.StageINIT)
+	t.NoError(err)
+	t.NotNil(ivp)
+	t.Equal(blk.Height(), ivp.Height())
+	t.Equal(base.StageINIT, ivp.Stage())
+
+	avp, err := t.Database.Voteproof(blk.Height(), base.StageACCEPT)
+	t.NoError(err)
+	t.NotNil(avp)
+	t.Equal(blk.Height(), avp.Height())
+	t.Equal(base.StageACCEPT, avp.Stage())
+
+	lavp := t.Database.LastVoteproof(base.StageACCEPT)
+	t.NotNil(lavp)
+	t.Equal(blk.Height(), lavp.Height())
+
+	// NOTE unknown height
+	uvp, err := t.Database.Voteproof(blk.Height()+1, base.StageINIT)
+	t.NoError(err)
+	t.Nil(uvp)
+}
+
+func (t *DatabaseTestSuite) TestCleanByHeight() {
+	var blks []block.Block
+	var facts []valuehash.Hash
+	for i := base.Height(33); i < 37; i++ {
+		fact := valuehash.RandomSHA256()
+		blk, _ := t.SaveNewBlock(i, nil, []valuehash.Hash{fact})
+
+		blks = append(blks, blk)
+		facts = append(facts, fact)
+	}
+
+	t.NoError(t.Database.CleanByHeight(base.Height(35)))
+
+	m, found, err := t.Database.LastManifest()
+	t.NoError(err)
+	t.True(found)
+	t.CompareManifest(blks[1], m)
+
+	for i := range blks {
+		blk := blks[i]
+		shouldExist := blk.Height() < 35
+
+		_, found, err := t.Database.ManifestByHeight(blk.Height())
+		t.NoError(err)
+		t.Equal(shouldExist, found)
+
+		_, found, err = t.Database.Manifest(blk.Hash())
+		t.NoError(err)
+		t.Equal(shouldExist, found)
+
+		_, found, err = t.Database.BlockdataMap(blk.Height())
+		t.NoError(err)
+		t.Equal(shouldExist, found)
+
+		vp, err := t.Database.Voteproof(blk.Height(), base.StageACCEPT)
+		t.NoError(err)
+		t.Equal(shouldExist, vp != nil)
+
+		found, err = t.Database.HasOperationFact(facts[i])
+		t.NoError(err)
+		t.Equal(shouldExist, found)
+	}
+
+	lavp := t.Database.LastVoteproof(base.StageACCEPT)
+	t.NotNil(lavp)
+	t.Equal(base.Height(34), lavp.Height())
+}
+
+func (t *DatabaseTestSuite) TestCopy() {
+	blk, _ := t.SaveNewBlock(base.Height(33), nil, nil)
+
+	other := t.NewDatabase()
+	t.NoError(other.Copy(t.Database))
+
+	m, found, err := other.LastManifest()
+	t.NoError(err)
+	t.True(found)
+	t.CompareManifest(blk, m)
+}
+
+func (t *DatabaseTestSuite) TestSyncerSession() {
+	sst, err := t.Database.NewSyncerSession()
+	t.NoError(err)
+
+	defer sst.Close()
+
+	var blks []block.Block
+	var bds []block.BlockdataMap
+	for i := base.Height(33); i < 36; i++ {
+		blk := t.NewBlock(i, nil, nil)
+
+		blks = append(blks, blk)
+		bds = append(bds, t.NewBlockdataMap(blk.Height(), blk.Hash(), true))
+	}
+
+	t.NoError(sst.SetBlocks(blks, bds))
+
+	found, err := sst.HasBlock(blks[0].Height())
+	t.NoError(err)
+	t.True(found)
+
+	_, found, err = t.Database.LastManifest()
+	t.NoError(err)
+	t.False(found)
+
+	t.NoError(sst.Commit())
+
+	m, found, err := t.Database.LastManifest()
+	t.NoError(err)
+	t.True(found)
+	t.CompareManifest(blks[2], m)
+}
+
+func (t *DatabaseTestSuite) TestOutbox() {
+	for i := base.Height(0); i < 5; i++ {
+		_, _ = t.SaveNewBlock(i, nil, nil)
+	}
+
+	outbox := func() []base.Height {
+		var heights []base.Height
+		t.NoError(t.Database.Outbox(func(height base.Height) (bool, error) {
+			heights = append(heights, height)
+
+			return true, nil
+		}))
+
+		return heights
+	}
+
+	t.Equal([]base.Height{0, 1, 2, 3, 4}, outbox())
+
+	t.NoError(t.Database.RemoveOutbox([]base.Height{0, 2}))
+	t.Equal([]base.Height{1, 3, 4}, outbox())
+
+	t.NoError(t.Database.CleanByHeight(base.Height(3)))
+	t.Equal([]base.Height{1}, outbox())
+}
+
+func (t *DatabaseTestSuite) TestEquivocation() {
+	n := base.RandomStringAddress()
+
+	evs := []base.EquivocationV0{
+		t.NewEquivocation(n, base.Height(3), base.Round(0), base.StageINIT),
+		t.NewEquivocation(n, base.Height(1), base.Round(0), base.StageACCEPT),
+		t.NewEquivocation(n, base.Height(2), base.Round(1), base.StageINIT),
+	}
+
+	for i := range evs {
+		t.NoError(t.Database.SetEquivocation(evs[i]))
+	}
+
+	// NOTE the evidence of same node, height, round and stage is stored once
+	t.NoError(t.Database.SetEquivocation(t.NewEquivocation(n, base.Height(1), base.Round(0), base.StageACCEPT)))
+
+	equivocations := func(sort bool) []base.EquivocationV0 {
+		var l []base.EquivocationV0
+		t.NoError(t.Database.Equivocations(func(ev base.EquivocationV0) (bool, error) {
+			l = append(l, ev)
+
+			return true, nil
+		}, sort))
+
+		return l
+	}
+
+	l := equivocations(true)
+	t.Equal(3, len(l))
+	t.Equal([]base.Height{1, 2, 3}, []base.Height{l[0].Height(), l[1].Height(), l[2].Height()})
+	t.True(evs[1].First().Fact().Hash().Equal(l[0].First().Fact().Hash()))
+	t.True(evs[1].Second().Fact().Hash().Equal(l[0].Second().Fact().Hash()))
+
+	l = equivocations(false)
+	t.Equal(3, len(l))
+	t.Equal([]base.Height{3, 2, 1}, []base.Height{l[0].Height(), l[1].Height(), l[2].Height()})
+
+	// NOTE the evidences are not removed by CleanByHeight
+	t.NoError(t.Database.CleanByHeight(base.Height(1)))
+	t.Equal(3, len(equivocations(true)))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/spikeekips/mitum/base/node"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
 	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
@@ -105,6 +106,8 @@ func (t *BaseTestDatabase) SetupSuite() {
 
 	_ = t.Encs.TestAddHinter(base.BallotFactSignHinter)
 	_ = t.Encs.TestAddHinter(base.BaseFactSignHinter)
+	_ = t.Encs.TestAddHinter(base.DummyBallotFact{})
+	_ = t.Encs.TestAddHinter(base.EquivocationV0Hinter)
 	_ = t.Encs.TestAddHinter(base.SignedBallotFactHinter)
 	_ = t.Encs.TestAddHinter(base.StringAddressHinter)
 	_ = t.Encs.TestAddHinter(base.VoteproofV0Hinter)
@@ -118,7 +121,11 @@ func (t *BaseTestDatabase) SetupSuite() {
 	_ = t.Encs.TestAddHinter(operation.KVOperationFact{})
 	_ = t.Encs.TestAddHinter(operation.KVOperation{})
 	_ = t.Encs.TestAddHinter(operation.SealHinter)
+	_ = t.Encs.TestAddHinter(operation.FixedTreeNodeHinter)
+	_ = t.Encs.TestAddHinter(operation.StatusV0Hinter)
 	_ = t.Encs.TestAddHinter(seal.DummySeal{})
+	_ = t.Encs.TestAddHinter(state.BytesValueHinter)
+	_ = t.Encs.TestAddHinter(state.StateV0{})
 	_ = t.Encs.TestAddHinter(tree.FixedTreeHinter)
 
 	t.PK = key.NewBasePrivatekey()
@@ -181,3 +188,84 @@ func (t *BaseTestDatabase) NewBlockdataMap(height base.Height, blk valuehash.Has
 
 	return bd
 }
+
+func (t *BaseTestDatabase) NewEquivocation(
+	n base.Address, height base.Height, round base.Round, stage base.Stage,
+) base.EquivocationV0 {
+	signed := func() base.SignedBallotFact {
+		fact := base.NewDummyBallotFact()
+		fact.HT = height
+		fact.R = round
+		fact.S = stage
+
+		sf, err := base.NewBaseSignedBallotFactFromFact(fact, n, t.PK, nil)
+		t.NoError(err)
+
+		return sf
+	}
+
+	ev := base.NewEquivocationV0(signed(), signed(), localtime.UTCNow())
+	t.NoError(ev.IsValid(nil))
+
+	return ev
+}
+
+// CheckStatesOverLastBlock checks StateAt and StateHistory of database do not
+// return the states, which are higher than the last block. saveBlock should
+// store the block of the given height.
+func (t *BaseTestDatabase) CheckStatesOverLastBlock(
+	db interface {
+		Database
+		StateUpdater
+	},
+	saveBlock func(base.Height),
+) {
+	key := util.UUID().String()
+
+	sts := map[base.Height]state.State{}
+	for _, height := range []base.Height{33, 36, 39, 42} {
+		v, err := state.NewBytesValue(util.UUID().Bytes())
+		t.NoError(err)
+
+		i, err := state.NewStateV0(key, v, height)
+		t.NoError(err)
+
+		sta, err := i.SetHash(i.GenerateHash())
+		t.NoError(err)
+		sts[height] = sta
+
+		t.NoError(db.NewState(sta))
+	}
+
+	saveBlock(base.Height(40))
+
+	cases := map[base.Height]base.Height{
+		39: 39,
+		40: 39,
+		42: 39,
+		99: 39,
+	}
+
+	for height, expected := range cases {
+		ust, found, err := db.StateAt(key, height)
+		t.NoError(err)
+		t.True(found, "height=%d", height)
+		t.Equal(expected, ust.Height(), "height=%d", height)
+		t.True(sts[expected].Hash().Equal(ust.Hash()), "height=%d", height)
+	}
+
+	history := func(from, to base.Height) []base.Height {
+		var heights []base.Height
+		t.NoError(db.StateHistory(key, from, to, func(st state.State) (bool, error) {
+			heights = append(heights, st.Height())
+
+			return true, nil
+		}))
+
+		return heights
+	}
+
+	t.Equal([]base.Height{33, 36, 39}, history(base.Height(0), base.Height(100)))
+	t.Equal([]base.Height{39}, history(base.Height(37), base.Height(42)))
+	t.Empty(history(base.Height(41), base.Height(100)))
+}
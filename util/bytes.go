@@ -23,7 +23,7 @@ func CopyBytes(b []byte) []byte {
 	n := make([]byte, len(b))
 	copy(n, b)
 
-	return b
+	return n
 }
 
 func GenerateChecksum(i io.Reader) (string, error) {
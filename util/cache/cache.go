@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"io"
 	"net/url"
 	"time"
 
@@ -18,6 +19,13 @@ type Cache interface {
 	New() (Cache, error)
 }
 
+// Scoper is the Cache, which can be shared with the other processes like
+// Redis. Scope returns the new Cache of the given scope; the caches of the
+// different scopes do not share the keys.
+type Scoper interface {
+	Scope(...string) (Cache, error)
+}
+
 func NewCacheFromURI(uri string) (Cache, error) {
 	u, err := url.Parse(uri)
 	if err != nil {
@@ -28,7 +36,26 @@ func NewCacheFromURI(uri string) (Cache, error) {
 		return NewGCacheWithQuery(u.Query())
 	case u.Scheme == "dummy":
 		return Dummy{}, nil
+	case u.Scheme == "redis":
+		return NewRedisWithURI(u)
+	case u.Scheme == "sized":
+		return NewSizedCacheWithQuery(u.Query())
 	default:
 		return nil, errors.Errorf("not supported uri of cache, %q", uri)
 	}
 }
+
+// CheckURI checks whether the cache can be created from uri. The created cache
+// is closed if it can be closed.
+func CheckURI(uri string) error {
+	ca, err := NewCacheFromURI(uri)
+	if err != nil {
+		return err
+	}
+
+	if i, ok := ca.(io.Closer); ok {
+		_ = i.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	libredis "github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
+)
+
+var (
+	DefaultRedisPrefix  = "mitum-cache"
+	DefaultRedisTimeout = time.Second
+)
+
+const (
+	redisValueBytes  = byte('b')
+	redisValueString = byte('s')
+	redisValueEmpty  = byte('e')
+	redisValueHinter = byte('h')
+)
+
+// EncoderSetter is the Cache, which needs encoder to store the hinted values.
+type EncoderSetter interface {
+	SetEncoder(encoder.Encoder) error
+}
+
+// Redis stores the values in redis, so the processes can share the cache.
+// Except []byte, string and struct{}, only hint.Hinter can be stored and it
+// needs encoder by SetEncoder. The caches from Scope() share the redis client
+// and the keys are separated by the prefix of scope.
+type Redis struct {
+	client  *libredis.Client
+	prefix  string
+	expire  time.Duration
+	timeout time.Duration
+	enc     encoder.Encoder
+}
+
+// NewRedisWithURI creates Redis from uri like
+// "redis://:password@localhost:6379/0?prefix=mitum-cache&expire=1h&timeout=1s".
+func NewRedisWithURI(u *url.URL) (*Redis, error) {
+	prefix := DefaultRedisPrefix
+	expire := DefaultCacheExpire
+	timeout := DefaultRedisTimeout
+
+	query := u.Query()
+	if s := query.Get("prefix"); len(s) > 0 {
+		prefix = s
+	}
+
+	if s := query.Get("expire"); len(s) > 0 {
+		n, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid expire, %q of Redis", s)
+		}
+		expire = n
+	}
+
+	if s := query.Get("timeout"); len(s) > 0 {
+		n, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout, %q of Redis", s)
+		}
+		timeout = n
+	}
+
+	for _, k := range []string{"prefix", "expire", "timeout"} {
+		query.Del(k)
+	}
+
+	nu := *u
+	nu.RawQuery = query.Encode()
+
+	opt, err := libredis.ParseURL(nu.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid uri of Redis")
+	}
+
+	return NewRedis(libredis.NewClient(opt), prefix, expire, timeout), nil
+}
+
+func NewRedis(client *libredis.Client, prefix string, expire, timeout time.Duration) *Redis {
+	return &Redis{
+		client:  client,
+		prefix:  prefix,
+		expire:  expire,
+		timeout: timeout,
+	}
+}
+
+func (ca *Redis) SetEncoder(enc encoder.Encoder) error {
+	ca.enc = enc
+
+	return nil
+}
+
+func (ca *Redis) Has(key interface{}) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), ca.timeout)
+	defer cancel()
+
+	n, err := ca.client.Exists(ctx, ca.key(key)).Result()
+
+	return err == nil && n > 0
+}
+
+func (ca *Redis) Get(key interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ca.timeout)
+	defer cancel()
+
+	b, err := ca.client.Get(ctx, ca.key(key)).Bytes()
+	switch {
+	case errors.Is(err, libredis.Nil):
+		return nil, util.NotFoundError.Errorf("key not found in Redis")
+	case err != nil:
+		return nil, err
+	}
+
+	return ca.unmarshal(b)
+}
+
+func (ca *Redis) Set(key interface{}, v interface{}, expire time.Duration) error {
+	if expire <= 0 {
+		expire = ca.expire
+	}
+
+	b, err := ca.marshal(v)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ca.timeout)
+	defer cancel()
+
+	return ca.client.Set(ctx, ca.key(key), b, expire).Err()
+}
+
+func (ca *Redis) Remove(key interface{}) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), ca.timeout)
+	defer cancel()
+
+	n, err := ca.client.Del(ctx, ca.key(key)).Result()
+
+	return err == nil && n > 0
+}
+
+// Purge removes all the keys under prefix. The keys of the caches from Scope()
+// are also removed.
+func (ca *Redis) Purge() error {
+	ctx := context.Background()
+
+	iter := ca.client.Scan(ctx, 0, ca.prefix+":*", 100).Iterator()
+
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+
+		if len(keys) == 100 {
+			if err := ca.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+
+			keys = nil
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(keys) > 0 {
+		return ca.client.Del(ctx, keys...).Err()
+	}
+
+	return nil
+}
+
+// New is not supported; the keys of Redis are shared with the other processes,
+// so the new cache should be separated by Scope().
+func (*Redis) New() (Cache, error) {
+	return nil, errors.Errorf("Redis can not be created without scope; use Scope()")
+}
+
+// Scope returns new Redis with the sub prefix of scope, like
+// "<prefix>:<scope>:<scope>". The processes with the same scope share the
+// same keys.
+func (ca *Redis) Scope(scope ...string) (Cache, error) {
+	if len(scope) < 1 {
+		return nil, errors.Errorf("empty scope of Redis")
+	}
+
+	prefix := ca.prefix
+	for i := range scope {
+		s := strings.TrimSpace(scope[i])
+		if len(s) < 1 {
+			return nil, errors.Errorf("empty scope of Redis")
+		}
+
+		prefix += ":" + s
+	}
+
+	nca := NewRedis(ca.client, prefix, ca.expire, ca.timeout)
+	nca.enc = ca.enc
+
+	return nca, nil
+}
+
+func (ca *Redis) Close() error {
+	return ca.client.Close()
+}
+
+func (ca *Redis) key(key interface{}) string {
+	var k string
+	switch t := key.(type) {
+	case string:
+		k = t
+	case []byte:
+		k = string(t)
+	case fmt.Stringer:
+		k = t.String()
+	default:
+		k = fmt.Sprintf("%v", t)
+	}
+
+	return ca.prefix + ":" + k
+}
+
+func (ca *Redis) marshal(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case []byte:
+		return append([]byte{redisValueBytes}, t...), nil
+	case string:
+		return append([]byte{redisValueString}, []byte(t)...), nil
+	case struct{}:
+		return []byte{redisValueEmpty}, nil
+	case hint.Hinter:
+		if ca.enc == nil {
+			return nil, errors.Errorf("empty encoder for hinted value of Redis, %T", v)
+		}
+
+		b, err := ca.enc.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]byte{redisValueHinter}, b...), nil
+	default:
+		return nil, errors.Errorf("not supported value of Redis, %T", v)
+	}
+}
+
+func (ca *Redis) unmarshal(b []byte) (interface{}, error) {
+	if len(b) < 1 {
+		return nil, errors.Errorf("empty value of Redis")
+	}
+
+	switch b[0] {
+	case redisValueBytes:
+		return b[1:], nil
+	case redisValueString:
+		return string(b[1:]), nil
+	case redisValueEmpty:
+		return struct{}{}, nil
+	case redisValueHinter:
+		if ca.enc == nil {
+			return nil, errors.Errorf("empty encoder for hinted value of Redis")
+		}
+
+		return ca.enc.Decode(b[1:])
+	default:
+		return nil, errors.Errorf("unknown value type of Redis, %q", b[0])
+	}
+}
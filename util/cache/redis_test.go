@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/stretchr/testify/suite"
+)
+
+type redisHinter struct {
+	hint.BaseHinter
+	A string
+}
+
+func (ht redisHinter) MarshalJSON() ([]byte, error) {
+	return util.JSON.Marshal(struct {
+		jsonenc.HintedHead
+		A string
+	}{
+		HintedHead: jsonenc.NewHintedHead(ht.Hint()),
+		A:          ht.A,
+	})
+}
+
+type testRedis struct {
+	suite.Suite
+}
+
+func (t *testRedis) TestNew() {
+	i, err := NewCacheFromURI("redis://localhost:6379/3?prefix=showme&expire=3s&timeout=33ms")
+	t.NoError(err)
+
+	ca, ok := i.(*Redis)
+	t.True(ok)
+	defer ca.Close()
+
+	t.Equal("showme", ca.prefix)
+	t.Equal(time.Second*3, ca.expire)
+	t.Equal(time.Millisecond*33, ca.timeout)
+	t.Equal(3, ca.client.Options().DB)
+}
+
+func (t *testRedis) TestNewDefault() {
+	u, _ := url.Parse("redis://localhost:6379")
+	ca, err := NewRedisWithURI(u)
+	t.NoError(err)
+	defer ca.Close()
+
+	t.Equal(DefaultRedisPrefix, ca.prefix)
+	t.Equal(DefaultCacheExpire, ca.expire)
+	t.Equal(DefaultRedisTimeout, ca.timeout)
+}
+
+func (t *testRedis) TestInvalidURI() {
+	_, err := NewCacheFromURI("redis://localhost:6379?expire=showme")
+	t.Error(err)
+	t.Contains(err.Error(), "invalid expire")
+
+	_, err = NewCacheFromURI("redis://localhost:6379?findme=1")
+	t.Error(err)
+	t.Contains(err.Error(), "invalid uri of Redis")
+}
+
+func (t *testRedis) TestScope() {
+	u, _ := url.Parse("redis://localhost:6379?prefix=showme")
+	ca, err := NewRedisWithURI(u)
+	t.NoError(err)
+	defer ca.Close()
+
+	_, err = ca.New()
+	t.Error(err)
+	t.Contains(err.Error(), "use Scope()")
+
+	a, err := ca.Scope("db0", "seal")
+	t.NoError(err)
+	b, err := ca.Scope("db1", "seal")
+	t.NoError(err)
+
+	t.Equal("showme:db0:seal", a.(*Redis).prefix)
+	t.Equal("showme:db1:seal", b.(*Redis).prefix)
+	t.Equal("showme:db0:seal:findme", a.(*Redis).key("findme"))
+
+	_, err = ca.Scope()
+	t.Error(err)
+
+	_, err = ca.Scope("db0", " ")
+	t.Error(err)
+}
+
+func (t *testRedis) TestValues() {
+	u, _ := url.Parse("redis://localhost:6379")
+	ca, err := NewRedisWithURI(u)
+	t.NoError(err)
+	defer ca.Close()
+
+	ht := redisHinter{BaseHinter: hint.NewBaseHinter(hint.NewHint(hint.Type("findme"), "v0.0.1")), A: "showme"}
+
+	_, err = ca.marshal(ht)
+	t.Error(err)
+	t.Contains(err.Error(), "empty encoder")
+
+	_, err = ca.marshal(3)
+	t.Error(err)
+	t.Contains(err.Error(), "not supported value")
+
+	enc := jsonenc.NewEncoder()
+	t.NoError(enc.Add(ht))
+	t.NoError(ca.SetEncoder(enc))
+
+	for _, v := range []interface{}{[]byte("showme"), "showme", struct{}{}, ht} {
+		b, err := ca.marshal(v)
+		t.NoError(err)
+
+		i, err := ca.unmarshal(b)
+		t.NoError(err)
+		t.Equal(v, i)
+	}
+
+	// NOTE Scope() keeps encoder
+	nca, err := ca.Scope("showme")
+	t.NoError(err)
+	t.Equal(enc, nca.(*Redis).enc)
+}
+
+func TestRedis(t *testing.T) {
+	suite.Run(t, new(testRedis))
+}
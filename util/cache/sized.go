@@ -0,0 +1,284 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/util"
+)
+
+var (
+	DefaultSizedCacheSize int64 = 64 << 20 // NOTE 64MiB
+	// DefaultSizedCacheEntrySize is the size of value, which size can not be
+	// known.
+	DefaultSizedCacheEntrySize int64 = 1 << 10
+)
+
+// SizedCacheStats is the statistics of SizedCache.
+type SizedCacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+	Size      int64  `json:"size"`
+	MaxSize   int64  `json:"max_size"`
+}
+
+type sizedCacheItem struct {
+	key      string
+	value    interface{}
+	size     int64
+	expireAt time.Time
+}
+
+// SizedCache is the LRU cache bounded by the total bytes of keys and values.
+// The size of value is len() of []byte and string, len(Bytes()) of
+// util.Byter or DefaultSizedCacheEntrySize.
+//
+// NOTE the size is approximate; it does not count the memory overhead of
+// items and the real memory of the other values can be bigger or smaller than
+// DefaultSizedCacheEntrySize. The size is the budget of each SizedCache; the
+// caches created by New() have their own budget, so the total memory of them
+// can be over the size.
+type SizedCache struct {
+	sync.Mutex
+	maxSize   int64
+	expire    time.Duration
+	size      int64
+	ll        *list.List
+	items     map[string]*list.Element
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func NewSizedCacheWithQuery(config url.Values) (*SizedCache, error) {
+	size := DefaultSizedCacheSize
+	expire := DefaultCacheExpire
+
+	if s := config.Get("size"); len(s) > 0 {
+		n, err := parseSizedCacheSize(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid size, %q of SizedCache", s)
+		}
+		size = n
+	}
+
+	if s := config.Get("expire"); len(s) > 0 {
+		n, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid expire, %q of SizedCache", s)
+		}
+		expire = n
+	}
+
+	return NewSizedCache(size, expire)
+}
+
+func NewSizedCache(size int64, expire time.Duration) (*SizedCache, error) {
+	if size < 1 {
+		return nil, errors.Errorf("invalid size of SizedCache, %d", size)
+	}
+
+	return &SizedCache{
+		maxSize: size,
+		expire:  expire,
+		ll:      list.New(),
+		items:   map[string]*list.Element{},
+	}, nil
+}
+
+func (ca *SizedCache) Has(key interface{}) bool {
+	ca.Lock()
+	defer ca.Unlock()
+
+	_, found := ca.get(sizedCacheKey(key))
+
+	return found
+}
+
+func (ca *SizedCache) Get(key interface{}) (interface{}, error) {
+	ca.Lock()
+	defer ca.Unlock()
+
+	e, found := ca.get(sizedCacheKey(key))
+	if !found {
+		ca.misses++
+
+		return nil, util.NotFoundError.Errorf("key not found in SizedCache")
+	}
+
+	ca.hits++
+	ca.ll.MoveToFront(e)
+
+	return e.Value.(*sizedCacheItem).value, nil
+}
+
+func (ca *SizedCache) Set(key interface{}, v interface{}, expire time.Duration) error {
+	if expire <= 0 {
+		expire = ca.expire
+	}
+
+	k := sizedCacheKey(key)
+	size := int64(len(k)) + sizedCacheValueSize(v)
+	if size > ca.maxSize {
+		return errors.Errorf("too big value for SizedCache; %d > %d", size, ca.maxSize)
+	}
+
+	ca.Lock()
+	defer ca.Unlock()
+
+	if e, found := ca.items[k]; found {
+		ca.removeElement(e)
+	}
+
+	for ca.size+size > ca.maxSize {
+		e := ca.ll.Back()
+		if e == nil {
+			break
+		}
+
+		ca.removeElement(e)
+		ca.evictions++
+	}
+
+	ca.items[k] = ca.ll.PushFront(&sizedCacheItem{
+		key:      k,
+		value:    v,
+		size:     size,
+		expireAt: time.Now().Add(expire),
+	})
+	ca.size += size
+
+	return nil
+}
+
+func (ca *SizedCache) Remove(key interface{}) bool {
+	ca.Lock()
+	defer ca.Unlock()
+
+	e, found := ca.items[sizedCacheKey(key)]
+	if !found {
+		return false
+	}
+
+	ca.removeElement(e)
+
+	return true
+}
+
+func (ca *SizedCache) Purge() error {
+	ca.Lock()
+	defer ca.Unlock()
+
+	ca.ll.Init()
+	ca.items = map[string]*list.Element{}
+	ca.size = 0
+
+	return nil
+}
+
+// New returns new SizedCache with same size and expire. The size is not
+// shared with the new one; each cache has the whole size as it's own budget.
+func (ca *SizedCache) New() (Cache, error) {
+	return NewSizedCache(ca.maxSize, ca.expire)
+}
+
+func (ca *SizedCache) Stats() SizedCacheStats {
+	ca.Lock()
+	defer ca.Unlock()
+
+	return SizedCacheStats{
+		Hits:      ca.hits,
+		Misses:    ca.misses,
+		Evictions: ca.evictions,
+		Entries:   ca.ll.Len(),
+		Size:      ca.size,
+		MaxSize:   ca.maxSize,
+	}
+}
+
+func (ca *SizedCache) get(k string) (*list.Element, bool) {
+	e, found := ca.items[k]
+	if !found {
+		return nil, false
+	}
+
+	if time.Now().After(e.Value.(*sizedCacheItem).expireAt) {
+		ca.removeElement(e)
+
+		return nil, false
+	}
+
+	return e, true
+}
+
+func (ca *SizedCache) removeElement(e *list.Element) {
+	item := ca.ll.Remove(e).(*sizedCacheItem)
+	delete(ca.items, item.key)
+	ca.size -= item.size
+}
+
+func sizedCacheKey(key interface{}) string {
+	switch t := key.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// sizedCacheValueSize returns the approximate size of value; the size of the
+// value, which is not bytes, string or util.Byter, is
+// DefaultSizedCacheEntrySize.
+func sizedCacheValueSize(v interface{}) int64 {
+	switch t := v.(type) {
+	case nil, struct{}:
+		return 0
+	case []byte:
+		return int64(len(t))
+	case string:
+		return int64(len(t))
+	case util.Byter:
+		return int64(len(t.Bytes()))
+	default:
+		return DefaultSizedCacheEntrySize
+	}
+}
+
+// parseSizedCacheSize parses the size with the optional unit, "kb", "mb" and
+// "gb".
+func parseSizedCacheSize(s string) (int64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	unit := int64(1)
+	for suffix, u := range map[string]int64{"kb": 1 << 10, "mb": 1 << 20, "gb": 1 << 30} {
+		if strings.HasSuffix(s, suffix) {
+			unit = u
+			s = strings.TrimSuffix(s, suffix)
+
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 1 {
+		return 0, errors.Errorf("size should be over zero, %d", n)
+	}
+
+	return n * unit, nil
+}
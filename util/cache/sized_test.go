@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSizedCache struct {
+	suite.Suite
+}
+
+func (t *testSizedCache) TestNew() {
+	ca, err := NewSizedCacheWithQuery(nil)
+	t.NoError(err)
+
+	_, ok := (interface{})(ca).(Cache)
+	t.True(ok)
+
+	t.Equal(DefaultSizedCacheSize, ca.maxSize)
+	t.Equal(DefaultCacheExpire, ca.expire)
+}
+
+func (t *testSizedCache) TestFromURI() {
+	i, err := NewCacheFromURI("sized:?size=3mb&expire=3s")
+	t.NoError(err)
+
+	ca, ok := i.(*SizedCache)
+	t.True(ok)
+
+	t.Equal(int64(3<<20), ca.maxSize)
+	t.Equal(time.Second*3, ca.expire)
+}
+
+func (t *testSizedCache) TestWithSize() {
+	for _, s := range []string{"a3333", "0", "-1", "3tb"} {
+		query := url.Values{}
+		query.Set("size", s)
+		_, err := NewSizedCacheWithQuery(query)
+		t.Error(err, s)
+		t.Contains(err.Error(), "invalid size", s)
+	}
+
+	for s, expected := range map[string]int64{"3333": 3333, "33KB": 33 << 10, "33mb": 33 << 20, "3gb": 3 << 30} {
+		query := url.Values{}
+		query.Set("size", s)
+		ca, err := NewSizedCacheWithQuery(query)
+		t.NoError(err)
+
+		t.Equal(expected, ca.maxSize, s)
+	}
+}
+
+func (t *testSizedCache) TestSetGet() {
+	ca, err := NewSizedCache(100, time.Minute)
+	t.NoError(err)
+
+	t.NoError(ca.Set("a", []byte("showme"), 0))
+	t.True(ca.Has("a"))
+
+	i, err := ca.Get("a")
+	t.NoError(err)
+	t.Equal([]byte("showme"), i)
+
+	_, err = ca.Get("b")
+	t.True(errors.Is(err, util.NotFoundError))
+
+	t.True(ca.Remove("a"))
+	t.False(ca.Has("a"))
+	t.False(ca.Remove("a"))
+
+	stats := ca.Stats()
+	t.Equal(uint64(1), stats.Hits)
+	t.Equal(uint64(1), stats.Misses)
+	t.Equal(0, stats.Entries)
+	t.Equal(int64(0), stats.Size)
+}
+
+func (t *testSizedCache) TestEvict() {
+	ca, err := NewSizedCache(30, time.Minute)
+	t.NoError(err)
+
+	// NOTE each item is 10 bytes
+	t.NoError(ca.Set("a", "012345678", 0))
+	t.NoError(ca.Set("b", "012345678", 0))
+	t.NoError(ca.Set("c", "012345678", 0))
+
+	// NOTE "a" is recently used
+	_, err = ca.Get("a")
+	t.NoError(err)
+
+	t.NoError(ca.Set("d", "012345678", 0))
+
+	t.True(ca.Has("a"))
+	t.False(ca.Has("b"))
+	t.True(ca.Has("c"))
+	t.True(ca.Has("d"))
+
+	stats := ca.Stats()
+	t.Equal(uint64(1), stats.Evictions)
+	t.Equal(3, stats.Entries)
+	t.Equal(int64(30), stats.Size)
+
+	// NOTE replace with bigger value
+	t.NoError(ca.Set("a", "0123456789012345678", 0))
+	t.True(ca.Has("a"))
+	t.True(ca.Has("d"))
+	t.False(ca.Has("c"))
+
+	stats = ca.Stats()
+	t.Equal(uint64(2), stats.Evictions)
+	t.Equal(int64(30), stats.Size)
+
+	err = ca.Set("e", "0123456789012345678901234567890", 0)
+	t.Error(err)
+	t.Contains(err.Error(), "too big")
+}
+
+func (t *testSizedCache) TestExpire() {
+	ca, err := NewSizedCache(100, time.Minute)
+	t.NoError(err)
+
+	t.NoError(ca.Set("a", "showme", time.Millisecond*10))
+	t.NoError(ca.Set("b", "showme", 0))
+
+	<-time.After(time.Millisecond * 20)
+
+	t.False(ca.Has("a"))
+	t.True(ca.Has("b"))
+
+	stats := ca.Stats()
+	t.Equal(1, stats.Entries)
+	t.Equal(int64(7), stats.Size)
+}
+
+func (t *testSizedCache) TestPurge() {
+	ca, err := NewSizedCache(100, time.Minute)
+	t.NoError(err)
+
+	t.NoError(ca.Set("a", "showme", 0))
+	t.NoError(ca.Purge())
+	t.False(ca.Has("a"))
+
+	stats := ca.Stats()
+	t.Equal(0, stats.Entries)
+	t.Equal(int64(0), stats.Size)
+}
+
+func TestSizedCache(t *testing.T) {
+	suite.Run(t, new(testSizedCache))
+}
@@ -17,7 +17,6 @@ type CallbackTimer struct {
 	callback     func(int) (bool, error)
 	intervalFunc func(int) time.Duration
 	errchan      chan error
-	ticker       *time.Ticker
 	stopped      bool
 	stopChan     chan struct{}
 	resetChan    chan struct{}
@@ -40,7 +39,6 @@ func NewCallbackTimer(
 		errchan:   make(chan error, 100),
 		stopped:   true,
 		stopChan:  make(chan struct{}, 1),
-		ticker:    time.NewTicker(defaultTimerDuration),
 		resetChan: make(chan struct{}),
 	}, nil
 }
@@ -78,7 +76,6 @@ func (ct *CallbackTimer) start() error {
 
 	ct.stopped = false
 
-	ct.ticker.Reset(defaultTimerDuration)
 	ct.stopChan = make(chan struct{}, 1)
 
 	go ct.clock()
@@ -142,16 +139,16 @@ func (ct *CallbackTimer) IsStarted() bool {
 	return !ct.stopped
 }
 
+// clock waits the interval by the global Clock, so the timer follows the
+// VirtualClock of simulation.
 func (ct *CallbackTimer) clock() {
-	lastInterval, err := ct.resetTicker(0, 0)
+	tick, err := ct.nextTick(0)
 	if err != nil {
 		_ = ct.Stop()
 
 		return
 	}
 
-	defer ct.ticker.Stop()
-
 	var i int
 
 end:
@@ -161,11 +158,11 @@ end:
 			return
 		case <-ct.resetChan:
 			i = 0
-			j, err := ct.resetTicker(0, lastInterval)
+			j, err := ct.nextTick(0)
 			if err != nil {
 				break end
 			}
-			lastInterval = j
+			tick = j
 		case err := <-ct.errchan:
 			if err == nil {
 				continue
@@ -178,7 +175,7 @@ end:
 			}
 
 			break end
-		case <-ct.ticker.C:
+		case <-tick:
 			go func(i int) {
 				if keep, err := ct.callback(i); err != nil {
 					ct.errchan <- err
@@ -189,25 +186,22 @@ end:
 
 			i++
 
-			d, err := ct.resetTicker(i, lastInterval)
+			j, err := ct.nextTick(i)
 			if err != nil {
 				break end
 			}
-			lastInterval = d
+			tick = j
 		}
 	}
 
 	_ = ct.Stop()
 }
 
-func (ct *CallbackTimer) resetTicker(i int, last time.Duration) (time.Duration, error) {
+func (ct *CallbackTimer) nextTick(i int) (<-chan time.Time, error) {
 	in := ct.intervalFunc(i)
 	if in < time.Nanosecond {
-		return 0, errors.Errorf("too narrow interval: %v", in)
-	}
-	if in != last {
-		ct.ticker.Reset(in)
+		return nil, errors.Errorf("too narrow interval: %v", in)
 	}
 
-	return in, nil
+	return After(in), nil
 }
@@ -273,6 +273,55 @@ func (t *testCallbackTimer) TestReset() {
 	ct.Stop()
 }
 
+func (t *testCallbackTimer) TestVirtualClock() {
+	vc := NewVirtualClock(time.Now())
+	SetClock(vc)
+	defer SetClock(nil)
+
+	ticked := make(chan int, 10)
+	ct, err := NewCallbackTimer(
+		TimerID("virtual timer"),
+		func(i int) (bool, error) {
+			ticked <- i
+
+			return true, nil
+		},
+		time.Second,
+	)
+	t.NoError(err)
+
+	t.NoError(ct.Start())
+	defer ct.Stop()
+
+	waitWaiter := func() {
+		for vc.Waiters() < 1 {
+			<-time.After(time.Millisecond)
+		}
+	}
+
+	waitWaiter()
+
+	// NOTE not ticked by real time
+	vc.Advance(time.Millisecond * 999)
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case <-ticked:
+		t.Fail("ticked before interval")
+	}
+
+	for i := 0; i < 3; i++ {
+		waitWaiter()
+		vc.Advance(time.Second)
+
+		select {
+		case <-time.After(time.Second):
+			t.Fail("not ticked")
+		case j := <-ticked:
+			t.Equal(i, j)
+		}
+	}
+}
+
 func TestCallbackTimer(t *testing.T) {
 	defer goleak.VerifyNone(t)
 
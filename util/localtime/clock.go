@@ -0,0 +1,177 @@
+package localtime
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time. By default the system clock is used; the
+// simulation replaces it with VirtualClock by SetClock.
+type Clock interface {
+	Now() time.Time
+	After(time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+var (
+	clockLock sync.RWMutex
+	clock     Clock = systemClock{}
+)
+
+// SetClock sets the global Clock; nil restores the system clock.
+func SetClock(c Clock) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+
+	if c == nil {
+		clock = systemClock{}
+
+		return
+	}
+
+	clock = c
+}
+
+func currentClock() Clock {
+	clockLock.RLock()
+	defer clockLock.RUnlock()
+
+	return clock
+}
+
+// After waits for the duration to elapse by the global Clock.
+func After(d time.Duration) <-chan time.Time {
+	return currentClock().After(d)
+}
+
+type virtualWaiter struct {
+	deadline time.Time
+	seq      uint64
+	f        func(time.Time)
+}
+
+// VirtualClock is the Clock, which moves only by Advance. The waiters are
+// fired in the order of deadline and, for the same deadline, in the order of
+// registration, so the same sequence of calls always gives the same result.
+type VirtualClock struct {
+	sync.Mutex
+	now     time.Time
+	seq     uint64
+	waiters []virtualWaiter
+}
+
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{now: start}
+}
+
+func (vc *VirtualClock) Now() time.Time {
+	vc.Lock()
+	defer vc.Unlock()
+
+	return vc.now
+}
+
+func (vc *VirtualClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+
+	vc.AfterFunc(d, func(t time.Time) {
+		ch <- t
+	})
+
+	return ch
+}
+
+// AfterFunc calls f when the clock reaches the deadline. f is called by the
+// goroutine of Advance, so f should not block. If d is not positive, f is
+// called immediately.
+func (vc *VirtualClock) AfterFunc(d time.Duration, f func(time.Time)) {
+	vc.Lock()
+
+	if d <= 0 {
+		now := vc.now
+		vc.Unlock()
+
+		f(now)
+
+		return
+	}
+
+	defer vc.Unlock()
+
+	vc.seq++
+	w := virtualWaiter{deadline: vc.now.Add(d), seq: vc.seq, f: f}
+
+	i := sort.Search(len(vc.waiters), func(i int) bool {
+		return w.deadline.Before(vc.waiters[i].deadline)
+	})
+
+	vc.waiters = append(vc.waiters, virtualWaiter{})
+	copy(vc.waiters[i+1:], vc.waiters[i:])
+	vc.waiters[i] = w
+}
+
+// Advance moves the clock forward by d and fires the waiters, whose deadline
+// is reached. It returns the number of fired waiters.
+func (vc *VirtualClock) Advance(d time.Duration) int {
+	vc.Lock()
+	target := vc.now.Add(d)
+	vc.Unlock()
+
+	var fired int
+
+	for {
+		vc.Lock()
+		if len(vc.waiters) < 1 || vc.waiters[0].deadline.After(target) {
+			vc.now = target
+			vc.Unlock()
+
+			return fired
+		}
+
+		w := vc.waiters[0]
+		vc.waiters = vc.waiters[1:]
+		vc.now = w.deadline
+		vc.Unlock()
+
+		w.f(w.deadline)
+		fired++
+	}
+}
+
+// Next returns the earliest deadline of waiters.
+func (vc *VirtualClock) Next() (time.Time, bool) {
+	vc.Lock()
+	defer vc.Unlock()
+
+	if len(vc.waiters) < 1 {
+		return time.Time{}, false
+	}
+
+	return vc.waiters[0].deadline, true
+}
+
+// Registered returns the number of waiters registered so far.
+func (vc *VirtualClock) Registered() uint64 {
+	vc.Lock()
+	defer vc.Unlock()
+
+	return vc.seq
+}
+
+// Waiters returns the number of waiters, which are not fired yet.
+func (vc *VirtualClock) Waiters() int {
+	vc.Lock()
+	defer vc.Unlock()
+
+	return len(vc.waiters)
+}
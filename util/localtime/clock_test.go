@@ -0,0 +1,118 @@
+package localtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testVirtualClock struct {
+	suite.Suite
+}
+
+func (t *testVirtualClock) TestAdvance() {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	vc := NewVirtualClock(start)
+
+	var fired []int
+	vc.AfterFunc(time.Second*2, func(time.Time) { fired = append(fired, 2) })
+	vc.AfterFunc(time.Second, func(time.Time) { fired = append(fired, 0) })
+	vc.AfterFunc(time.Second, func(time.Time) { fired = append(fired, 1) })
+
+	next, found := vc.Next()
+	t.True(found)
+	t.Equal(start.Add(time.Second), next)
+
+	t.Equal(2, vc.Advance(time.Second))
+	t.Equal([]int{0, 1}, fired)
+	t.Equal(start.Add(time.Second), vc.Now())
+	t.Equal(1, vc.Waiters())
+
+	t.Equal(1, vc.Advance(time.Second*3))
+	t.Equal([]int{0, 1, 2}, fired)
+	t.Equal(start.Add(time.Second*4), vc.Now())
+	t.Equal(0, vc.Waiters())
+}
+
+func (t *testVirtualClock) TestAfter() {
+	vc := NewVirtualClock(time.Now())
+
+	ch := vc.After(time.Second)
+
+	vc.Advance(time.Millisecond * 999)
+	select {
+	case <-ch:
+		t.NoError(context.DeadlineExceeded)
+	default:
+	}
+
+	vc.Advance(time.Millisecond)
+	select {
+	case <-ch:
+	default:
+		t.NoError(context.DeadlineExceeded)
+	}
+
+	// NOTE not positive duration fires immediately
+	select {
+	case <-vc.After(0):
+	default:
+		t.NoError(context.DeadlineExceeded)
+	}
+}
+
+func (t *testVirtualClock) TestSetClock() {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	vc := NewVirtualClock(start)
+
+	SetClock(vc)
+	defer SetClock(nil)
+
+	t.Equal(start, UTCNow())
+
+	vc.Advance(time.Minute)
+	t.Equal(start.Add(time.Minute), UTCNow())
+
+	SetClock(nil)
+	t.True(WithinNow(time.Now(), time.Second))
+}
+
+func (t *testVirtualClock) TestContextTimer() {
+	vc := NewVirtualClock(time.Now())
+
+	SetClock(vc)
+	defer SetClock(nil)
+
+	called := make(chan int, 10)
+	timer := NewContextTimer(TimerID("virtual"), time.Second*10, func(i int) (bool, error) {
+		called <- i
+
+		return true, nil
+	})
+	t.NoError(timer.Start())
+	defer func() {
+		_ = timer.Stop()
+	}()
+
+	for i := 0; i < 2; i++ {
+		// NOTE wait until timer waits the virtual clock
+		for vc.Waiters() < 1 {
+			<-time.After(time.Millisecond)
+		}
+
+		vc.Advance(time.Second * 10)
+
+		select {
+		case <-time.After(time.Second):
+			t.NoError(context.DeadlineExceeded)
+		case c := <-called:
+			t.Equal(i, c)
+		}
+	}
+}
+
+func TestVirtualClock(t *testing.T) {
+	suite.Run(t, new(testVirtualClock))
+}
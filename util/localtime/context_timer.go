@@ -182,7 +182,7 @@ func (ct *ContextTimer) waitAndRun(
 	select {
 	case <-ctx.Done():
 		return nil
-	case <-time.After(interval):
+	case <-After(interval):
 	}
 
 	if keep, err := callback(count); err != nil {
@@ -165,13 +165,14 @@ func SetTimeSyncer(syncer *TimeSyncer) {
 	timeSyncer = syncer
 }
 
-// Now returns the tuned Time with TimeSyncer.Offset().
+// Now returns the tuned Time of Clock with TimeSyncer.Offset().
 func Now() time.Time {
+	now := currentClock().Now()
 	if timeSyncer == nil {
-		return time.Now()
+		return now
 	}
 
-	return time.Now().Add(timeSyncer.Offset())
+	return now.Add(timeSyncer.Offset())
 }
 
 func UTCNow() time.Time {
@@ -6,10 +6,7 @@ import (
 	"github.com/spikeekips/mitum/util"
 )
 
-var (
-	StopTimerError       = util.NewError("stop timer")
-	defaultTimerDuration = time.Hour * 24 * 360
-)
+var StopTimerError = util.NewError("stop timer")
 
 type TimerID string
 
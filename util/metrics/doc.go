@@ -0,0 +1,5 @@
+/*
+Package metrics provides the simple metrics registry, which can be exported
+by the prometheus text exposition format.
+*/
+package metrics
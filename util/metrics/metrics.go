@@ -0,0 +1,342 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+type metricType string
+
+const (
+	counterType   metricType = "counter"
+	gaugeType     metricType = "gauge"
+	histogramType metricType = "histogram"
+)
+
+// Collector writes it's metrics in the prometheus text format.
+type Collector interface {
+	Name() string
+	Write(io.Writer) error
+}
+
+type metric interface {
+	write(w io.Writer, name, labels string) error
+}
+
+type vec struct {
+	sync.RWMutex
+	name   string
+	help   string
+	t      metricType
+	labels []string
+	newf   func() metric
+	m      map[string]metric
+	lvs    map[string][]string
+}
+
+func newVec(name, help string, t metricType, labels []string, newf func() metric) *vec {
+	return &vec{
+		name:   name,
+		help:   help,
+		t:      t,
+		labels: labels,
+		newf:   newf,
+		m:      map[string]metric{},
+		lvs:    map[string][]string{},
+	}
+}
+
+func (v *vec) Name() string {
+	return v.name
+}
+
+func (v *vec) get(values []string) metric {
+	if len(values) != len(v.labels) {
+		panic(errors.Errorf("wrong label values for %q; %d != %d", v.name, len(values), len(v.labels)))
+	}
+
+	key := strings.Join(values, "\xff")
+
+	v.RLock()
+	i, found := v.m[key]
+	v.RUnlock()
+
+	if found {
+		return i
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if i, found := v.m[key]; found {
+		return i
+	}
+
+	i = v.newf()
+	v.m[key] = i
+	v.lvs[key] = append([]string(nil), values...)
+
+	return i
+}
+
+func (v *vec) Write(w io.Writer) error {
+	v.RLock()
+	defer v.RUnlock()
+
+	if _, err := io.WriteString(w,
+		"# HELP "+v.name+" "+escapeHelp(v.help)+"\n# TYPE "+v.name+" "+string(v.t)+"\n",
+	); err != nil {
+		return err
+	}
+
+	keys := make([]string, len(v.m))
+	var i int
+	for k := range v.m {
+		keys[i] = k
+		i++
+	}
+	sort.Strings(keys)
+
+	for i := range keys {
+		if err := v.m[keys[i]].write(w, v.name, formatLabels(v.labels, v.lvs[keys[i]])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Counter only increases.
+type Counter struct {
+	v uint64
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds value; negative value is ignored.
+func (c *Counter) Add(f float64) {
+	if f < 0 {
+		return
+	}
+
+	addFloat(&c.v, f)
+}
+
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&c.v))
+}
+
+func (c *Counter) write(w io.Writer, name, labels string) error {
+	_, err := io.WriteString(w, name+labels+" "+formatFloat(c.Value())+"\n")
+
+	return err
+}
+
+type CounterVec struct {
+	*vec
+}
+
+func NewCounterVec(name, help string, labels ...string) CounterVec {
+	return CounterVec{vec: newVec(name, help, counterType, labels, func() metric { return &Counter{} })}
+}
+
+func (v CounterVec) WithLabelValues(values ...string) *Counter {
+	return v.get(values).(*Counter)
+}
+
+// Gauge can be set to any value.
+type Gauge struct {
+	v uint64
+}
+
+func (g *Gauge) Set(f float64) {
+	atomic.StoreUint64(&g.v, math.Float64bits(f))
+}
+
+func (g *Gauge) Add(f float64) {
+	addFloat(&g.v, f)
+}
+
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.v))
+}
+
+func (g *Gauge) write(w io.Writer, name, labels string) error {
+	_, err := io.WriteString(w, name+labels+" "+formatFloat(g.Value())+"\n")
+
+	return err
+}
+
+type GaugeVec struct {
+	*vec
+}
+
+func NewGaugeVec(name, help string, labels ...string) GaugeVec {
+	return GaugeVec{vec: newVec(name, help, gaugeType, labels, func() metric { return &Gauge{} })}
+}
+
+func (v GaugeVec) WithLabelValues(values ...string) *Gauge {
+	return v.get(values).(*Gauge)
+}
+
+// Histogram counts the observed values by buckets.
+type Histogram struct {
+	sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *Histogram) Observe(f float64) {
+	h.Lock()
+	defer h.Unlock()
+
+	for i := range h.buckets {
+		if f <= h.buckets[i] {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += f
+}
+
+// Since observes the elapsed seconds from t.
+func (h *Histogram) Since(t time.Time) {
+	h.Observe(time.Since(t).Seconds())
+}
+
+func (h *Histogram) Count() uint64 {
+	h.Lock()
+	defer h.Unlock()
+
+	return h.count
+}
+
+func (h *Histogram) write(w io.Writer, name, labels string) error {
+	h.Lock()
+	defer h.Unlock()
+
+	var sb strings.Builder
+	for i := range h.buckets {
+		sb.WriteString(name + "_bucket" + appendLabel(labels, "le", formatFloat(h.buckets[i])) +
+			" " + strconv.FormatUint(h.counts[i], 10) + "\n")
+	}
+
+	sb.WriteString(name + "_bucket" + appendLabel(labels, "le", "+Inf") +
+		" " + strconv.FormatUint(h.count, 10) + "\n")
+	sb.WriteString(name + "_sum" + labels + " " + formatFloat(h.sum) + "\n")
+	sb.WriteString(name + "_count" + labels + " " + strconv.FormatUint(h.count, 10) + "\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+type HistogramVec struct {
+	*vec
+}
+
+// NewHistogramVec creates new HistogramVec. If buckets is empty,
+// DefaultDurationBuckets is used.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) HistogramVec {
+	if len(buckets) < 1 {
+		buckets = DefaultDurationBuckets
+	}
+
+	bs := make([]float64, len(buckets))
+	copy(bs, buckets)
+	sort.Float64s(bs)
+
+	return HistogramVec{vec: newVec(name, help, histogramType, labels, func() metric { return newHistogram(bs) })}
+}
+
+func (v HistogramVec) WithLabelValues(values ...string) *Histogram {
+	return v.get(values).(*Histogram)
+}
+
+func addFloat(p *uint64, f float64) {
+	for {
+		o := atomic.LoadUint64(p)
+		n := math.Float64bits(math.Float64frombits(o) + f)
+		if atomic.CompareAndSwapUint64(p, o, n) {
+			return
+		}
+	}
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+func formatLabels(labels, values []string) string {
+	if len(labels) < 1 {
+		return ""
+	}
+
+	ls := make([]string, len(labels))
+	for i := range labels {
+		ls[i] = labels[i] + `="` + escapeLabelValue(values[i]) + `"`
+	}
+
+	return "{" + strings.Join(ls, ",") + "}"
+}
+
+func appendLabel(labels, name, value string) string {
+	l := name + `="` + value + `"`
+	if len(labels) < 1 {
+		return "{" + l + "}"
+	}
+
+	return labels[:len(labels)-1] + "," + l + "}"
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testMetrics struct {
+	suite.Suite
+}
+
+func (t *testMetrics) TestCounter() {
+	c := NewCounterVec("a_total", "a help", "k")
+	c.WithLabelValues("x").Inc()
+	c.WithLabelValues("x").Add(2)
+	c.WithLabelValues("y").Inc()
+	c.WithLabelValues("y").Add(-1) // NOTE ignored
+
+	t.Equal(float64(3), c.WithLabelValues("x").Value())
+	t.Equal(float64(1), c.WithLabelValues("y").Value())
+
+	var buf bytes.Buffer
+	t.NoError(c.Write(&buf))
+	t.Equal(`# HELP a_total a help
+# TYPE a_total counter
+a_total{k="x"} 3
+a_total{k="y"} 1
+`, buf.String())
+}
+
+func (t *testMetrics) TestGauge() {
+	g := NewGaugeVec("b", "b help")
+	g.WithLabelValues().Set(10)
+	g.WithLabelValues().Dec()
+	g.WithLabelValues().Add(0.5)
+
+	var buf bytes.Buffer
+	t.NoError(g.Write(&buf))
+	t.Equal(`# HELP b b help
+# TYPE b gauge
+b 9.5
+`, buf.String())
+}
+
+func (t *testMetrics) TestHistogram() {
+	h := NewHistogramVec("c_seconds", "c help", []float64{1, 0.1}, "k")
+	h.WithLabelValues("x").Observe(0.05)
+	h.WithLabelValues("x").Observe(0.5)
+	h.WithLabelValues("x").Observe(3)
+
+	var buf bytes.Buffer
+	t.NoError(h.Write(&buf))
+	t.Equal(`# HELP c_seconds c help
+# TYPE c_seconds histogram
+c_seconds_bucket{k="x",le="0.1"} 1
+c_seconds_bucket{k="x",le="1"} 2
+c_seconds_bucket{k="x",le="+Inf"} 3
+c_seconds_sum{k="x"} 3.55
+c_seconds_count{k="x"} 3
+`, buf.String())
+}
+
+func (t *testMetrics) TestWrongLabelValues() {
+	c := NewCounterVec("a_total", "a help", "k")
+
+	t.Panics(func() { c.WithLabelValues("x", "y") })
+}
+
+func (t *testMetrics) TestEscape() {
+	c := NewCounterVec("a_total", "a\nhelp", "k")
+	c.WithLabelValues("x\"\\\n").Inc()
+
+	var buf bytes.Buffer
+	t.NoError(c.Write(&buf))
+	t.Equal(`# HELP a_total a\nhelp
+# TYPE a_total counter
+a_total{k="x\"\\\n"} 1
+`, buf.String())
+}
+
+func (t *testMetrics) TestRegistry() {
+	r := NewRegistry()
+
+	b := NewGaugeVec("b", "b help")
+	a := NewCounterVec("a_total", "a help")
+	t.NoError(r.Register(b, a))
+
+	err := r.Register(NewCounterVec("a_total", "a help"))
+	t.Error(err)
+	t.Contains(err.Error(), "already registered")
+
+	a.WithLabelValues().Inc()
+	b.WithLabelValues().Set(1)
+
+	ts := httptest.NewServer(r.Handler())
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL)
+	t.NoError(err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	t.Equal(ContentType, res.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(res.Body)
+	t.NoError(err)
+	t.Equal(`# HELP a_total a help
+# TYPE a_total counter
+a_total 1
+# HELP b b help
+# TYPE b gauge
+b 1
+`, string(body))
+}
+
+func (t *testMetrics) TestServerBindFailed() {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	t.NoError(err)
+	defer l.Close()
+
+	sv := NewServer(l.Addr().String(), NewRegistry())
+
+	err = sv.Start()
+	t.Error(err)
+	t.Contains(err.Error(), "failed to open metrics server")
+	t.False(sv.IsStarted())
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, new(testMetrics))
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultRegistry collects the metrics of the mitum packages.
+var DefaultRegistry = NewRegistry()
+
+type Registry struct {
+	sync.RWMutex
+	collectors map[string]Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: map[string]Collector{}}
+}
+
+func (r *Registry) Register(cs ...Collector) error {
+	r.Lock()
+	defer r.Unlock()
+
+	for i := range cs {
+		name := cs[i].Name()
+		if _, found := r.collectors[name]; found {
+			return errors.Errorf("metric, %q already registered", name)
+		}
+
+		r.collectors[name] = cs[i]
+	}
+
+	return nil
+}
+
+func (r *Registry) MustRegister(cs ...Collector) {
+	if err := r.Register(cs...); err != nil {
+		panic(err)
+	}
+}
+
+// WriteTo writes all the metrics in the prometheus text format. The metrics
+// are sorted by name.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.RLock()
+	names := make([]string, len(r.collectors))
+	var i int
+	for k := range r.collectors {
+		names[i] = k
+		i++
+	}
+	sort.Strings(names)
+
+	cs := make([]Collector, len(names))
+	for i := range names {
+		cs[i] = r.collectors[names[i]]
+	}
+	r.RUnlock()
+
+	var buf bytes.Buffer
+	for i := range cs {
+		if err := cs[i].Write(&buf); err != nil {
+			return 0, err
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+
+		if _, err := r.WriteTo(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// MustRegister registers collectors to DefaultRegistry.
+func MustRegister(cs ...Collector) {
+	DefaultRegistry.MustRegister(cs...)
+}
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const DefaultPath = "/metrics"
+
+// Server exports the metrics of registry thru http.
+type Server struct {
+	*logging.Logging
+	*util.ContextDaemon
+	bind     string
+	registry *Registry
+	listener net.Listener
+}
+
+func NewServer(bind string, registry *Registry) *Server {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+
+	sv := &Server{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "metrics-server")
+		}),
+		bind:     bind,
+		registry: registry,
+	}
+
+	sv.ContextDaemon = util.NewContextDaemon("metrics-server", sv.run)
+
+	return sv
+}
+
+func (sv *Server) SetLogging(l *logging.Logging) *logging.Logging {
+	_ = sv.ContextDaemon.SetLogging(l)
+
+	return sv.Logging.SetLogging(l)
+}
+
+func (sv *Server) Bind() string {
+	return sv.bind
+}
+
+// Start opens the listener before the daemon starts, so the failure of bind
+// is returned.
+func (sv *Server) Start() error {
+	if sv.ContextDaemon.IsStarted() {
+		return util.DaemonAlreadyStartedError
+	}
+
+	listener, err := sv.listen()
+	if err != nil {
+		return err
+	}
+
+	sv.listener = listener
+
+	if err := sv.ContextDaemon.Start(); err != nil {
+		_ = listener.Close()
+
+		return err
+	}
+
+	return nil
+}
+
+func (sv *Server) listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", sv.bind)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open metrics server, %q", sv.bind)
+	}
+
+	return listener, nil
+}
+
+func (sv *Server) run(ctx context.Context) error {
+	listener := sv.listener
+	sv.listener = nil
+
+	if listener == nil {
+		i, err := sv.listen()
+		if err != nil {
+			return err
+		}
+
+		listener = i
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(DefaultPath, sv.registry.Handler())
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: time.Second * 3}
+
+	sv.Log().Debug().Str("bind", sv.bind).Msg("metrics server started")
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			sv.Log().Error().Err(err).Msg("metrics server failed")
+
+			errChan <- err
+		}
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		sctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		return server.Shutdown(sctx) // nolint:contextcheck
+	}
+}
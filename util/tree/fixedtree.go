@@ -191,7 +191,8 @@ func (tr FixedTree) Traverse(f func(FixedTreeNode) (bool, error)) error {
 }
 
 // Proof returns the nodes to prove whether node is in tree. It always returns
-// root node + N(2 children).
+// root node + N(2 children). The missing children, like the children of leaf
+// node, are nil.
 func (tr FixedTree) Proof(index uint64) ([]FixedTreeNode, error) {
 	self, err := tr.Node(index)
 	if err != nil {
@@ -375,17 +376,82 @@ func ProveFixedTreeProof(pr []FixedTreeNode) error {
 	return nil
 }
 
+// ProveFixedTreeProofByKey checks the proof and finds the node of the given
+// key in the proof. The found node was proved with the proof.
+func ProveFixedTreeProofByKey(pr []FixedTreeNode, key []byte) (FixedTreeNode, error) {
+	n, err := proveFixedTreeProofByKey(pr, key)
+	if err != nil {
+		return nil, InvalidProofError.Wrap(err)
+	}
+
+	return n, nil
+}
+
+func proveFixedTreeProofByKey(pr []FixedTreeNode, key []byte) (FixedTreeNode, error) {
+	if len(key) < 1 {
+		return nil, EmptyKeyError
+	}
+
+	if err := proveFixedTreeProof(pr); err != nil {
+		return nil, err
+	}
+
+	// NOTE the node of proof is the parent of the first children; if the node
+	// is root, it is the last node, if not, it is in the second children.
+	var self FixedTreeNode
+	if len(pr) == 3 {
+		self = pr[len(pr)-1]
+	} else {
+		for _, n := range pr[2:4] {
+			if n != nil && bytes.Equal(n.Key(), key) {
+				self = n
+
+				break
+			}
+		}
+	}
+
+	if self == nil || !bytes.Equal(self.Key(), key) {
+		return nil, util.NotFoundError.Errorf("node not found in proof")
+	}
+
+	if pr[0] == nil {
+		// NOTE leaf node; it's hash can be checked by it's key.
+		switch h, err := FixedTreeNodeHash(self, nil, nil); {
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(self.Hash(), h):
+			return nil, HashNotMatchError.Errorf("node, %d has wrong hash", self.Index())
+		}
+
+		return self, nil
+	}
+
+	switch p, err := parentNodeInProof(0, pr, pr[0].Index()); {
+	case err != nil:
+		return nil, err
+	case p.Index() != self.Index():
+		return nil, errors.Errorf("node, %d is not the parent of the first children", self.Index())
+	default:
+		return self, nil
+	}
+}
+
 func proveFixedTreeProof(pr []FixedTreeNode) error {
 	switch n := len(pr); {
 	case n < 1:
 		return errors.Errorf("nothing to prove")
 	case n%2 != 1:
 		return errors.Errorf("invalid proof; len=%d", n)
-	case pr[len(pr)-1].Index() != 0:
+	case pr[len(pr)-1] == nil, pr[len(pr)-1].Index() != 0:
 		return errors.Errorf("root node not found")
 	}
 
 	for i := range pr {
+		if pr[i] == nil {
+			continue
+		}
+
 		if err := pr[i].IsValid(nil); err != nil {
 			return InvalidNodeError.Errorf("node, %d", i)
 		}
@@ -393,8 +459,19 @@ func proveFixedTreeProof(pr []FixedTreeNode) error {
 
 	for i := 0; i < len(pr[:len(pr)-1])/2; i++ {
 		a, b := pr[(i*2)], pr[(i*2)+1]
+		switch {
+		case a == nil && b == nil:
+			if i > 0 {
+				return errors.Errorf("empty nodes in proof, %d", i)
+			}
+
+			continue // NOTE the node of proof is leaf
+		case a == nil:
+			return errors.Errorf("empty left node in proof, %d", i)
+		}
+
 		if p, err := parentNodeInProof(i, pr, a.Index()); err != nil {
-			return errors.Wrapf(err, "nodes, %d and %d", a.Index(), b.Index())
+			return errors.Wrapf(err, "node, %d", a.Index())
 		} else if h, err := FixedTreeNodeHash(p, a, b); err != nil {
 			return err
 		} else if !bytes.Equal(p.Hash(), h) {
@@ -414,16 +491,17 @@ func parentNodeInProof(i int, pr []FixedTreeNode, index uint64) (FixedTreeNode,
 		return p, err
 	case i < (len(pr[:len(pr)-1])/2)-1:
 		pa, pb := pr[(i*2)+2], pr[(i*2)+2+1]
-		if j == pa.Index() {
+		switch {
+		case pa != nil && j == pa.Index():
 			p = pa
-		} else {
+		case pb != nil && j == pb.Index():
 			p = pb
 		}
 	default:
 		p = pr[len(pr)-1]
 	}
 
-	if len(p.Key()) < 1 {
+	if p == nil || len(p.Key()) < 1 {
 		return p, errors.Errorf("parent node not found")
 	}
 
@@ -217,6 +217,94 @@ func (t *testFixedTree) TestProof() {
 	t.NoError(ProveFixedTreeProof(pr))
 }
 
+func (t *testFixedTree) TestProofAllIndex() {
+	for l := uint64(1); l < 20; l++ {
+		trg := NewFixedTreeGenerator(l)
+
+		for i := uint64(0); i < l; i++ {
+			n := NewBaseFixedTreeNode(t.hint, i, util.UUID().Bytes())
+			t.NoError(trg.Add(n))
+		}
+
+		tr, err := trg.Tree()
+		t.NoError(err)
+		t.NoError(tr.IsValid(nil))
+
+		for i := uint64(0); i < l; i++ {
+			pr, err := tr.Proof(i)
+			t.NoError(err)
+
+			t.NoError(ProveFixedTreeProof(pr), "size=%d index=%d", l, i)
+
+			n, err := tr.Node(i)
+			t.NoError(err)
+
+			un, err := ProveFixedTreeProofByKey(pr, n.Key())
+			t.NoError(err, "size=%d index=%d", l, i)
+			t.True(n.Equal(un))
+		}
+	}
+}
+
+func (t *testFixedTree) TestProofByKeyNotFound() {
+	l := uint64(15)
+	trg := NewFixedTreeGenerator(l)
+
+	for i := uint64(0); i < l; i++ {
+		n := NewBaseFixedTreeNode(t.hint, i, util.UUID().Bytes())
+		t.NoError(trg.Add(n))
+	}
+
+	tr, err := trg.Tree()
+	t.NoError(err)
+
+	pr, err := tr.Proof(4)
+	t.NoError(err)
+
+	{ // NOTE sibling is in proof, but it is not proved
+		n, err := tr.Node(3)
+		t.NoError(err)
+
+		_, err = ProveFixedTreeProofByKey(pr, n.Key())
+		t.True(errors.Is(err, InvalidProofError))
+	}
+
+	{ // NOTE unknown key
+		_, err = ProveFixedTreeProofByKey(pr, util.UUID().Bytes())
+		t.True(errors.Is(err, InvalidProofError))
+		t.True(errors.Is(err, util.NotFoundError))
+	}
+}
+
+func (t *testFixedTree) TestProofByKeyWrongLeafKey() {
+	l := uint64(15)
+	trg := NewFixedTreeGenerator(l)
+
+	for i := uint64(0); i < l; i++ {
+		n := NewBaseFixedTreeNode(t.hint, i, util.UUID().Bytes())
+		t.NoError(trg.Add(n))
+	}
+
+	tr, err := trg.Tree()
+	t.NoError(err)
+
+	pr, err := tr.Proof(9) // NOTE leaf
+	t.NoError(err)
+	t.Nil(pr[0])
+	t.Nil(pr[1])
+
+	n := pr[2].(BaseFixedTreeNode)
+	t.Equal(uint64(9), n.Index())
+
+	key := util.UUID().Bytes()
+	n.key = key // NOTE replace key of leaf
+	pr[2] = n
+
+	_, err = ProveFixedTreeProofByKey(pr, key)
+	t.True(errors.Is(err, InvalidProofError))
+	t.True(errors.Is(err, HashNotMatchError))
+}
+
 func (t *testFixedTree) TestEncodeJSON() {
 	l := uint64(15)
 	trg := NewFixedTreeGenerator(l)